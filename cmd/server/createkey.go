@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/config"
+	schedcrypto "github.com/dtorcivia/schedlock/internal/crypto"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/engine"
+)
+
+// runCreateKey implements "schedlock create-key". It only needs the server
+// secret and the database, so it works without Google or notification
+// settings. The database is in WAL mode with a busy timeout and keys are
+// read on every request, so it is safe to run next to a live server.
+func runCreateKey(args []string) error {
+	fs := flag.NewFlagSet("create-key", flag.ContinueOnError)
+	name := fs.String("name", "", "key name (required)")
+	tier := fs.String("tier", "", "key tier: read, write or admin (required)")
+	scopes := fs.String("scopes", "", "comma-separated scopes; empty means the tier's defaults")
+	constraintsJSON := fs.String("constraints", "", "key constraints as JSON, or @path to read them from a file")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: schedlock create-key -name NAME -tier TIER [-scopes a,b] [-constraints JSON|@file]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *name == "" || *tier == "" {
+		fs.Usage()
+		return fmt.Errorf("-name and -tier are required")
+	}
+	if *tier != database.TierRead && *tier != database.TierWrite && *tier != database.TierAdmin {
+		return fmt.Errorf("invalid tier %q: must be read, write or admin", *tier)
+	}
+
+	var scopeList []string
+	if *scopes != "" {
+		for _, scope := range strings.Split(*scopes, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopeList = append(scopeList, scope)
+			}
+		}
+	}
+
+	constraints, err := parseConstraintsFlag(*constraintsJSON)
+	if err != nil {
+		return err
+	}
+
+	cfg, isSetupMode, err := config.LoadWithSetupMode()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	// In setup mode the server secret is generated per process, so a key
+	// hashed now would never authenticate against the real server
+	if isSetupMode {
+		return fmt.Errorf("SchedLock is not configured yet; finish setup before creating keys")
+	}
+
+	hasher, err := schedcrypto.NewAPIKeyHasher(cfg.Auth.SecretKey)
+	if err != nil {
+		return err
+	}
+
+	db, err := database.Open(cfg.Database.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	repo := apikeys.NewRepository(db, hasher)
+	apiKey, fullKey, err := repo.CreateWithScopes(ctx, *name, *tier, scopeList, constraints)
+	if err != nil {
+		return fmt.Errorf("failed to create key: %w", err)
+	}
+
+	engine.NewAuditLogger(db).Log(ctx, database.AuditAPIKeyCreated, "", apiKey.ID, "cli", map[string]interface{}{
+		"name":   apiKey.Name,
+		"tier":   apiKey.Tier,
+		"scopes": apiKey.Scopes,
+	})
+
+	// Only the key goes to stdout so scripts can capture it directly
+	fmt.Fprintf(os.Stderr, "Created %s key %q (%s). It will not be shown again.\n", apiKey.Tier, apiKey.Name, apiKey.ID)
+	fmt.Println(fullKey)
+	return nil
+}
+
+// parseConstraintsFlag decodes the -constraints value. Unknown fields are
+// rejected so a typo does not silently create an unconstrained key.
+func parseConstraintsFlag(value string) (*database.KeyConstraints, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	data := []byte(value)
+	if strings.HasPrefix(value, "@") {
+		var err error
+		if data, err = os.ReadFile(strings.TrimPrefix(value, "@")); err != nil {
+			return nil, fmt.Errorf("failed to read constraints: %w", err)
+		}
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+	var constraints database.KeyConstraints
+	if err := decoder.Decode(&constraints); err != nil {
+		return nil, fmt.Errorf("invalid constraints JSON: %w", err)
+	}
+	return &constraints, nil
+}
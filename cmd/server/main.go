@@ -34,6 +34,18 @@ func main() {
 			}
 			fmt.Println(hash)
 			return
+		case "create-key":
+			if err := runCreateKey(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "rotate-encryption-key":
+			if err := runRotateEncryptionKey(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
 		}
 	}
 
@@ -68,7 +80,15 @@ func run() error {
 	)
 
 	// Open database
-	db, err := database.Open(cfg.Database.Path)
+	db, err := database.OpenWithOptions(cfg.Database.Path, database.Options{
+		ReadPool:        cfg.Database.ReadPool,
+		ReadPoolSize:    cfg.Database.ReadPoolSize,
+		DisableWAL:      !cfg.Database.WALMode,
+		BusyTimeoutMs:   cfg.Database.BusyTimeoutMs,
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: time.Duration(cfg.Database.ConnMaxLifetimeSeconds) * time.Second,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -76,6 +96,7 @@ func run() error {
 
 	logger.Info("Database initialized",
 		"path", cfg.Database.Path,
+		"read_pool", cfg.Database.ReadPool,
 	)
 
 	// Load runtime settings (database overrides)
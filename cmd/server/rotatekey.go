@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	schedcrypto "github.com/dtorcivia/schedlock/internal/crypto"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/engine"
+)
+
+// runRotateEncryptionKey implements "schedlock rotate-encryption-key". It
+// re-encrypts stored secrets from the old key to the configured
+// SCHEDLOCK_ENCRYPTION_KEY. It runs from the CLI rather than over HTTP so
+// neither key ever crosses the network. Stop the server first: a running
+// server keeps using the key it started with.
+func runRotateEncryptionKey(args []string) error {
+	fs := flag.NewFlagSet("rotate-encryption-key", flag.ContinueOnError)
+	oldKey := fs.String("old-key", os.Getenv("SCHEDLOCK_ENCRYPTION_KEY_OLD"), "key the secrets are currently encrypted with (default $SCHEDLOCK_ENCRYPTION_KEY_OLD)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: SCHEDLOCK_ENCRYPTION_KEY=new SCHEDLOCK_ENCRYPTION_KEY_OLD=old schedlock rotate-encryption-key")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *oldKey == "" {
+		fs.Usage()
+		return fmt.Errorf("the old key is required via SCHEDLOCK_ENCRYPTION_KEY_OLD or -old-key")
+	}
+
+	cfg, isSetupMode, err := config.LoadWithSetupMode()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	// In setup mode the encryption key is generated per process
+	if isSetupMode {
+		return fmt.Errorf("SchedLock is not configured yet; finish setup before rotating keys")
+	}
+	if *oldKey == cfg.Auth.EncryptionKey {
+		return fmt.Errorf("the old key matches SCHEDLOCK_ENCRYPTION_KEY; set the new key first")
+	}
+
+	from, err := schedcrypto.NewEncryptor(*oldKey)
+	if err != nil {
+		return fmt.Errorf("invalid old key: %w", err)
+	}
+	to, err := schedcrypto.NewEncryptor(cfg.Auth.EncryptionKey)
+	if err != nil {
+		return fmt.Errorf("invalid new key: %w", err)
+	}
+
+	db, err := database.Open(cfg.Database.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	count, err := db.RotateEncryptionKey(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("rotation aborted, no secrets were changed: %w", err)
+	}
+
+	engine.NewAuditLogger(db).Log(ctx, database.AuditEncryptionKeyRotated, "", "", "cli", map[string]interface{}{
+		"secrets": count,
+	})
+
+	fmt.Fprintf(os.Stderr, "Re-encrypted %d secrets. Remove SCHEDLOCK_ENCRYPTION_KEY_OLD and start the server with the new key.\n", count)
+	return nil
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/engine"
+	"github.com/dtorcivia/schedlock/internal/response"
+)
+
+// GetKeyAuditLog returns the audit trail of one API key. Admins may read any
+// key's trail; other keys only their own.
+func (h *Handler) GetKeyAuditLog(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeRequestsRead)
+	if authKey == nil {
+		return
+	}
+
+	keyID := r.PathValue("id")
+	if keyID != authKey.ID && !authKey.HasScope(apikeys.ScopeAdmin) {
+		response.Error(w, http.StatusForbidden, "access denied", nil)
+		return
+	}
+
+	key, err := h.apiKeyRepo.GetByID(r.Context(), keyID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to get API key", err)
+		return
+	}
+	if key == nil {
+		response.Error(w, http.StatusNotFound, "API key not found", nil)
+		return
+	}
+
+	q := engine.AuditQuery{
+		APIKeyID:  keyID,
+		EventType: r.URL.Query().Get("event_type"),
+	}
+	if !parseAuditPage(w, r, &q, 50) {
+		return
+	}
+	h.writeAuditPage(w, r, q)
+}
+
+// parseAuditPage reads the limit and offset query parameters into q. It
+// writes the error response and returns false if either is invalid.
+func parseAuditPage(w http.ResponseWriter, r *http.Request, q *engine.AuditQuery, defaultLimit int) bool {
+	query := r.URL.Query()
+	q.Limit = defaultLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n <= 0 || n > 500 {
+			response.Error(w, http.StatusBadRequest, "limit must be between 1 and 500", nil)
+			return false
+		}
+		q.Limit = n
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		n, err := strconv.Atoi(offsetStr)
+		if err != nil || n < 0 {
+			response.Error(w, http.StatusBadRequest, "invalid offset", nil)
+			return false
+		}
+		q.Offset = n
+	}
+	return true
+}
+
+// writeAuditPage runs q and writes one page of entries with the total.
+func (h *Handler) writeAuditPage(w http.ResponseWriter, r *http.Request, q engine.AuditQuery) {
+	entries, total, err := h.auditLogger.Query(r.Context(), q)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to get audit log", err)
+		return
+	}
+
+	response.Paginated(w, r, entries, response.OffsetPage(len(entries), total, q.Limit, q.Offset), map[string]interface{}{
+		"entries": entries,
+		"total":   total,
+		"limit":   q.Limit,
+		"offset":  q.Offset,
+	})
+}
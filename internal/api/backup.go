@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/response"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+// Backup streams a consistent copy of the SQLite database as a download.
+// The copy is written to a temporary file with VACUUM INTO, so the live
+// database stays online and writers are only blocked briefly. A database
+// over the configured size limit gets 413.
+func (h *Handler) Backup(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeAdmin)
+	if authKey == nil {
+		return
+	}
+
+	maxBytes, timeout := h.backupLimits()
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	// Check the live size first so an oversized database is never copied
+	size, err := h.db.Size(ctx)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to determine database size", err)
+		return
+	}
+	if maxBytes > 0 && size > maxBytes {
+		response.Error(w, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("database size %d bytes exceeds backup limit of %d bytes", size, maxBytes), nil)
+		return
+	}
+
+	// VACUUM INTO refuses to overwrite, so write into a fresh temp directory
+	dir, err := os.MkdirTemp("", "schedlock-backup-")
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to create backup directory", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	dest := filepath.Join(dir, "schedlock.db")
+	if err := h.db.Backup(ctx, dest); err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to create backup", err)
+		return
+	}
+
+	file, err := os.Open(dest)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to open backup", err)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to stat backup", err)
+		return
+	}
+	if maxBytes > 0 && info.Size() > maxBytes {
+		response.Error(w, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("backup size %d bytes exceeds limit of %d bytes", info.Size(), maxBytes), nil)
+		return
+	}
+
+	// Large backups can outlast the server write timeout; extend it to the backup timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = http.NewResponseController(w).SetWriteDeadline(deadline)
+	}
+
+	filename := fmt.Sprintf("schedlock-%s.db", time.Now().UTC().Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/vnd.sqlite3")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	written, err := io.Copy(w, file)
+	if err != nil {
+		// Headers are already sent; the client sees a truncated download
+		util.Error("Failed to stream database backup", "error", err, "written", written)
+		return
+	}
+
+	util.Info("Database backup downloaded", "api_key_id", authKey.ID, "size_bytes", written)
+	h.auditLogger.Log(r.Context(), database.AuditDatabaseBackup, "", authKey.ID, "api", map[string]interface{}{
+		"size_bytes": written,
+		"filename":   filename,
+	})
+}
+
+// backupLimits returns the configured backup size limit and timeout.
+func (h *Handler) backupLimits() (int64, time.Duration) {
+	maxBytes := int64(config.DefaultBackupMaxBytes)
+	timeoutSeconds := config.DefaultBackupTimeoutSeconds
+	if h.config != nil {
+		maxBytes = h.config.Database.BackupMaxBytes
+		if h.config.Database.BackupTimeoutSeconds > 0 {
+			timeoutSeconds = h.config.Database.BackupTimeoutSeconds
+		}
+	}
+	return maxBytes, time.Duration(timeoutSeconds) * time.Second
+}
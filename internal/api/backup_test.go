@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/engine"
+	"github.com/dtorcivia/schedlock/internal/server/middleware"
+)
+
+func newBackupTestHandler(t *testing.T, maxBytes int64) *Handler {
+	t.Helper()
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_admin', 'hash', 'sk_test', 'Backup Marker', 'admin')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Database.BackupMaxBytes = maxBytes
+	return &Handler{config: cfg, db: db, auditLogger: engine.NewAuditLogger(db)}
+}
+
+func backupRequest(tier string) *http.Request {
+	req := httptest.NewRequest("GET", "http://example.com/api/admin/backup", nil)
+	return req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key_admin",
+		Tier: tier,
+	}))
+}
+
+func TestBackupRequiresAdminScope(t *testing.T) {
+	h := newBackupTestHandler(t, config.DefaultBackupMaxBytes)
+
+	for _, tier := range []string{"read", "write"} {
+		rr := httptest.NewRecorder()
+		h.Backup(rr, backupRequest(tier))
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("%s key: got %d, want 403", tier, rr.Code)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	h.Backup(rr, httptest.NewRequest("GET", "http://example.com/api/admin/backup", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("no key: got %d, want 401", rr.Code)
+	}
+}
+
+func TestBackupRefusesOversizedDatabase(t *testing.T) {
+	h := newBackupTestHandler(t, 1024)
+
+	rr := httptest.NewRecorder()
+	h.Backup(rr, backupRequest("admin"))
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("got %d, want 413", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "PAYLOAD_TOO_LARGE") {
+		t.Errorf("body = %s, want code PAYLOAD_TOO_LARGE", rr.Body.String())
+	}
+	if rr.Header().Get("Content-Disposition") != "" {
+		t.Error("an oversized backup was offered as a download")
+	}
+}
+
+func TestBackupStreamsDatabaseCopy(t *testing.T) {
+	h := newBackupTestHandler(t, 0)
+
+	rr := httptest.NewRecorder()
+	h.Backup(rr, backupRequest("admin"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("Content-Type"); got != "application/vnd.sqlite3" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := rr.Header().Get("Content-Disposition"); !strings.HasPrefix(got, `attachment; filename="schedlock-`) {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	if !strings.HasPrefix(rr.Body.String(), "SQLite format 3\x00") {
+		t.Fatal("body is not a SQLite database")
+	}
+
+	// The download is a working copy of the live database
+	path := filepath.Join(t.TempDir(), "backup.db")
+	if err := os.WriteFile(path, rr.Body.Bytes(), 0o600); err != nil {
+		t.Fatalf("write backup: %v", err)
+	}
+	backup, err := database.Open(path)
+	if err != nil {
+		t.Fatalf("open backup: %v", err)
+	}
+	defer backup.Close()
+	var name string
+	if err := backup.QueryRow(`SELECT name FROM api_keys WHERE id = 'key_admin'`).Scan(&name); err != nil || name != "Backup Marker" {
+		t.Errorf("backup api key = %q, %v; want Backup Marker", name, err)
+	}
+
+	entries, err := h.auditLogger.GetByEventType(context.Background(), database.AuditDatabaseBackup, 10)
+	if err != nil {
+		t.Fatalf("GetByEventType() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].APIKeyID.String != "key_admin" {
+		t.Errorf("audit entries = %+v, want one backup by key_admin", entries)
+	}
+}
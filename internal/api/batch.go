@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/google"
+	"github.com/dtorcivia/schedlock/internal/response"
+)
+
+// GetRequestResults reports the per-item outcome of a batch request in the
+// multi-status shape: 200 when every item succeeded, 207 otherwise.
+func (h *Handler) GetRequestResults(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeRequestsRead)
+	if authKey == nil {
+		return
+	}
+
+	requestID := r.PathValue("requestId")
+	if requestID == "" {
+		response.Error(w, http.StatusBadRequest, "request ID required", nil)
+		return
+	}
+
+	req := h.getReadableRequest(w, r, authKey, requestID)
+	if req == nil {
+		return
+	}
+	if req.Operation != database.OperationCreateEventsBatch {
+		response.Error(w, http.StatusBadRequest, "request is not a batch operation", nil)
+		return
+	}
+
+	results, err := batchRequestResults(req)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to read batch results", err)
+		return
+	}
+	if results == nil {
+		response.WriteNotCompleted(w, req.ID, req.Status)
+		return
+	}
+	h.markResultFetched(r.Context(), authKey, req)
+	response.MultiStatus(w, results)
+}
+
+// batchRequestResults converts a batch request's outcome to item results. A
+// batch that created nothing stores no result, so every event is reported
+// with the request's error. It returns nil while the request has not run.
+func batchRequestResults(req *database.Request) ([]response.ItemResult, error) {
+	switch {
+	case req.Status == database.StatusCompleted && req.Result != nil:
+		var result google.BatchResult
+		if err := json.Unmarshal(req.Result, &result); err != nil {
+			return nil, err
+		}
+		return batchItemResults(result.Results), nil
+
+	case req.Status == database.StatusFailed:
+		var batch google.EventBatchIntent
+		if err := json.Unmarshal(req.Payload, &batch); err != nil {
+			return nil, err
+		}
+		message := req.Error.String
+		if message == "" {
+			message = "request failed"
+		}
+		results := make([]response.ItemResult, len(batch.Events))
+		for i, event := range batch.Events {
+			results[i] = response.ItemFailure(i, http.StatusBadGateway, response.ErrCodeGoogleAPIError, message)
+			results[i].Data = google.BatchItemResult{CalendarID: event.CalendarID, Summary: event.Summary}
+		}
+		return results, nil
+	}
+	return nil, nil
+}
+
+// batchItemResults maps batch create outcomes to item results. Created events
+// report 201 and failures 502, since they come from the Google API.
+func batchItemResults(items []google.BatchItemResult) []response.ItemResult {
+	results := make([]response.ItemResult, len(items))
+	for i, item := range items {
+		if item.Error == "" {
+			results[i] = response.ItemSuccess(i, http.StatusCreated, item)
+			continue
+		}
+		results[i] = response.ItemFailure(i, http.StatusBadGateway, response.ErrCodeGoogleAPIError, item.Error)
+		results[i].Data = google.BatchItemResult{CalendarID: item.CalendarID, Summary: item.Summary}
+	}
+	return results
+}
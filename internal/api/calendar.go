@@ -2,15 +2,22 @@ package api
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/config"
 	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/engine"
 	"github.com/dtorcivia/schedlock/internal/google"
 	"github.com/dtorcivia/schedlock/internal/response"
 	"github.com/dtorcivia/schedlock/internal/util"
@@ -18,7 +25,7 @@ import (
 
 // ListCalendars returns all accessible calendars.
 func (h *Handler) ListCalendars(w http.ResponseWriter, r *http.Request) {
-	authKey := requireTier(w, r, "read")
+	authKey := requireScope(w, r, apikeys.ScopeCalendarsList)
 	if authKey == nil {
 		return
 	}
@@ -26,7 +33,7 @@ func (h *Handler) ListCalendars(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	calendars, err := h.calendarClient.ListCalendars(ctx)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, "failed to list calendars", err)
+		writeGoogleError(w, "failed to list calendars", err)
 		return
 	}
 
@@ -34,14 +41,43 @@ func (h *Handler) ListCalendars(w http.ResponseWriter, r *http.Request) {
 		calendars = filterCalendars(calendars, authKey.Constraints.CalendarAllowlist)
 	}
 
-	response.JSON(w, http.StatusOK, map[string]interface{}{
+	response.Paginated(w, r, calendars, response.Pagination{Count: len(calendars)}, map[string]interface{}{
 		"calendars": calendars,
 	})
 }
 
+// colorPaletteTTL is how long the Google color palette is cached. It is
+// fixed by Google and changes very rarely.
+const colorPaletteTTL = 24 * time.Hour
+
+// GetColors returns the event and calendar color palette (ID to hex). It is
+// cached for colorPaletteTTL. Without a working Google connection it returns
+// the standard event colors with source "fallback", which is not cached.
+func (h *Handler) GetColors(w http.ResponseWriter, r *http.Request) {
+	if requireScope(w, r, apikeys.ScopeCalendarsList) == nil {
+		return
+	}
+
+	h.colorsMu.Lock()
+	defer h.colorsMu.Unlock()
+
+	if h.colors == nil || time.Since(h.colorsFetched) >= colorPaletteTTL {
+		palette, err := h.calendarClient.GetColors(r.Context())
+		if err != nil {
+			util.Warn("Failed to fetch color palette, using fallback", "error", err)
+			response.JSON(w, http.StatusOK, google.FallbackColorPalette())
+			return
+		}
+		h.colors = palette
+		h.colorsFetched = time.Now()
+	}
+
+	response.JSON(w, http.StatusOK, h.colors)
+}
+
 // ListEvents returns events from a calendar.
 func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
-	authKey := requireTier(w, r, "read")
+	authKey := requireScope(w, r, apikeys.ScopeEventsRead)
 	if authKey == nil {
 		return
 	}
@@ -53,7 +89,7 @@ func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if authKey.Constraints != nil && len(authKey.Constraints.CalendarAllowlist) > 0 {
-		if !calendarAllowed(calendarID, authKey.Constraints.CalendarAllowlist) {
+		if !apikeys.CalendarAllowed(calendarID, authKey.Constraints.CalendarAllowlist) {
 			response.WriteConstraintViolation(w, "calendar_allowlist", "calendar not in allowlist")
 			return
 		}
@@ -100,8 +136,7 @@ func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
 	}
 	orderBy := r.URL.Query().Get("orderBy")
 
-	ctx := r.Context()
-	eventsResp, err := h.calendarClient.ListEvents(ctx, google.EventListOptions{
+	opts := google.EventListOptions{
 		CalendarID:   calendarID,
 		TimeMin:      timeMin,
 		TimeMax:      timeMax,
@@ -110,24 +145,148 @@ func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
 		Query:        queryText,
 		SingleEvents: singleEvents,
 		OrderBy:      orderBy,
-	})
+	}
+
+	ctx := r.Context()
+	var eventsResp *google.EventListResponse
+	if attendee := strings.TrimSpace(r.URL.Query().Get("attendee")); attendee != "" {
+		if err := util.ValidateEmail(attendee); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid attendee email", nil)
+			return
+		}
+		// Filtering would reveal who attends events whose attendees are hidden
+		for _, field := range apikeys.RedactedFields(authKey) {
+			if strings.EqualFold(field, "attendees") {
+				response.WriteConstraintViolation(w, "redact_fields", "attendee filter is not available when attendees are redacted")
+				return
+			}
+		}
+		eventsResp, err = h.listEventsByAttendee(ctx, opts, attendee)
+		if errors.Is(err, errInvalidPageToken) {
+			response.Error(w, http.StatusBadRequest, "invalid pageToken", nil)
+			return
+		}
+	} else {
+		eventsResp, err = h.calendarClient.ListEvents(ctx, opts)
+	}
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, "failed to list events", err)
+		writeGoogleError(w, "failed to list events", err)
 		return
 	}
 
+	events := redactEvents(authKey, eventsResp.Events)
 	resp := map[string]interface{}{
-		"events": eventsResp.Events,
+		"events": events,
 	}
 	if eventsResp.NextPageToken != "" {
 		resp["next_page_token"] = eventsResp.NextPageToken
 	}
-	response.JSON(w, http.StatusOK, resp)
+	response.Paginated(w, r, events, response.Pagination{
+		Count:         len(events),
+		NextPageToken: eventsResp.NextPageToken,
+	}, resp)
+}
+
+const (
+	// attendeeFilterPageSize is the page size used when scanning for an
+	// attendee, so each Google call covers as many events as possible.
+	attendeeFilterPageSize = 250
+	// maxAttendeeFilterPages caps the Google calls one filtered listing makes.
+	maxAttendeeFilterPages = 10
+)
+
+var errInvalidPageToken = errors.New("invalid page token")
+
+// attendeeCursor is where a filtered listing resumes: a Google page and how
+// many of its matches were already returned.
+type attendeeCursor struct {
+	PageToken string `json:"p,omitempty"`
+	Skip      int    `json:"s,omitempty"`
+}
+
+func (c attendeeCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeAttendeeCursor(token string) (attendeeCursor, error) {
+	var c attendeeCursor
+	if token == "" {
+		return c, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(data, &c) != nil || c.Skip < 0 {
+		return c, errInvalidPageToken
+	}
+	return c, nil
+}
+
+// listEventsByAttendee lists the events that include attendee, which Google
+// cannot filter on. It reads pages until opts.MaxResults events match, the
+// calendar runs out or maxAttendeeFilterPages pages were read, so a page can
+// hold fewer matches than asked for while still returning a next page token.
+// The token resumes inside a Google page when the last one read had more
+// matches than fit.
+func (h *Handler) listEventsByAttendee(ctx context.Context, opts google.EventListOptions, attendee string) (*google.EventListResponse, error) {
+	cursor, err := decodeAttendeeCursor(opts.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	maxResults := opts.MaxResults
+	opts.MaxResults = attendeeFilterPageSize
+	opts.PageToken = cursor.PageToken
+
+	result := &google.EventListResponse{Events: []google.Event{}}
+	for pages := 1; ; pages++ {
+		resp, err := h.calendarClient.ListEvents(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			resp = &google.EventListResponse{}
+		}
+
+		var matches []google.Event
+		for _, event := range resp.Events {
+			if hasAttendee(event, attendee) {
+				matches = append(matches, event)
+			}
+		}
+		skip := min(cursor.Skip, len(matches))
+		matches = matches[skip:]
+		cursor.Skip = 0
+
+		if room := maxResults - len(result.Events); len(matches) > room {
+			result.Events = append(result.Events, matches[:room]...)
+			result.NextPageToken = attendeeCursor{PageToken: opts.PageToken, Skip: skip + room}.encode()
+			return result, nil
+		}
+		result.Events = append(result.Events, matches...)
+
+		if resp.NextPageToken == "" {
+			return result, nil
+		}
+		opts.PageToken = resp.NextPageToken
+		if len(result.Events) == maxResults || pages >= maxAttendeeFilterPages {
+			result.NextPageToken = attendeeCursor{PageToken: opts.PageToken}.encode()
+			return result, nil
+		}
+	}
+}
+
+// hasAttendee reports whether attendee is on the event's guest list.
+func hasAttendee(event google.Event, attendee string) bool {
+	for _, a := range event.Attendees {
+		if strings.EqualFold(a.Email, attendee) {
+			return true
+		}
+	}
+	return false
 }
 
 // GetEvent returns a single event.
 func (h *Handler) GetEvent(w http.ResponseWriter, r *http.Request) {
-	authKey := requireTier(w, r, "read")
+	authKey := requireScope(w, r, apikeys.ScopeEventsRead)
 	if authKey == nil {
 		return
 	}
@@ -141,7 +300,7 @@ func (h *Handler) GetEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if authKey.Constraints != nil && len(authKey.Constraints.CalendarAllowlist) > 0 {
-		if !calendarAllowed(calendarID, authKey.Constraints.CalendarAllowlist) {
+		if !apikeys.CalendarAllowed(calendarID, authKey.Constraints.CalendarAllowlist) {
 			response.WriteConstraintViolation(w, "calendar_allowlist", "calendar not in allowlist")
 			return
 		}
@@ -150,7 +309,7 @@ func (h *Handler) GetEvent(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	event, err := h.calendarClient.GetEvent(ctx, calendarID, eventID)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, "failed to get event", err)
+		writeGoogleError(w, "failed to get event", err)
 		return
 	}
 
@@ -159,6 +318,10 @@ func (h *Handler) GetEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if fields := apikeys.RedactedFields(authKey); len(fields) > 0 {
+		redacted := redactEvent(*event, fields)
+		event = &redacted
+	}
 	response.JSON(w, http.StatusOK, event)
 }
 
@@ -173,13 +336,17 @@ type FreeBusyRequest struct {
 
 // FreeBusy returns free/busy information.
 func (h *Handler) FreeBusy(w http.ResponseWriter, r *http.Request) {
-	authKey := requireTier(w, r, "read")
+	authKey := requireScope(w, r, apikeys.ScopeFreeBusyRead)
 	if authKey == nil {
 		return
 	}
 
 	var req FreeBusyRequest
-	if err := parseJSON(r, &req); err != nil {
+	if err := h.parseJSON(w, r, &req); err != nil {
+		if isBodyTooLarge(err) {
+			writeBodyError(w, err)
+			return
+		}
 		// Try query parameters as fallback
 		var parseErr error
 		req.TimeMin, parseErr = time.Parse(time.RFC3339, r.URL.Query().Get("timeMin"))
@@ -196,7 +363,7 @@ func (h *Handler) FreeBusy(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if len(req.Calendars) == 0 {
-		req.Calendars = []string{"primary"}
+		req.Calendars = []string{apikeys.DefaultCalendar(authKey)}
 	}
 	if req.TimeMin.IsZero() && !req.TimeMinAlt.IsZero() {
 		req.TimeMin = req.TimeMinAlt
@@ -208,7 +375,7 @@ func (h *Handler) FreeBusy(w http.ResponseWriter, r *http.Request) {
 	if authKey.Constraints != nil && len(authKey.Constraints.CalendarAllowlist) > 0 {
 		var filtered []string
 		for _, cal := range req.Calendars {
-			if calendarAllowed(cal, authKey.Constraints.CalendarAllowlist) {
+			if apikeys.CalendarAllowed(cal, authKey.Constraints.CalendarAllowlist) {
 				filtered = append(filtered, cal)
 			}
 		}
@@ -230,34 +397,304 @@ func (h *Handler) FreeBusy(w http.ResponseWriter, r *http.Request) {
 	}
 	result, err := h.calendarClient.FreeBusy(ctx, fbReq)
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, "failed to get free/busy", err)
+		writeGoogleError(w, "failed to get free/busy", err)
 		return
 	}
 
 	response.JSON(w, http.StatusOK, result)
 }
 
-// CreateEvent initiates a create event request (requires approval).
-func (h *Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
-	authKey := requireTier(w, r, "write")
+// MergedFreeBusy returns busy periods merged across every calendar the key
+// may access: its calendar allowlist, or primary for unconstrained keys.
+func (h *Handler) MergedFreeBusy(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeFreeBusyRead)
 	if authKey == nil {
 		return
 	}
 
-	var intent google.EventIntent
-	if err := parseJSON(r, &intent); err != nil {
-		response.Error(w, http.StatusBadRequest, "invalid request body", err)
+	query := r.URL.Query()
+	timeMin := time.Now()
+	if v := query.Get("timeMin"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "timeMin must be RFC3339", nil)
+			return
+		}
+		timeMin = parsed
+	}
+	timeMax := timeMin.AddDate(0, 0, 7) // Default 1 week
+	if v := query.Get("timeMax"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "timeMax must be RFC3339", nil)
+			return
+		}
+		timeMax = parsed
+	}
+	if !timeMax.After(timeMin) {
+		response.Error(w, http.StatusBadRequest, "timeMax must be after timeMin", nil)
 		return
 	}
 
-	// Validate intent
-	if err := intent.Validate(); err != nil {
-		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+	ctx := r.Context()
+	calendars, err := h.freeBusyCalendars(ctx, authKey)
+	if err != nil {
+		writeGoogleError(w, "failed to list calendars", err)
+		return
+	}
+	if len(calendars) == 0 {
+		response.WriteConstraintViolation(w, "calendar_allowlist", "no calendars allowed for this key")
+		return
+	}
+
+	fbReq := &google.FreeBusyRequest{
+		TimeMin: timeMin,
+		TimeMax: timeMax,
+	}
+	for _, cal := range calendars {
+		fbReq.Items = append(fbReq.Items, google.FreeBusyCalendar{ID: cal})
+	}
+	result, err := h.calendarClient.FreeBusy(ctx, fbReq)
+	if err != nil {
+		writeGoogleError(w, "failed to get free/busy", err)
+		return
+	}
+
+	var busy []google.TimePeriod
+	calendarErrors := make(map[string][]google.Error)
+	if result != nil {
+		for id, info := range result.Calendars {
+			busy = append(busy, info.Busy...)
+			if len(info.Errors) > 0 {
+				calendarErrors[id] = info.Errors
+			}
+		}
+	}
+
+	resp := map[string]interface{}{
+		"timeMin":   timeMin,
+		"timeMax":   timeMax,
+		"calendars": calendars,
+		"busy":      google.MergeBusyPeriods(busy),
+	}
+	if len(calendarErrors) > 0 {
+		resp["errors"] = calendarErrors
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// freeBusyCalendars expands a key's calendar allowlist into concrete IDs.
+// Wildcard entries such as "*" or "*@example.com" expand to the matching
+// calendars the account can see; exact entries are kept as given.
+func (h *Handler) freeBusyCalendars(ctx context.Context, authKey *apikeys.AuthenticatedKey) ([]string, error) {
+	if authKey.Constraints == nil || len(authKey.Constraints.CalendarAllowlist) == 0 {
+		return []string{apikeys.DefaultCalendar(authKey)}, nil
+	}
+
+	allowlist := authKey.Constraints.CalendarAllowlist
+	if !apikeys.HasCalendarWildcard(allowlist) {
+		return append([]string(nil), allowlist...), nil
+	}
+
+	all, err := h.calendarClient.ListCalendars(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	seen := make(map[string]bool)
+	for _, cal := range filterCalendars(all, allowlist) {
+		ids = append(ids, cal.ID)
+		seen[cal.ID] = true
+	}
+	for _, entry := range allowlist {
+		if !strings.Contains(entry, "*") && !seen[entry] {
+			ids = append(ids, entry)
+			seen[entry] = true
+		}
+	}
+	return ids, nil
+}
+
+// maxAgendaCalendars caps how many calendars one agenda request fans out to.
+const maxAgendaCalendars = 25
+
+// AgendaEvent is an event in a multi-calendar listing, tagged with the
+// calendar it came from.
+type AgendaEvent struct {
+	CalendarID string `json:"calendarId"`
+	google.Event
+}
+
+// ListAgenda lists events from several calendars in one call, merged and
+// sorted by start time. Calendars default to every calendar the key may
+// read. A calendar that is not allowed or fails to load is reported under
+// "errors" without failing the others; maxResults caps the merged total.
+func (h *Handler) ListAgenda(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeEventsRead)
+	if authKey == nil {
+		return
+	}
+
+	query := r.URL.Query()
+	timeMin := time.Now()
+	if v := query.Get("timeMin"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid timeMin format (use RFC3339)", nil)
+			return
+		}
+		timeMin = parsed
+	}
+	timeMax := timeMin.AddDate(0, 0, 7) // Default 1 week
+	if v := query.Get("timeMax"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid timeMax format (use RFC3339)", nil)
+			return
+		}
+		timeMax = parsed
+	}
+	if !timeMax.After(timeMin) {
+		response.Error(w, http.StatusBadRequest, "timeMax must be after timeMin", nil)
+		return
+	}
+
+	maxResults := 50
+	if v := query.Get("maxResults"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 250 {
+			maxResults = n
+		}
+	}
+
+	ctx := r.Context()
+	calendarErrors := make(map[string]string)
+	var calendars []string
+	if v := query.Get("calendars"); v != "" {
+		seen := make(map[string]bool)
+		for _, cal := range strings.Split(v, ",") {
+			cal = strings.TrimSpace(cal)
+			if cal == "" || seen[cal] {
+				continue
+			}
+			seen[cal] = true
+			if authKey.Constraints != nil && len(authKey.Constraints.CalendarAllowlist) > 0 &&
+				!apikeys.CalendarAllowed(cal, authKey.Constraints.CalendarAllowlist) {
+				calendarErrors[cal] = "calendar not in allowlist"
+				continue
+			}
+			calendars = append(calendars, cal)
+		}
+	} else {
+		var err error
+		if calendars, err = h.freeBusyCalendars(ctx, authKey); err != nil {
+			writeGoogleError(w, "failed to list calendars", err)
+			return
+		}
+	}
+	if len(calendars) == 0 {
+		response.WriteConstraintViolation(w, "calendar_allowlist", "no calendars allowed for this key")
+		return
+	}
+	if len(calendars) > maxAgendaCalendars {
+		response.Error(w, http.StatusBadRequest, fmt.Sprintf("at most %d calendars may be listed at once", maxAgendaCalendars), nil)
+		return
+	}
+
+	// Each calendar returns its own first maxResults events by start time,
+	// which is enough to build the first maxResults of the merged list
+	type calendarResult struct {
+		events    []google.Event
+		truncated bool
+		err       error
+	}
+	results := make([]calendarResult, len(calendars))
+	var wg sync.WaitGroup
+	for i, cal := range calendars {
+		wg.Add(1)
+		go func(i int, cal string) {
+			defer wg.Done()
+			resp, err := h.calendarClient.ListEvents(ctx, google.EventListOptions{
+				CalendarID:   cal,
+				TimeMin:      timeMin,
+				TimeMax:      timeMax,
+				MaxResults:   maxResults,
+				SingleEvents: true,
+				OrderBy:      "startTime",
+			})
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			if resp != nil {
+				results[i].events = resp.Events
+				results[i].truncated = resp.NextPageToken != ""
+			}
+		}(i, cal)
+	}
+	wg.Wait()
+
+	events := []AgendaEvent{}
+	truncated := false
+	for i, cal := range calendars {
+		if results[i].err != nil {
+			util.Warn("Failed to list events for agenda", "calendar_id", cal, "error", results[i].err)
+			calendarErrors[cal] = "failed to list events"
+			continue
+		}
+		truncated = truncated || results[i].truncated
+		for _, event := range redactEvents(authKey, results[i].events) {
+			events = append(events, AgendaEvent{CalendarID: cal, Event: event})
+		}
+	}
+	sort.SliceStable(events, func(a, b int) bool {
+		return eventStart(&events[a].Event).Before(eventStart(&events[b].Event))
+	})
+	if len(events) > maxResults {
+		events = events[:maxResults]
+		truncated = true
+	}
+
+	resp := map[string]interface{}{
+		"timeMin":   timeMin,
+		"timeMax":   timeMax,
+		"calendars": calendars,
+		"events":    events,
+		"truncated": truncated,
+	}
+	if len(calendarErrors) > 0 {
+		resp["errors"] = calendarErrors
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// eventStart returns when an event begins for ordering. All-day events
+// sort at midnight UTC of their date.
+func eventStart(event *google.Event) time.Time {
+	if event.Start == nil {
+		return time.Time{}
+	}
+	if !event.Start.DateTime.IsZero() {
+		return event.Start.DateTime
+	}
+	start, _ := time.Parse("2006-01-02", event.Start.Date)
+	return start
+}
+
+// CreateEvent initiates a create event request (requires approval).
+func (h *Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeEventsCreate)
+	if authKey == nil {
 		return
 	}
-	intent.Sanitize()
 
-	approvalRequired, err := h.evaluateConstraintsForCreate(authKey, &intent)
+	var intent google.EventIntent
+	sub, err := h.parseSubmission(w, r, &intent)
+	if err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	decision, err := h.prepareCreate(authKey, &intent)
 	if err != nil {
 		writeConstraintError(w, err)
 		return
@@ -266,19 +703,30 @@ func (h *Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 	// Get idempotency key
 	idempotencyKey := r.Header.Get("Idempotency-Key")
 
+	priority, err := requestPriority(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	timeout, err := h.requestTimeout(r, authKey)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
 	// Marshal payload
 	payload, _ := json.Marshal(intent)
 
 	// Submit request
 	ctx := r.Context()
-	req, err := h.engine.SubmitRequest(ctx, authKey, database.OperationCreateEvent, payload, idempotencyKey, approvalRequired, "policy")
+	req, err := h.engine.SubmitRequest(ctx, authKey, database.OperationCreateEvent, payload, sub.Context, sub.Tags, idempotencyKey, priority, timeout, decision, "policy")
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, "failed to submit request", err)
+		writeSubmitError(w, err)
 		return
 	}
 
 	statusCode := http.StatusAccepted
-	if !approvalRequired {
+	if !decision.RequiresApproval() {
 		statusCode = http.StatusOK
 	}
 	response.JSON(w, statusCode, map[string]interface{}{
@@ -289,31 +737,23 @@ func (h *Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// UpdateEvent initiates an update event request (requires approval).
-func (h *Handler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
-	authKey := requireTier(w, r, "write")
+// CreateEventsBatch initiates a request that creates several events, possibly
+// across calendars, under a single approval. Every event must pass the key's
+// create constraints.
+func (h *Handler) CreateEventsBatch(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeEventsCreate)
 	if authKey == nil {
 		return
 	}
 
-	var intent google.EventUpdateIntent
-	if err := parseJSON(r, &intent); err != nil {
-		response.Error(w, http.StatusBadRequest, "invalid request body", err)
-		return
-	}
-
-	// Validate intent
-	if err := intent.Validate(); err != nil {
-		response.Error(w, http.StatusBadRequest, err.Error(), nil)
-		return
-	}
-	if !intent.HasChanges() {
-		response.Error(w, http.StatusBadRequest, "no changes provided", nil)
+	var batch google.EventBatchIntent
+	sub, err := h.parseSubmission(w, r, &batch)
+	if err != nil {
+		writeBodyError(w, err)
 		return
 	}
-	sanitizeUpdateIntent(&intent)
 
-	approvalRequired, err := h.evaluateConstraintsForUpdate(r.Context(), authKey, &intent)
+	decision, err := h.prepareBatch(authKey, &batch)
 	if err != nil {
 		writeConstraintError(w, err)
 		return
@@ -322,49 +762,57 @@ func (h *Handler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
 	// Get idempotency key
 	idempotencyKey := r.Header.Get("Idempotency-Key")
 
+	priority, err := requestPriority(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	timeout, err := h.requestTimeout(r, authKey)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
 	// Marshal payload
-	payload, _ := json.Marshal(intent)
+	payload, _ := json.Marshal(batch)
 
 	// Submit request
 	ctx := r.Context()
-	req, err := h.engine.SubmitRequest(ctx, authKey, database.OperationUpdateEvent, payload, idempotencyKey, approvalRequired, "policy")
+	req, err := h.engine.SubmitRequest(ctx, authKey, database.OperationCreateEventsBatch, payload, sub.Context, sub.Tags, idempotencyKey, priority, timeout, decision, "policy")
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, "failed to submit request", err)
+		writeSubmitError(w, err)
 		return
 	}
 
 	statusCode := http.StatusAccepted
-	if !approvalRequired {
+	if !decision.RequiresApproval() {
 		statusCode = http.StatusOK
 	}
 	response.JSON(w, statusCode, map[string]interface{}{
-		"request_id": req.ID,
-		"status":     req.Status,
-		"expires_at": req.ExpiresAt,
-		"message":    "Event update request submitted",
+		"request_id":  req.ID,
+		"status":      req.Status,
+		"expires_at":  req.ExpiresAt,
+		"event_count": len(batch.Events),
+		"calendars":   batch.TargetCalendars(),
+		"message":     "Batch event creation request submitted",
 	})
 }
 
-// DeleteEvent initiates a delete event request (requires approval).
-func (h *Handler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
-	authKey := requireTier(w, r, "write")
+// UpdateEvent initiates an update event request (requires approval).
+func (h *Handler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeEventsUpdate)
 	if authKey == nil {
 		return
 	}
 
-	var intent google.EventDeleteIntent
-	if err := parseJSON(r, &intent); err != nil {
-		response.Error(w, http.StatusBadRequest, "invalid request body", err)
-		return
-	}
-
-	// Validate intent
-	if err := intent.Validate(); err != nil {
-		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+	var intent google.EventUpdateIntent
+	sub, err := h.parseSubmission(w, r, &intent)
+	if err != nil {
+		writeBodyError(w, err)
 		return
 	}
 
-	approvalRequired, err := h.evaluateConstraintsForDelete(authKey, &intent)
+	decision, err := h.prepareUpdate(r.Context(), authKey, &intent)
 	if err != nil {
 		writeConstraintError(w, err)
 		return
@@ -373,47 +821,516 @@ func (h *Handler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 	// Get idempotency key
 	idempotencyKey := r.Header.Get("Idempotency-Key")
 
+	priority, err := requestPriority(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	timeout, err := h.requestTimeout(r, authKey)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
 	// Marshal payload
 	payload, _ := json.Marshal(intent)
 
 	// Submit request
 	ctx := r.Context()
-	req, err := h.engine.SubmitRequest(ctx, authKey, database.OperationDeleteEvent, payload, idempotencyKey, approvalRequired, "policy")
+	req, err := h.engine.SubmitRequest(ctx, authKey, database.OperationUpdateEvent, payload, sub.Context, sub.Tags, idempotencyKey, priority, timeout, decision, "policy")
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, "failed to submit request", err)
+		writeSubmitError(w, err)
 		return
 	}
 
 	statusCode := http.StatusAccepted
-	if !approvalRequired {
+	if !decision.RequiresApproval() {
 		statusCode = http.StatusOK
 	}
 	response.JSON(w, statusCode, map[string]interface{}{
 		"request_id": req.ID,
 		"status":     req.Status,
 		"expires_at": req.ExpiresAt,
-		"message":    "Event deletion request submitted",
+		"message":    "Event update request submitted",
 	})
 }
 
-// Helpers
+// DeleteEvent initiates a delete event request (requires approval).
+func (h *Handler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeEventsDelete)
+	if authKey == nil {
+		return
+	}
 
-func (h *Handler) evaluateConstraintsForCreate(authKey *apikeys.AuthenticatedKey, intent *google.EventIntent) (bool, error) {
-	result, violation := apikeys.EvaluateConstraints(
-		authKey,
-		database.OperationCreateEvent,
-		intent.CalendarID,
-		intent.Attendees,
-		intent.Start,
-		intent.End,
-	)
-	return handleConstraintResult(result, violation)
-}
+	var intent google.EventDeleteIntent
+	sub, err := h.parseSubmission(w, r, &intent)
+	if err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	decision, err := h.prepareDelete(authKey, &intent)
+	if err != nil {
+		writeConstraintError(w, err)
+		return
+	}
+
+	// Get idempotency key
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+
+	priority, err := requestPriority(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	timeout, err := h.requestTimeout(r, authKey)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	// Marshal payload
+	payload, _ := json.Marshal(intent)
+
+	// Submit request
+	ctx := r.Context()
+	req, err := h.engine.SubmitRequest(ctx, authKey, database.OperationDeleteEvent, payload, sub.Context, sub.Tags, idempotencyKey, priority, timeout, decision, "policy")
+	if err != nil {
+		writeSubmitError(w, err)
+		return
+	}
+
+	statusCode := http.StatusAccepted
+	if !decision.RequiresApproval() {
+		statusCode = http.StatusOK
+	}
+	response.JSON(w, statusCode, map[string]interface{}{
+		"request_id": req.ID,
+		"status":     req.Status,
+		"expires_at": req.ExpiresAt,
+		"message":    "Event deletion request submitted",
+	})
+}
+
+// MoveEvent initiates a request to move an event to another calendar (requires approval).
+func (h *Handler) MoveEvent(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeEventsMove)
+	if authKey == nil {
+		return
+	}
+
+	var intent google.EventMoveIntent
+	sub, err := h.parseSubmission(w, r, &intent)
+	if err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	// Source calendar and event come from the path
+	intent.CalendarID = r.PathValue("calendarId")
+	intent.EventID = r.PathValue("eventId")
+
+	decision, err := h.prepareMove(authKey, &intent)
+	if err != nil {
+		writeConstraintError(w, err)
+		return
+	}
+
+	// Get idempotency key
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+
+	priority, err := requestPriority(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	timeout, err := h.requestTimeout(r, authKey)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	// Marshal payload
+	payload, _ := json.Marshal(intent)
+
+	// Submit request
+	ctx := r.Context()
+	req, err := h.engine.SubmitRequest(ctx, authKey, database.OperationMoveEvent, payload, sub.Context, sub.Tags, idempotencyKey, priority, timeout, decision, "policy")
+	if err != nil {
+		writeSubmitError(w, err)
+		return
+	}
+
+	statusCode := http.StatusAccepted
+	if !decision.RequiresApproval() {
+		statusCode = http.StatusOK
+	}
+	response.JSON(w, statusCode, map[string]interface{}{
+		"request_id": req.ID,
+		"status":     req.Status,
+		"expires_at": req.ExpiresAt,
+		"message":    "Event move request submitted",
+	})
+}
+
+// DuplicateEventRequest is the optional body for duplicating an event.
+type DuplicateEventRequest struct {
+	ShiftMinutes int `json:"shiftMinutes"` // Optional: offset applied to start and end
+}
+
+// duplicatePayload is a create_event payload annotated with the event it was cloned from.
+// The extra field is ignored when the payload is decoded as an EventIntent for execution.
+type duplicatePayload struct {
+	google.EventIntent
+	DuplicateOf duplicateSource `json:"duplicateOf"`
+}
+
+type duplicateSource struct {
+	CalendarID string `json:"calendarId"`
+	EventID    string `json:"eventId"`
+	HtmlLink   string `json:"htmlLink,omitempty"`
+}
+
+// DuplicateEvent initiates a request to create a copy of an existing event (requires approval).
+func (h *Handler) DuplicateEvent(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeEventsCreate)
+	if authKey == nil {
+		return
+	}
+
+	calendarID := r.PathValue("calendarId")
+	eventID := r.PathValue("eventId")
+	if err := util.ValidateCalendarID(calendarID); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if eventID == "" {
+		response.Error(w, http.StatusBadRequest, "eventId is required", nil)
+		return
+	}
+
+	var body DuplicateEventRequest
+	var sub submission
+	if r.ContentLength != 0 {
+		var err error
+		if sub, err = h.parseSubmission(w, r, &body); err != nil {
+			writeBodyError(w, err)
+			return
+		}
+	}
+
+	if authKey.Constraints != nil && len(authKey.Constraints.CalendarAllowlist) > 0 {
+		if !apikeys.CalendarAllowed(calendarID, authKey.Constraints.CalendarAllowlist) {
+			response.WriteConstraintViolation(w, "calendar_allowlist", "calendar not in allowlist")
+			return
+		}
+	}
+
+	ctx := r.Context()
+	existing, err := h.calendarClient.GetEvent(ctx, calendarID, eventID)
+	if err != nil {
+		writeGoogleError(w, "failed to get event", err)
+		return
+	}
+	if existing == nil {
+		response.Error(w, http.StatusNotFound, "event not found", nil)
+		return
+	}
+
+	intent, err := google.IntentFromEvent(existing, calendarID)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	shift := time.Duration(body.ShiftMinutes) * time.Minute
+	intent.Start = intent.Start.Add(shift)
+	intent.End = intent.End.Add(shift)
+
+	decision, err := h.prepareCreate(authKey, intent)
+	if err != nil {
+		writeConstraintError(w, err)
+		return
+	}
+
+	// Get idempotency key
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+
+	priority, err := requestPriority(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	timeout, err := h.requestTimeout(r, authKey)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	// Marshal payload
+	payload, _ := json.Marshal(duplicatePayload{
+		EventIntent: *intent,
+		DuplicateOf: duplicateSource{
+			CalendarID: calendarID,
+			EventID:    eventID,
+			HtmlLink:   existing.HtmlLink,
+		},
+	})
+
+	// Submit request
+	req, err := h.engine.SubmitRequest(ctx, authKey, database.OperationCreateEvent, payload, sub.Context, sub.Tags, idempotencyKey, priority, timeout, decision, "policy")
+	if err != nil {
+		writeSubmitError(w, err)
+		return
+	}
+
+	statusCode := http.StatusAccepted
+	if !decision.RequiresApproval() {
+		statusCode = http.StatusOK
+	}
+	response.JSON(w, statusCode, map[string]interface{}{
+		"request_id": req.ID,
+		"status":     req.Status,
+		"expires_at": req.ExpiresAt,
+		"message":    "Event duplication request submitted",
+	})
+}
+
+// ImportEventRequest identifies an existing event either by ID or by its
+// Google Calendar link. Explicit IDs take precedence over the link.
+type ImportEventRequest struct {
+	CalendarID string `json:"calendarId,omitempty"`
+	EventID    string `json:"eventId,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+// ImportEvent fetches an existing event and returns an update intent
+// prefilled with its current values, ready to be edited and submitted.
+func (h *Handler) ImportEvent(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeEventsRead)
+	if authKey == nil {
+		return
+	}
+
+	var body ImportEventRequest
+	if err := h.parseJSON(w, r, &body); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	calendarID, eventID := body.CalendarID, body.EventID
+	if body.URL != "" {
+		urlCalendarID, urlEventID, err := google.ParseEventURL(body.URL)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		if calendarID == "" {
+			calendarID = urlCalendarID
+		}
+		if eventID == "" {
+			eventID = urlEventID
+		}
+	}
+	if calendarID == "" {
+		calendarID = apikeys.DefaultCalendar(authKey)
+	}
+
+	if err := util.ValidateCalendarID(calendarID); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if eventID == "" {
+		response.Error(w, http.StatusBadRequest, "eventId or url is required", nil)
+		return
+	}
+
+	if authKey.Constraints != nil && len(authKey.Constraints.CalendarAllowlist) > 0 {
+		if !apikeys.CalendarAllowed(calendarID, authKey.Constraints.CalendarAllowlist) {
+			response.WriteConstraintViolation(w, "calendar_allowlist", "calendar not in allowlist")
+			return
+		}
+	}
+
+	ctx := r.Context()
+	existing, err := h.calendarClient.GetEvent(ctx, calendarID, eventID)
+	if err != nil {
+		writeGoogleError(w, "failed to get event", err)
+		return
+	}
+	if existing == nil {
+		response.Error(w, http.StatusNotFound, "event not found", nil)
+		return
+	}
+
+	intent := redactUpdateIntent(google.UpdateIntentFromEvent(existing, calendarID), apikeys.RedactedFields(authKey))
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"intent":    intent,
+		"all_day":   intent.Start == nil,
+		"html_link": existing.HtmlLink,
+		"message":   "Edit the intent and submit it to /api/calendar/events/update",
+	})
+}
+
+// Helpers
+
+// invalidIntentError is a request the caller must fix before it can be
+// evaluated. It is answered with 400.
+type invalidIntentError struct {
+	err error
+}
+
+func (e *invalidIntentError) Error() string { return e.err.Error() }
+func (e *invalidIntentError) Unwrap() error { return e.err }
+
+// The prepare functions fill in defaults, validate and sanitize an intent,
+// apply the key's overrides and evaluate its constraints. Submissions and
+// pre-flight checks share them, so both see the same outcome.
+
+func (h *Handler) prepareCreate(authKey *apikeys.AuthenticatedKey, intent *google.EventIntent) (apikeys.ConstraintDecision, error) {
+	if intent.CalendarID == "" {
+		intent.CalendarID = apikeys.DefaultCalendar(authKey)
+	}
+	if err := intent.Validate(); err != nil {
+		return apikeys.ConstraintDecision{}, &invalidIntentError{err}
+	}
+	intent.Sanitize(apikeys.HTMLDescriptionAllowed(authKey))
+	applyCreateOverrides(authKey, intent)
+	return h.applyRequireAll(h.evaluateConstraintsForCreate(authKey, intent))
+}
+
+func (h *Handler) prepareBatch(authKey *apikeys.AuthenticatedKey, batch *google.EventBatchIntent) (apikeys.ConstraintDecision, error) {
+	if err := batch.Expand(); err != nil {
+		return apikeys.ConstraintDecision{}, &invalidIntentError{err}
+	}
+	for i := range batch.Events {
+		if batch.Events[i].CalendarID == "" {
+			batch.Events[i].CalendarID = apikeys.DefaultCalendar(authKey)
+		}
+	}
+	if err := batch.Validate(); err != nil {
+		return apikeys.ConstraintDecision{}, &invalidIntentError{err}
+	}
+	batch.Sanitize(apikeys.HTMLDescriptionAllowed(authKey))
+	for i := range batch.Events {
+		applyCreateOverrides(authKey, &batch.Events[i])
+	}
+	return h.applyRequireAll(h.evaluateConstraintsForBatch(authKey, batch))
+}
+
+func (h *Handler) prepareUpdate(ctx context.Context, authKey *apikeys.AuthenticatedKey, intent *google.EventUpdateIntent) (apikeys.ConstraintDecision, error) {
+	if intent.CalendarID == "" {
+		intent.CalendarID = apikeys.DefaultCalendar(authKey)
+	}
+	if err := intent.Validate(); err != nil {
+		return apikeys.ConstraintDecision{}, &invalidIntentError{err}
+	}
+	if !intent.HasChanges() {
+		return apikeys.ConstraintDecision{}, &invalidIntentError{errors.New("no changes provided")}
+	}
+	sanitizeUpdateIntent(intent, apikeys.HTMLDescriptionAllowed(authKey))
+	applyUpdateOverrides(authKey, intent)
+	return h.applyRequireAll(h.evaluateConstraintsForUpdate(ctx, authKey, intent))
+}
+
+func (h *Handler) prepareDelete(authKey *apikeys.AuthenticatedKey, intent *google.EventDeleteIntent) (apikeys.ConstraintDecision, error) {
+	if intent.CalendarID == "" {
+		intent.CalendarID = apikeys.DefaultCalendar(authKey)
+	}
+	if err := intent.Validate(); err != nil {
+		return apikeys.ConstraintDecision{}, &invalidIntentError{err}
+	}
+	if mode := apikeys.ForcedSendUpdates(authKey); mode != "" {
+		intent.SendUpdates = mode
+	}
+	return h.applyRequireAll(h.evaluateConstraintsForDelete(authKey, intent))
+}
+
+func (h *Handler) prepareMove(authKey *apikeys.AuthenticatedKey, intent *google.EventMoveIntent) (apikeys.ConstraintDecision, error) {
+	if err := intent.Validate(); err != nil {
+		return apikeys.ConstraintDecision{}, &invalidIntentError{err}
+	}
+	return h.applyRequireAll(h.evaluateConstraintsForMove(authKey, intent))
+}
+
+// applyRequireAll holds a request the constraints would let run right away
+// when approval.require_all is on. Denials and errors pass through.
+func (h *Handler) applyRequireAll(decision apikeys.ConstraintDecision, err error) (apikeys.ConstraintDecision, error) {
+	if err != nil || h.config == nil || !h.config.Approval.RequireAll || decision.Result != apikeys.ConstraintAllow {
+		return decision, err
+	}
+	return apikeys.ConstraintDecision{
+		Result:     apikeys.ConstraintRequireApproval,
+		Constraint: "require_all",
+		Reason:     "Every write requires approval while approval.require_all is on",
+	}, nil
+}
+
+func (h *Handler) evaluateConstraintsForCreate(authKey *apikeys.AuthenticatedKey, intent *google.EventIntent) (apikeys.ConstraintDecision, error) {
+	if violation := apikeys.EvaluateEventProperties(authKey, &intent.Visibility, &intent.Transparency); violation != nil {
+		return denied(authKey, database.OperationCreateEvent, violation)
+	}
+	if violation := apikeys.EvaluateRooms(authKey, intent.Rooms); violation != nil {
+		return denied(authKey, database.OperationCreateEvent, violation)
+	}
+
+	decision := apikeys.EvaluateConstraints(
+		authKey,
+		database.OperationCreateEvent,
+		intent.CalendarID,
+		intent.AllAttendees(),
+		intent.Start,
+		intent.End,
+	)
+	return handleConstraintResult(authKey, database.OperationCreateEvent, decision)
+}
+
+// evaluateConstraintsForBatch applies the create constraints to every event.
+// Any denial rejects the whole batch; any event needing approval makes the
+// batch need approval, and the first such event explains why.
+func (h *Handler) evaluateConstraintsForBatch(authKey *apikeys.AuthenticatedKey, batch *google.EventBatchIntent) (apikeys.ConstraintDecision, error) {
+	var held *apikeys.ConstraintDecision
+	if authKey.Constraints != nil {
+		switch authKey.Constraints.Operations[database.OperationCreateEventsBatch] {
+		case "deny":
+			return denied(authKey, database.OperationCreateEventsBatch, &apikeys.ConstraintViolation{
+				Constraint: "operation",
+				Message:    fmt.Sprintf("Operation %s is not allowed for this API key", database.OperationCreateEventsBatch),
+			})
+		case "require_approval":
+			held = &apikeys.ConstraintDecision{
+				Result:     apikeys.ConstraintRequireApproval,
+				Constraint: "operation",
+				Reason:     fmt.Sprintf("Operation %s always requires approval for this API key", database.OperationCreateEventsBatch),
+			}
+		}
+	}
+
+	var last apikeys.ConstraintDecision
+	for i := range batch.Events {
+		decision, err := h.evaluateConstraintsForCreate(authKey, &batch.Events[i])
+		if err != nil {
+			return decision, err
+		}
+		if held == nil && decision.RequiresApproval() {
+			held = &decision
+		}
+		last = decision
+	}
+	if held != nil {
+		return *held, nil
+	}
+	return last, nil
+}
+
+func (h *Handler) evaluateConstraintsForUpdate(ctx context.Context, authKey *apikeys.AuthenticatedKey, intent *google.EventUpdateIntent) (apikeys.ConstraintDecision, error) {
+	// Only fields the update actually sets are checked against the allowlists
+	if violation := apikeys.EvaluateEventProperties(authKey, intent.Visibility, intent.Transparency); violation != nil {
+		return denied(authKey, database.OperationUpdateEvent, violation)
+	}
 
-func (h *Handler) evaluateConstraintsForUpdate(ctx context.Context, authKey *apikeys.AuthenticatedKey, intent *google.EventUpdateIntent) (bool, error) {
 	// If no constraints, rely on tier defaults only.
 	if authKey.Constraints == nil {
-		result, violation := apikeys.EvaluateConstraints(
+		decision := apikeys.EvaluateConstraints(
 			authKey,
 			database.OperationUpdateEvent,
 			intent.CalendarID,
@@ -421,14 +1338,18 @@ func (h *Handler) evaluateConstraintsForUpdate(ctx context.Context, authKey *api
 			time.Now(),
 			time.Now(),
 		)
-		return handleConstraintResult(result, violation)
+		return handleConstraintResult(authKey, database.OperationUpdateEvent, decision)
 	}
 
-	// Fetch existing event to compute effective values
-	existing, err := h.calendarClient.GetEvent(ctx, intent.CalendarID, intent.EventID)
+	// Fetch existing event to compute effective values, never from the cache
+	existing, err := h.calendarClient.GetEvent(google.BypassCache(ctx), intent.CalendarID, intent.TargetEventID())
 	if err != nil || existing == nil {
 		// Fail closed: require approval if we cannot evaluate safely
-		return true, nil
+		return apikeys.ConstraintDecision{
+			Result:     apikeys.ConstraintRequireApproval,
+			Constraint: "event_lookup",
+			Reason:     "The current event could not be loaded to check the key's constraints",
+		}, nil
 	}
 
 	start := extractEventTime(existing.Start)
@@ -447,24 +1368,36 @@ func (h *Handler) evaluateConstraintsForUpdate(ctx context.Context, authKey *api
 
 	if !start.IsZero() && !end.IsZero() {
 		if err := util.ValidateTimeRange(start, end, false); err != nil {
-			return false, err
+			return apikeys.ConstraintDecision{}, err
 		}
 	}
 
-	result, violation := apikeys.EvaluateConstraints(
+	decision, err := handleConstraintResult(authKey, database.OperationUpdateEvent, apikeys.EvaluateConstraints(
 		authKey,
 		database.OperationUpdateEvent,
 		intent.CalendarID,
 		attendees,
 		start,
 		end,
-	)
-	return handleConstraintResult(result, violation)
+	))
+	if err != nil || !decision.RequiresApproval() {
+		return decision, err
+	}
+
+	// Denials above always win; whitelisted fields only lift an approval requirement.
+	if apikeys.CanAutoApproveFields(authKey, changedUpdateFields(intent, existing)) {
+		return apikeys.ConstraintDecision{
+			Result:     apikeys.ConstraintAllow,
+			Constraint: "auto_approve_fields",
+			Reason:     "The update only changes fields this key may change without approval",
+		}, nil
+	}
+	return decision, nil
 }
 
-func (h *Handler) evaluateConstraintsForDelete(authKey *apikeys.AuthenticatedKey, intent *google.EventDeleteIntent) (bool, error) {
+func (h *Handler) evaluateConstraintsForDelete(authKey *apikeys.AuthenticatedKey, intent *google.EventDeleteIntent) (apikeys.ConstraintDecision, error) {
 	now := time.Now()
-	result, violation := apikeys.EvaluateConstraints(
+	decision := apikeys.EvaluateConstraints(
 		authKey,
 		database.OperationDeleteEvent,
 		intent.CalendarID,
@@ -472,25 +1405,66 @@ func (h *Handler) evaluateConstraintsForDelete(authKey *apikeys.AuthenticatedKey
 		now,
 		now,
 	)
-	return handleConstraintResult(result, violation)
+	return handleConstraintResult(authKey, database.OperationDeleteEvent, decision)
 }
 
-func handleConstraintResult(result apikeys.ConstraintResult, violation *apikeys.ConstraintViolation) (bool, error) {
-	switch result {
-	case apikeys.ConstraintDeny:
-		if violation != nil {
-			return false, violation
+// evaluateConstraintsForMove checks both the source and destination calendars.
+// Both must be allowed; approval is required if either calendar requires it.
+func (h *Handler) evaluateConstraintsForMove(authKey *apikeys.AuthenticatedKey, intent *google.EventMoveIntent) (apikeys.ConstraintDecision, error) {
+	now := time.Now()
+	var result apikeys.ConstraintDecision
+	for _, calendarID := range []string{intent.CalendarID, intent.DestinationCalendarID} {
+		decision, err := handleConstraintResult(authKey, database.OperationMoveEvent, apikeys.EvaluateConstraints(
+			authKey,
+			database.OperationMoveEvent,
+			calendarID,
+			nil,
+			now,
+			now,
+		))
+		if err != nil {
+			return decision, err
+		}
+		if !result.RequiresApproval() {
+			result = decision
 		}
-		return false, fmt.Errorf("operation denied by policy")
-	case apikeys.ConstraintRequireApproval:
-		return true, nil
-	default:
-		return false, nil
 	}
+	return result, nil
+}
+
+// handleConstraintResult turns a denial into an error and logs which
+// constraint decided the outcome.
+func handleConstraintResult(authKey *apikeys.AuthenticatedKey, operation string, decision apikeys.ConstraintDecision) (apikeys.ConstraintDecision, error) {
+	if violation := decision.Violation(); violation != nil {
+		return denied(authKey, operation, violation)
+	}
+	util.Debug("Constraint decision",
+		"api_key_id", authKey.ID,
+		"operation", operation,
+		"result", decision.Result.String(),
+		"constraint", decision.Constraint,
+	)
+	return decision, nil
+}
+
+// denied logs a policy denial and returns it as the evaluation error.
+func denied(authKey *apikeys.AuthenticatedKey, operation string, violation *apikeys.ConstraintViolation) (apikeys.ConstraintDecision, error) {
+	util.Info("Request denied by policy",
+		"api_key_id", authKey.ID,
+		"operation", operation,
+		"constraint", violation.Constraint,
+		"reason", violation.Message,
+	)
+	return apikeys.ConstraintDecision{
+		Result:     apikeys.ConstraintDeny,
+		Constraint: violation.Constraint,
+		Reason:     violation.Message,
+	}, violation
 }
 
 func writeConstraintError(w http.ResponseWriter, err error) {
-	if errors.Is(err, util.ErrPastTime) || errors.Is(err, util.ErrEndBeforeStart) {
+	var invalid *invalidIntentError
+	if errors.As(err, &invalid) || errors.Is(err, util.ErrPastTime) || errors.Is(err, util.ErrEndBeforeStart) {
 		response.Error(w, http.StatusBadRequest, err.Error(), nil)
 		return
 	}
@@ -501,19 +1475,161 @@ func writeConstraintError(w http.ResponseWriter, err error) {
 	response.Error(w, http.StatusForbidden, err.Error(), nil)
 }
 
-func calendarAllowed(calendarID string, allowlist []string) bool {
-	for _, allowed := range allowlist {
-		if allowed == "*" || allowed == calendarID {
-			return true
+// writeSubmitError maps an engine.SubmitRequest error to a response.
+func writeSubmitError(w http.ResponseWriter, err error) {
+	var limitErr *engine.PendingLimitError
+	if errors.As(err, &limitErr) {
+		response.WriteErrorWithDetails(w, http.StatusTooManyRequests, response.ErrCodeConstraintViolation,
+			limitErr.Error(), "", map[string]interface{}{
+				"constraint": "max_pending_requests",
+				"limit":      limitErr.Limit,
+				"pending":    limitErr.Pending,
+			})
+		return
+	}
+	response.Error(w, http.StatusInternalServerError, "failed to submit request", err)
+}
+
+// requestPriority reads the optional X-Request-Priority header.
+// A missing header means normal priority.
+func requestPriority(r *http.Request) (string, error) {
+	priority := strings.ToLower(strings.TrimSpace(r.Header.Get("X-Request-Priority")))
+	if priority == "" {
+		return database.PriorityNormal, nil
+	}
+	if !database.ValidPriority(priority) {
+		return "", fmt.Errorf("invalid X-Request-Priority %q: must be low, normal, or high", priority)
+	}
+	return priority, nil
+}
+
+// requestTimeout reads the optional X-Approval-Timeout-Minutes header,
+// which lets a caller give one request a shorter or longer approval window
+// than the configured timeout. A missing header returns 0, meaning the
+// configured timeout. When a timed-out request is approved, the window may
+// not be shorter than the configured one, or a caller could skip the
+// approver by picking a window nobody can answer in time.
+func (h *Handler) requestTimeout(r *http.Request, authKey *apikeys.AuthenticatedKey) (time.Duration, error) {
+	value := strings.TrimSpace(r.Header.Get("X-Approval-Timeout-Minutes"))
+	if value == "" {
+		return 0, nil
+	}
+	limits := h.config.Approval
+	if limits.MaxRequestTimeoutMinutes <= 0 {
+		return 0, fmt.Errorf("X-Approval-Timeout-Minutes is not enabled on this server")
+	}
+	minutes, err := strconv.Atoi(value)
+	if err != nil || minutes < limits.MinRequestTimeoutMinutes || minutes > limits.MaxRequestTimeoutMinutes {
+		return 0, fmt.Errorf("invalid X-Approval-Timeout-Minutes %q: must be a whole number of minutes from %d to %d",
+			value, limits.MinRequestTimeoutMinutes, limits.MaxRequestTimeoutMinutes)
+	}
+	if minutes < limits.TimeoutMinutes && timeoutAction(limits, authKey) == "approve" {
+		return 0, fmt.Errorf("invalid X-Approval-Timeout-Minutes %q: requests from this key are approved when they time out, so the window cannot be shorter than %d minutes",
+			value, limits.TimeoutMinutes)
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+// timeoutAction returns what happens to the key's requests when approval
+// times out: the key's timeout_action, else the configured default action,
+// else deny. It matches the timeout worker.
+func timeoutAction(approval config.ApprovalConfig, authKey *apikeys.AuthenticatedKey) string {
+	if authKey != nil && authKey.Constraints != nil && authKey.Constraints.TimeoutAction != "" {
+		return authKey.Constraints.TimeoutAction
+	}
+	if approval.DefaultAction != "" {
+		return approval.DefaultAction
+	}
+	return "deny"
+}
+
+// googleBusyRetryAfter is the Retry-After sent when the Google call limit
+// turns a read away.
+const googleBusyRetryAfter = 2 * time.Second
+
+// writeGoogleError writes the response for a failed Google call. A call
+// refused by the client's concurrency limit gets 503 with Retry-After, so
+// callers back off rather than treat Google as down.
+func writeGoogleError(w http.ResponseWriter, message string, err error) {
+	if errors.Is(err, google.ErrTooManyCalls) {
+		response.SetRetryAfter(w, googleBusyRetryAfter)
+		response.Error(w, http.StatusServiceUnavailable, "too many concurrent Google Calendar calls; retry shortly", nil)
+		return
+	}
+	response.Error(w, http.StatusInternalServerError, message, err)
+}
+
+// redactEvents returns the events with the key's redact_fields hidden.
+func redactEvents(authKey *apikeys.AuthenticatedKey, events []google.Event) []google.Event {
+	fields := apikeys.RedactedFields(authKey)
+	if len(fields) == 0 {
+		return events
+	}
+	redacted := make([]google.Event, len(events))
+	for i, event := range events {
+		redacted[i] = redactEvent(event, fields)
+	}
+	return redacted
+}
+
+// redactEvent returns a copy of the event with the given fields cleared. The
+// copy never shares the attendee list or people with the original, so events
+// held elsewhere are left untouched.
+func redactEvent(event google.Event, fields []string) google.Event {
+	for _, field := range fields {
+		switch strings.ToLower(field) {
+		case "summary":
+			event.Summary = ""
+		case "description":
+			event.Description = ""
+		case "location":
+			event.Location = ""
+		case "attendees":
+			if len(event.Attendees) == 0 {
+				continue
+			}
+			attendees := make([]google.Attendee, len(event.Attendees))
+			for i, attendee := range event.Attendees {
+				attendee.Email = ""
+				attendee.DisplayName = ""
+				attendees[i] = attendee
+			}
+			event.Attendees = attendees
+		case "organizer":
+			event.Creator = nil
+			event.Organizer = nil
+		case "extended_properties":
+			event.ExtendedProperties = nil
 		}
 	}
-	return false
+	return event
+}
+
+// redactUpdateIntent removes the given fields from an imported intent.
+// They are left out rather than emptied, so submitting the intent as an
+// update keeps the event's hidden values instead of clearing them.
+func redactUpdateIntent(intent *google.EventUpdateIntent, fields []string) *google.EventUpdateIntent {
+	for _, field := range fields {
+		switch strings.ToLower(field) {
+		case "summary":
+			intent.Summary = nil
+		case "description":
+			intent.Description = nil
+		case "location":
+			intent.Location = nil
+		case "attendees":
+			intent.Attendees = nil
+		case "extended_properties":
+			intent.ExtendedProperties = nil
+		}
+	}
+	return intent
 }
 
 func filterCalendars(calendars []google.Calendar, allowlist []string) []google.Calendar {
 	var filtered []google.Calendar
 	for _, cal := range calendars {
-		if calendarAllowed(cal.ID, allowlist) {
+		if apikeys.CalendarAllowed(cal.ID, allowlist) {
 			filtered = append(filtered, cal)
 		}
 	}
@@ -548,13 +1664,116 @@ func extractAttendees(attendees []google.Attendee) []string {
 	return result
 }
 
-func sanitizeUpdateIntent(intent *google.EventUpdateIntent) {
+// changedUpdateFields lists the intent fields whose values differ from the
+// existing event, using the JSON field names of EventUpdateIntent. Asking
+// Google to email attendees or widening the edit beyond eventId counts as a
+// change too, since neither shows up in the event itself.
+func changedUpdateFields(intent *google.EventUpdateIntent, existing *google.Event) []string {
+	var changed []string
+
+	// Google sends no update emails unless asked
+	if intent.SendUpdates != "" && intent.SendUpdates != "none" {
+		changed = append(changed, "sendUpdates")
+	}
+	if intent.RecurringEditScope != "" && intent.RecurringEditScope != google.RecurringEditInstance {
+		changed = append(changed, "recurringEditScope")
+	}
+
+	if intent.Summary != nil && *intent.Summary != existing.Summary {
+		changed = append(changed, "summary")
+	}
+	if intent.Description != nil && *intent.Description != existing.Description {
+		changed = append(changed, "description")
+	}
+	if intent.Location != nil && *intent.Location != existing.Location {
+		changed = append(changed, "location")
+	}
+	if intent.Start != nil && !intent.Start.Equal(extractEventTime(existing.Start)) {
+		changed = append(changed, "start")
+	}
+	if intent.End != nil && !intent.End.Equal(extractEventTime(existing.End)) {
+		changed = append(changed, "end")
+	}
+	if len(intent.Attendees) > 0 && !sameEmails(intent.Attendees, extractAttendees(existing.Attendees)) {
+		changed = append(changed, "attendees")
+	}
+	if intent.ColorID != nil && *intent.ColorID != existing.ColorId {
+		changed = append(changed, "colorId")
+	}
+	if intent.Visibility != nil && *intent.Visibility != existing.Visibility {
+		changed = append(changed, "visibility")
+	}
+	if intent.Transparency != nil && *intent.Transparency != existing.Transparency {
+		changed = append(changed, "transparency")
+	}
+	if intent.GuestsCanModify != nil && *intent.GuestsCanModify != existing.GuestsCanModify {
+		changed = append(changed, "guestsCanModify")
+	}
+	if intent.GuestsCanInviteOthers != nil && *intent.GuestsCanInviteOthers != existing.GuestsCanInviteOthers {
+		changed = append(changed, "guestsCanInviteOthers")
+	}
+	if intent.GuestsCanSeeOtherGuests != nil && *intent.GuestsCanSeeOtherGuests != existing.GuestsCanSeeOtherGuests {
+		changed = append(changed, "guestsCanSeeOtherGuests")
+	}
+	if intent.Reminders != nil && !reflect.DeepEqual(intent.Reminders, existing.Reminders) {
+		changed = append(changed, "reminders")
+	}
+	for key, value := range intent.ExtendedProperties {
+		if current, ok := existing.ExtendedProperties[key]; !ok || current != value {
+			changed = append(changed, "extendedProperties")
+			break
+		}
+	}
+
+	return changed
+}
+
+// sameEmails reports whether two attendee lists contain the same addresses, ignoring order and case.
+func sameEmails(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, email := range a {
+		counts[strings.ToLower(email)]++
+	}
+	for _, email := range b {
+		key := strings.ToLower(email)
+		if counts[key] == 0 {
+			return false
+		}
+		counts[key]--
+	}
+	return true
+}
+
+// applyCreateOverrides applies the settings a key forces on new events.
+func applyCreateOverrides(authKey *apikeys.AuthenticatedKey, intent *google.EventIntent) {
+	if apikeys.GuestInvitesDisabled(authKey) {
+		intent.GuestsCanInviteOthers = new(bool)
+	}
+	if mode := apikeys.ForcedSendUpdates(authKey); mode != "" {
+		intent.SendUpdates = mode
+	}
+}
+
+// applyUpdateOverrides applies the settings a key forces on updated events.
+func applyUpdateOverrides(authKey *apikeys.AuthenticatedKey, intent *google.EventUpdateIntent) {
+	if apikeys.GuestInvitesDisabled(authKey) {
+		intent.GuestsCanInviteOthers = new(bool)
+	}
+	if mode := apikeys.ForcedSendUpdates(authKey); mode != "" {
+		intent.SendUpdates = mode
+	}
+}
+
+func sanitizeUpdateIntent(intent *google.EventUpdateIntent, allowHTML bool) {
 	if intent.Summary != nil {
 		v := util.SanitizeString(*intent.Summary)
 		intent.Summary = &v
 	}
 	if intent.Description != nil {
-		v := util.SanitizeString(*intent.Description)
+		v := util.SanitizeDescription(*intent.Description, allowHTML)
 		intent.Description = &v
 	}
 	if intent.Location != nil {
@@ -2,38 +2,85 @@ package api
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/engine"
 	"github.com/dtorcivia/schedlock/internal/google"
+	"github.com/dtorcivia/schedlock/internal/requests"
+	"github.com/dtorcivia/schedlock/internal/response"
 	"github.com/dtorcivia/schedlock/internal/server/middleware"
+	"github.com/dtorcivia/schedlock/internal/util"
 )
 
 type fakeCalendarClient struct {
+	mu       sync.Mutex
 	lastOpts google.EventListOptions
 	resp     *google.EventListResponse
+	event    *google.Event
 	err      error
+
+	// Per-calendar responses and errors, used instead of resp/err when set
+	eventsByCalendar map[string]*google.EventListResponse
+	errByCalendar    map[string]error
+
+	// Responses by page token, used instead of resp when set
+	eventsByPage map[string]*google.EventListResponse
+	listCalls    int
+
+	lastFreeBusy *google.FreeBusyRequest
+	freeBusy     *google.FreeBusyResponse
+
+	lastGetCalendarID string
+	lastGetEventID    string
+	moveCalls         int
+
+	calendars []google.Calendar
+
+	colors     *google.ColorPalette
+	colorsErr  error
+	colorCalls int
 }
 
 func (f *fakeCalendarClient) ListCalendars(ctx context.Context) ([]google.Calendar, error) {
-	return nil, nil
+	return f.calendars, nil
 }
 
 func (f *fakeCalendarClient) ListEvents(ctx context.Context, opts google.EventListOptions) (*google.EventListResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.lastOpts = opts
+	f.listCalls++
+	if f.eventsByPage != nil {
+		return f.eventsByPage[opts.PageToken], nil
+	}
+	if f.eventsByCalendar != nil || f.errByCalendar != nil {
+		return f.eventsByCalendar[opts.CalendarID], f.errByCalendar[opts.CalendarID]
+	}
 	return f.resp, f.err
 }
 
 func (f *fakeCalendarClient) GetEvent(ctx context.Context, calendarID, eventID string) (*google.Event, error) {
-	return nil, nil
+	f.lastGetCalendarID = calendarID
+	f.lastGetEventID = eventID
+	return f.event, nil
 }
 
 func (f *fakeCalendarClient) FreeBusy(ctx context.Context, req *google.FreeBusyRequest) (*google.FreeBusyResponse, error) {
-	return nil, nil
+	f.lastFreeBusy = req
+	return f.freeBusy, nil
 }
 
 func (f *fakeCalendarClient) CreateEvent(ctx context.Context, intent *google.EventIntent) (*google.Event, error) {
@@ -48,6 +95,16 @@ func (f *fakeCalendarClient) DeleteEvent(ctx context.Context, intent *google.Eve
 	return nil
 }
 
+func (f *fakeCalendarClient) MoveEvent(ctx context.Context, intent *google.EventMoveIntent) (*google.Event, error) {
+	f.moveCalls++
+	return nil, nil
+}
+
+func (f *fakeCalendarClient) GetColors(ctx context.Context) (*google.ColorPalette, error) {
+	f.colorCalls++
+	return f.colors, f.colorsErr
+}
+
 func TestListEventsQueryParamsAndPagination(t *testing.T) {
 	fake := &fakeCalendarClient{
 		resp: &google.EventListResponse{
@@ -113,6 +170,59 @@ func TestListEventsQueryParamsAndPagination(t *testing.T) {
 	}
 }
 
+func TestListEndpointsPaginationEnvelope(t *testing.T) {
+	fake := &fakeCalendarClient{
+		resp: &google.EventListResponse{
+			Events:        []google.Event{{ID: "evt1"}, {ID: "evt2"}},
+			NextPageToken: "next123",
+		},
+		calendars: []google.Calendar{{ID: "primary"}},
+	}
+	h := &Handler{calendarClient: fake}
+	key := &apikeys.AuthenticatedKey{ID: "key1", Tier: "read"}
+
+	tests := []struct {
+		name      string
+		url       string
+		handler   http.HandlerFunc
+		count     int
+		nextToken string
+	}{
+		{"events", "/api/calendar/primary/events?envelope=true", h.ListEvents, 2, "next123"},
+		{"calendars", "/api/calendar/list?envelope=1", h.ListCalendars, 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			req.SetPathValue("calendarId", "primary")
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, key))
+			rr := httptest.NewRecorder()
+			tt.handler(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+			var resp struct {
+				Data       []map[string]interface{} `json:"data"`
+				Pagination map[string]interface{}   `json:"pagination"`
+				Events     interface{}              `json:"events"`
+			}
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Data) != tt.count || resp.Pagination["count"] != float64(tt.count) {
+				t.Errorf("data = %v, pagination = %v, want %d items", resp.Data, resp.Pagination, tt.count)
+			}
+			if token, _ := resp.Pagination["next_page_token"].(string); token != tt.nextToken {
+				t.Errorf("next_page_token = %q, want %q", token, tt.nextToken)
+			}
+			if resp.Events != nil {
+				t.Errorf("envelope should not carry the original fields")
+			}
+		})
+	}
+}
+
 func TestListEventsInvalidSingleEvents(t *testing.T) {
 	fake := &fakeCalendarClient{
 		resp: &google.EventListResponse{},
@@ -137,3 +247,1280 @@ func TestListEventsInvalidSingleEvents(t *testing.T) {
 		t.Fatalf("expected status 400, got %d", rr.Code)
 	}
 }
+
+func TestCreateEventOversizedBody(t *testing.T) {
+	h := &Handler{
+		config:         &config.Config{Server: config.ServerConfig{MaxBodyBytes: 64}},
+		calendarClient: &fakeCalendarClient{},
+	}
+
+	body := `{"calendarId":"primary","summary":"` + strings.Repeat("x", 256) + `"}`
+	req := httptest.NewRequest("POST", "http://example.com/api/calendar/events/create", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: "write",
+	}))
+
+	rr := httptest.NewRecorder()
+	h.CreateEvent(rr, req)
+
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status 413, got %d", rr.Code)
+	}
+}
+
+func TestRequestTimeoutHeader(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Approval.MinRequestTimeoutMinutes = 5
+	cfg.Approval.MaxRequestTimeoutMinutes = 120
+	h := &Handler{config: cfg}
+
+	tests := []struct {
+		header  string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"10", 10 * time.Minute, false},
+		{" 5 ", 5 * time.Minute, false},
+		{"120", 120 * time.Minute, false},
+		{"4", 0, true},
+		{"121", 0, true},
+		{"0", 0, true},
+		{"-10", 0, true},
+		{"1.5", 0, true},
+		{"10m", 0, true},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "http://example.com/api/calendar/events/create", nil)
+		if tt.header != "" {
+			req.Header.Set("X-Approval-Timeout-Minutes", tt.header)
+		}
+		got, err := h.requestTimeout(req, nil)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%q: got %v, %v; want %v (error %v)", tt.header, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	// A max of 0 turns the header off
+	cfg.Approval.MaxRequestTimeoutMinutes = 0
+	req := httptest.NewRequest("POST", "http://example.com/api/calendar/events/create", nil)
+	req.Header.Set("X-Approval-Timeout-Minutes", "10")
+	if _, err := h.requestTimeout(req, nil); err == nil {
+		t.Error("expected the header to be rejected when disabled")
+	}
+}
+
+func TestRequestTimeoutCannotHurryAutoApproval(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Approval.TimeoutMinutes = 60
+	cfg.Approval.MinRequestTimeoutMinutes = 1
+	cfg.Approval.MaxRequestTimeoutMinutes = 1440
+	h := &Handler{config: cfg}
+	keyWith := func(action string) *apikeys.AuthenticatedKey {
+		return &apikeys.AuthenticatedKey{ID: "key1", Tier: database.TierWrite, Constraints: &database.KeyConstraints{TimeoutAction: action}}
+	}
+
+	tests := []struct {
+		name          string
+		defaultAction string
+		key           *apikeys.AuthenticatedKey
+		header        string
+		wantErr       bool
+	}{
+		{"deny allows a short window", "deny", nil, "1", false},
+		{"unset action denies", "", nil, "1", false},
+		{"approve rejects a short window", "approve", nil, "1", true},
+		{"approve rejects just under the timeout", "approve", nil, "59", true},
+		{"approve allows the timeout", "approve", nil, "60", false},
+		{"approve allows a longer window", "approve", nil, "120", false},
+		{"key approve overrides config deny", "deny", keyWith("approve"), "10", true},
+		{"key deny overrides config approve", "approve", keyWith("deny"), "10", false},
+	}
+	for _, tt := range tests {
+		cfg.Approval.DefaultAction = tt.defaultAction
+		req := httptest.NewRequest("POST", "http://example.com/api/calendar/events/create", nil)
+		req.Header.Set("X-Approval-Timeout-Minutes", tt.header)
+		if _, err := h.requestTimeout(req, tt.key); (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// The handler turns the rejection into a 400 before creating a request
+	cfg.Approval.DefaultAction = "approve"
+	body := `{"calendarId":"primary","summary":"Sync","start":"2030-01-01T10:00:00Z","end":"2030-01-01T11:00:00Z"}`
+	req := httptest.NewRequest("POST", "http://example.com/api/calendar/events/create", strings.NewReader(body))
+	req.Header.Set("X-Approval-Timeout-Minutes", "1")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{ID: "key1", Tier: database.TierWrite}))
+	h.calendarClient = &fakeCalendarClient{}
+	rr := httptest.NewRecorder()
+	h.CreateEvent(rr, req)
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "X-Approval-Timeout-Minutes") {
+		t.Errorf("expected a 400 for the header, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestEvaluateConstraintsForUpdateAutoApproveFields(t *testing.T) {
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	fake := &fakeCalendarClient{
+		event: &google.Event{
+			ID:          "evt1",
+			Summary:     "Standup",
+			Description: "old notes",
+			Start:       &google.EventTime{DateTime: start},
+			End:         &google.EventTime{DateTime: start.Add(30 * time.Minute)},
+			Attendees:   []google.Attendee{{Email: "a@example.com"}},
+
+			ExtendedProperties: map[string]string{"ticket": "T-1"},
+		},
+	}
+	h := &Handler{calendarClient: fake}
+
+	authKey := &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: database.TierWrite,
+		Constraints: &database.KeyConstraints{
+			CalendarAllowlist: []string{"primary"},
+			AutoApproveFields: []string{"description", "reminders", "colorId", "start"},
+		},
+	}
+
+	description := "new notes"
+	sameSummary := "Standup"
+	newStart := start.Add(time.Hour)
+	newEnd := newStart.Add(30 * time.Minute)
+	color := "5"
+
+	tests := []struct {
+		name         string
+		intent       google.EventUpdateIntent
+		wantApproval bool
+		wantDeny     bool
+	}{
+		{"description only", google.EventUpdateIntent{CalendarID: "primary", EventID: "evt1", Description: &description}, false, false},
+		{"unchanged summary ignored", google.EventUpdateIntent{CalendarID: "primary", EventID: "evt1", Description: &description, Summary: &sameSummary}, false, false},
+		{"same attendees ignored", google.EventUpdateIntent{CalendarID: "primary", EventID: "evt1", ColorID: &color, Attendees: []string{"A@example.com"}}, false, false},
+		{"time change", google.EventUpdateIntent{CalendarID: "primary", EventID: "evt1", Description: &description, Start: &newStart, End: &newEnd}, true, false},
+		{"attendee change", google.EventUpdateIntent{CalendarID: "primary", EventID: "evt1", Attendees: []string{"b@example.com"}}, true, false},
+		{"no effective change", google.EventUpdateIntent{CalendarID: "primary", EventID: "evt1", Summary: &sameSummary}, true, false},
+		{"unchanged property ignored", google.EventUpdateIntent{CalendarID: "primary", EventID: "evt1", Description: &description, ExtendedProperties: map[string]string{"ticket": "T-1"}}, false, false},
+		{"property change", google.EventUpdateIntent{CalendarID: "primary", EventID: "evt1", Description: &description, ExtendedProperties: map[string]string{"ticket": "T-2"}}, true, false},
+		{"update emails", google.EventUpdateIntent{CalendarID: "primary", EventID: "evt1", Description: &description, SendUpdates: "all"}, true, false},
+		{"update emails off", google.EventUpdateIntent{CalendarID: "primary", EventID: "evt1", Description: &description, SendUpdates: "none"}, false, false},
+		{"series edit", google.EventUpdateIntent{CalendarID: "primary", EventID: "evt1_20300101T100000Z", Description: &description, RecurringEditScope: google.RecurringEditFollowing}, true, false},
+		{"instance edit", google.EventUpdateIntent{CalendarID: "primary", EventID: "evt1_20300101T100000Z", Description: &description, RecurringEditScope: google.RecurringEditInstance}, false, false},
+		{"calendar not allowed", google.EventUpdateIntent{CalendarID: "other", EventID: "evt1", Description: &description}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intent := tt.intent
+			approval, err := h.evaluateConstraintsForUpdate(context.Background(), authKey, &intent)
+			if tt.wantDeny {
+				if err == nil {
+					t.Fatalf("expected constraint denial, got approval=%v", approval)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if approval.RequiresApproval() != tt.wantApproval {
+				t.Errorf("approval required = %v, want %v", approval.RequiresApproval(), tt.wantApproval)
+			}
+		})
+	}
+}
+
+func TestEvaluateConstraintsForMove(t *testing.T) {
+	h := &Handler{}
+	teamRule := database.ConstraintRule{
+		Action: "require_approval",
+		When:   database.RuleCondition{Field: "calendar", Op: "==", Value: json.RawMessage(`"team@example.com"`)},
+	}
+
+	tests := []struct {
+		name           string
+		constraints    *database.KeyConstraints
+		source, target string
+		wantApproval   bool
+		wantConstraint string // set for denials
+	}{
+		{"target outside allowlist", &database.KeyConstraints{CalendarAllowlist: []string{"primary"}}, "primary", "team@example.com", false, "calendar_allowlist"},
+		{"source outside allowlist", &database.KeyConstraints{CalendarAllowlist: []string{"team@example.com"}}, "primary", "team@example.com", false, "calendar_allowlist"},
+		{"target needs approval", &database.KeyConstraints{Rules: []database.ConstraintRule{teamRule}}, "primary", "team@example.com", true, ""},
+		{"source needs approval", &database.KeyConstraints{Rules: []database.ConstraintRule{teamRule}}, "team@example.com", "primary", true, ""},
+		{"neither needs approval", &database.KeyConstraints{Rules: []database.ConstraintRule{teamRule}}, "primary", "home@example.com", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Admin keys move without approval unless a constraint says otherwise
+			authKey := &apikeys.AuthenticatedKey{ID: "key1", Tier: database.TierAdmin, Constraints: tt.constraints}
+			intent := &google.EventMoveIntent{CalendarID: tt.source, EventID: "evt1", DestinationCalendarID: tt.target}
+			decision, err := h.evaluateConstraintsForMove(authKey, intent)
+			if tt.wantConstraint != "" {
+				var violation *apikeys.ConstraintViolation
+				if !errors.As(err, &violation) || violation.Constraint != tt.wantConstraint {
+					t.Fatalf("expected a %s denial, got %v", tt.wantConstraint, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decision.RequiresApproval() != tt.wantApproval || (tt.wantApproval && decision.Constraint != "rule") {
+				t.Errorf("approval required = %v, want %v (%+v)", decision.RequiresApproval(), tt.wantApproval, decision)
+			}
+		})
+	}
+}
+
+func TestMoveEventSubmitsWithoutCallingGoogle(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key1', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+
+	fake := &fakeCalendarClient{}
+	cfg := &config.Config{}
+	cfg.Approval.TimeoutMinutes = 60
+	h := &Handler{
+		calendarClient: fake,
+		config:         cfg,
+		db:             db,
+		engine:         engine.NewEngine(cfg, requests.NewRepository(db), nil, engine.NewAuditLogger(db), nil),
+	}
+
+	body := `{"destinationCalendarId":"team@example.com"}`
+	req := httptest.NewRequest("POST", "http://example.com/api/calendar/primary/events/evt1/move", strings.NewReader(body))
+	req.SetPathValue("calendarId", "primary")
+	req.SetPathValue("eventId", "evt1")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{ID: "key1", Tier: database.TierWrite}))
+	rr := httptest.NewRecorder()
+	h.MoveEvent(rr, req)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var operation, status string
+	if err := db.QueryRow(`SELECT operation, status FROM requests`).Scan(&operation, &status); err != nil {
+		t.Fatalf("load request: %v", err)
+	}
+	if operation != database.OperationMoveEvent || status != database.StatusPendingApproval {
+		t.Errorf("request = %s/%s, want a pending move_event", operation, status)
+	}
+	// The move only reaches Google once the request is approved and executed
+	if fake.moveCalls != 0 {
+		t.Errorf("MoveEvent called %d times before approval", fake.moveCalls)
+	}
+}
+
+func TestMergedFreeBusyExpandsAllowlist(t *testing.T) {
+	base := time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC)
+	fake := &fakeCalendarClient{
+		freeBusy: &google.FreeBusyResponse{
+			Calendars: map[string]google.FreeBusyCalendarInfo{
+				"primary": {Busy: []google.TimePeriod{
+					{Start: base, End: base.Add(time.Hour)},
+					{Start: base.Add(4 * time.Hour), End: base.Add(5 * time.Hour)},
+				}},
+				"work": {Busy: []google.TimePeriod{
+					{Start: base.Add(30 * time.Minute), End: base.Add(2 * time.Hour)},
+					{Start: base.Add(2 * time.Hour), End: base.Add(3 * time.Hour)},
+				}},
+			},
+		},
+	}
+	h := &Handler{calendarClient: fake}
+
+	req := httptest.NewRequest("GET", "http://example.com/api/freebusy?timeMin=2026-02-02T00:00:00Z&timeMax=2026-02-03T00:00:00Z", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:          "key1",
+		Tier:        database.TierRead,
+		Constraints: &database.KeyConstraints{CalendarAllowlist: []string{"primary", "work"}},
+	}))
+	rec := httptest.NewRecorder()
+
+	h.MergedFreeBusy(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if fake.lastFreeBusy == nil || len(fake.lastFreeBusy.Items) != 2 {
+		t.Fatalf("expected both allowlisted calendars to be queried, got %+v", fake.lastFreeBusy)
+	}
+
+	var body struct {
+		Busy []google.TimePeriod `json:"busy"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	// 09:00-10:00, 09:30-11:00 and 11:00-12:00 merge into one block
+	want := []google.TimePeriod{
+		{Start: base, End: base.Add(3 * time.Hour)},
+		{Start: base.Add(4 * time.Hour), End: base.Add(5 * time.Hour)},
+	}
+	if len(body.Busy) != len(want) {
+		t.Fatalf("expected %d merged periods, got %+v", len(want), body.Busy)
+	}
+	for i := range want {
+		if !body.Busy[i].Start.Equal(want[i].Start) || !body.Busy[i].End.Equal(want[i].End) {
+			t.Errorf("period %d = %+v, want %+v", i, body.Busy[i], want[i])
+		}
+	}
+}
+
+func TestMergedFreeBusyUnconstrainedUsesPrimary(t *testing.T) {
+	fake := &fakeCalendarClient{freeBusy: &google.FreeBusyResponse{}}
+	h := &Handler{calendarClient: fake}
+
+	req := httptest.NewRequest("GET", "http://example.com/api/freebusy", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: database.TierRead,
+	}))
+	rec := httptest.NewRecorder()
+
+	h.MergedFreeBusy(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(fake.lastFreeBusy.Items) != 1 || fake.lastFreeBusy.Items[0].ID != "primary" {
+		t.Errorf("expected primary calendar, got %+v", fake.lastFreeBusy.Items)
+	}
+	if !strings.Contains(rec.Body.String(), `"busy":[]`) {
+		t.Errorf("expected empty busy array, got %s", rec.Body.String())
+	}
+}
+
+func TestWriteEndpointsRequireScope(t *testing.T) {
+	h := &Handler{calendarClient: &fakeCalendarClient{}}
+
+	req := httptest.NewRequest("POST", "http://example.com/api/calendar/events/delete",
+		strings.NewReader(`{"calendarId":"primary","eventId":"evt1"}`))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:     "key1",
+		Tier:   "write",
+		Scopes: []string{apikeys.ScopeEventsCreate},
+	}))
+
+	rr := httptest.NewRecorder()
+	h.DeleteEvent(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), apikeys.ScopeEventsDelete) {
+		t.Fatalf("expected missing scope in error, got %s", rr.Body.String())
+	}
+}
+
+func TestImportEventFromURL(t *testing.T) {
+	start := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	fake := &fakeCalendarClient{
+		event: &google.Event{
+			ID:         "evt123",
+			Summary:    "Planning",
+			Start:      &google.EventTime{DateTime: start},
+			End:        &google.EventTime{DateTime: start.Add(time.Hour)},
+			Attendees:  []google.Attendee{{Email: "me@example.com", Self: true}, {Email: "a@example.com"}},
+			Visibility: "private",
+		},
+	}
+	h := &Handler{calendarClient: fake}
+
+	eid := base64.RawURLEncoding.EncodeToString([]byte("evt123 team@g"))
+	body := `{"url":"https://calendar.google.com/calendar/u/0/r/eventedit/` + eid + `"}`
+
+	req := httptest.NewRequest("POST", "http://example.com/api/events/import", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: database.TierWrite,
+		Constraints: &database.KeyConstraints{
+			CalendarAllowlist: []string{"team@group.calendar.google.com"},
+		},
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ImportEvent(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if fake.lastGetCalendarID != "team@group.calendar.google.com" || fake.lastGetEventID != "evt123" {
+		t.Fatalf("fetched %q/%q", fake.lastGetCalendarID, fake.lastGetEventID)
+	}
+
+	var resp struct {
+		Intent google.EventUpdateIntent `json:"intent"`
+		AllDay bool                     `json:"all_day"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Intent.EventID != "evt123" || resp.Intent.CalendarID != "team@group.calendar.google.com" {
+		t.Errorf("intent target = %q/%q", resp.Intent.CalendarID, resp.Intent.EventID)
+	}
+	if resp.Intent.Summary == nil || *resp.Intent.Summary != "Planning" {
+		t.Errorf("summary not prefilled: %v", resp.Intent.Summary)
+	}
+	if resp.Intent.Start == nil || !resp.Intent.Start.Equal(start) {
+		t.Errorf("start not prefilled: %v", resp.Intent.Start)
+	}
+	if len(resp.Intent.Attendees) != 1 || resp.Intent.Attendees[0] != "a@example.com" {
+		t.Errorf("attendees = %v, want only a@example.com", resp.Intent.Attendees)
+	}
+	if resp.AllDay {
+		t.Error("timed event reported as all-day")
+	}
+}
+
+func TestImportEventRedactsFields(t *testing.T) {
+	start := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	fake := &fakeCalendarClient{
+		event: &google.Event{
+			ID:          "evt123",
+			Summary:     "Interview",
+			Description: "Candidate notes",
+			Location:    "Room 4",
+			Start:       &google.EventTime{DateTime: start},
+			End:         &google.EventTime{DateTime: start.Add(time.Hour)},
+			Attendees:   []google.Attendee{{Email: "alice@example.com"}},
+		},
+	}
+	h := &Handler{calendarClient: fake}
+
+	body := `{"calendarId":"primary","eventId":"evt123"}`
+	req := httptest.NewRequest("POST", "http://example.com/api/events/import", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: database.TierWrite,
+		Constraints: &database.KeyConstraints{
+			RedactFields: []string{"description", "location", "attendees"},
+		},
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ImportEvent(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	for _, hidden := range []string{"Candidate notes", "Room 4", "alice@example.com", `"description"`, `"location"`, `"attendees"`} {
+		if strings.Contains(rr.Body.String(), hidden) {
+			t.Errorf("response contains redacted %s: %s", hidden, rr.Body.String())
+		}
+	}
+
+	var resp struct {
+		Intent google.EventUpdateIntent `json:"intent"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Intent.Summary == nil || *resp.Intent.Summary != "Interview" || resp.Intent.Start == nil {
+		t.Errorf("unredacted fields missing: %+v", resp.Intent)
+	}
+}
+
+func TestImportEventRespectsAllowlist(t *testing.T) {
+	fake := &fakeCalendarClient{event: &google.Event{ID: "evt123"}}
+	h := &Handler{calendarClient: fake}
+
+	body := `{"calendarId":"private@group.calendar.google.com","eventId":"evt123"}`
+	req := httptest.NewRequest("POST", "http://example.com/api/events/import", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: database.TierWrite,
+		Constraints: &database.KeyConstraints{
+			CalendarAllowlist: []string{"primary"},
+		},
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ImportEvent(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", rr.Code)
+	}
+	if fake.lastGetEventID != "" {
+		t.Fatal("event should not be fetched for a disallowed calendar")
+	}
+}
+
+func TestImportEventRejectsForeignURL(t *testing.T) {
+	h := &Handler{calendarClient: &fakeCalendarClient{}}
+
+	body := `{"url":"https://example.com/calendar/event?eid=abc"}`
+	req := httptest.NewRequest("POST", "http://example.com/api/events/import", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: database.TierRead,
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ImportEvent(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+}
+
+func TestDuplicateEvent(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key1', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Minute)
+	fake := &fakeCalendarClient{
+		event: &google.Event{
+			ID:          "evt123",
+			Summary:     "Weekly sync",
+			Description: "Agenda in the doc",
+			Location:    "Room 4",
+			Start:       &google.EventTime{DateTime: start},
+			End:         &google.EventTime{DateTime: start.Add(time.Hour)},
+			Attendees:   []google.Attendee{{Email: "alice@example.com"}},
+			HtmlLink:    "https://www.google.com/calendar/event?eid=abc",
+		},
+	}
+	cfg := &config.Config{}
+	cfg.Approval.TimeoutMinutes = 60
+	h := &Handler{
+		calendarClient: fake,
+		config:         cfg,
+		db:             db,
+		engine:         engine.NewEngine(cfg, requests.NewRepository(db), nil, engine.NewAuditLogger(db), nil),
+	}
+	authKey := &apikeys.AuthenticatedKey{
+		ID:          "key1",
+		Tier:        database.TierWrite,
+		Constraints: &database.KeyConstraints{MinLeadTimeMinutes: 60},
+	}
+	duplicate := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "http://example.com/api/calendar/team@example.com/events/evt123/duplicate", strings.NewReader(body))
+		req.SetPathValue("calendarId", "team@example.com")
+		req.SetPathValue("eventId", "evt123")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, authKey))
+		rr := httptest.NewRecorder()
+		h.DuplicateEvent(rr, req)
+		return rr
+	}
+
+	// The copy keeps the source fields and moves by the requested offset
+	rr := duplicate(`{"shiftMinutes":1440}`)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if fake.lastGetCalendarID != "team@example.com" || fake.lastGetEventID != "evt123" {
+		t.Errorf("loaded %s/%s, want team@example.com/evt123", fake.lastGetCalendarID, fake.lastGetEventID)
+	}
+	var resp struct {
+		RequestID string `json:"request_id"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	var raw []byte
+	if err := db.QueryRow(`SELECT payload FROM requests WHERE id = ?`, resp.RequestID).Scan(&raw); err != nil {
+		t.Fatalf("load request: %v", err)
+	}
+	var payload duplicatePayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.CalendarID != "team@example.com" || payload.Summary != "Weekly sync" || payload.Description != "Agenda in the doc" ||
+		payload.Location != "Room 4" || len(payload.Attendees) != 1 || payload.Attendees[0] != "alice@example.com" {
+		t.Errorf("copied fields = %+v", payload.EventIntent)
+	}
+	if !payload.Start.Equal(start.Add(24*time.Hour)) || !payload.End.Equal(start.Add(25*time.Hour)) {
+		t.Errorf("copy runs %v to %v, want the source shifted by a day", payload.Start, payload.End)
+	}
+	if payload.DuplicateOf.CalendarID != "team@example.com" || payload.DuplicateOf.EventID != "evt123" || payload.DuplicateOf.HtmlLink == "" {
+		t.Errorf("duplicateOf = %+v", payload.DuplicateOf)
+	}
+
+	// Constraints are evaluated on the shifted copy, which starts in half an hour
+	rr = duplicate(`{"shiftMinutes":-2850}`)
+	if rr.Code != http.StatusForbidden || !strings.Contains(rr.Body.String(), "min_lead_time") {
+		t.Errorf("expected a min_lead_time denial, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// A missing source event is a 404
+	fake.event = nil
+	rr = duplicate("")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for a missing event, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestCreateEventsBatchDeniesDisallowedCalendar(t *testing.T) {
+	h := &Handler{calendarClient: &fakeCalendarClient{}}
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour).Format(time.RFC3339)
+	end := time.Now().Add(49 * time.Hour).Truncate(time.Hour).Format(time.RFC3339)
+	body := `{"event":{"summary":"Launch","start":"` + start + `","end":"` + end + `"},` +
+		`"calendarIds":["primary","secret@group.calendar.google.com"]}`
+
+	req := httptest.NewRequest("POST", "http://example.com/api/calendar/events/create-batch", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: database.TierWrite,
+		Constraints: &database.KeyConstraints{
+			CalendarAllowlist: []string{"primary"},
+		},
+	}))
+
+	rr := httptest.NewRecorder()
+	h.CreateEventsBatch(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "secret@group.calendar.google.com") {
+		t.Errorf("expected the disallowed calendar in the error, got %s", rr.Body.String())
+	}
+}
+
+func TestCreateEventsBatchRejectsMixedForms(t *testing.T) {
+	h := &Handler{calendarClient: &fakeCalendarClient{}}
+
+	body := `{"events":[{"calendarId":"primary","summary":"A"}],"event":{"summary":"B"},"calendarIds":["primary"]}`
+	req := httptest.NewRequest("POST", "http://example.com/api/calendar/events/create-batch", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: database.TierWrite,
+	}))
+
+	rr := httptest.NewRecorder()
+	h.CreateEventsBatch(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+}
+
+func TestEvaluateConstraintsForBatch(t *testing.T) {
+	h := &Handler{}
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+
+	batch := &google.EventBatchIntent{
+		Event:       &google.EventIntent{Summary: "Launch", Start: start, End: start.Add(time.Hour)},
+		CalendarIDs: []string{"primary", "team@group.calendar.google.com"},
+	}
+	if err := batch.Expand(); err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+	if len(batch.Events) != 2 || batch.Events[1].CalendarID != "team@group.calendar.google.com" {
+		t.Fatalf("unexpected expansion: %+v", batch.Events)
+	}
+
+	// Admin keys auto-approve each event, so the batch needs no approval
+	admin := &apikeys.AuthenticatedKey{ID: "key1", Tier: database.TierAdmin}
+	decision, err := h.evaluateConstraintsForBatch(admin, batch)
+	if err != nil || decision.RequiresApproval() {
+		t.Fatalf("admin: decision=%+v err=%v, want auto-approve", decision, err)
+	}
+
+	// An operation override can still force approval for batches
+	admin.Constraints = &database.KeyConstraints{
+		Operations: map[string]string{database.OperationCreateEventsBatch: "require_approval"},
+	}
+	decision, err = h.evaluateConstraintsForBatch(admin, batch)
+	if err != nil || !decision.RequiresApproval() || decision.Constraint != "operation" {
+		t.Fatalf("override: decision=%+v err=%v, want approval from the operation override", decision, err)
+	}
+
+	// A key without the create scope is denied for the whole batch
+	reader := &apikeys.AuthenticatedKey{ID: "key2", Tier: database.TierRead}
+	if _, err := h.evaluateConstraintsForBatch(reader, batch); err == nil {
+		t.Fatal("expected read key to be denied")
+	}
+}
+
+func TestImportEventUsesKeyDefaultCalendar(t *testing.T) {
+	start := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	fake := &fakeCalendarClient{
+		event: &google.Event{
+			ID:      "evt123",
+			Summary: "Planning",
+			Start:   &google.EventTime{DateTime: start},
+			End:     &google.EventTime{DateTime: start.Add(time.Hour)},
+		},
+	}
+	h := &Handler{calendarClient: fake}
+
+	req := httptest.NewRequest("POST", "http://example.com/api/events/import", strings.NewReader(`{"eventId":"evt123"}`))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: database.TierWrite,
+		Constraints: &database.KeyConstraints{
+			CalendarAllowlist: []string{"team@group.calendar.google.com"},
+			DefaultCalendar:   "team@group.calendar.google.com",
+		},
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ImportEvent(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if fake.lastGetCalendarID != "team@group.calendar.google.com" {
+		t.Fatalf("fetched from %q, want the key's default calendar", fake.lastGetCalendarID)
+	}
+}
+
+func TestCreateEventRejectsReservedExtendedProperty(t *testing.T) {
+	h := &Handler{calendarClient: &fakeCalendarClient{}}
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour).Format(time.RFC3339)
+	end := time.Now().Add(49 * time.Hour).Truncate(time.Hour).Format(time.RFC3339)
+	body := `{"calendarId":"primary","summary":"Sync","start":"` + start + `","end":"` + end + `",` +
+		`"extendedProperties":{"schedlock_request_id":"req_forged"}}`
+
+	req := httptest.NewRequest("POST", "http://example.com/api/calendar/events/create", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: database.TierWrite,
+	}))
+
+	rr := httptest.NewRecorder()
+	h.CreateEvent(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "reserved") {
+		t.Errorf("expected a reserved prefix error, got %s", rr.Body.String())
+	}
+}
+
+func TestParseSubmissionContext(t *testing.T) {
+	h := &Handler{}
+	parse := func(body string) (string, error) {
+		req := httptest.NewRequest("POST", "http://example.com/api/calendar/events/create", strings.NewReader(body))
+		var intent google.EventIntent
+		sub, err := h.parseSubmission(httptest.NewRecorder(), req, &intent)
+		return sub.Context, err
+	}
+
+	got, err := parse(`{"summary":"Sync","start":"2030-01-01T10:00:00Z","end":"2030-01-01T11:00:00Z","context":"  Asked by <b>Ana</b> in chat.\r\n<script>x</script>Needs a room. "}`)
+	if err != nil {
+		t.Fatalf("parseSubmission: %v", err)
+	}
+	if want := "Asked by Ana in chat.\nxNeeds a room."; got != want {
+		t.Errorf("context = %q, want %q", got, want)
+	}
+
+	if _, err := parse(`{"summary":"Sync","start":"2030-01-01T10:00:00Z","end":"2030-01-01T11:00:00Z","context":"` + strings.Repeat("x", util.MaxRequestContextLength+1) + `"}`); !errors.Is(err, util.ErrRequestContextTooLong) {
+		t.Errorf("expected ErrRequestContextTooLong, got %v", err)
+	}
+}
+
+func TestParseSubmissionTags(t *testing.T) {
+	h := &Handler{}
+	parse := func(tags string) (submission, error) {
+		req := httptest.NewRequest("POST", "http://example.com/api/calendar/events/create", strings.NewReader(
+			`{"summary":"Sync","start":"2030-01-01T10:00:00Z","end":"2030-01-01T11:00:00Z","tags":`+tags+`}`))
+		var intent google.EventIntent
+		return h.parseSubmission(httptest.NewRecorder(), req, &intent)
+	}
+
+	sub, err := parse(`[" Team:Eng ","project:launch","team:eng",""]`)
+	if err != nil {
+		t.Fatalf("parseSubmission: %v", err)
+	}
+	if strings.Join(sub.Tags, ",") != "team:eng,project:launch" {
+		t.Errorf("tags = %v, want [team:eng project:launch]", sub.Tags)
+	}
+
+	for _, tags := range []string{`["has space"]`, `["a:b:c"]`, `["<b>x</b>"]`, `["` + strings.Repeat("x", util.MaxRequestTagLen+1) + `"]`} {
+		if _, err := parse(tags); !errors.Is(err, util.ErrInvalidTag) {
+			t.Errorf("tags %s: expected ErrInvalidTag, got %v", tags, err)
+		}
+	}
+
+	many := make([]string, util.MaxRequestTags+1)
+	for i := range many {
+		many[i] = fmt.Sprintf("%q", fmt.Sprintf("t%d", i))
+	}
+	_, err = parse("[" + strings.Join(many, ",") + "]")
+	if !errors.Is(err, util.ErrTooManyTags) {
+		t.Fatalf("expected ErrTooManyTags, got %v", err)
+	}
+	rr := httptest.NewRecorder()
+	writeBodyError(rr, err)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rr.Code)
+	}
+}
+
+func TestParseSubmissionSchemaErrors(t *testing.T) {
+	h := &Handler{}
+	req := httptest.NewRequest("POST", "http://example.com/api/calendar/events/create", strings.NewReader(
+		`{"summary":"Sync","start":"tomorrow","end":"2030-01-01T11:00:00Z","attendees":["a@example.com","b@example.com","not-an-email"],"reminders":{"overrides":[{"method":"sms","minutes":10}]}}`))
+	var intent google.EventIntent
+	_, err := h.parseSubmission(httptest.NewRecorder(), req, &intent)
+
+	rr := httptest.NewRecorder()
+	writeBodyError(rr, err)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rr.Code)
+	}
+
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				Errors []google.FieldError `json:"errors"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Error.Code != response.ErrCodeValidationError {
+		t.Errorf("code = %q", body.Error.Code)
+	}
+
+	fields := map[string]bool{}
+	for _, fe := range body.Error.Details.Errors {
+		fields[fe.Field] = true
+	}
+	for _, want := range []string{"attendees[2]", "reminders.overrides[0].method", "start"} {
+		if !fields[want] {
+			t.Errorf("missing error for %s in %+v", want, body.Error.Details.Errors)
+		}
+	}
+	if len(body.Error.Details.Errors) != 3 {
+		t.Errorf("errors = %+v, want exactly 3", body.Error.Details.Errors)
+	}
+}
+
+func TestFreeBusyCalendarsExpandsWildcards(t *testing.T) {
+	h := &Handler{calendarClient: &fakeCalendarClient{calendars: []google.Calendar{
+		{ID: "ana@example.com"},
+		{ID: "room@Example.COM"},
+		{ID: "bob@other.com"},
+	}}}
+	authKey := &apikeys.AuthenticatedKey{Constraints: &database.KeyConstraints{
+		CalendarAllowlist: []string{"*@example.com", "primary"},
+	}}
+
+	got, err := h.freeBusyCalendars(context.Background(), authKey)
+	if err != nil {
+		t.Fatalf("freeBusyCalendars: %v", err)
+	}
+	want := []string{"ana@example.com", "room@Example.COM", "primary"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("calendars = %v, want %v", got, want)
+	}
+}
+
+func getColors(t *testing.T, h *Handler) *google.ColorPalette {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/api/calendar/colors", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{ID: "key1", Tier: "read"}))
+	rr := httptest.NewRecorder()
+	h.GetColors(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var palette google.ColorPalette
+	if err := json.NewDecoder(rr.Body).Decode(&palette); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return &palette
+}
+
+func TestGetColorsCachesPalette(t *testing.T) {
+	fake := &fakeCalendarClient{colors: &google.ColorPalette{
+		Event:    map[string]google.Color{"1": {Background: "#a4bdfc", Foreground: "#1d1d1d"}},
+		Calendar: map[string]google.Color{"1": {Background: "#ac725e", Foreground: "#1d1d1d"}},
+		Source:   google.ColorSourceGoogle,
+	}}
+	h := &Handler{calendarClient: fake}
+
+	for i := 0; i < 2; i++ {
+		palette := getColors(t, h)
+		if palette.Source != google.ColorSourceGoogle || palette.Event["1"].Background != "#a4bdfc" {
+			t.Fatalf("unexpected palette: %+v", palette)
+		}
+	}
+	if fake.colorCalls != 1 {
+		t.Errorf("Google was called %d times, want 1", fake.colorCalls)
+	}
+
+	// An expired cache entry is refreshed
+	h.colorsFetched = time.Now().Add(-colorPaletteTTL)
+	getColors(t, h)
+	if fake.colorCalls != 2 {
+		t.Errorf("Google was called %d times after expiry, want 2", fake.colorCalls)
+	}
+}
+
+func TestGetColorsFallsBackWithoutGoogle(t *testing.T) {
+	fake := &fakeCalendarClient{colorsErr: errors.New("no OAuth token")}
+	h := &Handler{calendarClient: fake}
+
+	palette := getColors(t, h)
+	if palette.Source != google.ColorSourceFallback || len(palette.Event) != 11 {
+		t.Fatalf("expected the 11 fallback event colors, got %+v", palette)
+	}
+
+	// The fallback is not cached, so a later connection is picked up
+	fake.colors, fake.colorsErr = &google.ColorPalette{Source: google.ColorSourceGoogle}, nil
+	if palette := getColors(t, h); palette.Source != google.ColorSourceGoogle {
+		t.Errorf("source after connecting = %q, want google", palette.Source)
+	}
+}
+
+func TestListAgendaMergesCalendars(t *testing.T) {
+	at := func(hour int) *google.EventTime {
+		return &google.EventTime{DateTime: time.Date(2026, 3, 2, hour, 0, 0, 0, time.UTC)}
+	}
+	fake := &fakeCalendarClient{
+		eventsByCalendar: map[string]*google.EventListResponse{
+			"work@example.com": {Events: []google.Event{{ID: "w1", Start: at(9)}, {ID: "w2", Start: at(13)}}},
+			"home@example.com": {Events: []google.Event{{ID: "h1", Start: at(11)}, {ID: "h2", Start: at(15)}}},
+		},
+		errByCalendar: map[string]error{"broken@example.com": errors.New("boom")},
+	}
+	h := &Handler{calendarClient: fake}
+
+	req := httptest.NewRequest("GET",
+		"/api/events?calendars=work@example.com,home@example.com,broken@example.com,secret@other.com&timeMin=2026-03-02T00:00:00Z&timeMax=2026-03-03T00:00:00Z&maxResults=3",
+		nil,
+	)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: "read",
+		Constraints: &database.KeyConstraints{
+			CalendarAllowlist: []string{"*@example.com"},
+		},
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ListAgenda(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Events []struct {
+			ID         string `json:"id"`
+			CalendarID string `json:"calendarId"`
+		} `json:"events"`
+		Errors    map[string]string `json:"errors"`
+		Truncated bool              `json:"truncated"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	var got []string
+	for _, event := range resp.Events {
+		got = append(got, event.CalendarID+"/"+event.ID)
+	}
+	want := "work@example.com/w1,home@example.com/h1,work@example.com/w2"
+	if strings.Join(got, ",") != want {
+		t.Errorf("events = %v, want %s", got, want)
+	}
+	if !resp.Truncated {
+		t.Error("expected truncated when maxResults cut the merged list")
+	}
+	if resp.Errors["secret@other.com"] != "calendar not in allowlist" {
+		t.Errorf("disallowed calendar error = %q", resp.Errors["secret@other.com"])
+	}
+	if resp.Errors["broken@example.com"] == "" {
+		t.Error("expected an error for the failing calendar")
+	}
+}
+
+func TestListAgendaNoAllowedCalendars(t *testing.T) {
+	h := &Handler{calendarClient: &fakeCalendarClient{}}
+	req := httptest.NewRequest("GET", "/api/events?calendars=secret@other.com", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:          "key1",
+		Tier:        "read",
+		Constraints: &database.KeyConstraints{CalendarAllowlist: []string{"primary"}},
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ListAgenda(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", rr.Code)
+	}
+}
+
+func TestReadsRedactFields(t *testing.T) {
+	event := &google.Event{
+		ID:          "evt1",
+		Summary:     "Interview",
+		Description: "Candidate notes",
+		Location:    "Room 4",
+		Start:       &google.EventTime{DateTime: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)},
+		Attendees: []google.Attendee{
+			{Email: "alice@example.com", DisplayName: "Alice", ResponseStatus: "accepted"},
+		},
+		Organizer: &google.Person{Email: "boss@example.com"},
+	}
+	fake := &fakeCalendarClient{
+		event: event,
+		resp:  &google.EventListResponse{Events: []google.Event{*event}},
+	}
+	h := &Handler{calendarClient: fake}
+	key := &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: "read",
+		Constraints: &database.KeyConstraints{
+			RedactFields: []string{"description", "attendees", "organizer"},
+		},
+	}
+
+	check := func(t *testing.T, got google.Event) {
+		t.Helper()
+		if got.Description != "" || got.Organizer != nil {
+			t.Errorf("description/organizer not redacted: %+v", got)
+		}
+		if len(got.Attendees) != 1 || got.Attendees[0].Email != "" || got.Attendees[0].DisplayName != "" {
+			t.Errorf("attendees = %+v, want one anonymous attendee", got.Attendees)
+		}
+		if got.Attendees[0].ResponseStatus != "accepted" {
+			t.Errorf("responseStatus = %q, want it kept", got.Attendees[0].ResponseStatus)
+		}
+		if got.Summary != "Interview" || got.Location != "Room 4" || got.Start == nil {
+			t.Errorf("unredacted fields changed: %+v", got)
+		}
+	}
+
+	t.Run("get", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/calendar/primary/events/evt1", nil)
+		req.SetPathValue("calendarId", "primary")
+		req.SetPathValue("eventId", "evt1")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, key))
+		rr := httptest.NewRecorder()
+		h.GetEvent(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rr.Code)
+		}
+		var got google.Event
+		if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		check(t, got)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/calendar/primary/events", nil)
+		req.SetPathValue("calendarId", "primary")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, key))
+		rr := httptest.NewRecorder()
+		h.ListEvents(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rr.Code)
+		}
+		var resp struct {
+			Events []google.Event `json:"events"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(resp.Events) != 1 {
+			t.Fatalf("got %d events, want 1", len(resp.Events))
+		}
+		check(t, resp.Events[0])
+	})
+
+	if event.Attendees[0].Email != "alice@example.com" || event.Organizer == nil {
+		t.Error("redaction modified the client's event")
+	}
+}
+
+func TestListEventsGoogleBusy(t *testing.T) {
+	h := &Handler{calendarClient: &fakeCalendarClient{err: fmt.Errorf("list: %w", google.ErrTooManyCalls)}}
+
+	req := httptest.NewRequest("GET", "/api/calendar/primary/events", nil)
+	req.SetPathValue("calendarId", "primary")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key1",
+		Tier: "read",
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ListEvents(rr, req)
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", rr.Code)
+	}
+	if rr.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+}
+
+func TestListEventsAttendeeFilter(t *testing.T) {
+	with := func(id string, emails ...string) google.Event {
+		event := google.Event{ID: id}
+		for _, email := range emails {
+			event.Attendees = append(event.Attendees, google.Attendee{Email: email})
+		}
+		return event
+	}
+	fake := &fakeCalendarClient{
+		eventsByPage: map[string]*google.EventListResponse{
+			"":   {Events: []google.Event{with("a1", "ana@example.com"), with("b1", "bo@example.com")}, NextPageToken: "p2"},
+			"p2": {Events: []google.Event{with("b2"), with("a2", "Ana@Example.com", "bo@example.com"), with("a3", "ana@example.com")}, NextPageToken: "p3"},
+			"p3": {Events: []google.Event{with("a4", "ana@example.com")}},
+		},
+	}
+	h := &Handler{calendarClient: fake}
+	key := &apikeys.AuthenticatedKey{ID: "key1", Tier: "read"}
+
+	list := func(t *testing.T, query string) ([]string, string) {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/api/calendar/primary/events?"+query, nil)
+		req.SetPathValue("calendarId", "primary")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, key))
+		rr := httptest.NewRecorder()
+		h.ListEvents(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var resp struct {
+			Events []struct {
+				ID string `json:"id"`
+			} `json:"events"`
+			NextPageToken string `json:"next_page_token"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		var ids []string
+		for _, event := range resp.Events {
+			ids = append(ids, event.ID)
+		}
+		return ids, resp.NextPageToken
+	}
+
+	// The second Google page has two matches but only one fits, so the
+	// token resumes inside that page
+	ids, token := list(t, "attendee=ana@example.com&maxResults=2")
+	if strings.Join(ids, ",") != "a1,a2" || token == "" {
+		t.Fatalf("first page = %v, token %q", ids, token)
+	}
+	if fake.lastOpts.MaxResults != attendeeFilterPageSize {
+		t.Errorf("page size = %d, want %d", fake.lastOpts.MaxResults, attendeeFilterPageSize)
+	}
+
+	ids, token = list(t, "attendee=ana@example.com&maxResults=2&pageToken="+token)
+	if strings.Join(ids, ",") != "a3,a4" || token != "" {
+		t.Fatalf("second page = %v, token %q", ids, token)
+	}
+
+	t.Run("invalid token", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/calendar/primary/events?attendee=ana@example.com&pageToken=%21%21", nil)
+		req.SetPathValue("calendarId", "primary")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, key))
+		rr := httptest.NewRecorder()
+		h.ListEvents(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("redacted attendees", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/calendar/primary/events?attendee=ana@example.com", nil)
+		req.SetPathValue("calendarId", "primary")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+			ID:          "key2",
+			Tier:        "read",
+			Constraints: &database.KeyConstraints{RedactFields: []string{"attendees"}},
+		}))
+		rr := httptest.NewRecorder()
+		h.ListEvents(rr, req)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d", rr.Code)
+		}
+	})
+}
+
+func TestListEventsAttendeeFilterPageCap(t *testing.T) {
+	pages := make(map[string]*google.EventListResponse)
+	for i := 0; i < 20; i++ {
+		pages[pageName(i)] = &google.EventListResponse{
+			Events:        []google.Event{{ID: fmt.Sprintf("e%d", i)}},
+			NextPageToken: pageName(i + 1),
+		}
+	}
+	fake := &fakeCalendarClient{eventsByPage: pages}
+	h := &Handler{calendarClient: fake}
+
+	req := httptest.NewRequest("GET", "/api/calendar/primary/events?attendee=ana@example.com", nil)
+	req.SetPathValue("calendarId", "primary")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{ID: "key1", Tier: "read"}))
+	rr := httptest.NewRecorder()
+	h.ListEvents(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if fake.listCalls != maxAttendeeFilterPages {
+		t.Errorf("Google calls = %d, want %d", fake.listCalls, maxAttendeeFilterPages)
+	}
+	var resp map[string]interface{}
+	json.NewDecoder(rr.Body).Decode(&resp)
+	if resp["next_page_token"] == nil {
+		t.Error("expected a next_page_token after hitting the page cap")
+	}
+}
+
+func pageName(i int) string {
+	if i == 0 {
+		return ""
+	}
+	return fmt.Sprintf("p%d", i)
+}
+
+func TestCreateConstraintsCountOptionalAttendees(t *testing.T) {
+	h := &Handler{}
+	authKey := &apikeys.AuthenticatedKey{
+		ID:          "key1",
+		Tier:        "write",
+		Constraints: &database.KeyConstraints{MaxAttendees: 2},
+	}
+	start := time.Now().Add(24 * time.Hour)
+	intent := &google.EventIntent{
+		CalendarID:        "primary",
+		Summary:           "Review",
+		Start:             start,
+		End:               start.Add(time.Hour),
+		Attendees:         []string{"ana@example.com"},
+		OptionalAttendees: []string{"ben@example.com", "cy@example.com"},
+	}
+
+	decision, err := h.evaluateConstraintsForCreate(authKey, intent)
+	if err == nil || decision.Constraint != "max_attendees" {
+		t.Fatalf("decision = %+v, err = %v; want max_attendees to count optional attendees", decision, err)
+	}
+
+	intent.OptionalAttendees = intent.OptionalAttendees[:1]
+	if _, err := h.evaluateConstraintsForCreate(authKey, intent); err != nil {
+		t.Fatalf("two attendees should be within the limit: %v", err)
+	}
+}
@@ -32,8 +32,11 @@ func (h *Handler) SuggestCallback(w http.ResponseWriter, r *http.Request) {
 		var body struct {
 			Suggestion string `json:"suggestion"`
 		}
-		if err := parseJSON(r, &body); err == nil {
+		if err := h.parseJSON(w, r, &body); err == nil {
 			suggestion = body.Suggestion
+		} else if isBodyTooLarge(err) {
+			writeBodyError(w, err)
+			return
 		}
 	}
 	if suggestion == "" {
@@ -154,7 +157,7 @@ func (h *Handler) HandleCallback(ctx context.Context, callback *notifications.Ca
 	}
 	switch callback.Action {
 	case "approve", "deny":
-		return h.engine.ProcessApproval(ctx, callback.RequestID, callback.Action, callback.RespondedBy)
+		return h.engine.ProcessApprovalWithReason(ctx, callback.RequestID, callback.Action, callback.RespondedBy, callback.Reason)
 	case "suggest":
 		return h.engine.ProcessSuggestion(ctx, callback.RequestID, callback.Suggestion, callback.RespondedBy)
 	default:
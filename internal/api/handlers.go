@@ -4,7 +4,11 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/dtorcivia/schedlock/internal/apikeys"
 	"github.com/dtorcivia/schedlock/internal/config"
@@ -16,11 +20,13 @@ import (
 	"github.com/dtorcivia/schedlock/internal/response"
 	"github.com/dtorcivia/schedlock/internal/server/middleware"
 	"github.com/dtorcivia/schedlock/internal/tokens"
+	"github.com/dtorcivia/schedlock/internal/util"
 )
 
 // Handler provides REST API handlers.
 type Handler struct {
 	config          *config.Config
+	db              *database.DB
 	engine          *engine.Engine
 	requestRepo     *requests.Repository
 	apiKeyRepo      *apikeys.Repository
@@ -28,6 +34,15 @@ type Handler struct {
 	calendarClient  CalendarClient
 	notificationMgr *notifications.Manager
 	auditLogger     *engine.AuditLogger
+	webhookPinger   WebhookPinger
+
+	webhookFailures    WebhookFailureStore
+	retentionPreviewer RetentionPreviewer
+	oauthStatus        OAuthStatusReporter
+
+	colorsMu      sync.Mutex
+	colors        *google.ColorPalette // cached Google palette
+	colorsFetched time.Time
 }
 
 // CalendarClient defines the subset of Google Calendar client behavior used by the API handler.
@@ -39,11 +54,20 @@ type CalendarClient interface {
 	CreateEvent(ctx context.Context, intent *google.EventIntent) (*google.Event, error)
 	UpdateEvent(ctx context.Context, intent *google.EventUpdateIntent) (*google.Event, error)
 	DeleteEvent(ctx context.Context, intent *google.EventDeleteIntent) error
+	MoveEvent(ctx context.Context, intent *google.EventMoveIntent) (*google.Event, error)
+	GetColors(ctx context.Context) (*google.ColorPalette, error)
+}
+
+// EventCacheReporter is implemented by calendar clients that cache event
+// reads; GetStats reports their counters.
+type EventCacheReporter interface {
+	EventCacheStats() (google.CacheStats, bool)
 }
 
 // NewHandler creates a new API handler.
 func NewHandler(
 	cfg *config.Config,
+	db *database.DB,
 	eng *engine.Engine,
 	requestRepo *requests.Repository,
 	apiKeyRepo *apikeys.Repository,
@@ -54,6 +78,7 @@ func NewHandler(
 ) *Handler {
 	return &Handler{
 		config:          cfg,
+		db:              db,
 		engine:          eng,
 		requestRepo:     requestRepo,
 		apiKeyRepo:      apiKeyRepo,
@@ -64,27 +89,48 @@ func NewHandler(
 	}
 }
 
-// RegisterRoutes registers API routes.
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
-	// Health check (no auth)
+// Router is the part of *http.ServeMux that RegisterRoutes uses.
+type Router interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// RegisterRoutes registers API routes. New routes must also be described in
+// apiOperations (openapi.go).
+func (h *Handler) RegisterRoutes(mux Router) {
+	// Health check and API description (no auth)
 	mux.HandleFunc("GET /api/health", h.Health)
+	mux.HandleFunc("GET /api/openapi.json", h.OpenAPI)
 
 	// Calendar read operations (read tier)
 	mux.HandleFunc("GET /api/calendar/list", h.ListCalendars)
+	mux.HandleFunc("GET /api/calendar/colors", h.GetColors)
 	mux.HandleFunc("GET /api/calendar/{calendarId}/events", h.ListEvents)
 	mux.HandleFunc("GET /api/calendar/{calendarId}/events/{eventId}", h.GetEvent)
 	mux.HandleFunc("GET /api/calendar/freebusy", h.FreeBusy)
 	mux.HandleFunc("POST /api/calendar/freebusy", h.FreeBusy)
+	mux.HandleFunc("GET /api/freebusy", h.MergedFreeBusy)
+	mux.HandleFunc("GET /api/events", h.ListAgenda)
 
 	// Calendar write operations (write tier)
 	mux.HandleFunc("POST /api/calendar/events/create", h.CreateEvent)
+	mux.HandleFunc("POST /api/calendar/events/create-batch", h.CreateEventsBatch)
 	mux.HandleFunc("POST /api/calendar/events/update", h.UpdateEvent)
 	mux.HandleFunc("POST /api/calendar/events/delete", h.DeleteEvent)
+	mux.HandleFunc("POST /api/calendar/{calendarId}/events/{eventId}/move", h.MoveEvent)
+	mux.HandleFunc("POST /api/calendar/{calendarId}/events/{eventId}/duplicate", h.DuplicateEvent)
+	mux.HandleFunc("POST /api/events/import", h.ImportEvent)
 
 	// Request management
 	mux.HandleFunc("GET /api/requests", h.ListRequests)
+	mux.HandleFunc("POST /api/requests/validate", h.ValidateRequest)
 	mux.HandleFunc("GET /api/requests/{requestId}", h.GetRequest)
+	mux.HandleFunc("GET /api/requests/by-idempotency/{key}", h.GetRequestByIdempotencyKey)
+	mux.HandleFunc("GET /api/requests/{requestId}/timeline", h.GetRequestTimeline)
+	mux.HandleFunc("GET /api/requests/{requestId}/results", h.GetRequestResults)
 	mux.HandleFunc("POST /api/requests/{requestId}/cancel", h.CancelRequest)
+	mux.HandleFunc("POST /api/requests/{requestId}/notify", h.ResendNotification)
+	mux.HandleFunc("POST /api/requests/{requestId}/retry", h.RetryRequest)
+	mux.HandleFunc("GET /api/requests/{requestId}/notification-preview", h.PreviewNotification)
 
 	// Callback endpoints (token-based auth)
 	mux.HandleFunc("POST /api/callback/approve/{token}", h.ApproveCallback)
@@ -96,6 +142,17 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	// Admin endpoints (admin tier)
 	mux.HandleFunc("GET /api/admin/stats", h.GetStats)
 	mux.HandleFunc("GET /api/admin/audit", h.GetAuditLog)
+	mux.HandleFunc("GET /api/admin/audit/verify", h.VerifyAuditLog)
+	mux.HandleFunc("GET /api/admin/keys", h.ListAPIKeys)
+	mux.HandleFunc("GET /api/admin/keys/usage", h.GetKeyUsage)
+	mux.HandleFunc("GET /api/admin/keys/{id}/audit", h.GetKeyAuditLog)
+	mux.HandleFunc("PATCH /api/admin/keys/{id}/constraints", h.UpdateKeyConstraints)
+	mux.HandleFunc("GET /api/admin/backup", h.Backup)
+	mux.HandleFunc("POST /api/admin/test-webhook", h.TestWebhook)
+	mux.HandleFunc("GET /api/admin/webhook-failures", h.ListWebhookFailures)
+	mux.HandleFunc("POST /api/admin/webhook-failures/{id}/retry", h.RetryWebhookFailure)
+	mux.HandleFunc("GET /api/admin/retention/preview", h.PreviewRetention)
+	mux.HandleFunc("GET /api/admin/oauth/status", h.GetOAuthStatus)
 }
 
 // Health returns server health status.
@@ -108,16 +165,13 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 
 // GetStats returns system statistics.
 func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
-	// Require admin tier
-	authKey := middleware.GetAuthenticatedKey(r)
-	if authKey == nil || authKey.Tier != "admin" {
-		response.Error(w, http.StatusForbidden, "admin access required", nil)
+	if requireScope(w, r, apikeys.ScopeAdmin) == nil {
 		return
 	}
 
 	ctx := r.Context()
 
-	stats, err := h.requestRepo.GetStats(ctx)
+	stats, err := h.requestRepo.GetStats(ctx, h.config.Display.StatsWindowDays)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "failed to get stats", err)
 		return
@@ -135,52 +189,155 @@ func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.JSON(w, http.StatusOK, map[string]interface{}{
+	resp := map[string]interface{}{
 		"requests":      stats,
 		"api_keys":      apiKeyStats,
 		"audit_entries": auditCount,
-	})
+	}
+	if cache, ok := h.calendarClient.(EventCacheReporter); ok {
+		if cacheStats, enabled := cache.EventCacheStats(); enabled {
+			resp["event_cache"] = cacheStats
+		}
+	}
+	response.JSON(w, http.StatusOK, resp)
 }
 
-// GetAuditLog returns recent audit entries.
+// GetAuditLog returns recent audit entries, optionally filtered by API key
+// and event type.
 func (h *Handler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
-	// Require admin tier
-	authKey := middleware.GetAuthenticatedKey(r)
-	if authKey == nil || authKey.Tier != "admin" {
-		response.Error(w, http.StatusForbidden, "admin access required", nil)
+	if requireScope(w, r, apikeys.ScopeAdmin) == nil {
 		return
 	}
 
-	ctx := r.Context()
+	query := r.URL.Query()
+	q := engine.AuditQuery{
+		APIKeyID:  query.Get("api_key_id"),
+		EventType: query.Get("event_type"),
+	}
+	if !parseAuditPage(w, r, &q, 100) {
+		return
+	}
+	h.writeAuditPage(w, r, q)
+}
+
+// VerifyAuditLog checks the audit hash chain and reports the first broken link.
+func (h *Handler) VerifyAuditLog(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeAdmin)
+	if authKey == nil {
+		return
+	}
 
-	entries, err := h.auditLogger.GetRecent(ctx, 100)
+	result, err := h.auditLogger.VerifyChain(r.Context())
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, "failed to get audit log", err)
+		response.Error(w, http.StatusInternalServerError, "failed to verify audit log", err)
 		return
 	}
 
-	response.JSON(w, http.StatusOK, map[string]interface{}{
-		"entries": entries,
-	})
+	response.JSON(w, http.StatusOK, result)
 }
 
 // parseJSON decodes JSON request body.
-func parseJSON(r *http.Request, v interface{}) error {
+func (h *Handler) parseJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBodyBytes())
 	defer r.Body.Close()
 	return json.NewDecoder(r.Body).Decode(v)
 }
 
-// requireTier checks if the authenticated key has at least the required tier.
-func requireTier(w http.ResponseWriter, r *http.Request, requiredTier string) *apikeys.AuthenticatedKey {
+// submission holds the fields of a write request that are stored beside the
+// payload rather than in it.
+type submission struct {
+	Context string   // explains the request to approvers
+	Tags    []string // labels for organizing the queue
+}
+
+// parseSubmission decodes a write request body into v and returns its
+// optional "context" and "tags" fields, sanitized and validated.
+func (h *Handler) parseSubmission(w http.ResponseWriter, r *http.Request, v interface{}) (submission, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBodyBytes())
+	defer r.Body.Close()
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return submission{}, err
+	}
+	return decodeSubmission(data, v)
+}
+
+// decodeSubmission decodes a write request payload into v and returns its
+// optional "context" and "tags" fields, sanitized and validated.
+func decodeSubmission(data []byte, v interface{}) (submission, error) {
+	// Check the raw payload first so callers get field-level errors
+	// instead of a generic decode failure
+	if s, ok := v.(interface{ Schema() *google.Schema }); ok {
+		if err := s.Schema().ValidateJSON(data); err != nil {
+			return submission{}, err
+		}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return submission{}, err
+	}
+
+	var body struct {
+		Context string   `json:"context"`
+		Tags    []string `json:"tags"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return submission{}, err
+	}
+	requestContext, err := util.SanitizeRequestContext(body.Context)
+	if err != nil {
+		return submission{}, err
+	}
+	tags, err := util.NormalizeTags(body.Tags)
+	if err != nil {
+		return submission{}, err
+	}
+	return submission{Context: requestContext, Tags: tags}, nil
+}
+
+// maxBodyBytes returns the configured request body limit.
+func (h *Handler) maxBodyBytes() int64 {
+	if h.config == nil || h.config.Server.MaxBodyBytes <= 0 {
+		return config.DefaultMaxBodyBytes
+	}
+	return h.config.Server.MaxBodyBytes
+}
+
+// isBodyTooLarge reports whether err came from exceeding the body limit.
+func isBodyTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
+
+// writeBodyError responds to a body parse failure, using 413 for oversized bodies.
+func writeBodyError(w http.ResponseWriter, err error) {
+	if isBodyTooLarge(err) {
+		response.Error(w, http.StatusRequestEntityTooLarge, "request body too large", nil)
+		return
+	}
+	if errors.Is(err, util.ErrRequestContextTooLong) || errors.Is(err, util.ErrInvalidTag) || errors.Is(err, util.ErrTooManyTags) {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	var schemaErr *google.SchemaError
+	if errors.As(err, &schemaErr) {
+		response.WriteValidationError(w, schemaErr.Error(), map[string]interface{}{
+			"errors": schemaErr.Errors,
+		})
+		return
+	}
+	response.Error(w, http.StatusBadRequest, "invalid request body", err)
+}
+
+// requireScope checks if the authenticated key holds the required scope.
+func requireScope(w http.ResponseWriter, r *http.Request, scope string) *apikeys.AuthenticatedKey {
 	authKey := middleware.GetAuthenticatedKey(r)
 	if authKey == nil {
 		response.Error(w, http.StatusUnauthorized, "authentication required", nil)
 		return nil
 	}
 
-	tierRank := map[string]int{"read": 1, "write": 2, "admin": 3}
-	if tierRank[authKey.Tier] < tierRank[requiredTier] {
-		response.Error(w, http.StatusForbidden, requiredTier+" tier required", nil)
+	if !authKey.HasScope(scope) {
+		response.Error(w, http.StatusForbidden, scope+" scope required", nil)
 		return nil
 	}
 
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/response"
+	"github.com/dtorcivia/schedlock/internal/settings"
+)
+
+// ListAPIKeys returns a filtered, paginated list of API keys (admin only).
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	if requireScope(w, r, apikeys.ScopeAdmin) == nil {
+		return
+	}
+
+	query := r.URL.Query()
+	opts := apikeys.ListOptions{
+		Tier:   query.Get("tier"),
+		Search: query.Get("q"),
+		Limit:  50,
+	}
+
+	if opts.Tier != "" && opts.Tier != database.TierRead && opts.Tier != database.TierWrite && opts.Tier != database.TierAdmin {
+		response.Error(w, http.StatusBadRequest, "invalid tier", nil)
+		return
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n <= 0 || n > 200 {
+			response.Error(w, http.StatusBadRequest, "limit must be between 1 and 200", nil)
+			return
+		}
+		opts.Limit = n
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		n, err := strconv.Atoi(offsetStr)
+		if err != nil || n < 0 {
+			response.Error(w, http.StatusBadRequest, "invalid offset", nil)
+			return
+		}
+		opts.Offset = n
+	}
+	if revokedStr := query.Get("includeRevoked"); revokedStr != "" {
+		includeRevoked, err := strconv.ParseBool(revokedStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid includeRevoked value", nil)
+			return
+		}
+		opts.IncludeRevoked = includeRevoked
+	}
+
+	keys, total, err := h.apiKeyRepo.List(r.Context(), opts)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to list API keys", err)
+		return
+	}
+
+	// Never expose key hashes
+	items := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		item := map[string]interface{}{
+			"id":          key.ID,
+			"name":        key.Name,
+			"key_prefix":  key.KeyPrefix,
+			"tier":        key.Tier,
+			"scopes":      key.Scopes,
+			"created_at":  key.CreatedAt,
+			"constraints": key.Constraints,
+		}
+		if key.LastUsedAt.Valid {
+			item["last_used_at"] = key.LastUsedAt.Time
+		}
+		if key.ExpiresAt.Valid {
+			item["expires_at"] = key.ExpiresAt.Time
+		}
+		if key.RevokedAt.Valid {
+			item["revoked_at"] = key.RevokedAt.Time
+		}
+		items = append(items, item)
+	}
+
+	response.Paginated(w, r, items, response.OffsetPage(len(items), total, opts.Limit, opts.Offset), map[string]interface{}{
+		"keys":   items,
+		"total":  total,
+		"limit":  opts.Limit,
+		"offset": opts.Offset,
+	})
+}
+
+// GetKeyUsage returns each unrevoked API key's request counts by status over
+// a window, with when it was last used (admin only). Keys with no requests
+// are listed too, so dormant keys can be found and revoked.
+func (h *Handler) GetKeyUsage(w http.ResponseWriter, r *http.Request) {
+	if requireScope(w, r, apikeys.ScopeAdmin) == nil {
+		return
+	}
+
+	days := config.DefaultStatsWindowDays
+	if h.config != nil && h.config.Display.StatsWindowDays > 0 {
+		days = h.config.Display.StatsWindowDays
+	}
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		n, err := strconv.Atoi(daysStr)
+		if err != nil || n <= 0 || n > 365 {
+			response.Error(w, http.StatusBadRequest, "days must be between 1 and 365", nil)
+			return
+		}
+		days = n
+	}
+
+	usage, err := h.apiKeyRepo.Usage(r.Context(), days)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to get key usage", err)
+		return
+	}
+
+	items := make([]map[string]interface{}, 0, len(usage))
+	for _, u := range usage {
+		item := map[string]interface{}{
+			"id":         u.ID,
+			"name":       u.Name,
+			"key_prefix": u.KeyPrefix,
+			"tier":       u.Tier,
+			"created_at": u.CreatedAt,
+			"requests":   u.Total,
+			"by_status":  u.StatusCounts,
+		}
+		if u.LastUsedAt.Valid {
+			item["last_used_at"] = u.LastUsedAt.Time
+		}
+		if u.LastRequestAt.Valid {
+			item["last_request_at"] = u.LastRequestAt.Time
+		}
+		items = append(items, item)
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"window_days": days,
+		"keys":        items,
+	})
+}
+
+// UpdateKeyConstraints changes an API key's constraints with a JSON merge
+// patch (admin only), so a key can be tightened or loosened without
+// rotating it. The changed fields are recorded in the audit log.
+func (h *Handler) UpdateKeyConstraints(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeAdmin)
+	if authKey == nil {
+		return
+	}
+
+	ctx := r.Context()
+	keyID := r.PathValue("id")
+	key, err := h.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to get API key", err)
+		return
+	}
+	if key == nil {
+		response.Error(w, http.StatusNotFound, "API key not found", nil)
+		return
+	}
+	if key.RevokedAt.Valid {
+		response.Error(w, http.StatusConflict, "API key is revoked", nil)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBodyBytes())
+	defer r.Body.Close()
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	updated, err := apikeys.MergeConstraints(key.Constraints, patch)
+	if err == nil {
+		err = apikeys.ValidateConstraints(updated)
+	}
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	changes := settings.Diff(apikeys.ConstraintsSnapshot(key.Constraints), apikeys.ConstraintsSnapshot(updated), nil)
+	if len(changes) > 0 {
+		if err := h.apiKeyRepo.UpdateConstraints(ctx, keyID, updated); err != nil {
+			response.Error(w, http.StatusInternalServerError, "failed to update constraints", err)
+			return
+		}
+		h.auditLogger.Log(ctx, database.AuditAPIKeyUpdated, "", keyID, "api", map[string]interface{}{
+			"updated_by": authKey.ID,
+			"changes":    changes,
+		})
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"id":          keyID,
+		"constraints": updated,
+		"changes":     changes,
+	})
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/google"
+	"github.com/dtorcivia/schedlock/internal/response"
+)
+
+// OAuthStatusReporter reports the stored Google OAuth token's status.
+// *google.OAuthManager satisfies it.
+type OAuthStatusReporter interface {
+	TokenStatus(ctx context.Context) (*google.OAuthTokenStatus, error)
+}
+
+// SetOAuthStatus sets the reporter used by GetOAuthStatus.
+func (h *Handler) SetOAuthStatus(r OAuthStatusReporter) {
+	h.oauthStatus = r
+}
+
+// GetOAuthStatus reports whether a Google OAuth token is stored, its scopes,
+// when it was stored and last saved, and whether the last refresh
+// succeeded. The token itself is never returned.
+func (h *Handler) GetOAuthStatus(w http.ResponseWriter, r *http.Request) {
+	if requireScope(w, r, apikeys.ScopeAdmin) == nil {
+		return
+	}
+	if h.oauthStatus == nil {
+		response.Error(w, http.StatusServiceUnavailable, "OAuth manager unavailable", nil)
+		return
+	}
+
+	status, err := h.oauthStatus.TokenStatus(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load OAuth token status", err)
+		return
+	}
+	response.JSON(w, http.StatusOK, status)
+}
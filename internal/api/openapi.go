@@ -0,0 +1,349 @@
+package api
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/google"
+	"github.com/dtorcivia/schedlock/internal/response"
+)
+
+// apiOperation describes one API route for the OpenAPI document. Keep this
+// list in step with RegisterRoutes; a test fails when a route is missing.
+type apiOperation struct {
+	method     string
+	path       string
+	summary    string
+	tag        string
+	scope      string // API key scope the handler requires
+	scopeNote  string // describes the scope when it depends on the body
+	public     bool   // no API key: health, spec and token callbacks
+	request    string // component schema of the JSON body
+	query      []apiParam
+	submission bool // creates a request: 202 when held for approval, 200 when auto-approved
+}
+
+// apiParam is a query parameter.
+type apiParam struct {
+	name        string
+	kind        string // JSON Schema type
+	description string
+}
+
+var apiOperations = []apiOperation{
+	{method: "GET", path: "/api/health", summary: "Server health", tag: "system", public: true},
+	{method: "GET", path: "/api/openapi.json", summary: "This OpenAPI document", tag: "system", public: true},
+
+	{method: "GET", path: "/api/calendar/list", summary: "List calendars", tag: "calendar", scope: apikeys.ScopeCalendarsList, query: []apiParam{
+		{"envelope", "boolean", "Return {data, pagination} instead of the original body"},
+	}},
+	{method: "GET", path: "/api/calendar/colors", summary: "Event color palette", tag: "calendar", scope: apikeys.ScopeCalendarsList},
+	{method: "GET", path: "/api/calendar/{calendarId}/events", summary: "List events on a calendar", tag: "calendar", scope: apikeys.ScopeEventsRead, query: []apiParam{
+		{"timeMin", "string", "RFC3339 lower bound"},
+		{"timeMax", "string", "RFC3339 upper bound"},
+		{"maxResults", "integer", "Page size"},
+		{"pageToken", "string", "Token from the previous page"},
+		{"q", "string", "Free-text search"},
+		{"attendee", "string", "Only events with this attendee email"},
+		{"singleEvents", "boolean", "Expand recurring events into instances"},
+		{"orderBy", "string", "startTime or updated"},
+		{"envelope", "boolean", "Return {data, pagination} instead of the original body"},
+	}},
+	{method: "GET", path: "/api/calendar/{calendarId}/events/{eventId}", summary: "Get an event", tag: "calendar", scope: apikeys.ScopeEventsRead},
+	{method: "GET", path: "/api/calendar/freebusy", summary: "Free/busy for calendars", tag: "calendar", scope: apikeys.ScopeFreeBusyRead, query: []apiParam{
+		{"timeMin", "string", "RFC3339 lower bound"},
+		{"timeMax", "string", "RFC3339 upper bound"},
+		{"calendars", "string", "Comma-separated calendar IDs"},
+	}},
+	{method: "POST", path: "/api/calendar/freebusy", summary: "Free/busy for calendars", tag: "calendar", scope: apikeys.ScopeFreeBusyRead, request: "FreeBusyRequest"},
+	{method: "GET", path: "/api/freebusy", summary: "Merged busy times across the key's calendars", tag: "calendar", scope: apikeys.ScopeFreeBusyRead, query: []apiParam{
+		{"timeMin", "string", "RFC3339 lower bound"},
+		{"timeMax", "string", "RFC3339 upper bound"},
+	}},
+	{method: "GET", path: "/api/events", summary: "Merged agenda across calendars", tag: "calendar", scope: apikeys.ScopeEventsRead, query: []apiParam{
+		{"timeMin", "string", "RFC3339 lower bound"},
+		{"timeMax", "string", "RFC3339 upper bound"},
+		{"calendars", "string", "Comma-separated calendar IDs"},
+		{"maxResults", "integer", "Maximum events returned"},
+	}},
+
+	{method: "POST", path: "/api/calendar/events/create", summary: "Request an event creation", tag: "write", scope: apikeys.ScopeEventsCreate, request: "CreateEvent", submission: true},
+	{method: "POST", path: "/api/calendar/events/create-batch", summary: "Request several event creations under one approval", tag: "write", scope: apikeys.ScopeEventsCreate, request: "BatchEvents", submission: true},
+	{method: "POST", path: "/api/calendar/events/update", summary: "Request an event update", tag: "write", scope: apikeys.ScopeEventsUpdate, request: "UpdateEvent", submission: true},
+	{method: "POST", path: "/api/calendar/events/delete", summary: "Request an event deletion", tag: "write", scope: apikeys.ScopeEventsDelete, request: "DeleteEvent", submission: true},
+	{method: "POST", path: "/api/calendar/{calendarId}/events/{eventId}/move", summary: "Request moving an event to another calendar", tag: "write", scope: apikeys.ScopeEventsMove, request: "MoveEvent", submission: true},
+	{method: "POST", path: "/api/calendar/{calendarId}/events/{eventId}/duplicate", summary: "Request a copy of an event", tag: "write", scope: apikeys.ScopeEventsCreate, request: "DuplicateEvent", submission: true},
+	{method: "POST", path: "/api/events/import", summary: "Prefill an update intent from an existing event", tag: "write", scope: apikeys.ScopeEventsRead, request: "ImportEvent"},
+
+	{method: "GET", path: "/api/requests", summary: "List the key's requests", tag: "requests", scope: apikeys.ScopeRequestsRead, query: []apiParam{
+		{"tag", "string", "Only requests carrying this tag"},
+		{"envelope", "boolean", "Return {data, pagination} instead of the original body"},
+	}},
+	{method: "POST", path: "/api/requests/validate", summary: "Check how a write request would be decided without submitting it", tag: "requests", request: "ValidateRequest",
+		scopeNote: "Requires the scope of the operation being checked."},
+	{method: "GET", path: "/api/requests/{requestId}", summary: "Get a request", tag: "requests", scope: apikeys.ScopeRequestsRead},
+	{method: "GET", path: "/api/requests/by-idempotency/{key}", summary: "Find a request by Idempotency-Key", tag: "requests", scope: apikeys.ScopeRequestsRead},
+	{method: "GET", path: "/api/requests/{requestId}/timeline", summary: "Request history", tag: "requests", scope: apikeys.ScopeRequestsRead},
+	{method: "GET", path: "/api/requests/{requestId}/results", summary: "Per-item results of a batch request", tag: "requests", scope: apikeys.ScopeRequestsRead},
+	{method: "POST", path: "/api/requests/{requestId}/cancel", summary: "Cancel a pending request", tag: "requests", scope: apikeys.ScopeRequestsCancel},
+	{method: "POST", path: "/api/requests/{requestId}/notify", summary: "Resend approval notifications", tag: "requests", scope: apikeys.ScopeAdmin, query: []apiParam{
+		{"force", "boolean", "Also resend to providers that already delivered it"},
+	}},
+	{method: "POST", path: "/api/requests/{requestId}/retry", summary: "Run a failed request again", tag: "requests", scope: apikeys.ScopeAdmin},
+	{method: "GET", path: "/api/requests/{requestId}/notification-preview", summary: "Render the approval message without sending it", tag: "requests", scope: apikeys.ScopeAdmin, query: []apiParam{
+		{"provider", "string", "Provider to render for"},
+	}},
+
+	{method: "POST", path: "/api/callback/approve/{token}", summary: "Approve with a decision token", tag: "callbacks", public: true},
+	{method: "POST", path: "/api/callback/deny/{token}", summary: "Deny with a decision token", tag: "callbacks", public: true},
+	{method: "POST", path: "/api/callback/suggest/{token}", summary: "Suggest a change with a decision token", tag: "callbacks", public: true, query: []apiParam{
+		{"suggestion", "string", "Suggested change"},
+	}},
+	{method: "GET", path: "/api/callback/approve/{token}", summary: "Approve with a decision token", tag: "callbacks", public: true},
+	{method: "GET", path: "/api/callback/deny/{token}", summary: "Deny with a decision token", tag: "callbacks", public: true},
+
+	{method: "GET", path: "/api/admin/stats", summary: "Request and key statistics", tag: "admin", scope: apikeys.ScopeAdmin},
+	{method: "GET", path: "/api/admin/audit", summary: "Recent audit entries", tag: "admin", scope: apikeys.ScopeAdmin, query: []apiParam{
+		{"api_key_id", "string", "Filter by API key"},
+		{"event_type", "string", "Filter by event type"},
+		{"limit", "integer", "Page size"},
+		{"offset", "integer", "Rows to skip"},
+		{"envelope", "boolean", "Return {data, pagination} instead of the original body"},
+	}},
+	{method: "GET", path: "/api/admin/audit/verify", summary: "Verify the audit hash chain", tag: "admin", scope: apikeys.ScopeAdmin},
+	{method: "GET", path: "/api/admin/keys", summary: "List API keys", tag: "admin", scope: apikeys.ScopeAdmin, query: []apiParam{
+		{"tier", "string", "Filter by tier"},
+		{"q", "string", "Search names and prefixes"},
+		{"includeRevoked", "boolean", "Include revoked keys"},
+		{"limit", "integer", "Page size"},
+		{"offset", "integer", "Rows to skip"},
+		{"envelope", "boolean", "Return {data, pagination} instead of the original body"},
+	}},
+	{method: "GET", path: "/api/admin/keys/usage", summary: "Request counts and last use per API key", tag: "admin", scope: apikeys.ScopeAdmin, query: []apiParam{
+		{"days", "integer", "Window in days (default: the stats window)"},
+	}},
+	{method: "PATCH", path: "/api/admin/keys/{id}/constraints", summary: "Change an API key's constraints", tag: "admin", scope: apikeys.ScopeAdmin},
+	{method: "GET", path: "/api/admin/keys/{id}/audit", summary: "Audit trail of one API key", tag: "admin", scope: apikeys.ScopeRequestsRead, query: []apiParam{
+		{"event_type", "string", "Filter by event type"},
+		{"limit", "integer", "Page size"},
+		{"offset", "integer", "Rows to skip"},
+		{"envelope", "boolean", "Return {data, pagination} instead of the original body"},
+	}},
+	{method: "GET", path: "/api/admin/backup", summary: "Download a database backup", tag: "admin", scope: apikeys.ScopeAdmin},
+	{method: "POST", path: "/api/admin/test-webhook", summary: "Send a test webhook", tag: "admin", scope: apikeys.ScopeAdmin},
+	{method: "GET", path: "/api/admin/webhook-failures", summary: "List failed webhook deliveries", tag: "admin", scope: apikeys.ScopeAdmin, query: []apiParam{
+		{"state", "string", "unresolved (default), resolved or all"},
+		{"request_id", "string", "Filter by request"},
+		{"limit", "integer", "Page size"},
+		{"offset", "integer", "Rows to skip"},
+		{"envelope", "boolean", "Return {data, pagination} instead of the original body"},
+	}},
+	{method: "POST", path: "/api/admin/webhook-failures/{id}/retry", summary: "Retry a failed webhook delivery", tag: "admin", scope: apikeys.ScopeAdmin},
+	{method: "GET", path: "/api/admin/retention/preview", summary: "Preview what retention cleanup would delete", tag: "admin", scope: apikeys.ScopeAdmin, query: []apiParam{
+		{"ids", "boolean", "Include the IDs that would be deleted"},
+	}},
+	{method: "GET", path: "/api/admin/oauth/status", summary: "Show the stored Google OAuth token's scopes and refresh status", tag: "admin", scope: apikeys.ScopeAdmin},
+}
+
+// openAPISchemas are the named request and response bodies. Payload schemas
+// are the ones requests are validated against.
+var openAPISchemas = map[string]*google.Schema{
+	"CreateEvent":    google.CreateEventSchema,
+	"UpdateEvent":    google.UpdateEventSchema,
+	"DeleteEvent":    google.DeleteEventSchema,
+	"MoveEvent":      google.MoveEventSchema,
+	"BatchEvents":    google.BatchEventSchema,
+	"DuplicateEvent": {Type: "object", Properties: map[string]*google.Schema{"shiftMinutes": {Type: "integer"}}},
+	"ImportEvent": {Type: "object", Properties: map[string]*google.Schema{
+		"calendarId": {Type: "string", Format: "calendar-id"},
+		"eventId":    {Type: "string"},
+		"url":        {Type: "string"},
+	}},
+	"ValidateRequest": {Type: "object", Required: []string{"operation", "payload"}, Properties: map[string]*google.Schema{
+		"operation": {Type: "string", Enum: []string{"create_event", "create_events_batch", "update_event", "delete_event", "move_event"}},
+		"payload":   {Type: "object"},
+	}},
+	"FreeBusyRequest": {Type: "object", Required: []string{"timeMin", "timeMax"}, Properties: map[string]*google.Schema{
+		"timeMin":   {Type: "string", Format: "date-time"},
+		"timeMax":   {Type: "string", Format: "date-time"},
+		"calendars": {Type: "array", Items: &google.Schema{Type: "string", Format: "calendar-id"}},
+	}},
+	"SubmittedRequest": {Type: "object", Required: []string{"request_id", "status"}, Properties: map[string]*google.Schema{
+		"request_id": {Type: "string"},
+		"status":     {Type: "string", Enum: []string{"pending_approval", "approved", "executing", "completed"}},
+		"expires_at": {Type: "string", Format: "date-time"},
+		"message":    {Type: "string"},
+	}},
+	"Error": {Type: "object", Required: []string{"error"}, Properties: map[string]*google.Schema{
+		"error": {Type: "object", Required: []string{"code", "message"}, Properties: map[string]*google.Schema{
+			"code":      {Type: "string", Enum: errorCodes},
+			"message":   {Type: "string"},
+			"requestId": {Type: "string"},
+			"details":   {Type: "object"},
+		}},
+	}},
+}
+
+var errorCodes = []string{
+	response.ErrCodeInvalidAPIKey, response.ErrCodeInsufficientPermissions, response.ErrCodeRateLimited,
+	response.ErrCodeApprovalDenied, response.ErrCodeChangeRequested, response.ErrCodeApprovalExpired,
+	response.ErrCodeRequestNotFound, response.ErrCodeGoogleAPIError, response.ErrCodeValidationError,
+	response.ErrCodeNotCompleted, response.ErrCodeAlreadyResolved, response.ErrCodeRequestExpired,
+	response.ErrCodeConstraintViolation, response.ErrCodeUnauthorized, response.ErrCodeInvalidToken,
+	response.ErrCodeTokenExpired, response.ErrCodeTokenConsumed, response.ErrCodeInternalError,
+	response.ErrCodeNotImplemented, "FORBIDDEN", "NOT_FOUND", "CONFLICT", "PAYLOAD_TOO_LARGE",
+	"SERVICE_UNAVAILABLE", "ERROR",
+}
+
+const openAPIDescription = "SchedLock puts a human approval step in front of Google Calendar writes. " +
+	"Write endpoints create a request that is executed once approved. Write bodies may also carry " +
+	"`context` (a note for the approver) and `tags`, and accept an `Idempotency-Key` header, an " +
+	"`X-Request-Priority` header (low, normal or high) and an `X-Approval-Timeout-Minutes` header " +
+	"that replaces the configured approval timeout for that request."
+
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Servers    []openAPIServer                         `json:"servers,omitempty"`
+	Security   []map[string][]string                   `json:"security"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*google.Schema        `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+type openAPIOperation struct {
+	Summary     string                     `json:"summary"`
+	OperationID string                     `json:"operationId"`
+	Tags        []string                   `json:"tags"`
+	Description string                     `json:"description,omitempty"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string        `json:"name"`
+	In          string        `json:"in"`
+	Required    bool          `json:"required,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Schema      google.Schema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema openAPIRef `json:"schema"`
+}
+
+type openAPIRef struct {
+	Ref string `json:"$ref,omitempty"`
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// buildOpenAPI assembles the document from apiOperations.
+func buildOpenAPI(baseURL string) *openAPIDocument {
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:       "SchedLock API",
+			Version:     "1.0.0",
+			Description: openAPIDescription,
+		},
+		Security: []map[string][]string{{"bearerAuth": {}}},
+		Paths:    make(map[string]map[string]*openAPIOperation),
+		Components: openAPIComponents{
+			Schemas: openAPISchemas,
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+	if baseURL != "" {
+		doc.Servers = []openAPIServer{{URL: baseURL}}
+	}
+
+	jsonRef := func(name string) map[string]openAPIMediaType {
+		return map[string]openAPIMediaType{"application/json": {Schema: openAPIRef{Ref: "#/components/schemas/" + name}}}
+	}
+	errorResponse := openAPIResponse{Description: "Error", Content: jsonRef("Error")}
+
+	for _, route := range apiOperations {
+		op := &openAPIOperation{
+			Summary:     route.summary,
+			OperationID: operationID(route.method, route.path),
+			Tags:        []string{route.tag},
+			Responses:   map[string]openAPIResponse{"default": errorResponse},
+		}
+		if route.public {
+			op.Security = []map[string][]string{}
+		} else if route.scopeNote != "" {
+			op.Description = route.scopeNote
+		} else {
+			op.Description = "Requires the `" + route.scope + "` scope."
+		}
+		for _, match := range pathParamPattern.FindAllStringSubmatch(route.path, -1) {
+			op.Parameters = append(op.Parameters, openAPIParameter{Name: match[1], In: "path", Required: true, Schema: google.Schema{Type: "string"}})
+		}
+		for _, param := range route.query {
+			op.Parameters = append(op.Parameters, openAPIParameter{Name: param.name, In: "query", Description: param.description, Schema: google.Schema{Type: param.kind}})
+		}
+		if route.request != "" {
+			op.RequestBody = &openAPIRequestBody{Required: true, Content: jsonRef(route.request)}
+		}
+		if route.submission {
+			op.Responses["202"] = openAPIResponse{Description: "Request held for approval", Content: jsonRef("SubmittedRequest")}
+			op.Responses["200"] = openAPIResponse{Description: "Request auto-approved", Content: jsonRef("SubmittedRequest")}
+		} else {
+			op.Responses["200"] = openAPIResponse{Description: "Success"}
+		}
+
+		if doc.Paths[route.path] == nil {
+			doc.Paths[route.path] = make(map[string]*openAPIOperation)
+		}
+		doc.Paths[route.path][strings.ToLower(route.method)] = op
+	}
+	return doc
+}
+
+// operationID derives a stable operationId such as "post_calendar_events_create".
+func operationID(method, path string) string {
+	id := strings.TrimPrefix(path, "/api/")
+	id = strings.NewReplacer("{", "", "}", "", "/", "_", "-", "_", ".", "_").Replace(id)
+	return strings.ToLower(method) + "_" + id
+}
+
+// OpenAPI serves the OpenAPI 3 description of the API. It needs no API key.
+func (h *Handler) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, buildOpenAPI(h.config.Server.BaseURL))
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+)
+
+type recordingRouter struct {
+	patterns []string
+}
+
+func (r *recordingRouter) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	r.patterns = append(r.patterns, pattern)
+}
+
+func TestOpenAPICoversRegisteredRoutes(t *testing.T) {
+	router := &recordingRouter{}
+	(&Handler{}).RegisterRoutes(router)
+
+	doc := buildOpenAPI("")
+	registered := make(map[string]bool)
+	for _, pattern := range router.patterns {
+		method, path, ok := strings.Cut(pattern, " ")
+		if !ok {
+			t.Errorf("route %q has no method", pattern)
+			continue
+		}
+		registered[pattern] = true
+		if doc.Paths[path][strings.ToLower(method)] == nil {
+			t.Errorf("route %s is missing from the OpenAPI document", pattern)
+		}
+	}
+
+	for _, route := range apiOperations {
+		if !registered[route.method+" "+route.path] {
+			t.Errorf("OpenAPI documents %s %s, which is not registered", route.method, route.path)
+		}
+		if !route.public && route.scope == "" && route.scopeNote == "" {
+			t.Errorf("%s %s needs a scope or public", route.method, route.path)
+		}
+		if route.request != "" && openAPISchemas[route.request] == nil {
+			t.Errorf("%s %s references unknown schema %s", route.method, route.path, route.request)
+		}
+	}
+}
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	h := &Handler{config: &config.Config{Server: config.ServerConfig{BaseURL: "https://schedlock.example.com"}}}
+
+	rr := httptest.NewRecorder()
+	h.OpenAPI(rr, httptest.NewRequest("GET", "/api/openapi.json", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name string `json:"name"`
+				In   string `json:"in"`
+			} `json:"parameters"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("openapi = %q", doc.OpenAPI)
+	}
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "https://schedlock.example.com" {
+		t.Errorf("servers = %+v", doc.Servers)
+	}
+	params := doc.Paths["/api/calendar/{calendarId}/events/{eventId}"]["get"].Parameters
+	if len(params) != 2 || params[0].Name != "calendarId" || params[0].In != "path" {
+		t.Errorf("path parameters = %+v", params)
+	}
+	if _, ok := doc.Components.Schemas["CreateEvent"]; !ok {
+		t.Error("expected the CreateEvent schema")
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/google"
+	"github.com/dtorcivia/schedlock/internal/response"
+)
+
+// ValidateRequestBody names the operation to check and the body that would
+// be sent to its write endpoint.
+type ValidateRequestBody struct {
+	Operation string          `json:"operation"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// preflightOperation is a write operation that can be checked without
+// submitting it: the scope its endpoint requires and its payload type.
+type preflightOperation struct {
+	scope  string
+	intent func() interface{}
+}
+
+var preflightOperations = map[string]preflightOperation{
+	database.OperationCreateEvent:       {apikeys.ScopeEventsCreate, func() interface{} { return &google.EventIntent{} }},
+	database.OperationCreateEventsBatch: {apikeys.ScopeEventsCreate, func() interface{} { return &google.EventBatchIntent{} }},
+	database.OperationUpdateEvent:       {apikeys.ScopeEventsUpdate, func() interface{} { return &google.EventUpdateIntent{} }},
+	database.OperationDeleteEvent:       {apikeys.ScopeEventsDelete, func() interface{} { return &google.EventDeleteIntent{} }},
+	database.OperationMoveEvent:         {apikeys.ScopeEventsMove, func() interface{} { return &google.EventMoveIntent{} }},
+}
+
+// ValidateRequest reports whether a write request would run right away,
+// wait for approval or be denied, without submitting it. The payload goes
+// through the same validation, sanitizing and constraint checks as the
+// operation's endpoint. Nothing is stored; Google is only asked for the
+// current event when an update has to be checked against it.
+func (h *Handler) ValidateRequest(w http.ResponseWriter, r *http.Request) {
+	var body ValidateRequestBody
+	if err := h.parseJSON(w, r, &body); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	op, ok := preflightOperations[body.Operation]
+	if !ok {
+		response.Error(w, http.StatusBadRequest, "operation must be one of create_event, create_events_batch, update_event, delete_event or move_event", nil)
+		return
+	}
+	authKey := requireScope(w, r, op.scope)
+	if authKey == nil {
+		return
+	}
+	if len(body.Payload) == 0 || string(body.Payload) == "null" {
+		response.Error(w, http.StatusBadRequest, "payload is required", nil)
+		return
+	}
+
+	intent := op.intent()
+	if _, err := decodeSubmission(body.Payload, intent); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	// A denial is the answer here, not an error
+	decision, err := h.prepareIntent(r.Context(), authKey, intent)
+	var violation *apikeys.ConstraintViolation
+	if err != nil && !errors.As(err, &violation) {
+		writeConstraintError(w, err)
+		return
+	}
+
+	result := map[string]interface{}{
+		"operation":  body.Operation,
+		"outcome":    preflightOutcome(decision),
+		"constraint": decision.Constraint,
+		"reason":     decision.Reason,
+	}
+	if violation != nil {
+		result["violation"] = map[string]interface{}{
+			"constraint": violation.Constraint,
+			"message":    violation.Message,
+		}
+	}
+	response.JSON(w, http.StatusOK, result)
+}
+
+// prepareIntent runs the prepare function matching the intent's type.
+func (h *Handler) prepareIntent(ctx context.Context, authKey *apikeys.AuthenticatedKey, intent interface{}) (apikeys.ConstraintDecision, error) {
+	switch intent := intent.(type) {
+	case *google.EventIntent:
+		return h.prepareCreate(authKey, intent)
+	case *google.EventBatchIntent:
+		return h.prepareBatch(authKey, intent)
+	case *google.EventUpdateIntent:
+		return h.prepareUpdate(ctx, authKey, intent)
+	case *google.EventDeleteIntent:
+		return h.prepareDelete(authKey, intent)
+	case *google.EventMoveIntent:
+		return h.prepareMove(authKey, intent)
+	}
+	return apikeys.ConstraintDecision{}, fmt.Errorf("unsupported intent %T", intent)
+}
+
+// preflightOutcome names a decision for pre-flight responses.
+func preflightOutcome(decision apikeys.ConstraintDecision) string {
+	switch decision.Result {
+	case apikeys.ConstraintDeny:
+		return "deny"
+	case apikeys.ConstraintRequireApproval:
+		return "require_approval"
+	default:
+		return "auto"
+	}
+}
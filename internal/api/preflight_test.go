@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/google"
+	"github.com/dtorcivia/schedlock/internal/server/middleware"
+)
+
+func validateRequest(t *testing.T, h *Handler, authKey *apikeys.AuthenticatedKey, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest("POST", "http://example.com/api/requests/validate", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, authKey))
+	rr := httptest.NewRecorder()
+	h.ValidateRequest(rr, req)
+
+	var result map[string]interface{}
+	if rr.Code == http.StatusOK {
+		if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}
+	return rr, result
+}
+
+func TestValidateRequestOutcomes(t *testing.T) {
+	// No request repository or engine: a pre-flight check must not need them
+	h := &Handler{calendarClient: &fakeCalendarClient{}}
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	create := `{"operation":"create_event","payload":{"calendarId":"primary","summary":"Sync","start":"` +
+		start.Format(time.RFC3339) + `","end":"` + start.Add(time.Hour).Format(time.RFC3339) + `"}}`
+
+	tests := []struct {
+		name       string
+		key        *apikeys.AuthenticatedKey
+		outcome    string
+		constraint string
+	}{
+		{"admin tier runs right away", &apikeys.AuthenticatedKey{ID: "key1", Tier: database.TierAdmin}, "auto", "tier"},
+		{"write tier needs approval", &apikeys.AuthenticatedKey{ID: "key1", Tier: database.TierWrite}, "require_approval", "tier"},
+		{"calendar outside the allowlist is denied", &apikeys.AuthenticatedKey{
+			ID:          "key1",
+			Tier:        database.TierWrite,
+			Constraints: &database.KeyConstraints{CalendarAllowlist: []string{"team@group.calendar.google.com"}},
+		}, "deny", "calendar_allowlist"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, result := validateRequest(t, h, tt.key, create)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+			if result["outcome"] != tt.outcome || result["constraint"] != tt.constraint {
+				t.Errorf("result = %v, want outcome %s from %s", result, tt.outcome, tt.constraint)
+			}
+			if _, ok := result["violation"]; ok != (tt.outcome == "deny") {
+				t.Errorf("violation present = %v for outcome %s", ok, tt.outcome)
+			}
+		})
+	}
+}
+
+func TestRequireAllHoldsEveryWrite(t *testing.T) {
+	h := &Handler{
+		calendarClient: &fakeCalendarClient{},
+		config:         &config.Config{Approval: config.ApprovalConfig{RequireAll: true}},
+	}
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	create := `{"operation":"create_event","payload":{"calendarId":"primary","summary":"Sync","start":"` +
+		start.Format(time.RFC3339) + `","end":"` + start.Add(time.Hour).Format(time.RFC3339) + `"}}`
+
+	tests := []struct {
+		name       string
+		key        *apikeys.AuthenticatedKey
+		body       string
+		outcome    string
+		constraint string
+	}{
+		{"admin tier is held", &apikeys.AuthenticatedKey{ID: "key1", Tier: database.TierAdmin}, create, "require_approval", "require_all"},
+		{"admin delete is held", &apikeys.AuthenticatedKey{ID: "key1", Tier: database.TierAdmin},
+			`{"operation":"delete_event","payload":{"eventId":"evt1"}}`, "require_approval", "require_all"},
+		{"write tier keeps its reason", &apikeys.AuthenticatedKey{ID: "key1", Tier: database.TierWrite}, create, "require_approval", "tier"},
+		{"denials still apply", &apikeys.AuthenticatedKey{
+			ID:          "key1",
+			Tier:        database.TierAdmin,
+			Constraints: &database.KeyConstraints{CalendarAllowlist: []string{"team@group.calendar.google.com"}},
+		}, create, "deny", "calendar_allowlist"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, result := validateRequest(t, h, tt.key, tt.body)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+			if result["outcome"] != tt.outcome || result["constraint"] != tt.constraint {
+				t.Errorf("result = %v, want outcome %s from %s", result, tt.outcome, tt.constraint)
+			}
+		})
+	}
+}
+
+func TestValidateRequestUpdateLoadsEvent(t *testing.T) {
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	fake := &fakeCalendarClient{event: &google.Event{
+		ID:    "evt1",
+		Start: &google.EventTime{DateTime: start},
+		End:   &google.EventTime{DateTime: start.Add(time.Hour)},
+	}}
+	h := &Handler{calendarClient: fake}
+	authKey := &apikeys.AuthenticatedKey{
+		ID:          "key1",
+		Tier:        database.TierWrite,
+		Constraints: &database.KeyConstraints{AutoApproveFields: []string{"description"}},
+	}
+
+	rr, result := validateRequest(t, h, authKey, `{"operation":"update_event","payload":{"eventId":"evt1","description":"New agenda"}}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if fake.lastGetEventID != "evt1" {
+		t.Errorf("expected the current event to be loaded, got %q", fake.lastGetEventID)
+	}
+	if result["outcome"] != "auto" || result["constraint"] != "auto_approve_fields" {
+		t.Errorf("result = %v, want auto from auto_approve_fields", result)
+	}
+}
+
+func TestValidateRequestRejectsBadInput(t *testing.T) {
+	h := &Handler{calendarClient: &fakeCalendarClient{}}
+	writer := &apikeys.AuthenticatedKey{ID: "key1", Tier: database.TierWrite}
+	reader := &apikeys.AuthenticatedKey{ID: "key2", Tier: database.TierRead}
+
+	tests := []struct {
+		name string
+		key  *apikeys.AuthenticatedKey
+		body string
+		want int
+	}{
+		{"unknown operation", writer, `{"operation":"rename_calendar","payload":{}}`, http.StatusBadRequest},
+		{"missing payload", writer, `{"operation":"delete_event"}`, http.StatusBadRequest},
+		{"invalid intent", writer, `{"operation":"create_event","payload":{"summary":"No times"}}`, http.StatusBadRequest},
+		{"update without changes", writer, `{"operation":"update_event","payload":{"eventId":"evt1"}}`, http.StatusBadRequest},
+		{"missing scope", reader, `{"operation":"delete_event","payload":{"eventId":"evt1"}}`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, _ := validateRequest(t, h, tt.key, tt.body)
+			if rr.Code != tt.want {
+				t.Errorf("expected status %d, got %d: %s", tt.want, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
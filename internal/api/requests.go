@@ -1,20 +1,48 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/engine"
+	"github.com/dtorcivia/schedlock/internal/notifications"
 	"github.com/dtorcivia/schedlock/internal/response"
+	"github.com/dtorcivia/schedlock/internal/util"
 )
 
-// ListRequests returns requests for the authenticated API key.
+// listRequestsLimit is how many of a key's most recent requests
+// ListRequests returns.
+const listRequestsLimit = 50
+
+// ListRequests returns requests for the authenticated API key, optionally
+// only those carrying the tag given in the "tag" query parameter.
 func (h *Handler) ListRequests(w http.ResponseWriter, r *http.Request) {
-	authKey := requireTier(w, r, "read")
+	authKey := requireScope(w, r, apikeys.ScopeRequestsRead)
 	if authKey == nil {
 		return
 	}
 
 	ctx := r.Context()
-	requests, err := h.requestRepo.GetByAPIKeyID(ctx, authKey.ID, 50)
+	var (
+		requests []database.Request
+		err      error
+	)
+	if tag := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("tag"))); tag != "" {
+		if _, err := util.NormalizeTags([]string{tag}); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		requests, err = h.requestRepo.GetByAPIKeyIDAndTag(ctx, authKey.ID, tag, listRequestsLimit)
+	} else {
+		requests, err = h.requestRepo.GetByAPIKeyID(ctx, authKey.ID, listRequestsLimit)
+	}
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "failed to list requests", err)
 		return
@@ -27,6 +55,7 @@ func (h *Handler) ListRequests(w http.ResponseWriter, r *http.Request) {
 			"id":         req.ID,
 			"operation":  req.Operation,
 			"status":     req.Status,
+			"priority":   req.Priority,
 			"created_at": req.CreatedAt,
 			"expires_at": req.ExpiresAt,
 		}
@@ -46,18 +75,21 @@ func (h *Handler) ListRequests(w http.ResponseWriter, r *http.Request) {
 		if req.SuggestionText.Valid {
 			item["suggestion"] = req.SuggestionText.String
 		}
+		if len(req.Tags) > 0 {
+			item["tags"] = req.Tags
+		}
 
 		items = append(items, item)
 	}
 
-	response.JSON(w, http.StatusOK, map[string]interface{}{
+	response.Paginated(w, r, items, response.Pagination{Count: len(items), Limit: listRequestsLimit}, map[string]interface{}{
 		"requests": items,
 	})
 }
 
 // GetRequest returns a specific request.
 func (h *Handler) GetRequest(w http.ResponseWriter, r *http.Request) {
-	authKey := requireTier(w, r, "read")
+	authKey := requireScope(w, r, apikeys.ScopeRequestsRead)
 	if authKey == nil {
 		return
 	}
@@ -68,34 +100,203 @@ func (h *Handler) GetRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := r.Context()
-	req, err := h.requestRepo.GetByID(ctx, requestID)
+	req := h.getReadableRequest(w, r, authKey, requestID)
+	if req == nil {
+		return
+	}
+
+	h.markResultFetched(r.Context(), authKey, req)
+	resp := requestDetail(req)
+	if req.Status == database.StatusApproved && h.engine != nil {
+		if ahead, ok := h.engine.QueuePosition(req.ID); ok {
+			resp["queue_ahead"] = ahead
+		}
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// getReadableRequest loads a request the key may read: its own, or any request
+// for admin keys. It writes the error response and returns nil otherwise.
+func (h *Handler) getReadableRequest(w http.ResponseWriter, r *http.Request, authKey *apikeys.AuthenticatedKey, requestID string) *database.Request {
+	req, err := h.requestRepo.GetByID(r.Context(), requestID)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "failed to get request", err)
-		return
+		return nil
 	}
 
 	if req == nil {
 		response.Error(w, http.StatusNotFound, "request not found", nil)
-		return
+		return nil
 	}
 
 	// Only allow access to own requests (unless admin)
-	if req.APIKeyID != authKey.ID && authKey.Tier != "admin" {
+	if req.APIKeyID != authKey.ID && !authKey.HasScope(apikeys.ScopeAdmin) {
 		response.Error(w, http.StatusForbidden, "access denied", nil)
+		return nil
+	}
+	return req
+}
+
+// TimelineEntry is one step in a request's history.
+type TimelineEntry struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Kind      string          `json:"kind"`  // status, suggestion, notification or event
+	Event     string          `json:"event"` // audit event type, or notification status
+	Actor     string          `json:"actor,omitempty"`
+	Provider  string          `json:"provider,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	Details   json.RawMessage `json:"details,omitempty"`
+}
+
+// GetRequestTimeline returns a request's history in time order, merging
+// audit entries with notification deliveries and callbacks.
+func (h *Handler) GetRequestTimeline(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeRequestsRead)
+	if authKey == nil {
+		return
+	}
+
+	requestID := r.PathValue("requestId")
+	if requestID == "" {
+		response.Error(w, http.StatusBadRequest, "request ID required", nil)
+		return
+	}
+
+	req := h.getReadableRequest(w, r, authKey, requestID)
+	if req == nil {
+		return
+	}
+
+	ctx := r.Context()
+	auditEntries, err := h.auditLogger.GetByRequestID(ctx, req.ID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load audit log", err)
+		return
+	}
+	var notificationLogs []notifications.NotificationLog
+	if h.notificationMgr != nil {
+		if notificationLogs, err = h.notificationMgr.GetNotificationLog(ctx, req.ID); err != nil {
+			response.Error(w, http.StatusInternalServerError, "failed to load notification log", err)
+			return
+		}
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"request_id": req.ID,
+		"status":     req.Status,
+		"timeline":   buildTimeline(auditEntries, notificationLogs),
+	})
+}
+
+// buildTimeline merges audit and notification records by timestamp. Audit
+// entries for notification deliveries are dropped because the notification
+// log records the same deliveries with provider and error details. The sort
+// is stable, so entries within the same second keep audit-then-delivery order.
+func buildTimeline(auditEntries []database.AuditLogEntry, notificationLogs []notifications.NotificationLog) []TimelineEntry {
+	timeline := make([]TimelineEntry, 0, len(auditEntries)+len(notificationLogs))
+
+	for _, entry := range auditEntries {
+		kind := "event"
+		switch {
+		case entry.EventType == database.AuditNotificationSent || entry.EventType == database.AuditNotificationFailed:
+			continue
+		case entry.EventType == database.AuditRequestChanged:
+			kind = "suggestion"
+		case strings.HasPrefix(entry.EventType, "request_"):
+			kind = "status"
+		}
+		timeline = append(timeline, TimelineEntry{
+			Timestamp: entry.Timestamp,
+			Kind:      kind,
+			Event:     entry.EventType,
+			Actor:     entry.Actor.String,
+			Details:   entry.Details,
+		})
+	}
+
+	for _, log := range notificationLogs {
+		timeline = append(timeline, TimelineEntry{
+			Timestamp: log.SentAt,
+			Kind:      "notification",
+			Event:     log.Status,
+			Provider:  log.Provider,
+			Error:     log.ErrorMessage,
+		})
+		if log.CallbackAt != nil {
+			timeline = append(timeline, TimelineEntry{
+				Timestamp: *log.CallbackAt,
+				Kind:      "notification",
+				Event:     "callback_received",
+				Provider:  log.Provider,
+			})
+		}
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Timestamp.Before(timeline[j].Timestamp)
+	})
+	return timeline
+}
+
+// GetRequestByIdempotencyKey finds the caller's request submitted with an
+// Idempotency-Key in the last 24 hours, so a client that lost the response
+// can recover the request ID without submitting again. Lookups are always
+// scoped to the calling key, admin or not.
+func (h *Handler) GetRequestByIdempotencyKey(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeRequestsRead)
+	if authKey == nil {
+		return
+	}
+
+	key := r.PathValue("key")
+	if key == "" {
+		response.Error(w, http.StatusBadRequest, "idempotency key required", nil)
+		return
+	}
+
+	req, err := h.requestRepo.FindByIdempotencyKey(r.Context(), authKey.ID, key)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to get request", err)
 		return
 	}
+	if req == nil {
+		response.Error(w, http.StatusNotFound, "no request found for this idempotency key", nil)
+		return
+	}
+
+	h.markResultFetched(r.Context(), authKey, req)
+	response.JSON(w, http.StatusOK, requestDetail(req))
+}
 
+// markResultFetched records that the key that made a completed request has
+// read its result, so the unclaimed results cleanup keeps it. Reads by other
+// admin keys do not count.
+func (h *Handler) markResultFetched(ctx context.Context, authKey *apikeys.AuthenticatedKey, req *database.Request) {
+	if req.APIKeyID != authKey.ID || req.Status != database.StatusCompleted {
+		return
+	}
+	if err := h.requestRepo.MarkResultFetched(ctx, req.ID); err != nil {
+		util.Warn("Failed to record result fetch", "request_id", req.ID, "error", err)
+	}
+}
+
+// requestDetail builds the JSON body describing a single request.
+func requestDetail(req *database.Request) map[string]interface{} {
 	resp := map[string]interface{}{
 		"id":          req.ID,
 		"operation":   req.Operation,
 		"status":      req.Status,
+		"priority":    req.Priority,
 		"payload":     req.Payload,
 		"created_at":  req.CreatedAt,
 		"expires_at":  req.ExpiresAt,
 		"retry_count": req.RetryCount,
 	}
 
+	if req.ManualRetryCount > 0 {
+		resp["manual_retry_count"] = req.ManualRetryCount
+	}
+
 	if req.Result != nil {
 		resp["result"] = req.Result
 	}
@@ -111,6 +312,12 @@ func (h *Handler) GetRequest(w http.ResponseWriter, r *http.Request) {
 	if req.Error.Valid {
 		resp["error"] = req.Error.String
 	}
+	if req.Context != "" {
+		resp["context"] = req.Context
+	}
+	if len(req.Tags) > 0 {
+		resp["tags"] = req.Tags
+	}
 	if req.SuggestionText.Valid {
 		resp["suggestion"] = map[string]interface{}{
 			"text":         req.SuggestionText.String,
@@ -119,12 +326,12 @@ func (h *Handler) GetRequest(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	response.JSON(w, http.StatusOK, resp)
+	return resp
 }
 
 // CancelRequest cancels a pending request.
 func (h *Handler) CancelRequest(w http.ResponseWriter, r *http.Request) {
-	authKey := requireTier(w, r, "write")
+	authKey := requireScope(w, r, apikeys.ScopeRequestsCancel)
 	if authKey == nil {
 		return
 	}
@@ -146,3 +353,119 @@ func (h *Handler) CancelRequest(w http.ResponseWriter, r *http.Request) {
 		"message": "request cancelled",
 	})
 }
+
+// ResendNotification re-sends approval notifications for a pending request
+// (admin only). Providers that already delivered it are skipped unless the
+// "force" query parameter is true.
+func (h *Handler) ResendNotification(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeAdmin)
+	if authKey == nil {
+		return
+	}
+
+	requestID := r.PathValue("requestId")
+	if requestID == "" {
+		response.Error(w, http.StatusBadRequest, "request ID required", nil)
+		return
+	}
+
+	force := r.URL.Query().Get("force") == "true"
+	err := h.engine.ResendApprovalNotification(r.Context(), requestID, "api:"+authKey.ID, force)
+	if err != nil {
+		var cooldown *engine.ResendCooldownError
+		switch {
+		case errors.Is(err, notifications.ErrAlreadyDelivered):
+			response.Error(w, http.StatusConflict, err.Error()+"; use force=true to resend", nil)
+		case errors.As(err, &cooldown):
+			response.SetRetryAfter(w, cooldown.RetryAfter)
+			response.Error(w, http.StatusTooManyRequests, err.Error(), nil)
+		case errors.Is(err, engine.ErrRequestNotFound):
+			response.Error(w, http.StatusNotFound, "request not found", nil)
+		case errors.Is(err, engine.ErrRequestNotPending):
+			response.Error(w, http.StatusConflict, err.Error(), nil)
+		default:
+			response.Error(w, http.StatusBadGateway, "failed to send notification", err)
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": "notification resent",
+	})
+}
+
+// RetryRequest runs a failed request again (admin only), after a transient
+// Google error outlasted the automatic retries.
+func (h *Handler) RetryRequest(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeAdmin)
+	if authKey == nil {
+		return
+	}
+
+	requestID := r.PathValue("requestId")
+	if requestID == "" {
+		response.Error(w, http.StatusBadRequest, "request ID required", nil)
+		return
+	}
+
+	err := h.engine.RetryFailedRequest(r.Context(), requestID, "api:"+authKey.ID)
+	switch {
+	case errors.Is(err, engine.ErrRequestNotFound):
+		response.Error(w, http.StatusNotFound, "request not found", nil)
+		return
+	case errors.Is(err, engine.ErrRequestNotFailed):
+		response.Error(w, http.StatusConflict, err.Error(), nil)
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to retry request", err)
+		return
+	}
+
+	response.JSON(w, http.StatusAccepted, map[string]interface{}{
+		"message": "request queued for retry",
+		"status":  database.StatusApproved,
+	})
+}
+
+// PreviewNotification returns the approval message one provider would send
+// for a request, without delivering it (admin only). Query parameter:
+// provider (ntfy, pushover, telegram or webhook).
+func (h *Handler) PreviewNotification(w http.ResponseWriter, r *http.Request) {
+	if requireScope(w, r, apikeys.ScopeAdmin) == nil {
+		return
+	}
+
+	requestID := r.PathValue("requestId")
+	provider := r.URL.Query().Get("provider")
+	if requestID == "" || provider == "" {
+		response.Error(w, http.StatusBadRequest, "request ID and provider required", nil)
+		return
+	}
+	if h.notificationMgr == nil {
+		response.Error(w, http.StatusServiceUnavailable, "notifications are not configured", nil)
+		return
+	}
+
+	ctx := r.Context()
+	notification, err := h.engine.PreviewApprovalNotification(ctx, requestID)
+	if errors.Is(err, engine.ErrRequestNotFound) {
+		response.Error(w, http.StatusNotFound, "request not found", nil)
+		return
+	}
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to load request", err)
+		return
+	}
+
+	rendered, err := h.notificationMgr.PreviewApproval(ctx, provider, notification)
+	switch {
+	case errors.Is(err, notifications.ErrProviderNotFound), errors.Is(err, notifications.ErrPreviewUnsupported):
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	case err != nil:
+		response.Error(w, http.StatusInternalServerError, "failed to render notification", err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, rendered)
+}
@@ -0,0 +1,290 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/engine"
+	"github.com/dtorcivia/schedlock/internal/notifications"
+	"github.com/dtorcivia/schedlock/internal/requests"
+	"github.com/dtorcivia/schedlock/internal/server/middleware"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+func TestGetRequestByIdempotencyKey(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	for _, keyID := range []string{"key_a", "key_b"} {
+		if _, err := db.Exec(`
+			INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+			VALUES (?, ?, ?, ?, 'write')
+		`, keyID, "hash_"+keyID, "sk_"+keyID, keyID); err != nil {
+			t.Fatalf("insert api key: %v", err)
+		}
+	}
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, payload, expires_at)
+		VALUES ('req_idem', 'key_a', 'create_event', '{}', ?)
+	`, util.SQLiteTimestamp(time.Now().Add(time.Hour))); err != nil {
+		t.Fatalf("insert request: %v", err)
+	}
+
+	repo := requests.NewRepository(db)
+	if err := repo.StoreIdempotencyKey(context.Background(), "key_a", "order-42", "req_idem"); err != nil {
+		t.Fatalf("store idempotency key: %v", err)
+	}
+
+	h := &Handler{requestRepo: repo}
+	lookup := func(keyID, tier, idempotencyKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "http://example.com/api/requests/by-idempotency/"+idempotencyKey, nil)
+		req.SetPathValue("key", idempotencyKey)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+			ID:   keyID,
+			Tier: tier,
+		}))
+		rr := httptest.NewRecorder()
+		h.GetRequestByIdempotencyKey(rr, req)
+		return rr
+	}
+
+	rr := lookup("key_a", database.TierWrite, "order-42")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var body struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.ID != "req_idem" {
+		t.Errorf("id = %q, want req_idem", body.ID)
+	}
+
+	if rr := lookup("key_a", database.TierWrite, "unknown"); rr.Code != http.StatusNotFound {
+		t.Errorf("unknown key: expected 404, got %d", rr.Code)
+	}
+
+	// Another key, even an admin one, cannot see key_a's idempotency keys
+	if rr := lookup("key_b", database.TierAdmin, "order-42"); rr.Code != http.StatusNotFound {
+		t.Errorf("cross-key lookup: expected 404, got %d", rr.Code)
+	}
+}
+
+func TestGetRequestTimeline(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_a', 'hash_a', 'sk_a', 'key_a', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, payload, expires_at)
+		VALUES ('req_tl', 'key_a', 'create_event', '{}', ?)
+	`, util.SQLiteTimestamp(time.Now().Add(time.Hour))); err != nil {
+		t.Fatalf("insert request: %v", err)
+	}
+
+	ctx := context.Background()
+	auditLogger := engine.NewAuditLogger(db)
+	auditLogger.Log(ctx, database.AuditRequestCreated, "req_tl", "key_a", "api", nil)
+	auditLogger.Log(ctx, database.AuditNotificationSent, "req_tl", "", "system", nil)
+	if _, err := db.Exec(`
+		INSERT INTO notification_log (request_id, provider, status, sent_at, callback_at)
+		VALUES ('req_tl', 'telegram', 'sent', ?, ?)
+	`, util.SQLiteTimestamp(time.Now().Add(time.Minute)), util.SQLiteTimestamp(time.Now().Add(2*time.Minute))); err != nil {
+		t.Fatalf("insert notification log: %v", err)
+	}
+
+	h := &Handler{
+		requestRepo:     requests.NewRepository(db),
+		auditLogger:     auditLogger,
+		notificationMgr: notifications.NewManager(db, &config.Config{}),
+	}
+	req := httptest.NewRequest("GET", "http://example.com/api/requests/req_tl/timeline", nil)
+	req.SetPathValue("requestId", "req_tl")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{
+		ID:   "key_a",
+		Tier: database.TierWrite,
+	}))
+	rr := httptest.NewRecorder()
+	h.GetRequestTimeline(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var body struct {
+		Timeline []TimelineEntry `json:"timeline"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	var events []string
+	for i, entry := range body.Timeline {
+		events = append(events, entry.Kind+":"+entry.Event)
+		if i > 0 && entry.Timestamp.Before(body.Timeline[i-1].Timestamp) {
+			t.Errorf("timeline out of order at %d", i)
+		}
+	}
+	want := []string{"status:request_created", "notification:sent", "notification:callback_received"}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Errorf("timeline = %v, want %v", events, want)
+	}
+}
+
+func TestBatchRequestResults(t *testing.T) {
+	payload := `{"events":[{"calendarId":"a@example.com","summary":"One"},{"calendarId":"b@example.com","summary":"Two"}]}`
+
+	completed := &database.Request{
+		Status:  database.StatusCompleted,
+		Payload: json.RawMessage(payload),
+		Result:  json.RawMessage(`{"results":[{"calendarId":"a@example.com","summary":"One","eventId":"evt1"},{"calendarId":"b@example.com","summary":"Two","error":"forbidden"}],"succeeded":1,"failed":1}`),
+	}
+	results, err := batchRequestResults(completed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || !results[0].OK() || results[1].OK() {
+		t.Fatalf("expected one success then one failure, got %+v", results)
+	}
+	if results[0].Status != http.StatusCreated || results[1].Error.Message != "forbidden" {
+		t.Errorf("unexpected item results: %+v", results)
+	}
+
+	failed := &database.Request{
+		Status:  database.StatusFailed,
+		Payload: json.RawMessage(payload),
+		Error:   sql.NullString{String: "all 2 events failed: quota", Valid: true},
+	}
+	if results, err = batchRequestResults(failed); err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range results {
+		if r.OK() || r.Index != i || r.Error.Message != failed.Error.String {
+			t.Errorf("item %d: expected failure with request error, got %+v", i, r)
+		}
+	}
+
+	pending := &database.Request{Status: database.StatusPendingApproval, Payload: json.RawMessage(payload)}
+	if results, _ = batchRequestResults(pending); results != nil {
+		t.Errorf("expected no results for a pending request, got %+v", results)
+	}
+}
+
+func TestGetKeyAuditLogAccess(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_a', 'hash_a', 'sk_a', 'key_a', 'write'), ('key_b', 'hash_b', 'sk_b', 'key_b', 'write')
+	`); err != nil {
+		t.Fatalf("insert api keys: %v", err)
+	}
+	ctx := context.Background()
+	auditLogger := engine.NewAuditLogger(db)
+	auditLogger.Log(ctx, database.AuditRequestCreated, "", "key_a", "api", nil)
+	auditLogger.Log(ctx, database.AuditRequestCreated, "", "key_b", "api", nil)
+
+	h := &Handler{apiKeyRepo: apikeys.NewRepository(db, nil), auditLogger: auditLogger}
+	get := func(callerID, tier, keyID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "http://example.com/api/admin/keys/"+keyID+"/audit", nil)
+		req.SetPathValue("id", keyID)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{ID: callerID, Tier: tier}))
+		rr := httptest.NewRecorder()
+		h.GetKeyAuditLog(rr, req)
+		return rr
+	}
+
+	rr := get("key_a", database.TierWrite, "key_a")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("own trail: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var body struct {
+		Total int `json:"total"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil || body.Total != 1 {
+		t.Errorf("expected 1 entry for key_a, got %d (%v)", body.Total, err)
+	}
+
+	if rr := get("key_a", database.TierWrite, "key_b"); rr.Code != http.StatusForbidden {
+		t.Errorf("other key's trail: expected 403, got %d", rr.Code)
+	}
+	if rr := get("admin", database.TierAdmin, "key_b"); rr.Code != http.StatusOK {
+		t.Errorf("admin: expected 200, got %d", rr.Code)
+	}
+	if rr := get("admin", database.TierAdmin, "key_missing"); rr.Code != http.StatusNotFound {
+		t.Errorf("missing key: expected 404, got %d", rr.Code)
+	}
+}
+
+func TestUpdateKeyConstraints(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier, constraints)
+		VALUES ('key_a', 'hash_a', 'sk_a', 'key_a', 'write', '{"calendar_allowlist":["primary"],"max_attendees":10}')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	auditLogger := engine.NewAuditLogger(db)
+	repo := apikeys.NewRepository(db, nil)
+	h := &Handler{apiKeyRepo: repo, auditLogger: auditLogger}
+
+	patch := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PATCH", "http://example.com/api/admin/keys/key_a/constraints", strings.NewReader(body))
+		req.SetPathValue("id", "key_a")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAPIKey, &apikeys.AuthenticatedKey{ID: "admin", Tier: database.TierAdmin}))
+		rr := httptest.NewRecorder()
+		h.UpdateKeyConstraints(rr, req)
+		return rr
+	}
+
+	if rr := patch(`{"max_attendees": 3, "max_duration_minutes": 60}`); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	key, _ := repo.GetByID(context.Background(), "key_a")
+	if key.Constraints.MaxAttendees != 3 || key.Constraints.MaxDurationMinutes != 60 || len(key.Constraints.CalendarAllowlist) != 1 {
+		t.Errorf("unexpected constraints after patch: %+v", key.Constraints)
+	}
+
+	entries, total, _ := auditLogger.Query(context.Background(), engine.AuditQuery{APIKeyID: "key_a", EventType: database.AuditAPIKeyUpdated})
+	if total != 1 || !strings.Contains(string(entries[0].Details), `"field":"max_attendees"`) {
+		t.Errorf("expected one audit entry listing the change, got %d", total)
+	}
+
+	if rr := patch(`{"default_calendar": "other@example.com"}`); rr.Code != http.StatusBadRequest {
+		t.Errorf("default outside allowlist: expected 400, got %d", rr.Code)
+	}
+	if rr := patch(`{"max_atendees": 1}`); rr.Code != http.StatusBadRequest {
+		t.Errorf("unknown field: expected 400, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/response"
+	"github.com/dtorcivia/schedlock/internal/workers"
+)
+
+// RetentionPreviewer reports what the retention cleanup would delete.
+type RetentionPreviewer interface {
+	Preview(ctx context.Context, includeIDs bool) (*workers.RetentionPreview, error)
+}
+
+// SetRetentionPreviewer sets the worker used by PreviewRetention.
+func (h *Handler) SetRetentionPreviewer(p RetentionPreviewer) {
+	h.retentionPreviewer = p
+}
+
+// PreviewRetention reports how many requests, audit entries, idempotency
+// keys and webhook failures the retention cleanup would delete under the
+// current settings, without deleting anything. With ids=true the IDs of
+// those rows are listed too, oldest first.
+func (h *Handler) PreviewRetention(w http.ResponseWriter, r *http.Request) {
+	if requireScope(w, r, apikeys.ScopeAdmin) == nil {
+		return
+	}
+	if h.retentionPreviewer == nil {
+		response.Error(w, http.StatusServiceUnavailable, "retention worker unavailable", nil)
+		return
+	}
+
+	includeIDs := false
+	if raw := r.URL.Query().Get("ids"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "ids must be true or false", nil)
+			return
+		}
+		includeIDs = v
+	}
+
+	preview, err := h.retentionPreviewer.Preview(r.Context(), includeIDs)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to preview retention", err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, preview)
+}
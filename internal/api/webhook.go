@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/response"
+	"github.com/dtorcivia/schedlock/internal/webhook"
+)
+
+// WebhookPinger sends a test event to the Moltbot webhook.
+type WebhookPinger interface {
+	Ping(ctx context.Context) (*webhook.PingResult, error)
+}
+
+// SetWebhookPinger sets the client used by TestWebhook.
+func (h *Handler) SetWebhookPinger(p WebhookPinger) {
+	h.webhookPinger = p
+}
+
+// TestWebhook sends a synthetic "test" event to the Moltbot webhook and
+// reports the HTTP status and latency.
+func (h *Handler) TestWebhook(w http.ResponseWriter, r *http.Request) {
+	if requireScope(w, r, apikeys.ScopeAdmin) == nil {
+		return
+	}
+
+	if h.webhookPinger == nil {
+		response.Error(w, http.StatusServiceUnavailable, "webhook client unavailable", nil)
+		return
+	}
+
+	result, err := h.webhookPinger.Ping(r.Context())
+	if errors.Is(err, webhook.ErrNotConfigured) {
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to send test webhook", err)
+		return
+	}
+
+	status := http.StatusOK
+	if !result.Success {
+		status = http.StatusBadGateway
+	}
+	response.JSON(w, status, result)
+}
+
+// WebhookFailureStore lists and redelivers failed Moltbot webhooks.
+type WebhookFailureStore interface {
+	ListFailures(ctx context.Context, opts webhook.FailureListOptions) ([]webhook.Failure, int, error)
+	RetryFailure(ctx context.Context, id int64) (*webhook.Failure, error)
+}
+
+// SetWebhookFailures sets the store used by the webhook failure endpoints.
+func (h *Handler) SetWebhookFailures(s WebhookFailureStore) {
+	h.webhookFailures = s
+}
+
+// ListWebhookFailures lists failed webhook deliveries, newest first.
+// Query parameters: state (unresolved, resolved or all; default unresolved),
+// request_id, limit (1-200, default 50) and offset.
+func (h *Handler) ListWebhookFailures(w http.ResponseWriter, r *http.Request) {
+	if requireScope(w, r, apikeys.ScopeAdmin) == nil {
+		return
+	}
+	if h.webhookFailures == nil {
+		response.Error(w, http.StatusServiceUnavailable, "webhook client unavailable", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	opts := webhook.FailureListOptions{
+		State:     query.Get("state"),
+		RequestID: query.Get("request_id"),
+		Limit:     50,
+	}
+	switch opts.State {
+	case "":
+		opts.State = webhook.FailuresUnresolved
+	case webhook.FailuresUnresolved, webhook.FailuresResolved, webhook.FailuresAll:
+	default:
+		response.Error(w, http.StatusBadRequest, "state must be unresolved, resolved or all", nil)
+		return
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n <= 0 || n > 200 {
+			response.Error(w, http.StatusBadRequest, "limit must be between 1 and 200", nil)
+			return
+		}
+		opts.Limit = n
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		n, err := strconv.Atoi(offsetStr)
+		if err != nil || n < 0 {
+			response.Error(w, http.StatusBadRequest, "invalid offset", nil)
+			return
+		}
+		opts.Offset = n
+	}
+
+	failures, total, err := h.webhookFailures.ListFailures(r.Context(), opts)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to list webhook failures", err)
+		return
+	}
+
+	response.Paginated(w, r, failures, response.OffsetPage(len(failures), total, opts.Limit, opts.Offset), map[string]interface{}{
+		"failures": failures,
+		"total":    total,
+		"limit":    opts.Limit,
+		"offset":   opts.Offset,
+	})
+}
+
+// RetryWebhookFailure redelivers one failed webhook immediately. It
+// returns 200 with the resolved failure, or 502 with the updated failure
+// if the receiver still rejects it.
+func (h *Handler) RetryWebhookFailure(w http.ResponseWriter, r *http.Request) {
+	authKey := requireScope(w, r, apikeys.ScopeAdmin)
+	if authKey == nil {
+		return
+	}
+	if h.webhookFailures == nil {
+		response.Error(w, http.StatusServiceUnavailable, "webhook client unavailable", nil)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid failure id", nil)
+		return
+	}
+
+	ctx := r.Context()
+	failure, err := h.webhookFailures.RetryFailure(ctx, id)
+	switch {
+	case errors.Is(err, webhook.ErrFailureNotFound):
+		response.Error(w, http.StatusNotFound, err.Error(), nil)
+		return
+	case errors.Is(err, webhook.ErrFailureResolved):
+		response.Error(w, http.StatusConflict, err.Error(), nil)
+		return
+	case errors.Is(err, webhook.ErrNotConfigured):
+		response.Error(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	case failure == nil:
+		response.Error(w, http.StatusInternalServerError, "failed to retry webhook", err)
+		return
+	}
+
+	h.auditLogger.Log(ctx, database.AuditWebhookRetried, failure.RequestID, authKey.ID, "api:"+authKey.ID, map[string]interface{}{
+		"failure_id": failure.ID,
+		"success":    err == nil,
+	})
+
+	status := http.StatusOK
+	body := map[string]interface{}{"success": err == nil, "failure": failure}
+	if err != nil {
+		status = http.StatusBadGateway
+		body["error"] = err.Error()
+	}
+	response.JSON(w, status, body)
+}
@@ -2,11 +2,17 @@
 package apikeys
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"net/mail"
+	"net/netip"
+	"path"
 	"strings"
 	"time"
 
 	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/util"
 )
 
 // ConstraintResult represents the result of constraint evaluation.
@@ -31,31 +37,87 @@ func (v ConstraintViolation) Error() string {
 	return v.Message
 }
 
-// EvaluateConstraints checks if an operation is allowed based on key constraints.
-// Returns the result and any violations.
+// ConstraintDecision is the outcome of constraint evaluation together with
+// the constraint that decided it, so approvers and logs can tell why a
+// request was allowed, held for approval or denied.
+type ConstraintDecision struct {
+	Result     ConstraintResult
+	Constraint string // deciding constraint, e.g. "max_attendees"; "tier" when the tier default applied
+	Reason     string
+}
+
+// RequiresApproval reports whether the decision holds the request for approval.
+func (d ConstraintDecision) RequiresApproval() bool {
+	return d.Result == ConstraintRequireApproval
+}
+
+// Violation returns the decision as an error if it denies the operation.
+func (d ConstraintDecision) Violation() *ConstraintViolation {
+	if d.Result != ConstraintDeny {
+		return nil
+	}
+	return &ConstraintViolation{Constraint: d.Constraint, Message: d.Reason}
+}
+
+// String returns the result as used in audit details and logs.
+func (r ConstraintResult) String() string {
+	switch r {
+	case ConstraintAllow:
+		return "allow"
+	case ConstraintRequireApproval:
+		return "require_approval"
+	case ConstraintDeny:
+		return "deny"
+	}
+	return "unknown"
+}
+
+func deny(constraint, reason string) ConstraintDecision {
+	return ConstraintDecision{Result: ConstraintDeny, Constraint: constraint, Reason: reason}
+}
+
+func requireApproval(constraint, reason string) ConstraintDecision {
+	return ConstraintDecision{Result: ConstraintRequireApproval, Constraint: constraint, Reason: reason}
+}
+
+// EvaluateConstraints checks if an operation is allowed based on key
+// constraints and reports which constraint decided the outcome. Every deny
+// check runs before an approval is returned, so a constraint that only asks
+// for approval cannot hide a denial from a later one; the first approval
+// found is reported.
 func EvaluateConstraints(
 	authKey *AuthenticatedKey,
 	operation string,
 	calendarID string,
 	attendees []string,
 	start, end time.Time,
-) (ConstraintResult, *ConstraintViolation) {
+) ConstraintDecision {
+	// Scopes gate the operation before any other constraint
+	if scope := OperationScope(operation); scope != "" && !authKey.HasScope(scope) {
+		return deny("scope", fmt.Sprintf("This API key lacks the %s scope", scope))
+	}
+
 	// If no constraints, use tier defaults
 	if authKey.Constraints == nil {
-		return getTierDefault(authKey.Tier, operation), nil
+		return tierDecision(authKey.Tier, operation)
 	}
 
 	constraints := authKey.Constraints
 
+	// The first constraint asking for approval; denials still take precedence
+	var approval *ConstraintDecision
+	holdForApproval := func(decision ConstraintDecision) {
+		if approval == nil {
+			approval = &decision
+		}
+	}
+
 	// Check operation override
 	if constraints.Operations != nil {
 		if action, ok := constraints.Operations[operation]; ok {
 			switch action {
 			case "deny":
-				return ConstraintDeny, &ConstraintViolation{
-					Constraint: "operation",
-					Message:    fmt.Sprintf("Operation %s is not allowed for this API key", operation),
-				}
+				return deny("operation", fmt.Sprintf("Operation %s is not allowed for this API key", operation))
 			case "allow", "auto":
 				// Will still check other constraints
 			case "require_approval":
@@ -66,18 +128,8 @@ func EvaluateConstraints(
 
 	// Check calendar allowlist
 	if len(constraints.CalendarAllowlist) > 0 {
-		allowed := false
-		for _, allowedCal := range constraints.CalendarAllowlist {
-			if allowedCal == calendarID {
-				allowed = true
-				break
-			}
-		}
-		if !allowed {
-			return ConstraintDeny, &ConstraintViolation{
-				Constraint: "calendar_allowlist",
-				Message:    fmt.Sprintf("Calendar %s is not in the allowed list", calendarID),
-			}
+		if !CalendarAllowed(calendarID, constraints.CalendarAllowlist) {
+			return deny("calendar_allowlist", fmt.Sprintf("Calendar %s is not in the allowed list", calendarID))
 		}
 	}
 
@@ -86,35 +138,45 @@ func EvaluateConstraints(
 		duration := end.Sub(start)
 		maxDuration := time.Duration(constraints.MaxDurationMinutes) * time.Minute
 		if duration > maxDuration {
-			return ConstraintDeny, &ConstraintViolation{
-				Constraint: "max_duration",
-				Message:    fmt.Sprintf("Event duration (%v) exceeds maximum allowed (%d minutes)", duration, constraints.MaxDurationMinutes),
-			}
+			return deny("max_duration", fmt.Sprintf("Event duration (%v) exceeds maximum allowed (%d minutes)", duration, constraints.MaxDurationMinutes))
 		}
 	}
 
 	// Check max attendees
 	if constraints.MaxAttendees > 0 && len(attendees) > constraints.MaxAttendees {
-		return ConstraintDeny, &ConstraintViolation{
-			Constraint: "max_attendees",
-			Message:    fmt.Sprintf("Number of attendees (%d) exceeds maximum allowed (%d)", len(attendees), constraints.MaxAttendees),
+		return deny("max_attendees", fmt.Sprintf("Number of attendees (%d) exceeds maximum allowed (%d)", len(attendees), constraints.MaxAttendees))
+	}
+
+	// Check blocked attendee domains. Malformed addresses fail closed.
+	if len(constraints.AttendeeDomainBlocklist) > 0 {
+		var blocked []string
+		for _, attendee := range attendees {
+			domain, ok := emailDomain(attendee)
+			if !ok || containsFold(constraints.AttendeeDomainBlocklist, domain) {
+				blocked = append(blocked, attendee)
+			}
+		}
+		if len(blocked) > 0 {
+			return deny("attendee_domain_blocklist", fmt.Sprintf("%s in a blocked domain: %s", attendeesNoun(blocked), strings.Join(blocked, ", ")))
 		}
 	}
 
 	// Check attendee domains
 	if len(constraints.AttendeeDomainAllowlist) > 0 {
+		var external []string
 		for _, attendee := range attendees {
 			if !isEmailInDomainList(attendee, constraints.AttendeeDomainAllowlist) {
-				if constraints.AllowExternalAttendees != nil && !*constraints.AllowExternalAttendees {
-					return ConstraintDeny, &ConstraintViolation{
-						Constraint: "attendee_domain",
-						Message:    fmt.Sprintf("Attendee %s is not in an allowed domain", attendee),
-					}
-				}
-				// External attendee, require approval
-				return ConstraintRequireApproval, nil
+				external = append(external, attendee)
 			}
 		}
+		if len(external) > 0 {
+			message := fmt.Sprintf("%s not in an allowed domain: %s", attendeesNoun(external), strings.Join(external, ", "))
+			if constraints.AllowExternalAttendees != nil && !*constraints.AllowExternalAttendees {
+				return deny("attendee_domain", message)
+			}
+			// External attendee, require approval
+			holdForApproval(requireApproval("attendee_domain", message))
+		}
 	}
 
 	// Check all-day events
@@ -122,22 +184,519 @@ func EvaluateConstraints(
 		// All-day events typically have no time component or span full days
 		// For simplicity, check if duration is >= 24 hours
 		if end.Sub(start) >= 24*time.Hour {
-			return ConstraintDeny, &ConstraintViolation{
-				Constraint: "all_day_events",
-				Message:    "All-day events are not allowed for this API key",
+			return deny("all_day_events", "All-day events are not allowed for this API key")
+		}
+	}
+
+	// Check business hours (only meaningful for operations that set event times)
+	if constraints.BusinessHours != nil && (operation == database.OperationCreateEvent || operation == database.OperationUpdateEvent) {
+		within, err := withinBusinessHours(constraints.BusinessHours, start, end)
+		if err != nil {
+			// Fail closed on a malformed window
+			return deny("business_hours", fmt.Sprintf("Invalid business hours constraint: %v", err))
+		}
+		if !within {
+			message := "Event falls outside the business hours allowed for this API key"
+			if constraints.BusinessHours.Mode != "require_approval" {
+				return deny("business_hours", message)
+			}
+			holdForApproval(requireApproval("business_hours", message))
+		}
+	}
+
+	// Check minimum lead time (only meaningful for operations that set event times)
+	if constraints.MinLeadTimeMinutes > 0 && (operation == database.OperationCreateEvent || operation == database.OperationUpdateEvent) {
+		if decision := leadTimeDecision(constraints, start, time.Now()); decision != nil {
+			if decision.Result == ConstraintDeny {
+				return *decision
+			}
+			holdForApproval(*decision)
+		}
+	}
+
+	// Check composite rules
+	if len(constraints.Rules) > 0 {
+		if decision := rulesDecision(constraints.Rules, newRuleFacts(operation, calendarID, attendees, start, end)); decision != nil {
+			if decision.Result == ConstraintDeny {
+				return *decision
 			}
+			holdForApproval(*decision)
 		}
 	}
 
+	if approval != nil {
+		return *approval
+	}
+
 	// Check operation-specific setting
 	if constraints.Operations != nil {
 		if action, ok := constraints.Operations[operation]; ok && action == "require_approval" {
-			return ConstraintRequireApproval, nil
+			return requireApproval("operation", fmt.Sprintf("Operation %s always requires approval for this API key", operation))
 		}
 	}
 
 	// Use tier default
-	return getTierDefault(authKey.Tier, operation), nil
+	return tierDecision(authKey.Tier, operation)
+}
+
+// leadTimeDecision returns the decision for an event starting sooner than
+// the key's minimum lead time, or nil when it starts far enough ahead. The
+// comparison is between instants, so the event's timezone does not matter.
+func leadTimeDecision(constraints *database.KeyConstraints, start, now time.Time) *ConstraintDecision {
+	lead := time.Duration(constraints.MinLeadTimeMinutes) * time.Minute
+	if !start.Before(now.Add(lead)) {
+		return nil
+	}
+	message := fmt.Sprintf("Event starts less than %d minutes from now; this API key requires at least that much notice", constraints.MinLeadTimeMinutes)
+	decision := deny("min_lead_time", message)
+	if constraints.MinLeadTimeMode == "require_approval" {
+		decision = requireApproval("min_lead_time", message)
+	}
+	return &decision
+}
+
+// tierDecision explains the tier default for an operation.
+func tierDecision(tier, operation string) ConstraintDecision {
+	result := getTierDefault(tier, operation)
+	decision := ConstraintDecision{Result: result, Constraint: "tier"}
+	switch result {
+	case ConstraintDeny:
+		decision.Reason = fmt.Sprintf("%s-tier keys cannot perform %s", tier, operation)
+	case ConstraintRequireApproval:
+		decision.Reason = fmt.Sprintf("%s-tier keys need approval for %s", tier, operation)
+	default:
+		decision.Reason = fmt.Sprintf("%s-tier keys may perform %s without approval", tier, operation)
+	}
+	return decision
+}
+
+// EvaluateEventProperties checks visibility and transparency against the key's
+// allowlists. A nil value is not being set and is skipped; an empty value is
+// treated as Google's default ("default" / "opaque") so omitting a field on
+// create cannot bypass the allowlist.
+func EvaluateEventProperties(authKey *AuthenticatedKey, visibility, transparency *string) *ConstraintViolation {
+	if authKey.Constraints == nil {
+		return nil
+	}
+	constraints := authKey.Constraints
+
+	if visibility != nil && len(constraints.AllowedVisibilities) > 0 {
+		value := *visibility
+		if value == "" {
+			value = "default"
+		}
+		if !containsFold(constraints.AllowedVisibilities, value) {
+			return &ConstraintViolation{
+				Constraint: "allowed_visibilities",
+				Message:    fmt.Sprintf("Visibility %s is not allowed for this API key", value),
+			}
+		}
+	}
+
+	if transparency != nil && len(constraints.AllowedTransparencies) > 0 {
+		value := *transparency
+		if value == "" {
+			value = "opaque"
+		}
+		if !containsFold(constraints.AllowedTransparencies, value) {
+			return &ConstraintViolation{
+				Constraint: "allowed_transparencies",
+				Message:    fmt.Sprintf("Transparency %s is not allowed for this API key", value),
+			}
+		}
+	}
+
+	return nil
+}
+
+// EvaluateRooms checks the rooms a create books against the key's
+// allowed_rooms list. Room addresses match ignoring case.
+func EvaluateRooms(authKey *AuthenticatedKey, rooms []string) *ConstraintViolation {
+	if authKey.Constraints == nil || len(authKey.Constraints.AllowedRooms) == 0 {
+		return nil
+	}
+
+	var denied []string
+	for _, room := range rooms {
+		if !containsFold(authKey.Constraints.AllowedRooms, strings.TrimSpace(room)) {
+			denied = append(denied, room)
+		}
+	}
+	if len(denied) > 0 {
+		return &ConstraintViolation{
+			Constraint: "allowed_rooms",
+			Message:    fmt.Sprintf("Rooms not allowed for this API key: %s", strings.Join(denied, ", ")),
+		}
+	}
+	return nil
+}
+
+// GuestInvitesDisabled reports whether the key forces guestsCanInviteOthers
+// to false on every event it creates or updates.
+func GuestInvitesDisabled(authKey *AuthenticatedKey) bool {
+	return authKey.Constraints != nil && authKey.Constraints.DisableGuestInvites
+}
+
+// HTMLDescriptionAllowed reports whether the key may send event descriptions
+// with formatting HTML. Other keys have all tags stripped.
+func HTMLDescriptionAllowed(authKey *AuthenticatedKey) bool {
+	return authKey.Constraints != nil && authKey.Constraints.AllowHTMLDescription
+}
+
+// ForcedSendUpdates returns the sendUpdates value the key forces on every
+// create, update and delete, or "" if requests choose their own.
+func ForcedSendUpdates(authKey *AuthenticatedKey) string {
+	if authKey.Constraints == nil {
+		return ""
+	}
+	return authKey.Constraints.ForceSendUpdates
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// withinBusinessHours reports whether [start, end] fits inside a single
+// occurrence of the window. Window boundaries are computed from wall-clock
+// times on each day, so DST transitions shift them with the local clock.
+// An occurrence belongs to the day it starts on, so an overnight window
+// configured for Friday covers Friday night into Saturday morning.
+func withinBusinessHours(bh *database.BusinessHours, start, end time.Time) (bool, error) {
+	startMin, err := parseClock(bh.Start)
+	if err != nil {
+		return false, err
+	}
+	endMin, err := parseClock(bh.End)
+	if err != nil {
+		return false, err
+	}
+
+	days := map[time.Weekday]bool{}
+	if len(bh.Days) == 0 {
+		for d := time.Monday; d <= time.Friday; d++ {
+			days[d] = true
+		}
+	}
+	for _, name := range bh.Days {
+		day, ok := weekdayNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return false, fmt.Errorf("unknown day %q", name)
+		}
+		days[day] = true
+	}
+
+	loc, err := businessHoursLocation(bh.Timezone)
+	if err != nil {
+		return false, err
+	}
+
+	overnight := endMin <= startMin
+	localStart := start.In(loc)
+
+	// The occurrence containing start began either today or, for overnight
+	// windows, yesterday.
+	for offset := 0; offset >= -1; offset-- {
+		if offset == -1 && !overnight {
+			break
+		}
+		y, m, d := localStart.Date()
+		day := time.Date(y, m, d+offset, 0, 0, 0, 0, loc)
+		if !days[day.Weekday()] {
+			continue
+		}
+
+		windowStart := time.Date(day.Year(), day.Month(), day.Day(), startMin/60, startMin%60, 0, 0, loc)
+		endDay := day.Day()
+		if overnight {
+			endDay++
+		}
+		windowEnd := time.Date(day.Year(), day.Month(), endDay, endMin/60, endMin%60, 0, 0, loc)
+
+		if !start.Before(windowStart) && !end.After(windowEnd) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// businessHoursLocation resolves the window's timezone, defaulting to the display timezone.
+func businessHoursLocation(name string) (*time.Location, error) {
+	if name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q", name)
+		}
+		return loc, nil
+	}
+	if formatter := util.GetDefaultFormatter(); formatter != nil && formatter.Location != nil {
+		return formatter.Location, nil
+	}
+	return time.UTC, nil
+}
+
+// neverAutoApproveFields are update fields that always follow the normal
+// approval path, even if listed in AutoApproveFields.
+var neverAutoApproveFields = map[string]bool{
+	"start":     true,
+	"end":       true,
+	"attendees": true,
+}
+
+// CanAutoApproveFields reports whether an update changing exactly these fields
+// may skip approval. Every changed field must be in the key's
+// AutoApproveFields and none may be a time or attendee field. An update that
+// changes nothing is not auto-approved.
+func CanAutoApproveFields(authKey *AuthenticatedKey, changedFields []string) bool {
+	if authKey.Constraints == nil || len(authKey.Constraints.AutoApproveFields) == 0 || len(changedFields) == 0 {
+		return false
+	}
+
+	for _, field := range changedFields {
+		if neverAutoApproveFields[field] || !containsFold(authKey.Constraints.AutoApproveFields, field) {
+			return false
+		}
+	}
+	return true
+}
+
+// IPAllowed reports whether the key may be used from the given client IP.
+// Keys without an allowed_ips constraint may be used from anywhere; an
+// address that cannot be parsed is never allowed by a list.
+func IPAllowed(authKey *AuthenticatedKey, ip string) bool {
+	if authKey.Constraints == nil || len(authKey.Constraints.AllowedIPs) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, entry := range authKey.Constraints.AllowedIPs {
+		prefix, err := util.ParseIPPrefix(entry)
+		if err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactableFields are the event fields the redact_fields constraint can
+// hide from a key's reads. "attendees" hides attendee emails and names but
+// keeps the list, so the key still sees how many people were invited.
+var RedactableFields = []string{"summary", "description", "location", "attendees", "organizer", "extended_properties"}
+
+// RedactedFields returns the event fields hidden from the key's reads.
+func RedactedFields(authKey *AuthenticatedKey) []string {
+	if authKey == nil || authKey.Constraints == nil {
+		return nil
+	}
+	return authKey.Constraints.RedactFields
+}
+
+// DefaultCalendar returns the calendar a request targets when it omits
+// calendarId: the key's default_calendar constraint, or "primary".
+func DefaultCalendar(authKey *AuthenticatedKey) string {
+	if authKey != nil && authKey.Constraints != nil && authKey.Constraints.DefaultCalendar != "" {
+		return authKey.Constraints.DefaultCalendar
+	}
+	return "primary"
+}
+
+// ValidateConstraints checks constraints for malformed values and settings
+// that contradict each other, so a key is never stored with a default it
+// could not use.
+func ValidateConstraints(constraints *database.KeyConstraints) error {
+	if constraints == nil {
+		return nil
+	}
+	if constraints.MaxPendingRequests < 0 {
+		return fmt.Errorf("max_pending_requests must not be negative")
+	}
+	if err := util.ValidateSendUpdates(constraints.ForceSendUpdates); err != nil {
+		return fmt.Errorf("force_send_updates: %w", err)
+	}
+	if constraints.TimeoutAction != "" && constraints.TimeoutAction != "approve" && constraints.TimeoutAction != "deny" {
+		return fmt.Errorf("timeout_action must be approve or deny")
+	}
+	if constraints.MinLeadTimeMinutes < 0 {
+		return fmt.Errorf("min_lead_time_minutes must not be negative")
+	}
+	if constraints.MinLeadTimeMode != "" && constraints.MinLeadTimeMode != "deny" && constraints.MinLeadTimeMode != "require_approval" {
+		return fmt.Errorf("min_lead_time_mode must be deny or require_approval")
+	}
+	if d := constraints.EventDefaults; d != nil {
+		if len(d.DescriptionSuffix) > util.MaxDescriptionSuffixLength {
+			return fmt.Errorf("event_defaults.description_suffix exceeds %d characters", util.MaxDescriptionSuffixLength)
+		}
+		if err := util.ValidateColorID(d.ColorID); err != nil {
+			return fmt.Errorf("event_defaults.color_id: %w", err)
+		}
+		for _, reminder := range d.Reminders {
+			if err := util.ValidateReminder(reminder.Method, reminder.Minutes); err != nil {
+				return fmt.Errorf("event_defaults.reminders: %w", err)
+			}
+		}
+	}
+	// Calendar IDs never contain glob character classes or escapes
+	for _, pattern := range constraints.CalendarAllowlist {
+		if pattern == "" || strings.ContainsAny(pattern, "[]\\ ") {
+			return fmt.Errorf("invalid calendar_allowlist entry %q: use a calendar ID, \"*\" or a pattern such as *@example.com", pattern)
+		}
+	}
+	for _, domains := range [][]string{constraints.AttendeeDomainAllowlist, constraints.AttendeeDomainBlocklist} {
+		for _, domain := range domains {
+			if domain == "" || strings.ContainsAny(domain, "@ ") {
+				return fmt.Errorf("invalid attendee domain %q: use a bare domain such as example.com", domain)
+			}
+		}
+	}
+	if len(constraints.AllowedRooms) > 0 {
+		if err := util.ValidateEmails(constraints.AllowedRooms); err != nil {
+			return fmt.Errorf("allowed_rooms: %w", err)
+		}
+	}
+	for _, entry := range constraints.AllowedIPs {
+		if _, err := util.ParseIPPrefix(entry); err != nil {
+			return fmt.Errorf("invalid allowed_ips entry %q: use an address such as 203.0.113.7 or a range such as 10.0.0.0/8", entry)
+		}
+	}
+	if err := ValidateRules(constraints.Rules); err != nil {
+		return fmt.Errorf("rules: %w", err)
+	}
+	for _, field := range constraints.RedactFields {
+		if !containsFold(RedactableFields, field) {
+			return fmt.Errorf("invalid redact_fields entry %q: use %s", field, strings.Join(RedactableFields, ", "))
+		}
+	}
+	if constraints.DefaultCalendar == "" {
+		return nil
+	}
+	if err := util.ValidateCalendarID(constraints.DefaultCalendar); err != nil {
+		return fmt.Errorf("default_calendar: %w", err)
+	}
+	if len(constraints.CalendarAllowlist) > 0 {
+		if CalendarAllowed(constraints.DefaultCalendar, constraints.CalendarAllowlist) {
+			return nil
+		}
+		return fmt.Errorf("default_calendar %s is not in the calendar allowlist", constraints.DefaultCalendar)
+	}
+	return nil
+}
+
+// ConstraintsSnapshot returns constraints as a map keyed by their JSON
+// field names, for diffing. Unset constraints are left out.
+func ConstraintsSnapshot(constraints *database.KeyConstraints) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if constraints == nil {
+		return fields
+	}
+	data, err := json.Marshal(constraints)
+	if err != nil {
+		return fields
+	}
+	json.Unmarshal(data, &fields)
+	return fields
+}
+
+// MergeConstraints applies a JSON merge patch to constraints: each field in
+// patch replaces the current value and null removes it. Objects such as
+// business_hours are replaced whole rather than merged. Unknown fields are
+// rejected, so a misspelled constraint cannot silently leave a key looser
+// than intended. The result is not validated.
+func MergeConstraints(current *database.KeyConstraints, patch []byte) (*database.KeyConstraints, error) {
+	var changes map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &changes); err != nil || changes == nil {
+		return nil, fmt.Errorf("constraints must be a JSON object")
+	}
+
+	fields := ConstraintsSnapshot(current)
+	for field, value := range changes {
+		if string(value) == "null" {
+			delete(fields, field)
+			continue
+		}
+		fields[field] = value
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	merged := &database.KeyConstraints{}
+	if err := decoder.Decode(merged); err != nil {
+		return nil, fmt.Errorf("invalid constraints: %w", err)
+	}
+	return merged, nil
+}
+
+// CalendarAllowed reports whether a calendar matches a key's allowlist.
+// Entries are exact IDs, "*" for every calendar, or globs such as
+// "*@example.com". The part after "@" is compared without case, since
+// email domains are case-insensitive.
+func CalendarAllowed(calendarID string, allowlist []string) bool {
+	for _, pattern := range allowlist {
+		if pattern == "*" {
+			return true
+		}
+		if !strings.Contains(pattern, "*") {
+			if foldDomain(pattern) == foldDomain(calendarID) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(foldDomain(pattern), foldDomain(calendarID)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCalendarWildcard reports whether an allowlist contains any pattern
+// rather than only exact calendar IDs.
+func HasCalendarWildcard(allowlist []string) bool {
+	for _, pattern := range allowlist {
+		if strings.Contains(pattern, "*") {
+			return true
+		}
+	}
+	return false
+}
+
+// foldDomain lowercases the part of an address after the last "@".
+func foldDomain(address string) string {
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return address
+	}
+	return address[:at+1] + strings.ToLower(address[at+1:])
+}
+
+// containsFold reports whether list contains value, ignoring case.
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
 }
 
 // getTierDefault returns the default constraint result for a tier and operation.
@@ -145,18 +704,14 @@ func getTierDefault(tier, operation string) ConstraintResult {
 	switch tier {
 	case database.TierRead:
 		// Read tier cannot perform write operations
-		if operation == database.OperationCreateEvent ||
-			operation == database.OperationUpdateEvent ||
-			operation == database.OperationDeleteEvent {
+		if isWriteOperation(operation) {
 			return ConstraintDeny
 		}
 		return ConstraintAllow
 
 	case database.TierWrite:
 		// Write tier requires approval for write operations
-		if operation == database.OperationCreateEvent ||
-			operation == database.OperationUpdateEvent ||
-			operation == database.OperationDeleteEvent {
+		if isWriteOperation(operation) {
 			return ConstraintRequireApproval
 		}
 		return ConstraintAllow
@@ -171,21 +726,50 @@ func getTierDefault(tier, operation string) ConstraintResult {
 	}
 }
 
+// isWriteOperation reports whether the operation modifies calendar data.
+func isWriteOperation(operation string) bool {
+	switch operation {
+	case database.OperationCreateEvent,
+		database.OperationUpdateEvent,
+		database.OperationDeleteEvent,
+		database.OperationMoveEvent,
+		database.OperationCreateEventsBatch:
+		return true
+	default:
+		return false
+	}
+}
+
 // isEmailInDomainList checks if an email's domain is in the allowlist.
 func isEmailInDomainList(email string, domains []string) bool {
-	parts := strings.Split(email, "@")
-	if len(parts) != 2 {
+	domain, ok := emailDomain(email)
+	if !ok {
 		return false
 	}
+	return containsFold(domains, domain)
+}
 
-	emailDomain := strings.ToLower(parts[1])
-	for _, domain := range domains {
-		if strings.ToLower(domain) == emailDomain {
-			return true
-		}
+// emailDomain returns the lowercased domain of an attendee address. It
+// accepts "Name <user@example.com>" and reports false for anything that does
+// not parse as a single address.
+func emailDomain(email string) (string, bool) {
+	addr, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil {
+		return "", false
+	}
+	at := strings.LastIndex(addr.Address, "@")
+	if at <= 0 || at == len(addr.Address)-1 {
+		return "", false
 	}
+	return strings.ToLower(addr.Address[at+1:]), true
+}
 
-	return false
+// attendeesNoun starts a violation message with the right verb agreement.
+func attendeesNoun(attendees []string) string {
+	if len(attendees) == 1 {
+		return "Attendee is"
+	}
+	return "Attendees are"
 }
 
 // CanPerformOperation is a simple check if the tier allows the operation at all.
@@ -193,9 +777,7 @@ func CanPerformOperation(tier, operation string) bool {
 	switch tier {
 	case database.TierRead:
 		// Read tier can only perform read operations
-		return operation != database.OperationCreateEvent &&
-			operation != database.OperationUpdateEvent &&
-			operation != database.OperationDeleteEvent
+		return !isWriteOperation(operation)
 
 	case database.TierWrite, database.TierAdmin:
 		// Write and admin can perform all operations
@@ -215,9 +797,7 @@ func RequiresApproval(tier, operation string) bool {
 
 	case database.TierWrite:
 		// Write tier requires approval for write operations
-		return operation == database.OperationCreateEvent ||
-			operation == database.OperationUpdateEvent ||
-			operation == database.OperationDeleteEvent
+		return isWriteOperation(operation)
 
 	case database.TierAdmin:
 		// Admin tier doesn't require approval
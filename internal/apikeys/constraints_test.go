@@ -0,0 +1,706 @@
+package apikeys
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/database"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("Failed to load location %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestWithinBusinessHours_Weekdays(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	bh := &database.BusinessHours{Start: "09:00", End: "17:00", Timezone: "America/New_York"}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       bool
+	}{
+		{"inside", time.Date(2024, 1, 16, 10, 0, 0, 0, ny), time.Date(2024, 1, 16, 11, 0, 0, 0, ny), true},
+		{"exact window", time.Date(2024, 1, 16, 9, 0, 0, 0, ny), time.Date(2024, 1, 16, 17, 0, 0, 0, ny), true},
+		{"starts early", time.Date(2024, 1, 16, 8, 30, 0, 0, ny), time.Date(2024, 1, 16, 9, 30, 0, 0, ny), false},
+		{"ends late", time.Date(2024, 1, 16, 16, 30, 0, 0, ny), time.Date(2024, 1, 16, 17, 30, 0, 0, ny), false},
+		{"weekend", time.Date(2024, 1, 20, 10, 0, 0, 0, ny), time.Date(2024, 1, 20, 11, 0, 0, 0, ny), false},
+		// 14:00 UTC is 09:00 in New York in winter
+		{"utc input", time.Date(2024, 1, 16, 14, 0, 0, 0, time.UTC), time.Date(2024, 1, 16, 15, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := withinBusinessHours(bh, tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("withinBusinessHours() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("withinBusinessHours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithinBusinessHours_DSTBoundaries(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	bh := &database.BusinessHours{Start: "09:00", End: "17:00", Timezone: "America/New_York"}
+
+	// Monday after spring-forward (2024-03-10): 09:00 EDT is 13:00 UTC
+	got, err := withinBusinessHours(bh,
+		time.Date(2024, 3, 11, 13, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 11, 14, 0, 0, 0, time.UTC))
+	if err != nil || !got {
+		t.Errorf("expected 09:00 EDT to be inside the window, got %v (err %v)", got, err)
+	}
+
+	// 13:30 UTC on the Friday before was 08:30 EST, outside the window
+	got, err = withinBusinessHours(bh,
+		time.Date(2024, 3, 8, 13, 30, 0, 0, time.UTC),
+		time.Date(2024, 3, 8, 14, 30, 0, 0, time.UTC))
+	if err != nil || got {
+		t.Errorf("expected 08:30 EST to be outside the window, got %v (err %v)", got, err)
+	}
+
+	// Monday after fall-back (2024-11-03): 09:00 EST is 14:00 UTC, so 13:30 UTC is too early
+	got, err = withinBusinessHours(bh,
+		time.Date(2024, 11, 4, 13, 30, 0, 0, time.UTC),
+		time.Date(2024, 11, 4, 14, 30, 0, 0, time.UTC))
+	if err != nil || got {
+		t.Errorf("expected 08:30 EST to be outside the window, got %v (err %v)", got, err)
+	}
+
+	// Overnight window spanning the spring-forward gap stays anchored to wall-clock times
+	overnight := &database.BusinessHours{Days: []string{"sat"}, Start: "22:00", End: "06:00", Timezone: "America/New_York"}
+	got, err = withinBusinessHours(overnight,
+		time.Date(2024, 3, 9, 23, 0, 0, 0, ny),
+		time.Date(2024, 3, 10, 5, 30, 0, 0, ny))
+	if err != nil || !got {
+		t.Errorf("expected overnight event across DST change to be inside, got %v (err %v)", got, err)
+	}
+	got, err = withinBusinessHours(overnight,
+		time.Date(2024, 3, 9, 23, 0, 0, 0, ny),
+		time.Date(2024, 3, 10, 6, 30, 0, 0, ny))
+	if err != nil || got {
+		t.Errorf("expected event ending 06:30 EDT to be outside, got %v (err %v)", got, err)
+	}
+}
+
+func TestWithinBusinessHours_Overnight(t *testing.T) {
+	bh := &database.BusinessHours{Days: []string{"fri"}, Start: "22:00", End: "06:00", Timezone: "UTC"}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       bool
+	}{
+		{"friday night", time.Date(2024, 1, 19, 23, 0, 0, 0, time.UTC), time.Date(2024, 1, 20, 2, 0, 0, 0, time.UTC), true},
+		{"early saturday from friday window", time.Date(2024, 1, 20, 1, 0, 0, 0, time.UTC), time.Date(2024, 1, 20, 2, 0, 0, 0, time.UTC), true},
+		{"runs past end", time.Date(2024, 1, 20, 5, 0, 0, 0, time.UTC), time.Date(2024, 1, 20, 7, 0, 0, 0, time.UTC), false},
+		{"saturday night not allowed", time.Date(2024, 1, 20, 23, 0, 0, 0, time.UTC), time.Date(2024, 1, 21, 1, 0, 0, 0, time.UTC), false},
+		{"friday early morning belongs to thursday", time.Date(2024, 1, 19, 1, 0, 0, 0, time.UTC), time.Date(2024, 1, 19, 2, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := withinBusinessHours(bh, tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("withinBusinessHours() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("withinBusinessHours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// evaluate splits a decision into the result and violation the assertions
+// below compare against.
+func evaluate(authKey *AuthenticatedKey, operation, calendarID string, attendees []string, start, end time.Time) (ConstraintResult, *ConstraintViolation) {
+	decision := EvaluateConstraints(authKey, operation, calendarID, attendees, start, end)
+	return decision.Result, decision.Violation()
+}
+
+func TestEvaluateConstraints_BusinessHoursMode(t *testing.T) {
+	start := time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC) // Saturday
+	end := start.Add(time.Hour)
+
+	denyKey := &AuthenticatedKey{Tier: database.TierWrite, Constraints: &database.KeyConstraints{
+		BusinessHours: &database.BusinessHours{Start: "09:00", End: "17:00", Timezone: "UTC"},
+	}}
+	result, violation := evaluate(denyKey, database.OperationCreateEvent, "primary", nil, start, end)
+	if result != ConstraintDeny || violation == nil || violation.Constraint != "business_hours" {
+		t.Errorf("expected business_hours denial, got %v %+v", result, violation)
+	}
+
+	approvalKey := &AuthenticatedKey{Tier: database.TierAdmin, Constraints: &database.KeyConstraints{
+		BusinessHours: &database.BusinessHours{Start: "09:00", End: "17:00", Timezone: "UTC", Mode: "require_approval"},
+	}}
+	result, violation = evaluate(approvalKey, database.OperationCreateEvent, "primary", nil, start, end)
+	if result != ConstraintRequireApproval || violation != nil {
+		t.Errorf("expected approval to be required, got %v %+v", result, violation)
+	}
+
+	// Deletes carry no event times and are not subject to business hours
+	result, _ = evaluate(approvalKey, database.OperationDeleteEvent, "primary", nil, start, start)
+	if result != ConstraintAllow {
+		t.Errorf("expected delete to be allowed for admin, got %v", result)
+	}
+
+	badKey := &AuthenticatedKey{Tier: database.TierWrite, Constraints: &database.KeyConstraints{
+		BusinessHours: &database.BusinessHours{Start: "9am", End: "17:00"},
+	}}
+	result, violation = evaluate(badKey, database.OperationCreateEvent, "primary", nil, start, end)
+	if result != ConstraintDeny || violation == nil {
+		t.Errorf("expected malformed window to fail closed, got %v %+v", result, violation)
+	}
+}
+
+func TestEvaluateConstraints_ExternalAttendeeKeepsBusinessHoursDenial(t *testing.T) {
+	start := time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC) // Saturday
+	end := start.Add(time.Hour)
+	key := &AuthenticatedKey{Tier: database.TierAdmin, Constraints: &database.KeyConstraints{
+		AttendeeDomainAllowlist: []string{"example.com"},
+		BusinessHours:           &database.BusinessHours{Start: "09:00", End: "17:00", Timezone: "UTC"},
+	}}
+
+	// An outside attendee asks for approval, but the out-of-hours start still denies
+	result, violation := evaluate(key, database.OperationCreateEvent, "primary", []string{"bob@other.org"}, start, end)
+	if result != ConstraintDeny || violation == nil || violation.Constraint != "business_hours" {
+		t.Errorf("expected business_hours denial, got %v %+v", result, violation)
+	}
+
+	// Within business hours the external attendee still needs approval
+	monday := time.Date(2024, 1, 22, 10, 0, 0, 0, time.UTC)
+	decision := EvaluateConstraints(key, database.OperationCreateEvent, "primary", []string{"bob@other.org"}, monday, monday.Add(time.Hour))
+	if decision.Result != ConstraintRequireApproval || decision.Constraint != "attendee_domain" {
+		t.Errorf("decision = %s/%s, want require_approval/attendee_domain", decision.Result, decision.Constraint)
+	}
+}
+
+func TestLeadTimeDecision(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	constraints := &database.KeyConstraints{MinLeadTimeMinutes: 60}
+
+	tests := []struct {
+		name  string
+		start time.Time
+		held  bool
+	}{
+		{"exactly at the lead time", now.Add(time.Hour), false},
+		{"just under the lead time", now.Add(time.Hour - time.Second), true},
+		{"over the lead time", now.Add(time.Hour + time.Minute), false},
+		{"same instant in another timezone", now.Add(time.Hour).In(time.FixedZone("UTC-5", -5*3600)), false},
+		{"already started", now.Add(-time.Minute), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := leadTimeDecision(constraints, tt.start, now)
+			if (decision != nil) != tt.held {
+				t.Fatalf("leadTimeDecision = %+v, want held %v", decision, tt.held)
+			}
+			if decision != nil && (decision.Result != ConstraintDeny || decision.Constraint != "min_lead_time") {
+				t.Errorf("decision = %+v, want a min_lead_time denial", decision)
+			}
+		})
+	}
+
+	constraints.MinLeadTimeMode = "require_approval"
+	if decision := leadTimeDecision(constraints, now.Add(time.Minute), now); decision == nil || decision.Result != ConstraintRequireApproval {
+		t.Errorf("decision = %+v, want approval to be required", decision)
+	}
+}
+
+func TestEvaluateConstraints_MinLeadTime(t *testing.T) {
+	key := &AuthenticatedKey{Tier: database.TierWrite, Constraints: &database.KeyConstraints{MinLeadTimeMinutes: 120}}
+	soon := time.Now().Add(30 * time.Minute)
+	result, violation := evaluate(key, database.OperationCreateEvent, "primary", nil, soon, soon.Add(time.Hour))
+	if result != ConstraintDeny || violation == nil || violation.Constraint != "min_lead_time" {
+		t.Errorf("expected min_lead_time denial, got %v %+v", result, violation)
+	}
+
+	later := time.Now().Add(3 * time.Hour)
+	if result, violation := evaluate(key, database.OperationCreateEvent, "primary", nil, later, later.Add(time.Hour)); violation != nil {
+		t.Errorf("expected an event three hours out to pass the lead time, got %v %+v", result, violation)
+	}
+
+	// Deletes carry no event times and are not subject to the lead time
+	if result, _ := evaluate(key, database.OperationDeleteEvent, "primary", nil, soon, soon); result == ConstraintDeny {
+		t.Errorf("expected delete not to be denied by the lead time")
+	}
+
+	for _, c := range []database.KeyConstraints{{MinLeadTimeMinutes: -1}, {MinLeadTimeMinutes: 30, MinLeadTimeMode: "warn"}} {
+		if err := ValidateConstraints(&c); err == nil {
+			t.Errorf("expected %+v to be rejected", c)
+		}
+	}
+}
+
+func TestEvaluateConstraints_ExternalAttendeeKeepsLeadTimeDenial(t *testing.T) {
+	key := &AuthenticatedKey{Tier: database.TierAdmin, Constraints: &database.KeyConstraints{
+		AttendeeDomainAllowlist: []string{"example.com"},
+		MinLeadTimeMinutes:      120,
+	}}
+	soon := time.Now().Add(30 * time.Minute)
+	result, violation := evaluate(key, database.OperationCreateEvent, "primary", []string{"bob@other.org"}, soon, soon.Add(time.Hour))
+	if result != ConstraintDeny || violation == nil || violation.Constraint != "min_lead_time" {
+		t.Errorf("expected min_lead_time denial despite the external attendee, got %v %+v", result, violation)
+	}
+
+	// A lead time that only asks for approval does not hide a deny rule
+	key.Constraints.MinLeadTimeMode = "require_approval"
+	key.Constraints.Rules = []database.ConstraintRule{{Action: "deny", When: database.RuleCondition{
+		Field: RuleFieldAttendees, Op: ">=", Value: json.RawMessage("1"),
+	}}}
+	result, violation = evaluate(key, database.OperationCreateEvent, "primary", []string{"bob@other.org"}, soon, soon.Add(time.Hour))
+	if result != ConstraintDeny || violation == nil || violation.Constraint != "rule" {
+		t.Errorf("expected the deny rule to win, got %v %+v", result, violation)
+	}
+}
+
+func TestEvaluateConstraints_AttendeeDomains(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+	end := start.Add(time.Hour)
+	deny := false
+
+	strictKey := &AuthenticatedKey{Tier: database.TierAdmin, Constraints: &database.KeyConstraints{
+		AttendeeDomainAllowlist: []string{"Example.com"},
+		AllowExternalAttendees:  &deny,
+	}}
+	result, violation := evaluate(strictKey, database.OperationCreateEvent, "primary",
+		[]string{"ana@example.com", "bob@other.org", "Carol <carol@else.net>"}, start, end)
+	if result != ConstraintDeny || violation == nil || violation.Constraint != "attendee_domain" {
+		t.Fatalf("expected attendee_domain denial, got %v %+v", result, violation)
+	}
+	if !strings.Contains(violation.Message, "bob@other.org") || !strings.Contains(violation.Message, "carol@else.net") ||
+		strings.Contains(violation.Message, "ana@example.com") {
+		t.Errorf("violation should list only the external attendees: %s", violation.Message)
+	}
+
+	result, violation = evaluate(strictKey, database.OperationCreateEvent, "primary", []string{"ANA@EXAMPLE.COM"}, start, end)
+	if result != ConstraintAllow || violation != nil {
+		t.Errorf("expected in-domain attendee to be allowed, got %v %+v", result, violation)
+	}
+
+	// Without allow_external_attendees=false, external attendees need approval
+	softKey := &AuthenticatedKey{Tier: database.TierAdmin, Constraints: &database.KeyConstraints{
+		AttendeeDomainAllowlist: []string{"example.com"},
+	}}
+	result, _ = evaluate(softKey, database.OperationCreateEvent, "primary", []string{"bob@other.org"}, start, end)
+	if result != ConstraintRequireApproval {
+		t.Errorf("expected approval for external attendee, got %v", result)
+	}
+
+	blockKey := &AuthenticatedKey{Tier: database.TierAdmin, Constraints: &database.KeyConstraints{
+		AttendeeDomainBlocklist: []string{"competitor.com"},
+	}}
+	for _, attendees := range [][]string{{"eve@competitor.com"}, {"not-an-email"}, {"x@"}} {
+		result, violation = evaluate(blockKey, database.OperationCreateEvent, "primary", attendees, start, end)
+		if result != ConstraintDeny || violation == nil || violation.Constraint != "attendee_domain_blocklist" {
+			t.Errorf("%v: expected blocklist denial, got %v %+v", attendees, result, violation)
+		}
+	}
+	result, violation = evaluate(blockKey, database.OperationCreateEvent, "primary", []string{"ana@example.com"}, start, end)
+	if result != ConstraintAllow || violation != nil {
+		t.Errorf("expected unblocked attendee to be allowed, got %v %+v", result, violation)
+	}
+}
+
+func TestValidateConstraints_AttendeeDomains(t *testing.T) {
+	if err := ValidateConstraints(&database.KeyConstraints{AttendeeDomainBlocklist: []string{"example.com"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, domain := range []string{"", "user@example.com"} {
+		if err := ValidateConstraints(&database.KeyConstraints{AttendeeDomainAllowlist: []string{domain}}); err == nil {
+			t.Errorf("expected %q to be rejected", domain)
+		}
+	}
+}
+
+func TestCanAutoApproveFields(t *testing.T) {
+	key := &AuthenticatedKey{Tier: database.TierWrite, Constraints: &database.KeyConstraints{
+		AutoApproveFields: []string{"description", "colorId", "start"},
+	}}
+
+	tests := []struct {
+		name    string
+		changed []string
+		want    bool
+	}{
+		{"whitelisted", []string{"description", "colorId"}, true},
+		{"case insensitive", []string{"ColorID"}, true},
+		{"not whitelisted", []string{"description", "summary"}, false},
+		{"time never auto-approved", []string{"start"}, false},
+		{"nothing changed", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanAutoApproveFields(key, tt.changed); got != tt.want {
+				t.Errorf("CanAutoApproveFields(%v) = %v, want %v", tt.changed, got, tt.want)
+			}
+		})
+	}
+
+	if CanAutoApproveFields(&AuthenticatedKey{Tier: database.TierWrite}, []string{"description"}) {
+		t.Error("expected key without constraints not to auto-approve")
+	}
+}
+
+func TestValidateConstraints_DefaultCalendar(t *testing.T) {
+	cases := []struct {
+		name        string
+		constraints *database.KeyConstraints
+		wantErr     bool
+	}{
+		{"no constraints", nil, false},
+		{"default without allowlist", &database.KeyConstraints{DefaultCalendar: "work@example.com"}, false},
+		{"default in allowlist", &database.KeyConstraints{DefaultCalendar: "work@example.com", CalendarAllowlist: []string{"primary", "work@example.com"}}, false},
+		{"wildcard allowlist", &database.KeyConstraints{DefaultCalendar: "work@example.com", CalendarAllowlist: []string{"*"}}, false},
+		{"default outside allowlist", &database.KeyConstraints{DefaultCalendar: "home@example.com", CalendarAllowlist: []string{"work@example.com"}}, true},
+		{"invalid calendar ID", &database.KeyConstraints{DefaultCalendar: "not a calendar"}, true},
+	}
+
+	for _, tc := range cases {
+		err := ValidateConstraints(tc.constraints)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestDefaultCalendar(t *testing.T) {
+	if got := DefaultCalendar(&AuthenticatedKey{}); got != "primary" {
+		t.Errorf("unconstrained key: got %q, want primary", got)
+	}
+	key := &AuthenticatedKey{Constraints: &database.KeyConstraints{DefaultCalendar: "work@example.com"}}
+	if got := DefaultCalendar(key); got != "work@example.com" {
+		t.Errorf("constrained key: got %q, want work@example.com", got)
+	}
+}
+
+func TestCalendarAllowed(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowlist []string
+		id        string
+		want      bool
+	}{
+		{"exact", []string{"primary"}, "primary", true},
+		{"exact miss", []string{"primary"}, "team@group.calendar.google.com", false},
+		{"exact domain case", []string{"ana@example.com"}, "ana@EXAMPLE.com", true},
+		{"exact local part case", []string{"ana@example.com"}, "Ana@example.com", false},
+		{"full wildcard", []string{"*"}, "anything@other.com", true},
+		{"domain suffix", []string{"*@example.com"}, "room1@example.com", true},
+		{"domain suffix case", []string{"*@Example.com"}, "room1@EXAMPLE.COM", true},
+		{"domain suffix miss", []string{"*@example.com"}, "room1@example.com.evil.net", false},
+		{"subdomain is not the domain", []string{"*@example.com"}, "room1@sub.example.com", false},
+		{"wildcard subdomain", []string{"*@*.example.com"}, "room1@sub.example.com", true},
+		{"domain suffix does not match primary", []string{"*@example.com"}, "primary", false},
+		{"empty allowlist", nil, "primary", false},
+	}
+	for _, tt := range tests {
+		if got := CalendarAllowed(tt.id, tt.allowlist); got != tt.want {
+			t.Errorf("%s: CalendarAllowed(%q, %v) = %v, want %v", tt.name, tt.id, tt.allowlist, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateConstraints_CalendarWildcard(t *testing.T) {
+	key := &AuthenticatedKey{
+		Tier:        database.TierWrite,
+		Constraints: &database.KeyConstraints{CalendarAllowlist: []string{"*@example.com"}},
+	}
+	start := time.Now().Add(time.Hour)
+
+	if _, err := evaluate(key, database.OperationCreateEvent, "team@Example.com", nil, start, start.Add(time.Hour)); err != nil {
+		t.Errorf("expected calendar in domain to be allowed, got %v", err)
+	}
+	if _, err := evaluate(key, database.OperationCreateEvent, "team@other.com", nil, start, start.Add(time.Hour)); err == nil {
+		t.Error("expected calendar outside domain to be denied")
+	}
+}
+
+func TestValidateConstraints_CalendarAllowlist(t *testing.T) {
+	for _, entry := range []string{"", "[a-z]*@example.com", "a b@example.com"} {
+		if err := ValidateConstraints(&database.KeyConstraints{CalendarAllowlist: []string{entry}}); err == nil {
+			t.Errorf("expected %q to be rejected", entry)
+		}
+	}
+	ok := &database.KeyConstraints{CalendarAllowlist: []string{"*@example.com"}, DefaultCalendar: "team@example.com"}
+	if err := ValidateConstraints(ok); err != nil {
+		t.Errorf("default calendar matching a pattern should be valid: %v", err)
+	}
+}
+
+func TestForcedSendUpdates(t *testing.T) {
+	if got := ForcedSendUpdates(&AuthenticatedKey{}); got != "" {
+		t.Errorf("unconstrained key forced %q", got)
+	}
+	key := &AuthenticatedKey{Constraints: &database.KeyConstraints{ForceSendUpdates: "none"}}
+	if got := ForcedSendUpdates(key); got != "none" {
+		t.Errorf("ForcedSendUpdates() = %q, want none", got)
+	}
+
+	if err := ValidateConstraints(&database.KeyConstraints{ForceSendUpdates: "externalOnly"}); err != nil {
+		t.Errorf("externalOnly should be valid: %v", err)
+	}
+	if err := ValidateConstraints(&database.KeyConstraints{ForceSendUpdates: "everyone"}); err == nil {
+		t.Error("expected unknown force_send_updates value to be rejected")
+	}
+}
+
+func TestValidateConstraints_EventDefaults(t *testing.T) {
+	valid := &database.KeyConstraints{EventDefaults: &database.EventDefaults{
+		DescriptionSuffix: "Booked via SchedLock",
+		ColorID:           "5",
+		Reminders:         []database.DefaultReminder{{Method: "popup", Minutes: 10}},
+	}}
+	if err := ValidateConstraints(valid); err != nil {
+		t.Errorf("valid event defaults rejected: %v", err)
+	}
+
+	for name, d := range map[string]*database.EventDefaults{
+		"color":           {ColorID: "12"},
+		"reminder method": {Reminders: []database.DefaultReminder{{Method: "sms", Minutes: 10}}},
+		"reminder range":  {Reminders: []database.DefaultReminder{{Method: "popup", Minutes: -1}}},
+		"suffix length":   {DescriptionSuffix: strings.Repeat("x", 1025)},
+	} {
+		if err := ValidateConstraints(&database.KeyConstraints{EventDefaults: d}); err == nil {
+			t.Errorf("expected invalid %s to be rejected", name)
+		}
+	}
+}
+
+func TestValidateConstraints_TimeoutAction(t *testing.T) {
+	for _, action := range []string{"", "approve", "deny"} {
+		if err := ValidateConstraints(&database.KeyConstraints{TimeoutAction: action}); err != nil {
+			t.Errorf("timeout_action %q rejected: %v", action, err)
+		}
+	}
+	if err := ValidateConstraints(&database.KeyConstraints{TimeoutAction: "expire"}); err == nil {
+		t.Error("expected unknown timeout_action to be rejected")
+	}
+}
+
+func TestValidateConstraints_RedactFields(t *testing.T) {
+	if err := ValidateConstraints(&database.KeyConstraints{RedactFields: []string{"attendees", "Description"}}); err != nil {
+		t.Errorf("valid redact_fields rejected: %v", err)
+	}
+	if err := ValidateConstraints(&database.KeyConstraints{RedactFields: []string{"start"}}); err == nil {
+		t.Error("expected unknown redact_fields entry to be rejected")
+	}
+}
+
+func TestEvaluateRooms(t *testing.T) {
+	rooms := []string{"Room-A@resource.calendar.google.com"}
+	if v := EvaluateRooms(&AuthenticatedKey{}, rooms); v != nil {
+		t.Errorf("unconstrained key denied rooms: %v", v)
+	}
+
+	key := &AuthenticatedKey{Constraints: &database.KeyConstraints{
+		AllowedRooms: []string{"room-a@resource.calendar.google.com"},
+	}}
+	if v := EvaluateRooms(key, rooms); v != nil {
+		t.Errorf("allowed room denied: %v", v)
+	}
+	v := EvaluateRooms(key, append(rooms, "boardroom@resource.calendar.google.com"))
+	if v == nil || v.Constraint != "allowed_rooms" {
+		t.Fatalf("expected allowed_rooms violation, got %v", v)
+	}
+	if !strings.Contains(v.Message, "boardroom@resource.calendar.google.com") {
+		t.Errorf("message %q does not name the denied room", v.Message)
+	}
+
+	if err := ValidateConstraints(&database.KeyConstraints{AllowedRooms: []string{"not-an-email"}}); err == nil {
+		t.Error("expected malformed allowed_rooms entry to be rejected")
+	}
+}
+
+func TestEvaluateEventProperties(t *testing.T) {
+	str := func(s string) *string { return &s }
+	key := &AuthenticatedKey{Constraints: &database.KeyConstraints{
+		AllowedVisibilities:   []string{"private", "Default"},
+		AllowedTransparencies: []string{"transparent"},
+	}}
+
+	tests := []struct {
+		name                     string
+		visibility, transparency *string
+		want                     string // violated constraint, "" when allowed
+	}{
+		{"allowed values", str("private"), str("transparent"), ""},
+		{"case-insensitive", str("PRIVATE"), str("Transparent"), ""},
+		{"unset visibility is default", str(""), str("transparent"), ""},
+		{"fields not being set", nil, nil, ""},
+		{"rejected visibility", str("public"), nil, "allowed_visibilities"},
+		{"rejected transparency", nil, str("opaque"), "allowed_transparencies"},
+		{"unset transparency is opaque", nil, str(""), "allowed_transparencies"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := EvaluateEventProperties(key, tt.visibility, tt.transparency)
+			switch {
+			case tt.want == "" && v != nil:
+				t.Errorf("unexpected violation: %v", v)
+			case tt.want != "" && (v == nil || v.Constraint != tt.want):
+				t.Errorf("violation = %v, want %s", v, tt.want)
+			}
+		})
+	}
+
+	if v := EvaluateEventProperties(&AuthenticatedKey{}, str("public"), str("opaque")); v != nil {
+		t.Errorf("unconstrained key denied: %v", v)
+	}
+}
+
+func TestEvaluateConstraints_DecidingConstraint(t *testing.T) {
+	start := time.Date(2024, 1, 22, 10, 0, 0, 0, time.UTC) // Monday
+	end := start.Add(time.Hour)
+	no := false
+	weekend := &database.BusinessHours{Days: []string{"sat", "sun"}, Start: "09:00", End: "17:00"}
+	weekendApproval := &database.BusinessHours{Days: []string{"sat", "sun"}, Start: "09:00", End: "17:00", Mode: "require_approval"}
+
+	tests := []struct {
+		name        string
+		tier        string
+		scopes      []string
+		constraints *database.KeyConstraints
+		operation   string
+		calendar    string
+		attendees   []string
+		end         time.Time
+		want        ConstraintResult
+		constraint  string
+	}{
+		{name: "write tier default", tier: database.TierWrite, want: ConstraintRequireApproval, constraint: "tier"},
+		{name: "admin tier default", tier: database.TierAdmin, want: ConstraintAllow, constraint: "tier"},
+		{name: "missing scope", tier: database.TierWrite, scopes: []string{ScopeEventsRead}, want: ConstraintDeny, constraint: "scope"},
+		{name: "operation deny", tier: database.TierAdmin, constraints: &database.KeyConstraints{
+			Operations: map[string]string{database.OperationCreateEvent: "deny"}}, want: ConstraintDeny, constraint: "operation"},
+		{name: "operation require approval", tier: database.TierAdmin, constraints: &database.KeyConstraints{
+			Operations: map[string]string{database.OperationCreateEvent: "require_approval"}}, want: ConstraintRequireApproval, constraint: "operation"},
+		{name: "calendar allowlist", tier: database.TierAdmin, constraints: &database.KeyConstraints{
+			CalendarAllowlist: []string{"team@example.com"}}, want: ConstraintDeny, constraint: "calendar_allowlist"},
+		{name: "max duration", tier: database.TierAdmin, constraints: &database.KeyConstraints{
+			MaxDurationMinutes: 30}, want: ConstraintDeny, constraint: "max_duration"},
+		{name: "max attendees", tier: database.TierAdmin, constraints: &database.KeyConstraints{MaxAttendees: 1},
+			attendees: []string{"a@example.com", "b@example.com"}, want: ConstraintDeny, constraint: "max_attendees"},
+		{name: "attendee blocklist", tier: database.TierAdmin, constraints: &database.KeyConstraints{
+			AttendeeDomainBlocklist: []string{"rival.com"}}, attendees: []string{"x@rival.com"}, want: ConstraintDeny, constraint: "attendee_domain_blocklist"},
+		{name: "external attendee denied", tier: database.TierAdmin, constraints: &database.KeyConstraints{
+			AttendeeDomainAllowlist: []string{"example.com"}, AllowExternalAttendees: &no}, attendees: []string{"x@other.com"}, want: ConstraintDeny, constraint: "attendee_domain"},
+		{name: "external attendee needs approval", tier: database.TierAdmin, constraints: &database.KeyConstraints{
+			AttendeeDomainAllowlist: []string{"example.com"}}, attendees: []string{"x@other.com"}, want: ConstraintRequireApproval, constraint: "attendee_domain"},
+		{name: "all-day events", tier: database.TierAdmin, constraints: &database.KeyConstraints{
+			BlockAllDayEvents: true}, end: start.Add(24 * time.Hour), want: ConstraintDeny, constraint: "all_day_events"},
+		{name: "business hours deny", tier: database.TierAdmin, constraints: &database.KeyConstraints{
+			BusinessHours: weekend}, want: ConstraintDeny, constraint: "business_hours"},
+		{name: "business hours approval", tier: database.TierAdmin, constraints: &database.KeyConstraints{
+			BusinessHours: weekendApproval}, want: ConstraintRequireApproval, constraint: "business_hours"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := &AuthenticatedKey{Tier: tt.tier, Scopes: tt.scopes, Constraints: tt.constraints}
+			operation, calendar, eventEnd := tt.operation, tt.calendar, tt.end
+			if operation == "" {
+				operation = database.OperationCreateEvent
+			}
+			if calendar == "" {
+				calendar = "primary"
+			}
+			if eventEnd.IsZero() {
+				eventEnd = end
+			}
+
+			decision := EvaluateConstraints(key, operation, calendar, tt.attendees, start, eventEnd)
+			if decision.Result != tt.want || decision.Constraint != tt.constraint {
+				t.Fatalf("decision = %s/%s (%s), want %s/%s", decision.Result, decision.Constraint, decision.Reason, tt.want, tt.constraint)
+			}
+			if decision.Reason == "" {
+				t.Error("decision has no reason")
+			}
+			if (decision.Violation() != nil) != (tt.want == ConstraintDeny) {
+				t.Errorf("Violation() = %v for result %s", decision.Violation(), decision.Result)
+			}
+		})
+	}
+}
+
+func TestIPAllowed(t *testing.T) {
+	key := &AuthenticatedKey{Constraints: &database.KeyConstraints{
+		AllowedIPs: []string{"203.0.113.7", "10.0.0.0/8", "2001:db8::/32"},
+	}}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"203.0.113.7", true},
+		{"203.0.113.8", false},
+		{"10.20.30.40", true},
+		{"::ffff:10.1.1.1", true},
+		{"2001:db8::1", true},
+		{"2001:db9::1", false},
+		{"not-an-ip", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IPAllowed(key, tt.ip); got != tt.want {
+			t.Errorf("IPAllowed(%q) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+
+	if !IPAllowed(&AuthenticatedKey{}, "198.51.100.1") {
+		t.Error("expected key without constraints to be allowed from any address")
+	}
+}
+
+func TestValidateConstraints_AllowedIPs(t *testing.T) {
+	if err := ValidateConstraints(&database.KeyConstraints{AllowedIPs: []string{"192.0.2.1", "192.0.2.0/24", "::1"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, entry := range []string{"", "192.0.2.0/33", "example.com", "192.0.2.*"} {
+		if err := ValidateConstraints(&database.KeyConstraints{AllowedIPs: []string{entry}}); err == nil {
+			t.Errorf("expected %q to be rejected", entry)
+		}
+	}
+}
+
+func TestMergeConstraints(t *testing.T) {
+	allow := false
+	current := &database.KeyConstraints{
+		CalendarAllowlist:      []string{"primary"},
+		MaxAttendees:           10,
+		AllowExternalAttendees: &allow,
+		BusinessHours:          &database.BusinessHours{Start: "09:00", End: "17:00"},
+	}
+
+	merged, err := MergeConstraints(current, []byte(`{"max_attendees": 5, "allow_external_attendees": null, "business_hours": {"start": "08:00", "end": "12:00"}}`))
+	if err != nil {
+		t.Fatalf("MergeConstraints() error = %v", err)
+	}
+	if merged.MaxAttendees != 5 || merged.AllowExternalAttendees != nil {
+		t.Errorf("expected patched fields, got %+v", merged)
+	}
+	if len(merged.CalendarAllowlist) != 1 || merged.CalendarAllowlist[0] != "primary" {
+		t.Errorf("expected untouched allowlist to be kept, got %v", merged.CalendarAllowlist)
+	}
+	if merged.BusinessHours.Start != "08:00" || current.BusinessHours.Start != "09:00" {
+		t.Errorf("expected business hours replaced without changing the original")
+	}
+
+	for _, patch := range []string{`{"max_atendees": 5}`, `[]`, `null`, `{"max_attendees": "five"}`} {
+		if _, err := MergeConstraints(current, []byte(patch)); err == nil {
+			t.Errorf("expected %s to be rejected", patch)
+		}
+	}
+}
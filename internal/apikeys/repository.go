@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/dtorcivia/schedlock/internal/crypto"
@@ -32,12 +33,33 @@ type AuthenticatedKey struct {
 	KeyPrefix   string
 	Name        string
 	Tier        string
+	Scopes      []string
 	Constraints *database.KeyConstraints
 }
 
-// Create generates and stores a new API key.
+// Create generates and stores a new API key with its tier's default scopes.
 // Returns the full key (show once to user) and the stored record.
 func (r *Repository) Create(ctx context.Context, name, tier string, constraints *database.KeyConstraints) (*database.APIKey, string, error) {
+	return r.CreateWithScopes(ctx, name, tier, nil, constraints)
+}
+
+// CreateWithScopes generates and stores a new API key limited to the given
+// scopes. Nil scopes means the tier's default set.
+func (r *Repository) CreateWithScopes(ctx context.Context, name, tier string, scopes []string, constraints *database.KeyConstraints) (*database.APIKey, string, error) {
+	if scopes == nil {
+		scopes = TierScopes(tier)
+	}
+	if err := ValidateScopes(tier, scopes); err != nil {
+		return nil, "", err
+	}
+	if err := ValidateConstraints(constraints); err != nil {
+		return nil, "", err
+	}
+	scopesJSON, err := json.Marshal(scopes)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to serialize scopes: %w", err)
+	}
+
 	// Generate new API key
 	fullKey, err := r.hasher.GenerateAPIKey(tier)
 	if err != nil {
@@ -65,9 +87,9 @@ func (r *Repository) Create(ctx context.Context, name, tier string, constraints
 
 	// Insert into database
 	_, err = r.db.ExecContext(ctx, `
-		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier, constraints, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, datetime('now'))
-	`, keyID, keyHash, keyPrefix, name, tier, constraintsJSON)
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier, scopes, constraints, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, datetime('now'))
+	`, keyID, keyHash, keyPrefix, name, tier, string(scopesJSON), constraintsJSON)
 
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to insert API key: %w", err)
@@ -80,6 +102,7 @@ func (r *Repository) Create(ctx context.Context, name, tier string, constraints
 		KeyPrefix:   keyPrefix,
 		Name:        name,
 		Tier:        tier,
+		Scopes:      scopes,
 		Constraints: constraints,
 		CreatedAt:   time.Now(),
 	}
@@ -104,16 +127,17 @@ func (r *Repository) Authenticate(ctx context.Context, key string) (*Authenticat
 		keyPrefix       string
 		name            string
 		storedTier      string
+		scopesJSON      sql.NullString
 		constraintsJSON sql.NullString
 		expiresAt       sql.NullTime
 		revokedAt       sql.NullTime
 	)
 
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, key_prefix, name, tier, constraints, expires_at, revoked_at
+		SELECT id, key_prefix, name, tier, scopes, constraints, expires_at, revoked_at
 		FROM api_keys
 		WHERE key_hash = ?
-	`, keyHash).Scan(&id, &keyPrefix, &name, &storedTier, &constraintsJSON, &expiresAt, &revokedAt)
+	`, keyHash).Scan(&id, &keyPrefix, &name, &storedTier, &scopesJSON, &constraintsJSON, &expiresAt, &revokedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("API key not found")
@@ -146,6 +170,7 @@ func (r *Repository) Authenticate(ctx context.Context, key string) (*Authenticat
 		KeyPrefix:   keyPrefix,
 		Name:        name,
 		Tier:        storedTier,
+		Scopes:      parseScopes(scopesJSON, storedTier),
 		Constraints: constraints,
 	}, nil
 }
@@ -157,6 +182,7 @@ func (r *Repository) GetByID(ctx context.Context, id string) (*database.APIKey,
 		keyPrefix         string
 		name              string
 		tier              string
+		scopesJSON        sql.NullString
 		constraintsJSON   sql.NullString
 		createdAtStr      sql.NullString
 		lastUsedAtStr     sql.NullString
@@ -166,12 +192,12 @@ func (r *Repository) GetByID(ctx context.Context, id string) (*database.APIKey,
 	)
 
 	err := r.db.QueryRowContext(ctx, `
-		SELECT key_hash, key_prefix, name, tier, constraints, created_at,
+		SELECT key_hash, key_prefix, name, tier, scopes, constraints, created_at,
 		       last_used_at, expires_at, revoked_at, rate_limit_override
 		FROM api_keys
 		WHERE id = ?
 	`, id).Scan(
-		&keyHash, &keyPrefix, &name, &tier, &constraintsJSON,
+		&keyHash, &keyPrefix, &name, &tier, &scopesJSON, &constraintsJSON,
 		&createdAtStr, &lastUsedAtStr, &expiresAtStr, &revokedAtStr, &rateLimitOverride,
 	)
 
@@ -218,6 +244,7 @@ func (r *Repository) GetByID(ctx context.Context, id string) (*database.APIKey,
 		KeyPrefix:         keyPrefix,
 		Name:              name,
 		Tier:              tier,
+		Scopes:            parseScopes(scopesJSON, tier),
 		Constraints:       constraints,
 		CreatedAt:         createdAt,
 		LastUsedAt:        lastUsedAt,
@@ -227,21 +254,55 @@ func (r *Repository) GetByID(ctx context.Context, id string) (*database.APIKey,
 	}, nil
 }
 
-// List returns all API keys (active and revoked).
-func (r *Repository) List(ctx context.Context, includeRevoked bool) ([]database.APIKey, error) {
+// ListOptions filters and paginates API key listings.
+type ListOptions struct {
+	IncludeRevoked bool
+	Tier           string // Optional: exact tier match
+	Search         string // Optional: case-insensitive substring match on name
+	Limit          int    // 0 = no limit
+	Offset         int
+}
+
+// List returns API keys matching the options, newest first, along with the
+// total number of matching keys before pagination.
+func (r *Repository) List(ctx context.Context, opts ListOptions) ([]database.APIKey, int, error) {
+	var conditions []string
+	var args []interface{}
+	if !opts.IncludeRevoked {
+		conditions = append(conditions, "revoked_at IS NULL")
+	}
+	if opts.Tier != "" {
+		conditions = append(conditions, "tier = ?")
+		args = append(args, opts.Tier)
+	}
+	if opts.Search != "" {
+		conditions = append(conditions, `name LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(opts.Search)+"%")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.Reader().QueryRowContext(ctx, "SELECT COUNT(*) FROM api_keys"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("database error: %w", err)
+	}
+
 	query := `
-		SELECT id, key_hash, key_prefix, name, tier, constraints, created_at,
+		SELECT id, key_hash, key_prefix, name, tier, scopes, constraints, created_at,
 		       last_used_at, expires_at, revoked_at, rate_limit_override
 		FROM api_keys
-	`
-	if !includeRevoked {
-		query += " WHERE revoked_at IS NULL"
+	` + where + " ORDER BY created_at DESC, id"
+	if opts.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, opts.Limit, opts.Offset)
 	}
-	query += " ORDER BY created_at DESC"
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.Reader().QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("database error: %w", err)
+		return nil, 0, fmt.Errorf("database error: %w", err)
 	}
 	defer rows.Close()
 
@@ -253,6 +314,7 @@ func (r *Repository) List(ctx context.Context, includeRevoked bool) ([]database.
 			keyPrefix         string
 			name              string
 			tier              string
+			scopesJSON        sql.NullString
 			constraintsJSON   sql.NullString
 			createdAtStr      sql.NullString
 			lastUsedAtStr     sql.NullString
@@ -262,10 +324,10 @@ func (r *Repository) List(ctx context.Context, includeRevoked bool) ([]database.
 		)
 
 		if err := rows.Scan(
-			&id, &keyHash, &keyPrefix, &name, &tier, &constraintsJSON,
+			&id, &keyHash, &keyPrefix, &name, &tier, &scopesJSON, &constraintsJSON,
 			&createdAtStr, &lastUsedAtStr, &expiresAtStr, &revokedAtStr, &rateLimitOverride,
 		); err != nil {
-			return nil, fmt.Errorf("scan error: %w", err)
+			return nil, 0, fmt.Errorf("scan error: %w", err)
 		}
 
 		var constraints *database.KeyConstraints
@@ -303,6 +365,7 @@ func (r *Repository) List(ctx context.Context, includeRevoked bool) ([]database.
 			KeyPrefix:         keyPrefix,
 			Name:              name,
 			Tier:              tier,
+			Scopes:            parseScopes(scopesJSON, tier),
 			Constraints:       constraints,
 			CreatedAt:         createdAt,
 			LastUsedAt:        lastUsedAt,
@@ -312,7 +375,24 @@ func (r *Repository) List(ctx context.Context, includeRevoked bool) ([]database.
 		})
 	}
 
-	return keys, rows.Err()
+	return keys, total, rows.Err()
+}
+
+// parseScopes decodes a stored scope list, falling back to the tier's
+// scopes when none is stored.
+func parseScopes(raw sql.NullString, tier string) []string {
+	if raw.Valid && raw.String != "" {
+		var scopes []string
+		if err := json.Unmarshal([]byte(raw.String), &scopes); err == nil {
+			return scopes
+		}
+	}
+	return TierScopes(tier)
+}
+
+// escapeLike escapes LIKE wildcards so user input matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 // Revoke marks an API key as revoked.
@@ -345,8 +425,12 @@ func (r *Repository) UpdateLastUsed(ctx context.Context, id string) error {
 	return err
 }
 
-// UpdateConstraints updates the constraints for an API key.
+// UpdateConstraints updates the constraints for an API key. They are
+// validated as on creation.
 func (r *Repository) UpdateConstraints(ctx context.Context, id string, constraints *database.KeyConstraints) error {
+	if err := ValidateConstraints(constraints); err != nil {
+		return err
+	}
 	constraintsJSON, err := json.Marshal(constraints)
 	if err != nil {
 		return fmt.Errorf("failed to serialize constraints: %w", err)
@@ -363,7 +447,7 @@ func (r *Repository) UpdateConstraints(ctx context.Context, id string, constrain
 
 // Count returns the count of API keys by tier.
 func (r *Repository) Count(ctx context.Context) (map[string]int, error) {
-	rows, err := r.db.QueryContext(ctx, `
+	rows, err := r.db.Reader().QueryContext(ctx, `
 		SELECT tier, COUNT(*) as count
 		FROM api_keys
 		WHERE revoked_at IS NULL
@@ -386,3 +470,90 @@ func (r *Repository) Count(ctx context.Context) (map[string]int, error) {
 
 	return counts, rows.Err()
 }
+
+// KeyUsage is one API key's request activity over a window.
+type KeyUsage struct {
+	ID            string
+	Name          string
+	KeyPrefix     string
+	Tier          string
+	CreatedAt     time.Time
+	LastUsedAt    sql.NullTime   // last authenticated call of any kind
+	LastRequestAt sql.NullTime   // newest request submitted in the window
+	StatusCounts  map[string]int // requests created in the window by current status
+	Total         int
+}
+
+// Usage returns the request activity of every unrevoked key over the last
+// windowDays days, newest key first. Keys without requests in the window
+// are included with a zero total, so dormant keys stand out.
+func (r *Repository) Usage(ctx context.Context, windowDays int) ([]KeyUsage, error) {
+	if windowDays < 1 {
+		windowDays = 1
+	}
+	rows, err := r.db.Reader().QueryContext(ctx, `
+		SELECT k.id, k.name, k.key_prefix, k.tier, k.created_at, k.last_used_at,
+		       r.status, COUNT(r.id), MAX(r.created_at)
+		FROM api_keys k
+		LEFT JOIN requests r
+		  ON r.api_key_id = k.id AND r.created_at > datetime('now', ?)
+		WHERE k.revoked_at IS NULL
+		GROUP BY k.id, r.status
+		ORDER BY k.created_at DESC, k.id
+	`, fmt.Sprintf("-%d days", windowDays))
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []KeyUsage
+	for rows.Next() {
+		var (
+			id, name, keyPrefix, tier   string
+			createdAtStr, lastUsedAtStr sql.NullString
+			status, lastRequestStr      sql.NullString
+			count                       int
+		)
+		if err := rows.Scan(&id, &name, &keyPrefix, &tier, &createdAtStr, &lastUsedAtStr, &status, &count, &lastRequestStr); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+
+		// Rows arrive grouped by key, one per status
+		if len(usage) == 0 || usage[len(usage)-1].ID != id {
+			u := KeyUsage{
+				ID:           id,
+				Name:         name,
+				KeyPrefix:    keyPrefix,
+				Tier:         tier,
+				LastUsedAt:   parseNullTime(lastUsedAtStr),
+				StatusCounts: make(map[string]int),
+			}
+			if createdAtStr.Valid {
+				u.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAtStr.String)
+			}
+			usage = append(usage, u)
+		}
+		u := &usage[len(usage)-1]
+		if !status.Valid {
+			continue
+		}
+		u.StatusCounts[status.String] = count
+		u.Total += count
+		if t := parseNullTime(lastRequestStr); t.Valid && (!u.LastRequestAt.Valid || t.Time.After(u.LastRequestAt.Time)) {
+			u.LastRequestAt = t
+		}
+	}
+	return usage, rows.Err()
+}
+
+// parseNullTime parses a TEXT timestamp column.
+func parseNullTime(s sql.NullString) sql.NullTime {
+	if !s.Valid || s.String == "" {
+		return sql.NullTime{}
+	}
+	t, err := time.Parse("2006-01-02 15:04:05", s.String)
+	if err != nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
@@ -235,7 +235,7 @@ func TestRepository_List(t *testing.T) {
 	repo.Create(ctx, "Key 3", "admin", nil)
 
 	// List all keys
-	keys, err := repo.List(ctx, false)
+	keys, _, err := repo.List(ctx, ListOptions{})
 	if err != nil {
 		t.Fatalf("List failed: %v", err)
 	}
@@ -257,7 +257,7 @@ func TestRepository_List_ExcludesRevoked(t *testing.T) {
 	repo.Revoke(ctx, key2.ID)
 
 	// List without revoked
-	keys, _ := repo.List(ctx, false)
+	keys, _, _ := repo.List(ctx, ListOptions{})
 	if len(keys) != 1 {
 		t.Errorf("Expected 1 active key, got %d", len(keys))
 	}
@@ -266,12 +266,88 @@ func TestRepository_List_ExcludesRevoked(t *testing.T) {
 	}
 
 	// List with revoked
-	allKeys, _ := repo.List(ctx, true)
+	allKeys, _, _ := repo.List(ctx, ListOptions{IncludeRevoked: true})
 	if len(allKeys) != 2 {
 		t.Errorf("Expected 2 total keys, got %d", len(allKeys))
 	}
 }
 
+func TestRepository_List_Filters(t *testing.T) {
+	repo, db := setupTestRepo(t)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	repo.Create(ctx, "Prod Reader", "read", nil)
+	repo.Create(ctx, "Prod Writer", "write", nil)
+	repo.Create(ctx, "Staging Writer", "write", nil)
+	repo.Create(ctx, "100%_literal", "read", nil)
+
+	// Tier filter
+	keys, total, err := repo.List(ctx, ListOptions{Tier: "write"})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(keys) != 2 || total != 2 {
+		t.Errorf("Expected 2 write keys, got %d (total %d)", len(keys), total)
+	}
+
+	// Name search is case-insensitive and combines with tier
+	keys, total, _ = repo.List(ctx, ListOptions{Tier: "write", Search: "prod"})
+	if len(keys) != 1 || total != 1 || keys[0].Name != "Prod Writer" {
+		t.Errorf("Expected only Prod Writer, got %d keys (total %d)", len(keys), total)
+	}
+
+	// LIKE wildcards in the search are matched literally
+	keys, _, _ = repo.List(ctx, ListOptions{Search: "%_"})
+	if len(keys) != 1 || keys[0].Name != "100%_literal" {
+		t.Errorf("Expected wildcard search to match literally, got %d keys", len(keys))
+	}
+
+	// Pagination keeps the total of all matches
+	keys, total, _ = repo.List(ctx, ListOptions{Limit: 3})
+	if len(keys) != 3 || total != 4 {
+		t.Errorf("Expected 3 keys of 4, got %d of %d", len(keys), total)
+	}
+	rest, _, _ := repo.List(ctx, ListOptions{Limit: 3, Offset: 3})
+	if len(rest) != 1 {
+		t.Fatalf("Expected 1 key on second page, got %d", len(rest))
+	}
+	for _, k := range keys {
+		if k.ID == rest[0].ID {
+			t.Errorf("Key %s appears on both pages", k.ID)
+		}
+	}
+}
+
+func TestRepository_List_FiltersWithRevoked(t *testing.T) {
+	repo, db := setupTestRepo(t)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	active, _, _ := repo.Create(ctx, "Writer A", "write", nil)
+	revoked, _, _ := repo.Create(ctx, "Writer B", "write", nil)
+	repo.Create(ctx, "Reader", "read", nil)
+	repo.Revoke(ctx, revoked.ID)
+
+	// Revoked keys are excluded before filtering and counting
+	keys, total, _ := repo.List(ctx, ListOptions{Tier: "write"})
+	if len(keys) != 1 || total != 1 || keys[0].ID != active.ID {
+		t.Errorf("Expected only active write key, got %d (total %d)", len(keys), total)
+	}
+
+	keys, total, _ = repo.List(ctx, ListOptions{Tier: "write", IncludeRevoked: true})
+	if len(keys) != 2 || total != 2 {
+		t.Errorf("Expected 2 write keys including revoked, got %d (total %d)", len(keys), total)
+	}
+
+	keys, total, _ = repo.List(ctx, ListOptions{Search: "Writer B"})
+	if len(keys) != 0 || total != 0 {
+		t.Errorf("Expected revoked key to be hidden from search, got %d (total %d)", len(keys), total)
+	}
+}
+
 func TestRepository_Revoke(t *testing.T) {
 	repo, db := setupTestRepo(t)
 	defer db.Close()
@@ -395,7 +471,7 @@ func TestRepository_Count(t *testing.T) {
 	repo.Create(ctx, "Admin 1", "admin", nil)
 
 	// Revoke one read key
-	keys, _ := repo.List(ctx, false)
+	keys, _, _ := repo.List(ctx, ListOptions{})
 	for _, k := range keys {
 		if k.Name == "Read 1" {
 			repo.Revoke(ctx, k.ID)
@@ -419,3 +495,94 @@ func TestRepository_Count(t *testing.T) {
 		t.Errorf("Admin count wrong: got %d, want 1", counts["admin"])
 	}
 }
+
+func TestRepository_UpdateConstraints_Validates(t *testing.T) {
+	repo, db := setupTestRepo(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	apiKey, _, _ := repo.Create(ctx, "Constraint Validation", "write", nil)
+
+	if err := repo.UpdateConstraints(ctx, apiKey.ID, &database.KeyConstraints{TimeoutAction: "maybe"}); err == nil {
+		t.Fatal("expected invalid constraints to be rejected")
+	}
+
+	retrieved, _ := repo.GetByID(ctx, apiKey.ID)
+	if retrieved.Constraints != nil && retrieved.Constraints.TimeoutAction != "" {
+		t.Errorf("rejected constraints were stored: %+v", retrieved.Constraints)
+	}
+}
+
+func TestRepository_Usage(t *testing.T) {
+	repo, db := setupTestRepo(t)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	busy, _, _ := repo.Create(ctx, "Busy", "write", nil)
+	dormant, _, _ := repo.Create(ctx, "Dormant", "read", nil)
+	revoked, _, _ := repo.Create(ctx, "Revoked", "write", nil)
+	if err := repo.UpdateLastUsed(ctx, busy.ID); err != nil {
+		t.Fatalf("UpdateLastUsed failed: %v", err)
+	}
+
+	requests := []struct {
+		id, apiKeyID, status, createdAt string
+	}{
+		{"req_1", busy.ID, database.StatusCompleted, "datetime('now', '-1 hour')"},
+		{"req_2", busy.ID, database.StatusCompleted, "datetime('now', '-2 days')"},
+		{"req_3", busy.ID, database.StatusDenied, "datetime('now', '-3 hours')"},
+		{"req_4", busy.ID, database.StatusCompleted, "datetime('now', '-30 days')"}, // outside the window
+		{"req_5", revoked.ID, database.StatusCompleted, "datetime('now', '-1 hour')"},
+	}
+	for _, r := range requests {
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO requests (id, api_key_id, operation, payload, status, created_at, expires_at)
+			VALUES (?, ?, 'create_event', '{}', ?, `+r.createdAt+`, datetime('now', '+1 hour'))
+		`, r.id, r.apiKeyID, r.status)
+		if err != nil {
+			t.Fatalf("insert request: %v", err)
+		}
+	}
+	if err := repo.Revoke(ctx, revoked.ID); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+
+	usage, err := repo.Usage(ctx, 7)
+	if err != nil {
+		t.Fatalf("Usage failed: %v", err)
+	}
+	if len(usage) != 2 {
+		t.Fatalf("expected 2 unrevoked keys, got %d", len(usage))
+	}
+
+	byID := make(map[string]KeyUsage)
+	for _, u := range usage {
+		byID[u.ID] = u
+	}
+	if _, ok := byID[revoked.ID]; ok {
+		t.Error("revoked key should be excluded")
+	}
+
+	b := byID[busy.ID]
+	if b.Total != 3 || b.StatusCounts[database.StatusCompleted] != 2 || b.StatusCounts[database.StatusDenied] != 1 {
+		t.Errorf("busy key usage = %d %v, want 3 requests (2 completed, 1 denied)", b.Total, b.StatusCounts)
+	}
+	if !b.LastUsedAt.Valid || !b.LastRequestAt.Valid {
+		t.Errorf("busy key should have last used and last request times: %+v", b)
+	}
+	if time.Since(b.LastRequestAt.Time) > 2*time.Hour {
+		t.Errorf("last request = %v, want about an hour ago", b.LastRequestAt.Time)
+	}
+
+	d, ok := byID[dormant.ID]
+	if !ok {
+		t.Fatal("dormant key should be listed")
+	}
+	if d.Total != 0 || len(d.StatusCounts) != 0 || d.LastUsedAt.Valid || d.LastRequestAt.Valid {
+		t.Errorf("dormant key usage = %+v, want none", d)
+	}
+	if d.Name != "Dormant" || d.Tier != "read" {
+		t.Errorf("dormant key details = %+v", d)
+	}
+}
@@ -0,0 +1,317 @@
+package apikeys
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/database"
+)
+
+// Composite rules let a key combine conditions with AND and OR, where the
+// single-value constraints can only test one thing each. A rule is
+//
+//	{"name": "big meetings", "action": "require_approval", "when": {"any": [
+//	    {"field": "attendees", "op": ">", "value": 10},
+//	    {"field": "duration_minutes", "op": ">", "value": 120}
+//	]}}
+//
+// and a condition is one of
+//
+//	{"all": [condition, ...]}          every child matches
+//	{"any": [condition, ...]}          at least one child matches
+//	{"field": f, "op": o, "value": v}  a comparison
+//
+// The numeric fields are attendees (the number of attendees) and
+// duration_minutes; they take a non-negative number and any of >, >=, <,
+// <=, == and !=. The string fields are operation (e.g. "create_event") and
+// calendar (the calendar ID); they take a string and == or !=. Deletes and
+// moves have no attendees and a zero duration.
+//
+// Rules are checked in order. A matching deny rule denies the write even if
+// an earlier require_approval rule matched.
+const (
+	// MaxRules is the most rules a key may have.
+	MaxRules = 20
+	// maxRuleDepth bounds nesting, counting the top-level condition as 1.
+	maxRuleDepth = 4
+	// maxRuleConditions bounds the nodes in one rule's condition.
+	maxRuleConditions = 32
+)
+
+// Rule field names.
+const (
+	RuleFieldAttendees       = "attendees"
+	RuleFieldDurationMinutes = "duration_minutes"
+	RuleFieldOperation       = "operation"
+	RuleFieldCalendar        = "calendar"
+)
+
+var ruleNumericOps = []string{">", ">=", "<", "<=", "==", "!="}
+
+// ruleFacts are the values of a write that rules compare against.
+type ruleFacts struct {
+	operation       string
+	calendarID      string
+	attendees       int
+	durationMinutes float64
+}
+
+func newRuleFacts(operation, calendarID string, attendees []string, start, end time.Time) ruleFacts {
+	return ruleFacts{
+		operation:       operation,
+		calendarID:      calendarID,
+		attendees:       len(attendees),
+		durationMinutes: end.Sub(start).Minutes(),
+	}
+}
+
+// rulesDecision returns the decision of the first matching deny rule, or
+// else of the first matching require_approval rule, or nil when no rule
+// matches. A malformed rule denies, so a bad rule never loosens a key.
+func rulesDecision(rules []database.ConstraintRule, facts ruleFacts) *ConstraintDecision {
+	var approval *ConstraintDecision
+	for i, rule := range rules {
+		matched, err := matchCondition(rule.When, facts)
+		if err != nil {
+			decision := deny("rule", fmt.Sprintf("Invalid constraint rule %s: %v", ruleLabel(rule, i), err))
+			return &decision
+		}
+		if !matched {
+			continue
+		}
+		message := fmt.Sprintf("Constraint rule %s matched: %s", ruleLabel(rule, i), describeCondition(rule.When))
+		switch rule.Action {
+		case "deny":
+			decision := deny("rule", message)
+			return &decision
+		case "require_approval":
+			if approval == nil {
+				decision := requireApproval("rule", message)
+				approval = &decision
+			}
+		default:
+			decision := deny("rule", fmt.Sprintf("Invalid constraint rule %s: unknown action %q", ruleLabel(rule, i), rule.Action))
+			return &decision
+		}
+	}
+	return approval
+}
+
+// matchCondition reports whether a condition holds for facts. Validated
+// rules are bounded by maxRuleDepth, but the depth is checked again here so
+// evaluation never recurses without limit.
+func matchCondition(cond database.RuleCondition, facts ruleFacts) (bool, error) {
+	return matchConditionDepth(cond, facts, 1)
+}
+
+func matchConditionDepth(cond database.RuleCondition, facts ruleFacts, depth int) (bool, error) {
+	if depth > maxRuleDepth {
+		return false, fmt.Errorf("conditions nest deeper than %d levels", maxRuleDepth)
+	}
+	switch {
+	case len(cond.All) > 0:
+		for _, child := range cond.All {
+			matched, err := matchConditionDepth(child, facts, depth+1)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+		return true, nil
+	case len(cond.Any) > 0:
+		for _, child := range cond.Any {
+			matched, err := matchConditionDepth(child, facts, depth+1)
+			if err != nil || matched {
+				return matched, err
+			}
+		}
+		return false, nil
+	}
+
+	switch cond.Field {
+	case RuleFieldAttendees, RuleFieldDurationMinutes:
+		value, err := ruleNumber(cond.Value)
+		if err != nil {
+			return false, err
+		}
+		actual := float64(facts.attendees)
+		if cond.Field == RuleFieldDurationMinutes {
+			actual = facts.durationMinutes
+		}
+		return compareNumbers(actual, cond.Op, value)
+	case RuleFieldOperation, RuleFieldCalendar:
+		value, err := ruleString(cond.Value)
+		if err != nil {
+			return false, err
+		}
+		actual := facts.operation
+		if cond.Field == RuleFieldCalendar {
+			actual = facts.calendarID
+		}
+		switch cond.Op {
+		case "==":
+			return actual == value, nil
+		case "!=":
+			return actual != value, nil
+		}
+		return false, fmt.Errorf("%s supports only == and !=", cond.Field)
+	case "":
+		return false, fmt.Errorf("condition needs all, any or field")
+	}
+	return false, fmt.Errorf("unknown field %q", cond.Field)
+}
+
+func compareNumbers(actual float64, op string, value float64) (bool, error) {
+	switch op {
+	case ">":
+		return actual > value, nil
+	case ">=":
+		return actual >= value, nil
+	case "<":
+		return actual < value, nil
+	case "<=":
+		return actual <= value, nil
+	case "==":
+		return actual == value, nil
+	case "!=":
+		return actual != value, nil
+	}
+	return false, fmt.Errorf("unknown operator %q", op)
+}
+
+func ruleNumber(raw json.RawMessage) (float64, error) {
+	var value float64
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return 0, fmt.Errorf("value must be a number")
+	}
+	if value < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		return 0, fmt.Errorf("value must not be negative")
+	}
+	return value, nil
+}
+
+func ruleString(raw json.RawMessage) (string, error) {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil || value == "" {
+		return "", fmt.Errorf("value must be a non-empty string")
+	}
+	return value, nil
+}
+
+// ValidateRules checks rules against the grammar above and the size limits.
+func ValidateRules(rules []database.ConstraintRule) error {
+	if len(rules) > MaxRules {
+		return fmt.Errorf("at most %d rules are allowed", MaxRules)
+	}
+	for i, rule := range rules {
+		if rule.Action != "deny" && rule.Action != "require_approval" {
+			return fmt.Errorf("rule %s: action must be deny or require_approval", ruleLabel(rule, i))
+		}
+		count := 0
+		if err := validateCondition(rule.When, 1, &count); err != nil {
+			return fmt.Errorf("rule %s: %w", ruleLabel(rule, i), err)
+		}
+	}
+	return nil
+}
+
+func validateCondition(cond database.RuleCondition, depth int, count *int) error {
+	if depth > maxRuleDepth {
+		return fmt.Errorf("conditions nest deeper than %d levels", maxRuleDepth)
+	}
+	*count++
+	if *count > maxRuleConditions {
+		return fmt.Errorf("more than %d conditions", maxRuleConditions)
+	}
+
+	set := 0
+	for _, present := range []bool{cond.All != nil, cond.Any != nil, cond.Field != ""} {
+		if present {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("each condition needs exactly one of all, any or field")
+	}
+
+	children := cond.All
+	if cond.Any != nil {
+		children = cond.Any
+	}
+	if cond.Field == "" {
+		if len(children) == 0 {
+			return fmt.Errorf("all and any need at least one condition")
+		}
+		if cond.Op != "" || len(cond.Value) > 0 {
+			return fmt.Errorf("all and any cannot have op or value")
+		}
+		for _, child := range children {
+			if err := validateCondition(child, depth+1, count); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch cond.Field {
+	case RuleFieldAttendees, RuleFieldDurationMinutes:
+		if !containsString(ruleNumericOps, cond.Op) {
+			return fmt.Errorf("%s: op must be one of %s", cond.Field, strings.Join(ruleNumericOps, " "))
+		}
+		if _, err := ruleNumber(cond.Value); err != nil {
+			return fmt.Errorf("%s: %w", cond.Field, err)
+		}
+	case RuleFieldOperation, RuleFieldCalendar:
+		if cond.Op != "==" && cond.Op != "!=" {
+			return fmt.Errorf("%s: op must be == or !=", cond.Field)
+		}
+		value, err := ruleString(cond.Value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", cond.Field, err)
+		}
+		if cond.Field == RuleFieldOperation && OperationScope(value) == "" {
+			return fmt.Errorf("operation: unknown operation %q", value)
+		}
+	default:
+		return fmt.Errorf("unknown field %q: use %s, %s, %s or %s", cond.Field,
+			RuleFieldAttendees, RuleFieldDurationMinutes, RuleFieldOperation, RuleFieldCalendar)
+	}
+	return nil
+}
+
+// describeCondition renders a condition for decision reasons, e.g.
+// "attendees > 10 or duration_minutes > 120".
+func describeCondition(cond database.RuleCondition) string {
+	join := func(children []database.RuleCondition, sep string) string {
+		parts := make([]string, len(children))
+		for i, child := range children {
+			parts[i] = describeCondition(child)
+			if len(child.All) > 0 || len(child.Any) > 0 {
+				parts[i] = "(" + parts[i] + ")"
+			}
+		}
+		return strings.Join(parts, sep)
+	}
+	switch {
+	case len(cond.All) > 0:
+		return join(cond.All, " and ")
+	case len(cond.Any) > 0:
+		return join(cond.Any, " or ")
+	}
+	var value bytes.Buffer
+	if err := json.Compact(&value, cond.Value); err != nil {
+		value.Write(cond.Value)
+	}
+	return fmt.Sprintf("%s %s %s", cond.Field, cond.Op, value.String())
+}
+
+// ruleLabel names a rule in messages, falling back to its 1-based position.
+func ruleLabel(rule database.ConstraintRule, index int) string {
+	if rule.Name != "" {
+		return fmt.Sprintf("%q", rule.Name)
+	}
+	return fmt.Sprintf("#%d", index+1)
+}
@@ -0,0 +1,301 @@
+package apikeys
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/database"
+)
+
+func mustRules(t *testing.T, data string) []database.ConstraintRule {
+	t.Helper()
+	var rules []database.ConstraintRule
+	if err := json.Unmarshal([]byte(data), &rules); err != nil {
+		t.Fatalf("invalid rules JSON: %v", err)
+	}
+	return rules
+}
+
+func TestMatchCondition(t *testing.T) {
+	big := ruleFacts{operation: database.OperationCreateEvent, calendarID: "team@example.com", attendees: 12, durationMinutes: 30}
+	long := ruleFacts{operation: database.OperationCreateEvent, calendarID: "primary", attendees: 3, durationMinutes: 180}
+	small := ruleFacts{operation: database.OperationCreateEvent, calendarID: "primary", attendees: 3, durationMinutes: 30}
+	bigLong := ruleFacts{operation: database.OperationUpdateEvent, calendarID: "primary", attendees: 12, durationMinutes: 180}
+	del := ruleFacts{operation: database.OperationDeleteEvent, calendarID: "primary"}
+
+	tests := []struct {
+		name  string
+		when  string
+		facts ruleFacts
+		want  bool
+	}{
+		{"gt matches", `{"field": "attendees", "op": ">", "value": 10}`, big, true},
+		{"gt boundary", `{"field": "attendees", "op": ">", "value": 12}`, big, false},
+		{"gte boundary", `{"field": "attendees", "op": ">=", "value": 12}`, big, true},
+		{"lt", `{"field": "attendees", "op": "<", "value": 5}`, small, true},
+		{"lte boundary", `{"field": "duration_minutes", "op": "<=", "value": 30}`, small, true},
+		{"eq", `{"field": "attendees", "op": "==", "value": 3}`, small, true},
+		{"ne", `{"field": "attendees", "op": "!=", "value": 3}`, small, false},
+		{"zero value", `{"field": "attendees", "op": ">", "value": 0}`, small, true},
+		{"fractional duration", `{"field": "duration_minutes", "op": ">", "value": 179.5}`, long, true},
+		{"operation eq", `{"field": "operation", "op": "==", "value": "update_event"}`, bigLong, true},
+		{"operation ne", `{"field": "operation", "op": "!=", "value": "create_event"}`, small, false},
+		{"calendar eq", `{"field": "calendar", "op": "==", "value": "team@example.com"}`, big, true},
+		{"calendar is exact", `{"field": "calendar", "op": "==", "value": "TEAM@example.com"}`, big, false},
+
+		{"or first", `{"any": [{"field": "attendees", "op": ">", "value": 10}, {"field": "duration_minutes", "op": ">", "value": 120}]}`, big, true},
+		{"or second", `{"any": [{"field": "attendees", "op": ">", "value": 10}, {"field": "duration_minutes", "op": ">", "value": 120}]}`, long, true},
+		{"or both", `{"any": [{"field": "attendees", "op": ">", "value": 10}, {"field": "duration_minutes", "op": ">", "value": 120}]}`, bigLong, true},
+		{"or neither", `{"any": [{"field": "attendees", "op": ">", "value": 10}, {"field": "duration_minutes", "op": ">", "value": 120}]}`, small, false},
+
+		{"and both", `{"all": [{"field": "attendees", "op": ">", "value": 10}, {"field": "duration_minutes", "op": ">", "value": 120}]}`, bigLong, true},
+		{"and one", `{"all": [{"field": "attendees", "op": ">", "value": 10}, {"field": "duration_minutes", "op": ">", "value": 120}]}`, big, false},
+		{"and other", `{"all": [{"field": "attendees", "op": ">", "value": 10}, {"field": "duration_minutes", "op": ">", "value": 120}]}`, long, false},
+		{"single child all", `{"all": [{"field": "attendees", "op": ">", "value": 10}]}`, big, true},
+
+		// (create and attendees > 10) or (calendar == team and duration > 120)
+		{"nested or of ands", `{"any": [
+			{"all": [{"field": "operation", "op": "==", "value": "create_event"}, {"field": "attendees", "op": ">", "value": 10}]},
+			{"all": [{"field": "calendar", "op": "==", "value": "team@example.com"}, {"field": "duration_minutes", "op": ">", "value": 120}]}
+		]}`, big, true},
+		{"nested or of ands, update", `{"any": [
+			{"all": [{"field": "operation", "op": "==", "value": "create_event"}, {"field": "attendees", "op": ">", "value": 10}]},
+			{"all": [{"field": "calendar", "op": "==", "value": "team@example.com"}, {"field": "duration_minutes", "op": ">", "value": 120}]}
+		]}`, bigLong, false},
+		// create and (attendees > 10 or duration > 120)
+		{"and of or", `{"all": [
+			{"field": "operation", "op": "==", "value": "create_event"},
+			{"any": [{"field": "attendees", "op": ">", "value": 10}, {"field": "duration_minutes", "op": ">", "value": 120}]}
+		]}`, long, true},
+		{"and of or, wrong operation", `{"all": [
+			{"field": "operation", "op": "==", "value": "create_event"},
+			{"any": [{"field": "attendees", "op": ">", "value": 10}, {"field": "duration_minutes", "op": ">", "value": 120}]}
+		]}`, bigLong, false},
+
+		{"delete has no attendees", `{"field": "attendees", "op": ">", "value": 0}`, del, false},
+		{"delete has zero duration", `{"field": "duration_minutes", "op": "==", "value": 0}`, del, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cond database.RuleCondition
+			if err := json.Unmarshal([]byte(tt.when), &cond); err != nil {
+				t.Fatalf("invalid condition JSON: %v", err)
+			}
+			if err := validateCondition(cond, 1, new(int)); err != nil {
+				t.Fatalf("condition should be valid: %v", err)
+			}
+			got, err := matchCondition(cond, tt.facts)
+			if err != nil {
+				t.Fatalf("matchCondition() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("matchCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateConstraints_Rules(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+	rules := mustRules(t, `[
+		{"name": "big or long", "action": "require_approval", "when": {"any": [
+			{"field": "attendees", "op": ">", "value": 10},
+			{"field": "duration_minutes", "op": ">", "value": 120}
+		]}},
+		{"name": "huge", "action": "deny", "when": {"field": "attendees", "op": ">", "value": 50}}
+	]`)
+	key := &AuthenticatedKey{Tier: database.TierAdmin, Constraints: &database.KeyConstraints{Rules: rules}}
+	attendees := func(n int) []string {
+		list := make([]string, n)
+		for i := range list {
+			list[i] = "guest@example.com"
+		}
+		return list
+	}
+
+	tests := []struct {
+		name       string
+		attendees  []string
+		duration   time.Duration
+		want       ConstraintResult
+		constraint string
+	}{
+		{"small and short", attendees(3), time.Hour, ConstraintAllow, "tier"},
+		{"many attendees", attendees(11), time.Hour, ConstraintRequireApproval, "rule"},
+		{"long", attendees(3), 3 * time.Hour, ConstraintRequireApproval, "rule"},
+		// The deny rule wins even though the approval rule matched first
+		{"too many attendees", attendees(51), time.Hour, ConstraintDeny, "rule"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := EvaluateConstraints(key, database.OperationCreateEvent, "primary", tt.attendees, start, start.Add(tt.duration))
+			if decision.Result != tt.want || decision.Constraint != tt.constraint {
+				t.Errorf("got %v (%s), want %v (%s)", decision.Result, decision.Constraint, tt.want, tt.constraint)
+			}
+		})
+	}
+
+	decision := EvaluateConstraints(key, database.OperationCreateEvent, "primary", attendees(11), start, start.Add(time.Hour))
+	if !strings.Contains(decision.Reason, `"big or long"`) || !strings.Contains(decision.Reason, "attendees > 10 or duration_minutes > 120") {
+		t.Errorf("reason should name the rule and its condition: %s", decision.Reason)
+	}
+
+	// Single-value constraints are checked before rules
+	key.Constraints.MaxAttendees = 20
+	decision = EvaluateConstraints(key, database.OperationCreateEvent, "primary", attendees(51), start, start.Add(time.Hour))
+	if decision.Result != ConstraintDeny || decision.Constraint != "max_attendees" {
+		t.Errorf("expected max_attendees denial, got %v (%s)", decision.Result, decision.Constraint)
+	}
+
+	// An unnamed rule is reported by position
+	key = &AuthenticatedKey{Tier: database.TierAdmin, Constraints: &database.KeyConstraints{Rules: mustRules(t, `[
+		{"action": "deny", "when": {"field": "calendar", "op": "==", "value": "other"}},
+		{"action": "require_approval", "when": {"field": "operation", "op": "==", "value": "delete_event"}}
+	]`)}}
+	now := time.Now()
+	decision = EvaluateConstraints(key, database.OperationDeleteEvent, "primary", nil, now, now)
+	if decision.Result != ConstraintRequireApproval || !strings.Contains(decision.Reason, "#2") {
+		t.Errorf("expected approval from rule #2, got %v: %s", decision.Result, decision.Reason)
+	}
+}
+
+func TestEvaluateConstraints_ExternalAttendeeKeepsRuleDenial(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+	key := &AuthenticatedKey{Tier: database.TierAdmin, Constraints: &database.KeyConstraints{
+		AttendeeDomainAllowlist: []string{"example.com"},
+		Rules: mustRules(t, `[
+			{"name": "no long meetings", "action": "deny", "when": {"field": "duration_minutes", "op": ">", "value": 120}},
+			{"name": "deletes", "action": "require_approval", "when": {"field": "operation", "op": "==", "value": "delete_event"}}
+		]`),
+	}}
+
+	// Inviting an outside address must not turn the deny rule into an approval request
+	decision := EvaluateConstraints(key, database.OperationCreateEvent, "primary", []string{"bob@other.org"}, start, start.Add(3*time.Hour))
+	if decision.Result != ConstraintDeny || decision.Constraint != "rule" {
+		t.Errorf("got %v (%s), want the deny rule", decision.Result, decision.Constraint)
+	}
+
+	// When nothing denies, the first approval found is reported
+	decision = EvaluateConstraints(key, database.OperationCreateEvent, "primary", []string{"bob@other.org"}, start, start.Add(time.Hour))
+	if decision.Result != ConstraintRequireApproval || decision.Constraint != "attendee_domain" {
+		t.Errorf("got %v (%s), want approval from attendee_domain", decision.Result, decision.Constraint)
+	}
+}
+
+func TestEvaluateConstraints_MalformedRuleDenies(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+	// Stored rules are validated, but a rule that slipped through must not
+	// loosen the key
+	for _, data := range []string{
+		`[{"action": "require_approval", "when": {"field": "attendees", "op": "=~", "value": 1}}]`,
+		`[{"action": "require_approval", "when": {"field": "location", "op": "==", "value": "x"}}]`,
+		`[{"action": "allow", "when": {"field": "attendees", "op": ">", "value": 1}}]`,
+		`[{"action": "deny", "when": {"all": [{"all": [{"all": [{"all": [{"field": "attendees", "op": ">", "value": 0}]}]}]}]}}]`,
+	} {
+		key := &AuthenticatedKey{Tier: database.TierAdmin, Constraints: &database.KeyConstraints{Rules: mustRules(t, data)}}
+		decision := EvaluateConstraints(key, database.OperationCreateEvent, "primary", []string{"a@example.com", "b@example.com"}, start, start.Add(time.Hour))
+		if decision.Result != ConstraintDeny || decision.Constraint != "rule" {
+			t.Errorf("%s: expected rule denial, got %v (%s)", data, decision.Result, decision.Constraint)
+		}
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	valid := []string{
+		`[]`,
+		`[{"action": "deny", "when": {"field": "attendees", "op": ">", "value": 10}}]`,
+		`[{"action": "require_approval", "when": {"field": "duration_minutes", "op": ">=", "value": 0}}]`,
+		`[{"name": "x", "action": "require_approval", "when": {"any": [{"field": "attendees", "op": ">", "value": 10}, {"field": "duration_minutes", "op": ">", "value": 120}]}}]`,
+		`[{"action": "deny", "when": {"all": [{"field": "operation", "op": "==", "value": "move_event"}, {"field": "calendar", "op": "!=", "value": "primary"}]}}]`,
+		`[{"action": "deny", "when": {"all": [{"any": [{"all": [{"field": "attendees", "op": ">", "value": 1}]}]}]}}]`,
+	}
+	for _, data := range valid {
+		if err := ValidateRules(mustRules(t, data)); err != nil {
+			t.Errorf("%s: unexpected error: %v", data, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"missing action":       `[{"when": {"field": "attendees", "op": ">", "value": 10}}]`,
+		"unknown action":       `[{"action": "allow", "when": {"field": "attendees", "op": ">", "value": 10}}]`,
+		"empty condition":      `[{"action": "deny", "when": {}}]`,
+		"empty any":            `[{"action": "deny", "when": {"any": []}}]`,
+		"all and field":        `[{"action": "deny", "when": {"all": [{"field": "attendees", "op": ">", "value": 1}], "field": "attendees", "op": ">", "value": 1}}]`,
+		"all and any":          `[{"action": "deny", "when": {"all": [{"field": "attendees", "op": ">", "value": 1}], "any": [{"field": "attendees", "op": ">", "value": 1}]}}]`,
+		"op on group":          `[{"action": "deny", "when": {"any": [{"field": "attendees", "op": ">", "value": 1}], "op": ">"}}]`,
+		"unknown field":        `[{"action": "deny", "when": {"field": "location", "op": "==", "value": "x"}}]`,
+		"unknown op":           `[{"action": "deny", "when": {"field": "attendees", "op": "=>", "value": 1}}]`,
+		"missing op":           `[{"action": "deny", "when": {"field": "attendees", "value": 1}}]`,
+		"missing value":        `[{"action": "deny", "when": {"field": "attendees", "op": ">"}}]`,
+		"string number":        `[{"action": "deny", "when": {"field": "attendees", "op": ">", "value": "10"}}]`,
+		"negative number":      `[{"action": "deny", "when": {"field": "duration_minutes", "op": ">", "value": -1}}]`,
+		"ordered string":       `[{"action": "deny", "when": {"field": "calendar", "op": ">", "value": "a"}}]`,
+		"numeric string field": `[{"action": "deny", "when": {"field": "calendar", "op": "==", "value": 1}}]`,
+		"empty string":         `[{"action": "deny", "when": {"field": "calendar", "op": "==", "value": ""}}]`,
+		"unknown operation":    `[{"action": "deny", "when": {"field": "operation", "op": "==", "value": "drop_event"}}]`,
+		"invalid child":        `[{"action": "deny", "when": {"any": [{"field": "attendees", "op": ">", "value": 1}, {"field": "attendees"}]}}]`,
+		"too deep":             `[{"action": "deny", "when": {"all": [{"all": [{"all": [{"all": [{"field": "attendees", "op": ">", "value": 0}]}]}]}]}}]`,
+	}
+	for name, data := range invalid {
+		if err := ValidateRules(mustRules(t, data)); err == nil {
+			t.Errorf("%s: expected %s to be rejected", name, data)
+		}
+	}
+
+	// Size limits
+	leaf := database.RuleCondition{Field: RuleFieldAttendees, Op: ">", Value: json.RawMessage("1")}
+	wide := database.ConstraintRule{Action: "deny", When: database.RuleCondition{Any: make([]database.RuleCondition, maxRuleConditions)}}
+	for i := range wide.When.Any {
+		wide.When.Any[i] = leaf
+	}
+	if err := ValidateRules([]database.ConstraintRule{wide}); err == nil {
+		t.Error("expected a rule with too many conditions to be rejected")
+	}
+	many := make([]database.ConstraintRule, MaxRules+1)
+	for i := range many {
+		many[i] = database.ConstraintRule{Action: "deny", When: leaf}
+	}
+	if err := ValidateRules(many); err == nil {
+		t.Error("expected too many rules to be rejected")
+	}
+	if err := ValidateRules(many[:MaxRules]); err != nil {
+		t.Errorf("expected %d rules to be allowed: %v", MaxRules, err)
+	}
+}
+
+func TestValidateConstraints_Rules(t *testing.T) {
+	constraints := &database.KeyConstraints{Rules: mustRules(t, `[{"action": "deny", "when": {"field": "attendees", "op": ">", "value": "many"}}]`)}
+	err := ValidateConstraints(constraints)
+	if err == nil || !strings.Contains(err.Error(), "rules") {
+		t.Errorf("expected rules error, got %v", err)
+	}
+
+	// Rules survive a merge patch of other fields, including zero values
+	current := &database.KeyConstraints{Rules: mustRules(t, `[{"action": "require_approval", "when": {"field": "duration_minutes", "op": ">", "value": 0}}]`)}
+	merged, err := MergeConstraints(current, []byte(`{"max_attendees": 5}`))
+	if err != nil {
+		t.Fatalf("MergeConstraints: %v", err)
+	}
+	if err := ValidateConstraints(merged); err != nil {
+		t.Errorf("merged rules should stay valid: %v", err)
+	}
+	if len(merged.Rules) != 1 || string(merged.Rules[0].When.Value) != "0" {
+		t.Errorf("rules were not kept: %+v", merged.Rules)
+	}
+}
+
+func TestRepository_CreateRejectsInvalidRules(t *testing.T) {
+	repo, db := setupTestRepo(t)
+	defer db.Close()
+
+	_, _, err := repo.Create(context.Background(), "bad rules", database.TierWrite, &database.KeyConstraints{
+		Rules: mustRules(t, `[{"action": "deny", "when": {"field": "attendees", "op": "~", "value": 1}}]`),
+	})
+	if err == nil {
+		t.Fatal("expected a key with an invalid rule to be rejected")
+	}
+}
@@ -0,0 +1,112 @@
+package apikeys
+
+import (
+	"fmt"
+
+	"github.com/dtorcivia/schedlock/internal/database"
+)
+
+// Scope constants name the individual permissions an API key can hold.
+const (
+	ScopeCalendarsList  = "calendars:list"
+	ScopeEventsRead     = "events:read"
+	ScopeFreeBusyRead   = "freebusy:read"
+	ScopeRequestsRead   = "requests:read"
+	ScopeEventsCreate   = "events:create"
+	ScopeEventsUpdate   = "events:update"
+	ScopeEventsDelete   = "events:delete"
+	ScopeEventsMove     = "events:move"
+	ScopeRequestsCancel = "requests:cancel"
+	ScopeAdmin          = "admin"
+)
+
+var readScopes = []string{
+	ScopeCalendarsList,
+	ScopeEventsRead,
+	ScopeFreeBusyRead,
+	ScopeRequestsRead,
+}
+
+var writeScopes = append(append([]string{}, readScopes...),
+	ScopeEventsCreate,
+	ScopeEventsUpdate,
+	ScopeEventsDelete,
+	ScopeEventsMove,
+	ScopeRequestsCancel,
+)
+
+var adminScopes = append(append([]string{}, writeScopes...), ScopeAdmin)
+
+// AllScopes returns every known scope in display order.
+func AllScopes() []string {
+	return append([]string{}, adminScopes...)
+}
+
+// TierScopes returns the scopes equivalent to a tier. Keys created before
+// scopes existed are granted exactly this set.
+func TierScopes(tier string) []string {
+	switch tier {
+	case database.TierRead:
+		return append([]string{}, readScopes...)
+	case database.TierWrite:
+		return append([]string{}, writeScopes...)
+	case database.TierAdmin:
+		return append([]string{}, adminScopes...)
+	}
+	return nil
+}
+
+// OperationScope returns the scope required to submit a write operation.
+func OperationScope(operation string) string {
+	switch operation {
+	case database.OperationCreateEvent, database.OperationCreateEventsBatch:
+		return ScopeEventsCreate
+	case database.OperationUpdateEvent:
+		return ScopeEventsUpdate
+	case database.OperationDeleteEvent:
+		return ScopeEventsDelete
+	case database.OperationMoveEvent:
+		return ScopeEventsMove
+	}
+	return ""
+}
+
+// ValidateScopes checks that every scope is known and allowed for the tier.
+// Scopes cannot grant more than the tier itself would.
+func ValidateScopes(tier string, scopes []string) error {
+	allowed := TierScopes(tier)
+	if allowed == nil {
+		return fmt.Errorf("invalid tier: %s", tier)
+	}
+	if len(scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !containsString(adminScopes, scope) {
+			return fmt.Errorf("unknown scope: %s", scope)
+		}
+		if !containsString(allowed, scope) {
+			return fmt.Errorf("scope %s is not available to %s keys", scope, tier)
+		}
+	}
+	return nil
+}
+
+// HasScope reports whether the key holds a scope. Keys without an explicit
+// scope list fall back to their tier's scopes.
+func (k *AuthenticatedKey) HasScope(scope string) bool {
+	scopes := k.Scopes
+	if scopes == nil {
+		scopes = TierScopes(k.Tier)
+	}
+	return containsString(scopes, scope)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
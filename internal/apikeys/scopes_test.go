@@ -0,0 +1,155 @@
+package apikeys
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/database"
+)
+
+func TestValidateScopes(t *testing.T) {
+	cases := []struct {
+		tier    string
+		scopes  []string
+		wantErr bool
+	}{
+		{"write", []string{ScopeEventsCreate}, false},
+		{"read", []string{ScopeEventsRead, ScopeFreeBusyRead}, false},
+		{"admin", []string{ScopeAdmin}, false},
+		{"read", []string{ScopeEventsCreate}, true},
+		{"write", []string{ScopeAdmin}, true},
+		{"write", []string{"events:teleport"}, true},
+		{"write", []string{}, true},
+		{"owner", []string{ScopeEventsRead}, true},
+	}
+
+	for _, tc := range cases {
+		err := ValidateScopes(tc.tier, tc.scopes)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ValidateScopes(%q, %v) error = %v, wantErr %v", tc.tier, tc.scopes, err, tc.wantErr)
+		}
+	}
+}
+
+func TestCreateWithScopes_LimitsWriteKeyToCreates(t *testing.T) {
+	repo, db := setupTestRepo(t)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, fullKey, err := repo.CreateWithScopes(ctx, "Creator", "write", []string{ScopeEventsRead, ScopeEventsCreate}, nil)
+	if err != nil {
+		t.Fatalf("CreateWithScopes failed: %v", err)
+	}
+
+	authKey, err := repo.Authenticate(ctx, fullKey)
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+
+	if !authKey.HasScope(ScopeEventsCreate) {
+		t.Error("expected events:create scope")
+	}
+	if authKey.HasScope(ScopeEventsDelete) {
+		t.Error("did not expect events:delete scope")
+	}
+	if authKey.HasScope(ScopeCalendarsList) {
+		t.Error("did not expect calendars:list scope")
+	}
+
+	start := time.Now().Add(24 * time.Hour)
+	end := start.Add(time.Hour)
+
+	result, violation := evaluate(authKey, database.OperationCreateEvent, "primary", nil, start, end)
+	if result != ConstraintRequireApproval || violation != nil {
+		t.Errorf("create: got %v (%v), want require approval", result, violation)
+	}
+
+	result, violation = evaluate(authKey, database.OperationDeleteEvent, "primary", nil, start, end)
+	if result != ConstraintDeny {
+		t.Fatalf("delete: got %v, want deny", result)
+	}
+	if violation == nil || violation.Constraint != "scope" {
+		t.Errorf("delete: expected scope violation, got %+v", violation)
+	}
+}
+
+func TestCreateWithScopes_RejectsScopesAboveTier(t *testing.T) {
+	repo, db := setupTestRepo(t)
+	defer db.Close()
+
+	if _, _, err := repo.CreateWithScopes(context.Background(), "Reader", "read", []string{ScopeEventsDelete}, nil); err == nil {
+		t.Fatal("expected error for write scope on read key")
+	}
+}
+
+func TestCreate_DefaultsToTierScopes(t *testing.T) {
+	repo, db := setupTestRepo(t)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	created, _, err := repo.Create(ctx, "Writer", "write", nil)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	stored, err := repo.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if !sameScopes(stored.Scopes, TierScopes("write")) {
+		t.Errorf("scopes = %v, want %v", stored.Scopes, TierScopes("write"))
+	}
+}
+
+func TestAuthenticate_LegacyKeyUsesTierScopes(t *testing.T) {
+	repo, db := setupTestRepo(t)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	created, fullKey, err := repo.Create(ctx, "Legacy", "read", nil)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE api_keys SET scopes = NULL WHERE id = ?`, created.ID); err != nil {
+		t.Fatalf("clear scopes: %v", err)
+	}
+
+	authKey, err := repo.Authenticate(ctx, fullKey)
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if !sameScopes(authKey.Scopes, TierScopes("read")) {
+		t.Errorf("scopes = %v, want %v", authKey.Scopes, TierScopes("read"))
+	}
+	if authKey.HasScope(ScopeEventsCreate) {
+		t.Error("legacy read key should not gain events:create")
+	}
+}
+
+func TestHasScope_NilScopesFallBackToTier(t *testing.T) {
+	key := &AuthenticatedKey{Tier: "admin"}
+	if !key.HasScope(ScopeAdmin) {
+		t.Error("admin tier should hold admin scope")
+	}
+
+	key = &AuthenticatedKey{Tier: "write"}
+	if key.HasScope(ScopeAdmin) {
+		t.Error("write tier should not hold admin scope")
+	}
+}
+
+func sameScopes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
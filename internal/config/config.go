@@ -5,11 +5,15 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/dtorcivia/schedlock/internal/util"
 )
 
 // Config holds all application configuration.
@@ -26,6 +30,8 @@ type Config struct {
 	Logging       LoggingConfig
 	Display       DisplayConfig
 	Retention     RetentionConfig
+	Audit         AuditConfig
+	EventDefaults EventDefaultsConfig
 }
 
 // ServerConfig holds HTTP server settings.
@@ -35,13 +41,30 @@ type ServerConfig struct {
 	BaseURL      string
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	MaxBodyBytes int64 // Upper bound on request body size (JSON, forms, webhooks)
+
+	// Headers set by a reverse proxy that carry the real client IP, checked in
+	// order (e.g. CF-Connecting-IP behind Cloudflare). Empty uses the peer
+	// address, or X-Forwarded-For and X-Real-IP when TrustedProxies is set.
+	// Requires TrustedProxies, since any client can send these headers.
+	ClientIPHeaders []string
+	TrustedProxies  []string // proxy addresses or CIDR ranges whose client IP headers are believed
 }
 
 // DatabaseConfig holds SQLite settings.
 type DatabaseConfig struct {
-	Path          string
-	WALMode       bool
-	BusyTimeoutMs int
+	Path                 string
+	WALMode              bool
+	BusyTimeoutMs        int
+	ReadPool             bool // Separate read-only connection pool for read-heavy queries
+	ReadPoolSize         int
+	BackupMaxBytes       int64 // Refuse backups larger than this; 0 disables the limit
+	BackupTimeoutSeconds int   // Upper bound on creating and streaming a backup
+
+	// Primary pool limits; 0 leaves the database/sql default
+	MaxOpenConns           int
+	MaxIdleConns           int
+	ConnMaxLifetimeSeconds int
 }
 
 // GoogleConfig holds Google OAuth settings.
@@ -50,12 +73,133 @@ type GoogleConfig struct {
 	ClientSecret string
 	RedirectURI  string
 	Scopes       []string
+
+	// MaxConcurrentCalls caps Google API calls in flight across execution
+	// and read endpoints (0 means unlimited). A call waits up to CallWaitMs
+	// for a free slot before failing.
+	MaxConcurrentCalls int
+	CallWaitMs         int
+
+	// EventCacheTTLSeconds keeps event reads for this long, so repeated
+	// reads of the same calendar skip Google (0 disables the cache).
+	// EventCacheSize caps the cached reads.
+	EventCacheTTLSeconds int
+	EventCacheSize       int
 }
 
 // ApprovalConfig holds approval workflow settings.
 type ApprovalConfig struct {
 	TimeoutMinutes int
 	DefaultAction  string // "approve" or "deny"
+	// ReminderMinutes re-notifies approvers this long before expiry (0 disables).
+	ReminderMinutes int
+	// ReminderMinAgeMinutes skips reminders for requests younger than this.
+	ReminderMinAgeMinutes int
+	// EscalationMinutes sends a still-pending request to the escalation
+	// target this long after it was created (0 disables).
+	EscalationMinutes int
+	// EscalationProvider is the notification provider used to escalate
+	// ("telegram", "ntfy" or "pushover").
+	EscalationProvider string
+	// EscalationTarget is the provider's alternate recipient: a Telegram chat
+	// ID, an ntfy topic or a Pushover user key.
+	EscalationTarget string
+	// ResendCooldownSeconds is the minimum gap between manual notification resends per request.
+	ResendCooldownSeconds int
+	// SuggestWindowHours is how far either side of the requested time to look for free slots.
+	SuggestWindowHours int
+	// SuggestSlotMinutes is the granularity of suggested slot start times.
+	SuggestSlotMinutes int
+	// Approvers names the people who decide requests through approval links.
+	// When set, link approvals must say which of them is deciding.
+	Approvers []string
+	// Routes sends approvals for matching calendars to their own recipients
+	// instead of the providers' usual ones.
+	Routes []ApprovalRoute
+	// RequireAll holds every write for approval, whatever the key's tier or
+	// constraints would allow. Denials still apply.
+	RequireAll bool
+	// MinRequestTimeoutMinutes and MaxRequestTimeoutMinutes bound the
+	// timeout a caller may set for one request with the
+	// X-Approval-Timeout-Minutes header. A max of 0 rejects the header.
+	MinRequestTimeoutMinutes int
+	MaxRequestTimeoutMinutes int
+}
+
+// ApprovalRoute sends approval requests for matching calendars through one
+// provider to an alternate recipient.
+type ApprovalRoute struct {
+	// Calendar is a calendar ID, or a pattern such as
+	// "*@group.calendar.google.com" where * matches any run of characters.
+	Calendar string `json:"calendar"`
+	// Provider delivers the approval ("telegram", "ntfy" or "pushover").
+	Provider string `json:"provider"`
+	// Target is the provider's recipient: a Telegram chat ID, an ntfy
+	// topic or a Pushover user key.
+	Target string `json:"target"`
+}
+
+// IsPattern reports whether the route's calendar is a pattern rather than
+// a calendar ID.
+func (r ApprovalRoute) IsPattern() bool {
+	return strings.ContainsAny(r.Calendar, "*?[")
+}
+
+// ValidateApprovalRoutes checks that every route names a calendar, a
+// supported provider and a target.
+func ValidateApprovalRoutes(routes []ApprovalRoute) error {
+	for _, route := range routes {
+		if route.Calendar == "" || strings.ContainsAny(route.Calendar, " \t") {
+			return fmt.Errorf("approval route calendar %q must be a calendar ID or pattern without spaces", route.Calendar)
+		}
+		if _, err := path.Match(route.Calendar, ""); err != nil {
+			return fmt.Errorf("approval route calendar %q is not a valid pattern", route.Calendar)
+		}
+		switch route.Provider {
+		case "telegram", "ntfy", "pushover":
+		default:
+			return fmt.Errorf("approval route for %s: provider must be telegram, ntfy or pushover", route.Calendar)
+		}
+		if route.Target == "" {
+			return fmt.Errorf("approval route for %s: target is required", route.Calendar)
+		}
+	}
+	return nil
+}
+
+// MaxApproverNameLength bounds an approver's name or email.
+const MaxApproverNameLength = 100
+
+// ValidateApprovers checks an approver list for blank, overlong and
+// duplicate entries. Names are compared without case.
+func ValidateApprovers(approvers []string) error {
+	seen := make(map[string]bool, len(approvers))
+	for _, approver := range approvers {
+		name := strings.TrimSpace(approver)
+		if name == "" {
+			return fmt.Errorf("approver names must not be empty")
+		}
+		if len(name) > MaxApproverNameLength {
+			return fmt.Errorf("approver %q exceeds %d characters", name, MaxApproverNameLength)
+		}
+		if seen[strings.ToLower(name)] {
+			return fmt.Errorf("approver %q is listed twice", name)
+		}
+		seen[strings.ToLower(name)] = true
+	}
+	return nil
+}
+
+// Approver returns the configured approver matching name, ignoring case and
+// surrounding space, and whether there was one.
+func (c ApprovalConfig) Approver(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for _, approver := range c.Approvers {
+		if name != "" && strings.EqualFold(strings.TrimSpace(approver), name) {
+			return strings.TrimSpace(approver), true
+		}
+	}
+	return "", false
 }
 
 // TierLimit defines rate limits for a specific tier.
@@ -73,9 +217,14 @@ type RateLimitsConfig struct {
 
 // RetryConfig holds retry settings for Google API calls.
 type RetryConfig struct {
-	Enabled              bool
-	MaxAttempts          int
+	Enabled     bool
+	MaxAttempts int
+	// Strategy is "fixed" (index into BackoffSeconds) or "exponential"
+	// (BaseDelaySeconds doubled per attempt, capped at MaxDelaySeconds, with jitter).
+	Strategy             string
 	BackoffSeconds       []int
+	BaseDelaySeconds     int
+	MaxDelaySeconds      int // also caps a Retry-After sent by Google
 	RetryableStatusCodes []int
 }
 
@@ -87,6 +236,8 @@ type NtfyConfig struct {
 	Token          string
 	Priority       string
 	MinimalContent bool
+
+	ResultsOn []string // request statuses sent as result messages; empty sends none
 }
 
 // PushoverConfig holds Pushover notification settings.
@@ -96,6 +247,8 @@ type PushoverConfig struct {
 	UserKey  string
 	Priority int
 	Sound    string
+
+	ResultsOn []string // request statuses sent as result messages; empty sends none
 }
 
 // TelegramConfig holds Telegram notification settings.
@@ -106,6 +259,9 @@ type TelegramConfig struct {
 	WebhookSecret       string
 	WebhookPath         string
 	AutoRegisterWebhook bool
+
+	DenyReasons []string // preset reasons offered as one-tap deny buttons
+	ResultsOn   []string // request statuses sent as result messages; empty sends none
 }
 
 // GenericWebhookConfig holds generic webhook notification settings.
@@ -114,6 +270,8 @@ type GenericWebhookConfig struct {
 	URL            string
 	Secret         string // For HMAC-SHA256 signature
 	TimeoutSeconds int
+
+	ResultsOn []string // request statuses sent as result messages; empty sends none
 }
 
 // NotificationsConfig holds all notification provider settings.
@@ -134,6 +292,107 @@ type WebhookConfig struct {
 	MaxRetries       int
 	RetryBackoff     []int
 	NotifyOn         []string
+
+	// Optional mTLS client certificate presented on delivery
+	ClientCertFile string
+	ClientKeyFile  string
+	CACertFile     string
+
+	// Optional batching: events within the window are sent as one array payload
+	BatchWindowMs int // 0 disables batching
+	BatchMaxSize  int // flush early once this many events are queued
+
+	// IncludeDiff adds the before/after field diff to update requests' events
+	IncludeDiff bool
+
+	// DebounceMs holds intermediate statuses such as approved this long and
+	// drops them if a later status for the same request follows. 0 sends
+	// every status.
+	DebounceMs int
+
+	// Destinations are further receivers, each with its own secret and
+	// status filter. URL, Token and NotifyOn above form the default one.
+	Destinations []WebhookDestination
+}
+
+// DefaultWebhookDestination names the destination configured by the
+// top-level webhook URL.
+const DefaultWebhookDestination = "default"
+
+// WebhookDestination is one receiver of Moltbot webhook events.
+type WebhookDestination struct {
+	Name     string
+	URL      string
+	Token    string
+	NotifyOn []string // empty falls back to the webhook's NotifyOn
+}
+
+// AllDestinations returns every configured destination, the default one
+// first. Destinations without their own NotifyOn get the webhook's.
+func (c *WebhookConfig) AllDestinations() []WebhookDestination {
+	var destinations []WebhookDestination
+	if c.URL != "" {
+		destinations = append(destinations, WebhookDestination{
+			Name:     DefaultWebhookDestination,
+			URL:      c.URL,
+			Token:    c.Token,
+			NotifyOn: c.NotifyOn,
+		})
+	}
+	for _, d := range c.Destinations {
+		if len(d.NotifyOn) == 0 {
+			d.NotifyOn = c.NotifyOn
+		}
+		destinations = append(destinations, d)
+	}
+	return destinations
+}
+
+// NotifiesAbout reports whether events for status pass the webhook's
+// NotifyOn or the filter of any extra destination.
+func (c *WebhookConfig) NotifiesAbout(status string) bool {
+	if (WebhookDestination{NotifyOn: c.NotifyOn}).Wants(status) {
+		return true
+	}
+	for _, d := range c.Destinations {
+		if len(d.NotifyOn) > 0 && d.Wants(status) {
+			return true
+		}
+	}
+	return false
+}
+
+// Wants reports whether the destination receives events for status. An
+// empty NotifyOn receives every status.
+func (d WebhookDestination) Wants(status string) bool {
+	if len(d.NotifyOn) == 0 {
+		return true
+	}
+	for _, allowed := range d.NotifyOn {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDestinations checks the extra destinations: each needs a unique name and an
+// http or https URL.
+func (c *WebhookConfig) validateDestinations() error {
+	names := map[string]bool{DefaultWebhookDestination: c.URL != ""}
+	for i, d := range c.Destinations {
+		if d.Name == "" {
+			return fmt.Errorf("moltbot webhook destination %d needs a name", i+1)
+		}
+		if names[d.Name] {
+			return fmt.Errorf("moltbot webhook destination name %q is used more than once", d.Name)
+		}
+		names[d.Name] = true
+		if u, err := url.Parse(d.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("moltbot webhook destination %q URL must be an http or https URL", d.Name)
+		}
+	}
+	return nil
 }
 
 // MoltbotConfig holds Moltbot integration settings.
@@ -156,7 +415,11 @@ type AuthConfig struct {
 	EncryptionKey     string
 	SessionDuration   time.Duration
 	SessionRefresh    bool
-	CloudflareAccess  CloudflareAccessConfig
+
+	// SessionIdleTimeout ends sessions unused for this long, independent of
+	// SessionDuration. Zero disables the idle check.
+	SessionIdleTimeout time.Duration
+	CloudflareAccess   CloudflareAccessConfig
 }
 
 // LoggingConfig holds logging settings.
@@ -172,6 +435,13 @@ type DisplayConfig struct {
 	DateFormat     string
 	TimeFormat     string
 	DatetimeFormat string
+	// Locale translates month and weekday names and relative phrases
+	// (en, de, fr or es).
+	Locale string
+	// RelativeTime shows times alongside their distance from now.
+	RelativeTime bool
+	// StatsWindowDays is the period covered by operation and decision-time stats.
+	StatsWindowDays int
 }
 
 // RetentionConfig holds data retention settings.
@@ -181,6 +451,62 @@ type RetentionConfig struct {
 	AuditLogDays          int
 	WebhookFailuresDays   int
 	VacuumSchedule        string
+
+	// UnclaimedResultsHours deletes completed requests whose caller never
+	// fetched them this many hours after they ran; 0 keeps them until
+	// CompletedRequestsDays.
+	UnclaimedResultsHours int
+}
+
+// AuditWebhookConfig holds settings for streaming audit events to an
+// external collector such as a SIEM. It is separate from the Moltbot webhook.
+type AuditWebhookConfig struct {
+	URL            string // empty disables streaming
+	Secret         string // For HMAC-SHA256 signature
+	TimeoutSeconds int
+	MaxRetries     int
+	RetryBackoff   []int
+	BatchWindowMs  int // 0 sends each entry on its own
+	BatchMaxSize   int // flush early once this many entries are queued
+	QueueSize      int // entries buffered before new ones are dropped
+}
+
+// validate checks an audit webhook's settings; name prefixes the errors.
+func (w AuditWebhookConfig) validate(name string) error {
+	if w.URL != "" {
+		if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s URL must be an http or https URL", name)
+		}
+	}
+	if w.BatchWindowMs < 0 {
+		return fmt.Errorf("%s batch window must not be negative", name)
+	}
+	if w.BatchMaxSize < 1 {
+		return fmt.Errorf("%s batch max size must be at least 1", name)
+	}
+	if w.QueueSize < 1 {
+		return fmt.Errorf("%s queue size must be at least 1", name)
+	}
+	return nil
+}
+
+// AuditConfig holds audit log settings.
+type AuditConfig struct {
+	Webhook AuditWebhookConfig
+
+	// SettingsWebhook receives only settings changes, each with a redacted
+	// diff, e.g. for a compliance team that does not want every audit event.
+	SettingsWebhook AuditWebhookConfig
+}
+
+// EventDefaultsConfig holds values merged into every created event that
+// leaves the field empty. A key's event_defaults constraint overrides each
+// field.
+type EventDefaultsConfig struct {
+	DescriptionSuffix string // appended to every description
+	Location          string
+	ColorID           string   // "1"-"11"
+	Reminders         []string // "method:minutes", such as "popup:10"
 }
 
 // Load reads configuration from environment variables with defaults.
@@ -220,9 +546,149 @@ func (c *Config) Validate() error {
 	if c.Approval.DefaultAction != "" && c.Approval.DefaultAction != "approve" && c.Approval.DefaultAction != "deny" {
 		return fmt.Errorf("approval default action must be approve or deny")
 	}
+	if c.Approval.EscalationMinutes < 0 {
+		return fmt.Errorf("approval escalation minutes must not be negative")
+	}
+	if c.Approval.MaxRequestTimeoutMinutes < 0 {
+		return fmt.Errorf("approval max request timeout must not be negative")
+	}
+	if c.Approval.MaxRequestTimeoutMinutes > 0 {
+		if c.Approval.MinRequestTimeoutMinutes < 1 {
+			return fmt.Errorf("approval min request timeout must be at least 1 minute")
+		}
+		if c.Approval.MinRequestTimeoutMinutes > c.Approval.MaxRequestTimeoutMinutes {
+			return fmt.Errorf("approval min request timeout must not exceed the max request timeout")
+		}
+	}
+	if err := ValidateApprovers(c.Approval.Approvers); err != nil {
+		return err
+	}
+	if err := ValidateApprovalRoutes(c.Approval.Routes); err != nil {
+		return err
+	}
+	if c.Approval.EscalationMinutes > 0 {
+		switch c.Approval.EscalationProvider {
+		case "telegram", "ntfy", "pushover":
+		default:
+			return fmt.Errorf("approval escalation provider must be telegram, ntfy or pushover")
+		}
+		if c.Approval.EscalationTarget == "" {
+			return fmt.Errorf("approval escalation target is required when escalation is enabled")
+		}
+		if c.Approval.TimeoutMinutes > 0 && c.Approval.EscalationMinutes >= c.Approval.TimeoutMinutes {
+			return fmt.Errorf("approval escalation minutes must be less than the approval timeout")
+		}
+	}
 	if c.Logging.Format != "" && c.Logging.Format != "json" && c.Logging.Format != "text" {
 		return fmt.Errorf("logging format must be json or text")
 	}
+	if c.Auth.SessionIdleTimeout < 0 {
+		return fmt.Errorf("session idle timeout must not be negative")
+	}
+	if c.Server.MaxBodyBytes < 0 {
+		return fmt.Errorf("max body bytes must not be negative")
+	}
+	for _, header := range c.Server.ClientIPHeaders {
+		if header == "" || strings.ContainsAny(header, " :") {
+			return fmt.Errorf("invalid client IP header %q", header)
+		}
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, err := util.ParseIPPrefix(proxy); err != nil {
+			return fmt.Errorf("invalid trusted proxy %q: use an address or CIDR range", proxy)
+		}
+	}
+	if len(c.Server.ClientIPHeaders) > 0 && len(c.Server.TrustedProxies) == 0 {
+		return fmt.Errorf("client IP headers require trusted proxies: set SCHEDLOCK_TRUSTED_PROXIES to the proxies that set them")
+	}
+	if c.Database.BusyTimeoutMs < 0 {
+		return fmt.Errorf("database busy timeout must not be negative")
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 || c.Database.ConnMaxLifetimeSeconds < 0 {
+		return fmt.Errorf("database pool limits must not be negative")
+	}
+	if c.Database.ReadPool && !c.Database.WALMode {
+		return fmt.Errorf("database read pool requires WAL mode")
+	}
+	if c.Retention.UnclaimedResultsHours < 0 {
+		return fmt.Errorf("unclaimed results retention must not be negative")
+	}
+	if c.Database.BackupMaxBytes < 0 {
+		return fmt.Errorf("database backup max bytes must not be negative")
+	}
+	if c.Database.BackupTimeoutSeconds <= 0 {
+		return fmt.Errorf("database backup timeout must be positive")
+	}
+	if c.Google.MaxConcurrentCalls < 0 || c.Google.CallWaitMs < 0 {
+		return fmt.Errorf("google concurrency limit and call wait must not be negative")
+	}
+	if c.Google.EventCacheTTLSeconds < 0 {
+		return fmt.Errorf("google event cache TTL must not be negative")
+	}
+	if c.Google.EventCacheTTLSeconds > 0 && c.Google.EventCacheSize < 1 {
+		return fmt.Errorf("google event cache size must be positive when the cache is enabled")
+	}
+	if _, err := util.NormalizeLocale(c.Display.Locale); err != nil {
+		return fmt.Errorf("invalid display locale: %w", err)
+	}
+	if c.Display.StatsWindowDays < 1 {
+		return fmt.Errorf("stats window must be at least 1 day")
+	}
+	if c.Retry.Strategy != "" && c.Retry.Strategy != RetryStrategyFixed && c.Retry.Strategy != RetryStrategyExponential {
+		return fmt.Errorf("retry strategy must be fixed or exponential")
+	}
+	if (c.Moltbot.Webhook.ClientCertFile == "") != (c.Moltbot.Webhook.ClientKeyFile == "") {
+		return fmt.Errorf("moltbot webhook client cert and key must be set together")
+	}
+	if c.Moltbot.Webhook.BatchWindowMs < 0 {
+		return fmt.Errorf("moltbot webhook batch window must not be negative")
+	}
+	if c.Moltbot.Webhook.BatchWindowMs > 0 && c.Moltbot.Webhook.BatchMaxSize < 1 {
+		return fmt.Errorf("moltbot webhook batch max size must be at least 1")
+	}
+	if c.Moltbot.Webhook.DebounceMs < 0 {
+		return fmt.Errorf("moltbot webhook debounce must not be negative")
+	}
+	if err := c.Moltbot.Webhook.validateDestinations(); err != nil {
+		return err
+	}
+	if err := c.Audit.Webhook.validate("audit webhook"); err != nil {
+		return err
+	}
+	if err := c.Audit.SettingsWebhook.validate("settings webhook"); err != nil {
+		return err
+	}
+	if len(c.EventDefaults.DescriptionSuffix) > util.MaxDescriptionSuffixLength {
+		return fmt.Errorf("event default description suffix exceeds %d characters", util.MaxDescriptionSuffixLength)
+	}
+	if err := util.ValidateColorID(c.EventDefaults.ColorID); err != nil {
+		return fmt.Errorf("event default color: %w", err)
+	}
+	for _, reminder := range c.EventDefaults.Reminders {
+		if _, _, err := util.ParseReminder(reminder); err != nil {
+			return fmt.Errorf("event default reminder %q: %w", reminder, err)
+		}
+	}
+	if len(c.Notifications.Telegram.DenyReasons) > MaxTelegramDenyReasons {
+		return fmt.Errorf("at most %d telegram deny reasons are allowed", MaxTelegramDenyReasons)
+	}
+	for _, reason := range c.Notifications.Telegram.DenyReasons {
+		if strings.TrimSpace(reason) == "" || len(reason) > MaxTelegramDenyReasonLength {
+			return fmt.Errorf("telegram deny reasons must be 1-%d characters", MaxTelegramDenyReasonLength)
+		}
+	}
+	for provider, statuses := range map[string][]string{
+		"ntfy":     c.Notifications.Ntfy.ResultsOn,
+		"pushover": c.Notifications.Pushover.ResultsOn,
+		"telegram": c.Notifications.Telegram.ResultsOn,
+		"webhook":  c.Notifications.Webhook.ResultsOn,
+	} {
+		for _, status := range statuses {
+			if status != ResultStatusCompleted && status != ResultStatusFailed && status != ResultStatusDenied {
+				return fmt.Errorf("%s results_on status %q must be completed, failed or denied", provider, status)
+			}
+		}
+	}
 
 	// Validate at least one notification provider is enabled or warn
 	if !c.Notifications.Ntfy.Enabled && !c.Notifications.Pushover.Enabled && !c.Notifications.Telegram.Enabled && !c.Notifications.Webhook.Enabled {
@@ -314,6 +780,28 @@ func getEnvDurationAny(defaultValue time.Duration, keys ...string) time.Duration
 	return defaultValue
 }
 
+// getEnvListAny reads a comma-separated list. A variable that is set but
+// empty yields an empty list, so defaults can be switched off.
+func getEnvListAny(defaultValue []string, keys ...string) []string {
+	for _, key := range keys {
+		if value, exists := os.LookupEnv(key); exists {
+			return splitList(value)
+		}
+	}
+	return defaultValue
+}
+
+// splitList splits a comma-separated list, dropping blank entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func defaultConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
@@ -322,18 +810,35 @@ func defaultConfig() *Config {
 			BaseURL:      DefaultBaseURL,
 			ReadTimeout:  DefaultReadTimeout,
 			WriteTimeout: DefaultWriteTimeout,
+			MaxBodyBytes: DefaultMaxBodyBytes,
 		},
 		Database: DatabaseConfig{
-			Path:          filepath.Join(DefaultDataDir, "schedlock.db"),
-			WALMode:       true,
-			BusyTimeoutMs: DefaultBusyTimeoutMs,
+			Path:                 filepath.Join(DefaultDataDir, "schedlock.db"),
+			WALMode:              true,
+			BusyTimeoutMs:        DefaultBusyTimeoutMs,
+			ReadPool:             false,
+			ReadPoolSize:         DefaultReadPoolSize,
+			BackupMaxBytes:       DefaultBackupMaxBytes,
+			BackupTimeoutSeconds: DefaultBackupTimeoutSeconds,
+			MaxIdleConns:         DefaultMaxIdleConns,
 		},
 		Google: GoogleConfig{
-			Scopes: []string{"https://www.googleapis.com/auth/calendar.events"},
+			Scopes:             []string{"https://www.googleapis.com/auth/calendar.events"},
+			MaxConcurrentCalls: DefaultGoogleMaxConcurrentCalls,
+			CallWaitMs:         DefaultGoogleCallWaitMs,
+			EventCacheSize:     DefaultGoogleEventCacheSize,
 		},
 		Approval: ApprovalConfig{
-			TimeoutMinutes: DefaultApprovalTimeoutMinutes,
-			DefaultAction:  DefaultApprovalDefaultAction,
+			TimeoutMinutes:        DefaultApprovalTimeoutMinutes,
+			DefaultAction:         DefaultApprovalDefaultAction,
+			ReminderMinutes:       DefaultApprovalReminderMinutes,
+			ReminderMinAgeMinutes: DefaultApprovalReminderMinAgeMinutes,
+			ResendCooldownSeconds: DefaultApprovalResendCooldownSeconds,
+			SuggestWindowHours:    DefaultApprovalSuggestWindowHours,
+			SuggestSlotMinutes:    DefaultApprovalSuggestSlotMinutes,
+
+			MinRequestTimeoutMinutes: DefaultApprovalMinRequestTimeoutMinutes,
+			MaxRequestTimeoutMinutes: DefaultApprovalMaxRequestTimeoutMinutes,
 		},
 		RateLimits: RateLimitsConfig{
 			Read:  TierLimit{RequestsPerMinute: 60, Burst: 10},
@@ -343,7 +848,10 @@ func defaultConfig() *Config {
 		Retry: RetryConfig{
 			Enabled:              true,
 			MaxAttempts:          3,
+			Strategy:             RetryStrategyFixed,
 			BackoffSeconds:       []int{5, 10, 20},
+			BaseDelaySeconds:     DefaultRetryBaseDelaySeconds,
+			MaxDelaySeconds:      DefaultRetryMaxDelaySeconds,
 			RetryableStatusCodes: []int{429, 500, 502, 503},
 		},
 		Notifications: NotificationsConfig{
@@ -362,6 +870,7 @@ func defaultConfig() *Config {
 				Enabled:             false,
 				WebhookPath:         "/webhooks/telegram",
 				AutoRegisterWebhook: true,
+				DenyReasons:         splitList(DefaultTelegramDenyReasons),
 			},
 			Webhook: GenericWebhookConfig{
 				Enabled:        false,
@@ -376,6 +885,7 @@ func defaultConfig() *Config {
 				MaxRetries:       3,
 				RetryBackoff:     []int{1, 5, 15},
 				NotifyOn:         []string{"approved", "denied", "expired", "change_requested", "completed", "failed"},
+				BatchMaxSize:     DefaultWebhookBatchMaxSize,
 			},
 		},
 		Auth: AuthConfig{
@@ -392,6 +902,9 @@ func defaultConfig() *Config {
 			DateFormat:     "Jan 2, 2006",
 			TimeFormat:     "3:04 PM",
 			DatetimeFormat: "Jan 2, 2006 at 3:04 PM",
+			Locale:         util.DefaultLocale,
+
+			StatsWindowDays: DefaultStatsWindowDays,
 		},
 		Retention: RetentionConfig{
 			Enabled:               true,
@@ -400,6 +913,23 @@ func defaultConfig() *Config {
 			WebhookFailuresDays:   DefaultWebhookFailuresDays,
 			VacuumSchedule:        "0 3 * * *",
 		},
+		Audit: AuditConfig{
+			Webhook: AuditWebhookConfig{
+				TimeoutSeconds: 10,
+				MaxRetries:     3,
+				RetryBackoff:   []int{1, 5, 15},
+				BatchWindowMs:  DefaultAuditWebhookBatchWindowMs,
+				BatchMaxSize:   DefaultAuditWebhookBatchMaxSize,
+				QueueSize:      DefaultAuditWebhookQueueSize,
+			},
+			SettingsWebhook: AuditWebhookConfig{
+				TimeoutSeconds: 10,
+				MaxRetries:     3,
+				RetryBackoff:   []int{1, 5, 15},
+				BatchMaxSize:   DefaultAuditWebhookBatchMaxSize,
+				QueueSize:      DefaultAuditWebhookQueueSize,
+			},
+		},
 	}
 }
 
@@ -412,7 +942,10 @@ func applyEnvOverrides(cfg *Config) {
 	cfg.Server.Port = getEnvIntAny(cfg.Server.Port, "SCHEDLOCK_SERVER_PORT", "PORT")
 	cfg.Server.BaseURL = getEnvAnyDefault(cfg.Server.BaseURL, "SCHEDLOCK_BASE_URL", "BASE_URL")
 	cfg.Server.ReadTimeout = getEnvDurationAny(cfg.Server.ReadTimeout, "SCHEDLOCK_READ_TIMEOUT", "READ_TIMEOUT")
+	cfg.Server.MaxBodyBytes = int64(getEnvIntAny(int(cfg.Server.MaxBodyBytes), "SCHEDLOCK_MAX_BODY_BYTES", "MAX_BODY_BYTES"))
 	cfg.Server.WriteTimeout = getEnvDurationAny(cfg.Server.WriteTimeout, "SCHEDLOCK_WRITE_TIMEOUT", "WRITE_TIMEOUT")
+	cfg.Server.ClientIPHeaders = getEnvListAny(cfg.Server.ClientIPHeaders, "SCHEDLOCK_CLIENT_IP_HEADERS")
+	cfg.Server.TrustedProxies = getEnvListAny(cfg.Server.TrustedProxies, "SCHEDLOCK_TRUSTED_PROXIES")
 
 	dataDir := getEnvAny("SCHEDLOCK_DATA_DIR", "DATA_DIR")
 	dbName := getEnvAny("SCHEDLOCK_DB_NAME", "DB_NAME")
@@ -432,46 +965,88 @@ func applyEnvOverrides(cfg *Config) {
 		cfg.Database.Path = filepath.Join(dataDir, dbName)
 	}
 
+	cfg.Database.WALMode = getEnvBoolAny(cfg.Database.WALMode, "SCHEDLOCK_DB_WAL_MODE", "DB_WAL_MODE")
+	cfg.Database.BusyTimeoutMs = getEnvIntAny(cfg.Database.BusyTimeoutMs, "SCHEDLOCK_DB_BUSY_TIMEOUT_MS", "DB_BUSY_TIMEOUT_MS")
+	cfg.Database.MaxOpenConns = getEnvIntAny(cfg.Database.MaxOpenConns, "SCHEDLOCK_DB_MAX_OPEN_CONNS", "DB_MAX_OPEN_CONNS")
+	cfg.Database.MaxIdleConns = getEnvIntAny(cfg.Database.MaxIdleConns, "SCHEDLOCK_DB_MAX_IDLE_CONNS", "DB_MAX_IDLE_CONNS")
+	cfg.Database.ConnMaxLifetimeSeconds = getEnvIntAny(cfg.Database.ConnMaxLifetimeSeconds, "SCHEDLOCK_DB_CONN_MAX_LIFETIME_SECONDS", "DB_CONN_MAX_LIFETIME_SECONDS")
+	cfg.Database.ReadPool = getEnvBoolAny(cfg.Database.ReadPool, "SCHEDLOCK_DB_READ_POOL", "DB_READ_POOL")
+	cfg.Database.ReadPoolSize = getEnvIntAny(cfg.Database.ReadPoolSize, "SCHEDLOCK_DB_READ_POOL_SIZE", "DB_READ_POOL_SIZE")
+	cfg.Database.BackupMaxBytes = int64(getEnvIntAny(int(cfg.Database.BackupMaxBytes), "SCHEDLOCK_DB_BACKUP_MAX_BYTES", "DB_BACKUP_MAX_BYTES"))
+	cfg.Database.BackupTimeoutSeconds = getEnvIntAny(cfg.Database.BackupTimeoutSeconds, "SCHEDLOCK_DB_BACKUP_TIMEOUT_SECONDS", "DB_BACKUP_TIMEOUT_SECONDS")
+
 	cfg.Google.ClientID = getEnvAnyDefault(cfg.Google.ClientID, "SCHEDLOCK_GOOGLE_CLIENT_ID", "GOOGLE_CLIENT_ID")
 	cfg.Google.ClientSecret = getEnvAnyDefault(cfg.Google.ClientSecret, "SCHEDLOCK_GOOGLE_CLIENT_SECRET", "GOOGLE_CLIENT_SECRET")
 	cfg.Google.RedirectURI = getEnvAnyDefault(cfg.Google.RedirectURI, "SCHEDLOCK_GOOGLE_REDIRECT_URI", "GOOGLE_REDIRECT_URI")
+	cfg.Google.MaxConcurrentCalls = getEnvIntAny(cfg.Google.MaxConcurrentCalls, "SCHEDLOCK_GOOGLE_MAX_CONCURRENT_CALLS", "GOOGLE_MAX_CONCURRENT_CALLS")
+	cfg.Google.CallWaitMs = getEnvIntAny(cfg.Google.CallWaitMs, "SCHEDLOCK_GOOGLE_CALL_WAIT_MS", "GOOGLE_CALL_WAIT_MS")
+	cfg.Google.EventCacheTTLSeconds = getEnvIntAny(cfg.Google.EventCacheTTLSeconds, "SCHEDLOCK_GOOGLE_EVENT_CACHE_TTL_SECONDS")
+	cfg.Google.EventCacheSize = getEnvIntAny(cfg.Google.EventCacheSize, "SCHEDLOCK_GOOGLE_EVENT_CACHE_SIZE")
 
 	cfg.Approval.TimeoutMinutes = getEnvIntAny(cfg.Approval.TimeoutMinutes, "SCHEDLOCK_APPROVAL_TIMEOUT", "APPROVAL_TIMEOUT_MINUTES")
 	cfg.Approval.DefaultAction = getEnvAnyDefault(cfg.Approval.DefaultAction, "SCHEDLOCK_APPROVAL_DEFAULT_ACTION", "APPROVAL_DEFAULT_ACTION")
+	cfg.Approval.ReminderMinutes = getEnvIntAny(cfg.Approval.ReminderMinutes, "SCHEDLOCK_APPROVAL_REMINDER_MINUTES", "APPROVAL_REMINDER_MINUTES")
+	cfg.Approval.ReminderMinAgeMinutes = getEnvIntAny(cfg.Approval.ReminderMinAgeMinutes, "SCHEDLOCK_APPROVAL_REMINDER_MIN_AGE_MINUTES", "APPROVAL_REMINDER_MIN_AGE_MINUTES")
+	cfg.Approval.EscalationMinutes = getEnvIntAny(cfg.Approval.EscalationMinutes, "SCHEDLOCK_APPROVAL_ESCALATION_MINUTES", "APPROVAL_ESCALATION_MINUTES")
+	cfg.Approval.EscalationProvider = getEnvAnyDefault(cfg.Approval.EscalationProvider, "SCHEDLOCK_APPROVAL_ESCALATION_PROVIDER", "APPROVAL_ESCALATION_PROVIDER")
+	cfg.Approval.EscalationTarget = getEnvAnyDefault(cfg.Approval.EscalationTarget, "SCHEDLOCK_APPROVAL_ESCALATION_TARGET", "APPROVAL_ESCALATION_TARGET")
+	cfg.Approval.ResendCooldownSeconds = getEnvIntAny(cfg.Approval.ResendCooldownSeconds, "SCHEDLOCK_APPROVAL_RESEND_COOLDOWN_SECONDS", "APPROVAL_RESEND_COOLDOWN_SECONDS")
+	cfg.Approval.SuggestWindowHours = getEnvIntAny(cfg.Approval.SuggestWindowHours, "SCHEDLOCK_APPROVAL_SUGGEST_WINDOW_HOURS", "APPROVAL_SUGGEST_WINDOW_HOURS")
+	cfg.Approval.SuggestSlotMinutes = getEnvIntAny(cfg.Approval.SuggestSlotMinutes, "SCHEDLOCK_APPROVAL_SUGGEST_SLOT_MINUTES", "APPROVAL_SUGGEST_SLOT_MINUTES")
+	cfg.Approval.Approvers = getEnvListAny(cfg.Approval.Approvers, "SCHEDLOCK_APPROVERS")
+	cfg.Approval.RequireAll = getEnvBoolAny(cfg.Approval.RequireAll, "SCHEDLOCK_APPROVAL_REQUIRE_ALL")
+	cfg.Approval.MinRequestTimeoutMinutes = getEnvIntAny(cfg.Approval.MinRequestTimeoutMinutes, "SCHEDLOCK_APPROVAL_MIN_REQUEST_TIMEOUT")
+	cfg.Approval.MaxRequestTimeoutMinutes = getEnvIntAny(cfg.Approval.MaxRequestTimeoutMinutes, "SCHEDLOCK_APPROVAL_MAX_REQUEST_TIMEOUT")
 
 	cfg.RateLimits.Read.RequestsPerMinute = getEnvIntAny(cfg.RateLimits.Read.RequestsPerMinute, "SCHEDLOCK_RATE_LIMIT_READ", "RATE_LIMIT_READ")
 	cfg.RateLimits.Write.RequestsPerMinute = getEnvIntAny(cfg.RateLimits.Write.RequestsPerMinute, "SCHEDLOCK_RATE_LIMIT_WRITE", "RATE_LIMIT_WRITE")
 	cfg.RateLimits.Admin.RequestsPerMinute = getEnvIntAny(cfg.RateLimits.Admin.RequestsPerMinute, "SCHEDLOCK_RATE_LIMIT_ADMIN", "RATE_LIMIT_ADMIN")
 
+	cfg.Retry.Strategy = getEnvAnyDefault(cfg.Retry.Strategy, "SCHEDLOCK_RETRY_STRATEGY", "RETRY_STRATEGY")
+	cfg.Retry.BaseDelaySeconds = getEnvIntAny(cfg.Retry.BaseDelaySeconds, "SCHEDLOCK_RETRY_BASE_DELAY_SECONDS", "RETRY_BASE_DELAY_SECONDS")
+	cfg.Retry.MaxDelaySeconds = getEnvIntAny(cfg.Retry.MaxDelaySeconds, "SCHEDLOCK_RETRY_MAX_DELAY_SECONDS", "RETRY_MAX_DELAY_SECONDS")
+
 	cfg.Notifications.Ntfy.Enabled = getEnvBoolAny(cfg.Notifications.Ntfy.Enabled, "SCHEDLOCK_NTFY_ENABLED", "NTFY_ENABLED")
 	cfg.Notifications.Ntfy.Server = getEnvAnyDefault(cfg.Notifications.Ntfy.Server, "SCHEDLOCK_NTFY_SERVER_URL", "SCHEDLOCK_NTFY_SERVER", "NTFY_SERVER")
 	cfg.Notifications.Ntfy.Topic = getEnvAnyDefault(cfg.Notifications.Ntfy.Topic, "SCHEDLOCK_NTFY_TOPIC", "NTFY_TOPIC")
 	cfg.Notifications.Ntfy.Token = getEnvAnyDefault(cfg.Notifications.Ntfy.Token, "SCHEDLOCK_NTFY_TOKEN", "NTFY_TOKEN")
 	cfg.Notifications.Ntfy.Priority = getEnvAnyDefault(cfg.Notifications.Ntfy.Priority, "SCHEDLOCK_NTFY_PRIORITY", "NTFY_PRIORITY")
 	cfg.Notifications.Ntfy.MinimalContent = getEnvBoolAny(cfg.Notifications.Ntfy.MinimalContent, "SCHEDLOCK_NTFY_MINIMAL_CONTENT", "NTFY_MINIMAL_CONTENT")
+	cfg.Notifications.Ntfy.ResultsOn = getEnvListAny(cfg.Notifications.Ntfy.ResultsOn, "SCHEDLOCK_NTFY_RESULTS_ON")
 
 	cfg.Notifications.Pushover.Enabled = getEnvBoolAny(cfg.Notifications.Pushover.Enabled, "SCHEDLOCK_PUSHOVER_ENABLED", "PUSHOVER_ENABLED")
 	cfg.Notifications.Pushover.AppToken = getEnvAnyDefault(cfg.Notifications.Pushover.AppToken, "SCHEDLOCK_PUSHOVER_TOKEN", "SCHEDLOCK_PUSHOVER_APP_TOKEN", "PUSHOVER_APP_TOKEN")
 	cfg.Notifications.Pushover.UserKey = getEnvAnyDefault(cfg.Notifications.Pushover.UserKey, "SCHEDLOCK_PUSHOVER_USER_KEY", "PUSHOVER_USER_KEY")
 	cfg.Notifications.Pushover.Priority = getEnvIntAny(cfg.Notifications.Pushover.Priority, "SCHEDLOCK_PUSHOVER_PRIORITY", "PUSHOVER_PRIORITY")
 	cfg.Notifications.Pushover.Sound = getEnvAnyDefault(cfg.Notifications.Pushover.Sound, "SCHEDLOCK_PUSHOVER_SOUND", "PUSHOVER_SOUND")
+	cfg.Notifications.Pushover.ResultsOn = getEnvListAny(cfg.Notifications.Pushover.ResultsOn, "SCHEDLOCK_PUSHOVER_RESULTS_ON")
 
 	cfg.Notifications.Telegram.Enabled = getEnvBoolAny(cfg.Notifications.Telegram.Enabled, "SCHEDLOCK_TELEGRAM_ENABLED", "TELEGRAM_ENABLED")
 	cfg.Notifications.Telegram.BotToken = getEnvAnyDefault(cfg.Notifications.Telegram.BotToken, "SCHEDLOCK_TELEGRAM_BOT_TOKEN", "TELEGRAM_BOT_TOKEN")
 	cfg.Notifications.Telegram.ChatID = getEnvAnyDefault(cfg.Notifications.Telegram.ChatID, "SCHEDLOCK_TELEGRAM_CHAT_ID", "TELEGRAM_CHAT_ID")
 	cfg.Notifications.Telegram.WebhookSecret = getEnvAnyDefault(cfg.Notifications.Telegram.WebhookSecret, "SCHEDLOCK_TELEGRAM_WEBHOOK_SECRET", "TELEGRAM_WEBHOOK_SECRET")
 	cfg.Notifications.Telegram.AutoRegisterWebhook = getEnvBoolAny(cfg.Notifications.Telegram.AutoRegisterWebhook, "SCHEDLOCK_TELEGRAM_AUTO_REGISTER_WEBHOOK", "TELEGRAM_AUTO_REGISTER_WEBHOOK")
+	cfg.Notifications.Telegram.DenyReasons = getEnvListAny(cfg.Notifications.Telegram.DenyReasons, "SCHEDLOCK_TELEGRAM_DENY_REASONS", "TELEGRAM_DENY_REASONS")
+	cfg.Notifications.Telegram.ResultsOn = getEnvListAny(cfg.Notifications.Telegram.ResultsOn, "SCHEDLOCK_TELEGRAM_RESULTS_ON")
 
 	cfg.Notifications.Webhook.Enabled = getEnvBoolAny(cfg.Notifications.Webhook.Enabled, "SCHEDLOCK_WEBHOOK_ENABLED", "WEBHOOK_ENABLED")
 	cfg.Notifications.Webhook.URL = getEnvAnyDefault(cfg.Notifications.Webhook.URL, "SCHEDLOCK_WEBHOOK_URL", "WEBHOOK_URL")
 	cfg.Notifications.Webhook.Secret = getEnvAnyDefault(cfg.Notifications.Webhook.Secret, "SCHEDLOCK_WEBHOOK_SECRET", "WEBHOOK_SECRET")
 	cfg.Notifications.Webhook.TimeoutSeconds = getEnvIntAny(cfg.Notifications.Webhook.TimeoutSeconds, "SCHEDLOCK_WEBHOOK_TIMEOUT", "WEBHOOK_TIMEOUT")
+	cfg.Notifications.Webhook.ResultsOn = getEnvListAny(cfg.Notifications.Webhook.ResultsOn, "SCHEDLOCK_WEBHOOK_RESULTS_ON")
 
 	cfg.Moltbot.Webhook.Enabled = getEnvBoolAny(cfg.Moltbot.Webhook.Enabled, "SCHEDLOCK_MOLTBOT_WEBHOOK_ENABLED", "MOLTBOT_WEBHOOK_ENABLED")
 	cfg.Moltbot.Webhook.URL = getEnvAnyDefault(cfg.Moltbot.Webhook.URL, "SCHEDLOCK_MOLTBOT_WEBHOOK_URL", "MOLTBOT_WEBHOOK_URL")
 	cfg.Moltbot.Webhook.Token = getEnvAnyDefault(cfg.Moltbot.Webhook.Token, "SCHEDLOCK_MOLTBOT_WEBHOOK_SECRET", "SCHEDLOCK_MOLTBOT_WEBHOOK_TOKEN", "MOLTBOT_WEBHOOK_TOKEN")
 	cfg.Moltbot.Webhook.TimeoutSeconds = getEnvIntAny(cfg.Moltbot.Webhook.TimeoutSeconds, "SCHEDLOCK_MOLTBOT_WEBHOOK_TIMEOUT", "MOLTBOT_WEBHOOK_TIMEOUT")
 	cfg.Moltbot.Webhook.MaxRetries = getEnvIntAny(cfg.Moltbot.Webhook.MaxRetries, "SCHEDLOCK_MOLTBOT_WEBHOOK_MAX_RETRIES", "MOLTBOT_WEBHOOK_MAX_RETRIES")
+	cfg.Moltbot.Webhook.ClientCertFile = getEnvAnyDefault(cfg.Moltbot.Webhook.ClientCertFile, "SCHEDLOCK_MOLTBOT_WEBHOOK_CLIENT_CERT", "MOLTBOT_WEBHOOK_CLIENT_CERT")
+	cfg.Moltbot.Webhook.ClientKeyFile = getEnvAnyDefault(cfg.Moltbot.Webhook.ClientKeyFile, "SCHEDLOCK_MOLTBOT_WEBHOOK_CLIENT_KEY", "MOLTBOT_WEBHOOK_CLIENT_KEY")
+	cfg.Moltbot.Webhook.CACertFile = getEnvAnyDefault(cfg.Moltbot.Webhook.CACertFile, "SCHEDLOCK_MOLTBOT_WEBHOOK_CA_CERT", "MOLTBOT_WEBHOOK_CA_CERT")
+	cfg.Moltbot.Webhook.BatchWindowMs = getEnvIntAny(cfg.Moltbot.Webhook.BatchWindowMs, "SCHEDLOCK_MOLTBOT_WEBHOOK_BATCH_WINDOW_MS", "MOLTBOT_WEBHOOK_BATCH_WINDOW_MS")
+	cfg.Moltbot.Webhook.BatchMaxSize = getEnvIntAny(cfg.Moltbot.Webhook.BatchMaxSize, "SCHEDLOCK_MOLTBOT_WEBHOOK_BATCH_MAX_SIZE", "MOLTBOT_WEBHOOK_BATCH_MAX_SIZE")
+	cfg.Moltbot.Webhook.IncludeDiff = getEnvBoolAny(cfg.Moltbot.Webhook.IncludeDiff, "SCHEDLOCK_MOLTBOT_WEBHOOK_INCLUDE_DIFF", "MOLTBOT_WEBHOOK_INCLUDE_DIFF")
+	cfg.Moltbot.Webhook.DebounceMs = getEnvIntAny(cfg.Moltbot.Webhook.DebounceMs, "SCHEDLOCK_MOLTBOT_WEBHOOK_DEBOUNCE_MS", "MOLTBOT_WEBHOOK_DEBOUNCE_MS")
 
 	cfg.Auth.AdminPasswordHash = getEnvAnyDefault(cfg.Auth.AdminPasswordHash, "SCHEDLOCK_AUTH_PASSWORD_HASH", "ADMIN_PASSWORD_HASH")
 	cfg.Auth.AdminPassword = getEnvAnyDefault(cfg.Auth.AdminPassword, "SCHEDLOCK_ADMIN_PASSWORD", "ADMIN_PASSWORD")
@@ -479,6 +1054,7 @@ func applyEnvOverrides(cfg *Config) {
 	cfg.Auth.EncryptionKey = getEnvAnyDefault(cfg.Auth.EncryptionKey, "SCHEDLOCK_ENCRYPTION_KEY", "ENCRYPTION_KEY")
 	cfg.Auth.SessionDuration = getEnvDurationAny(cfg.Auth.SessionDuration, "SCHEDLOCK_SESSION_DURATION", "SESSION_DURATION")
 	cfg.Auth.SessionRefresh = getEnvBoolAny(cfg.Auth.SessionRefresh, "SCHEDLOCK_SESSION_REFRESH", "SESSION_REFRESH")
+	cfg.Auth.SessionIdleTimeout = getEnvDurationAny(cfg.Auth.SessionIdleTimeout, "SCHEDLOCK_SESSION_IDLE_TIMEOUT", "SESSION_IDLE_TIMEOUT")
 	cfg.Auth.CloudflareAccess.Enabled = getEnvBoolAny(cfg.Auth.CloudflareAccess.Enabled, "SCHEDLOCK_CF_ACCESS_ENABLED", "CF_ACCESS_ENABLED")
 	cfg.Auth.CloudflareAccess.Team = getEnvAnyDefault(cfg.Auth.CloudflareAccess.Team, "SCHEDLOCK_CF_ACCESS_TEAM", "CF_ACCESS_TEAM")
 	cfg.Auth.CloudflareAccess.Aud = getEnvAnyDefault(cfg.Auth.CloudflareAccess.Aud, "SCHEDLOCK_CF_ACCESS_AUD", "CF_ACCESS_AUD")
@@ -487,10 +1063,31 @@ func applyEnvOverrides(cfg *Config) {
 	cfg.Logging.Format = getEnvAnyDefault(cfg.Logging.Format, "SCHEDLOCK_LOG_FORMAT", "LOG_FORMAT")
 
 	cfg.Display.Timezone = getEnvAnyDefault(cfg.Display.Timezone, "SCHEDLOCK_DISPLAY_TIMEZONE", "DISPLAY_TIMEZONE")
+	cfg.Display.Locale = getEnvAnyDefault(cfg.Display.Locale, "SCHEDLOCK_DISPLAY_LOCALE", "DISPLAY_LOCALE")
+	cfg.Display.RelativeTime = getEnvBoolAny(cfg.Display.RelativeTime, "SCHEDLOCK_DISPLAY_RELATIVE_TIME", "DISPLAY_RELATIVE_TIME")
+	cfg.Display.StatsWindowDays = getEnvIntAny(cfg.Display.StatsWindowDays, "SCHEDLOCK_STATS_WINDOW_DAYS", "STATS_WINDOW_DAYS")
 
 	cfg.Retention.CompletedRequestsDays = getEnvIntAny(cfg.Retention.CompletedRequestsDays, "SCHEDLOCK_RETENTION_REQUEST_DAYS", "RETENTION_COMPLETED_DAYS")
 	cfg.Retention.AuditLogDays = getEnvIntAny(cfg.Retention.AuditLogDays, "SCHEDLOCK_RETENTION_AUDIT_DAYS", "RETENTION_AUDIT_DAYS")
 	cfg.Retention.WebhookFailuresDays = getEnvIntAny(cfg.Retention.WebhookFailuresDays, "SCHEDLOCK_RETENTION_WEBHOOK_FAILURES_DAYS", "RETENTION_WEBHOOK_FAILURES_DAYS")
+	cfg.Retention.UnclaimedResultsHours = getEnvIntAny(cfg.Retention.UnclaimedResultsHours, "SCHEDLOCK_RETENTION_UNCLAIMED_HOURS")
+
+	cfg.Audit.Webhook.URL = getEnvAnyDefault(cfg.Audit.Webhook.URL, "SCHEDLOCK_AUDIT_WEBHOOK_URL")
+	cfg.Audit.Webhook.Secret = getEnvAnyDefault(cfg.Audit.Webhook.Secret, "SCHEDLOCK_AUDIT_WEBHOOK_SECRET")
+	cfg.Audit.Webhook.TimeoutSeconds = getEnvIntAny(cfg.Audit.Webhook.TimeoutSeconds, "SCHEDLOCK_AUDIT_WEBHOOK_TIMEOUT")
+	cfg.Audit.Webhook.MaxRetries = getEnvIntAny(cfg.Audit.Webhook.MaxRetries, "SCHEDLOCK_AUDIT_WEBHOOK_MAX_RETRIES")
+	cfg.Audit.Webhook.BatchWindowMs = getEnvIntAny(cfg.Audit.Webhook.BatchWindowMs, "SCHEDLOCK_AUDIT_WEBHOOK_BATCH_WINDOW_MS")
+	cfg.Audit.Webhook.BatchMaxSize = getEnvIntAny(cfg.Audit.Webhook.BatchMaxSize, "SCHEDLOCK_AUDIT_WEBHOOK_BATCH_MAX_SIZE")
+	cfg.Audit.Webhook.QueueSize = getEnvIntAny(cfg.Audit.Webhook.QueueSize, "SCHEDLOCK_AUDIT_WEBHOOK_QUEUE_SIZE")
+	cfg.Audit.SettingsWebhook.URL = getEnvAnyDefault(cfg.Audit.SettingsWebhook.URL, "SCHEDLOCK_SETTINGS_WEBHOOK_URL")
+	cfg.Audit.SettingsWebhook.Secret = getEnvAnyDefault(cfg.Audit.SettingsWebhook.Secret, "SCHEDLOCK_SETTINGS_WEBHOOK_SECRET")
+	cfg.Audit.SettingsWebhook.TimeoutSeconds = getEnvIntAny(cfg.Audit.SettingsWebhook.TimeoutSeconds, "SCHEDLOCK_SETTINGS_WEBHOOK_TIMEOUT")
+	cfg.Audit.SettingsWebhook.MaxRetries = getEnvIntAny(cfg.Audit.SettingsWebhook.MaxRetries, "SCHEDLOCK_SETTINGS_WEBHOOK_MAX_RETRIES")
+
+	cfg.EventDefaults.DescriptionSuffix = getEnvAnyDefault(cfg.EventDefaults.DescriptionSuffix, "SCHEDLOCK_EVENT_DEFAULT_DESCRIPTION_SUFFIX")
+	cfg.EventDefaults.Location = getEnvAnyDefault(cfg.EventDefaults.Location, "SCHEDLOCK_EVENT_DEFAULT_LOCATION")
+	cfg.EventDefaults.ColorID = getEnvAnyDefault(cfg.EventDefaults.ColorID, "SCHEDLOCK_EVENT_DEFAULT_COLOR")
+	cfg.EventDefaults.Reminders = getEnvListAny(cfg.EventDefaults.Reminders, "SCHEDLOCK_EVENT_DEFAULT_REMINDERS")
 }
 
 // IsFirstRun checks if this is the first run (no password hash configured).
@@ -50,3 +50,95 @@ logging:
 		t.Fatalf("unexpected redirect uri: %s", cfg.Google.RedirectURI)
 	}
 }
+
+func TestApprovalConfigApprover(t *testing.T) {
+	approval := ApprovalConfig{Approvers: []string{"Dana Lee", "sam@example.com"}}
+
+	if got, ok := approval.Approver("  dana lee "); !ok || got != "Dana Lee" {
+		t.Fatalf("expected Dana Lee, got %q %v", got, ok)
+	}
+	if _, ok := approval.Approver("Mallory"); ok {
+		t.Fatal("expected unknown approver to be rejected")
+	}
+	if _, ok := approval.Approver(""); ok {
+		t.Fatal("expected empty approver to be rejected")
+	}
+}
+
+func TestValidateApprovalRoutes(t *testing.T) {
+	valid := []ApprovalRoute{
+		{Calendar: "ceo@example.com", Provider: "telegram", Target: "-100123"},
+		{Calendar: "*@group.calendar.google.com", Provider: "ntfy", Target: "team"},
+	}
+	if err := ValidateApprovalRoutes(valid); err != nil {
+		t.Fatalf("expected routes to be valid: %v", err)
+	}
+
+	for _, route := range []ApprovalRoute{
+		{Calendar: "", Provider: "telegram", Target: "-100123"},
+		{Calendar: "[team", Provider: "telegram", Target: "-100123"},
+		{Calendar: "ceo@example.com", Provider: "webhook", Target: "https://example.com"},
+		{Calendar: "ceo@example.com", Provider: "pushover", Target: ""},
+	} {
+		if err := ValidateApprovalRoutes([]ApprovalRoute{route}); err == nil {
+			t.Errorf("expected %+v to be rejected", route)
+		}
+	}
+}
+
+func TestWebhookDestinations(t *testing.T) {
+	webhook := WebhookConfig{
+		URL:      "https://moltbot.example.com/hook",
+		Token:    "main-secret",
+		NotifyOn: []string{"approved", "denied"},
+		Destinations: []WebhookDestination{
+			{Name: "audit", URL: "https://audit.example.com/in", Token: "audit-secret", NotifyOn: []string{"completed"}},
+			{Name: "mirror", URL: "https://mirror.example.com/in"},
+		},
+	}
+	if err := webhook.validateDestinations(); err != nil {
+		t.Fatalf("expected destinations to be valid: %v", err)
+	}
+
+	all := webhook.AllDestinations()
+	if len(all) != 3 || all[0].Name != DefaultWebhookDestination || all[0].Token != "main-secret" {
+		t.Fatalf("AllDestinations() = %+v", all)
+	}
+	if all[1].Wants("approved") || !all[1].Wants("completed") {
+		t.Errorf("audit filter = %v", all[1].NotifyOn)
+	}
+	if !all[2].Wants("denied") || all[2].Wants("completed") {
+		t.Errorf("mirror should inherit the default filter, got %v", all[2].NotifyOn)
+	}
+	if !webhook.NotifiesAbout("completed") || webhook.NotifiesAbout("failed") {
+		t.Error("NotifiesAbout should combine the destination filters")
+	}
+
+	for _, d := range []WebhookDestination{
+		{URL: "https://audit.example.com/in"},
+		{Name: "default", URL: "https://audit.example.com/in"},
+		{Name: "audit", URL: "ftp://audit.example.com/in"},
+	} {
+		bad := webhook
+		bad.Destinations = append([]WebhookDestination{d}, webhook.Destinations...)
+		if err := bad.validateDestinations(); err == nil {
+			t.Errorf("expected %+v to be rejected", d)
+		}
+	}
+}
+
+func TestValidateRequiresTrustedProxiesForClientIPHeaders(t *testing.T) {
+	t.Setenv("SCHEDLOCK_SERVER_SECRET", "test-secret")
+	t.Setenv("SCHEDLOCK_ENCRYPTION_KEY", "test-encryption")
+	t.Setenv("SCHEDLOCK_AUTH_PASSWORD_HASH", "argon2id$fake")
+	t.Setenv("SCHEDLOCK_CLIENT_IP_HEADERS", "CF-Connecting-IP")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected client IP headers without trusted proxies to be rejected")
+	}
+
+	t.Setenv("SCHEDLOCK_TRUSTED_PROXIES", "173.245.48.0/20")
+	if _, err := Load(); err != nil {
+		t.Fatalf("Load with trusted proxies: %v", err)
+	}
+}
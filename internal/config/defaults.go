@@ -10,18 +10,47 @@ const (
 	DefaultBaseURL      = "http://localhost:8080"
 	DefaultReadTimeout  = 30 * time.Second
 	DefaultWriteTimeout = 30 * time.Second
+	DefaultMaxBodyBytes = 1 << 20 // 1 MiB
 )
 
 // Database defaults
 const (
-	DefaultDataDir       = "/data"
-	DefaultBusyTimeoutMs = 5000
+	DefaultDataDir              = "/data"
+	DefaultBusyTimeoutMs        = 5000
+	DefaultReadPoolSize         = 4
+	DefaultBackupMaxBytes       = 1 << 30 // 1 GiB
+	DefaultBackupTimeoutSeconds = 120
+	DefaultMaxIdleConns         = 2
+)
+
+// Google API defaults
+const (
+	DefaultGoogleMaxConcurrentCalls = 4
+	DefaultGoogleCallWaitMs         = 5000
+	DefaultGoogleEventCacheSize     = 500
 )
 
 // Approval defaults
 const (
-	DefaultApprovalTimeoutMinutes = 60
-	DefaultApprovalDefaultAction  = "deny"
+	DefaultApprovalTimeoutMinutes        = 60
+	DefaultApprovalDefaultAction         = "deny"
+	DefaultApprovalReminderMinutes       = 0 // reminders are opt-in
+	DefaultApprovalReminderMinAgeMinutes = 5
+	DefaultApprovalResendCooldownSeconds = 60
+	DefaultApprovalSuggestWindowHours    = 24
+	DefaultApprovalSuggestSlotMinutes    = 30
+
+	// Bounds on X-Approval-Timeout-Minutes
+	DefaultApprovalMinRequestTimeoutMinutes = 1
+	DefaultApprovalMaxRequestTimeoutMinutes = 1440
+)
+
+// Retry defaults
+const (
+	RetryStrategyFixed           = "fixed"
+	RetryStrategyExponential     = "exponential"
+	DefaultRetryBaseDelaySeconds = 2
+	DefaultRetryMaxDelaySeconds  = 60
 )
 
 // Auth defaults
@@ -36,7 +65,8 @@ const (
 
 // Display defaults
 const (
-	DefaultTimezone = "America/New_York"
+	DefaultTimezone        = "America/New_York"
+	DefaultStatsWindowDays = 7
 )
 
 // Retention defaults
@@ -45,3 +75,29 @@ const (
 	DefaultAuditLogDays          = 365
 	DefaultWebhookFailuresDays   = 30
 )
+
+// Moltbot webhook defaults
+const (
+	DefaultWebhookBatchMaxSize = 50
+)
+
+// Audit webhook defaults
+const (
+	DefaultAuditWebhookBatchWindowMs = 1000
+	DefaultAuditWebhookBatchMaxSize  = 100
+	DefaultAuditWebhookQueueSize     = 1000
+)
+
+// Request statuses a provider can send result messages for
+const (
+	ResultStatusCompleted = "completed"
+	ResultStatusFailed    = "failed"
+	ResultStatusDenied    = "denied"
+)
+
+// Telegram defaults
+const (
+	DefaultTelegramDenyReasons  = "conflict,wrong attendees" // comma-separated
+	MaxTelegramDenyReasons      = 6
+	MaxTelegramDenyReasonLength = 40
+)
@@ -53,6 +53,8 @@ type ConfigFile struct {
 	Logging       *LoggingConfigFile       `yaml:"logging"`
 	Display       *DisplayConfigFile       `yaml:"display"`
 	Retention     *RetentionConfigFile     `yaml:"retention"`
+	Audit         *AuditConfigFile         `yaml:"audit"`
+	EventDefaults *EventDefaultsConfigFile `yaml:"event_defaults"`
 }
 
 type ServerConfigFile struct {
@@ -61,24 +63,62 @@ type ServerConfigFile struct {
 	BaseURL      *string       `yaml:"base_url"`
 	ReadTimeout  *fileDuration `yaml:"read_timeout"`
 	WriteTimeout *fileDuration `yaml:"write_timeout"`
+	MaxBodyBytes *int64        `yaml:"max_body_bytes"`
+
+	ClientIPHeaders *[]string `yaml:"client_ip_headers"`
+	TrustedProxies  *[]string `yaml:"trusted_proxies"`
 }
 
 type DatabaseConfigFile struct {
-	Path          *string `yaml:"path"`
-	WALMode       *bool   `yaml:"wal_mode"`
-	BusyTimeoutMs *int    `yaml:"busy_timeout_ms"`
+	Path                 *string `yaml:"path"`
+	WALMode              *bool   `yaml:"wal_mode"`
+	BusyTimeoutMs        *int    `yaml:"busy_timeout_ms"`
+	ReadPool             *bool   `yaml:"read_pool"`
+	ReadPoolSize         *int    `yaml:"read_pool_size"`
+	BackupMaxBytes       *int64  `yaml:"backup_max_bytes"`
+	BackupTimeoutSeconds *int    `yaml:"backup_timeout_seconds"`
+
+	MaxOpenConns           *int `yaml:"max_open_conns"`
+	MaxIdleConns           *int `yaml:"max_idle_conns"`
+	ConnMaxLifetimeSeconds *int `yaml:"conn_max_lifetime_seconds"`
 }
 
 type GoogleConfigFile struct {
-	ClientID     *string   `yaml:"client_id"`
-	ClientSecret *string   `yaml:"client_secret"`
-	RedirectURI  *string   `yaml:"redirect_uri"`
-	Scopes       *[]string `yaml:"scopes"`
+	ClientID           *string   `yaml:"client_id"`
+	ClientSecret       *string   `yaml:"client_secret"`
+	RedirectURI        *string   `yaml:"redirect_uri"`
+	Scopes             *[]string `yaml:"scopes"`
+	MaxConcurrentCalls *int      `yaml:"max_concurrent_calls"`
+	CallWaitMs         *int      `yaml:"call_wait_ms"`
+
+	EventCacheTTLSeconds *int `yaml:"event_cache_ttl_seconds"`
+	EventCacheSize       *int `yaml:"event_cache_size"`
 }
 
 type ApprovalConfigFile struct {
-	TimeoutMinutes *int    `yaml:"timeout_minutes"`
-	DefaultAction  *string `yaml:"default_action"`
+	TimeoutMinutes        *int    `yaml:"timeout_minutes"`
+	DefaultAction         *string `yaml:"default_action"`
+	ReminderMinutes       *int    `yaml:"reminder_minutes"`
+	ReminderMinAgeMinutes *int    `yaml:"reminder_min_age_minutes"`
+	EscalationMinutes     *int    `yaml:"escalation_minutes"`
+	EscalationProvider    *string `yaml:"escalation_provider"`
+	EscalationTarget      *string `yaml:"escalation_target"`
+	ResendCooldownSeconds *int    `yaml:"resend_cooldown_seconds"`
+	SuggestWindowHours    *int    `yaml:"suggest_window_hours"`
+	SuggestSlotMinutes    *int    `yaml:"suggest_slot_minutes"`
+
+	Approvers  *[]string            `yaml:"approvers"`
+	Routes     *[]ApprovalRouteFile `yaml:"routes"`
+	RequireAll *bool                `yaml:"require_all"`
+
+	MinRequestTimeoutMinutes *int `yaml:"min_request_timeout_minutes"`
+	MaxRequestTimeoutMinutes *int `yaml:"max_request_timeout_minutes"`
+}
+
+type ApprovalRouteFile struct {
+	Calendar string `yaml:"calendar"`
+	Provider string `yaml:"provider"`
+	Target   string `yaml:"target"`
 }
 
 type TierLimitFile struct {
@@ -93,10 +133,13 @@ type RateLimitsConfigFile struct {
 }
 
 type RetryConfigFile struct {
-	Enabled              *bool  `yaml:"enabled"`
-	MaxAttempts          *int   `yaml:"max_attempts"`
-	BackoffSeconds       *[]int `yaml:"backoff_seconds"`
-	RetryableStatusCodes *[]int `yaml:"retryable_status_codes"`
+	Enabled              *bool   `yaml:"enabled"`
+	MaxAttempts          *int    `yaml:"max_attempts"`
+	Strategy             *string `yaml:"strategy"`
+	BackoffSeconds       *[]int  `yaml:"backoff_seconds"`
+	BaseDelaySeconds     *int    `yaml:"base_delay_seconds"`
+	MaxDelaySeconds      *int    `yaml:"max_delay_seconds"`
+	RetryableStatusCodes *[]int  `yaml:"retryable_status_codes"`
 }
 
 type NtfyConfigFile struct {
@@ -106,6 +149,8 @@ type NtfyConfigFile struct {
 	Token          *string `yaml:"token"`
 	Priority       *string `yaml:"priority"`
 	MinimalContent *bool   `yaml:"minimal_content"`
+
+	ResultsOn *[]string `yaml:"results_on"`
 }
 
 type PushoverConfigFile struct {
@@ -114,6 +159,8 @@ type PushoverConfigFile struct {
 	UserKey  *string `yaml:"user_key"`
 	Priority *int    `yaml:"priority"`
 	Sound    *string `yaml:"sound"`
+
+	ResultsOn *[]string `yaml:"results_on"`
 }
 
 type TelegramConfigFile struct {
@@ -123,6 +170,9 @@ type TelegramConfigFile struct {
 	WebhookSecret       *string `yaml:"webhook_secret"`
 	WebhookPath         *string `yaml:"webhook_path"`
 	AutoRegisterWebhook *bool   `yaml:"auto_register_webhook"`
+
+	DenyReasons *[]string `yaml:"deny_reasons"`
+	ResultsOn   *[]string `yaml:"results_on"`
 }
 
 type NotificationsConfigFile struct {
@@ -140,6 +190,22 @@ type WebhookConfigFile struct {
 	MaxRetries       *int      `yaml:"max_retries"`
 	RetryBackoff     *[]int    `yaml:"retry_backoff"`
 	NotifyOn         *[]string `yaml:"notify_on"`
+	ClientCertFile   *string   `yaml:"client_cert_file"`
+	ClientKeyFile    *string   `yaml:"client_key_file"`
+	CACertFile       *string   `yaml:"ca_cert_file"`
+	BatchWindowMs    *int      `yaml:"batch_window_ms"`
+	BatchMaxSize     *int      `yaml:"batch_max_size"`
+	IncludeDiff      *bool     `yaml:"include_diff"`
+	DebounceMs       *int      `yaml:"debounce_ms"`
+
+	Destinations *[]WebhookDestinationFile `yaml:"destinations"`
+}
+
+type WebhookDestinationFile struct {
+	Name     string   `yaml:"name"`
+	URL      string   `yaml:"url"`
+	Token    string   `yaml:"token"`
+	NotifyOn []string `yaml:"notify_on"`
 }
 
 type MoltbotConfigFile struct {
@@ -153,13 +219,14 @@ type CloudflareAccessConfigFile struct {
 }
 
 type AuthConfigFile struct {
-	AdminPasswordHash *string                     `yaml:"admin_password_hash"`
-	AdminPassword     *string                     `yaml:"admin_password"`
-	SecretKey         *string                     `yaml:"secret_key"`
-	EncryptionKey     *string                     `yaml:"encryption_key"`
-	SessionDuration   *fileDuration               `yaml:"session_duration"`
-	SessionRefresh    *bool                       `yaml:"session_refresh"`
-	CloudflareAccess  *CloudflareAccessConfigFile `yaml:"cloudflare_access"`
+	AdminPasswordHash  *string                     `yaml:"admin_password_hash"`
+	AdminPassword      *string                     `yaml:"admin_password"`
+	SecretKey          *string                     `yaml:"secret_key"`
+	EncryptionKey      *string                     `yaml:"encryption_key"`
+	SessionDuration    *fileDuration               `yaml:"session_duration"`
+	SessionRefresh     *bool                       `yaml:"session_refresh"`
+	SessionIdleTimeout *fileDuration               `yaml:"session_idle_timeout"`
+	CloudflareAccess   *CloudflareAccessConfigFile `yaml:"cloudflare_access"`
 }
 
 type LoggingConfigFile struct {
@@ -173,6 +240,10 @@ type DisplayConfigFile struct {
 	DateFormat     *string `yaml:"date_format"`
 	TimeFormat     *string `yaml:"time_format"`
 	DatetimeFormat *string `yaml:"datetime_format"`
+	Locale         *string `yaml:"locale"`
+	RelativeTime   *bool   `yaml:"relative_time"`
+
+	StatsWindowDays *int `yaml:"stats_window_days"`
 }
 
 type RetentionConfigFile struct {
@@ -181,6 +252,30 @@ type RetentionConfigFile struct {
 	AuditLogDays          *int    `yaml:"audit_log_days"`
 	WebhookFailuresDays   *int    `yaml:"webhook_failures_days"`
 	VacuumSchedule        *string `yaml:"vacuum_schedule"`
+	UnclaimedResultsHours *int    `yaml:"unclaimed_results_hours"`
+}
+
+type AuditWebhookConfigFile struct {
+	URL            *string `yaml:"url"`
+	Secret         *string `yaml:"secret"`
+	TimeoutSeconds *int    `yaml:"timeout_seconds"`
+	MaxRetries     *int    `yaml:"max_retries"`
+	RetryBackoff   *[]int  `yaml:"retry_backoff"`
+	BatchWindowMs  *int    `yaml:"batch_window_ms"`
+	BatchMaxSize   *int    `yaml:"batch_max_size"`
+	QueueSize      *int    `yaml:"queue_size"`
+}
+
+type AuditConfigFile struct {
+	Webhook         *AuditWebhookConfigFile `yaml:"webhook"`
+	SettingsWebhook *AuditWebhookConfigFile `yaml:"settings_webhook"`
+}
+
+type EventDefaultsConfigFile struct {
+	DescriptionSuffix *string   `yaml:"description_suffix"`
+	Location          *string   `yaml:"location"`
+	ColorID           *string   `yaml:"color_id"`
+	Reminders         *[]string `yaml:"reminders"`
 }
 
 func loadConfigFile(cfg *Config, path string) error {
@@ -230,6 +325,15 @@ func applyConfigFile(cfg *Config, file *ConfigFile) {
 		if file.Server.WriteTimeout != nil {
 			cfg.Server.WriteTimeout = time.Duration(*file.Server.WriteTimeout)
 		}
+		if file.Server.MaxBodyBytes != nil {
+			cfg.Server.MaxBodyBytes = *file.Server.MaxBodyBytes
+		}
+		if file.Server.ClientIPHeaders != nil {
+			cfg.Server.ClientIPHeaders = *file.Server.ClientIPHeaders
+		}
+		if file.Server.TrustedProxies != nil {
+			cfg.Server.TrustedProxies = *file.Server.TrustedProxies
+		}
 	}
 
 	if file.Database != nil {
@@ -242,6 +346,27 @@ func applyConfigFile(cfg *Config, file *ConfigFile) {
 		if file.Database.BusyTimeoutMs != nil {
 			cfg.Database.BusyTimeoutMs = *file.Database.BusyTimeoutMs
 		}
+		if file.Database.ReadPool != nil {
+			cfg.Database.ReadPool = *file.Database.ReadPool
+		}
+		if file.Database.ReadPoolSize != nil {
+			cfg.Database.ReadPoolSize = *file.Database.ReadPoolSize
+		}
+		if file.Database.BackupMaxBytes != nil {
+			cfg.Database.BackupMaxBytes = *file.Database.BackupMaxBytes
+		}
+		if file.Database.BackupTimeoutSeconds != nil {
+			cfg.Database.BackupTimeoutSeconds = *file.Database.BackupTimeoutSeconds
+		}
+		if file.Database.MaxOpenConns != nil {
+			cfg.Database.MaxOpenConns = *file.Database.MaxOpenConns
+		}
+		if file.Database.MaxIdleConns != nil {
+			cfg.Database.MaxIdleConns = *file.Database.MaxIdleConns
+		}
+		if file.Database.ConnMaxLifetimeSeconds != nil {
+			cfg.Database.ConnMaxLifetimeSeconds = *file.Database.ConnMaxLifetimeSeconds
+		}
 	}
 
 	if file.Google != nil {
@@ -257,6 +382,18 @@ func applyConfigFile(cfg *Config, file *ConfigFile) {
 		if file.Google.Scopes != nil {
 			cfg.Google.Scopes = *file.Google.Scopes
 		}
+		if file.Google.MaxConcurrentCalls != nil {
+			cfg.Google.MaxConcurrentCalls = *file.Google.MaxConcurrentCalls
+		}
+		if file.Google.CallWaitMs != nil {
+			cfg.Google.CallWaitMs = *file.Google.CallWaitMs
+		}
+		if file.Google.EventCacheTTLSeconds != nil {
+			cfg.Google.EventCacheTTLSeconds = *file.Google.EventCacheTTLSeconds
+		}
+		if file.Google.EventCacheSize != nil {
+			cfg.Google.EventCacheSize = *file.Google.EventCacheSize
+		}
 	}
 
 	if file.Approval != nil {
@@ -266,6 +403,52 @@ func applyConfigFile(cfg *Config, file *ConfigFile) {
 		if file.Approval.DefaultAction != nil {
 			cfg.Approval.DefaultAction = *file.Approval.DefaultAction
 		}
+		if file.Approval.ReminderMinutes != nil {
+			cfg.Approval.ReminderMinutes = *file.Approval.ReminderMinutes
+		}
+		if file.Approval.ReminderMinAgeMinutes != nil {
+			cfg.Approval.ReminderMinAgeMinutes = *file.Approval.ReminderMinAgeMinutes
+		}
+		if file.Approval.EscalationMinutes != nil {
+			cfg.Approval.EscalationMinutes = *file.Approval.EscalationMinutes
+		}
+		if file.Approval.EscalationProvider != nil {
+			cfg.Approval.EscalationProvider = *file.Approval.EscalationProvider
+		}
+		if file.Approval.EscalationTarget != nil {
+			cfg.Approval.EscalationTarget = *file.Approval.EscalationTarget
+		}
+		if file.Approval.ResendCooldownSeconds != nil {
+			cfg.Approval.ResendCooldownSeconds = *file.Approval.ResendCooldownSeconds
+		}
+		if file.Approval.SuggestWindowHours != nil {
+			cfg.Approval.SuggestWindowHours = *file.Approval.SuggestWindowHours
+		}
+		if file.Approval.SuggestSlotMinutes != nil {
+			cfg.Approval.SuggestSlotMinutes = *file.Approval.SuggestSlotMinutes
+		}
+		if file.Approval.Approvers != nil {
+			cfg.Approval.Approvers = *file.Approval.Approvers
+		}
+		if file.Approval.RequireAll != nil {
+			cfg.Approval.RequireAll = *file.Approval.RequireAll
+		}
+		if file.Approval.MinRequestTimeoutMinutes != nil {
+			cfg.Approval.MinRequestTimeoutMinutes = *file.Approval.MinRequestTimeoutMinutes
+		}
+		if file.Approval.MaxRequestTimeoutMinutes != nil {
+			cfg.Approval.MaxRequestTimeoutMinutes = *file.Approval.MaxRequestTimeoutMinutes
+		}
+		if file.Approval.Routes != nil {
+			cfg.Approval.Routes = make([]ApprovalRoute, 0, len(*file.Approval.Routes))
+			for _, route := range *file.Approval.Routes {
+				cfg.Approval.Routes = append(cfg.Approval.Routes, ApprovalRoute{
+					Calendar: route.Calendar,
+					Provider: route.Provider,
+					Target:   route.Target,
+				})
+			}
+		}
 	}
 
 	if file.RateLimits != nil {
@@ -281,9 +464,18 @@ func applyConfigFile(cfg *Config, file *ConfigFile) {
 		if file.Retry.MaxAttempts != nil {
 			cfg.Retry.MaxAttempts = *file.Retry.MaxAttempts
 		}
+		if file.Retry.Strategy != nil {
+			cfg.Retry.Strategy = *file.Retry.Strategy
+		}
 		if file.Retry.BackoffSeconds != nil {
 			cfg.Retry.BackoffSeconds = *file.Retry.BackoffSeconds
 		}
+		if file.Retry.BaseDelaySeconds != nil {
+			cfg.Retry.BaseDelaySeconds = *file.Retry.BaseDelaySeconds
+		}
+		if file.Retry.MaxDelaySeconds != nil {
+			cfg.Retry.MaxDelaySeconds = *file.Retry.MaxDelaySeconds
+		}
 		if file.Retry.RetryableStatusCodes != nil {
 			cfg.Retry.RetryableStatusCodes = *file.Retry.RetryableStatusCodes
 		}
@@ -309,6 +501,9 @@ func applyConfigFile(cfg *Config, file *ConfigFile) {
 			if file.Notifications.Ntfy.MinimalContent != nil {
 				cfg.Notifications.Ntfy.MinimalContent = *file.Notifications.Ntfy.MinimalContent
 			}
+			if file.Notifications.Ntfy.ResultsOn != nil {
+				cfg.Notifications.Ntfy.ResultsOn = *file.Notifications.Ntfy.ResultsOn
+			}
 		}
 		if file.Notifications.Pushover != nil {
 			if file.Notifications.Pushover.Enabled != nil {
@@ -326,6 +521,9 @@ func applyConfigFile(cfg *Config, file *ConfigFile) {
 			if file.Notifications.Pushover.Sound != nil {
 				cfg.Notifications.Pushover.Sound = *file.Notifications.Pushover.Sound
 			}
+			if file.Notifications.Pushover.ResultsOn != nil {
+				cfg.Notifications.Pushover.ResultsOn = *file.Notifications.Pushover.ResultsOn
+			}
 		}
 		if file.Notifications.Telegram != nil {
 			if file.Notifications.Telegram.Enabled != nil {
@@ -346,6 +544,12 @@ func applyConfigFile(cfg *Config, file *ConfigFile) {
 			if file.Notifications.Telegram.AutoRegisterWebhook != nil {
 				cfg.Notifications.Telegram.AutoRegisterWebhook = *file.Notifications.Telegram.AutoRegisterWebhook
 			}
+			if file.Notifications.Telegram.DenyReasons != nil {
+				cfg.Notifications.Telegram.DenyReasons = *file.Notifications.Telegram.DenyReasons
+			}
+			if file.Notifications.Telegram.ResultsOn != nil {
+				cfg.Notifications.Telegram.ResultsOn = *file.Notifications.Telegram.ResultsOn
+			}
 		}
 	}
 
@@ -372,9 +576,41 @@ func applyConfigFile(cfg *Config, file *ConfigFile) {
 		if w.RetryBackoff != nil {
 			cfg.Moltbot.Webhook.RetryBackoff = *w.RetryBackoff
 		}
+		if w.ClientCertFile != nil {
+			cfg.Moltbot.Webhook.ClientCertFile = *w.ClientCertFile
+		}
+		if w.ClientKeyFile != nil {
+			cfg.Moltbot.Webhook.ClientKeyFile = *w.ClientKeyFile
+		}
+		if w.CACertFile != nil {
+			cfg.Moltbot.Webhook.CACertFile = *w.CACertFile
+		}
+		if w.BatchWindowMs != nil {
+			cfg.Moltbot.Webhook.BatchWindowMs = *w.BatchWindowMs
+		}
+		if w.BatchMaxSize != nil {
+			cfg.Moltbot.Webhook.BatchMaxSize = *w.BatchMaxSize
+		}
 		if w.NotifyOn != nil {
 			cfg.Moltbot.Webhook.NotifyOn = *w.NotifyOn
 		}
+		if w.IncludeDiff != nil {
+			cfg.Moltbot.Webhook.IncludeDiff = *w.IncludeDiff
+		}
+		if w.DebounceMs != nil {
+			cfg.Moltbot.Webhook.DebounceMs = *w.DebounceMs
+		}
+		if w.Destinations != nil {
+			cfg.Moltbot.Webhook.Destinations = nil
+			for _, d := range *w.Destinations {
+				cfg.Moltbot.Webhook.Destinations = append(cfg.Moltbot.Webhook.Destinations, WebhookDestination{
+					Name:     d.Name,
+					URL:      d.URL,
+					Token:    d.Token,
+					NotifyOn: d.NotifyOn,
+				})
+			}
+		}
 	}
 
 	if file.Auth != nil {
@@ -396,6 +632,9 @@ func applyConfigFile(cfg *Config, file *ConfigFile) {
 		if file.Auth.SessionRefresh != nil {
 			cfg.Auth.SessionRefresh = *file.Auth.SessionRefresh
 		}
+		if file.Auth.SessionIdleTimeout != nil {
+			cfg.Auth.SessionIdleTimeout = time.Duration(*file.Auth.SessionIdleTimeout)
+		}
 		if file.Auth.CloudflareAccess != nil {
 			if file.Auth.CloudflareAccess.Enabled != nil {
 				cfg.Auth.CloudflareAccess.Enabled = *file.Auth.CloudflareAccess.Enabled
@@ -434,6 +673,15 @@ func applyConfigFile(cfg *Config, file *ConfigFile) {
 		if file.Display.DatetimeFormat != nil {
 			cfg.Display.DatetimeFormat = *file.Display.DatetimeFormat
 		}
+		if file.Display.Locale != nil {
+			cfg.Display.Locale = *file.Display.Locale
+		}
+		if file.Display.RelativeTime != nil {
+			cfg.Display.RelativeTime = *file.Display.RelativeTime
+		}
+		if file.Display.StatsWindowDays != nil {
+			cfg.Display.StatsWindowDays = *file.Display.StatsWindowDays
+		}
 	}
 
 	if file.Retention != nil {
@@ -452,6 +700,30 @@ func applyConfigFile(cfg *Config, file *ConfigFile) {
 		if file.Retention.VacuumSchedule != nil {
 			cfg.Retention.VacuumSchedule = *file.Retention.VacuumSchedule
 		}
+		if file.Retention.UnclaimedResultsHours != nil {
+			cfg.Retention.UnclaimedResultsHours = *file.Retention.UnclaimedResultsHours
+		}
+	}
+
+	if file.Audit != nil {
+		applyAuditWebhookFile(&cfg.Audit.Webhook, file.Audit.Webhook)
+		applyAuditWebhookFile(&cfg.Audit.SettingsWebhook, file.Audit.SettingsWebhook)
+	}
+
+	if file.EventDefaults != nil {
+		d := file.EventDefaults
+		if d.DescriptionSuffix != nil {
+			cfg.EventDefaults.DescriptionSuffix = *d.DescriptionSuffix
+		}
+		if d.Location != nil {
+			cfg.EventDefaults.Location = *d.Location
+		}
+		if d.ColorID != nil {
+			cfg.EventDefaults.ColorID = *d.ColorID
+		}
+		if d.Reminders != nil {
+			cfg.EventDefaults.Reminders = *d.Reminders
+		}
 	}
 }
 
@@ -530,3 +802,34 @@ func GetConfigFilePath() string {
 	dataDir := getEnvAnyDefault(DefaultDataDir, "SCHEDLOCK_DATA_DIR", "DATA_DIR")
 	return getEnvAnyDefault(filepath.Join(dataDir, "config.yaml"), "SCHEDLOCK_CONFIG_FILE", "CONFIG_FILE")
 }
+
+// applyAuditWebhookFile copies the fields set in w to cfg.
+func applyAuditWebhookFile(cfg *AuditWebhookConfig, w *AuditWebhookConfigFile) {
+	if w == nil {
+		return
+	}
+	if w.URL != nil {
+		cfg.URL = *w.URL
+	}
+	if w.Secret != nil {
+		cfg.Secret = *w.Secret
+	}
+	if w.TimeoutSeconds != nil {
+		cfg.TimeoutSeconds = *w.TimeoutSeconds
+	}
+	if w.MaxRetries != nil {
+		cfg.MaxRetries = *w.MaxRetries
+	}
+	if w.RetryBackoff != nil {
+		cfg.RetryBackoff = *w.RetryBackoff
+	}
+	if w.BatchWindowMs != nil {
+		cfg.BatchWindowMs = *w.BatchWindowMs
+	}
+	if w.BatchMaxSize != nil {
+		cfg.BatchMaxSize = *w.BatchMaxSize
+	}
+	if w.QueueSize != nil {
+		cfg.QueueSize = *w.QueueSize
+	}
+}
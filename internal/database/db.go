@@ -2,34 +2,89 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/dtorcivia/schedlock/internal/config"
 )
 
 // DB wraps the sql.DB connection with additional functionality.
 type DB struct {
 	*sql.DB
 	path string
+	read *sql.DB // optional read-only pool; nil when disabled
+}
+
+// Options controls optional connection behavior.
+type Options struct {
+	// ReadPool opens a separate read-only connection pool for read-heavy queries.
+	ReadPool bool
+	// ReadPoolSize caps the number of open read-only connections.
+	ReadPoolSize int
+
+	// DisableWAL keeps the rollback journal instead of switching to WAL mode.
+	DisableWAL bool
+	// BusyTimeoutMs is how long a connection waits for a lock before failing
+	// with "database is locked". Zero means DefaultBusyTimeoutMs.
+	BusyTimeoutMs int
+
+	// Limits for the primary pool; zero leaves the database/sql default.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// DefaultBusyTimeoutMs is the lock wait used when Options leaves it unset.
+const DefaultBusyTimeoutMs = config.DefaultBusyTimeoutMs
+
+// busyTimeout returns the configured lock wait in milliseconds.
+func (o Options) busyTimeout() int {
+	if o.BusyTimeoutMs <= 0 {
+		return DefaultBusyTimeoutMs
+	}
+	return o.BusyTimeoutMs
 }
 
 // Open creates or opens a SQLite database with WAL mode enabled.
 func Open(path string) (*DB, error) {
+	return OpenWithOptions(path, Options{})
+}
+
+// OpenWithOptions opens the database and, if requested, a read-only pool.
+func OpenWithOptions(path string, opts Options) (*DB, error) {
 	// Ensure directory exists
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	// Open database with foreign keys enabled
-	dsn := fmt.Sprintf("%s?_foreign_keys=on&_busy_timeout=5000", path)
+	// Connection-level settings go in the DSN so every pooled connection
+	// gets them, not just the one that happens to run a PRAGMA
+	journalMode := "WAL"
+	if opts.DisableWAL {
+		journalMode = "DELETE"
+	}
+	dsn := fmt.Sprintf("%s?_foreign_keys=on&_busy_timeout=%d&_journal_mode=%s", fileURI(path), opts.busyTimeout(), journalMode)
 	sqlDB, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	if opts.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(opts.MaxOpenConns)
+	}
+	if opts.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(opts.MaxIdleConns)
+	}
+	if opts.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	}
 
 	db := &DB{
 		DB:   sqlDB,
@@ -48,15 +103,71 @@ func Open(path string) (*DB, error) {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	// In-memory databases are private to a connection, so they cannot be shared
+	if opts.ReadPool && path != ":memory:" {
+		if err := db.openReadPool(opts.ReadPoolSize, opts.busyTimeout()); err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+	}
+
 	return db, nil
 }
 
+// openReadPool opens a read-only pool against the same file. The primary
+// connection has already switched the file to WAL mode, so readers see every
+// committed write without blocking the writer.
+func (db *DB) openReadPool(size, busyTimeoutMs int) error {
+	if size <= 0 {
+		size = config.DefaultReadPoolSize
+	}
+
+	dsn := fmt.Sprintf("%s?mode=ro&_foreign_keys=on&_busy_timeout=%d", fileURI(db.path), busyTimeoutMs)
+	readDB, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return fmt.Errorf("failed to open read pool: %w", err)
+	}
+	readDB.SetMaxOpenConns(size)
+	readDB.SetMaxIdleConns(size)
+
+	var journalMode string
+	if err := readDB.QueryRow("PRAGMA journal_mode").Scan(&journalMode); err != nil {
+		readDB.Close()
+		return fmt.Errorf("failed to verify read pool: %w", err)
+	}
+	if journalMode != "wal" {
+		readDB.Close()
+		return fmt.Errorf("read pool requires WAL mode, database is in %s mode", journalMode)
+	}
+
+	db.read = readDB
+	return nil
+}
+
+// fileURI returns path as a SQLite file: URI, escaping the characters that
+// would otherwise start the query string or fragment (?, #) or an escape
+// (%). ":memory:" is returned as is.
+func fileURI(path string) string {
+	if path == ":memory:" {
+		return path
+	}
+	return "file:" + (&url.URL{Path: path}).EscapedPath()
+}
+
+// Reader returns the pool to use for read-only queries: the read-only pool
+// when enabled, otherwise the primary connection.
+func (db *DB) Reader() *sql.DB {
+	if db.read != nil {
+		return db.read
+	}
+	return db.DB
+}
+
 // configure sets up SQLite pragmas for optimal performance and safety.
+// Journal mode and busy timeout are set through the DSN.
 func (db *DB) configure() error {
 	pragmas := []string{
-		"PRAGMA journal_mode=WAL",
 		"PRAGMA synchronous=NORMAL",
-		"PRAGMA busy_timeout=5000",
 		"PRAGMA cache_size=-64000", // 64MB cache
 		"PRAGMA foreign_keys=ON",
 		"PRAGMA temp_store=MEMORY",
@@ -73,6 +184,10 @@ func (db *DB) configure() error {
 
 // Close closes the database connection.
 func (db *DB) Close() error {
+	if db.read != nil {
+		db.read.Close()
+	}
+
 	// Checkpoint WAL before closing
 	if _, err := db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
 		// Log but don't fail
@@ -92,7 +207,60 @@ func (db *DB) Vacuum() error {
 	return err
 }
 
+// Size returns the current size of the main database file in bytes,
+// as reported by SQLite (page_count * page_size).
+func (db *DB) Size(ctx context.Context) (int64, error) {
+	var pageCount, pageSize int64
+	if err := db.QueryRowContext(ctx, "PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, fmt.Errorf("failed to read page count: %w", err)
+	}
+	if err := db.QueryRowContext(ctx, "PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("failed to read page size: %w", err)
+	}
+	return pageCount * pageSize, nil
+}
+
+// Backup writes a consistent, compacted copy of the database to dest using
+// VACUUM INTO. The WAL is checkpointed first so the copy does not depend on
+// uncheckpointed frames. dest must not already exist.
+func (db *DB) Backup(ctx context.Context, dest string) error {
+	if _, err := db.ExecContext(ctx, "PRAGMA wal_checkpoint(FULL)"); err != nil {
+		return fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", dest); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	return nil
+}
+
 // BeginTx starts a transaction with the given options.
 func (db *DB) BeginTx() (*sql.Tx, error) {
 	return db.DB.Begin()
 }
+
+// ImmediateTx runs fn in a transaction that takes the write lock when it
+// begins, waiting up to the busy timeout for it. Use it for a read followed
+// by a write: in a deferred transaction, a commit from another connection
+// between the two makes the write fail with SQLITE_BUSY_SNAPSHOT, which the
+// busy timeout does not retry. The transaction commits if fn returns nil.
+func (db *DB) ImmediateTx(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return err
+	}
+	if err := fn(conn); err != nil {
+		// Roll back even if ctx is done, so the connection returns to the pool clean
+		conn.ExecContext(context.Background(), "ROLLBACK")
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		conn.ExecContext(context.Background(), "ROLLBACK")
+		return err
+	}
+	return nil
+}
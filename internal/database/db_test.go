@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenEnablesWAL(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+
+	var mode string
+	if err := db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("journal_mode: %v", err)
+	}
+	if mode != "wal" {
+		t.Fatalf("journal_mode = %q, want wal", mode)
+	}
+}
+
+func TestOpenWithOptionsAppliesBusyTimeoutToEveryConnection(t *testing.T) {
+	db, err := OpenWithOptions(filepath.Join(t.TempDir(), "schedlock.db"), Options{
+		BusyTimeoutMs: 1234,
+		MaxOpenConns:  3,
+		MaxIdleConns:  3,
+	})
+	if err != nil {
+		t.Fatalf("OpenWithOptions: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatalf("Conn: %v", err)
+		}
+		defer conn.Close()
+
+		var timeout int
+		if err := conn.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&timeout); err != nil {
+			t.Fatalf("busy_timeout: %v", err)
+		}
+		if timeout != 1234 {
+			t.Fatalf("connection %d busy_timeout = %d, want 1234", i, timeout)
+		}
+	}
+	if got := db.Stats().MaxOpenConnections; got != 3 {
+		t.Fatalf("MaxOpenConnections = %d, want 3", got)
+	}
+}
+
+func TestOpenWithOptionsDisableWAL(t *testing.T) {
+	db, err := OpenWithOptions(filepath.Join(t.TempDir(), "schedlock.db"), Options{DisableWAL: true})
+	if err != nil {
+		t.Fatalf("OpenWithOptions: %v", err)
+	}
+	defer db.Close()
+
+	var mode string
+	if err := db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("journal_mode: %v", err)
+	}
+	if mode != "delete" {
+		t.Fatalf("journal_mode = %q, want delete", mode)
+	}
+}
+
+func TestImmediateTxRollsBackOnError(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.Exec("CREATE TABLE items (name TEXT)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	failure := errors.New("boom")
+	err = db.ImmediateTx(ctx, func(conn *sql.Conn) error {
+		if _, err := conn.ExecContext(ctx, "INSERT INTO items (name) VALUES ('rolled back')"); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("ImmediateTx error = %v, want %v", err, failure)
+	}
+	if err := db.ImmediateTx(ctx, func(conn *sql.Conn) error {
+		_, err := conn.ExecContext(ctx, "INSERT INTO items (name) VALUES ('kept')")
+		return err
+	}); err != nil {
+		t.Fatalf("ImmediateTx: %v", err)
+	}
+
+	var names []string
+	rows, err := db.Query("SELECT name FROM items")
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		names = append(names, name)
+	}
+	if len(names) != 1 || names[0] != "kept" {
+		t.Fatalf("rows = %v, want [kept]", names)
+	}
+}
+
+func TestReadPoolSeesCommittedWrites(t *testing.T) {
+	// The characters that delimit or escape a file: URI must not break the path
+	path := filepath.Join(t.TempDir(), "data #1 ?100%", "schedlock.db")
+	db, err := OpenWithOptions(path, Options{ReadPool: true})
+	if err != nil {
+		t.Fatalf("OpenWithOptions: %v", err)
+	}
+	defer db.Close()
+
+	if db.Reader() == db.DB {
+		t.Fatal("Reader returned the primary pool, want the read pool")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("database not created at %q: %v", path, err)
+	}
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_a', 'hash_a', 'sk_test', 'Test', 'read')
+	`); err != nil {
+		t.Fatalf("insert on primary: %v", err)
+	}
+
+	var name string
+	if err := db.Reader().QueryRow("SELECT name FROM api_keys WHERE id = 'key_a'").Scan(&name); err != nil {
+		t.Fatalf("read through Reader: %v", err)
+	}
+	if name != "Test" {
+		t.Fatalf("name = %q, want Test", name)
+	}
+
+	if _, err := db.Reader().Exec("DELETE FROM api_keys"); err == nil {
+		t.Fatal("expected the read pool to reject writes")
+	}
+}
@@ -2,6 +2,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -40,10 +41,32 @@ func (db *DB) migrate() error {
 type migration struct {
 	version int
 	sql     string
+	// rebuild marks migrations that recreate a table referenced by foreign
+	// keys. SQLite cannot alter CHECK constraints in place, so these run with
+	// foreign key enforcement disabled on a dedicated connection, following
+	// the documented table-rebuild procedure.
+	rebuild bool
 }
 
 func (db *DB) runMigration(m migration) error {
-	tx, err := db.Begin()
+	ctx := context.Background()
+
+	// Pin a single connection so the foreign_keys pragma applies to the
+	// transaction below. The pragma is a no-op inside a transaction.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.rebuild {
+		if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+			return fmt.Errorf("failed to disable foreign keys: %w", err)
+		}
+		defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -53,6 +76,18 @@ func (db *DB) runMigration(m migration) error {
 		return fmt.Errorf("failed to execute migration SQL: %w", err)
 	}
 
+	if m.rebuild {
+		rows, err := tx.Query("PRAGMA foreign_key_check")
+		if err != nil {
+			return fmt.Errorf("failed to check foreign keys: %w", err)
+		}
+		violations := rows.Next()
+		rows.Close()
+		if violations {
+			return fmt.Errorf("foreign key violations after table rebuild")
+		}
+	}
+
 	if _, err := tx.Exec("INSERT INTO migrations (version) VALUES (?)", m.version); err != nil {
 		return fmt.Errorf("failed to record migration: %w", err)
 	}
@@ -70,9 +105,258 @@ func getAllMigrations() []migration {
 			version: 2,
 			sql:     migration002NotificationCredentials,
 		},
+		{
+			version: 3,
+			sql:     migration003MoveEventOperation,
+			rebuild: true,
+		},
+		{
+			version: 4,
+			sql:     migration004RequestReminders,
+		},
+		{
+			version: 5,
+			sql:     migration005AuditHashChain,
+		},
+		{
+			version: 6,
+			sql:     migration006RequestPriority,
+		},
+		{
+			version: 7,
+			sql:     migration007APIKeyScopes,
+		},
+		{
+			version: 8,
+			sql:     migration008DecisionTokenPINBypass,
+		},
+		{
+			version: 9,
+			sql:     migration009CreateEventsBatchOperation,
+			rebuild: true,
+		},
+		{
+			version: 10,
+			sql:     migration010RequestContext,
+		},
+		{
+			version: 11,
+			sql:     migration011WebhookSequence,
+		},
+		{
+			version: 12,
+			sql:     migration012RequestTags,
+		},
+		{
+			version: 13,
+			sql:     migration013RequestEscalation,
+		},
+		{
+			version: 14,
+			sql:     migration014RequestManualRetry,
+		},
+		{
+			version: 15,
+			sql:     migration015AuditKeyIndex,
+		},
+		{
+			version: 16,
+			sql:     migration016SessionTimezone,
+		},
+		{
+			version: 17,
+			sql:     migration017RequestResultFetched,
+		},
+		{
+			version: 18,
+			sql:     migration018WebhookFailureDestination,
+		},
 	}
 }
 
+const migration018WebhookFailureDestination = `
+-- Which webhook destination a failed delivery was meant for
+ALTER TABLE webhook_failures ADD COLUMN destination TEXT NOT NULL DEFAULT 'default';
+`
+
+const migration017RequestResultFetched = `
+-- When the caller first read a completed request, so unclaimed results can be cleaned up early
+ALTER TABLE requests ADD COLUMN result_fetched_at TEXT;
+`
+
+const migration016SessionTimezone = `
+-- Timezone the signed-in approver chose for viewing event times
+ALTER TABLE sessions ADD COLUMN timezone TEXT;
+`
+
+const migration015AuditKeyIndex = `
+-- Serve per-key audit trails without scanning the whole log
+CREATE INDEX IF NOT EXISTS idx_audit_api_key ON audit_log(api_key_id, timestamp);
+`
+
+const migration014RequestManualRetry = `
+-- Count admin-triggered re-runs of failed requests apart from automatic retries
+ALTER TABLE requests ADD COLUMN manual_retry_count INTEGER DEFAULT 0;
+`
+
+const migration013RequestEscalation = `
+-- Track when a still-pending request was escalated to the backup approver
+ALTER TABLE requests ADD COLUMN escalated_at TEXT;
+`
+
+const migration012RequestTags = `
+-- Caller-supplied labels for organizing the approval queue, as a JSON array
+ALTER TABLE requests ADD COLUMN tags TEXT NOT NULL DEFAULT '[]';
+`
+
+const migration011WebhookSequence = `
+-- Last sequence number handed out to a webhook event for the request
+ALTER TABLE requests ADD COLUMN webhook_sequence INTEGER NOT NULL DEFAULT 0;
+`
+
+const migration010RequestContext = `
+-- Caller-supplied explanation shown to approvers, kept apart from the payload
+ALTER TABLE requests ADD COLUMN context TEXT NOT NULL DEFAULT '';
+`
+
+const migration009CreateEventsBatchOperation = `
+-- Rebuild requests table to allow the 'create_events_batch' operation.
+CREATE TABLE requests_new (
+    id TEXT PRIMARY KEY,
+    api_key_id TEXT NOT NULL REFERENCES api_keys(id),
+    operation TEXT NOT NULL CHECK (operation IN (
+        'create_event', 'update_event', 'delete_event', 'move_event',
+        'create_events_batch'
+    )),
+    status TEXT NOT NULL DEFAULT 'pending_approval' CHECK (status IN (
+        'pending_approval', 'change_requested', 'approved', 'denied', 'expired',
+        'cancelled', 'executing', 'completed', 'failed'
+    )),
+    payload TEXT NOT NULL,
+    result TEXT,
+    error TEXT,
+    suggestion_text TEXT,
+    suggestion_at TEXT,
+    suggestion_by TEXT,
+    created_at TEXT DEFAULT (datetime('now')),
+    expires_at TEXT NOT NULL,
+    decided_at TEXT,
+    decided_by TEXT,
+    executed_at TEXT,
+    retry_count INTEGER DEFAULT 0,
+    webhook_notified_at TEXT,
+    reminded_at TEXT,
+    priority TEXT NOT NULL DEFAULT 'normal'
+);
+
+INSERT INTO requests_new (
+    id, api_key_id, operation, status, payload, result, error,
+    suggestion_text, suggestion_at, suggestion_by, created_at, expires_at,
+    decided_at, decided_by, executed_at, retry_count, webhook_notified_at,
+    reminded_at, priority
+)
+SELECT
+    id, api_key_id, operation, status, payload, result, error,
+    suggestion_text, suggestion_at, suggestion_by, created_at, expires_at,
+    decided_at, decided_by, executed_at, retry_count, webhook_notified_at,
+    reminded_at, priority
+FROM requests;
+
+DROP TABLE requests;
+ALTER TABLE requests_new RENAME TO requests;
+
+CREATE INDEX IF NOT EXISTS idx_requests_status ON requests(status);
+CREATE INDEX IF NOT EXISTS idx_requests_pending ON requests(expires_at)
+    WHERE status = 'pending_approval';
+CREATE INDEX IF NOT EXISTS idx_requests_api_key ON requests(api_key_id);
+CREATE INDEX IF NOT EXISTS idx_requests_created ON requests(created_at);
+`
+
+const migration008DecisionTokenPINBypass = `
+-- Links created by a signed-in admin may skip the approval PIN
+ALTER TABLE decision_tokens ADD COLUMN pin_satisfied INTEGER NOT NULL DEFAULT 0;
+`
+
+const migration007APIKeyScopes = `
+-- Fine-grained scopes; existing keys get the scope set equivalent to their tier
+ALTER TABLE api_keys ADD COLUMN scopes TEXT;
+
+UPDATE api_keys SET scopes = '["calendars:list","events:read","freebusy:read","requests:read"]'
+WHERE tier = 'read';
+
+UPDATE api_keys SET scopes = '["calendars:list","events:read","freebusy:read","requests:read","events:create","events:update","events:delete","events:move","requests:cancel"]'
+WHERE tier = 'write';
+
+UPDATE api_keys SET scopes = '["calendars:list","events:read","freebusy:read","requests:read","events:create","events:update","events:delete","events:move","requests:cancel","admin"]'
+WHERE tier = 'admin';
+`
+
+const migration006RequestPriority = `
+-- Priority lets notification providers ignore low-urgency requests
+ALTER TABLE requests ADD COLUMN priority TEXT NOT NULL DEFAULT 'normal';
+`
+
+const migration005AuditHashChain = `
+-- Tamper evidence: each audit row stores a hash over its contents and the
+-- previous row's hash. Rows written before this migration stay unhashed.
+ALTER TABLE audit_log ADD COLUMN prev_hash TEXT;
+ALTER TABLE audit_log ADD COLUMN hash TEXT;
+`
+
+const migration004RequestReminders = `
+-- Track when an expiry reminder was sent for a pending request
+ALTER TABLE requests ADD COLUMN reminded_at TEXT;
+`
+
+const migration003MoveEventOperation = `
+-- Rebuild requests table to allow the 'move_event' operation.
+-- SQLite cannot alter CHECK constraints, so copy into a new table.
+CREATE TABLE requests_new (
+    id TEXT PRIMARY KEY,
+    api_key_id TEXT NOT NULL REFERENCES api_keys(id),
+    operation TEXT NOT NULL CHECK (operation IN (
+        'create_event', 'update_event', 'delete_event', 'move_event'
+    )),
+    status TEXT NOT NULL DEFAULT 'pending_approval' CHECK (status IN (
+        'pending_approval', 'change_requested', 'approved', 'denied', 'expired',
+        'cancelled', 'executing', 'completed', 'failed'
+    )),
+    payload TEXT NOT NULL,
+    result TEXT,
+    error TEXT,
+    suggestion_text TEXT,
+    suggestion_at TEXT,
+    suggestion_by TEXT,
+    created_at TEXT DEFAULT (datetime('now')),
+    expires_at TEXT NOT NULL,
+    decided_at TEXT,
+    decided_by TEXT,
+    executed_at TEXT,
+    retry_count INTEGER DEFAULT 0,
+    webhook_notified_at TEXT
+);
+
+INSERT INTO requests_new (
+    id, api_key_id, operation, status, payload, result, error,
+    suggestion_text, suggestion_at, suggestion_by, created_at, expires_at,
+    decided_at, decided_by, executed_at, retry_count, webhook_notified_at
+)
+SELECT
+    id, api_key_id, operation, status, payload, result, error,
+    suggestion_text, suggestion_at, suggestion_by, created_at, expires_at,
+    decided_at, decided_by, executed_at, retry_count, webhook_notified_at
+FROM requests;
+
+DROP TABLE requests;
+ALTER TABLE requests_new RENAME TO requests;
+
+CREATE INDEX IF NOT EXISTS idx_requests_status ON requests(status);
+CREATE INDEX IF NOT EXISTS idx_requests_pending ON requests(expires_at)
+    WHERE status = 'pending_approval';
+CREATE INDEX IF NOT EXISTS idx_requests_api_key ON requests(api_key_id);
+CREATE INDEX IF NOT EXISTS idx_requests_created ON requests(created_at);
+`
+
 const migration002NotificationCredentials = `
 -- Notification credentials table
 -- Stores encrypted credentials for notification providers
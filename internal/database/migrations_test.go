@@ -0,0 +1,158 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigration003MoveEventRebuild(t *testing.T) {
+	sqlDB, err := sql.Open("sqlite3", fileURI(filepath.Join(t.TempDir(), "schedlock.db"))+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	db := &DB{DB: sqlDB}
+	defer db.Close()
+
+	// Bring the schema up to version 2, before move_event existed
+	if _, err := db.Exec(`CREATE TABLE migrations (version INTEGER PRIMARY KEY, applied_at TEXT)`); err != nil {
+		t.Fatalf("create migrations table: %v", err)
+	}
+	all := getAllMigrations()
+	for _, m := range all[:2] {
+		if err := db.runMigration(m); err != nil {
+			t.Fatalf("migration %d: %v", m.version, err)
+		}
+	}
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_test', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, status, payload, expires_at, decided_by, retry_count)
+		VALUES ('req_old', 'key_test', 'update_event', 'completed', '{"eventId":"evt1"}', '2030-01-01T00:00:00Z', 'web:admin', 2)
+	`); err != nil {
+		t.Fatalf("insert request: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO decision_tokens (token_hash, request_id, allowed_actions, expires_at)
+		VALUES ('tok_hash', 'req_old', '["approve"]', '2030-01-01T00:00:00Z')
+	`); err != nil {
+		t.Fatalf("insert decision token: %v", err)
+	}
+	insertMove := func(id string) error {
+		_, err := db.Exec(`
+			INSERT INTO requests (id, api_key_id, operation, payload, expires_at)
+			VALUES (?, 'key_test', 'move_event', '{}', '2030-01-01T00:00:00Z')
+		`, id)
+		return err
+	}
+	if err := insertMove("req_early"); err == nil {
+		t.Fatal("move_event should be rejected before the rebuild")
+	}
+
+	if err := db.runMigration(all[2]); err != nil {
+		t.Fatalf("migration 3: %v", err)
+	}
+
+	// Existing rows survive the rebuild, and rows pointing at them still resolve
+	var operation, status, payload, decidedBy string
+	var retries int
+	if err := db.QueryRow(`
+		SELECT operation, status, payload, decided_by, retry_count FROM requests WHERE id = 'req_old'
+	`).Scan(&operation, &status, &payload, &decidedBy, &retries); err != nil {
+		t.Fatalf("read migrated request: %v", err)
+	}
+	if operation != "update_event" || status != "completed" || payload != `{"eventId":"evt1"}` || decidedBy != "web:admin" || retries != 2 {
+		t.Errorf("migrated request = %s %s %s %s %d", operation, status, payload, decidedBy, retries)
+	}
+	var tokenRequest string
+	if err := db.QueryRow(`
+		SELECT r.id FROM decision_tokens t JOIN requests r ON r.id = t.request_id WHERE t.token_hash = 'tok_hash'
+	`).Scan(&tokenRequest); err != nil {
+		t.Fatalf("decision token lost its request: %v", err)
+	}
+
+	if err := insertMove("req_move"); err != nil {
+		t.Errorf("move_event should be accepted after the rebuild: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, payload, expires_at)
+		VALUES ('req_bad', 'key_test', 'rename_event', '{}', '2030-01-01T00:00:00Z')
+	`); err == nil {
+		t.Error("unknown operations should still be rejected")
+	}
+
+	// Foreign keys are enforced again once the rebuild is done
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, payload, expires_at)
+		VALUES ('req_orphan', 'key_missing', 'move_event', '{}', '2030-01-01T00:00:00Z')
+	`); err == nil {
+		t.Error("a request for an unknown key should violate the foreign key")
+	}
+}
+
+func TestMigration007APIKeyScopes(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	// Recreate the pre-scopes schema with one key per tier
+	if _, err := db.Exec(`ALTER TABLE api_keys DROP COLUMN scopes`); err != nil {
+		t.Fatalf("drop scopes column: %v", err)
+	}
+	for _, tier := range []string{TierRead, TierWrite, TierAdmin} {
+		if _, err := db.Exec(`
+			INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+			VALUES (?, ?, ?, ?, ?)
+		`, "key_"+tier, "hash_"+tier, "sk_"+tier, tier+" key", tier); err != nil {
+			t.Fatalf("insert %s key: %v", tier, err)
+		}
+	}
+
+	if _, err := db.Exec(migration007APIKeyScopes); err != nil {
+		t.Fatalf("run migration: %v", err)
+	}
+
+	cases := []struct {
+		tier    string
+		want    []string
+		notWant []string
+	}{
+		{TierRead, []string{"calendars:list", "events:read", "freebusy:read", "requests:read"}, []string{"events:create", "admin"}},
+		{TierWrite, []string{"events:read", "events:create", "events:delete", "requests:cancel"}, []string{"admin"}},
+		{TierAdmin, []string{"events:read", "events:create", "admin"}, nil},
+	}
+
+	for _, tc := range cases {
+		var raw string
+		if err := db.QueryRow(`SELECT scopes FROM api_keys WHERE tier = ?`, tc.tier).Scan(&raw); err != nil {
+			t.Fatalf("read %s scopes: %v", tc.tier, err)
+		}
+		var scopes []string
+		if err := json.Unmarshal([]byte(raw), &scopes); err != nil {
+			t.Fatalf("%s scopes are not a JSON list: %v", tc.tier, err)
+		}
+
+		has := make(map[string]bool, len(scopes))
+		for _, s := range scopes {
+			has[s] = true
+		}
+		for _, s := range tc.want {
+			if !has[s] {
+				t.Errorf("%s key missing scope %s: %v", tc.tier, s, scopes)
+			}
+		}
+		for _, s := range tc.notWant {
+			if has[s] {
+				t.Errorf("%s key should not have scope %s", tc.tier, s)
+			}
+		}
+	}
+}
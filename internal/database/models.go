@@ -14,6 +14,7 @@ type APIKey struct {
 	KeyPrefix         string
 	Name              string
 	Tier              string
+	Scopes            []string
 	Constraints       *KeyConstraints
 	CreatedAt         time.Time
 	LastUsedAt        sql.NullTime
@@ -29,9 +30,77 @@ type KeyConstraints struct {
 	Operations              map[string]string `json:"operations,omitempty"` // "create_event": "require_approval"
 	MaxDurationMinutes      int               `json:"max_duration_minutes,omitempty"`
 	AttendeeDomainAllowlist []string          `json:"attendee_domain_allowlist,omitempty"`
+	AttendeeDomainBlocklist []string          `json:"attendee_domain_blocklist,omitempty"` // always denied, checked before the allowlist
 	AllowExternalAttendees  *bool             `json:"allow_external_attendees,omitempty"`
 	MaxAttendees            int               `json:"max_attendees,omitempty"`
+	AllowedRooms            []string          `json:"allowed_rooms,omitempty"` // room resource emails the key may book; unset allows any room
 	BlockAllDayEvents       bool              `json:"block_all_day_events,omitempty"`
+	AllowedVisibilities     []string          `json:"allowed_visibilities,omitempty"`   // e.g. ["private"]; unset visibility counts as "default"
+	AllowedTransparencies   []string          `json:"allowed_transparencies,omitempty"` // "opaque" (busy) / "transparent" (free)
+	BusinessHours           *BusinessHours    `json:"business_hours,omitempty"`
+	AutoApproveFields       []string          `json:"auto_approve_fields,omitempty"` // update fields that skip approval, e.g. ["description", "reminders", "colorId"]
+	DefaultCalendar         string            `json:"default_calendar,omitempty"`    // used when a request omits calendarId; must be in the allowlist
+	AllowedIPs              []string          `json:"allowed_ips,omitempty"`         // client addresses or CIDR ranges the key may be used from; unset allows any
+	RedactFields            []string          `json:"redact_fields,omitempty"`       // event fields hidden from the key's reads, e.g. ["attendees", "description"]
+
+	MaxPendingRequests int `json:"max_pending_requests,omitempty"` // requests awaiting approval at once; 0 means unlimited
+
+	MinLeadTimeMinutes int    `json:"min_lead_time_minutes,omitempty"` // created and updated events must start at least this far ahead
+	MinLeadTimeMode    string `json:"min_lead_time_mode,omitempty"`    // "deny" (default) or "require_approval"
+
+	DedupContent bool `json:"dedup_content,omitempty"` // return a recent identical request instead of creating another
+
+	AllowHTMLDescription bool `json:"allow_html_description,omitempty"` // keep links, bold, italics and lists in descriptions; otherwise HTML is stripped
+
+	DisableGuestInvites bool   `json:"disable_guest_invites,omitempty"` // force guestsCanInviteOthers=false on created and updated events
+	ForceSendUpdates    string `json:"force_send_updates,omitempty"`    // "all", "externalOnly" or "none"; overrides the request's sendUpdates
+
+	EventDefaults *EventDefaults `json:"event_defaults,omitempty"` // merged into created events; each field overrides the global default
+	TimeoutAction string         `json:"timeout_action,omitempty"` // "approve" or "deny" when approval times out; overrides the configured default action
+
+	Rules []ConstraintRule `json:"rules,omitempty"` // composite conditions checked after the single-value constraints
+}
+
+// ConstraintRule denies a write or sends it for approval when its condition
+// matches, e.g. "require approval if attendees > 10 or duration > 2h".
+type ConstraintRule struct {
+	Name   string        `json:"name,omitempty"` // shown in the decision reason
+	Action string        `json:"action"`         // "deny" or "require_approval"
+	When   RuleCondition `json:"when"`
+}
+
+// RuleCondition is one node of a rule's condition: all or any of its
+// children, or a single comparison of a request field with a value. Exactly
+// one of All, Any or Field is set.
+type RuleCondition struct {
+	All   []RuleCondition `json:"all,omitempty"`
+	Any   []RuleCondition `json:"any,omitempty"`
+	Field string          `json:"field,omitempty"` // "attendees", "duration_minutes", "operation" or "calendar"
+	Op    string          `json:"op,omitempty"`    // ">", ">=", "<", "<=", "==" or "!="
+	Value json.RawMessage `json:"value,omitempty"` // a number, or a string for operation and calendar
+}
+
+// EventDefaults fills in fields that a created event leaves empty.
+type EventDefaults struct {
+	DescriptionSuffix string            `json:"description_suffix,omitempty"` // appended to every description
+	Location          string            `json:"location,omitempty"`
+	ColorID           string            `json:"color_id,omitempty"`
+	Reminders         []DefaultReminder `json:"reminders,omitempty"` // used when the event sets no reminders
+}
+
+// DefaultReminder is one reminder in EventDefaults.
+type DefaultReminder struct {
+	Method  string `json:"method"` // "email" or "popup"
+	Minutes int    `json:"minutes"`
+}
+
+// BusinessHours restricts event times to a recurring weekly window.
+type BusinessHours struct {
+	Days     []string `json:"days,omitempty"`     // "mon".."sun"; empty means Monday to Friday
+	Start    string   `json:"start"`              // "09:00" wall-clock time
+	End      string   `json:"end"`                // "17:00"; an end at or before start spans midnight
+	Timezone string   `json:"timezone,omitempty"` // IANA name; empty uses the display timezone
+	Mode     string   `json:"mode,omitempty"`     // "deny" (default) or "require_approval"
 }
 
 // Request represents a calendar operation request.
@@ -52,27 +121,62 @@ type Request struct {
 	DecidedBy         sql.NullString
 	ExecutedAt        sql.NullTime
 	RetryCount        int
+	ManualRetryCount  int // re-runs of the failed request started by an admin
 	WebhookNotifiedAt sql.NullTime
+	Priority          string
+	Context           string   // caller's explanation for the approver
+	Tags              []string // caller-supplied labels, e.g. "team:eng"
 }
 
 // RequestStatus constants
 const (
-	StatusPendingApproval  = "pending_approval"
-	StatusChangeRequested  = "change_requested"
-	StatusApproved         = "approved"
-	StatusDenied           = "denied"
-	StatusExpired          = "expired"
-	StatusCancelled        = "cancelled"
-	StatusExecuting        = "executing"
-	StatusCompleted        = "completed"
-	StatusFailed           = "failed"
+	StatusPendingApproval = "pending_approval"
+	StatusChangeRequested = "change_requested"
+	StatusApproved        = "approved"
+	StatusDenied          = "denied"
+	StatusExpired         = "expired"
+	StatusCancelled       = "cancelled"
+	StatusExecuting       = "executing"
+	StatusCompleted       = "completed"
+	StatusFailed          = "failed"
 )
 
+// Request priority constants
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+)
+
+// ValidPriority reports whether priority is a known request priority.
+func ValidPriority(priority string) bool {
+	switch priority {
+	case PriorityLow, PriorityNormal, PriorityHigh:
+		return true
+	}
+	return false
+}
+
+// PriorityRank orders request priorities from low to high.
+// Empty or unknown values rank as normal.
+func PriorityRank(priority string) int {
+	switch priority {
+	case PriorityLow:
+		return 0
+	case PriorityHigh:
+		return 2
+	}
+	return 1
+}
+
 // Operation constants
 const (
 	OperationCreateEvent = "create_event"
 	OperationUpdateEvent = "update_event"
 	OperationDeleteEvent = "delete_event"
+	OperationMoveEvent   = "move_event"
+
+	OperationCreateEventsBatch = "create_events_batch"
 )
 
 // Tier constants
@@ -96,29 +200,39 @@ type AuditLogEntry struct {
 
 // Audit event types
 const (
-	AuditAPIKeyCreated     = "api_key_created"
-	AuditAPIKeyRevoked     = "api_key_revoked"
-	AuditAPIKeyUsed        = "api_key_used"
-	AuditRequestCreated    = "request_created"
-	AuditRequestApproved   = "request_approved"
-	AuditRequestDenied     = "request_denied"
-	AuditRequestExpired    = "request_expired"
-	AuditRequestChanged    = "request_change_requested"
-	AuditRequestCancelled  = "request_cancelled"
-	AuditRequestExecuting  = "request_executing"
-	AuditRequestCompleted  = "request_completed"
-	AuditRequestFailed     = "request_failed"
-	AuditNotificationSent  = "notification_sent"
-	AuditNotificationFailed = "notification_failed"
-	AuditCallbackReceived  = "callback_received"
-	AuditSettingsChanged   = "settings_changed"
-	AuditOAuthConnected    = "oauth_connected"
-	AuditOAuthRefreshed    = "oauth_refreshed"
-	AuditOAuthFailed       = "oauth_failed"
-	AuditLoginSuccess      = "login_success"
-	AuditLoginFailed       = "login_failed"
-	AuditSessionCreated    = "session_created"
-	AuditSessionExpired    = "session_expired"
+	AuditAPIKeyCreated         = "api_key_created"
+	AuditAPIKeyRevoked         = "api_key_revoked"
+	AuditAPIKeyUpdated         = "api_key_updated"
+	AuditAPIKeyUsed            = "api_key_used"
+	AuditRequestCreated        = "request_created"
+	AuditRequestApproved       = "request_approved"
+	AuditRequestDenied         = "request_denied"
+	AuditRequestExpired        = "request_expired"
+	AuditRequestChanged        = "request_change_requested"
+	AuditRequestCancelled      = "request_cancelled"
+	AuditRequestExecuting      = "request_executing"
+	AuditRequestCompleted      = "request_completed"
+	AuditRequestFailed         = "request_failed"
+	AuditRequestRetried        = "request_retried"
+	AuditRequestPartialFailure = "request_partial_failure"
+	AuditNotificationSent      = "notification_sent"
+	AuditNotificationFailed    = "notification_failed"
+	AuditNotificationResent    = "notification_resent"
+	AuditCallbackReceived      = "callback_received"
+	AuditSettingsChanged       = "settings_changed"
+	AuditOAuthConnected        = "oauth_connected"
+	AuditOAuthRefreshed        = "oauth_refreshed"
+	AuditOAuthFailed           = "oauth_failed"
+	AuditLoginSuccess          = "login_success"
+	AuditLoginFailed           = "login_failed"
+	AuditSessionCreated        = "session_created"
+	AuditSessionExpired        = "session_expired"
+	AuditSessionRevoked        = "session_revoked"
+	AuditDatabaseBackup        = "database_backup"
+	AuditApprovalLinkCreated   = "approval_link_created"
+	AuditEncryptionKeyRotated  = "encryption_key_rotated"
+	AuditWebhookRetried        = "webhook_retried"
+	AuditRequestEscalated      = "request_escalated"
 )
 
 // NotificationLog represents a notification delivery record.
@@ -169,19 +283,21 @@ type DecisionToken struct {
 	ConsumedAt     sql.NullTime
 	ConsumedAction sql.NullString
 	CreatedAt      time.Time
+	PinSatisfied   bool
 }
 
 // WebhookFailure represents a failed Moltbot webhook delivery.
 type WebhookFailure struct {
-	ID         int64
-	WebhookID  string
-	RequestID  string
-	Status     string
-	Payload    json.RawMessage
-	Error      sql.NullString
-	Attempts   int
-	CreatedAt  time.Time
-	ResolvedAt sql.NullTime
+	ID          int64
+	WebhookID   string
+	Destination string
+	RequestID   string
+	Status      string
+	Payload     json.RawMessage
+	Error       sql.NullString
+	Attempts    int
+	CreatedAt   time.Time
+	ResolvedAt  sql.NullTime
 }
 
 // Setting represents a configuration setting.
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// SecretCipher encrypts and decrypts stored secrets. *crypto.Encryptor
+// satisfies it.
+type SecretCipher interface {
+	Encrypt(plaintext string) ([]byte, error)
+	Decrypt(ciphertext []byte) (string, error)
+}
+
+// encryptedColumns lists every column holding data encrypted with the
+// server encryption key, keyed by table. Add new encrypted columns here so
+// key rotation covers them.
+var encryptedColumns = []struct {
+	table  string
+	key    string
+	column string
+}{
+	{"notification_credentials", "provider", "credentials_enc"},
+	{"oauth_tokens", "id", "refresh_token_enc"},
+}
+
+type encryptedSecret struct {
+	table, key, column string
+	id                 string
+	plaintext          string
+}
+
+// RotateEncryptionKey re-encrypts every stored secret from one key to
+// another in a single transaction. Every blob is decrypted with the old key
+// before anything is written, so a wrong old key aborts without changes.
+// It returns the number of secrets re-encrypted.
+func (db *DB) RotateEncryptionKey(ctx context.Context, from, to SecretCipher) (int, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var secrets []encryptedSecret
+	for _, col := range encryptedColumns {
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf(
+			"SELECT %s, %s FROM %s WHERE %s IS NOT NULL", col.key, col.column, col.table, col.column))
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", col.table, err)
+		}
+		for rows.Next() {
+			var id string
+			var blob []byte
+			if err := rows.Scan(&id, &blob); err != nil {
+				rows.Close()
+				return 0, fmt.Errorf("failed to scan %s: %w", col.table, err)
+			}
+			if len(blob) == 0 {
+				continue
+			}
+			plaintext, err := from.Decrypt(blob)
+			if err != nil {
+				rows.Close()
+				return 0, fmt.Errorf("failed to decrypt %s.%s for %q (wrong old key?): %w", col.table, col.column, id, err)
+			}
+			secrets = append(secrets, encryptedSecret{col.table, col.key, col.column, id, plaintext})
+		}
+		if err := rows.Close(); err != nil {
+			return 0, err
+		}
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", col.table, err)
+		}
+	}
+
+	for _, secret := range secrets {
+		blob, err := to.Encrypt(secret.plaintext)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encrypt %s for %q: %w", secret.table, secret.id, err)
+		}
+		// Round-trip before writing so a broken new key can never be committed
+		if check, err := to.Decrypt(blob); err != nil || check != secret.plaintext {
+			return 0, fmt.Errorf("failed to verify re-encrypted %s for %q", secret.table, secret.id)
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+			"UPDATE %s SET %s = ?, updated_at = datetime('now') WHERE %s = ?", secret.table, secret.column, secret.key),
+			blob, secret.id); err != nil {
+			return 0, fmt.Errorf("failed to update %s for %q: %w", secret.table, secret.id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit: %w", err)
+	}
+	return len(secrets), nil
+}
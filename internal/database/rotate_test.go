@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	schedcrypto "github.com/dtorcivia/schedlock/internal/crypto"
+)
+
+func TestRotateEncryptionKey(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	oldKey, _ := schedcrypto.NewEncryptor("old-secret")
+	newKey, _ := schedcrypto.NewEncryptor("new-secret")
+	wrongKey, _ := schedcrypto.NewEncryptor("wrong-secret")
+
+	creds, _ := oldKey.Encrypt(`{"topic":"approvals"}`)
+	token, _ := oldKey.Encrypt("refresh-token")
+	if _, err := db.Exec(`INSERT INTO notification_credentials (provider, enabled, credentials_enc) VALUES ('ntfy', 1, ?)`, creds); err != nil {
+		t.Fatalf("insert credentials: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO oauth_tokens (id, refresh_token_enc) VALUES ('primary', ?)`, token); err != nil {
+		t.Fatalf("insert token: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := db.RotateEncryptionKey(ctx, wrongKey, newKey); err == nil {
+		t.Fatal("expected rotation with the wrong old key to fail")
+	}
+	var blob []byte
+	db.QueryRow(`SELECT refresh_token_enc FROM oauth_tokens`).Scan(&blob)
+	if got, err := oldKey.Decrypt(blob); err != nil || got != "refresh-token" {
+		t.Fatalf("failed rotation modified data: %q, %v", got, err)
+	}
+
+	count, err := db.RotateEncryptionKey(ctx, oldKey, newKey)
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("rotated %d secrets, want 2", count)
+	}
+
+	db.QueryRow(`SELECT refresh_token_enc FROM oauth_tokens`).Scan(&blob)
+	if got, err := newKey.Decrypt(blob); err != nil || got != "refresh-token" {
+		t.Errorf("token under new key = %q, %v", got, err)
+	}
+	if _, err := oldKey.Decrypt(blob); err == nil {
+		t.Error("token still decrypts with the old key")
+	}
+	db.QueryRow(`SELECT credentials_enc FROM notification_credentials`).Scan(&blob)
+	if got, err := newKey.Decrypt(blob); err != nil || got != `{"topic":"approvals"}` {
+		t.Errorf("credentials under new key = %q, %v", got, err)
+	}
+}
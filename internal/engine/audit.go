@@ -3,16 +3,32 @@ package engine
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/dtorcivia/schedlock/internal/database"
 	"github.com/dtorcivia/schedlock/internal/util"
 )
 
+// auditChainMu serializes audit inserts so each row links to the row
+// before it. It is package-level because several AuditLoggers may share a database.
+var auditChainMu sync.Mutex
+
+// AuditSink receives each audit entry after it is stored. Publish is called
+// on the logging goroutine and must not block.
+type AuditSink interface {
+	Publish(entry database.AuditLogEntry)
+}
+
 // AuditLogger handles audit log entries.
 type AuditLogger struct {
-	db *database.DB
+	db    *database.DB
+	sinks []AuditSink
 }
 
 // NewAuditLogger creates a new audit logger.
@@ -20,38 +36,168 @@ func NewAuditLogger(db *database.DB) *AuditLogger {
 	return &AuditLogger{db: db}
 }
 
-// Log records an audit event.
+// SetSink sets where stored entries are streamed, e.g. the audit webhook,
+// replacing any sinks added before.
+func (a *AuditLogger) SetSink(sink AuditSink) {
+	a.sinks = []AuditSink{sink}
+}
+
+// AddSink streams stored entries to another sink as well.
+func (a *AuditLogger) AddSink(sink AuditSink) {
+	a.sinks = append(a.sinks, sink)
+}
+
+// Log records an audit event, with the client IP if ctx comes from an HTTP request.
 func (a *AuditLogger) Log(ctx context.Context, eventType, requestID, apiKeyID, actor string, details map[string]interface{}) {
+	a.LogWithIP(ctx, eventType, requestID, apiKeyID, actor, util.ClientIPFromContext(ctx), details)
+}
+
+// LogWithIP records an audit event with IP address.
+func (a *AuditLogger) LogWithIP(ctx context.Context, eventType, requestID, apiKeyID, actor, ipAddress string, details map[string]interface{}) {
 	var detailsJSON []byte
 	if details != nil {
 		detailsJSON, _ = json.Marshal(details)
 	}
 
-	_, err := a.db.ExecContext(ctx, `
-		INSERT INTO audit_log (event_type, request_id, api_key_id, actor, details)
-		VALUES (?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)
-	`, eventType, requestID, apiKeyID, actor, string(detailsJSON))
-
+	entry, err := a.insert(ctx, eventType, requestID, apiKeyID, actor, string(detailsJSON), ipAddress)
 	if err != nil {
 		util.Error("Failed to write audit log", "error", err, "event_type", eventType)
+		return
+	}
+	for _, sink := range a.sinks {
+		sink.Publish(*entry)
 	}
 }
 
-// LogWithIP records an audit event with IP address.
-func (a *AuditLogger) LogWithIP(ctx context.Context, eventType, requestID, apiKeyID, actor, ipAddress string, details map[string]interface{}) {
-	var detailsJSON []byte
-	if details != nil {
-		detailsJSON, _ = json.Marshal(details)
+// insert writes an audit row linked to the previous row's hash. Hashing
+// happens only here, so reads and verification never rewrite rows.
+func (a *AuditLogger) insert(ctx context.Context, eventType, requestID, apiKeyID, actor, details, ipAddress string) (*database.AuditLogEntry, error) {
+	auditChainMu.Lock()
+	defer auditChainMu.Unlock()
+
+	now := time.Now()
+	timestamp := util.SQLiteTimestamp(now)
+	var id int64
+
+	// The write lock is taken before reading the previous hash, so another
+	// process cannot commit in between and fail the insert
+	err := a.db.ImmediateTx(ctx, func(conn *sql.Conn) error {
+		var prevHash sql.NullString
+		err := conn.QueryRowContext(ctx, `
+			SELECT hash FROM audit_log WHERE hash IS NOT NULL ORDER BY id DESC LIMIT 1
+		`).Scan(&prevHash)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to read previous audit hash: %w", err)
+		}
+
+		hash := auditHash(prevHash.String, timestamp, eventType, requestID, apiKeyID, actor, details, ipAddress)
+		result, err := conn.ExecContext(ctx, `
+			INSERT INTO audit_log (timestamp, event_type, request_id, api_key_id, actor, details, ip_address, prev_hash, hash)
+			VALUES (?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?, ?)
+		`, timestamp, eventType, requestID, apiKeyID, actor, details, ipAddress, prevHash.String, hash)
+		if err != nil {
+			return err
+		}
+		id, err = result.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	_, err := a.db.ExecContext(ctx, `
-		INSERT INTO audit_log (event_type, request_id, api_key_id, actor, details, ip_address)
-		VALUES (?, NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?)
-	`, eventType, requestID, apiKeyID, actor, string(detailsJSON), ipAddress)
+	entry := &database.AuditLogEntry{
+		ID:        id,
+		Timestamp: now.UTC(),
+		EventType: eventType,
+		RequestID: sql.NullString{String: requestID, Valid: requestID != ""},
+		APIKeyID:  sql.NullString{String: apiKeyID, Valid: apiKeyID != ""},
+		Actor:     sql.NullString{String: actor, Valid: actor != ""},
+		IPAddress: sql.NullString{String: ipAddress, Valid: ipAddress != ""},
+	}
+	if details != "" {
+		entry.Details = json.RawMessage(details)
+	}
+	return entry, nil
+}
 
+// auditHash computes the chain hash for a row. Fields are JSON-encoded as an
+// array so no separator inside a value can make two rows hash alike.
+func auditHash(prevHash, timestamp, eventType, requestID, apiKeyID, actor, details, ipAddress string) string {
+	data, _ := json.Marshal([]string{prevHash, timestamp, eventType, requestID, apiKeyID, actor, details, ipAddress})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ChainVerification reports the result of checking the audit hash chain.
+type ChainVerification struct {
+	Valid         bool   `json:"valid"`
+	Verified      int    `json:"verified_entries"`
+	LegacyEntries int    `json:"legacy_entries"` // rows written before hashing was enabled
+	BrokenID      int64  `json:"broken_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// VerifyChain walks the audit log in insert order and reports the first row
+// whose hash or link to the previous row does not match. Retention cleanup
+// removes the oldest rows, so the first remaining row's stored previous hash
+// is taken as the anchor.
+func (a *AuditLogger) VerifyChain(ctx context.Context) (*ChainVerification, error) {
+	rows, err := a.db.Reader().QueryContext(ctx, `
+		SELECT id, timestamp, event_type, request_id, api_key_id, actor, details, ip_address, prev_hash, hash
+		FROM audit_log
+		ORDER BY id ASC
+	`)
 	if err != nil {
-		util.Error("Failed to write audit log", "error", err, "event_type", eventType)
+		return nil, err
 	}
+	defer rows.Close()
+
+	result := &ChainVerification{Valid: true}
+	var (
+		expectedPrev string
+		started      bool
+	)
+
+	for rows.Next() {
+		var (
+			id                                                  int64
+			timestamp, eventType                                string
+			requestID, apiKeyID, actor, details, ip, prev, hash sql.NullString
+		)
+		if err := rows.Scan(&id, &timestamp, &eventType, &requestID, &apiKeyID, &actor, &details, &ip, &prev, &hash); err != nil {
+			return nil, err
+		}
+
+		if !hash.Valid {
+			if !started {
+				result.LegacyEntries++
+				continue
+			}
+			return result.broken(id, "entry has no hash"), nil
+		}
+
+		if started && prev.String != expectedPrev {
+			return result.broken(id, "previous hash does not match the preceding entry"), nil
+		}
+		started = true
+
+		computed := auditHash(prev.String, timestamp, eventType, requestID.String, apiKeyID.String, actor.String, details.String, ip.String)
+		if computed != hash.String {
+			return result.broken(id, "entry contents do not match its hash"), nil
+		}
+
+		expectedPrev = hash.String
+		result.Verified++
+	}
+
+	return result, rows.Err()
+}
+
+func (v *ChainVerification) broken(id int64, reason string) *ChainVerification {
+	v.Valid = false
+	v.BrokenID = id
+	v.Reason = reason
+	return v
 }
 
 // GetRecent retrieves recent audit entries.
@@ -60,7 +206,7 @@ func (a *AuditLogger) GetRecent(ctx context.Context, limit int) ([]database.Audi
 		limit = 50
 	}
 
-	rows, err := a.db.QueryContext(ctx, `
+	rows, err := a.db.Reader().QueryContext(ctx, `
 		SELECT id, timestamp, event_type, request_id, api_key_id, actor, details, ip_address
 		FROM audit_log
 		ORDER BY timestamp DESC
@@ -101,7 +247,7 @@ func (a *AuditLogger) GetRecent(ctx context.Context, limit int) ([]database.Audi
 
 // GetByRequestID retrieves audit entries for a specific request.
 func (a *AuditLogger) GetByRequestID(ctx context.Context, requestID string) ([]database.AuditLogEntry, error) {
-	rows, err := a.db.QueryContext(ctx, `
+	rows, err := a.db.Reader().QueryContext(ctx, `
 		SELECT id, timestamp, event_type, request_id, api_key_id, actor, details, ip_address
 		FROM audit_log
 		WHERE request_id = ?
@@ -140,13 +286,62 @@ func (a *AuditLogger) GetByRequestID(ctx context.Context, requestID string) ([]d
 	return entries, rows.Err()
 }
 
+// GetByRequestTag retrieves recent audit entries for requests carrying the
+// given tag.
+func (a *AuditLogger) GetByRequestTag(ctx context.Context, tag string, limit int) ([]database.AuditLogEntry, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	rows, err := a.db.Reader().QueryContext(ctx, `
+		SELECT id, timestamp, event_type, request_id, api_key_id, actor, details, ip_address
+		FROM audit_log
+		WHERE request_id IN (
+			SELECT r.id FROM requests r, json_each(r.tags) t WHERE t.value = ?
+		)
+		ORDER BY timestamp DESC
+		LIMIT ?
+	`, tag, limit)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []database.AuditLogEntry
+	for rows.Next() {
+		var (
+			entry       database.AuditLogEntry
+			timestamp   string
+			detailsJSON []byte
+		)
+
+		if err := rows.Scan(
+			&entry.ID, &timestamp, &entry.EventType,
+			&entry.RequestID, &entry.APIKeyID, &entry.Actor,
+			&detailsJSON, &entry.IPAddress,
+		); err != nil {
+			return nil, err
+		}
+
+		entry.Timestamp, _ = util.ParseSQLiteTimestamp(timestamp)
+		if len(detailsJSON) > 0 {
+			entry.Details = detailsJSON
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
 // GetByEventType retrieves audit entries of a specific type.
 func (a *AuditLogger) GetByEventType(ctx context.Context, eventType string, limit int) ([]database.AuditLogEntry, error) {
 	if limit <= 0 {
 		limit = 50
 	}
 
-	rows, err := a.db.QueryContext(ctx, `
+	rows, err := a.db.Reader().QueryContext(ctx, `
 		SELECT id, timestamp, event_type, request_id, api_key_id, actor, details, ip_address
 		FROM audit_log
 		WHERE event_type = ?
@@ -186,10 +381,81 @@ func (a *AuditLogger) GetByEventType(ctx context.Context, eventType string, limi
 	return entries, rows.Err()
 }
 
+// AuditQuery filters and pages audit entries. Empty filters match every
+// entry.
+type AuditQuery struct {
+	APIKeyID  string
+	EventType string
+	Limit     int
+	Offset    int
+}
+
+// Query returns the newest audit entries matching q and the total number of
+// matches.
+func (a *AuditLogger) Query(ctx context.Context, q AuditQuery) ([]database.AuditLogEntry, int, error) {
+	if q.Limit <= 0 {
+		q.Limit = 50
+	}
+
+	where := "1 = 1"
+	var args []interface{}
+	if q.APIKeyID != "" {
+		where += " AND api_key_id = ?"
+		args = append(args, q.APIKeyID)
+	}
+	if q.EventType != "" {
+		where += " AND event_type = ?"
+		args = append(args, q.EventType)
+	}
+
+	var total int
+	if err := a.db.Reader().QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_log WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := a.db.Reader().QueryContext(ctx, `
+		SELECT id, timestamp, event_type, request_id, api_key_id, actor, details, ip_address
+		FROM audit_log
+		WHERE `+where+`
+		ORDER BY timestamp DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, append(args, q.Limit, q.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []database.AuditLogEntry{}
+	for rows.Next() {
+		var (
+			entry       database.AuditLogEntry
+			timestamp   string
+			detailsJSON []byte
+		)
+
+		if err := rows.Scan(
+			&entry.ID, &timestamp, &entry.EventType,
+			&entry.RequestID, &entry.APIKeyID, &entry.Actor,
+			&detailsJSON, &entry.IPAddress,
+		); err != nil {
+			return nil, 0, err
+		}
+
+		entry.Timestamp, _ = util.ParseSQLiteTimestamp(timestamp)
+		if len(detailsJSON) > 0 {
+			entry.Details = detailsJSON
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, total, rows.Err()
+}
+
 // Count returns the total number of audit entries.
 func (a *AuditLogger) Count(ctx context.Context) (int, error) {
 	var count int
-	err := a.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_log`).Scan(&count)
+	err := a.db.Reader().QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_log`).Scan(&count)
 	return count, err
 }
 
@@ -0,0 +1,234 @@
+package engine
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/dtorcivia/schedlock/internal/database"
+)
+
+func newTestAuditLogger(t *testing.T) (*AuditLogger, *database.DB) {
+	t.Helper()
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewAuditLogger(db), db
+}
+
+func logEntries(ctx context.Context, logger *AuditLogger, n int) {
+	for i := 0; i < n; i++ {
+		logger.LogWithIP(ctx, database.AuditLoginSuccess, "", "", "web:admin", "10.0.0.1", map[string]interface{}{"n": i})
+	}
+}
+
+func TestVerifyChainValid(t *testing.T) {
+	ctx := context.Background()
+	logger, db := newTestAuditLogger(t)
+
+	// A row from before hashing was enabled is reported, not treated as tampering
+	if _, err := db.Exec(`INSERT INTO audit_log (event_type, actor) VALUES ('login_success', 'legacy')`); err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+	logEntries(ctx, logger, 5)
+
+	result, err := logger.VerifyChain(ctx)
+	if err != nil {
+		t.Fatalf("VerifyChain() error = %v", err)
+	}
+	if !result.Valid || result.Verified != 5 || result.LegacyEntries != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestVerifyChainDetectsEdits(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		tamper   string
+		brokenID int64
+	}{
+		{"edited details", `UPDATE audit_log SET details = '{"n":99}' WHERE id = 3`, 3},
+		{"edited actor", `UPDATE audit_log SET actor = 'someone' WHERE id = 2`, 2},
+		{"deleted row", `DELETE FROM audit_log WHERE id = 3`, 4},
+		{"unhashed insert", `INSERT INTO audit_log (event_type, actor) VALUES ('login_success', 'forged')`, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, db := newTestAuditLogger(t)
+			logEntries(ctx, logger, 5)
+
+			if _, err := db.Exec(tt.tamper); err != nil {
+				t.Fatalf("tamper failed: %v", err)
+			}
+
+			result, err := logger.VerifyChain(ctx)
+			if err != nil {
+				t.Fatalf("VerifyChain() error = %v", err)
+			}
+			if result.Valid || result.BrokenID != tt.brokenID {
+				t.Errorf("expected break at %d, got %+v", tt.brokenID, result)
+			}
+		})
+	}
+}
+
+func TestVerifyChainAfterRetentionCleanup(t *testing.T) {
+	ctx := context.Background()
+	logger, db := newTestAuditLogger(t)
+	logEntries(ctx, logger, 5)
+
+	// Retention removes the oldest rows; the remaining chain is still intact
+	if _, err := db.Exec(`DELETE FROM audit_log WHERE id <= 2`); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	logEntries(ctx, logger, 2)
+
+	result, err := logger.VerifyChain(ctx)
+	if err != nil {
+		t.Fatalf("VerifyChain() error = %v", err)
+	}
+	if !result.Valid || result.Verified != 5 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+type recordingSink struct {
+	entries []database.AuditLogEntry
+}
+
+func (s *recordingSink) Publish(entry database.AuditLogEntry) {
+	s.entries = append(s.entries, entry)
+}
+
+func TestLogPublishesStoredEntries(t *testing.T) {
+	ctx := context.Background()
+	logger, _ := newTestAuditLogger(t)
+	sink := &recordingSink{}
+	logger.SetSink(sink)
+
+	logEntries(ctx, logger, 2)
+	logger.Log(ctx, database.AuditSettingsChanged, "", "", "web:admin", nil)
+
+	if len(sink.entries) != 3 {
+		t.Fatalf("published %d entries, want 3", len(sink.entries))
+	}
+	stored, err := logger.GetRecent(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetRecent() error = %v", err)
+	}
+	// GetRecent returns newest first
+	for i, entry := range sink.entries {
+		if entry.ID != stored[len(stored)-1-i].ID {
+			t.Errorf("entry %d has ID %d, want %d", i, entry.ID, stored[len(stored)-1-i].ID)
+		}
+	}
+	last := sink.entries[2]
+	if last.EventType != database.AuditSettingsChanged || last.Actor.String != "web:admin" || last.APIKeyID.Valid || last.Details != nil {
+		t.Errorf("unexpected entry: %+v", last)
+	}
+	if string(sink.entries[0].Details) != `{"n":0}` || sink.entries[0].IPAddress.String != "10.0.0.1" {
+		t.Errorf("unexpected first entry: %+v", sink.entries[0])
+	}
+}
+
+func TestQueryFiltersByKey(t *testing.T) {
+	ctx := context.Background()
+	logger, db := newTestAuditLogger(t)
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_a', 'hash_a', 'sk_a', 'key_a', 'write'), ('key_b', 'hash_b', 'sk_b', 'key_b', 'write')
+	`); err != nil {
+		t.Fatalf("insert api keys: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		logger.Log(ctx, database.AuditRequestCreated, "", "key_a", "api", map[string]interface{}{"n": i})
+	}
+	logger.Log(ctx, database.AuditRequestApproved, "", "key_a", "api", nil)
+	logger.Log(ctx, database.AuditRequestCreated, "", "key_b", "api", nil)
+
+	entries, total, err := logger.Query(ctx, AuditQuery{APIKeyID: "key_a", Limit: 2, Offset: 1})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if total != 6 || len(entries) != 2 {
+		t.Fatalf("expected 2 of 6 entries, got %d of %d", len(entries), total)
+	}
+	for _, entry := range entries {
+		if entry.APIKeyID.String != "key_a" {
+			t.Errorf("entry %d belongs to %q", entry.ID, entry.APIKeyID.String)
+		}
+	}
+	// Newest first: the offset skips the approval logged last
+	if entries[0].ID <= entries[1].ID || entries[0].EventType != database.AuditRequestCreated {
+		t.Errorf("unexpected order: %+v", entries)
+	}
+
+	if _, total, _ = logger.Query(ctx, AuditQuery{APIKeyID: "key_a", EventType: database.AuditRequestApproved}); total != 1 {
+		t.Errorf("expected 1 approval for key_a, got %d", total)
+	}
+}
+
+func TestAuditInsertWithConcurrentWriters(t *testing.T) {
+	ctx := context.Background()
+	logger, db := newTestAuditLogger(t)
+
+	// A second handle stands in for another process, such as the create-key
+	// CLI, writing to the same file outside this process's audit lock
+	other, err := database.Open(db.Path())
+	if err != nil {
+		t.Fatalf("Failed to open second handle: %v", err)
+	}
+	defer other.Close()
+	if _, err := other.Exec(`CREATE TABLE churn (n INTEGER)`); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+
+	const loggers, entries = 2, 200
+	done := make(chan struct{})
+	var writers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		writers.Add(1)
+		go func() {
+			defer writers.Done()
+			for n := 0; ; n++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				other.Exec(`INSERT INTO churn (n) VALUES (?)`, n)
+			}
+		}()
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < loggers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logEntries(ctx, logger, entries)
+		}()
+	}
+	wg.Wait()
+	close(done)
+	writers.Wait()
+
+	count, err := logger.Count(ctx)
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != loggers*entries {
+		t.Errorf("stored %d audit entries, want %d", count, loggers*entries)
+	}
+	result, err := logger.VerifyChain(ctx)
+	if err != nil || !result.Valid {
+		t.Errorf("chain should verify, got %+v (err %v)", result, err)
+	}
+}
@@ -6,7 +6,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand"
 	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"google.golang.org/api/googleapi"
@@ -31,6 +36,44 @@ type Engine struct {
 	executionQueue *ExecutionQueue
 	auditLogger    *AuditLogger
 	tokenRepo      *tokens.Repository
+	keyLookup      KeyLookup
+
+	resendMu   sync.Mutex
+	lastResend map[string]time.Time // request ID -> last manual resend
+
+	webhookMu     sync.Mutex
+	webhookTails  map[string]chan struct{}  // request ID -> closed when its latest webhook is done
+	webhookLatest map[string]*latestWebhook // request ID -> its most recently scheduled webhook
+
+	diffMu      sync.Mutex
+	updateDiffs map[string][]google.Diff // request ID -> changes captured before an update ran
+}
+
+// Errors returned by ResendApprovalNotification and RetryFailedRequest.
+var (
+	ErrRequestNotFound   = errors.New("request not found")
+	ErrRequestNotPending = errors.New("request is not pending approval")
+	ErrRequestNotFailed  = errors.New("only failed requests can be retried")
+)
+
+// PendingLimitError is returned by SubmitRequest when the key already has
+// its maximum number of requests awaiting approval.
+type PendingLimitError struct {
+	Limit   int
+	Pending int
+}
+
+func (e *PendingLimitError) Error() string {
+	return fmt.Sprintf("too many pending requests: %d of %d allowed are awaiting approval", e.Pending, e.Limit)
+}
+
+// ResendCooldownError is returned when a resend is attempted too soon after the previous one.
+type ResendCooldownError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ResendCooldownError) Error() string {
+	return fmt.Sprintf("notification was resent recently; try again in %s", e.RetryAfter.Round(time.Second))
 }
 
 // NotificationManager interface for sending approval notifications.
@@ -38,18 +81,47 @@ type NotificationManager interface {
 	SendApprovalRequest(ctx context.Context, req *notifications.ApprovalNotification) error
 }
 
+// EscalationNotifier is implemented by notifiers that can send an approval
+// request to an alternate recipient through one provider.
+type EscalationNotifier interface {
+	SendEscalation(ctx context.Context, providerName, recipient string, notification *notifications.ApprovalNotification) error
+}
+
+// ResultNotifier is implemented by notifiers that can report a request's
+// outcome to the approver channels.
+type ResultNotifier interface {
+	SendResult(ctx context.Context, notification *notifications.ResultNotification) error
+}
+
+// DeliveryChecker is implemented by notifiers that can tell whether every
+// provider already delivered a request's approval notification.
+type DeliveryChecker interface {
+	AlreadyDelivered(ctx context.Context, notification *notifications.ApprovalNotification) bool
+}
+
 // WebhookClient interface for sending Moltbot webhooks.
 type WebhookClient interface {
 	Deliver(ctx context.Context, event WebhookEvent) error
 }
 
+// KeyLookup loads an API key, for per-key settings applied at execution.
+type KeyLookup interface {
+	GetByID(ctx context.Context, id string) (*database.APIKey, error)
+}
+
 // WebhookEvent contains data for Moltbot webhook.
 type WebhookEvent struct {
 	RequestID  string
 	Status     string
 	Message    string
 	Suggestion string
+	Reason     string // approver's note on a decision
 	Result     json.RawMessage
+	Sequence   int64         // per-request, increases with each event
+	Diff       []google.Diff // update requests only, when enabled
+	// Destinations, when set, limits delivery to the named destinations,
+	// for an event the debounce dropped for the others.
+	Destinations []string
 }
 
 // NewEngine creates a new engine instance.
@@ -66,6 +138,10 @@ func NewEngine(
 		calendarClient: calendarClient,
 		auditLogger:    auditLogger,
 		tokenRepo:      tokenRepo,
+		lastResend:     make(map[string]time.Time),
+		webhookTails:   make(map[string]chan struct{}),
+		webhookLatest:  make(map[string]*latestWebhook),
+		updateDiffs:    make(map[string][]google.Diff),
 	}
 
 	// Create execution queue with single worker
@@ -84,6 +160,11 @@ func (e *Engine) SetWebhookClient(c WebhookClient) {
 	e.webhookClient = c
 }
 
+// SetKeyLookup sets where per-key event defaults are read from.
+func (e *Engine) SetKeyLookup(k KeyLookup) {
+	e.keyLookup = k
+}
+
 // Start starts the execution queue workers.
 func (e *Engine) Start(ctx context.Context) {
 	e.executionQueue.Start(ctx)
@@ -99,21 +180,37 @@ func (e *Engine) QueueExecution(requestID string) {
 	e.executionQueue.Enqueue(requestID)
 }
 
+// QueuePosition reports how many requests will execute before an approved
+// request waiting in the execution queue. ok is false if it is not waiting,
+// for example while it runs or during a retry backoff.
+func (e *Engine) QueuePosition(requestID string) (ahead int, ok bool) {
+	return e.executionQueue.Position(requestID)
+}
+
 // NotifyWebhookStatus sends a webhook status update.
 func (e *Engine) NotifyWebhookStatus(ctx context.Context, requestID, status string) {
 	e.notifyWebhook(ctx, requestID, status)
 }
 
-// SubmitRequest creates a new request and sends notifications.
+// SubmitRequest creates a new request and sends notifications. The
+// constraint decision says whether the request waits for approval, and its
+// deciding constraint is recorded in the audit log. A positive timeout
+// replaces the configured approval timeout for this request.
 func (e *Engine) SubmitRequest(
 	ctx context.Context,
 	authKey *apikeys.AuthenticatedKey,
 	operation string,
 	payload json.RawMessage,
+	requestContext string,
+	tags []string,
 	idempotencyKey string,
-	approvalRequired bool,
+	priority string,
+	timeout time.Duration,
+	decision apikeys.ConstraintDecision,
 	decidedBy string,
 ) (*database.Request, error) {
+	approvalRequired := decision.RequiresApproval()
+
 	// Check idempotency key first
 	if idempotencyKey != "" {
 		existing, err := e.requestRepo.FindByIdempotencyKey(ctx, authKey.ID, idempotencyKey)
@@ -129,16 +226,44 @@ func (e *Engine) SubmitRequest(
 		}
 	}
 
-	// Calculate expiry time
-	expiresAt := time.Now().Add(time.Duration(e.config.Approval.TimeoutMinutes) * time.Minute)
+	// Without an idempotency key, opted-in keys still collapse accidental
+	// resubmissions of the same payload
+	if idempotencyKey == "" && authKey.Constraints != nil && authKey.Constraints.DedupContent {
+		if existing := e.findDuplicateContent(ctx, authKey.ID, operation, payload); existing != nil {
+			return existing, nil
+		}
+	}
+
+	// Calculate expiry time; decision tokens expire with the request
+	if timeout <= 0 {
+		timeout = time.Duration(e.config.Approval.TimeoutMinutes) * time.Minute
+	}
+	expiresAt := time.Now().Add(timeout)
 
-	// Create the request
-	req, err := e.requestRepo.Create(ctx, &requests.CreateRequest{
+	create := &requests.CreateRequest{
 		APIKeyID:  authKey.ID,
 		Operation: operation,
 		Payload:   payload,
 		ExpiresAt: expiresAt,
-	})
+		Priority:  priority,
+		Context:   requestContext,
+		Tags:      tags,
+	}
+
+	// Create the request. Auto-approved requests never wait in the queue, so
+	// only these count toward the pending limit.
+	var req *database.Request
+	var err error
+	if approvalRequired && authKey.Constraints != nil && authKey.Constraints.MaxPendingRequests > 0 {
+		limit := authKey.Constraints.MaxPendingRequests
+		var pending int
+		req, pending, err = e.requestRepo.CreateWithinLimit(ctx, create, limit)
+		if err == nil && req == nil {
+			return nil, &PendingLimitError{Limit: limit, Pending: pending}
+		}
+	} else {
+		req, err = e.requestRepo.Create(ctx, create)
+	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -153,7 +278,12 @@ func (e *Engine) SubmitRequest(
 
 	// Log to audit
 	e.auditLogger.Log(ctx, database.AuditRequestCreated, req.ID, authKey.ID, "api", map[string]interface{}{
-		"operation": operation,
+		"operation":           operation,
+		"priority":            req.Priority,
+		"tags":                req.Tags,
+		"decision":            decision.Result.String(),
+		"decision_constraint": decision.Constraint,
+		"decision_reason":     decision.Reason,
 	})
 
 	if approvalRequired {
@@ -180,8 +310,40 @@ func (e *Engine) SubmitRequest(
 	return req, nil
 }
 
+// ContentDedupWindow is how far back content deduplication looks for an
+// identical request from the same key.
+const ContentDedupWindow = 10 * time.Minute
+
+// findDuplicateContent returns a recent request from the key with the same
+// operation and payload, or nil. Lookup errors fall through to creating a
+// new request, since deduplication is best-effort.
+func (e *Engine) findDuplicateContent(ctx context.Context, apiKeyID, operation string, payload json.RawMessage) *database.Request {
+	hash, err := requests.ContentHash(operation, payload)
+	if err != nil {
+		return nil
+	}
+	existing, err := e.requestRepo.FindByContentHash(ctx, apiKeyID, operation, hash, time.Now().Add(-ContentDedupWindow))
+	if err != nil {
+		util.Warn("Content dedup check failed", "error", err)
+		return nil
+	}
+	if existing != nil {
+		util.Info("Returning existing request with identical content",
+			"request_id", existing.ID,
+			"operation", operation,
+		)
+	}
+	return existing
+}
+
 // ProcessApproval handles an approval decision.
 func (e *Engine) ProcessApproval(ctx context.Context, requestID, action, decidedBy string) error {
+	return e.ProcessApprovalWithReason(ctx, requestID, action, decidedBy, "")
+}
+
+// ProcessApprovalWithReason handles an approval decision with the approver's
+// reason, which is recorded in the audit log and sent to the webhook.
+func (e *Engine) ProcessApprovalWithReason(ctx context.Context, requestID, action, decidedBy, reason string) error {
 	var newStatus string
 	switch action {
 	case "approve":
@@ -212,20 +374,34 @@ func (e *Engine) ProcessApproval(ctx context.Context, requestID, action, decided
 	if action == "deny" {
 		auditEvent = database.AuditRequestDenied
 	}
-	e.auditLogger.Log(ctx, auditEvent, requestID, "", decidedBy, nil)
+	var details map[string]interface{}
+	if reason != "" {
+		details = map[string]interface{}{"reason": reason}
+	}
+	e.auditLogger.Log(ctx, auditEvent, requestID, "", decidedBy, details)
+
+	// Capture the diff before execution can change the event
+	if action == "approve" {
+		e.captureUpdateDiff(ctx, requestID)
+	}
+
+	// Reserve the webhook's place before execution can report completion
+	if send := e.scheduleWebhook(requestID, newStatus, reason, ""); send != nil {
+		go send(context.Background())
+	}
 
 	// If approved, queue for execution
 	if action == "approve" {
 		e.executionQueue.Enqueue(requestID)
+	} else {
+		go e.sendResultNotification(context.Background(), requestID, newStatus, reason)
 	}
 
-	// Send webhook notification
-	go e.notifyWebhook(context.Background(), requestID, newStatus)
-
 	util.Info("Request decision processed",
 		"request_id", requestID,
 		"action", action,
 		"decided_by", decidedBy,
+		"reason", reason,
 	)
 
 	return nil
@@ -243,7 +419,9 @@ func (e *Engine) ProcessSuggestion(ctx context.Context, requestID, suggestion, s
 	})
 
 	// Send webhook notification with suggestion
-	go e.notifyWebhookWithSuggestion(context.Background(), requestID, suggestion)
+	if send := e.scheduleWebhook(requestID, database.StatusChangeRequested, "", suggestion); send != nil {
+		go send(context.Background())
+	}
 
 	util.Info("Suggestion recorded",
 		"request_id", requestID,
@@ -269,6 +447,45 @@ func (e *Engine) CancelRequest(ctx context.Context, requestID, apiKeyID string)
 	return nil
 }
 
+// RetryFailedRequest runs a failed request again, for failures that
+// outlasted the automatic retries. The request goes back to approved with
+// its automatic retry count reset, and its manual retry count is increased.
+func (e *Engine) RetryFailedRequest(ctx context.Context, requestID, actor string) error {
+	req, err := e.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if req == nil {
+		return ErrRequestNotFound
+	}
+	if req.Status != database.StatusFailed {
+		return fmt.Errorf("%w (status %s)", ErrRequestNotFailed, req.Status)
+	}
+
+	retried, err := e.requestRepo.RetryFailed(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if !retried {
+		// Another retry got there first
+		return ErrRequestNotFailed
+	}
+
+	e.auditLogger.Log(ctx, database.AuditRequestRetried, requestID, req.APIKeyID, actor, map[string]interface{}{
+		"previous_error":     req.Error.String,
+		"manual_retry_count": req.ManualRetryCount + 1,
+	})
+	e.executionQueue.Enqueue(requestID)
+
+	util.Info("Failed request queued for retry",
+		"request_id", requestID,
+		"actor", actor,
+		"manual_retry_count", req.ManualRetryCount+1,
+	)
+
+	return nil
+}
+
 // ExecuteRequest executes an approved request.
 func (e *Engine) ExecuteRequest(ctx context.Context, requestID string) error {
 	req, err := e.requestRepo.GetByID(ctx, requestID)
@@ -295,9 +512,14 @@ func (e *Engine) ExecuteRequest(ctx context.Context, requestID string) error {
 	case database.OperationCreateEvent:
 		result, execErr = e.executeCreateEvent(ctx, req)
 	case database.OperationUpdateEvent:
+		e.captureUpdateDiff(ctx, requestID)
 		result, execErr = e.executeUpdateEvent(ctx, req)
 	case database.OperationDeleteEvent:
 		execErr = e.executeDeleteEvent(ctx, req)
+	case database.OperationMoveEvent:
+		result, execErr = e.executeMoveEvent(ctx, req)
+	case database.OperationCreateEventsBatch:
+		result, execErr = e.executeCreateEventsBatch(ctx, req)
 	default:
 		execErr = fmt.Errorf("unknown operation: %s", req.Operation)
 	}
@@ -313,7 +535,7 @@ func (e *Engine) ExecuteRequest(ctx context.Context, requestID string) error {
 			e.requestRepo.IncrementRetryCount(ctx, requestID)
 			// Re-queue after backoff
 			go func() {
-				backoff := e.getBackoffDuration(req.RetryCount)
+				backoff := e.getBackoffDuration(req.RetryCount, execErr)
 				time.Sleep(backoff)
 				e.executionQueue.Enqueue(requestID)
 			}()
@@ -325,7 +547,12 @@ func (e *Engine) ExecuteRequest(ctx context.Context, requestID string) error {
 		e.auditLogger.Log(ctx, database.AuditRequestFailed, requestID, req.APIKeyID, "engine", map[string]interface{}{
 			"error": execErr.Error(),
 		})
-		go e.notifyWebhook(context.Background(), requestID, database.StatusFailed)
+		if send := e.scheduleWebhook(requestID, database.StatusFailed, "", ""); send != nil {
+			go send(context.Background())
+		} else {
+			e.takeUpdateDiff(requestID)
+		}
+		go e.sendResultNotification(context.Background(), requestID, database.StatusFailed, "")
 		return execErr
 	}
 
@@ -339,7 +566,12 @@ func (e *Engine) ExecuteRequest(ctx context.Context, requestID string) error {
 	}
 
 	e.auditLogger.Log(ctx, database.AuditRequestCompleted, requestID, req.APIKeyID, "engine", nil)
-	go e.notifyWebhook(context.Background(), requestID, database.StatusCompleted)
+	if send := e.scheduleWebhook(requestID, database.StatusCompleted, "", ""); send != nil {
+		go send(context.Background())
+	} else {
+		e.takeUpdateDiff(requestID)
+	}
+	go e.sendResultNotification(context.Background(), requestID, database.StatusCompleted, "")
 
 	util.Info("Request executed successfully", "request_id", requestID)
 
@@ -353,10 +585,63 @@ func (e *Engine) executeCreateEvent(ctx context.Context, req *database.Request)
 	if err := json.Unmarshal(req.Payload, &intent); err != nil {
 		return nil, fmt.Errorf("invalid payload: %w", err)
 	}
+	intent.StampRequestID(req.ID)
+	intent.ApplyDefaults(e.eventDefaults(ctx, req.APIKeyID))
 
 	return e.calendarClient.CreateEvent(ctx, &intent)
 }
 
+// eventDefaults returns the defaults for events created by a key: the
+// global config with the key's event_defaults constraint layered on top.
+func (e *Engine) eventDefaults(ctx context.Context, apiKeyID string) google.EventDefaults {
+	var keyDefaults *database.EventDefaults
+	if e.keyLookup != nil && apiKeyID != "" {
+		key, err := e.keyLookup.GetByID(ctx, apiKeyID)
+		if err != nil {
+			util.Warn("Failed to load key event defaults", "api_key_id", apiKeyID, "error", err)
+		} else if key != nil && key.Constraints != nil {
+			keyDefaults = key.Constraints.EventDefaults
+		}
+	}
+	return resolveEventDefaults(e.config.EventDefaults, keyDefaults)
+}
+
+// resolveEventDefaults merges global and per-key defaults. Each field the
+// key sets replaces the global value.
+func resolveEventDefaults(global config.EventDefaultsConfig, key *database.EventDefaults) google.EventDefaults {
+	d := google.EventDefaults{
+		DescriptionSuffix: global.DescriptionSuffix,
+		Location:          global.Location,
+		ColorID:           global.ColorID,
+	}
+	for _, reminder := range global.Reminders {
+		// Validated at startup
+		if method, minutes, err := util.ParseReminder(reminder); err == nil {
+			d.Reminders = append(d.Reminders, google.Reminder{Method: method, Minutes: minutes})
+		}
+	}
+
+	if key == nil {
+		return d
+	}
+	if key.DescriptionSuffix != "" {
+		d.DescriptionSuffix = key.DescriptionSuffix
+	}
+	if key.Location != "" {
+		d.Location = key.Location
+	}
+	if key.ColorID != "" {
+		d.ColorID = key.ColorID
+	}
+	if len(key.Reminders) > 0 {
+		d.Reminders = make([]google.Reminder, len(key.Reminders))
+		for i, reminder := range key.Reminders {
+			d.Reminders[i] = google.Reminder{Method: reminder.Method, Minutes: reminder.Minutes}
+		}
+	}
+	return d
+}
+
 func (e *Engine) executeUpdateEvent(ctx context.Context, req *database.Request) (*google.Event, error) {
 	var intent google.EventUpdateIntent
 	if err := json.Unmarshal(req.Payload, &intent); err != nil {
@@ -367,7 +652,9 @@ func (e *Engine) executeUpdateEvent(ctx context.Context, req *database.Request)
 		"request_id", req.ID,
 		"calendar_id", intent.CalendarID,
 		"event_id", intent.EventID,
+		"recurring_edit_scope", intent.RecurringEditScope,
 	)
+	intent.StampRequestID(req.ID)
 
 	return e.calendarClient.UpdateEvent(ctx, &intent)
 }
@@ -387,6 +674,75 @@ func (e *Engine) executeDeleteEvent(ctx context.Context, req *database.Request)
 	return e.calendarClient.DeleteEvent(ctx, &intent)
 }
 
+func (e *Engine) executeMoveEvent(ctx context.Context, req *database.Request) (*google.Event, error) {
+	var intent google.EventMoveIntent
+	if err := json.Unmarshal(req.Payload, &intent); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	util.Debug("Executing move event",
+		"request_id", req.ID,
+		"calendar_id", intent.CalendarID,
+		"event_id", intent.EventID,
+		"destination_calendar_id", intent.DestinationCalendarID,
+	)
+
+	return e.calendarClient.MoveEvent(ctx, &intent)
+}
+
+// executeCreateEventsBatch inserts each event in turn. Failures are recorded
+// per item; the request only fails (and may be retried) when nothing was
+// created, so a retry can never duplicate events that already exist.
+func (e *Engine) executeCreateEventsBatch(ctx context.Context, req *database.Request) (*google.BatchResult, error) {
+	var batch google.EventBatchIntent
+	if err := json.Unmarshal(req.Payload, &batch); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	result := &google.BatchResult{}
+	var firstErr error
+	defaults := e.eventDefaults(ctx, req.APIKeyID)
+	for i := range batch.Events {
+		intent := &batch.Events[i]
+		intent.StampRequestID(req.ID)
+		intent.ApplyDefaults(defaults)
+		item := google.BatchItemResult{CalendarID: intent.CalendarID, Summary: intent.Summary}
+
+		event, err := e.calendarClient.CreateEvent(ctx, intent)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			item.Error = err.Error()
+			result.Failed++
+			util.Warn("Batch event creation failed",
+				"request_id", req.ID,
+				"calendar_id", intent.CalendarID,
+				"error", err,
+			)
+		} else {
+			if event != nil {
+				item.EventID = event.ID
+				item.HtmlLink = event.HtmlLink
+			}
+			result.Succeeded++
+		}
+		result.Results = append(result.Results, item)
+	}
+
+	if result.Succeeded == 0 && firstErr != nil {
+		return nil, fmt.Errorf("all %d events failed: %w", result.Failed, firstErr)
+	}
+	if result.Failed > 0 {
+		e.auditLogger.Log(ctx, database.AuditRequestPartialFailure, req.ID, req.APIKeyID, "engine", map[string]interface{}{
+			"succeeded": result.Succeeded,
+			"failed":    result.Failed,
+		})
+	}
+
+	return result, nil
+}
+
 func (e *Engine) isRetryable(err error) bool {
 	if !e.config.Retry.Enabled {
 		return false
@@ -405,6 +761,11 @@ func (e *Engine) isRetryable(err error) bool {
 		return true
 	}
 
+	// The call never reached Google because the concurrency limit was full
+	if errors.Is(err, google.ErrTooManyCalls) {
+		return true
+	}
+
 	var netErr net.Error
 	if errors.As(err, &netErr) {
 		return netErr.Timeout() || netErr.Temporary()
@@ -413,22 +774,224 @@ func (e *Engine) isRetryable(err error) bool {
 	return false
 }
 
-func (e *Engine) getBackoffDuration(retryCount int) time.Duration {
+// getBackoffDuration returns how long to wait before the next attempt.
+// A Retry-After header from Google takes precedence over the configured
+// strategy, capped at Retry.MaxDelaySeconds so a large value cannot stall
+// the execution worker.
+func (e *Engine) getBackoffDuration(retryCount int, err error) time.Duration {
+	if d, ok := retryAfterFromError(err); ok {
+		if max := time.Duration(e.config.Retry.MaxDelaySeconds) * time.Second; max > 0 && d > max {
+			return max
+		}
+		return d
+	}
+
+	if e.config.Retry.Strategy == config.RetryStrategyExponential {
+		return exponentialBackoff(
+			retryCount,
+			time.Duration(e.config.Retry.BaseDelaySeconds)*time.Second,
+			time.Duration(e.config.Retry.MaxDelaySeconds)*time.Second,
+		)
+	}
+
+	if len(e.config.Retry.BackoffSeconds) == 0 {
+		return 0
+	}
 	if retryCount >= len(e.config.Retry.BackoffSeconds) {
 		retryCount = len(e.config.Retry.BackoffSeconds) - 1
 	}
 	return time.Duration(e.config.Retry.BackoffSeconds[retryCount]) * time.Second
 }
 
+// exponentialBackoff returns base plus a random jitter, so the result lies in
+// [base, min(base*2^(retryCount+1), max)].
+func exponentialBackoff(retryCount int, base, max time.Duration) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	if max < base {
+		max = base
+	}
+
+	ceiling := base
+	for i := 0; i <= retryCount && ceiling < max; i++ {
+		ceiling *= 2
+	}
+	if ceiling > max {
+		ceiling = max
+	}
+
+	return base + time.Duration(rand.Int63n(int64(ceiling-base)+1))
+}
+
+// retryAfterFromError extracts the Retry-After delay from a Google API error.
+func retryAfterFromError(err error) (time.Duration, bool) {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Header == nil {
+		return 0, false
+	}
+	return parseRetryAfter(apiErr.Header.Get("Retry-After"), time.Now())
+}
+
+// parseRetryAfter parses a Retry-After value in either delay-seconds or HTTP-date form.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
 func (e *Engine) sendApprovalNotifications(ctx context.Context, req *database.Request) {
 	if e.notifier == nil {
 		return
 	}
 
+	notification := e.buildApprovalNotification(ctx, req)
+	if err := e.notifier.SendApprovalRequest(ctx, notification); err != nil {
+		util.Error("Failed to send approval notifications", "error", err, "request_id", req.ID)
+	}
+}
+
+// SendExpiryReminder re-sends the approval notification for a pending request
+// that is about to expire.
+func (e *Engine) SendExpiryReminder(ctx context.Context, req *database.Request) error {
+	if e.notifier == nil {
+		return nil
+	}
+
+	notification := e.buildApprovalNotification(ctx, req)
+	notification.Summary = "Reminder: " + notification.Summary
+	notification.Force = true
+	return e.notifier.SendApprovalRequest(ctx, notification)
+}
+
+// SendEscalation sends the approval notification for a request nobody has
+// answered to the escalation recipient, and records it in the audit log.
+func (e *Engine) SendEscalation(ctx context.Context, req *database.Request, providerName, recipient string) error {
+	escalator, ok := e.notifier.(EscalationNotifier)
+	if !ok {
+		return fmt.Errorf("notifications are not configured")
+	}
+
+	notification := e.buildApprovalNotification(ctx, req)
+	notification.Summary = "Escalation: " + notification.Summary
+	if err := escalator.SendEscalation(ctx, providerName, recipient, notification); err != nil {
+		return err
+	}
+
+	e.auditLogger.Log(ctx, database.AuditRequestEscalated, req.ID, req.APIKeyID, "escalation_worker", map[string]interface{}{
+		"provider": providerName,
+	})
+	return nil
+}
+
+// ResendApprovalNotification re-dispatches the approval notification for a
+// request that is still pending, e.g. after the first delivery failed.
+// Providers that already delivered it are skipped unless force is set; if
+// all of them did, notifications.ErrAlreadyDelivered is returned without
+// issuing a token or using up the cooldown. Only token hashes are stored, so
+// every resend issues a fresh decision token; earlier tokens stay valid until
+// the request expires. Resends for the same request are limited to one per
+// Approval.ResendCooldownSeconds.
+func (e *Engine) ResendApprovalNotification(ctx context.Context, requestID, actor string, force bool) error {
+	if e.notifier == nil {
+		return fmt.Errorf("notifications are not configured")
+	}
+
+	req, err := e.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if req == nil {
+		return ErrRequestNotFound
+	}
+	if req.Status != database.StatusPendingApproval {
+		return fmt.Errorf("%w (status %s)", ErrRequestNotPending, req.Status)
+	}
+
+	// Check for a finished delivery before claiming the cooldown or issuing
+	// a token, so the follow-up "resend to all" is not rate limited
+	if checker, ok := e.notifier.(DeliveryChecker); ok && !force {
+		if checker.AlreadyDelivered(ctx, approvalNotification(req, "")) {
+			return notifications.ErrAlreadyDelivered
+		}
+	}
+
+	if err := e.claimResend(req.ID); err != nil {
+		return err
+	}
+
+	notification := e.buildApprovalNotification(ctx, req)
+	notification.Force = force
+	sendErr := e.notifier.SendApprovalRequest(ctx, notification)
+	if errors.Is(sendErr, notifications.ErrAlreadyDelivered) {
+		e.releaseResend(req.ID)
+		return sendErr
+	}
+
+	details := map[string]interface{}{"force": force}
+	if sendErr != nil {
+		details["error"] = sendErr.Error()
+	}
+	e.auditLogger.Log(ctx, database.AuditNotificationResent, req.ID, req.APIKeyID, actor, details)
+
+	return sendErr
+}
+
+// claimResend records a resend for requestID unless one happened within the cooldown.
+func (e *Engine) claimResend(requestID string) error {
+	cooldown := time.Duration(e.config.Approval.ResendCooldownSeconds) * time.Second
+	now := time.Now()
+
+	e.resendMu.Lock()
+	defer e.resendMu.Unlock()
+
+	// Drop entries whose cooldown has passed so the map stays small
+	for id, at := range e.lastResend {
+		if now.Sub(at) >= cooldown {
+			delete(e.lastResend, id)
+		}
+	}
+
+	if last, ok := e.lastResend[requestID]; ok {
+		return &ResendCooldownError{RetryAfter: cooldown - now.Sub(last)}
+	}
+	if cooldown > 0 {
+		e.lastResend[requestID] = now
+	}
+	return nil
+}
+
+// releaseResend drops the cooldown claimed for requestID when nothing was sent.
+func (e *Engine) releaseResend(requestID string) {
+	e.resendMu.Lock()
+	defer e.resendMu.Unlock()
+	delete(e.lastResend, requestID)
+}
+
+// buildApprovalNotification creates the notification payload, including a fresh decision token.
+func (e *Engine) buildApprovalNotification(ctx context.Context, req *database.Request) *notifications.ApprovalNotification {
 	// Create decision token for callbacks if possible
 	var decisionToken string
 	if e.tokenRepo != nil {
-		token, err := e.tokenRepo.Create(ctx, req.ID, req.ExpiresAt)
+		token, err := e.tokenRepo.Create(ctx, req.ID, req.ExpiresAt, false)
 		if err != nil {
 			util.Error("Failed to create decision token", "error", err, "request_id", req.ID)
 		} else {
@@ -436,9 +999,29 @@ func (e *Engine) sendApprovalNotifications(ctx context.Context, req *database.Re
 		}
 	}
 
+	return approvalNotification(req, decisionToken)
+}
+
+// PreviewApprovalNotification builds the approval notification for a
+// request without issuing a decision token; links carry
+// notifications.PreviewDecisionToken instead.
+func (e *Engine) PreviewApprovalNotification(ctx context.Context, requestID string) (*notifications.ApprovalNotification, error) {
+	req, err := e.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, ErrRequestNotFound
+	}
+	return approvalNotification(req, notifications.PreviewDecisionToken), nil
+}
+
+// approvalNotification builds the notification payload for a request.
+func approvalNotification(req *database.Request, decisionToken string) *notifications.ApprovalNotification {
 	// Parse payload to get event details
 	var details *notifications.EventDetails
-	if req.Operation == database.OperationCreateEvent {
+	switch req.Operation {
+	case database.OperationCreateEvent:
 		var intent google.EventIntent
 		if err := json.Unmarshal(req.Payload, &intent); err == nil {
 			details = &notifications.EventDetails{
@@ -448,73 +1031,238 @@ func (e *Engine) sendApprovalNotifications(ctx context.Context, req *database.Re
 				Location:    intent.Location,
 				Attendees:   intent.Attendees,
 				Description: intent.Description,
+
+				OptionalAttendees: intent.OptionalAttendees,
+			}
+		}
+	case database.OperationCreateEventsBatch:
+		var batch google.EventBatchIntent
+		if err := json.Unmarshal(req.Payload, &batch); err == nil && len(batch.Events) > 0 {
+			first := batch.Events[0]
+			details = &notifications.EventDetails{
+				Title:       first.Summary,
+				StartTime:   first.Start,
+				EndTime:     first.End,
+				Location:    first.Location,
+				Attendees:   first.Attendees,
+				Description: first.Description,
+				Calendars:   batch.TargetCalendars(),
+
+				OptionalAttendees: first.OptionalAttendees,
 			}
 		}
 	}
 
 	notification := &notifications.ApprovalNotification{
-		RequestID: req.ID,
-		Operation: req.Operation,
-		Summary:   getOperationSummary(req.Operation, details),
-		Details:   details,
-		ExpiresAt: req.ExpiresAt,
-		ExpiresIn: util.GetDefaultFormatter().FormatExpiresIn(req.ExpiresAt),
+		RequestID:     req.ID,
+		Operation:     req.Operation,
+		Priority:      req.Priority,
+		Summary:       getOperationSummary(req.Operation, details),
+		Details:       details,
+		ExpiresAt:     req.ExpiresAt,
+		ExpiresIn:     util.GetDefaultFormatter().FormatExpiresIn(req.ExpiresAt),
 		DecisionToken: decisionToken,
+		Calendars:     requestCalendars(req),
 		// URLs will be set by the notification manager based on config
 	}
 
-	if err := e.notifier.SendApprovalRequest(ctx, notification); err != nil {
-		util.Error("Failed to send approval notifications", "error", err, "request_id", req.ID)
-	}
+	return notification
 }
 
-func (e *Engine) notifyWebhook(ctx context.Context, requestID, status string) {
-	if e.webhookClient == nil {
-		return
+// requestCalendars lists the calendars a request touches. Moves touch both
+// their source and destination calendars.
+func requestCalendars(req *database.Request) []string {
+	if req.Operation == database.OperationCreateEventsBatch {
+		var batch google.EventBatchIntent
+		if err := json.Unmarshal(req.Payload, &batch); err != nil {
+			return nil
+		}
+		return batch.TargetCalendars()
 	}
-	if !e.shouldNotify(status) {
-		return
+
+	var target struct {
+		CalendarID            string `json:"calendarId"`
+		DestinationCalendarID string `json:"destinationCalendarId"`
 	}
+	if err := json.Unmarshal(req.Payload, &target); err != nil {
+		return nil
+	}
+	var calendars []string
+	for _, calendarID := range []string{target.CalendarID, target.DestinationCalendarID} {
+		if calendarID != "" {
+			calendars = append(calendars, calendarID)
+		}
+	}
+	return calendars
+}
 
+// sendResultNotification reports a finished request to the notification
+// providers configured to send results for the status. reason is the
+// approver's reason for a denial, if any.
+func (e *Engine) sendResultNotification(ctx context.Context, requestID, status, reason string) {
+	notifier, ok := e.notifier.(ResultNotifier)
+	if !ok {
+		return
+	}
 	req, err := e.requestRepo.GetByID(ctx, requestID)
 	if err != nil || req == nil {
 		return
 	}
+	if err := notifier.SendResult(ctx, resultNotification(req, status, reason)); err != nil {
+		util.Error("Failed to send result notifications", "error", err, "request_id", requestID)
+	}
+}
 
-	event := WebhookEvent{
-		RequestID: requestID,
+// resultNotification builds the result payload for a finished request.
+func resultNotification(req *database.Request, status, reason string) *notifications.ResultNotification {
+	notification := &notifications.ResultNotification{
+		RequestID: req.ID,
+		Operation: req.Operation,
 		Status:    status,
 		Message:   buildWebhookMessage(req, status),
 		Result:    req.Result,
 	}
-
-	if err := e.webhookClient.Deliver(ctx, event); err != nil {
-		util.Error("Failed to deliver webhook", "error", err, "request_id", requestID)
-		return
+	if status == database.StatusFailed && req.Error.Valid {
+		// Providers show the error on its own line
+		notification.Message = "Your calendar request failed."
+		notification.Error = req.Error.String
 	}
+	if status == database.StatusDenied {
+		notification.Message = buildDenialMessage(reason)
+	}
+	var event google.Event
+	if len(req.Result) > 0 && json.Unmarshal(req.Result, &event) == nil {
+		notification.EventURL = event.HtmlLink
+	}
+	return notification
+}
 
-	e.requestRepo.SetWebhookNotified(ctx, requestID)
+func (e *Engine) notifyWebhook(ctx context.Context, requestID, status string) {
+	if send := e.scheduleWebhook(requestID, status, "", ""); send != nil {
+		send(ctx)
+	}
 }
 
-func (e *Engine) notifyWebhookWithSuggestion(ctx context.Context, requestID, suggestion string) {
+// scheduleWebhook reserves the request's next webhook sequence number and
+// returns a function that delivers the event once every earlier event for
+// the same request has been delivered or has failed. Call it at the point
+// the status changes so sequence numbers follow status order; the returned
+// function may run in a goroutine. It returns nil when no webhook is due.
+func (e *Engine) scheduleWebhook(requestID, status, reason, suggestion string) func(ctx context.Context) {
 	if e.webhookClient == nil {
-		return
+		return nil
 	}
-	if !e.shouldNotify(database.StatusChangeRequested) {
-		return
+	if !e.shouldNotify(status) {
+		return nil
+	}
+
+	// Hold the lock across the increment so sequence order and queue order agree
+	e.webhookMu.Lock()
+	seq, err := e.requestRepo.NextWebhookSequence(context.Background(), requestID)
+	if err != nil {
+		e.webhookMu.Unlock()
+		util.Error("Failed to reserve webhook sequence", "error", err, "request_id", requestID)
+		return nil
+	}
+	prev := e.webhookTails[requestID]
+	done := make(chan struct{})
+	e.webhookTails[requestID] = done
+	if superseded := e.webhookLatest[requestID]; superseded != nil {
+		superseded.by = status
+		close(superseded.superseded)
+	}
+	latest := &latestWebhook{superseded: make(chan struct{})}
+	e.webhookLatest[requestID] = latest
+	e.webhookMu.Unlock()
+
+	return func(ctx context.Context) {
+		defer func() {
+			close(done)
+			e.webhookMu.Lock()
+			if e.webhookTails[requestID] == done {
+				delete(e.webhookTails, requestID)
+			}
+			if e.webhookLatest[requestID] == latest {
+				delete(e.webhookLatest, requestID)
+			}
+			e.webhookMu.Unlock()
+		}()
+
+		if prev != nil {
+			<-prev
+		}
+		destinations, drop := e.debounceWebhook(status, latest)
+		if drop {
+			util.Debug("Dropped superseded webhook", "request_id", requestID, "status", status, "sequence", seq)
+			return
+		}
+		e.deliverWebhook(ctx, requestID, status, reason, suggestion, seq, destinations)
+	}
+}
+
+// intermediateWebhookStatus reports whether a request moves on from status
+// without anyone acting, so a later status makes its webhook stale. Only
+// these statuses are held back by the webhook debounce.
+func intermediateWebhookStatus(status string) bool {
+	return status == database.StatusApproved || status == database.StatusExecuting
+}
+
+// latestWebhook tracks a request's most recently scheduled webhook until a
+// newer one supersedes it.
+type latestWebhook struct {
+	superseded chan struct{} // closed when a newer webhook is scheduled
+	by         string        // the newer webhook's status, set before superseded is closed
+}
+
+// debounceWebhook holds an intermediate status for the configured debounce
+// window. If a newer webhook for the same request is scheduled meanwhile,
+// the event is only still due to destinations that want its status but not
+// the newer one, which they would otherwise never hear about: it returns
+// those destinations, and drop is true when there are none. Other statuses,
+// and every status when the debounce is off, return at once for every
+// destination.
+func (e *Engine) debounceWebhook(status string, latest *latestWebhook) (destinations []string, drop bool) {
+	window := time.Duration(e.config.Moltbot.Webhook.DebounceMs) * time.Millisecond
+	if window <= 0 || !intermediateWebhookStatus(status) {
+		return nil, false
+	}
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+	select {
+	case <-latest.superseded:
+	case <-timer.C:
+		return nil, false
+	}
+
+	for _, dest := range e.config.Moltbot.Webhook.AllDestinations() {
+		if dest.Wants(status) && !dest.Wants(latest.by) {
+			destinations = append(destinations, dest.Name)
+		}
 	}
+	return destinations, len(destinations) == 0
+}
 
+func (e *Engine) deliverWebhook(ctx context.Context, requestID, status, reason, suggestion string, seq int64, destinations []string) {
 	req, err := e.requestRepo.GetByID(ctx, requestID)
 	if err != nil || req == nil {
 		return
 	}
 
 	event := WebhookEvent{
-		RequestID:  requestID,
-		Status:     database.StatusChangeRequested,
-		Message:    buildSuggestionMessage(req, suggestion),
-		Suggestion: suggestion,
+		RequestID:    requestID,
+		Status:       status,
+		Reason:       reason,
+		Sequence:     seq,
+		Destinations: destinations,
+	}
+	if status == database.StatusChangeRequested {
+		event.Message = buildSuggestionMessage(req, suggestion)
+		event.Suggestion = suggestion
+	} else {
+		event.Message = buildWebhookMessage(req, status)
+		event.Result = req.Result
 	}
+	event.Diff = e.webhookDiff(ctx, req, status)
 
 	if err := e.webhookClient.Deliver(ctx, event); err != nil {
 		util.Error("Failed to deliver webhook", "error", err, "request_id", requestID)
@@ -524,19 +1272,79 @@ func (e *Engine) notifyWebhookWithSuggestion(ctx context.Context, requestID, sug
 	e.requestRepo.SetWebhookNotified(ctx, requestID)
 }
 
-func (e *Engine) shouldNotify(status string) bool {
-	if e.webhookClient == nil {
-		return false
+// includeDiff reports whether the request's webhooks carry an event diff.
+func (e *Engine) includeDiff(req *database.Request) bool {
+	return e.webhookClient != nil && e.config.Moltbot.Webhook.IncludeDiff &&
+		req.Operation == database.OperationUpdateEvent
+}
+
+// captureUpdateDiff records the changes an approved update is about to
+// make, so its webhooks can still report them once the event has changed.
+func (e *Engine) captureUpdateDiff(ctx context.Context, requestID string) {
+	if e.webhookClient == nil || !e.config.Moltbot.Webhook.IncludeDiff {
+		return
 	}
-	if len(e.config.Moltbot.Webhook.NotifyOn) == 0 {
-		return true
+	req, err := e.requestRepo.GetByID(ctx, requestID)
+	if err != nil || req == nil || !e.includeDiff(req) {
+		return
+	}
+	// The diff records the event as it is just before the write
+	diffs, err := e.UpdateDiff(google.BypassCache(ctx), req)
+	if err != nil {
+		util.Warn("Failed to compute update diff for webhook", "error", err, "request_id", requestID)
+		return
+	}
+	e.diffMu.Lock()
+	e.updateDiffs[requestID] = diffs
+	e.diffMu.Unlock()
+}
+
+// takeUpdateDiff removes and returns a captured diff.
+func (e *Engine) takeUpdateDiff(requestID string) ([]google.Diff, bool) {
+	e.diffMu.Lock()
+	defer e.diffMu.Unlock()
+	diffs, ok := e.updateDiffs[requestID]
+	delete(e.updateDiffs, requestID)
+	return diffs, ok
+}
+
+// webhookDiff returns the diff sent with an update request's webhook. A
+// diff captured before execution is preferred and is released once the
+// request completes or fails. Otherwise it is computed from the current
+// event, except for completed requests, whose event already holds the
+// new values.
+func (e *Engine) webhookDiff(ctx context.Context, req *database.Request, status string) []google.Diff {
+	if !e.includeDiff(req) {
+		return nil
 	}
-	for _, allowed := range e.config.Moltbot.Webhook.NotifyOn {
-		if allowed == status {
-			return true
+
+	if status == database.StatusCompleted || status == database.StatusFailed {
+		if diffs, ok := e.takeUpdateDiff(req.ID); ok || status == database.StatusCompleted {
+			return diffs
+		}
+	} else {
+		e.diffMu.Lock()
+		diffs, ok := e.updateDiffs[req.ID]
+		e.diffMu.Unlock()
+		if ok {
+			return diffs
 		}
 	}
-	return false
+
+	diffs, err := e.UpdateDiff(ctx, req)
+	if err != nil {
+		util.Warn("Failed to compute update diff for webhook", "error", err, "request_id", req.ID)
+		return nil
+	}
+	return diffs
+}
+
+func (e *Engine) shouldNotify(status string) bool {
+	if e.webhookClient == nil {
+		return false
+	}
+	// Each destination filters again on delivery
+	return e.config.Moltbot.Webhook.NotifiesAbout(status)
 }
 
 func getOperationSummary(operation string, details *notifications.EventDetails) string {
@@ -550,6 +1358,13 @@ func getOperationSummary(operation string, details *notifications.EventDetails)
 		return "Update Event"
 	case database.OperationDeleteEvent:
 		return "Delete Event"
+	case database.OperationMoveEvent:
+		return "Move Event"
+	case database.OperationCreateEventsBatch:
+		if details != nil {
+			return fmt.Sprintf("Create on %d calendars: %s", len(details.Calendars), details.Title)
+		}
+		return "Create Events"
 	default:
 		return operation
 	}
@@ -562,6 +1377,13 @@ func buildWebhookMessage(req *database.Request, status string) string {
 	case database.StatusDenied:
 		return "Your calendar request was denied."
 	case database.StatusCompleted:
+		if req.Operation == database.OperationCreateEventsBatch {
+			var result google.BatchResult
+			if err := json.Unmarshal(req.Result, &result); err == nil && result.Failed > 0 {
+				return fmt.Sprintf("Your calendar request completed with errors: %d of %d events were created.",
+					result.Succeeded, result.Succeeded+result.Failed)
+			}
+		}
 		return "Your calendar request was completed successfully."
 	case database.StatusFailed:
 		return fmt.Sprintf("Your calendar request failed: %s", req.Error.String)
@@ -572,6 +1394,17 @@ func buildWebhookMessage(req *database.Request, status string) string {
 	}
 }
 
+// buildDenialMessage tells the requester its request was denied, with the
+// approver's reason when one was given, and what to do next.
+func buildDenialMessage(reason string) string {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "Your calendar request was denied. Check the request and submit a new one if it is still needed."
+	}
+	return fmt.Sprintf("Your calendar request was denied: %s. Address the reason and submit a new request if it is still needed.",
+		strings.TrimSuffix(reason, "."))
+}
+
 func buildSuggestionMessage(req *database.Request, suggestion string) string {
 	return fmt.Sprintf(`Calendar request needs changes.
 
@@ -0,0 +1,915 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/google"
+	"github.com/dtorcivia/schedlock/internal/notifications"
+	"github.com/dtorcivia/schedlock/internal/requests"
+	"github.com/dtorcivia/schedlock/internal/tokens"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+func TestExponentialBackoffJitterBounds(t *testing.T) {
+	base := 2 * time.Second
+	max := 30 * time.Second
+
+	tests := []struct {
+		retryCount int
+		ceiling    time.Duration
+	}{
+		{retryCount: 0, ceiling: 4 * time.Second},
+		{retryCount: 1, ceiling: 8 * time.Second},
+		{retryCount: 2, ceiling: 16 * time.Second},
+		{retryCount: 3, ceiling: 30 * time.Second},
+		{retryCount: 10, ceiling: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 200; i++ {
+			d := exponentialBackoff(tt.retryCount, base, max)
+			if d < base || d > tt.ceiling {
+				t.Fatalf("retry %d: backoff %v outside [%v, %v]", tt.retryCount, d, base, tt.ceiling)
+			}
+		}
+	}
+}
+
+func TestExponentialBackoffDegenerateConfig(t *testing.T) {
+	if d := exponentialBackoff(3, 0, time.Minute); d != 0 {
+		t.Fatalf("expected zero backoff for zero base, got %v", d)
+	}
+	if d := exponentialBackoff(3, 5*time.Second, time.Second); d != 5*time.Second {
+		t.Fatalf("expected max below base to clamp to base, got %v", d)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{name: "seconds", value: "120", want: 2 * time.Minute, ok: true},
+		{name: "seconds with whitespace", value: " 7 ", want: 7 * time.Second, ok: true},
+		{name: "http date", value: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second, ok: true},
+		{name: "http date in past", value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, ok: true},
+		{name: "empty", value: "", ok: false},
+		{name: "negative", value: "-5", ok: false},
+		{name: "garbage", value: "soon", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Fatalf("duration = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetBackoffDurationHonorsRetryAfter(t *testing.T) {
+	cfg := &config.Config{Retry: config.RetryConfig{
+		Strategy:       config.RetryStrategyFixed,
+		BackoffSeconds: []int{5, 10, 20},
+	}}
+	e := &Engine{config: cfg}
+
+	header := http.Header{}
+	header.Set("Retry-After", "42")
+	apiErr := &googleapi.Error{Code: http.StatusTooManyRequests, Header: header}
+	wrapped := fmt.Errorf("failed to create event: %w", apiErr)
+
+	if d := e.getBackoffDuration(0, wrapped); d != 42*time.Second {
+		t.Fatalf("expected Retry-After of 42s, got %v", d)
+	}
+
+	// A Retry-After beyond the configured maximum is capped
+	cfg.Retry.MaxDelaySeconds = 30
+	if d := e.getBackoffDuration(0, wrapped); d != 30*time.Second {
+		t.Fatalf("expected Retry-After capped at 30s, got %v", d)
+	}
+	header.Set("Retry-After", "12")
+	if d := e.getBackoffDuration(0, wrapped); d != 12*time.Second {
+		t.Fatalf("expected Retry-After of 12s under the cap, got %v", d)
+	}
+
+	// Without Retry-After the fixed schedule applies, clamped to the last entry.
+	if d := e.getBackoffDuration(1, &googleapi.Error{Code: http.StatusServiceUnavailable}); d != 10*time.Second {
+		t.Fatalf("expected fixed backoff of 10s, got %v", d)
+	}
+	if d := e.getBackoffDuration(7, nil); d != 20*time.Second {
+		t.Fatalf("expected clamped fixed backoff of 20s, got %v", d)
+	}
+}
+
+func TestClaimResendCooldown(t *testing.T) {
+	e := &Engine{
+		config:     &config.Config{Approval: config.ApprovalConfig{ResendCooldownSeconds: 60}},
+		lastResend: make(map[string]time.Time),
+	}
+
+	if err := e.claimResend("req_1"); err != nil {
+		t.Fatalf("first resend should be allowed, got %v", err)
+	}
+
+	err := e.claimResend("req_1")
+	var cooldown *ResendCooldownError
+	if !errors.As(err, &cooldown) {
+		t.Fatalf("expected cooldown error, got %v", err)
+	}
+	if cooldown.RetryAfter <= 0 || cooldown.RetryAfter > time.Minute {
+		t.Fatalf("unexpected retry after %v", cooldown.RetryAfter)
+	}
+
+	// Other requests are limited independently
+	if err := e.claimResend("req_2"); err != nil {
+		t.Fatalf("resend for another request should be allowed, got %v", err)
+	}
+
+	// An expired entry no longer blocks
+	e.lastResend["req_1"] = time.Now().Add(-2 * time.Minute)
+	if err := e.claimResend("req_1"); err != nil {
+		t.Fatalf("resend after cooldown should be allowed, got %v", err)
+	}
+}
+
+func TestFindFreeSlots(t *testing.T) {
+	requested := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	windowStart := time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)
+	windowEnd := time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC)
+	busy := google.MergeBusyPeriods([]google.TimePeriod{
+		{Start: requested.Add(-30 * time.Minute), End: requested.Add(90 * time.Minute)}, // 09:30-11:30
+		{Start: requested.Add(2 * time.Hour), End: requested.Add(3 * time.Hour)},        // 12:00-13:00
+	})
+
+	slots := findFreeSlots(busy, requested, windowStart, windowEnd, time.Hour, 30*time.Minute, 3)
+
+	want := []time.Time{
+		requested.Add(-90 * time.Minute), // 08:30
+		requested.Add(-2 * time.Hour),    // 08:00
+		requested.Add(3 * time.Hour),     // 13:00
+	}
+	if len(slots) != len(want) {
+		t.Fatalf("expected %d slots, got %+v", len(want), slots)
+	}
+	for i, start := range want {
+		if !slots[i].Start.Equal(start) || slots[i].End.Sub(slots[i].Start) != time.Hour {
+			t.Errorf("slot %d = %v-%v, want start %v", i, slots[i].Start, slots[i].End, start)
+		}
+	}
+}
+
+func TestFindFreeSlotsSkipsRequestedTime(t *testing.T) {
+	requested := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	slots := findFreeSlots(nil, requested, requested.Add(-time.Hour), requested.Add(2*time.Hour), time.Hour, time.Hour, 5)
+
+	if len(slots) != 2 {
+		t.Fatalf("expected two slots around the requested time, got %+v", slots)
+	}
+	for _, slot := range slots {
+		if slot.Start.Equal(requested) {
+			t.Errorf("requested time should not be suggested back")
+		}
+	}
+}
+
+func TestBuildWebhookMessageBatchPartialFailure(t *testing.T) {
+	result, _ := json.Marshal(google.BatchResult{Succeeded: 2, Failed: 1})
+	req := &database.Request{
+		Operation: database.OperationCreateEventsBatch,
+		Result:    result,
+	}
+
+	msg := buildWebhookMessage(req, database.StatusCompleted)
+	if !strings.Contains(msg, "2 of 3 events") {
+		t.Fatalf("expected partial failure counts, got %q", msg)
+	}
+
+	result, _ = json.Marshal(google.BatchResult{Succeeded: 3})
+	req.Result = result
+	if msg := buildWebhookMessage(req, database.StatusCompleted); strings.Contains(msg, "errors") {
+		t.Fatalf("expected plain success message, got %q", msg)
+	}
+}
+
+func TestResultNotificationDenied(t *testing.T) {
+	req := &database.Request{ID: "req_1", Operation: database.OperationCreateEvent}
+
+	n := resultNotification(req, database.StatusDenied, "conflict.")
+	if n.Status != database.StatusDenied || !strings.Contains(n.Message, "denied: conflict. Address") {
+		t.Fatalf("expected the reason in the denial message, got %+v", n)
+	}
+	if n := resultNotification(req, database.StatusDenied, ""); strings.Contains(n.Message, ":") {
+		t.Fatalf("expected a denial message without a reason, got %q", n.Message)
+	}
+}
+
+// recordingWebhookClient records delivered events. The first delivery blocks
+// until release is closed, so later events are forced to wait their turn.
+type recordingWebhookClient struct {
+	mu      sync.Mutex
+	events  []WebhookEvent
+	release chan struct{}
+}
+
+func (c *recordingWebhookClient) Deliver(ctx context.Context, event WebhookEvent) error {
+	c.mu.Lock()
+	first := len(c.events) == 0
+	c.mu.Unlock()
+	if first && event.Status == database.StatusApproved {
+		<-c.release
+	}
+	c.mu.Lock()
+	c.events = append(c.events, event)
+	c.mu.Unlock()
+	return nil
+}
+
+func TestScheduleWebhookPreservesOrder(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_test', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, payload, expires_at)
+		VALUES ('req_test', 'key_test', 'create_event', '{}', ?)
+	`, util.SQLiteTimestamp(time.Now().Add(time.Hour))); err != nil {
+		t.Fatalf("insert request: %v", err)
+	}
+
+	client := &recordingWebhookClient{release: make(chan struct{})}
+	e := NewEngine(&config.Config{}, requests.NewRepository(db), nil, nil, nil)
+	e.SetWebhookClient(client)
+
+	approved := e.scheduleWebhook("req_test", database.StatusApproved, "", "")
+	completed := e.scheduleWebhook("req_test", database.StatusCompleted, "", "")
+	if approved == nil || completed == nil {
+		t.Fatal("expected webhooks to be scheduled")
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); completed(context.Background()) }()
+	go func() { defer wg.Done(); approved(context.Background()) }()
+
+	// Completed must not overtake approved while approved is stuck in delivery
+	time.Sleep(50 * time.Millisecond)
+	client.mu.Lock()
+	early := len(client.events)
+	client.mu.Unlock()
+	if early != 0 {
+		t.Fatalf("%d events delivered before the first one finished", early)
+	}
+
+	close(client.release)
+	wg.Wait()
+
+	if len(client.events) != 2 {
+		t.Fatalf("delivered %d events, want 2", len(client.events))
+	}
+	for i, want := range []string{database.StatusApproved, database.StatusCompleted} {
+		if got := client.events[i]; got.Status != want || got.Sequence != int64(i+1) {
+			t.Errorf("event %d = %s #%d, want %s #%d", i, got.Status, got.Sequence, want, i+1)
+		}
+	}
+	if len(e.webhookTails) != 0 {
+		t.Errorf("delivery queue not cleaned up: %v", e.webhookTails)
+	}
+}
+
+func TestWebhookDebounceDropsSupersededStatuses(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_test', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, payload, expires_at)
+		VALUES ('req_test', 'key_test', 'create_event', '{}', ?)
+	`, util.SQLiteTimestamp(time.Now().Add(time.Hour))); err != nil {
+		t.Fatalf("insert request: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Moltbot.Webhook.DebounceMs = 200
+	client := &recordingWebhookClient{release: make(chan struct{})}
+	close(client.release)
+	e := NewEngine(cfg, requests.NewRepository(db), nil, nil, nil)
+	e.SetWebhookClient(client)
+
+	// approved is superseded by completed inside the window
+	approved := e.scheduleWebhook("req_test", database.StatusApproved, "", "")
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() { defer wg.Done(); approved(context.Background()) }()
+	time.Sleep(20 * time.Millisecond)
+	start := time.Now()
+	e.scheduleWebhook("req_test", database.StatusCompleted, "", "")(context.Background())
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("completed waited %v for the dropped event's window", elapsed)
+	}
+
+	if len(client.events) != 1 {
+		t.Fatalf("delivered %d events, want only completed", len(client.events))
+	}
+	if got := client.events[0]; got.Status != database.StatusCompleted || got.Sequence != 2 {
+		t.Errorf("event = %s #%d, want %s #2", got.Status, got.Sequence, database.StatusCompleted)
+	}
+
+	// An intermediate status nothing follows is sent once the window ends
+	start = time.Now()
+	e.scheduleWebhook("req_test", database.StatusApproved, "", "")(context.Background())
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("approved was sent after %v, before the window ended", elapsed)
+	}
+	if got := client.events[len(client.events)-1]; got.Status != database.StatusApproved {
+		t.Errorf("last event = %s, want approved", got.Status)
+	}
+
+	// Final statuses are never held
+	start = time.Now()
+	e.scheduleWebhook("req_test", database.StatusFailed, "", "")(context.Background())
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("failed was held for %v", elapsed)
+	}
+	if len(e.webhookTails) != 0 || len(e.webhookLatest) != 0 {
+		t.Errorf("delivery state not cleaned up: %v %v", e.webhookTails, e.webhookLatest)
+	}
+}
+
+func TestWebhookDebounceKeepsEventsOtherDestinationsNeed(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_test', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, payload, expires_at)
+		VALUES ('req_test', 'key_test', 'create_event', '{}', ?)
+	`, util.SQLiteTimestamp(time.Now().Add(time.Hour))); err != nil {
+		t.Fatalf("insert request: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Moltbot.Webhook.DebounceMs = 200
+	cfg.Moltbot.Webhook.Destinations = []config.WebhookDestination{
+		{Name: "approvals", URL: "https://a.example.com", NotifyOn: []string{database.StatusApproved}},
+		{Name: "results", URL: "https://b.example.com", NotifyOn: []string{database.StatusCompleted}},
+		{Name: "both", URL: "https://c.example.com", NotifyOn: []string{database.StatusApproved, database.StatusCompleted}},
+	}
+	client := &recordingWebhookClient{release: make(chan struct{})}
+	close(client.release)
+	e := NewEngine(cfg, requests.NewRepository(db), nil, nil, nil)
+	e.SetWebhookClient(client)
+
+	approved := e.scheduleWebhook("req_test", database.StatusApproved, "", "")
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() { defer wg.Done(); approved(context.Background()) }()
+	time.Sleep(20 * time.Millisecond)
+	e.scheduleWebhook("req_test", database.StatusCompleted, "", "")(context.Background())
+	wg.Wait()
+
+	if len(client.events) != 2 {
+		t.Fatalf("delivered %d events, want approved and completed", len(client.events))
+	}
+	// Only the destination that never hears about completed still gets approved
+	if got := client.events[0]; got.Status != database.StatusApproved ||
+		len(got.Destinations) != 1 || got.Destinations[0] != "approvals" {
+		t.Errorf("event 0 = %s to %v, want approved to [approvals]", got.Status, got.Destinations)
+	}
+	if got := client.events[1]; got.Status != database.StatusCompleted || got.Destinations != nil {
+		t.Errorf("event 1 = %s to %v, want completed to every destination", got.Status, got.Destinations)
+	}
+}
+
+func TestWebhookIncludesCapturedUpdateDiff(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_test', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, payload, expires_at)
+		VALUES ('req_test', 'key_test', 'update_event', '{"calendarId":"primary","eventId":"evt1"}', ?)
+	`, util.SQLiteTimestamp(time.Now().Add(time.Hour))); err != nil {
+		t.Fatalf("insert request: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Moltbot.Webhook.IncludeDiff = true
+	client := &recordingWebhookClient{release: make(chan struct{})}
+	close(client.release)
+	e := NewEngine(cfg, requests.NewRepository(db), nil, nil, nil)
+	e.SetWebhookClient(client)
+
+	diff := []google.Diff{{Field: "Summary", OldValue: "Standup", NewValue: "Retro"}}
+	e.updateDiffs["req_test"] = diff
+
+	for _, status := range []string{database.StatusApproved, database.StatusCompleted} {
+		send := e.scheduleWebhook("req_test", status, "", "")
+		if send == nil {
+			t.Fatalf("expected %s webhook to be scheduled", status)
+		}
+		send(context.Background())
+	}
+
+	if len(client.events) != 2 {
+		t.Fatalf("delivered %d events, want 2", len(client.events))
+	}
+	for _, event := range client.events {
+		if len(event.Diff) != 1 || event.Diff[0] != diff[0] {
+			t.Errorf("%s event diff = %v, want %v", event.Status, event.Diff, diff)
+		}
+	}
+	if len(e.updateDiffs) != 0 {
+		t.Errorf("captured diff not released after completion: %v", e.updateDiffs)
+	}
+
+	// Completed without a captured diff sends none rather than an empty
+	// comparison against the already updated event
+	e.notifyWebhook(context.Background(), "req_test", database.StatusCompleted)
+	if got := client.events[len(client.events)-1]; got.Diff != nil {
+		t.Errorf("expected no diff without a capture, got %v", got.Diff)
+	}
+
+	cfg.Moltbot.Webhook.IncludeDiff = false
+	e.updateDiffs["req_test"] = diff
+	e.notifyWebhook(context.Background(), "req_test", database.StatusApproved)
+	if got := client.events[len(client.events)-1]; got.Diff != nil {
+		t.Errorf("expected no diff when disabled, got %v", got.Diff)
+	}
+}
+
+func TestExecutionQueuePosition(t *testing.T) {
+	q := NewExecutionQueue(1, nil)
+	for _, id := range []string{"req_a", "req_b", "req_c"} {
+		q.Enqueue(id)
+	}
+
+	if ahead, ok := q.Position("req_c"); !ok || ahead != 2 {
+		t.Fatalf("Position(req_c) = %d, %v; want 2, true", ahead, ok)
+	}
+
+	// A worker takes req_a: it still runs ahead of the others
+	q.dequeued(<-q.ch)
+	if _, ok := q.Position("req_a"); ok {
+		t.Error("running request reported as waiting")
+	}
+	if ahead, ok := q.Position("req_c"); !ok || ahead != 2 {
+		t.Errorf("Position(req_c) while req_a runs = %d, %v; want 2, true", ahead, ok)
+	}
+
+	// req_a finishes
+	q.running--
+	if ahead, ok := q.Position("req_b"); !ok || ahead != 0 {
+		t.Errorf("Position(req_b) = %d, %v; want 0, true", ahead, ok)
+	}
+	if _, ok := q.Position("req_unknown"); ok {
+		t.Error("unknown request reported as waiting")
+	}
+}
+
+func TestSubmitRequestPendingLimit(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_test', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	expiresAt := util.SQLiteTimestamp(time.Now().Add(time.Hour))
+	for _, row := range []struct{ id, status string }{
+		{"req_pending1", database.StatusPendingApproval},
+		{"req_pending2", database.StatusPendingApproval},
+		{"req_done", database.StatusCompleted},
+	} {
+		if _, err := db.Exec(`
+			INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status)
+			VALUES (?, 'key_test', 'create_event', '{}', ?, ?)
+		`, row.id, expiresAt, row.status); err != nil {
+			t.Fatalf("insert request: %v", err)
+		}
+	}
+
+	e := NewEngine(&config.Config{}, requests.NewRepository(db), nil, nil, nil)
+	authKey := &apikeys.AuthenticatedKey{
+		ID:          "key_test",
+		Tier:        database.TierWrite,
+		Constraints: &database.KeyConstraints{MaxPendingRequests: 2},
+	}
+
+	_, err = e.SubmitRequest(context.Background(), authKey, database.OperationCreateEvent, json.RawMessage(`{}`), "", nil, "", database.PriorityNormal, 0, apikeys.ConstraintDecision{Result: apikeys.ConstraintRequireApproval}, "")
+	var limitErr *PendingLimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("expected PendingLimitError, got %v", err)
+	}
+	if limitErr.Limit != 2 || limitErr.Pending != 2 {
+		t.Errorf("limit error = %+v, want 2 of 2", limitErr)
+	}
+}
+
+func TestSubmitRequestPendingLimitConcurrent(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_test', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+
+	e := NewEngine(&config.Config{}, requests.NewRepository(db), nil, NewAuditLogger(db), nil)
+	authKey := &apikeys.AuthenticatedKey{
+		ID:          "key_test",
+		Tier:        database.TierWrite,
+		Constraints: &database.KeyConstraints{MaxPendingRequests: 2},
+	}
+
+	// Submissions racing each other must not all pass the count before any insert
+	const submits = 8
+	errs := make([]error, submits)
+	var wg sync.WaitGroup
+	for i := 0; i < submits; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			payload := json.RawMessage(fmt.Sprintf(`{"n":%d}`, i))
+			_, errs[i] = e.SubmitRequest(context.Background(), authKey, database.OperationCreateEvent, payload, "", nil, "", database.PriorityNormal, 0, apikeys.ConstraintDecision{Result: apikeys.ConstraintRequireApproval}, "")
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		var limitErr *PendingLimitError
+		switch {
+		case err == nil:
+			created++
+		case !errors.As(err, &limitErr):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	var pending int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM requests WHERE status = ?`, database.StatusPendingApproval).Scan(&pending); err != nil {
+		t.Fatalf("count requests: %v", err)
+	}
+	if created != 2 || pending != 2 {
+		t.Errorf("created %d requests (%d pending), want 2", created, pending)
+	}
+}
+
+func TestSubmitRequestCustomTimeout(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_test', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Approval.TimeoutMinutes = 60
+	tokenRepo := tokens.NewRepository(db)
+	e := NewEngine(cfg, requests.NewRepository(db), nil, NewAuditLogger(db), tokenRepo)
+	authKey := &apikeys.AuthenticatedKey{ID: "key_test", Tier: database.TierWrite}
+	decision := apikeys.ConstraintDecision{Result: apikeys.ConstraintRequireApproval}
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{10 * time.Minute, 10 * time.Minute},
+		{0, 60 * time.Minute},
+	} {
+		req, err := e.SubmitRequest(ctx, authKey, database.OperationCreateEvent, json.RawMessage(`{}`), "", nil, "", database.PriorityNormal, tt.timeout, decision, "")
+		if err != nil {
+			t.Fatalf("SubmitRequest: %v", err)
+		}
+		// Timestamps are stored to the second
+		if got := time.Until(req.ExpiresAt); got > tt.want || got < tt.want-5*time.Second {
+			t.Errorf("timeout %v: request expires in %v, want %v", tt.timeout, got, tt.want)
+		}
+
+		// Decision tokens expire with the request
+		e.buildApprovalNotification(ctx, req)
+		issued, err := tokenRepo.GetByRequestID(ctx, req.ID)
+		if err != nil || len(issued) != 1 {
+			t.Fatalf("tokens = %v, %v; want one", issued, err)
+		}
+		if !issued[0].ExpiresAt.Equal(req.ExpiresAt) {
+			t.Errorf("token expires at %v, request at %v", issued[0].ExpiresAt, req.ExpiresAt)
+		}
+	}
+}
+
+func TestRetryFailedRequest(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_test', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	expiresAt := util.SQLiteTimestamp(time.Now().Add(time.Hour))
+	for _, row := range []struct{ id, status string }{
+		{"req_failed", database.StatusFailed},
+		{"req_done", database.StatusCompleted},
+	} {
+		if _, err := db.Exec(`
+			INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status, error, retry_count)
+			VALUES (?, 'key_test', 'create_event', '{}', ?, ?, 'googleapi: Error 503', 3)
+		`, row.id, expiresAt, row.status); err != nil {
+			t.Fatalf("insert request: %v", err)
+		}
+	}
+
+	repo := requests.NewRepository(db)
+	e := NewEngine(&config.Config{}, repo, nil, NewAuditLogger(db), nil)
+	ctx := context.Background()
+
+	if err := e.RetryFailedRequest(ctx, "req_failed", "api:key_admin"); err != nil {
+		t.Fatalf("RetryFailedRequest: %v", err)
+	}
+	req, _ := repo.GetByID(ctx, "req_failed")
+	if req.Status != database.StatusApproved || req.Error.Valid {
+		t.Errorf("status = %s, error = %v; want approved with the error cleared", req.Status, req.Error)
+	}
+	if req.RetryCount != 0 || req.ManualRetryCount != 1 {
+		t.Errorf("retry_count = %d, manual_retry_count = %d; want 0 and 1", req.RetryCount, req.ManualRetryCount)
+	}
+	select {
+	case id := <-e.executionQueue.ch:
+		if id != "req_failed" {
+			t.Errorf("enqueued %s, want req_failed", id)
+		}
+	default:
+		t.Error("expected the request to be queued for execution")
+	}
+
+	if err := e.RetryFailedRequest(ctx, "req_failed", "api:key_admin"); !errors.Is(err, ErrRequestNotFailed) {
+		t.Errorf("second retry: got %v, want ErrRequestNotFailed", err)
+	}
+	if err := e.RetryFailedRequest(ctx, "req_done", "api:key_admin"); !errors.Is(err, ErrRequestNotFailed) {
+		t.Errorf("completed request: got %v, want ErrRequestNotFailed", err)
+	}
+	if err := e.RetryFailedRequest(ctx, "req_missing", "api:key_admin"); !errors.Is(err, ErrRequestNotFound) {
+		t.Errorf("missing request: got %v, want ErrRequestNotFound", err)
+	}
+}
+
+// deliveredNotifier behaves like a notification manager whose providers have
+// all delivered the request already.
+type deliveredNotifier struct {
+	sends int
+}
+
+func (n *deliveredNotifier) SendApprovalRequest(ctx context.Context, req *notifications.ApprovalNotification) error {
+	n.sends++
+	if !req.Force {
+		return notifications.ErrAlreadyDelivered
+	}
+	return nil
+}
+
+// checkingNotifier also implements DeliveryChecker.
+type checkingNotifier struct{ *deliveredNotifier }
+
+func (n *checkingNotifier) AlreadyDelivered(ctx context.Context, req *notifications.ApprovalNotification) bool {
+	return true
+}
+
+func TestResendAlreadyDeliveredKeepsCooldown(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_test', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status)
+		VALUES ('req_1', 'key_test', 'create_event', '{}', ?, ?)
+	`, util.SQLiteTimestamp(time.Now().Add(time.Hour)), database.StatusPendingApproval); err != nil {
+		t.Fatalf("insert request: %v", err)
+	}
+
+	ctx := context.Background()
+	cfg := &config.Config{Approval: config.ApprovalConfig{ResendCooldownSeconds: 60}}
+	countTokens := func() int {
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM decision_tokens WHERE request_id = 'req_1'`).Scan(&n); err != nil {
+			t.Fatalf("count tokens: %v", err)
+		}
+		return n
+	}
+
+	for _, tc := range []struct {
+		name      string
+		checker   bool
+		wantSends int
+	}{
+		// The checker answers before a token is issued or the cooldown is claimed
+		{"checker", true, 1},
+		// Without a checker the claim is released after the send reports it
+		{"no checker", false, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			delivered := &deliveredNotifier{}
+			var notifier NotificationManager = delivered
+			if tc.checker {
+				notifier = &checkingNotifier{delivered}
+			}
+			e := NewEngine(cfg, requests.NewRepository(db), nil, NewAuditLogger(db), tokens.NewRepository(db))
+			e.SetNotifier(notifier)
+			tokensBefore := countTokens()
+
+			if err := e.ResendApprovalNotification(ctx, "req_1", "admin", false); !errors.Is(err, notifications.ErrAlreadyDelivered) {
+				t.Fatalf("resend: got %v, want ErrAlreadyDelivered", err)
+			}
+			if _, claimed := e.lastResend["req_1"]; claimed {
+				t.Error("an already-delivered resend should not start the cooldown")
+			}
+			if tc.checker && countTokens() != tokensBefore {
+				t.Error("an already-delivered resend should not issue a decision token")
+			}
+
+			// Resend to all goes through straight away
+			if err := e.ResendApprovalNotification(ctx, "req_1", "admin", true); err != nil {
+				t.Fatalf("forced resend: %v", err)
+			}
+			if delivered.sends != tc.wantSends {
+				t.Errorf("sends = %d, want %d", delivered.sends, tc.wantSends)
+			}
+		})
+	}
+}
+
+type fakeKeyLookup map[string]*database.APIKey
+
+func (f fakeKeyLookup) GetByID(ctx context.Context, id string) (*database.APIKey, error) {
+	if key, ok := f[id]; ok {
+		return key, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestResolveEventDefaults(t *testing.T) {
+	global := config.EventDefaultsConfig{
+		DescriptionSuffix: "Booked via SchedLock",
+		Location:          "HQ",
+		ColorID:           "2",
+		Reminders:         []string{"popup:10", "email:60"},
+	}
+	globalReminders := []google.Reminder{{Method: "popup", Minutes: 10}, {Method: "email", Minutes: 60}}
+
+	tests := []struct {
+		name   string
+		global config.EventDefaultsConfig
+		key    *database.EventDefaults
+		want   google.EventDefaults
+	}{
+		{
+			name: "nothing configured",
+			want: google.EventDefaults{},
+		},
+		{
+			name:   "global only",
+			global: global,
+			want:   google.EventDefaults{DescriptionSuffix: "Booked via SchedLock", Location: "HQ", ColorID: "2", Reminders: globalReminders},
+		},
+		{
+			name: "key only",
+			key:  &database.EventDefaults{Location: "Room 4", Reminders: []database.DefaultReminder{{Method: "popup", Minutes: 5}}},
+			want: google.EventDefaults{Location: "Room 4", Reminders: []google.Reminder{{Method: "popup", Minutes: 5}}},
+		},
+		{
+			name:   "empty key constraint keeps global",
+			global: global,
+			key:    &database.EventDefaults{},
+			want:   google.EventDefaults{DescriptionSuffix: "Booked via SchedLock", Location: "HQ", ColorID: "2", Reminders: globalReminders},
+		},
+		{
+			name:   "key overrides only the fields it sets",
+			global: global,
+			key:    &database.EventDefaults{ColorID: "9", DescriptionSuffix: "Team footer"},
+			want:   google.EventDefaults{DescriptionSuffix: "Team footer", Location: "HQ", ColorID: "9", Reminders: globalReminders},
+		},
+		{
+			name:   "key reminders replace the global list",
+			global: global,
+			key:    &database.EventDefaults{Reminders: []database.DefaultReminder{{Method: "email", Minutes: 1440}}},
+			want:   google.EventDefaults{DescriptionSuffix: "Booked via SchedLock", Location: "HQ", ColorID: "2", Reminders: []google.Reminder{{Method: "email", Minutes: 1440}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveEventDefaults(tt.global, tt.key)
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(tt.want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("resolveEventDefaults() = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestEngineEventDefaultsUsesKeyConstraint(t *testing.T) {
+	cfg := &config.Config{EventDefaults: config.EventDefaultsConfig{Location: "HQ", ColorID: "2"}}
+	e := NewEngine(cfg, nil, nil, nil, nil)
+	e.SetKeyLookup(fakeKeyLookup{
+		"key_room": {ID: "key_room", Constraints: &database.KeyConstraints{EventDefaults: &database.EventDefaults{Location: "Room 4"}}},
+		"key_none": {ID: "key_none"},
+	})
+
+	ctx := context.Background()
+	if got := e.eventDefaults(ctx, "key_room"); got.Location != "Room 4" || got.ColorID != "2" {
+		t.Errorf("key defaults = %+v, want location Room 4 and global color 2", got)
+	}
+	if got := e.eventDefaults(ctx, "key_none"); got.Location != "HQ" {
+		t.Errorf("unconstrained key location = %q, want HQ", got.Location)
+	}
+	// A lookup failure falls back to the global defaults
+	if got := e.eventDefaults(ctx, "key_missing"); got.Location != "HQ" {
+		t.Errorf("missing key location = %q, want HQ", got.Location)
+	}
+}
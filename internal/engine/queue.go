@@ -17,6 +17,13 @@ type ExecutionQueue struct {
 	wg       sync.WaitGroup
 	stopCh   chan struct{}
 	stopOnce sync.Once
+
+	// waiting mirrors the channel's contents in order, so positions can be
+	// reported; running counts requests being executed.
+	enqueueMu sync.Mutex // keeps waiting in channel order
+	mu        sync.Mutex
+	waiting   []string
+	running   int
 }
 
 // NewExecutionQueue creates a new execution queue.
@@ -54,6 +61,13 @@ func (q *ExecutionQueue) Stop() {
 
 // Enqueue adds a request ID to the execution queue.
 func (q *ExecutionQueue) Enqueue(requestID string) {
+	q.enqueueMu.Lock()
+	defer q.enqueueMu.Unlock()
+
+	q.mu.Lock()
+	q.waiting = append(q.waiting, requestID)
+	q.mu.Unlock()
+
 	select {
 	case q.ch <- requestID:
 		util.Debug("Request enqueued", "request_id", requestID)
@@ -80,7 +94,11 @@ func (q *ExecutionQueue) worker(ctx context.Context, id int) {
 			util.Debug("Worker stopping due to stop signal", "worker_id", id)
 			return
 		case requestID := <-q.ch:
+			q.dequeued(requestID)
 			q.processRequest(ctx, requestID)
+			q.mu.Lock()
+			q.running--
+			q.mu.Unlock()
 		}
 	}
 }
@@ -98,6 +116,33 @@ func (q *ExecutionQueue) processRequest(ctx context.Context, requestID string) {
 	}
 }
 
+// dequeued moves a request taken off the channel from waiting to running.
+func (q *ExecutionQueue) dequeued(requestID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, id := range q.waiting {
+		if id == requestID {
+			q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+			break
+		}
+	}
+	q.running++
+}
+
+// Position reports how many requests will execute before a waiting request,
+// counting those being executed now. ok is false if the request is not
+// waiting in the queue.
+func (q *ExecutionQueue) Position(requestID string) (ahead int, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, id := range q.waiting {
+		if id == requestID {
+			return q.running + i, true
+		}
+	}
+	return 0, false
+}
+
 // Len returns the current queue length.
 func (q *ExecutionQueue) Len() int {
 	return len(q.ch)
@@ -0,0 +1,186 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/google"
+)
+
+// maxSuggestedSlots caps how many free slots SuggestFreeSlots returns.
+const maxSuggestedSlots = 5
+
+// Errors returned by SuggestFreeSlots and UpdateDiff.
+var (
+	ErrCalendarNotConnected = errors.New("google calendar is not connected")
+	ErrNoRequestedTime      = errors.New("request does not propose an event time")
+	ErrEventNotFound        = errors.New("event not found")
+)
+
+// FreeSlot is a candidate open time for a request.
+type FreeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// SuggestFreeSlots looks for open slots around the time a pending request
+// proposes, closest first. A zero duration keeps the requested duration.
+func (e *Engine) SuggestFreeSlots(ctx context.Context, requestID string, duration time.Duration) ([]FreeSlot, error) {
+	req, err := e.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, ErrRequestNotFound
+	}
+
+	calendarID, start, end, err := requestedTime(req)
+	if err != nil {
+		return nil, err
+	}
+	if duration <= 0 {
+		duration = end.Sub(start)
+	}
+	if duration <= 0 {
+		return nil, ErrNoRequestedTime
+	}
+
+	if e.calendarClient == nil {
+		return nil, ErrCalendarNotConnected
+	}
+
+	window := time.Duration(e.config.Approval.SuggestWindowHours) * time.Hour
+	if window <= 0 {
+		window = config.DefaultApprovalSuggestWindowHours * time.Hour
+	}
+	step := time.Duration(e.config.Approval.SuggestSlotMinutes) * time.Minute
+	if step <= 0 {
+		step = config.DefaultApprovalSuggestSlotMinutes * time.Minute
+	}
+
+	// Never suggest times that have already passed
+	windowStart := start.Add(-window)
+	if now := time.Now(); windowStart.Before(now) {
+		windowStart = now
+	}
+	windowEnd := start.Add(window).Add(duration)
+	if !windowEnd.After(windowStart) {
+		return nil, nil
+	}
+
+	result, err := e.calendarClient.FreeBusy(ctx, &google.FreeBusyRequest{
+		TimeMin: windowStart,
+		TimeMax: windowEnd,
+		Items:   []google.FreeBusyCalendar{{ID: calendarID}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query free/busy: %w", err)
+	}
+
+	return findFreeSlots(result.BusyPeriods(), start, windowStart, windowEnd, duration, step, maxSuggestedSlots), nil
+}
+
+// requestedTime extracts the calendar and proposed time from a create or update request.
+func requestedTime(req *database.Request) (string, time.Time, time.Time, error) {
+	switch req.Operation {
+	case database.OperationCreateEvent:
+		var intent google.EventIntent
+		if err := json.Unmarshal(req.Payload, &intent); err != nil {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("invalid payload: %w", err)
+		}
+		return intent.CalendarID, intent.Start, intent.End, nil
+
+	case database.OperationUpdateEvent:
+		var intent google.EventUpdateIntent
+		if err := json.Unmarshal(req.Payload, &intent); err != nil {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("invalid payload: %w", err)
+		}
+		if intent.Start == nil || intent.End == nil {
+			return "", time.Time{}, time.Time{}, ErrNoRequestedTime
+		}
+		return intent.CalendarID, *intent.Start, *intent.End, nil
+	}
+
+	return "", time.Time{}, time.Time{}, ErrNoRequestedTime
+}
+
+// findFreeSlots returns up to limit slots of the given duration that avoid
+// busy periods, aligned to step and ordered by distance from requested.
+// busy must be sorted and merged.
+func findFreeSlots(busy []google.TimePeriod, requested, windowStart, windowEnd time.Time, duration, step time.Duration, limit int) []FreeSlot {
+	// Align candidates to the step relative to the requested time so the
+	// requested time itself and its neighbours are always on the grid.
+	offset := requested.Sub(windowStart) % step
+	if offset < 0 {
+		offset += step
+	}
+	first := windowStart.Add(offset)
+
+	var slots []FreeSlot
+	for candidate := first; !candidate.Add(duration).After(windowEnd); candidate = candidate.Add(step) {
+		if candidate.Equal(requested) {
+			continue
+		}
+		slotEnd := candidate.Add(duration)
+		if overlapsBusy(busy, candidate, slotEnd) {
+			continue
+		}
+		slots = append(slots, FreeSlot{Start: candidate, End: slotEnd})
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		return absDuration(slots[i].Start.Sub(requested)) < absDuration(slots[j].Start.Sub(requested))
+	})
+	if len(slots) > limit {
+		slots = slots[:limit]
+	}
+	return slots
+}
+
+func overlapsBusy(busy []google.TimePeriod, start, end time.Time) bool {
+	for _, period := range busy {
+		if period.Start.Before(end) && period.End.After(start) {
+			return true
+		}
+	}
+	return false
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// UpdateDiff compares an update_event request with the event as it is now,
+// so approvers can see what would change.
+func (e *Engine) UpdateDiff(ctx context.Context, req *database.Request) ([]google.Diff, error) {
+	if req.Operation != database.OperationUpdateEvent {
+		return nil, fmt.Errorf("request is not an update: %s", req.Operation)
+	}
+
+	var intent google.EventUpdateIntent
+	if err := json.Unmarshal(req.Payload, &intent); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	if e.calendarClient == nil {
+		return nil, ErrCalendarNotConnected
+	}
+
+	existing, err := e.calendarClient.GetEvent(ctx, intent.CalendarID, intent.TargetEventID())
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrEventNotFound
+	}
+	return google.GenerateDiff(existing, &intent), nil
+}
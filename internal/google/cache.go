@@ -0,0 +1,170 @@
+package google
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CacheStats reports how well the event cache is doing.
+type CacheStats struct {
+	Entries int    `json:"entries"`
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+}
+
+// eventCache is a size-capped cache of event reads with a short TTL. Values
+// are stored as JSON, so callers never share an event with the cache.
+type eventCache struct {
+	ttl  time.Duration
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front is most recently used
+	hits    uint64
+	misses  uint64
+}
+
+type eventCacheEntry struct {
+	key        string
+	calendarID string
+	data       []byte
+	expires    time.Time
+}
+
+func newEventCache(ttl time.Duration, size int) *eventCache {
+	return &eventCache{
+		ttl:     ttl,
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get decodes the cached value for key into v, reporting whether it was
+// found and fresh.
+func (c *eventCache) get(key string, v interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if ok && time.Now().After(el.Value.(*eventCacheEntry).expires) {
+		c.remove(el)
+		ok = false
+	}
+	if !ok {
+		c.misses++
+		return false
+	}
+	if err := json.Unmarshal(el.Value.(*eventCacheEntry).data, v); err != nil {
+		c.remove(el)
+		c.misses++
+		return false
+	}
+	c.order.MoveToFront(el)
+	c.hits++
+	return true
+}
+
+// put stores v for key, evicting the least recently used entry when full.
+func (c *eventCache) put(calendarID, key string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	c.entries[key] = c.order.PushFront(&eventCacheEntry{
+		key:        key,
+		calendarID: calendarID,
+		data:       data,
+		expires:    time.Now().Add(c.ttl),
+	})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// invalidate drops the cached reads of the given calendars. The primary
+// calendar can also be addressed by its owner's email, so a write to
+// "primary" drops everything and a write to any other calendar also drops
+// reads made as "primary".
+func (c *eventCache) invalidate(calendarIDs ...string) {
+	drop := map[string]bool{DefaultCalendarID: true}
+	for _, id := range calendarIDs {
+		if id == "" || id == DefaultCalendarID {
+			c.clear()
+			return
+		}
+		drop[id] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for el := c.order.Front(); el != nil; {
+		next := el.Next()
+		if drop[el.Value.(*eventCacheEntry).calendarID] {
+			c.remove(el)
+		}
+		el = next
+	}
+}
+
+func (c *eventCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// remove deletes an entry. The caller holds mu.
+func (c *eventCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*eventCacheEntry).key)
+}
+
+func (c *eventCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Entries: c.order.Len(), Hits: c.hits, Misses: c.misses}
+}
+
+func getEventCacheKey(calendarID, eventID string) string {
+	return fmt.Sprintf("get\x00%s\x00%s", calendarID, eventID)
+}
+
+func listEventsCacheKey(calendarID string, opts EventListOptions) string {
+	return fmt.Sprintf("list\x00%s\x00%s\x00%s\x00%d\x00%s\x00%s\x00%t\x00%s",
+		calendarID,
+		opts.TimeMin.UTC().Format(time.RFC3339Nano),
+		opts.TimeMax.UTC().Format(time.RFC3339Nano),
+		opts.MaxResults,
+		opts.PageToken,
+		opts.Query,
+		opts.SingleEvents,
+		opts.OrderBy,
+	)
+}
+
+type bypassCacheKey struct{}
+
+// BypassCache returns a context whose event reads go to Google even when
+// the event cache is enabled. Use it for reads that decide or record a
+// write, where a stale event would give the wrong answer.
+func BypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
@@ -0,0 +1,143 @@
+package google
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEventCacheServesCopies(t *testing.T) {
+	c := NewCalendarClient(nil)
+	c.SetEventCache(time.Minute, 10)
+	ctx := context.Background()
+
+	c.cache.put("team", getEventCacheKey("team", "evt1"), &Event{
+		ID:        "evt1",
+		Summary:   "Sync",
+		Attendees: []Attendee{{Email: "ana@example.com"}},
+	})
+
+	// A hit never reaches Google, which this client has no access to
+	first, err := c.GetEvent(ctx, "team", "evt1")
+	if err != nil {
+		t.Fatalf("GetEvent: %v", err)
+	}
+	first.Summary = "Changed"
+	first.Attendees[0].Email = "changed@example.com"
+
+	second, err := c.GetEvent(ctx, "team", "evt1")
+	if err != nil {
+		t.Fatalf("GetEvent: %v", err)
+	}
+	if second.Summary != "Sync" || second.Attendees[0].Email != "ana@example.com" {
+		t.Errorf("cached event was changed by a caller: %+v", second)
+	}
+
+	opts := EventListOptions{CalendarID: "team", TimeMin: time.Date(2026, 1, 28, 0, 0, 0, 0, time.UTC), MaxResults: 50}
+	c.cache.put("team", listEventsCacheKey("team", opts), &EventListResponse{Events: []Event{{ID: "evt1"}}, NextPageToken: "next"})
+	list, err := c.ListEvents(ctx, opts)
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(list.Events) != 1 || list.NextPageToken != "next" {
+		t.Errorf("cached list = %+v", list)
+	}
+
+	if stats, ok := c.EventCacheStats(); !ok || stats.Hits != 3 || stats.Entries != 2 {
+		t.Errorf("stats = %+v, %v; want 3 hits over 2 entries", stats, ok)
+	}
+}
+
+func TestEventCacheKeysIncludeParameters(t *testing.T) {
+	base := EventListOptions{TimeMin: time.Date(2026, 1, 28, 0, 0, 0, 0, time.UTC), MaxResults: 50}
+	variants := []EventListOptions{base, base, base, base, base}
+	variants[1].PageToken = "next"
+	variants[2].Query = "sync"
+	variants[3].SingleEvents = true
+	variants[4].MaxResults = 10
+
+	seen := make(map[string]bool)
+	for _, opts := range variants {
+		seen[listEventsCacheKey("primary", opts)] = true
+	}
+	if len(seen) != len(variants) {
+		t.Errorf("expected a distinct key per parameter set, got %d of %d", len(seen), len(variants))
+	}
+	if listEventsCacheKey("a", base) == listEventsCacheKey("b", base) {
+		t.Error("calendars share a key")
+	}
+}
+
+func TestEventCacheExpiryAndEviction(t *testing.T) {
+	c := newEventCache(20*time.Millisecond, 2)
+	var e Event
+
+	c.put("cal", "a", &Event{ID: "a"})
+	c.put("cal", "b", &Event{ID: "b"})
+	if !c.get("a", &e) {
+		t.Fatal("expected a to be cached")
+	}
+	// b is now the least recently used
+	c.put("cal", "c", &Event{ID: "c"})
+	if c.get("b", &e) {
+		t.Error("expected b to be evicted")
+	}
+	if !c.get("a", &e) || !c.get("c", &e) {
+		t.Error("expected a and c to remain")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if c.get("a", &e) {
+		t.Error("expected a to expire")
+	}
+	if stats := c.stats(); stats.Entries != 1 {
+		t.Errorf("expired entry should be removed on read, got %d entries", stats.Entries)
+	}
+}
+
+func TestEventCacheInvalidate(t *testing.T) {
+	fill := func() *eventCache {
+		c := newEventCache(time.Minute, 10)
+		for _, cal := range []string{"primary", "team", "other"} {
+			c.put(cal, cal, &Event{ID: cal})
+		}
+		return c
+	}
+	cached := func(c *eventCache, key string) bool {
+		var e Event
+		return c.get(key, &e)
+	}
+
+	c := fill()
+	c.invalidate("team")
+	if cached(c, "team") || cached(c, "primary") {
+		t.Error("a write to team should drop team and primary reads")
+	}
+	if !cached(c, "other") {
+		t.Error("a write to team should keep other calendars")
+	}
+
+	c = fill()
+	c.invalidate("primary")
+	if stats := c.stats(); stats.Entries != 0 {
+		t.Errorf("a write to primary should drop every read, %d left", stats.Entries)
+	}
+}
+
+func TestBypassCache(t *testing.T) {
+	c := NewCalendarClient(nil)
+	if c.cached(context.Background()) {
+		t.Error("cache should be off by default")
+	}
+	c.SetEventCache(time.Minute, 10)
+	if !c.cached(context.Background()) {
+		t.Error("cache should be used once enabled")
+	}
+	if c.cached(BypassCache(context.Background())) {
+		t.Error("BypassCache should skip the cache")
+	}
+	c.SetEventCache(0, 10)
+	if _, ok := c.EventCacheStats(); ok {
+		t.Error("a zero TTL should disable the cache")
+	}
+}
@@ -3,17 +3,34 @@ package google
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"google.golang.org/api/calendar/v3"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
+
+	"github.com/dtorcivia/schedlock/internal/util"
 )
 
+// DefaultCalendarID is used when a call is made without a calendar ID. The
+// API handlers substitute the key's default_calendar constraint before a
+// request reaches the client, so this fallback only applies to keys without one.
+const DefaultCalendarID = "primary"
+
+// ErrTooManyCalls is returned when no call slot frees up within the wait
+// set by SetConcurrencyLimit.
+var ErrTooManyCalls = errors.New("too many concurrent Google Calendar calls")
+
 // CalendarClient provides access to Google Calendar API.
 type CalendarClient struct {
 	oauth *OAuthManager
+
+	slots chan struct{} // one entry per call in flight; nil means unlimited
+	wait  time.Duration
+
+	cache *eventCache // nil when event reads are not cached
 }
 
 // NewCalendarClient creates a new Calendar API client.
@@ -21,27 +38,108 @@ func NewCalendarClient(oauth *OAuthManager) *CalendarClient {
 	return &CalendarClient{oauth: oauth}
 }
 
-// getService returns a configured Calendar API service.
-func (c *CalendarClient) getService(ctx context.Context) (*calendar.Service, error) {
+// SetConcurrencyLimit caps the client's calls in flight at once, across all
+// callers. A call waits up to wait for a slot before failing with
+// ErrTooManyCalls. A limit of 0 removes the cap. It must be called before
+// the client is used.
+func (c *CalendarClient) SetConcurrencyLimit(limit int, wait time.Duration) {
+	c.slots = nil
+	if limit > 0 {
+		c.slots = make(chan struct{}, limit)
+	}
+	c.wait = wait
+}
+
+// SetEventCache caches GetEvent and ListEvents results for ttl, keeping at
+// most size of them. Writes through the client drop the cached reads of the
+// calendars they touch, and BypassCache skips the cache for one call. A ttl
+// of 0 disables the cache. It must be called before the client is used.
+func (c *CalendarClient) SetEventCache(ttl time.Duration, size int) {
+	c.cache = nil
+	if ttl > 0 && size > 0 {
+		c.cache = newEventCache(ttl, size)
+	}
+}
+
+// EventCacheStats returns the event cache's counters, or false when the
+// cache is disabled.
+func (c *CalendarClient) EventCacheStats() (CacheStats, bool) {
+	if c.cache == nil {
+		return CacheStats{}, false
+	}
+	return c.cache.stats(), true
+}
+
+// cached reports whether reads in ctx go through the event cache.
+func (c *CalendarClient) cached(ctx context.Context) bool {
+	return c.cache != nil && !cacheBypassed(ctx)
+}
+
+// invalidate drops cached reads of calendars after a write to them.
+func (c *CalendarClient) invalidate(calendarIDs ...string) {
+	if c.cache != nil {
+		c.cache.invalidate(calendarIDs...)
+	}
+}
+
+// acquire takes a call slot, returning the function that gives it back.
+func (c *CalendarClient) acquire(ctx context.Context) (func(), error) {
+	if c.slots == nil {
+		return func() {}, nil
+	}
+	release := func() { <-c.slots }
+
+	select {
+	case c.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	timer := time.NewTimer(c.wait)
+	defer timer.Stop()
+	select {
+	case c.slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, ErrTooManyCalls
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// getService returns a configured Calendar API service holding a call slot.
+// Callers must call release once their Google calls are done.
+func (c *CalendarClient) getService(ctx context.Context) (service *calendar.Service, release func(), err error) {
+	release, err = c.acquire(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+
 	httpClient, err := c.oauth.GetClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get OAuth client: %w", err)
+		return nil, nil, fmt.Errorf("failed to get OAuth client: %w", err)
 	}
 
-	service, err := calendar.NewService(ctx, option.WithHTTPClient(httpClient))
+	service, err = calendar.NewService(ctx, option.WithHTTPClient(httpClient))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Calendar service: %w", err)
+		return nil, nil, fmt.Errorf("failed to create Calendar service: %w", err)
 	}
 
-	return service, nil
+	return service, release, nil
 }
 
 // ListCalendars returns all accessible calendars.
 func (c *CalendarClient) ListCalendars(ctx context.Context) ([]Calendar, error) {
-	service, err := c.getService(ctx)
+	service, release, err := c.getService(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 
 	list, err := service.CalendarList.List().Context(ctx).Do()
 	if err != nil {
@@ -65,16 +163,24 @@ func (c *CalendarClient) ListCalendars(ctx context.Context) ([]Calendar, error)
 
 // ListEvents returns events from a calendar.
 func (c *CalendarClient) ListEvents(ctx context.Context, opts EventListOptions) (*EventListResponse, error) {
-	service, err := c.getService(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	calendarID := opts.CalendarID
 	if calendarID == "" {
-		calendarID = "primary"
+		calendarID = DefaultCalendarID
+	}
+	cacheKey := listEventsCacheKey(calendarID, opts)
+	if c.cached(ctx) {
+		var resp EventListResponse
+		if c.cache.get(cacheKey, &resp) {
+			return &resp, nil
+		}
 	}
 
+	service, release, err := c.getService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	call := service.Events.List(calendarID).Context(ctx)
 
 	if !opts.TimeMin.IsZero() {
@@ -104,22 +210,34 @@ func (c *CalendarClient) ListEvents(ctx context.Context, opts EventListOptions)
 		return nil, fmt.Errorf("failed to list events: %w", err)
 	}
 
-	return &EventListResponse{
+	resp := &EventListResponse{
 		Events:        convertEvents(events.Items),
 		NextPageToken: events.NextPageToken,
-	}, nil
+	}
+	if c.cached(ctx) {
+		c.cache.put(calendarID, cacheKey, resp)
+	}
+	return resp, nil
 }
 
 // GetEvent returns a single event by ID.
 func (c *CalendarClient) GetEvent(ctx context.Context, calendarID, eventID string) (*Event, error) {
-	service, err := c.getService(ctx)
-	if err != nil {
-		return nil, err
+	if calendarID == "" {
+		calendarID = DefaultCalendarID
+	}
+	cacheKey := getEventCacheKey(calendarID, eventID)
+	if c.cached(ctx) {
+		var cached Event
+		if c.cache.get(cacheKey, &cached) {
+			return &cached, nil
+		}
 	}
 
-	if calendarID == "" {
-		calendarID = "primary"
+	service, release, err := c.getService(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
 
 	event, err := service.Events.Get(calendarID, eventID).Context(ctx).Do()
 	if err != nil {
@@ -127,20 +245,25 @@ func (c *CalendarClient) GetEvent(ctx context.Context, calendarID, eventID strin
 	}
 
 	converted := convertEvent(event)
+	if c.cached(ctx) {
+		c.cache.put(calendarID, cacheKey, &converted)
+	}
 	return &converted, nil
 }
 
 // CreateEvent creates a new event.
 func (c *CalendarClient) CreateEvent(ctx context.Context, intent *EventIntent) (*Event, error) {
-	service, err := c.getService(ctx)
+	service, release, err := c.getService(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 
 	calendarID := intent.CalendarID
 	if calendarID == "" {
-		calendarID = "primary"
+		calendarID = DefaultCalendarID
 	}
+	defer c.invalidate(calendarID)
 
 	// Build Google Calendar event
 	gcalEvent := &calendar.Event{
@@ -163,6 +286,20 @@ func (c *CalendarClient) CreateEvent(ctx context.Context, intent *EventIntent) (
 			})
 		}
 	}
+	for _, email := range intent.OptionalAttendees {
+		gcalEvent.Attendees = append(gcalEvent.Attendees, &calendar.EventAttendee{
+			Email:    email,
+			Optional: true,
+		})
+	}
+
+	// Rooms are booked by inviting the room's resource calendar
+	for _, room := range intent.Rooms {
+		gcalEvent.Attendees = append(gcalEvent.Attendees, &calendar.EventAttendee{
+			Email:    room,
+			Resource: true,
+		})
+	}
 
 	// Add optional fields
 	if intent.ColorID != "" {
@@ -171,6 +308,17 @@ func (c *CalendarClient) CreateEvent(ctx context.Context, intent *EventIntent) (
 	if intent.Visibility != "" {
 		gcalEvent.Visibility = intent.Visibility
 	}
+	if intent.Transparency != "" {
+		gcalEvent.Transparency = intent.Transparency
+	}
+	if intent.GuestsCanModify != nil {
+		gcalEvent.GuestsCanModify = *intent.GuestsCanModify
+	}
+	gcalEvent.GuestsCanInviteOthers = intent.GuestsCanInviteOthers
+	gcalEvent.GuestsCanSeeOtherGuests = intent.GuestsCanSeeOtherGuests
+	if len(intent.ExtendedProperties) > 0 {
+		gcalEvent.ExtendedProperties = &calendar.EventExtendedProperties{Private: intent.ExtendedProperties}
+	}
 	if intent.Reminders != nil {
 		gcalEvent.Reminders = &calendar.EventReminders{
 			UseDefault: intent.Reminders.UseDefault,
@@ -184,7 +332,11 @@ func (c *CalendarClient) CreateEvent(ctx context.Context, intent *EventIntent) (
 		}
 	}
 
-	created, err := service.Events.Insert(calendarID, gcalEvent).Context(ctx).Do()
+	call := service.Events.Insert(calendarID, gcalEvent)
+	if intent.SendUpdates != "" {
+		call = call.SendUpdates(intent.SendUpdates)
+	}
+	created, err := call.Context(ctx).Do()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create event: %w", err)
 	}
@@ -193,104 +345,235 @@ func (c *CalendarClient) CreateEvent(ctx context.Context, intent *EventIntent) (
 	return &converted, nil
 }
 
-// UpdateEvent updates an existing event using PATCH semantics.
+// UpdateEvent updates an existing event using PATCH semantics. For a
+// recurring event, RecurringEditScope picks the occurrence, the series, or
+// the occurrence and every later one.
 func (c *CalendarClient) UpdateEvent(ctx context.Context, intent *EventUpdateIntent) (*Event, error) {
-	service, err := c.getService(ctx)
+	service, release, err := c.getService(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 
 	calendarID := intent.CalendarID
 	if calendarID == "" {
-		calendarID = "primary"
+		calendarID = DefaultCalendarID
+	}
+	defer c.invalidate(calendarID)
+
+	// Validate Start < End if both are being updated
+	if intent.Start != nil && intent.End != nil {
+		if !intent.Start.Before(*intent.End) {
+			return nil, fmt.Errorf("start time must be before end time")
+		}
+	}
+
+	if intent.RecurringEditScope == RecurringEditFollowing {
+		return updateFollowing(ctx, service, calendarID, intent)
 	}
 
 	// Build a patch event with only the fields we want to update
 	patchEvent := &calendar.Event{}
+	applyUpdate(patchEvent, intent)
 
+	// Use Patch instead of Update - only sends the fields we specify.
+	// An occurrence ID patches that occurrence alone; a series ID, every
+	// occurrence that has not been changed on its own.
+	eventID := intent.TargetEventID()
+	call := service.Events.Patch(calendarID, eventID, patchEvent)
+	if intent.SendUpdates != "" {
+		call = call.SendUpdates(intent.SendUpdates)
+	}
+	updated, err := call.Context(ctx).Do()
+	if err != nil {
+		// Extract detailed error information from Google API
+		var details string
+		if gErr, ok := err.(*googleapi.Error); ok {
+			details = fmt.Sprintf("code=%d, message=%s, errors=%v", gErr.Code, gErr.Message, gErr.Errors)
+		}
+		return nil, fmt.Errorf("failed to update event (calendar=%s, event=%s, details=%s): %w", calendarID, eventID, details, err)
+	}
+
+	converted := convertEvent(updated)
+	return &converted, nil
+}
+
+// applyUpdate sets the fields the intent changes on event. Fields the intent
+// leaves unset are not touched.
+func applyUpdate(event *calendar.Event, intent *EventUpdateIntent) {
 	if intent.Summary != nil {
-		patchEvent.Summary = *intent.Summary
+		event.Summary = *intent.Summary
 	}
 	if intent.Description != nil {
-		patchEvent.Description = *intent.Description
+		event.Description = *intent.Description
 	}
 	if intent.Location != nil {
-		patchEvent.Location = *intent.Location
+		event.Location = *intent.Location
 	}
 	if intent.Start != nil {
-		// Use RFC3339 format which includes timezone offset - don't set TimeZone separately
-		patchEvent.Start = &calendar.EventDateTime{
+		// Use RFC3339 format which includes timezone offset. A time zone
+		// already on the event is kept; recurring events need one.
+		event.Start = &calendar.EventDateTime{
 			DateTime: intent.Start.Format(time.RFC3339),
+			TimeZone: eventTimeZone(event.Start),
 		}
 	}
 	if intent.End != nil {
-		// Use RFC3339 format which includes timezone offset - don't set TimeZone separately
-		patchEvent.End = &calendar.EventDateTime{
+		event.End = &calendar.EventDateTime{
 			DateTime: intent.End.Format(time.RFC3339),
-		}
-	}
-
-	// Validate Start < End if both are being updated
-	if intent.Start != nil && intent.End != nil {
-		if !intent.Start.Before(*intent.End) {
-			return nil, fmt.Errorf("start time must be before end time")
+			TimeZone: eventTimeZone(event.End),
 		}
 	}
 
 	if len(intent.Attendees) > 0 {
+		event.Attendees = nil
 		for _, email := range intent.Attendees {
-			patchEvent.Attendees = append(patchEvent.Attendees, &calendar.EventAttendee{
+			event.Attendees = append(event.Attendees, &calendar.EventAttendee{
 				Email: email,
 			})
 		}
 	}
 	if intent.ColorID != nil {
-		patchEvent.ColorId = *intent.ColorID
+		event.ColorId = *intent.ColorID
 	}
 	if intent.Visibility != nil {
-		patchEvent.Visibility = *intent.Visibility
+		event.Visibility = *intent.Visibility
+	}
+	if intent.Transparency != nil {
+		event.Transparency = *intent.Transparency
+	}
+	if intent.GuestsCanModify != nil {
+		event.GuestsCanModify = *intent.GuestsCanModify
+		// false is the zero value, so it must be sent explicitly to clear the flag
+		event.ForceSendFields = append(event.ForceSendFields, "GuestsCanModify")
+	}
+	if intent.GuestsCanInviteOthers != nil {
+		event.GuestsCanInviteOthers = intent.GuestsCanInviteOthers
+	}
+	if intent.GuestsCanSeeOtherGuests != nil {
+		event.GuestsCanSeeOtherGuests = intent.GuestsCanSeeOtherGuests
+	}
+	if len(intent.ExtendedProperties) > 0 {
+		// Patch merges private properties, so keys that are not sent are kept
+		private := make(map[string]string)
+		if event.ExtendedProperties != nil {
+			for k, v := range event.ExtendedProperties.Private {
+				private[k] = v
+			}
+		}
+		for k, v := range intent.ExtendedProperties {
+			private[k] = v
+		}
+		event.ExtendedProperties = &calendar.EventExtendedProperties{Private: private}
 	}
 	if intent.Reminders != nil {
-		patchEvent.Reminders = &calendar.EventReminders{
+		event.Reminders = &calendar.EventReminders{
 			UseDefault: intent.Reminders.UseDefault,
 		}
 		for _, r := range intent.Reminders.Overrides {
-			patchEvent.Reminders.Overrides = append(patchEvent.Reminders.Overrides,
+			event.Reminders.Overrides = append(event.Reminders.Overrides,
 				&calendar.EventReminder{
 					Method:  r.Method,
 					Minutes: int64(r.Minutes),
 				})
 		}
 	}
+}
+
+// eventTimeZone returns the time zone set on an event time, if any.
+func eventTimeZone(t *calendar.EventDateTime) string {
+	if t == nil {
+		return ""
+	}
+	return t.TimeZone
+}
 
-	// Use Patch instead of Update - only sends the fields we specify
-	updated, err := service.Events.Patch(calendarID, intent.EventID, patchEvent).Context(ctx).Do()
+// updateFollowing changes an occurrence of a recurring event and every later
+// one. Google has no call for this, so the series is split as Google
+// Calendar does it: a copy of the series starting at the occurrence gets the
+// changes, then the original series is ended just before the occurrence.
+func updateFollowing(ctx context.Context, service *calendar.Service, calendarID string, intent *EventUpdateIntent) (*Event, error) {
+	instance, err := service.Events.Get(calendarID, intent.EventID).Context(ctx).Do()
 	if err != nil {
-		// Extract detailed error information from Google API
-		var details string
-		if gErr, ok := err.(*googleapi.Error); ok {
-			details = fmt.Sprintf("code=%d, message=%s, errors=%v", gErr.Code, gErr.Message, gErr.Errors)
+		return nil, fmt.Errorf("failed to get occurrence (calendar=%s, event=%s): %w", calendarID, intent.EventID, err)
+	}
+	if instance.RecurringEventId == "" || instance.OriginalStartTime == nil {
+		return nil, fmt.Errorf("event %s is not an occurrence of a recurring event", intent.EventID)
+	}
+	series, err := service.Events.Get(calendarID, instance.RecurringEventId).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recurring event (calendar=%s, event=%s): %w", calendarID, instance.RecurringEventId, err)
+	}
+
+	ended, err := endRecurrenceBefore(series.Recurrence, instance.OriginalStartTime)
+	if err != nil {
+		return nil, err
+	}
+
+	following := &calendar.Event{
+		Summary:                 series.Summary,
+		Description:             series.Description,
+		Location:                series.Location,
+		ColorId:                 series.ColorId,
+		Visibility:              series.Visibility,
+		Transparency:            series.Transparency,
+		Attendees:               series.Attendees,
+		Reminders:               series.Reminders,
+		ExtendedProperties:      series.ExtendedProperties,
+		GuestsCanModify:         series.GuestsCanModify,
+		GuestsCanInviteOthers:   series.GuestsCanInviteOthers,
+		GuestsCanSeeOtherGuests: series.GuestsCanSeeOtherGuests,
+		Start:                   instance.Start,
+		End:                     instance.End,
+		Recurrence:              series.Recurrence,
+	}
+	applyUpdate(following, intent)
+
+	insert := service.Events.Insert(calendarID, following)
+	if intent.SendUpdates != "" {
+		insert = insert.SendUpdates(intent.SendUpdates)
+	}
+	created, err := insert.Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create following occurrences (calendar=%s, event=%s): %w", calendarID, intent.EventID, err)
+	}
+
+	patch := service.Events.Patch(calendarID, series.Id, &calendar.Event{Recurrence: ended})
+	if intent.SendUpdates != "" {
+		patch = patch.SendUpdates(intent.SendUpdates)
+	}
+	if _, err := patch.Context(ctx).Do(); err != nil {
+		// Without the original series ending, both would show the later
+		// occurrences; remove the copy so nothing is doubled.
+		if delErr := service.Events.Delete(calendarID, created.Id).Context(ctx).Do(); delErr != nil {
+			util.Error("Failed to remove new series after failed split", "calendar", calendarID, "event", created.Id, "error", delErr)
 		}
-		return nil, fmt.Errorf("failed to update event (calendar=%s, event=%s, details=%s): %w", calendarID, intent.EventID, details, err)
+		return nil, fmt.Errorf("failed to end recurring event (calendar=%s, event=%s): %w", calendarID, series.Id, err)
 	}
 
-	converted := convertEvent(updated)
+	converted := convertEvent(created)
 	return &converted, nil
 }
 
 // DeleteEvent deletes an event.
 func (c *CalendarClient) DeleteEvent(ctx context.Context, intent *EventDeleteIntent) error {
-	service, err := c.getService(ctx)
+	service, release, err := c.getService(ctx)
 	if err != nil {
 		return err
 	}
+	defer release()
 
 	calendarID := intent.CalendarID
 	if calendarID == "" {
-		calendarID = "primary"
+		calendarID = DefaultCalendarID
 	}
+	defer c.invalidate(calendarID)
 
-	err = service.Events.Delete(calendarID, intent.EventID).Context(ctx).Do()
+	call := service.Events.Delete(calendarID, intent.EventID)
+	if intent.SendUpdates != "" {
+		call = call.SendUpdates(intent.SendUpdates)
+	}
+	err = call.Context(ctx).Do()
 	if err != nil {
 		return fmt.Errorf("failed to delete event (calendar=%s, event=%s): %w", calendarID, intent.EventID, err)
 	}
@@ -298,12 +581,33 @@ func (c *CalendarClient) DeleteEvent(ctx context.Context, intent *EventDeleteInt
 	return nil
 }
 
+// MoveEvent moves an event from its source calendar to a destination calendar.
+func (c *CalendarClient) MoveEvent(ctx context.Context, intent *EventMoveIntent) (*Event, error) {
+	service, release, err := c.getService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	defer c.invalidate(intent.CalendarID, intent.DestinationCalendarID)
+
+	moved, err := service.Events.Move(intent.CalendarID, intent.EventID, intent.DestinationCalendarID).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to move event (calendar=%s, event=%s, destination=%s): %w",
+			intent.CalendarID, intent.EventID, intent.DestinationCalendarID, err)
+	}
+
+	converted := convertEvent(moved)
+	return &converted, nil
+}
+
 // FreeBusy checks availability.
 func (c *CalendarClient) FreeBusy(ctx context.Context, req *FreeBusyRequest) (*FreeBusyResponse, error) {
-	service, err := c.getService(ctx)
+	service, release, err := c.getService(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 
 	// Build request
 	fbReq := &calendar.FreeBusyRequest{
@@ -340,8 +644,8 @@ func (c *CalendarClient) FreeBusy(ctx context.Context, req *FreeBusyRequest) (*F
 		}
 		for _, err := range calInfo.Errors {
 			info.Errors = append(info.Errors, Error{
-				Domain:  err.Domain,
-				Reason:  err.Reason,
+				Domain: err.Domain,
+				Reason: err.Reason,
 			})
 		}
 		result.Calendars[calID] = info
@@ -362,14 +666,19 @@ func convertEvents(items []*calendar.Event) []Event {
 
 func convertEvent(e *calendar.Event) Event {
 	event := Event{
-		ID:          e.Id,
-		Summary:     e.Summary,
-		Description: e.Description,
-		Location:    e.Location,
-		HtmlLink:    e.HtmlLink,
-		Status:      e.Status,
-		ColorId:     e.ColorId,
-		Visibility:  e.Visibility,
+		ID:           e.Id,
+		Summary:      e.Summary,
+		Description:  e.Description,
+		Location:     e.Location,
+		HtmlLink:     e.HtmlLink,
+		Status:       e.Status,
+		ColorId:      e.ColorId,
+		Visibility:   e.Visibility,
+		Transparency: e.Transparency,
+
+		GuestsCanModify:         e.GuestsCanModify,
+		GuestsCanInviteOthers:   boolOr(e.GuestsCanInviteOthers, DefaultGuestsCanInviteOthers),
+		GuestsCanSeeOtherGuests: boolOr(e.GuestsCanSeeOtherGuests, DefaultGuestsCanSeeOtherGuests),
 	}
 
 	if e.Start != nil {
@@ -400,9 +709,14 @@ func convertEvent(e *calendar.Event) Event {
 			Optional:       a.Optional,
 			Organizer:      a.Organizer,
 			Self:           a.Self,
+			Resource:       a.Resource,
 		})
 	}
 
+	if e.ExtendedProperties != nil && len(e.ExtendedProperties.Private) > 0 {
+		event.ExtendedProperties = e.ExtendedProperties.Private
+	}
+
 	if e.Creator != nil {
 		event.Creator = &Person{
 			Email:       e.Creator.Email,
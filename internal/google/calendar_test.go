@@ -0,0 +1,55 @@
+package google
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCalendarClientConcurrencyLimit(t *testing.T) {
+	c := NewCalendarClient(nil)
+	c.SetConcurrencyLimit(2, 20*time.Millisecond)
+	ctx := context.Background()
+
+	first, err := c.acquire(ctx)
+	if err != nil {
+		t.Fatalf("first acquire: %v", err)
+	}
+	second, err := c.acquire(ctx)
+	if err != nil {
+		t.Fatalf("second acquire: %v", err)
+	}
+	defer second()
+	if _, err := c.acquire(ctx); !errors.Is(err, ErrTooManyCalls) {
+		t.Fatalf("acquire over the limit: got %v, want ErrTooManyCalls", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := c.acquire(cancelled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("acquire with cancelled context: got %v, want context.Canceled", err)
+	}
+
+	// A waiting call takes the slot as soon as one is released
+	c.wait = time.Second
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		first()
+	}()
+	third, err := c.acquire(ctx)
+	if err != nil {
+		t.Fatalf("acquire after release: %v", err)
+	}
+	third()
+}
+
+func TestCalendarClientUnlimited(t *testing.T) {
+	c := NewCalendarClient(nil)
+	c.SetConcurrencyLimit(0, 0)
+	for i := 0; i < 100; i++ {
+		if _, err := c.acquire(context.Background()); err != nil {
+			t.Fatalf("acquire %d: %v", i, err)
+		}
+	}
+}
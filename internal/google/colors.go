@@ -0,0 +1,76 @@
+package google
+
+import (
+	"context"
+	"fmt"
+)
+
+// Color is one entry in Google Calendar's color palette.
+type Color struct {
+	Background string `json:"background"`
+	Foreground string `json:"foreground"`
+}
+
+// ColorPalette maps color IDs to colors. Event colors are the valid values
+// for an event's colorId; calendar colors apply to whole calendars.
+type ColorPalette struct {
+	Event    map[string]Color `json:"event"`
+	Calendar map[string]Color `json:"calendar,omitempty"`
+	Source   string           `json:"source"` // "google" or "fallback"
+}
+
+// Palette sources.
+const (
+	ColorSourceGoogle   = "google"
+	ColorSourceFallback = "fallback"
+)
+
+// FallbackColorPalette returns Google's standard event colors, for use when
+// the palette cannot be fetched (for example before OAuth is connected).
+// Calendar colors vary more between accounts and are left out.
+func FallbackColorPalette() *ColorPalette {
+	event := map[string]Color{}
+	for id, background := range map[string]string{
+		"1":  "#a4bdfc",
+		"2":  "#7ae7bf",
+		"3":  "#dbadff",
+		"4":  "#ff887c",
+		"5":  "#fbd75b",
+		"6":  "#ffb878",
+		"7":  "#46d6db",
+		"8":  "#e1e1e1",
+		"9":  "#5484ed",
+		"10": "#51b749",
+		"11": "#dc2127",
+	} {
+		event[id] = Color{Background: background, Foreground: "#1d1d1d"}
+	}
+	return &ColorPalette{Event: event, Source: ColorSourceFallback}
+}
+
+// GetColors returns the account's event and calendar color palette.
+func (c *CalendarClient) GetColors(ctx context.Context) (*ColorPalette, error) {
+	service, release, err := c.getService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	colors, err := service.Colors.Get().Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get colors: %w", err)
+	}
+
+	palette := &ColorPalette{
+		Event:    make(map[string]Color, len(colors.Event)),
+		Calendar: make(map[string]Color, len(colors.Calendar)),
+		Source:   ColorSourceGoogle,
+	}
+	for id, def := range colors.Event {
+		palette.Event[id] = Color{Background: def.Background, Foreground: def.Foreground}
+	}
+	for id, def := range colors.Calendar {
+		palette.Calendar[id] = Color{Background: def.Background, Foreground: def.Foreground}
+	}
+	return palette, nil
+}
@@ -0,0 +1,62 @@
+package google
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Google shortens well-known calendar ID domains inside event links.
+var eidCalendarSuffixes = map[string]string{
+	"@g": "@group.calendar.google.com",
+	"@m": "@gmail.com",
+}
+
+// ParseEventURL extracts the calendar and event IDs from a Google Calendar
+// event link. Both the "event?eid=" share links and "r/eventedit/" editor
+// links are supported; the eid is base64 of "<eventId> <calendarId>".
+func ParseEventURL(raw string) (calendarID, eventID string, err error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", "", fmt.Errorf("invalid event URL: %w", err)
+	}
+	if u.Host != "calendar.google.com" && u.Host != "www.google.com" {
+		return "", "", fmt.Errorf("not a Google Calendar URL")
+	}
+
+	eid := u.Query().Get("eid")
+	if eid == "" {
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		for i, segment := range segments {
+			if (segment == "eventedit" || segment == "event") && i+1 < len(segments) {
+				eid = segments[i+1]
+				break
+			}
+		}
+	}
+	if eid == "" {
+		return "", "", fmt.Errorf("event URL has no event identifier")
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(eid, "="))
+	if err != nil {
+		if decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(eid, "=")); err != nil {
+			return "", "", fmt.Errorf("event URL identifier is not valid base64")
+		}
+	}
+
+	parts := strings.Fields(string(decoded))
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("event URL identifier is malformed")
+	}
+	eventID, calendarID = parts[0], parts[1]
+	for short, full := range eidCalendarSuffixes {
+		if strings.HasSuffix(calendarID, short) {
+			calendarID = strings.TrimSuffix(calendarID, short) + full
+			break
+		}
+	}
+
+	return calendarID, eventID, nil
+}
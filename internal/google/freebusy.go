@@ -0,0 +1,41 @@
+package google
+
+import "sort"
+
+// MergeBusyPeriods sorts busy periods and coalesces overlapping or touching ones.
+func MergeBusyPeriods(periods []TimePeriod) []TimePeriod {
+	merged := make([]TimePeriod, 0, len(periods))
+	if len(periods) == 0 {
+		return merged
+	}
+
+	sorted := append([]TimePeriod(nil), periods...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	current := sorted[0]
+	for _, period := range sorted[1:] {
+		if !period.Start.After(current.End) {
+			if period.End.After(current.End) {
+				current.End = period.End
+			}
+			continue
+		}
+		merged = append(merged, current)
+		current = period
+	}
+	return append(merged, current)
+}
+
+// BusyPeriods returns the merged busy periods across all calendars in the response.
+func (r *FreeBusyResponse) BusyPeriods() []TimePeriod {
+	if r == nil {
+		return MergeBusyPeriods(nil)
+	}
+	var busy []TimePeriod
+	for _, info := range r.Calendars {
+		busy = append(busy, info.Busy...)
+	}
+	return MergeBusyPeriods(busy)
+}
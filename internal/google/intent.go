@@ -3,6 +3,9 @@ package google
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/dtorcivia/schedlock/internal/util"
@@ -11,16 +14,31 @@ import (
 // EventIntent represents the constrained schema for event creation/update.
 // Unknown fields from API requests are silently ignored for security.
 type EventIntent struct {
-	CalendarID  string     `json:"calendarId"`            // Required: "primary" or calendar ID
-	Summary     string     `json:"summary"`               // Required: Event title
-	Description string     `json:"description,omitempty"` // Optional: Event description
-	Location    string     `json:"location,omitempty"`    // Optional: Location text
-	Start       time.Time  `json:"start"`                 // Required: RFC3339 with timezone
-	End         time.Time  `json:"end"`                   // Required: RFC3339 with timezone
-	Attendees   []string   `json:"attendees,omitempty"`   // Optional: Email addresses
-	ColorID     string     `json:"colorId,omitempty"`     // Optional: Event color (1-11)
-	Visibility  string     `json:"visibility,omitempty"`  // Optional: "default", "public", "private"
-	Reminders   *Reminders `json:"reminders,omitempty"`   // Optional: Custom reminders
+	CalendarID   string     `json:"calendarId"`             // "primary" or calendar ID; the API fills in the key's default
+	Summary      string     `json:"summary"`                // Required: Event title
+	Description  string     `json:"description,omitempty"`  // Optional: Event description
+	Location     string     `json:"location,omitempty"`     // Optional: Location text
+	Start        time.Time  `json:"start"`                  // Required: RFC3339 with timezone
+	End          time.Time  `json:"end"`                    // Required unless durationMinutes is set: RFC3339 with timezone
+	Attendees    []string   `json:"attendees,omitempty"`    // Optional: Email addresses
+	Rooms        []string   `json:"rooms,omitempty"`        // Optional: Room resource emails, booked as resource attendees
+	ColorID      string     `json:"colorId,omitempty"`      // Optional: Event color (1-11)
+	Visibility   string     `json:"visibility,omitempty"`   // Optional: "default", "public", "private"
+	Transparency string     `json:"transparency,omitempty"` // Optional: "opaque" (busy) or "transparent" (free)
+	Reminders    *Reminders `json:"reminders,omitempty"`    // Optional: Custom reminders
+
+	OptionalAttendees []string `json:"optionalAttendees,omitempty"` // Optional: Email addresses invited as optional (FYI) guests
+
+	DurationMinutes int `json:"durationMinutes,omitempty"` // Optional: length of the event, instead of end; Validate turns it into End
+
+	ExtendedProperties map[string]string `json:"extendedProperties,omitempty"` // Optional: private key/value metadata
+
+	SendUpdates string `json:"sendUpdates,omitempty"` // Optional: who Google emails: "all", "externalOnly" or "none"
+
+	// Guest permissions; nil leaves Google's default (modify: false, invite and see others: true)
+	GuestsCanModify         *bool `json:"guestsCanModify,omitempty"`
+	GuestsCanInviteOthers   *bool `json:"guestsCanInviteOthers,omitempty"`
+	GuestsCanSeeOtherGuests *bool `json:"guestsCanSeeOtherGuests,omitempty"`
 }
 
 // Validate checks if the EventIntent has all required fields and valid values.
@@ -40,8 +58,12 @@ func (e *EventIntent) Validate() error {
 		return fmt.Errorf("start time is required")
 	}
 
+	if err := e.resolveDuration(); err != nil {
+		return err
+	}
+
 	if e.End.IsZero() {
-		return fmt.Errorf("end time is required")
+		return fmt.Errorf("end time or durationMinutes is required")
 	}
 
 	if err := util.ValidateTimeRange(e.Start, e.End, false); err != nil {
@@ -60,36 +82,235 @@ func (e *EventIntent) Validate() error {
 		}
 	}
 
+	if err := util.ValidateTransparency(e.Transparency); err != nil {
+		return err
+	}
+
+	if err := util.ValidateSendUpdates(e.SendUpdates); err != nil {
+		return err
+	}
+
 	if len(e.Attendees) > 0 {
 		if err := util.ValidateEmails(e.Attendees); err != nil {
 			return err
 		}
 	}
 
+	if len(e.OptionalAttendees) > 0 {
+		if err := util.ValidateEmails(e.OptionalAttendees); err != nil {
+			return fmt.Errorf("optionalAttendees: %w", err)
+		}
+		for _, optional := range e.OptionalAttendees {
+			for _, required := range e.Attendees {
+				if strings.EqualFold(optional, required) {
+					return fmt.Errorf("%s is listed in both attendees and optionalAttendees", optional)
+				}
+			}
+		}
+	}
+
+	if len(e.Rooms) > 0 {
+		if err := util.ValidateEmails(e.Rooms); err != nil {
+			return fmt.Errorf("rooms: %w", err)
+		}
+	}
+
+	if err := util.ValidateExtendedProperties(e.ExtendedProperties); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-// Sanitize cleans and normalizes the EventIntent fields.
-func (e *EventIntent) Sanitize() {
+// resolveDuration sets End from DurationMinutes, so constraints, approvers
+// and Google only ever see an end time. An end that is also given must agree
+// with the duration.
+func (e *EventIntent) resolveDuration() error {
+	if e.DurationMinutes == 0 {
+		return nil
+	}
+	if e.DurationMinutes < 0 {
+		return fmt.Errorf("durationMinutes must be positive")
+	}
+	end := e.Start.Add(time.Duration(e.DurationMinutes) * time.Minute)
+	if !e.End.IsZero() && !e.End.Equal(end) {
+		return fmt.Errorf("end and durationMinutes disagree; set only one of them")
+	}
+	e.End = end
+	e.DurationMinutes = 0
+	return nil
+}
+
+// AllAttendees returns the required attendees followed by the optional
+// ones. Attendee constraints apply to both.
+func (e *EventIntent) AllAttendees() []string {
+	if len(e.OptionalAttendees) == 0 {
+		return e.Attendees
+	}
+	all := make([]string, 0, len(e.Attendees)+len(e.OptionalAttendees))
+	all = append(all, e.Attendees...)
+	return append(all, e.OptionalAttendees...)
+}
+
+// Sanitize cleans and normalizes the EventIntent fields. HTML in the
+// description is stripped unless allowHTML is set, in which case only
+// allowlisted formatting tags are kept.
+func (e *EventIntent) Sanitize(allowHTML bool) {
 	e.Summary = util.SanitizeString(e.Summary)
-	e.Description = util.SanitizeString(e.Description)
+	e.Description = util.SanitizeDescription(e.Description, allowHTML)
 	e.Location = util.SanitizeString(e.Location)
 }
 
+// IntentFromEvent builds an EventIntent that recreates an existing event on the
+// given calendar. All-day events are not supported by EventIntent.
+func IntentFromEvent(event *Event, calendarID string) (*EventIntent, error) {
+	if event.Start == nil || event.End == nil || event.Start.DateTime.IsZero() || event.End.DateTime.IsZero() {
+		return nil, fmt.Errorf("all-day events cannot be duplicated")
+	}
+
+	intent := &EventIntent{
+		CalendarID:   calendarID,
+		Summary:      event.Summary,
+		Description:  event.Description,
+		Location:     event.Location,
+		Start:        event.Start.DateTime,
+		End:          event.End.DateTime,
+		ColorID:      event.ColorId,
+		Visibility:   event.Visibility,
+		Transparency: event.Transparency,
+
+		GuestsCanModify:         boolPtr(event.GuestsCanModify),
+		GuestsCanInviteOthers:   boolPtr(event.GuestsCanInviteOthers),
+		GuestsCanSeeOtherGuests: boolPtr(event.GuestsCanSeeOtherGuests),
+	}
+	for _, attendee := range event.Attendees {
+		// The organizer is implied by the calendar the copy is created on
+		switch {
+		case attendee.Email == "" || attendee.Self:
+		case attendee.Resource:
+			intent.Rooms = append(intent.Rooms, attendee.Email)
+		case attendee.Optional:
+			intent.OptionalAttendees = append(intent.OptionalAttendees, attendee.Email)
+		default:
+			intent.Attendees = append(intent.Attendees, attendee.Email)
+		}
+	}
+	if event.Reminders != nil && !event.Reminders.UseDefault {
+		intent.Reminders = event.Reminders
+	}
+
+	return intent, nil
+}
+
+// UpdateIntentFromEvent builds an EventUpdateIntent prefilled with an existing
+// event's current values, as a starting point for an edit. Start and End are
+// left empty for all-day events, which EventUpdateIntent cannot express.
+func UpdateIntentFromEvent(event *Event, calendarID string) *EventUpdateIntent {
+	intent := &EventUpdateIntent{
+		CalendarID:  calendarID,
+		EventID:     event.ID,
+		Summary:     &event.Summary,
+		Description: &event.Description,
+		Location:    &event.Location,
+	}
+	if event.ColorId != "" {
+		intent.ColorID = &event.ColorId
+	}
+	if event.Visibility != "" {
+		intent.Visibility = &event.Visibility
+	}
+	if event.Transparency != "" {
+		intent.Transparency = &event.Transparency
+	}
+	if event.Start != nil && event.End != nil && !event.Start.DateTime.IsZero() && !event.End.DateTime.IsZero() {
+		intent.Start = &event.Start.DateTime
+		intent.End = &event.End.DateTime
+	}
+	for _, attendee := range event.Attendees {
+		if attendee.Email != "" && !attendee.Self {
+			intent.Attendees = append(intent.Attendees, attendee.Email)
+		}
+	}
+	if event.Reminders != nil {
+		intent.Reminders = event.Reminders
+	}
+
+	return intent
+}
+
 // EventUpdateIntent represents the schema for event updates.
 // Only provided fields will be updated (PATCH semantics).
 type EventUpdateIntent struct {
-	CalendarID  string     `json:"calendarId"`            // Required: "primary" or calendar ID
-	EventID     string     `json:"eventId"`               // Required: Event to update
-	Summary     *string    `json:"summary,omitempty"`     // Optional: New title
-	Description *string    `json:"description,omitempty"` // Optional: New description
-	Location    *string    `json:"location,omitempty"`    // Optional: New location
-	Start       *time.Time `json:"start,omitempty"`       // Optional: New start time
-	End         *time.Time `json:"end,omitempty"`         // Optional: New end time
-	Attendees   []string   `json:"attendees,omitempty"`   // Optional: Replace attendees
-	ColorID     *string    `json:"colorId,omitempty"`     // Optional: New color
-	Visibility  *string    `json:"visibility,omitempty"`  // Optional: New visibility
-	Reminders   *Reminders `json:"reminders,omitempty"`   // Optional: New reminders
+	CalendarID   string     `json:"calendarId"`             // "primary" or calendar ID; the API fills in the key's default
+	EventID      string     `json:"eventId"`                // Required: Event to update
+	Summary      *string    `json:"summary,omitempty"`      // Optional: New title
+	Description  *string    `json:"description,omitempty"`  // Optional: New description
+	Location     *string    `json:"location,omitempty"`     // Optional: New location
+	Start        *time.Time `json:"start,omitempty"`        // Optional: New start time
+	End          *time.Time `json:"end,omitempty"`          // Optional: New end time
+	Attendees    []string   `json:"attendees,omitempty"`    // Optional: Replace attendees
+	ColorID      *string    `json:"colorId,omitempty"`      // Optional: New color
+	Visibility   *string    `json:"visibility,omitempty"`   // Optional: New visibility
+	Transparency *string    `json:"transparency,omitempty"` // Optional: New busy/free setting
+	Reminders    *Reminders `json:"reminders,omitempty"`    // Optional: New reminders
+
+	ExtendedProperties map[string]string `json:"extendedProperties,omitempty"` // Optional: Private properties to set; others are kept
+
+	SendUpdates string `json:"sendUpdates,omitempty"` // Optional: who Google emails about the change
+
+	// Guest permissions; nil keeps the event's current setting
+	GuestsCanModify         *bool `json:"guestsCanModify,omitempty"`
+	GuestsCanInviteOthers   *bool `json:"guestsCanInviteOthers,omitempty"`
+	GuestsCanSeeOtherGuests *bool `json:"guestsCanSeeOtherGuests,omitempty"`
+
+	// RecurringEditScope says which occurrences of a recurring event change.
+	// Empty updates eventId as given, like RecurringEditInstance for an
+	// instance ID or RecurringEditAll for a series ID.
+	RecurringEditScope string `json:"recurringEditScope,omitempty"`
+}
+
+// Recurring edit scopes for EventUpdateIntent.RecurringEditScope.
+const (
+	RecurringEditInstance  = "instance"  // only the occurrence eventId names
+	RecurringEditFollowing = "following" // that occurrence and every later one
+	RecurringEditAll       = "all"       // every occurrence in the series
+)
+
+// RecurringEditScopes lists the valid recurring edit scopes.
+var RecurringEditScopes = []string{RecurringEditInstance, RecurringEditFollowing, RecurringEditAll}
+
+// instanceIDPattern matches the ID Google gives an occurrence of a recurring
+// event: the series ID, an underscore and the occurrence's original start,
+// a date for all-day events or a UTC date-time otherwise.
+var instanceIDPattern = regexp.MustCompile(`^(.+)_(\d{8}(?:T\d{6}Z)?)$`)
+
+// SplitInstanceID returns the series ID and original start of a recurring
+// event occurrence ID such as "abc123_20260301T150000Z". ok is false if id
+// is not an occurrence ID.
+func SplitInstanceID(id string) (seriesID, originalStart string, ok bool) {
+	m := instanceIDPattern.FindStringSubmatch(id)
+	if m == nil {
+		return "", "", false
+	}
+	layout := "20060102"
+	if len(m[2]) > len(layout) {
+		layout = "20060102T150405Z"
+	}
+	if _, err := time.Parse(layout, m[2]); err != nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// TargetEventID returns the event the update is applied to: the series for
+// RecurringEditAll, otherwise eventId.
+func (e *EventUpdateIntent) TargetEventID() string {
+	if e.RecurringEditScope == RecurringEditAll {
+		if seriesID, _, ok := SplitInstanceID(e.EventID); ok {
+			return seriesID
+		}
+	}
+	return e.EventID
 }
 
 // Validate checks if the EventUpdateIntent has all required fields and valid values.
@@ -105,6 +326,16 @@ func (e *EventUpdateIntent) Validate() error {
 		return fmt.Errorf("eventId is required")
 	}
 
+	switch e.RecurringEditScope {
+	case "", RecurringEditAll:
+	case RecurringEditInstance, RecurringEditFollowing:
+		if _, _, ok := SplitInstanceID(e.EventID); !ok {
+			return fmt.Errorf("recurringEditScope %q requires an occurrence eventId such as eventId_20060102 or eventId_20060102T150405Z", e.RecurringEditScope)
+		}
+	default:
+		return fmt.Errorf("recurringEditScope must be one of: %s", strings.Join(RecurringEditScopes, ", "))
+	}
+
 	// Validate optional fields if provided
 	if e.Start != nil && e.End != nil {
 		if err := util.ValidateTimeRange(*e.Start, *e.End, false); err != nil {
@@ -124,12 +355,26 @@ func (e *EventUpdateIntent) Validate() error {
 		}
 	}
 
+	if e.Transparency != nil {
+		if err := util.ValidateTransparency(*e.Transparency); err != nil {
+			return err
+		}
+	}
+
+	if err := util.ValidateSendUpdates(e.SendUpdates); err != nil {
+		return err
+	}
+
 	if len(e.Attendees) > 0 {
 		if err := util.ValidateEmails(e.Attendees); err != nil {
 			return err
 		}
 	}
 
+	if err := util.ValidateExtendedProperties(e.ExtendedProperties); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -137,13 +382,70 @@ func (e *EventUpdateIntent) Validate() error {
 func (e *EventUpdateIntent) HasChanges() bool {
 	return e.Summary != nil || e.Description != nil || e.Location != nil ||
 		e.Start != nil || e.End != nil || len(e.Attendees) > 0 ||
-		e.ColorID != nil || e.Visibility != nil || e.Reminders != nil
+		e.ColorID != nil || e.Visibility != nil || e.Reminders != nil ||
+		e.Transparency != nil || len(e.ExtendedProperties) > 0 ||
+		e.GuestsCanModify != nil || e.GuestsCanInviteOthers != nil || e.GuestsCanSeeOtherGuests != nil
+}
+
+// RequestIDProperty is the private extended property that records which
+// SchedLock request last created or updated an event.
+const RequestIDProperty = util.ReservedPropertyPrefix + "_request_id"
+
+// StampRequestID records the SchedLock request ID in the event's private
+// extended properties.
+func (e *EventIntent) StampRequestID(requestID string) {
+	e.ExtendedProperties = withRequestID(e.ExtendedProperties, requestID)
+}
+
+// StampRequestID records the SchedLock request ID in the event's private
+// extended properties.
+func (e *EventUpdateIntent) StampRequestID(requestID string) {
+	e.ExtendedProperties = withRequestID(e.ExtendedProperties, requestID)
+}
+
+// EventDefaults holds operator-configured values for created events.
+type EventDefaults struct {
+	DescriptionSuffix string     // appended to the description unless already present
+	Location          string     // used when the event has no location
+	ColorID           string     // used when the event has no color
+	Reminders         []Reminder // used when the event sets no reminders
+}
+
+// ApplyDefaults fills empty fields from d and appends the description
+// suffix. Fields the caller set are kept.
+func (e *EventIntent) ApplyDefaults(d EventDefaults) {
+	if suffix := strings.TrimSpace(d.DescriptionSuffix); suffix != "" && !strings.HasSuffix(strings.TrimSpace(e.Description), suffix) {
+		if e.Description == "" {
+			e.Description = suffix
+		} else {
+			e.Description = strings.TrimRight(e.Description, "\n") + "\n\n" + suffix
+		}
+	}
+	if e.Location == "" {
+		e.Location = d.Location
+	}
+	if e.ColorID == "" {
+		e.ColorID = d.ColorID
+	}
+	if e.Reminders == nil && len(d.Reminders) > 0 {
+		e.Reminders = &Reminders{Overrides: append([]Reminder(nil), d.Reminders...)}
+	}
+}
+
+func withRequestID(props map[string]string, requestID string) map[string]string {
+	stamped := make(map[string]string, len(props)+1)
+	for key, value := range props {
+		stamped[key] = value
+	}
+	stamped[RequestIDProperty] = requestID
+	return stamped
 }
 
 // EventDeleteIntent represents the schema for event deletion.
 type EventDeleteIntent struct {
-	CalendarID string `json:"calendarId"` // Required: "primary" or calendar ID
-	EventID    string `json:"eventId"`    // Required: Event to delete
+	CalendarID  string `json:"calendarId"`            // "primary" or calendar ID; the API fills in the key's default
+	EventID     string `json:"eventId"`               // Required: Event to delete
+	SendUpdates string `json:"sendUpdates,omitempty"` // Optional: who Google emails about the cancellation
 }
 
 // Validate checks if the EventDeleteIntent has all required fields.
@@ -159,9 +461,139 @@ func (e *EventDeleteIntent) Validate() error {
 		return fmt.Errorf("eventId is required")
 	}
 
+	if err := util.ValidateSendUpdates(e.SendUpdates); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EventMoveIntent represents the schema for moving an event to another calendar.
+type EventMoveIntent struct {
+	CalendarID            string `json:"calendarId"`            // Required: source calendar
+	EventID               string `json:"eventId"`               // Required: Event to move
+	DestinationCalendarID string `json:"destinationCalendarId"` // Required: target calendar
+}
+
+// Validate checks if the EventMoveIntent has all required fields.
+func (e *EventMoveIntent) Validate() error {
+	if e.CalendarID == "" {
+		return fmt.Errorf("calendarId is required")
+	}
+	if err := util.ValidateCalendarID(e.CalendarID); err != nil {
+		return err
+	}
+
+	if e.EventID == "" {
+		return fmt.Errorf("eventId is required")
+	}
+
+	if e.DestinationCalendarID == "" {
+		return fmt.Errorf("destinationCalendarId is required")
+	}
+	if err := util.ValidateCalendarID(e.DestinationCalendarID); err != nil {
+		return err
+	}
+
+	if e.DestinationCalendarID == e.CalendarID {
+		return fmt.Errorf("destinationCalendarId must differ from calendarId")
+	}
+
 	return nil
 }
 
+// MaxBatchEvents caps how many events one batch create request may insert.
+const MaxBatchEvents = 25
+
+// EventBatchIntent creates several events under a single approval. Callers
+// either list the events, or give one event plus the calendars to copy it to.
+type EventBatchIntent struct {
+	Events      []EventIntent `json:"events,omitempty"`
+	Event       *EventIntent  `json:"event,omitempty"`
+	CalendarIDs []string      `json:"calendarIds,omitempty"`
+}
+
+// Expand normalizes the batch into an explicit event list, copying Event to
+// each of CalendarIDs when that form is used.
+func (b *EventBatchIntent) Expand() error {
+	if b.Event != nil || len(b.CalendarIDs) > 0 {
+		if len(b.Events) > 0 {
+			return fmt.Errorf("use either events or event with calendarIds, not both")
+		}
+		if b.Event == nil {
+			return fmt.Errorf("event is required with calendarIds")
+		}
+		if len(b.CalendarIDs) == 0 {
+			return fmt.Errorf("calendarIds is required with event")
+		}
+		seen := make(map[string]bool, len(b.CalendarIDs))
+		for _, calendarID := range b.CalendarIDs {
+			if seen[calendarID] {
+				return fmt.Errorf("calendar %s is listed more than once", calendarID)
+			}
+			seen[calendarID] = true
+			event := *b.Event
+			event.CalendarID = calendarID
+			b.Events = append(b.Events, event)
+		}
+		b.Event = nil
+		b.CalendarIDs = nil
+	}
+	return nil
+}
+
+// Validate checks the expanded batch and every event in it.
+func (b *EventBatchIntent) Validate() error {
+	if len(b.Events) == 0 {
+		return fmt.Errorf("at least one event is required")
+	}
+	if len(b.Events) > MaxBatchEvents {
+		return fmt.Errorf("a batch may create at most %d events", MaxBatchEvents)
+	}
+	for i := range b.Events {
+		if err := b.Events[i].Validate(); err != nil {
+			return fmt.Errorf("events[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Sanitize cleans every event in the batch.
+func (b *EventBatchIntent) Sanitize(allowHTML bool) {
+	for i := range b.Events {
+		b.Events[i].Sanitize(allowHTML)
+	}
+}
+
+// TargetCalendars returns the distinct target calendars in request order.
+func (b *EventBatchIntent) TargetCalendars() []string {
+	var calendars []string
+	seen := make(map[string]bool)
+	for _, event := range b.Events {
+		if !seen[event.CalendarID] {
+			seen[event.CalendarID] = true
+			calendars = append(calendars, event.CalendarID)
+		}
+	}
+	return calendars
+}
+
+// BatchItemResult is the outcome of one insertion in a batch.
+type BatchItemResult struct {
+	CalendarID string `json:"calendarId"`
+	Summary    string `json:"summary"`
+	EventID    string `json:"eventId,omitempty"`
+	HtmlLink   string `json:"htmlLink,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// BatchResult aggregates the outcomes of a batch create.
+type BatchResult struct {
+	Results   []BatchItemResult `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
 // Diff represents the changes between two EventIntents for display.
 type Diff struct {
 	Field    string `json:"field"`
@@ -197,27 +629,138 @@ func GenerateDiff(existing *Event, update *EventUpdateIntent) []Diff {
 		})
 	}
 
-	if update.Start != nil && existing.Start != nil {
-		if !update.Start.Equal(existing.Start.DateTime) {
-			diffs = append(diffs, Diff{
-				Field:    "Start",
-				OldValue: existing.Start.DateTime.Format(time.RFC3339),
-				NewValue: update.Start.Format(time.RFC3339),
-			})
-		}
+	if update.Start != nil && (existing.Start == nil || !update.Start.Equal(existing.Start.DateTime)) {
+		diffs = append(diffs, Diff{
+			Field:    "Start",
+			OldValue: formatEventTime(existing.Start),
+			NewValue: util.GetDefaultFormatter().FormatDateTimeWithZone(*update.Start),
+		})
+	}
+
+	if update.End != nil && (existing.End == nil || !update.End.Equal(existing.End.DateTime)) {
+		diffs = append(diffs, Diff{
+			Field:    "End",
+			OldValue: formatEventTime(existing.End),
+			NewValue: util.GetDefaultFormatter().FormatDateTimeWithZone(*update.End),
+		})
 	}
 
-	if update.End != nil && existing.End != nil {
-		if !update.End.Equal(existing.End.DateTime) {
+	diffs = appendGuestPermissionDiff(diffs, "Guests can modify", existing.GuestsCanModify, update.GuestsCanModify)
+	diffs = appendGuestPermissionDiff(diffs, "Guests can invite others", existing.GuestsCanInviteOthers, update.GuestsCanInviteOthers)
+	diffs = appendGuestPermissionDiff(diffs, "Guests can see other guests", existing.GuestsCanSeeOtherGuests, update.GuestsCanSeeOtherGuests)
+
+	// Attendees are replaced wholesale, so compare as sets of addresses
+	if update.Attendees != nil {
+		var current []string
+		for _, attendee := range existing.Attendees {
+			current = append(current, attendee.Email)
+		}
+		if !sameAddresses(current, update.Attendees) {
 			diffs = append(diffs, Diff{
-				Field:    "End",
-				OldValue: existing.End.DateTime.Format(time.RFC3339),
-				NewValue: update.End.Format(time.RFC3339),
+				Field:    "Attendees",
+				OldValue: strings.Join(current, ", "),
+				NewValue: strings.Join(update.Attendees, ", "),
 			})
 		}
 	}
 
-	// TODO: Attendee diff comparison
-
 	return diffs
 }
+
+func appendGuestPermissionDiff(diffs []Diff, field string, current bool, update *bool) []Diff {
+	if update == nil || *update == current {
+		return diffs
+	}
+	return append(diffs, Diff{Field: field, OldValue: yesNo(current), NewValue: yesNo(*update)})
+}
+
+// yesNo renders a flag for approvers.
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
+
+// GuestPermission describes one guest permission for display.
+type GuestPermission struct {
+	Label string
+	Value bool
+}
+
+// GuestPermissions resolves the guest permissions a create intent will
+// give the event, filling unset flags with Google's defaults.
+func (e *EventIntent) GuestPermissions() []GuestPermission {
+	return []GuestPermission{
+		{Label: "Guests can modify", Value: boolOr(e.GuestsCanModify, DefaultGuestsCanModify)},
+		{Label: "Guests can invite others", Value: boolOr(e.GuestsCanInviteOthers, DefaultGuestsCanInviteOthers)},
+		{Label: "Guests can see other guests", Value: boolOr(e.GuestsCanSeeOtherGuests, DefaultGuestsCanSeeOtherGuests)},
+	}
+}
+
+// GuestPermissions lists only the guest permissions the update changes.
+func (e *EventUpdateIntent) GuestPermissions() []GuestPermission {
+	var perms []GuestPermission
+	if e.GuestsCanModify != nil {
+		perms = append(perms, GuestPermission{Label: "Guests can modify", Value: *e.GuestsCanModify})
+	}
+	if e.GuestsCanInviteOthers != nil {
+		perms = append(perms, GuestPermission{Label: "Guests can invite others", Value: *e.GuestsCanInviteOthers})
+	}
+	if e.GuestsCanSeeOtherGuests != nil {
+		perms = append(perms, GuestPermission{Label: "Guests can see other guests", Value: *e.GuestsCanSeeOtherGuests})
+	}
+	return perms
+}
+
+// Google's defaults for guest permissions on a new event.
+const (
+	DefaultGuestsCanModify         = false
+	DefaultGuestsCanInviteOthers   = true
+	DefaultGuestsCanSeeOtherGuests = true
+)
+
+func boolOr(value *bool, fallback bool) bool {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
+
+func boolPtr(v bool) *bool { return &v }
+
+// formatEventTime renders an existing event's start or end for a diff.
+// All-day events only carry a date.
+func formatEventTime(t *EventTime) string {
+	switch {
+	case t == nil:
+		return ""
+	case !t.DateTime.IsZero():
+		return util.GetDefaultFormatter().FormatDateTimeWithZone(t.DateTime)
+	default:
+		return t.Date
+	}
+}
+
+// sameAddresses reports whether two address lists hold the same addresses,
+// ignoring order and case.
+func sameAddresses(a, b []string) bool {
+	normalize := func(list []string) []string {
+		out := make([]string, 0, len(list))
+		for _, addr := range list {
+			out = append(out, strings.ToLower(strings.TrimSpace(addr)))
+		}
+		sort.Strings(out)
+		return out
+	}
+	x, y := normalize(a), normalize(b)
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
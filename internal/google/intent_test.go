@@ -0,0 +1,323 @@
+package google
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerateDiff(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	existing := &Event{
+		Summary:   "Standup",
+		Location:  "Room 1",
+		Start:     &EventTime{DateTime: start},
+		End:       &EventTime{DateTime: start.Add(30 * time.Minute)},
+		Attendees: []Attendee{{Email: "ana@example.com"}, {Email: "bo@example.com"}},
+	}
+
+	summary := "Standup"
+	location := "Room 2"
+	newStart := start.Add(time.Hour)
+	update := &EventUpdateIntent{
+		Summary:   &summary,
+		Location:  &location,
+		Start:     &newStart,
+		Attendees: []string{"BO@example.com", "ana@example.com"},
+	}
+
+	diffs := GenerateDiff(existing, update)
+	fields := map[string]Diff{}
+	for _, d := range diffs {
+		fields[d.Field] = d
+	}
+
+	if len(diffs) != 2 {
+		t.Fatalf("diffs = %+v, want Location and Start only", diffs)
+	}
+	if d := fields["Location"]; d.OldValue != "Room 1" || d.NewValue != "Room 2" {
+		t.Errorf("Location diff = %+v", d)
+	}
+	if d := fields["Start"]; d.OldValue == d.NewValue || d.NewValue == "" {
+		t.Errorf("Start diff = %+v", d)
+	}
+
+	update.Attendees = []string{"ana@example.com", "cy@example.com"}
+	var attendees *Diff
+	for _, d := range GenerateDiff(existing, update) {
+		if d.Field == "Attendees" {
+			d := d
+			attendees = &d
+		}
+	}
+	if attendees == nil || attendees.OldValue != "ana@example.com, bo@example.com" || attendees.NewValue != "ana@example.com, cy@example.com" {
+		t.Errorf("Attendees diff = %+v", attendees)
+	}
+}
+
+func TestGenerateDiffAllDayEvent(t *testing.T) {
+	existing := &Event{Start: &EventTime{Date: "2026-03-02"}}
+	newStart := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	diffs := GenerateDiff(existing, &EventUpdateIntent{Start: &newStart})
+	if len(diffs) != 1 || diffs[0].OldValue != "2026-03-02" {
+		t.Errorf("diffs = %+v, want the all-day date as the old value", diffs)
+	}
+}
+
+func TestGenerateDiffGuestPermissions(t *testing.T) {
+	existing := &Event{ID: "evt1", GuestsCanInviteOthers: true, GuestsCanSeeOtherGuests: true}
+	no, yes := false, true
+	update := &EventUpdateIntent{
+		EventID:                 "evt1",
+		GuestsCanModify:         &yes,
+		GuestsCanInviteOthers:   &no,
+		GuestsCanSeeOtherGuests: &yes, // unchanged
+	}
+
+	diffs := GenerateDiff(existing, update)
+	if len(diffs) != 2 {
+		t.Fatalf("got %d diffs, want 2: %+v", len(diffs), diffs)
+	}
+	if diffs[0] != (Diff{Field: "Guests can modify", OldValue: "No", NewValue: "Yes"}) {
+		t.Errorf("unexpected modify diff: %+v", diffs[0])
+	}
+	if diffs[1] != (Diff{Field: "Guests can invite others", OldValue: "Yes", NewValue: "No"}) {
+		t.Errorf("unexpected invite diff: %+v", diffs[1])
+	}
+}
+
+func TestGuestPermissions(t *testing.T) {
+	no := false
+	create := &EventIntent{GuestsCanSeeOtherGuests: &no}
+	got := create.GuestPermissions()
+	want := []GuestPermission{
+		{Label: "Guests can modify", Value: false},
+		{Label: "Guests can invite others", Value: true},
+		{Label: "Guests can see other guests", Value: false},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("permission %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	update := &EventUpdateIntent{GuestsCanInviteOthers: &no}
+	if perms := update.GuestPermissions(); len(perms) != 1 || perms[0].Label != "Guests can invite others" || perms[0].Value {
+		t.Errorf("update permissions = %+v", perms)
+	}
+	if !update.HasChanges() {
+		t.Error("a guest permission change should count as a change")
+	}
+}
+
+func TestSendUpdatesValidation(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	intent := &EventIntent{CalendarID: "primary", Summary: "Sync", Start: start, End: start.Add(time.Hour), SendUpdates: "externalOnly"}
+	if err := intent.Validate(); err != nil {
+		t.Errorf("externalOnly should be valid: %v", err)
+	}
+	intent.SendUpdates = "everyone"
+	if err := intent.Validate(); err == nil {
+		t.Error("expected invalid sendUpdates to be rejected")
+	}
+
+	del := &EventDeleteIntent{CalendarID: "primary", EventID: "evt1", SendUpdates: "bogus"}
+	if err := del.Validate(); err == nil {
+		t.Error("expected invalid sendUpdates on delete to be rejected")
+	}
+
+	err := DeleteEventSchema.ValidateJSON([]byte(`{"eventId":"evt1","sendUpdates":"everyone"}`))
+	if _, ok := err.(*SchemaError); !ok {
+		t.Errorf("expected schema error, got %v", err)
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	defaults := EventDefaults{
+		DescriptionSuffix: "Booked via SchedLock",
+		Location:          "HQ",
+		ColorID:           "2",
+		Reminders:         []Reminder{{Method: "popup", Minutes: 10}},
+	}
+
+	empty := &EventIntent{Summary: "Sync"}
+	empty.ApplyDefaults(defaults)
+	if empty.Description != "Booked via SchedLock" || empty.Location != "HQ" || empty.ColorID != "2" {
+		t.Errorf("empty intent after defaults = %+v", empty)
+	}
+	if empty.Reminders == nil || empty.Reminders.UseDefault || len(empty.Reminders.Overrides) != 1 {
+		t.Fatalf("reminders = %+v, want one override", empty.Reminders)
+	}
+	// The intent gets its own copy of the reminder list
+	empty.Reminders.Overrides[0].Minutes = 99
+	if defaults.Reminders[0].Minutes != 10 {
+		t.Error("ApplyDefaults shared the defaults' reminder slice")
+	}
+
+	set := &EventIntent{
+		Summary:     "Sync",
+		Description: "Agenda\n",
+		Location:    "Room 4",
+		ColorID:     "9",
+		Reminders:   &Reminders{UseDefault: true},
+	}
+	set.ApplyDefaults(defaults)
+	if set.Description != "Agenda\n\nBooked via SchedLock" {
+		t.Errorf("description = %q", set.Description)
+	}
+	if set.Location != "Room 4" || set.ColorID != "9" || !set.Reminders.UseDefault {
+		t.Errorf("caller-set fields were overwritten: %+v", set)
+	}
+
+	// Applying twice, as on a retried execution, does not repeat the suffix
+	set.ApplyDefaults(defaults)
+	if set.Description != "Agenda\n\nBooked via SchedLock" {
+		t.Errorf("description after second apply = %q", set.Description)
+	}
+}
+
+func TestIntentFromEventSeparatesRooms(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	event := &Event{
+		Summary: "Planning",
+		Start:   &EventTime{DateTime: start},
+		End:     &EventTime{DateTime: start.Add(time.Hour)},
+		Attendees: []Attendee{
+			{Email: "me@example.com", Self: true},
+			{Email: "ana@example.com"},
+			{Email: "room-a@resource.calendar.google.com", Resource: true},
+		},
+	}
+	intent, err := IntentFromEvent(event, "primary")
+	if err != nil {
+		t.Fatalf("IntentFromEvent: %v", err)
+	}
+	if len(intent.Attendees) != 1 || intent.Attendees[0] != "ana@example.com" {
+		t.Errorf("attendees = %v", intent.Attendees)
+	}
+	if len(intent.Rooms) != 1 || intent.Rooms[0] != "room-a@resource.calendar.google.com" {
+		t.Errorf("rooms = %v", intent.Rooms)
+	}
+
+	intent.Rooms = []string{"not a room"}
+	if err := intent.Validate(); err == nil {
+		t.Error("expected an invalid room address to be rejected")
+	}
+}
+
+func TestOptionalAttendees(t *testing.T) {
+	start := time.Date(2030, 3, 2, 9, 0, 0, 0, time.UTC)
+	event := &Event{
+		Summary: "Review",
+		Start:   &EventTime{DateTime: start},
+		End:     &EventTime{DateTime: start.Add(time.Hour)},
+		Attendees: []Attendee{
+			{Email: "ana@example.com"},
+			{Email: "ben@example.com", Optional: true},
+		},
+	}
+	intent, err := IntentFromEvent(event, "primary")
+	if err != nil {
+		t.Fatalf("IntentFromEvent: %v", err)
+	}
+	if len(intent.Attendees) != 1 || intent.Attendees[0] != "ana@example.com" {
+		t.Errorf("attendees = %v", intent.Attendees)
+	}
+	if len(intent.OptionalAttendees) != 1 || intent.OptionalAttendees[0] != "ben@example.com" {
+		t.Errorf("optional attendees = %v", intent.OptionalAttendees)
+	}
+	if all := intent.AllAttendees(); len(all) != 2 {
+		t.Errorf("AllAttendees() = %v, want both lists", all)
+	}
+	if err := intent.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	intent.OptionalAttendees = []string{"ANA@example.com"}
+	if err := intent.Validate(); err == nil {
+		t.Error("expected an address in both lists to be rejected")
+	}
+	intent.OptionalAttendees = []string{"not an address"}
+	if err := intent.Validate(); err == nil {
+		t.Error("expected an invalid optional attendee to be rejected")
+	}
+}
+
+func TestEventIntentDurationMinutes(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+
+	tests := []struct {
+		name     string
+		end      time.Time
+		duration int
+		wantEnd  time.Time
+		wantErr  bool
+	}{
+		{name: "duration only", duration: 45, wantEnd: start.Add(45 * time.Minute)},
+		{name: "end only", end: start.Add(time.Hour), wantEnd: start.Add(time.Hour)},
+		{name: "matching end and duration", end: start.Add(30 * time.Minute), duration: 30, wantEnd: start.Add(30 * time.Minute)},
+		{name: "conflicting end and duration", end: start.Add(time.Hour), duration: 30, wantErr: true},
+		{name: "negative duration", duration: -15, wantErr: true},
+		{name: "neither", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intent := &EventIntent{CalendarID: "primary", Summary: "Sync", Start: start, End: tt.end, DurationMinutes: tt.duration}
+			err := intent.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !intent.End.Equal(tt.wantEnd) {
+				t.Errorf("End = %v, want %v", intent.End, tt.wantEnd)
+			}
+			if intent.DurationMinutes != 0 {
+				t.Errorf("DurationMinutes = %d, want it cleared once End is set", intent.DurationMinutes)
+			}
+		})
+	}
+
+	if err := CreateEventSchema.ValidateJSON([]byte(`{"summary":"Sync","start":"2030-01-01T10:00:00Z","durationMinutes":30}`)); err != nil {
+		t.Errorf("expected schema to accept durationMinutes without end: %v", err)
+	}
+	if err := CreateEventSchema.ValidateJSON([]byte(`{"summary":"Sync","start":"2030-01-01T10:00:00Z","durationMinutes":0}`)); err == nil {
+		t.Error("expected schema to reject a zero duration")
+	}
+}
+
+func TestRecurringEditScopeValidation(t *testing.T) {
+	tests := []struct {
+		scope   string
+		eventID string
+		wantErr bool
+		target  string
+	}{
+		{"", "abc123", false, "abc123"},
+		{"", "abc123_20260301T150000Z", false, "abc123_20260301T150000Z"},
+		{"instance", "abc123_20260301T150000Z", false, "abc123_20260301T150000Z"},
+		{"instance", "abc123_20260301", false, "abc123_20260301"},
+		{"following", "abc123_20260301T150000Z", false, "abc123_20260301T150000Z"},
+		{"all", "abc123_20260301T150000Z", false, "abc123"},
+		{"all", "abc123", false, "abc123"},
+		{"instance", "abc123", true, ""},
+		{"following", "abc123_20261301", true, ""},
+		{"this", "abc123_20260301", true, ""},
+	}
+	for _, tt := range tests {
+		intent := &EventUpdateIntent{CalendarID: "primary", EventID: tt.eventID, RecurringEditScope: tt.scope}
+		err := intent.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s/%s: Validate() error = %v, wantErr %v", tt.scope, tt.eventID, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && intent.TargetEventID() != tt.target {
+			t.Errorf("%s/%s: TargetEventID() = %q, want %q", tt.scope, tt.eventID, intent.TargetEventID(), tt.target)
+		}
+	}
+}
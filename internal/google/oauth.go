@@ -35,6 +35,13 @@ type OAuthManager struct {
 	// In-memory token cache
 	cachedToken *oauth2.Token
 	cacheExpiry time.Time
+
+	// Refresh health, guarded separately so Health never waits on a refresh
+	healthMu    sync.Mutex
+	health      OAuthHealth
+	refreshedAt time.Time
+	alerter     OAuthAlerter
+	lastAlertAt time.Time
 }
 
 // NewOAuthManager creates a new OAuth manager.
@@ -223,6 +230,7 @@ func (m *OAuthManager) ExchangeCode(ctx context.Context, code string) error {
 	m.cachedToken = token
 	m.cacheExpiry = token.Expiry
 	m.mu.Unlock()
+	m.markHealthy()
 
 	util.Info("Google OAuth token saved successfully")
 	return nil
@@ -265,8 +273,10 @@ func (m *OAuthManager) GetValidToken(ctx context.Context) (*oauth2.Token, error)
 		if err != nil {
 			// Log the failure - this is critical
 			util.Error("OAuth token refresh failed", "error", err)
+			m.markRefreshFailed(err)
 			return nil, fmt.Errorf("token refresh failed: %w", err)
 		}
+		m.markRefreshed()
 		if newToken.RefreshToken == "" {
 			newToken.RefreshToken = token.RefreshToken
 		}
@@ -393,6 +403,7 @@ func (m *OAuthManager) DeleteToken(ctx context.Context) error {
 	m.cachedToken = nil
 	m.cacheExpiry = time.Time{}
 	m.mu.Unlock()
+	m.markHealthy()
 
 	_, err := m.db.ExecContext(ctx, `DELETE FROM oauth_tokens WHERE id = 'primary'`)
 	return err
@@ -0,0 +1,111 @@
+package google
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/dtorcivia/schedlock/internal/notifications"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+// oauthAlertInterval is the minimum time between repeated alerts while the
+// token keeps failing to refresh.
+const oauthAlertInterval = time.Hour
+
+// OAuthAlerter delivers admin alerts. *notifications.Manager satisfies it.
+type OAuthAlerter interface {
+	SendAlert(ctx context.Context, alert *notifications.AlertNotification) error
+}
+
+// OAuthHealth reports whether the most recent token refresh succeeded.
+type OAuthHealth struct {
+	Unhealthy bool
+	LastError string
+	FailedAt  time.Time
+
+	// RefreshedAt is the last successful refresh since startup.
+	RefreshedAt time.Time
+}
+
+// SetAlerter sets where refresh failure alerts are sent.
+func (m *OAuthManager) SetAlerter(alerter OAuthAlerter) {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	m.alerter = alerter
+}
+
+// Health returns the current OAuth health. It does not wait for a refresh
+// in progress.
+func (m *OAuthManager) Health() OAuthHealth {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	health := m.health
+	health.RefreshedAt = m.refreshedAt
+	return health
+}
+
+// markRefreshFailed records a failed refresh and alerts the admin unless an
+// alert went out within oauthAlertInterval.
+func (m *OAuthManager) markRefreshFailed(err error) {
+	message := refreshFailureMessage(err)
+
+	m.healthMu.Lock()
+	m.health = OAuthHealth{Unhealthy: true, LastError: message, FailedAt: time.Now()}
+	alerter := m.alerter
+	send := alerter != nil && time.Since(m.lastAlertAt) >= oauthAlertInterval
+	if send {
+		m.lastAlertAt = time.Now()
+	}
+	m.healthMu.Unlock()
+
+	if !send {
+		return
+	}
+
+	alert := &notifications.AlertNotification{
+		Kind:    notifications.AlertOAuthUnhealthy,
+		Title:   "SchedLock: Google Calendar disconnected",
+		Message: message + " Approved requests cannot be executed until Google Calendar is reconnected.",
+		URL:     strings.TrimRight(m.baseURL, "/") + "/oauth/start",
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := alerter.SendAlert(ctx, alert); err != nil {
+			util.Error("Failed to send OAuth alert", "error", err)
+		}
+	}()
+}
+
+// markHealthy clears the unhealthy flag after a successful refresh or a new
+// authorization, so the next failure alerts immediately.
+func (m *OAuthManager) markHealthy() {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	if m.health.Unhealthy {
+		util.Info("Google OAuth recovered")
+	}
+	m.health = OAuthHealth{}
+	m.lastAlertAt = time.Time{}
+}
+
+// markRefreshed records a successful token refresh.
+func (m *OAuthManager) markRefreshed() {
+	m.markHealthy()
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	m.refreshedAt = time.Now()
+}
+
+// refreshFailureMessage explains a refresh error in terms an admin can act on.
+func refreshFailureMessage(err error) string {
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "invalid_grant" {
+		return "Google rejected the refresh token; it was revoked or has expired."
+	}
+	return "Refreshing the Google OAuth token failed: " + err.Error() + "."
+}
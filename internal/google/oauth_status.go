@@ -0,0 +1,84 @@
+package google
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+// Last refresh outcomes reported by TokenStatus.
+const (
+	RefreshSucceeded = "succeeded"
+	RefreshFailed    = "failed"
+	RefreshNone      = "none" // no refresh since startup
+)
+
+// OAuthTokenStatus describes the stored OAuth token without revealing it.
+type OAuthTokenStatus struct {
+	Connected bool       `json:"connected"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+	// LastRefresh is RefreshSucceeded, RefreshFailed or RefreshNone. It is
+	// kept in memory, so it starts over at RefreshNone on restart.
+	LastRefresh      string     `json:"last_refresh"`
+	LastRefreshAt    *time.Time `json:"last_refresh_at,omitempty"`
+	LastRefreshError string     `json:"last_refresh_error,omitempty"`
+}
+
+// TokenStatus reports whether a token is stored, its scopes, when it was
+// first stored and last saved, and the outcome of the last refresh.
+// UpdatedAt moves on every refresh that saves the token.
+func (m *OAuthManager) TokenStatus(ctx context.Context) (*OAuthTokenStatus, error) {
+	var scopes, createdAt, updatedAt sql.NullString
+	err := m.db.QueryRowContext(ctx, `
+		SELECT scopes, created_at, updated_at
+		FROM oauth_tokens
+		WHERE id = 'primary'
+	`).Scan(&scopes, &createdAt, &updatedAt)
+
+	status := &OAuthTokenStatus{Scopes: []string{}}
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return nil, fmt.Errorf("database error: %w", err)
+	default:
+		status.Connected = true
+		status.Scopes = append(status.Scopes, strings.Fields(scopes.String)...)
+		status.CreatedAt = parseTokenTime(createdAt)
+		status.UpdatedAt = parseTokenTime(updatedAt)
+	}
+
+	health := m.Health()
+	switch {
+	case health.Unhealthy:
+		status.LastRefresh = RefreshFailed
+		failedAt := health.FailedAt
+		status.LastRefreshAt = &failedAt
+		status.LastRefreshError = health.LastError
+	case !health.RefreshedAt.IsZero():
+		status.LastRefresh = RefreshSucceeded
+		refreshedAt := health.RefreshedAt
+		status.LastRefreshAt = &refreshedAt
+	default:
+		status.LastRefresh = RefreshNone
+	}
+	return status, nil
+}
+
+// parseTokenTime parses an oauth_tokens timestamp, or returns nil.
+func parseTokenTime(value sql.NullString) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t, err := util.ParseSQLiteTimestamp(value.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,72 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/crypto"
+	"github.com/dtorcivia/schedlock/internal/database"
+)
+
+func TestTokenStatus(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	encryptor, err := crypto.NewEncryptor("test-encryption-key")
+	if err != nil {
+		t.Fatalf("encryptor: %v", err)
+	}
+
+	ctx := context.Background()
+	m := NewOAuthManager(&config.Config{}, db, encryptor)
+
+	status, err := m.TokenStatus(ctx)
+	if err != nil {
+		t.Fatalf("status without token: %v", err)
+	}
+	if status.Connected || len(status.Scopes) != 0 || status.LastRefresh != RefreshNone {
+		t.Fatalf("expected no token, got %+v", status)
+	}
+
+	token := (&oauth2.Token{RefreshToken: "secret-refresh-token"}).WithExtra(map[string]interface{}{
+		"scope": "https://www.googleapis.com/auth/calendar openid",
+	})
+	if err := m.saveToken(ctx, token); err != nil {
+		t.Fatalf("save token: %v", err)
+	}
+	m.markRefreshed()
+
+	status, err = m.TokenStatus(ctx)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !status.Connected || len(status.Scopes) != 2 || status.Scopes[0] != "https://www.googleapis.com/auth/calendar" {
+		t.Fatalf("expected stored scopes, got %+v", status)
+	}
+	if status.CreatedAt == nil || status.UpdatedAt == nil {
+		t.Fatalf("expected timestamps, got %+v", status)
+	}
+	if status.LastRefresh != RefreshSucceeded || status.LastRefreshAt == nil {
+		t.Fatalf("expected a successful refresh, got %+v", status)
+	}
+
+	body, _ := json.Marshal(status)
+	if strings.Contains(string(body), "secret-refresh-token") {
+		t.Fatalf("status leaked the token: %s", body)
+	}
+
+	m.markRefreshFailed(errors.New("network down"))
+	status, _ = m.TokenStatus(ctx)
+	if status.LastRefresh != RefreshFailed || !strings.Contains(status.LastRefreshError, "network down") {
+		t.Fatalf("expected a failed refresh, got %+v", status)
+	}
+}
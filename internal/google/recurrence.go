@@ -0,0 +1,67 @@
+package google
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// endRecurrenceBefore rewrites a series' recurrence rules to end just before
+// the occurrence originally starting at split. Rules limited by COUNT are
+// refused: the occurrences left for the new series cannot be known from the
+// rule alone.
+func endRecurrenceBefore(rules []string, split *calendar.EventDateTime) ([]string, error) {
+	until, err := untilBefore(split)
+	if err != nil {
+		return nil, err
+	}
+
+	ended := make([]string, 0, len(rules))
+	found := false
+	for _, rule := range rules {
+		if !strings.HasPrefix(strings.ToUpper(rule), "RRULE:") {
+			ended = append(ended, rule)
+			continue
+		}
+		found = true
+		parts := []string{}
+		for _, part := range strings.Split(rule[len("RRULE:"):], ";") {
+			name, _, _ := strings.Cut(strings.ToUpper(part), "=")
+			switch name {
+			case "COUNT":
+				return nil, fmt.Errorf("a recurring event limited to a number of occurrences cannot be split; edit this occurrence or all of them")
+			case "UNTIL":
+				continue
+			}
+			parts = append(parts, part)
+		}
+		ended = append(ended, "RRULE:"+strings.Join(append(parts, "UNTIL="+until), ";"))
+	}
+	if !found {
+		return nil, fmt.Errorf("the recurring event has no recurrence rule")
+	}
+	return ended, nil
+}
+
+// untilBefore returns the RRULE UNTIL value for the moment before an
+// occurrence: the previous day for all-day events, otherwise one second
+// earlier in UTC.
+func untilBefore(split *calendar.EventDateTime) (string, error) {
+	if split == nil {
+		return "", fmt.Errorf("the occurrence has no original start time")
+	}
+	if split.Date != "" {
+		day, err := time.Parse("2006-01-02", split.Date)
+		if err != nil {
+			return "", fmt.Errorf("invalid occurrence date %q: %w", split.Date, err)
+		}
+		return day.AddDate(0, 0, -1).Format("20060102"), nil
+	}
+	start, err := time.Parse(time.RFC3339, split.DateTime)
+	if err != nil {
+		return "", fmt.Errorf("invalid occurrence start %q: %w", split.DateTime, err)
+	}
+	return start.Add(-time.Second).UTC().Format("20060102T150405Z"), nil
+}
@@ -0,0 +1,36 @@
+package google
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestEndRecurrenceBefore(t *testing.T) {
+	rules := []string{"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20261231T000000Z", "EXDATE;TZID=Europe/Berlin:20260309T090000"}
+
+	got, err := endRecurrenceBefore(rules, &calendar.EventDateTime{DateTime: "2026-03-16T09:00:00+01:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20260316T075959Z", "EXDATE;TZID=Europe/Berlin:20260309T090000"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	got, err = endRecurrenceBefore([]string{"RRULE:FREQ=DAILY"}, &calendar.EventDateTime{Date: "2026-03-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"RRULE:FREQ=DAILY;UNTIL=20260228"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("all-day: got %v, want %v", got, want)
+	}
+
+	if _, err := endRecurrenceBefore([]string{"RRULE:FREQ=DAILY;COUNT=10"}, &calendar.EventDateTime{Date: "2026-03-01"}); err == nil {
+		t.Fatal("expected a COUNT-limited series to be refused")
+	}
+	if _, err := endRecurrenceBefore(nil, &calendar.EventDateTime{Date: "2026-03-01"}); err == nil {
+		t.Fatal("expected an event without a rule to be refused")
+	}
+}
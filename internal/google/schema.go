@@ -0,0 +1,321 @@
+package google
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+// Schema is the subset of JSON Schema used to check request payloads before
+// they are decoded. It marshals to standard JSON Schema; "calendar-id" is a
+// custom format. Unknown properties are allowed, matching how intents
+// ignore fields they do not know.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	MinLength            int                `json:"minLength,omitempty"`
+	MaxLength            int                `json:"maxLength,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Maximum              *int               `json:"maximum,omitempty"`
+	MaxItems             int                `json:"maxItems,omitempty"`
+	MaxProperties        int                `json:"maxProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"` // schema for every value of a map
+}
+
+// FieldError is one schema violation, e.g. field "attendees[2]".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// SchemaError lists every violation found in a payload.
+type SchemaError struct {
+	Errors []FieldError
+}
+
+func (e *SchemaError) Error() string {
+	first := e.Errors[0]
+	msg := first.Message
+	if first.Field != "" {
+		msg = first.Field + " " + msg
+	}
+	if len(e.Errors) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(e.Errors)-1)
+	}
+	return msg
+}
+
+// ValidateJSON checks a raw JSON document against the schema. It returns a
+// *SchemaError for violations and a plain error for malformed JSON.
+func (s *Schema) ValidateJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	var errs []FieldError
+	s.validate(value, "", &errs)
+	if len(errs) > 0 {
+		return &SchemaError{Errors: errs}
+	}
+	return nil
+}
+
+func (s *Schema) validate(value interface{}, path string, errs *[]FieldError) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			fail("must be an object")
+			return
+		}
+		for _, name := range s.Required {
+			if v, present := obj[name]; !present || v == nil {
+				*errs = append(*errs, FieldError{Field: joinPath(path, name), Message: "is required"})
+			}
+		}
+		if s.MaxProperties > 0 && len(obj) > s.MaxProperties {
+			fail("must have at most %d entries", s.MaxProperties)
+		}
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			// Null means absent; required fields were reported above
+			if obj[key] == nil {
+				continue
+			}
+			if prop := s.Properties[key]; prop != nil {
+				prop.validate(obj[key], joinPath(path, key), errs)
+			} else if s.AdditionalProperties != nil {
+				s.AdditionalProperties.validate(obj[key], joinPath(path, key), errs)
+			}
+		}
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			fail("must be an array")
+			return
+		}
+		if s.MaxItems > 0 && len(items) > s.MaxItems {
+			fail("must have at most %d items", s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+		if len(str) < s.MinLength {
+			if s.MinLength == 1 {
+				fail("must not be empty")
+			} else {
+				fail("must be at least %d characters", s.MinLength)
+			}
+			return
+		}
+		if s.MaxLength > 0 && len(str) > s.MaxLength {
+			fail("must be at most %d characters", s.MaxLength)
+			return
+		}
+		if len(s.Enum) > 0 && !containsString(s.Enum, str) {
+			fail("must be one of %s", strings.Join(s.Enum, ", "))
+			return
+		}
+		if msg := checkFormat(s.Format, str); msg != "" {
+			fail("%s", msg)
+		}
+
+	case "integer":
+		num, ok := value.(json.Number)
+		if !ok {
+			fail("must be an integer")
+			return
+		}
+		f, err := num.Float64()
+		if err != nil || f != math.Trunc(f) {
+			fail("must be an integer")
+			return
+		}
+		if s.Minimum != nil && f < float64(*s.Minimum) {
+			fail("must be at least %d", *s.Minimum)
+		}
+		if s.Maximum != nil && f > float64(*s.Maximum) {
+			fail("must be at most %d", *s.Maximum)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("must be true or false")
+		}
+	}
+}
+
+// checkFormat returns a message when str does not match the named format.
+func checkFormat(format, str string) string {
+	switch format {
+	case "email":
+		if util.ValidateEmail(str) != nil {
+			return "is not a valid email address"
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			return "must be an RFC3339 timestamp with a timezone, e.g. 2024-01-15T10:00:00-05:00"
+		}
+	case "calendar-id":
+		if util.ValidateCalendarID(str) != nil {
+			return `must be "primary" or a calendar ID`
+		}
+	}
+	return ""
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func intPtr(v int) *int { return &v }
+
+// Payload schemas for each write operation.
+var (
+	CreateEventSchema = eventSchema(true)
+	UpdateEventSchema = eventSchema(false)
+	DeleteEventSchema = &Schema{
+		Type:     "object",
+		Required: []string{"eventId"},
+		Properties: map[string]*Schema{
+			"calendarId":  {Type: "string", Format: "calendar-id"},
+			"eventId":     {Type: "string", MinLength: 1},
+			"sendUpdates": sendUpdatesSchema,
+		},
+	}
+	// The source calendar and event come from the URL path
+	MoveEventSchema = &Schema{
+		Type:     "object",
+		Required: []string{"destinationCalendarId"},
+		Properties: map[string]*Schema{
+			"destinationCalendarId": {Type: "string", Format: "calendar-id"},
+		},
+	}
+	BatchEventSchema = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"events":      {Type: "array", MaxItems: MaxBatchEvents, Items: CreateEventSchema},
+			"event":       CreateEventSchema,
+			"calendarIds": {Type: "array", MaxItems: MaxBatchEvents, Items: &Schema{Type: "string", Format: "calendar-id"}},
+		},
+	}
+)
+
+var sendUpdatesSchema = &Schema{Type: "string", Enum: []string{"all", "externalOnly", "none"}}
+
+// eventSchema describes a create intent, or with create false an update
+// intent. Semantic rules such as end after start stay in Validate.
+func eventSchema(create bool) *Schema {
+	s := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"calendarId":   {Type: "string", Format: "calendar-id"},
+			"summary":      {Type: "string"},
+			"description":  {Type: "string"},
+			"location":     {Type: "string"},
+			"start":        {Type: "string", Format: "date-time"},
+			"end":          {Type: "string", Format: "date-time"},
+			"attendees":    {Type: "array", Items: &Schema{Type: "string", Format: "email"}},
+			"colorId":      {Type: "string", Enum: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}},
+			"visibility":   {Type: "string", Enum: []string{"default", "public", "private"}},
+			"transparency": {Type: "string", Enum: []string{"opaque", "transparent"}},
+
+			"sendUpdates":             sendUpdatesSchema,
+			"guestsCanModify":         {Type: "boolean"},
+			"guestsCanInviteOthers":   {Type: "boolean"},
+			"guestsCanSeeOtherGuests": {Type: "boolean"},
+			"reminders": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"useDefault": {Type: "boolean"},
+					"overrides": {
+						Type: "array",
+						Items: &Schema{
+							Type:     "object",
+							Required: []string{"method", "minutes"},
+							Properties: map[string]*Schema{
+								"method":  {Type: "string", Enum: []string{"email", "popup"}},
+								"minutes": {Type: "integer", Minimum: intPtr(0), Maximum: intPtr(40320)},
+							},
+						},
+					},
+				},
+			},
+			"extendedProperties": {
+				Type:                 "object",
+				MaxProperties:        util.MaxExtendedProperties,
+				AdditionalProperties: &Schema{Type: "string", MaxLength: util.MaxExtendedPropertyValue},
+			},
+		},
+	}
+	if create {
+		// end may be replaced by durationMinutes; Validate requires one of them
+		s.Required = []string{"summary", "start"}
+		s.Properties["summary"].MinLength = 1
+		s.Properties["durationMinutes"] = &Schema{Type: "integer", Minimum: intPtr(1)}
+		s.Properties["optionalAttendees"] = &Schema{Type: "array", Items: &Schema{Type: "string", Format: "email"}}
+	} else {
+		s.Required = []string{"eventId"}
+		s.Properties["eventId"] = &Schema{Type: "string", MinLength: 1}
+		s.Properties["recurringEditScope"] = &Schema{Type: "string", Enum: RecurringEditScopes}
+	}
+	return s
+}
+
+// Schema returns the payload schema for create requests.
+func (e *EventIntent) Schema() *Schema { return CreateEventSchema }
+
+// Schema returns the payload schema for update requests.
+func (e *EventUpdateIntent) Schema() *Schema { return UpdateEventSchema }
+
+// Schema returns the payload schema for delete requests.
+func (e *EventDeleteIntent) Schema() *Schema { return DeleteEventSchema }
+
+// Schema returns the payload schema for move requests.
+func (e *EventMoveIntent) Schema() *Schema { return MoveEventSchema }
+
+// Schema returns the payload schema for batch create requests.
+func (b *EventBatchIntent) Schema() *Schema { return BatchEventSchema }
@@ -34,6 +34,13 @@ type Event struct {
 	Visibility   string     `json:"visibility,omitempty"`
 	Transparency string     `json:"transparency,omitempty"`
 	Reminders    *Reminders `json:"reminders,omitempty"`
+
+	ExtendedProperties map[string]string `json:"extendedProperties,omitempty"` // private properties only
+
+	// Guest permissions with Google's defaults filled in
+	GuestsCanModify         bool `json:"guestsCanModify"`
+	GuestsCanInviteOthers   bool `json:"guestsCanInviteOthers"`
+	GuestsCanSeeOtherGuests bool `json:"guestsCanSeeOtherGuests"`
 }
 
 // EventTime represents a time with optional date-only and timezone.
@@ -51,6 +58,7 @@ type Attendee struct {
 	Optional       bool   `json:"optional,omitempty"`
 	Organizer      bool   `json:"organizer,omitempty"`
 	Self           bool   `json:"self,omitempty"`
+	Resource       bool   `json:"resource,omitempty"` // a room or other bookable resource
 }
 
 // Person represents a person (creator/organizer).
@@ -92,9 +100,9 @@ type EventListResponse struct {
 
 // FreeBusyRequest represents a free/busy query request.
 type FreeBusyRequest struct {
-	TimeMin time.Time           `json:"timeMin"`
-	TimeMax time.Time           `json:"timeMax"`
-	Items   []FreeBusyCalendar  `json:"items"`
+	TimeMin time.Time          `json:"timeMin"`
+	TimeMax time.Time          `json:"timeMax"`
+	Items   []FreeBusyCalendar `json:"items"`
 }
 
 // FreeBusyCalendar identifies a calendar in a free/busy query.
@@ -104,8 +112,8 @@ type FreeBusyCalendar struct {
 
 // FreeBusyResponse represents the response from a free/busy query.
 type FreeBusyResponse struct {
-	TimeMin   time.Time                    `json:"timeMin"`
-	TimeMax   time.Time                    `json:"timeMax"`
+	TimeMin   time.Time                       `json:"timeMin"`
+	TimeMax   time.Time                       `json:"timeMax"`
 	Calendars map[string]FreeBusyCalendarInfo `json:"calendars"`
 }
 
@@ -32,14 +32,20 @@ type NtfyCredentials struct {
 	Token          string `json:"token,omitempty"`
 	Priority       string `json:"priority,omitempty"`
 	MinimalContent bool   `json:"minimal_content,omitempty"`
+	MinPriority    string `json:"min_priority,omitempty"`
+
+	MessageTemplate string `json:"message_template,omitempty"` // custom approval message, see TemplateData
 }
 
 // PushoverCredentials holds Pushover provider credentials.
 type PushoverCredentials struct {
-	AppToken string `json:"app_token"`
-	UserKey  string `json:"user_key"`
-	Priority int    `json:"priority,omitempty"`
-	Sound    string `json:"sound,omitempty"`
+	AppToken    string `json:"app_token"`
+	UserKey     string `json:"user_key"`
+	Priority    int    `json:"priority,omitempty"`
+	Sound       string `json:"sound,omitempty"`
+	MinPriority string `json:"min_priority,omitempty"`
+
+	MessageTemplate string `json:"message_template,omitempty"` // custom approval message, see TemplateData
 }
 
 // TelegramCredentials holds Telegram provider credentials.
@@ -47,6 +53,9 @@ type TelegramCredentials struct {
 	BotToken      string `json:"bot_token"`
 	ChatID        string `json:"chat_id"`
 	WebhookSecret string `json:"webhook_secret,omitempty"`
+	MinPriority   string `json:"min_priority,omitempty"`
+
+	MessageTemplate string `json:"message_template,omitempty"` // custom approval message, see TemplateData
 }
 
 // GoogleOAuthCredentials holds Google OAuth client credentials.
@@ -60,6 +69,7 @@ type WebhookCredentials struct {
 	URL            string `json:"url"`
 	Secret         string `json:"secret,omitempty"`
 	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+	MinPriority    string `json:"min_priority,omitempty"`
 }
 
 // ProviderCredentials holds the enabled state and credentials for a provider.
@@ -69,6 +79,36 @@ type ProviderCredentials struct {
 	Credentials interface{} // NtfyCredentials, PushoverCredentials, or TelegramCredentials
 }
 
+// MessageTemplate returns the provider's custom approval message template.
+// Empty means the built-in message.
+func (pc *ProviderCredentials) MessageTemplate() string {
+	switch c := pc.Credentials.(type) {
+	case *NtfyCredentials:
+		return c.MessageTemplate
+	case *PushoverCredentials:
+		return c.MessageTemplate
+	case *TelegramCredentials:
+		return c.MessageTemplate
+	}
+	return ""
+}
+
+// MinPriority returns the lowest request priority the provider should be
+// notified about. Empty means every request.
+func (pc *ProviderCredentials) MinPriority() string {
+	switch c := pc.Credentials.(type) {
+	case *NtfyCredentials:
+		return c.MinPriority
+	case *PushoverCredentials:
+		return c.MinPriority
+	case *TelegramCredentials:
+		return c.MinPriority
+	case *WebhookCredentials:
+		return c.MinPriority
+	}
+	return ""
+}
+
 // Save stores encrypted credentials for a provider.
 func (s *CredentialsStore) Save(ctx context.Context, provider string, enabled bool, credentials interface{}) error {
 	credJSON, err := json.Marshal(credentials)
@@ -98,6 +138,23 @@ func (s *CredentialsStore) Save(ctx context.Context, provider string, enabled bo
 	return err
 }
 
+// SetEnabled turns a provider on or off, keeping its stored credentials so
+// it can be re-enabled without entering them again. Providers without
+// stored credentials are left alone.
+func (s *CredentialsStore) SetEnabled(ctx context.Context, provider string, enabled bool) error {
+	enabledInt := 0
+	if enabled {
+		enabledInt = 1
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE notification_credentials SET enabled = ?, updated_at = datetime('now')
+		WHERE provider = ?
+	`, enabledInt, provider)
+
+	return err
+}
+
 // Load retrieves and decrypts credentials for a provider.
 func (s *CredentialsStore) Load(ctx context.Context, provider string) (*ProviderCredentials, error) {
 	var enabled int
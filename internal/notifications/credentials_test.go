@@ -0,0 +1,53 @@
+package notifications
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/dtorcivia/schedlock/internal/database"
+)
+
+func TestSetEnabledKeepsCredentials(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	store, err := NewCredentialsStore(db, "test-encryption-key")
+	if err != nil {
+		t.Fatalf("credentials store: %v", err)
+	}
+
+	ctx := context.Background()
+	saved := &PushoverCredentials{AppToken: "app-token", UserKey: "user-key", Sound: "siren"}
+	if err := store.Save(ctx, "pushover", true, saved); err != nil {
+		t.Fatalf("save pushover: %v", err)
+	}
+
+	for _, enabled := range []bool{false, true} {
+		if err := store.SetEnabled(ctx, "pushover", enabled); err != nil {
+			t.Fatalf("set enabled %v: %v", enabled, err)
+		}
+		creds, err := store.Load(ctx, "pushover")
+		if err != nil {
+			t.Fatalf("load pushover: %v", err)
+		}
+		if creds.Enabled != enabled {
+			t.Fatalf("expected enabled=%v, got %v", enabled, creds.Enabled)
+		}
+		pc, ok := creds.Credentials.(*PushoverCredentials)
+		if !ok || *pc != *saved {
+			t.Fatalf("enabled=%v: expected credentials to be kept, got %+v", enabled, creds.Credentials)
+		}
+	}
+
+	// Disabling a provider that was never configured stores nothing.
+	if err := store.SetEnabled(ctx, "ntfy", false); err != nil {
+		t.Fatalf("set enabled ntfy: %v", err)
+	}
+	if creds, err := store.Load(ctx, "ntfy"); err != nil || creds != nil {
+		t.Fatalf("expected no ntfy credentials, got %+v, %v", creds, err)
+	}
+}
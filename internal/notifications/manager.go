@@ -3,6 +3,7 @@ package notifications
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -14,10 +15,11 @@ import (
 
 // Manager handles multi-provider notification delivery.
 type Manager struct {
-	db        *database.DB
-	config    *config.Config
-	providers []Provider
-	mu        sync.RWMutex
+	db          *database.DB
+	config      *config.Config
+	providers   []Provider
+	credentials *CredentialsStore
+	mu          sync.RWMutex
 }
 
 // NewManager creates a new notification manager.
@@ -29,6 +31,14 @@ func NewManager(db *database.DB, cfg *config.Config) *Manager {
 	}
 }
 
+// SetCredentialsStore sets the store used to read per-provider routing
+// settings such as the minimum request priority.
+func (m *Manager) SetCredentialsStore(store *CredentialsStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.credentials = store
+}
+
 // RegisterProvider adds a notification provider.
 func (m *Manager) RegisterProvider(p Provider) {
 	m.mu.Lock()
@@ -58,21 +68,37 @@ func (m *Manager) GetEnabledProviders() []Provider {
 	return enabled
 }
 
-// SendApprovalRequest sends approval notifications to all enabled providers.
+// SendApprovalRequest sends approval notifications to the recipients of the
+// approval routes matching the request's calendars, and to all enabled
+// providers when a calendar has no route.
 func (m *Manager) SendApprovalRequest(ctx context.Context, notification *ApprovalNotification) error {
-	providers := m.GetEnabledProviders()
+	providers := m.approvalProviders(ctx, notification)
 	if len(providers) == 0 {
-		util.Warn("No notification providers enabled")
 		return nil
 	}
 
+	if !notification.Force {
+		providers = m.skipDelivered(ctx, providers, notification.RequestID)
+		if len(providers) == 0 {
+			return ErrAlreadyDelivered
+		}
+	}
+
 	m.populateApprovalURLs(notification)
+	templates := m.messageTemplates(ctx)
 
 	var lastErr error
 	successCount := 0
 
 	for _, provider := range providers {
-		messageID, err := provider.SendApproval(ctx, notification)
+		providerNotification := notification
+		if tmpl := templates[provider.Name()]; tmpl != "" {
+			copied := *notification
+			copied.MessageTemplate = tmpl
+			providerNotification = &copied
+		}
+
+		messageID, err := provider.SendApproval(ctx, providerNotification)
 		if err != nil {
 			util.Error("Failed to send notification",
 				"provider", provider.Name(),
@@ -101,23 +127,293 @@ func (m *Manager) SendApprovalRequest(ctx context.Context, notification *Approva
 	return nil
 }
 
-// SendResult sends result notifications to all enabled providers.
-func (m *Manager) SendResult(ctx context.Context, notification *ResultNotification) error {
-	providers := m.GetEnabledProviders()
+// approvalProviders returns the providers that should receive the approval
+// notification, before any already-delivered providers are skipped.
+func (m *Manager) approvalProviders(ctx context.Context, notification *ApprovalNotification) []Provider {
+	providers, fallback := m.routeApproval(notification.Calendars)
+	if !fallback {
+		return providers
+	}
 
-	for _, provider := range providers {
+	enabled := m.GetEnabledProviders()
+	if len(enabled) == 0 && len(providers) == 0 {
+		util.Warn("No notification providers enabled")
+		return nil
+	}
+
+	enabled = m.filterByPriority(ctx, enabled, notification.Priority)
+	if len(enabled) == 0 && len(providers) == 0 {
+		util.Info("No notification providers accept request priority",
+			"request_id", notification.RequestID,
+			"priority", notification.Priority,
+		)
+		return nil
+	}
+	return append(providers, enabled...)
+}
+
+// AlreadyDelivered reports whether every provider that would receive the
+// approval notification has already delivered it, i.e. whether
+// SendApprovalRequest would return ErrAlreadyDelivered without Force.
+func (m *Manager) AlreadyDelivered(ctx context.Context, notification *ApprovalNotification) bool {
+	providers := m.approvalProviders(ctx, notification)
+	if len(providers) == 0 {
+		return false
+	}
+	return len(m.skipDelivered(ctx, providers, notification.RequestID)) == 0
+}
+
+// ErrAlreadyDelivered is returned by SendApprovalRequest when every provider
+// already delivered the request and Force is not set.
+var ErrAlreadyDelivered = errors.New("approval already delivered by every provider")
+
+// skipDelivered drops providers with a successful delivery for the request
+// in the notification log, so retries after a partial failure only reach
+// the providers that missed it. If the log cannot be read, nothing is
+// skipped.
+func (m *Manager) skipDelivered(ctx context.Context, providers []Provider, requestID string) []Provider {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT DISTINCT provider FROM notification_log
+		WHERE request_id = ? AND status IN (?, ?)
+	`, requestID, database.NotificationSent, database.NotificationCallbackReceived)
+	if err != nil {
+		util.Warn("Failed to check notification log", "error", err, "request_id", requestID)
+		return providers
+	}
+	defer rows.Close()
+
+	delivered := make(map[string]bool)
+	for rows.Next() {
+		var provider string
+		if err := rows.Scan(&provider); err != nil {
+			util.Warn("Failed to check notification log", "error", err, "request_id", requestID)
+			return providers
+		}
+		delivered[provider] = true
+	}
+	if len(delivered) == 0 {
+		return providers
+	}
+
+	var pending []Provider
+	for _, p := range providers {
+		if delivered[p.Name()] {
+			util.Info("Skipping provider that already delivered the request",
+				"provider", p.Name(),
+				"request_id", requestID,
+			)
+			continue
+		}
+		pending = append(pending, p)
+	}
+	return pending
+}
+
+// Errors returned by PreviewApproval and SendEscalation.
+var (
+	ErrProviderNotFound      = errors.New("unknown notification provider")
+	ErrPreviewUnsupported    = errors.New("provider does not support previews")
+	ErrEscalationUnsupported = errors.New("provider does not support escalation")
+)
+
+// SendEscalation delivers the approval notification through one provider to
+// an alternate recipient, such as a manager's chat, ignoring the provider's
+// minimum priority. The delivery is recorded in the notification log.
+func (m *Manager) SendEscalation(ctx context.Context, providerName, recipient string, notification *ApprovalNotification) error {
+	provider := m.GetProviderByName(providerName)
+	if provider == nil || !provider.Enabled() {
+		return fmt.Errorf("%w: %s", ErrProviderNotFound, providerName)
+	}
+	sender, ok := provider.(EscalationSender)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrEscalationUnsupported, providerName)
+	}
+
+	copied := *notification
+	m.populateApprovalURLs(&copied)
+	copied.MessageTemplate = m.messageTemplates(ctx)[providerName]
+
+	messageID, err := sender.SendApprovalTo(ctx, &copied, recipient)
+	if err != nil {
+		m.logNotification(ctx, notification.RequestID, providerName, "", database.NotificationFailed, err.Error())
+		return err
+	}
+	m.logNotification(ctx, notification.RequestID, providerName, messageID, database.NotificationSent, "")
+	util.Info("Sent escalation notification",
+		"provider", providerName,
+		"request_id", notification.RequestID,
+		"message_id", messageID,
+	)
+	return nil
+}
+
+// PreviewApproval renders the approval message one provider would send for
+// the notification, with the same links and message template, without
+// delivering it. Disabled providers can be previewed too.
+func (m *Manager) PreviewApproval(ctx context.Context, providerName string, notification *ApprovalNotification) (*RenderedApproval, error) {
+	var provider Provider
+	for _, p := range m.GetProviders() {
+		if p.Name() == providerName {
+			provider = p
+			break
+		}
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("%w: %s", ErrProviderNotFound, providerName)
+	}
+	renderer, ok := provider.(ApprovalRenderer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPreviewUnsupported, providerName)
+	}
+
+	copied := *notification
+	m.populateApprovalURLs(&copied)
+	copied.MessageTemplate = m.messageTemplates(ctx)[providerName]
+
+	return renderer.RenderApproval(&copied), nil
+}
+
+// filterByPriority drops providers whose configured minimum priority is
+// above the request's priority.
+func (m *Manager) filterByPriority(ctx context.Context, providers []Provider, priority string) []Provider {
+	m.mu.RLock()
+	store := m.credentials
+	m.mu.RUnlock()
+	if store == nil {
+		return providers
+	}
+
+	creds, err := store.LoadAll(ctx)
+	if err != nil {
+		util.Warn("Failed to load provider settings, notifying all providers", "error", err)
+		return providers
+	}
+
+	var allowed []Provider
+	for _, p := range providers {
+		minPriority := ""
+		if pc := creds[p.Name()]; pc != nil {
+			minPriority = pc.MinPriority()
+		}
+		if !meetsMinPriority(priority, minPriority) {
+			util.Debug("Skipping provider below request priority",
+				"provider", p.Name(),
+				"priority", priority,
+				"min_priority", minPriority,
+			)
+			continue
+		}
+		allowed = append(allowed, p)
+	}
+	return allowed
+}
+
+// messageTemplates returns each provider's custom approval template.
+func (m *Manager) messageTemplates(ctx context.Context) map[string]string {
+	m.mu.RLock()
+	store := m.credentials
+	m.mu.RUnlock()
+	if store == nil {
+		return nil
+	}
+
+	creds, err := store.LoadAll(ctx)
+	if err != nil {
+		util.Warn("Failed to load message templates, using built-in messages", "error", err)
+		return nil
+	}
+
+	templates := make(map[string]string)
+	for name, pc := range creds {
+		if pc != nil {
+			templates[name] = pc.MessageTemplate()
+		}
+	}
+	return templates
+}
+
+// meetsMinPriority reports whether a request priority reaches a provider's
+// minimum. An empty minimum accepts every request.
+func meetsMinPriority(priority, minPriority string) bool {
+	if minPriority == "" {
+		return true
+	}
+	return database.PriorityRank(priority) >= database.PriorityRank(minPriority)
+}
+
+// SendResult sends a result notification to every enabled provider
+// configured to report the request's final status.
+func (m *Manager) SendResult(ctx context.Context, notification *ResultNotification) error {
+	for _, provider := range m.GetEnabledProviders() {
+		if !m.sendsResult(provider.Name(), notification.Status) {
+			continue
+		}
 		if err := provider.SendResult(ctx, notification); err != nil {
 			util.Error("Failed to send result notification",
 				"provider", provider.Name(),
 				"request_id", notification.RequestID,
 				"error", err,
 			)
+			continue
 		}
+		util.Info("Sent result notification",
+			"provider", provider.Name(),
+			"request_id", notification.RequestID,
+			"status", notification.Status,
+		)
 	}
 
 	return nil
 }
 
+// sendsResult reports whether a provider's results_on setting includes the
+// status.
+func (m *Manager) sendsResult(providerName, status string) bool {
+	var statuses []string
+	switch providerName {
+	case "ntfy":
+		statuses = m.config.Notifications.Ntfy.ResultsOn
+	case "pushover":
+		statuses = m.config.Notifications.Pushover.ResultsOn
+	case "telegram":
+		statuses = m.config.Notifications.Telegram.ResultsOn
+	case "webhook":
+		statuses = m.config.Notifications.Webhook.ResultsOn
+	}
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// SendAlert sends an admin alert to every enabled provider that supports
+// alerts. Alerts ignore provider minimum priorities.
+func (m *Manager) SendAlert(ctx context.Context, alert *AlertNotification) error {
+	sent := 0
+	for _, provider := range m.GetEnabledProviders() {
+		sender, ok := provider.(AlertSender)
+		if !ok {
+			continue
+		}
+		if err := sender.SendAlert(ctx, alert); err != nil {
+			util.Error("Failed to send alert",
+				"provider", provider.Name(),
+				"kind", alert.Kind,
+				"error", err,
+			)
+			continue
+		}
+		sent++
+	}
+
+	if sent == 0 {
+		return fmt.Errorf("no provider delivered the %s alert", alert.Kind)
+	}
+	return nil
+}
+
 // TestProvider sends a test notification to a specific provider.
 func (m *Manager) TestProvider(ctx context.Context, providerName string) error {
 	m.mu.RLock()
@@ -0,0 +1,345 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+)
+
+type recordingProvider struct {
+	name    string
+	sent    []string
+	results []string
+}
+
+func (p *recordingProvider) Name() string  { return p.name }
+func (p *recordingProvider) Enabled() bool { return true }
+
+func (p *recordingProvider) SendApproval(ctx context.Context, n *ApprovalNotification) (string, error) {
+	p.sent = append(p.sent, n.RequestID)
+	return "", nil
+}
+
+func (p *recordingProvider) SendResult(ctx context.Context, n *ResultNotification) error {
+	p.results = append(p.results, n.Status)
+	return nil
+}
+
+func (p *recordingProvider) SendTest(ctx context.Context) error {
+	return nil
+}
+
+func TestMeetsMinPriority(t *testing.T) {
+	cases := []struct {
+		priority string
+		min      string
+		want     bool
+	}{
+		{database.PriorityLow, "", true},
+		{database.PriorityNormal, "", true},
+		{database.PriorityHigh, "", true},
+		{database.PriorityLow, database.PriorityLow, true},
+		{database.PriorityNormal, database.PriorityLow, true},
+		{database.PriorityHigh, database.PriorityLow, true},
+		{database.PriorityLow, database.PriorityNormal, false},
+		{database.PriorityNormal, database.PriorityNormal, true},
+		{database.PriorityHigh, database.PriorityNormal, true},
+		{database.PriorityLow, database.PriorityHigh, false},
+		{database.PriorityNormal, database.PriorityHigh, false},
+		{database.PriorityHigh, database.PriorityHigh, true},
+		// Requests without a priority are treated as normal
+		{"", database.PriorityNormal, true},
+		{"", database.PriorityHigh, false},
+	}
+
+	for _, tc := range cases {
+		if got := meetsMinPriority(tc.priority, tc.min); got != tc.want {
+			t.Errorf("meetsMinPriority(%q, %q) = %v, want %v", tc.priority, tc.min, got, tc.want)
+		}
+	}
+}
+
+func TestSendApprovalRequestFiltersByMinPriority(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	store, err := NewCredentialsStore(db, "test-encryption-key")
+	if err != nil {
+		t.Fatalf("credentials store: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := store.Save(ctx, "telegram", true, &TelegramCredentials{BotToken: "t", ChatID: "1"}); err != nil {
+		t.Fatalf("save telegram: %v", err)
+	}
+	if err := store.Save(ctx, "pushover", true, &PushoverCredentials{AppToken: "a", UserKey: "u", MinPriority: database.PriorityHigh}); err != nil {
+		t.Fatalf("save pushover: %v", err)
+	}
+
+	telegram := &recordingProvider{name: "telegram"}
+	pushover := &recordingProvider{name: "pushover"}
+
+	mgr := NewManager(db, &config.Config{})
+	mgr.SetCredentialsStore(store)
+	mgr.RegisterProvider(telegram)
+	mgr.RegisterProvider(pushover)
+
+	for _, priority := range []string{database.PriorityLow, database.PriorityNormal, database.PriorityHigh} {
+		if err := mgr.SendApprovalRequest(ctx, &ApprovalNotification{RequestID: priority, Priority: priority}); err != nil {
+			t.Fatalf("send %s: %v", priority, err)
+		}
+	}
+
+	if len(telegram.sent) != 3 {
+		t.Fatalf("expected telegram to receive every request, got %v", telegram.sent)
+	}
+	if len(pushover.sent) != 1 || pushover.sent[0] != database.PriorityHigh {
+		t.Fatalf("expected pushover to receive only the high priority request, got %v", pushover.sent)
+	}
+}
+
+type alertingProvider struct {
+	recordingProvider
+	alerts []string
+}
+
+func (p *alertingProvider) SendAlert(ctx context.Context, alert *AlertNotification) error {
+	p.alerts = append(p.alerts, alert.Kind)
+	return nil
+}
+
+func TestSendAlertSkipsProvidersWithoutAlerts(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	mgr := NewManager(db, &config.Config{})
+
+	plain := &recordingProvider{name: "plain"}
+	mgr.RegisterProvider(plain)
+	if err := mgr.SendAlert(ctx, &AlertNotification{Kind: AlertOAuthUnhealthy}); err == nil {
+		t.Fatal("expected an error when no provider can deliver alerts")
+	}
+
+	alerting := &alertingProvider{recordingProvider: recordingProvider{name: "alerting"}}
+	mgr.RegisterProvider(alerting)
+	if err := mgr.SendAlert(ctx, &AlertNotification{Kind: AlertOAuthUnhealthy}); err != nil {
+		t.Fatalf("SendAlert failed: %v", err)
+	}
+	if len(alerting.alerts) != 1 || alerting.alerts[0] != AlertOAuthUnhealthy {
+		t.Fatalf("expected one oauth alert, got %v", alerting.alerts)
+	}
+}
+
+type renderingProvider struct {
+	recordingProvider
+}
+
+func (p *renderingProvider) RenderApproval(n *ApprovalNotification) *RenderedApproval {
+	return &RenderedApproval{Provider: p.name, Format: "text", Body: n.MessageTemplate + "|" + n.ApprovePageURL}
+}
+
+func TestPreviewApproval(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	store, err := NewCredentialsStore(db, "test-encryption-key")
+	if err != nil {
+		t.Fatalf("credentials store: %v", err)
+	}
+	ctx := context.Background()
+	if err := store.Save(ctx, "telegram", false, &TelegramCredentials{BotToken: "t", ChatID: "1", MessageTemplate: "custom"}); err != nil {
+		t.Fatalf("save telegram: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Server.BaseURL = "https://schedlock.example.com"
+	mgr := NewManager(db, cfg)
+	mgr.SetCredentialsStore(store)
+	telegram := &renderingProvider{recordingProvider{name: "telegram"}}
+	mgr.RegisterProvider(telegram)
+	mgr.RegisterProvider(&recordingProvider{name: "plain"})
+
+	notification := &ApprovalNotification{RequestID: "req_1", DecisionToken: PreviewDecisionToken}
+	rendered, err := mgr.PreviewApproval(ctx, "telegram", notification)
+	if err != nil {
+		t.Fatalf("PreviewApproval failed: %v", err)
+	}
+	if want := "custom|https://schedlock.example.com/approve/preview"; rendered.Body != want {
+		t.Errorf("body = %q, want %q", rendered.Body, want)
+	}
+	if notification.ApprovePageURL != "" || notification.MessageTemplate != "" {
+		t.Error("PreviewApproval modified the caller's notification")
+	}
+	if len(telegram.sent) != 0 {
+		t.Errorf("preview delivered a notification: %v", telegram.sent)
+	}
+
+	if _, err := mgr.PreviewApproval(ctx, "pushover", notification); !errors.Is(err, ErrProviderNotFound) {
+		t.Errorf("unregistered provider: got %v, want ErrProviderNotFound", err)
+	}
+	if _, err := mgr.PreviewApproval(ctx, "plain", notification); !errors.Is(err, ErrPreviewUnsupported) {
+		t.Errorf("provider without renderer: got %v, want ErrPreviewUnsupported", err)
+	}
+}
+
+type escalatingProvider struct {
+	recordingProvider
+	recipients []string
+}
+
+func (p *escalatingProvider) SendApprovalTo(ctx context.Context, n *ApprovalNotification, recipient string) (string, error) {
+	p.recipients = append(p.recipients, n.RequestID+"@"+recipient)
+	return "", nil
+}
+
+func TestSendEscalation(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	mgr := NewManager(db, &config.Config{})
+	telegram := &escalatingProvider{recordingProvider: recordingProvider{name: "telegram"}}
+	mgr.RegisterProvider(telegram)
+	mgr.RegisterProvider(&recordingProvider{name: "plain"})
+
+	notification := &ApprovalNotification{RequestID: "req_1", Priority: database.PriorityLow}
+	if err := mgr.SendEscalation(ctx, "telegram", "-100123", notification); err != nil {
+		t.Fatalf("SendEscalation failed: %v", err)
+	}
+	if len(telegram.recipients) != 1 || telegram.recipients[0] != "req_1@-100123" {
+		t.Errorf("recipients = %v, want [req_1@-100123]", telegram.recipients)
+	}
+	if len(telegram.sent) != 0 {
+		t.Errorf("escalation went to the primary recipient: %v", telegram.sent)
+	}
+
+	if err := mgr.SendEscalation(ctx, "pushover", "u", notification); !errors.Is(err, ErrProviderNotFound) {
+		t.Errorf("unregistered provider: got %v, want ErrProviderNotFound", err)
+	}
+	if err := mgr.SendEscalation(ctx, "plain", "x", notification); !errors.Is(err, ErrEscalationUnsupported) {
+		t.Errorf("provider without escalation: got %v, want ErrEscalationUnsupported", err)
+	}
+}
+
+type flakyProvider struct {
+	recordingProvider
+	failures int
+}
+
+func (p *flakyProvider) SendApproval(ctx context.Context, n *ApprovalNotification) (string, error) {
+	if p.failures > 0 {
+		p.failures--
+		return "", errors.New("unavailable")
+	}
+	return p.recordingProvider.SendApproval(ctx, n)
+}
+
+func TestSendApprovalRequestSkipsDeliveredProviders(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_a', 'hash_a', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status)
+		VALUES ('req_1', 'key_a', 'create_event', '{}', datetime('now', '+1 hour'), 'pending_approval')
+	`); err != nil {
+		t.Fatalf("insert request: %v", err)
+	}
+
+	ctx := context.Background()
+	mgr := NewManager(db, &config.Config{})
+	telegram := &recordingProvider{name: "telegram"}
+	pushover := &flakyProvider{recordingProvider: recordingProvider{name: "pushover"}, failures: 1}
+	mgr.RegisterProvider(telegram)
+	mgr.RegisterProvider(pushover)
+
+	// Pushover fails the first time; the retry only goes to Pushover
+	for i := 0; i < 2; i++ {
+		if err := mgr.SendApprovalRequest(ctx, &ApprovalNotification{RequestID: "req_1"}); err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+	}
+	if len(telegram.sent) != 1 || len(pushover.sent) != 1 {
+		t.Fatalf("deliveries: telegram %v, pushover %v; want one each", telegram.sent, pushover.sent)
+	}
+
+	if !mgr.AlreadyDelivered(ctx, &ApprovalNotification{RequestID: "req_1"}) {
+		t.Error("AlreadyDelivered = false after every provider delivered")
+	}
+	if mgr.AlreadyDelivered(ctx, &ApprovalNotification{RequestID: "req_2"}) {
+		t.Error("AlreadyDelivered = true for a request nobody delivered")
+	}
+
+	if err := mgr.SendApprovalRequest(ctx, &ApprovalNotification{RequestID: "req_1"}); !errors.Is(err, ErrAlreadyDelivered) {
+		t.Fatalf("third send: got %v, want ErrAlreadyDelivered", err)
+	}
+
+	if err := mgr.SendApprovalRequest(ctx, &ApprovalNotification{RequestID: "req_1", Force: true}); err != nil {
+		t.Fatalf("forced send: %v", err)
+	}
+	if len(telegram.sent) != 2 || len(pushover.sent) != 2 {
+		t.Errorf("forced send should reach every provider: telegram %v, pushover %v", telegram.sent, pushover.sent)
+	}
+}
+
+func TestSendResultFollowsResultsOn(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{}
+	cfg.Notifications.Telegram.ResultsOn = []string{config.ResultStatusCompleted, config.ResultStatusFailed, config.ResultStatusDenied}
+	cfg.Notifications.Ntfy.ResultsOn = []string{config.ResultStatusFailed}
+
+	ctx := context.Background()
+	mgr := NewManager(db, cfg)
+	telegram := &recordingProvider{name: "telegram"}
+	ntfy := &recordingProvider{name: "ntfy"}
+	pushover := &recordingProvider{name: "pushover"}
+	mgr.RegisterProvider(telegram)
+	mgr.RegisterProvider(ntfy)
+	mgr.RegisterProvider(pushover)
+
+	for _, status := range []string{database.StatusCompleted, database.StatusFailed, database.StatusDenied} {
+		if err := mgr.SendResult(ctx, &ResultNotification{RequestID: "req_1", Status: status}); err != nil {
+			t.Fatalf("send %s: %v", status, err)
+		}
+	}
+
+	if len(telegram.results) != 3 {
+		t.Errorf("telegram results = %v, want completed, failed and denied", telegram.results)
+	}
+	if len(ntfy.results) != 1 || ntfy.results[0] != database.StatusFailed {
+		t.Errorf("ntfy results = %v, want [failed]", ntfy.results)
+	}
+	if len(pushover.results) != 0 {
+		t.Errorf("pushover has no results_on but got %v", pushover.results)
+	}
+}
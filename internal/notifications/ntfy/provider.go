@@ -13,6 +13,7 @@ import (
 
 	"github.com/dtorcivia/schedlock/internal/config"
 	"github.com/dtorcivia/schedlock/internal/notifications"
+	"github.com/dtorcivia/schedlock/internal/util"
 )
 
 // Provider implements ntfy notifications.
@@ -62,12 +63,58 @@ type ntfyAction struct {
 
 // SendApproval sends an approval request notification.
 func (p *Provider) SendApproval(ctx context.Context, notification *notifications.ApprovalNotification) (string, error) {
+	return p.send(ctx, p.approvalMessage(notification))
+}
+
+// SendApprovalTo sends an approval request notification to another topic on
+// the same server.
+func (p *Provider) SendApprovalTo(ctx context.Context, notification *notifications.ApprovalNotification, topic string) (string, error) {
+	msg := p.approvalMessage(notification)
+	msg.Topic = topic
+	return p.send(ctx, msg)
+}
+
+// RenderApproval returns the approval message, click target and actions
+// without sending them.
+func (p *Provider) RenderApproval(notification *notifications.ApprovalNotification) *notifications.RenderedApproval {
+	msg := p.approvalMessage(notification)
+	return &notifications.RenderedApproval{
+		Provider: p.Name(),
+		Format:   "text",
+		Title:    msg.Title,
+		Body:     msg.Message,
+		Markup: map[string]interface{}{
+			"click":    msg.Click,
+			"actions":  msg.Actions,
+			"priority": msg.Priority,
+		},
+	}
+}
+
+// approvalMessage builds the ntfy message for an approval request.
+func (p *Provider) approvalMessage(notification *notifications.ApprovalNotification) *ntfyMessage {
 	title := fmt.Sprintf("[Approval] %s", notification.Summary)
+	if p.config.MinimalContent {
+		// The summary names the event, so keep it off the lock screen too
+		title = "[Approval] Calendar request"
+	}
 
 	var body strings.Builder
+	custom := ""
+	if notification.MessageTemplate != "" && !p.config.MinimalContent {
+		rendered, err := notifications.RenderApprovalTemplate(notification.MessageTemplate, notification, notifications.TemplateMarkup{})
+		if err != nil {
+			util.Warn("ntfy message template failed, using default message", "error", err)
+		} else {
+			custom = rendered
+		}
+	}
+
 	if p.config.MinimalContent {
 		body.WriteString("A calendar request is awaiting approval.\n\n")
 		body.WriteString("Review details in the web UI.\n")
+	} else if custom != "" {
+		body.WriteString(custom)
 	} else {
 		body.WriteString(fmt.Sprintf("Operation: %s\n", notification.Operation))
 
@@ -76,7 +123,7 @@ func (p *Provider) SendApproval(ctx context.Context, notification *notifications
 				body.WriteString(fmt.Sprintf("Event: %s\n", notification.Details.Title))
 			}
 			if !notification.Details.StartTime.IsZero() {
-				body.WriteString(fmt.Sprintf("When: %s\n", notification.Details.StartTime.Format("Mon Jan 2, 3:04 PM")))
+				body.WriteString(fmt.Sprintf("When: %s\n", notifications.FormatEventTime(notification.Details.StartTime)))
 			}
 			if notification.Details.Location != "" {
 				body.WriteString(fmt.Sprintf("Where: %s\n", notification.Details.Location))
@@ -84,10 +131,18 @@ func (p *Provider) SendApproval(ctx context.Context, notification *notifications
 			if len(notification.Details.Attendees) > 0 {
 				body.WriteString(fmt.Sprintf("Attendees: %s\n", strings.Join(notification.Details.Attendees, ", ")))
 			}
+			if len(notification.Details.OptionalAttendees) > 0 {
+				body.WriteString(fmt.Sprintf("Optional: %s\n", strings.Join(notification.Details.OptionalAttendees, ", ")))
+			}
+			if len(notification.Details.Calendars) > 0 {
+				body.WriteString(fmt.Sprintf("Calendars: %s\n", strings.Join(notification.Details.Calendars, ", ")))
+			}
 		}
 	}
 
-	body.WriteString(fmt.Sprintf("\nExpires: %s", notification.ExpiresIn))
+	if custom == "" {
+		body.WriteString(fmt.Sprintf("\nExpires: %s", notification.ExpiresIn))
+	}
 
 	// Use public approval page for click action (opens in browser)
 	clickURL := notification.ApprovePageURL
@@ -134,7 +189,7 @@ func (p *Provider) SendApproval(ctx context.Context, notification *notifications
 		})
 	}
 
-	return p.send(ctx, &msg)
+	return &msg
 }
 
 // SendResult sends a result notification.
@@ -187,6 +242,21 @@ func (p *Provider) SendTest(ctx context.Context) error {
 	return err
 }
 
+// SendAlert sends an admin alert at urgent priority.
+func (p *Provider) SendAlert(ctx context.Context, alert *notifications.AlertNotification) error {
+	msg := ntfyMessage{
+		Topic:    p.config.Topic,
+		Title:    alert.Title,
+		Message:  alert.Message,
+		Priority: 5,
+		Tags:     []string{"warning"},
+		Click:    alert.URL,
+	}
+
+	_, err := p.send(ctx, &msg)
+	return err
+}
+
 func mapPriority(value string) int {
 	switch strings.ToLower(value) {
 	case "urgent":
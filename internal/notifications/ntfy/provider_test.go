@@ -0,0 +1,111 @@
+package ntfy
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/notifications"
+)
+
+// capture starts a fake ntfy server that records the last published request.
+func capture(t *testing.T) (*httptest.Server, *http.Header, *ntfyMessage) {
+	t.Helper()
+	var header http.Header
+	var msg ntfyMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Errorf("invalid JSON body: %v: %s", err, body)
+		}
+		w.Write([]byte(`{"id":"msg123"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &header, &msg
+}
+
+func approval() *notifications.ApprovalNotification {
+	return &notifications.ApprovalNotification{
+		RequestID:      "req_1",
+		Operation:      "create_event",
+		Summary:        "Create: Dentist",
+		ExpiresIn:      "15m",
+		ApproveURL:     "https://sl.example.com/api/callback/approve/tok?a=1&b=2",
+		DenyURL:        "https://sl.example.com/api/callback/deny/tok",
+		ApprovePageURL: "https://sl.example.com/approve/tok",
+		Details: &notifications.EventDetails{
+			Title:     "Dentist",
+			StartTime: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+			Location:  "Main St",
+		},
+	}
+}
+
+func TestSendApprovalActions(t *testing.T) {
+	server, header, msg := capture(t)
+	provider := NewProvider(&config.NtfyConfig{Enabled: true, Server: server.URL, Topic: "approvals", Token: "tk_secret"})
+
+	id, err := provider.SendApproval(context.Background(), approval())
+	if err != nil {
+		t.Fatalf("SendApproval: %v", err)
+	}
+	if id != "msg123" {
+		t.Errorf("message ID = %q", id)
+	}
+
+	if got := header.Get("Authorization"); got != "Bearer tk_secret" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	if msg.Topic != "approvals" || msg.Click != "https://sl.example.com/approve/tok" {
+		t.Errorf("topic/click = %q/%q", msg.Topic, msg.Click)
+	}
+	want := []ntfyAction{
+		{Action: "http", Label: "Approve", URL: "https://sl.example.com/api/callback/approve/tok?a=1&b=2", Method: "POST", Clear: true},
+		{Action: "http", Label: "Deny", URL: "https://sl.example.com/api/callback/deny/tok", Method: "POST", Clear: true},
+		{Action: "view", Label: "Review", URL: "https://sl.example.com/approve/tok"},
+	}
+	if len(msg.Actions) != len(want) {
+		t.Fatalf("actions = %+v", msg.Actions)
+	}
+	for i := range want {
+		if msg.Actions[i] != want[i] {
+			t.Errorf("action %d = %+v, want %+v", i, msg.Actions[i], want[i])
+		}
+	}
+	if !strings.Contains(msg.Message, "Dentist") || !strings.Contains(msg.Message, "Main St") {
+		t.Errorf("message should include event details: %q", msg.Message)
+	}
+}
+
+func TestSendApprovalMinimalContent(t *testing.T) {
+	server, header, msg := capture(t)
+	provider := NewProvider(&config.NtfyConfig{Enabled: true, Server: server.URL, Topic: "approvals", MinimalContent: true})
+
+	if _, err := provider.SendApproval(context.Background(), approval()); err != nil {
+		t.Fatalf("SendApproval: %v", err)
+	}
+
+	if got := header.Get("Authorization"); got != "" {
+		t.Errorf("unexpected Authorization header %q without a token", got)
+	}
+	for _, text := range []string{msg.Title, msg.Message} {
+		if strings.Contains(text, "Dentist") || strings.Contains(text, "Main St") {
+			t.Errorf("minimal content leaked event details: %q", text)
+		}
+	}
+	// Buttons still work without details
+	if len(msg.Actions) != 3 {
+		t.Errorf("expected approve, deny and review actions, got %+v", msg.Actions)
+	}
+}
@@ -24,6 +24,28 @@ type Provider interface {
 	SendTest(ctx context.Context) error
 }
 
+// ApprovalRenderer is implemented by providers that can build their
+// approval message without sending it.
+type ApprovalRenderer interface {
+	// RenderApproval returns the approval message SendApproval would deliver.
+	RenderApproval(notification *ApprovalNotification) *RenderedApproval
+}
+
+// EscalationSender is implemented by providers that can deliver an approval
+// request to a recipient other than their configured one, for escalations.
+type EscalationSender interface {
+	// SendApprovalTo sends the approval request to recipient, whose meaning
+	// is provider-specific (a chat ID, topic or user key).
+	SendApprovalTo(ctx context.Context, notification *ApprovalNotification, recipient string) (messageID string, err error)
+}
+
+// AlertSender is implemented by providers that can deliver admin alerts
+// about the health of SchedLock itself, outside any request.
+type AlertSender interface {
+	// SendAlert sends an operational alert to the administrator.
+	SendAlert(ctx context.Context, alert *AlertNotification) error
+}
+
 // CallbackHandler handles approval callbacks from providers that support them.
 type CallbackHandler interface {
 	// HandleCallback processes an approval callback.
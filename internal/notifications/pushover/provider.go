@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"net/url"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/dtorcivia/schedlock/internal/config"
 	"github.com/dtorcivia/schedlock/internal/notifications"
+	"github.com/dtorcivia/schedlock/internal/util"
 )
 
 const pushoverAPIURL = "https://api.pushover.net/1/messages.json"
@@ -45,9 +47,83 @@ func (p *Provider) Enabled() bool {
 
 // SendApproval sends an approval request notification.
 func (p *Provider) SendApproval(ctx context.Context, notification *notifications.ApprovalNotification) (string, error) {
-	title := fmt.Sprintf("Calendar: %s", notification.Summary)
+	return p.SendApprovalTo(ctx, notification, p.config.UserKey)
+}
+
+// SendApprovalTo sends an approval request notification to another Pushover
+// user or group key.
+func (p *Provider) SendApprovalTo(ctx context.Context, notification *notifications.ApprovalNotification, userKey string) (string, error) {
+	title, body, mainURL := approvalMessage(notification)
+
+	params := url.Values{
+		"token":     {p.config.AppToken},
+		"user":      {userKey},
+		"title":     {title},
+		"message":   {body},
+		"html":      {"1"},
+		"priority":  {"1"}, // High priority
+		"url":       {mainURL},
+		"url_title": {approvalURLTitle},
+	}
+
+	return p.send(ctx, params)
+}
+
+// approvalURLTitle labels the supplementary URL on approval messages.
+const approvalURLTitle = "Review & Decide"
+
+// RenderApproval returns the approval title, HTML body and link without
+// sending them.
+func (p *Provider) RenderApproval(notification *notifications.ApprovalNotification) *notifications.RenderedApproval {
+	title, body, mainURL := approvalMessage(notification)
+	return &notifications.RenderedApproval{
+		Provider: p.Name(),
+		Format:   "html",
+		Title:    title,
+		Body:     body,
+		Markup: map[string]string{
+			"url":       mainURL,
+			"url_title": approvalURLTitle,
+		},
+	}
+}
+
+// approvalMessage builds the title, HTML body and supplementary URL for an
+// approval request.
+func approvalMessage(notification *notifications.ApprovalNotification) (title, body, mainURL string) {
+	title = fmt.Sprintf("Calendar: %s", notification.Summary)
+
+	var text strings.Builder
+	if notification.MessageTemplate != "" {
+		rendered, err := notifications.RenderApprovalTemplate(notification.MessageTemplate, notification, htmlMarkup)
+		if err != nil {
+			util.Warn("Pushover message template failed, using default message", "error", err)
+		} else if rendered != "" {
+			text.WriteString(rendered)
+			text.WriteString("\n\n")
+		}
+	}
+
+	if text.Len() == 0 {
+		writeDefaultApproval(&text, notification)
+	}
+
+	// Use public approval page for browser links
+	if notification.ApprovePageURL != "" {
+		text.WriteString(fmt.Sprintf("<a href=\"%s\">Review & Approve</a>", notification.ApprovePageURL))
+	}
+
+	// Main URL points to public approval page for one-tap action
+	mainURL = notification.ApprovePageURL
+	if mainURL == "" {
+		mainURL = notification.WebURL
+	}
 
-	var body strings.Builder
+	return title, text.String(), mainURL
+}
+
+// writeDefaultApproval writes the built-in approval details.
+func writeDefaultApproval(body *strings.Builder, notification *notifications.ApprovalNotification) {
 	body.WriteString(fmt.Sprintf("<b>Operation:</b> %s\n", notification.Operation))
 
 	if notification.Details != nil {
@@ -55,7 +131,7 @@ func (p *Provider) SendApproval(ctx context.Context, notification *notifications
 			body.WriteString(fmt.Sprintf("<b>Event:</b> %s\n", notification.Details.Title))
 		}
 		if !notification.Details.StartTime.IsZero() {
-			body.WriteString(fmt.Sprintf("<b>When:</b> %s\n", notification.Details.StartTime.Format("Mon Jan 2, 3:04 PM")))
+			body.WriteString(fmt.Sprintf("<b>When:</b> %s\n", notifications.FormatEventTime(notification.Details.StartTime)))
 		}
 		if notification.Details.Location != "" {
 			body.WriteString(fmt.Sprintf("<b>Where:</b> %s\n", notification.Details.Location))
@@ -63,32 +139,23 @@ func (p *Provider) SendApproval(ctx context.Context, notification *notifications
 		if len(notification.Details.Attendees) > 0 {
 			body.WriteString(fmt.Sprintf("<b>Attendees:</b> %s\n", strings.Join(notification.Details.Attendees, ", ")))
 		}
+		if len(notification.Details.OptionalAttendees) > 0 {
+			body.WriteString(fmt.Sprintf("<b>Optional:</b> %s\n", strings.Join(notification.Details.OptionalAttendees, ", ")))
+		}
+		if len(notification.Details.Calendars) > 0 {
+			body.WriteString(fmt.Sprintf("<b>Calendars:</b> %s\n", strings.Join(notification.Details.Calendars, ", ")))
+		}
 	}
 
 	body.WriteString(fmt.Sprintf("\n<b>Expires:</b> %s\n\n", notification.ExpiresIn))
-	// Use public approval page for browser links
-	if notification.ApprovePageURL != "" {
-		body.WriteString(fmt.Sprintf("<a href=\"%s\">Review & Approve</a>", notification.ApprovePageURL))
-	}
-
-	// Main URL points to public approval page for one-tap action
-	mainURL := notification.ApprovePageURL
-	if mainURL == "" {
-		mainURL = notification.WebURL
-	}
-
-	params := url.Values{
-		"token":     {p.config.AppToken},
-		"user":      {p.config.UserKey},
-		"title":     {title},
-		"message":   {body.String()},
-		"html":      {"1"},
-		"priority":  {"1"}, // High priority
-		"url":       {mainURL},
-		"url_title": {"Review & Decide"},
-	}
+}
 
-	return p.send(ctx, params)
+// htmlMarkup renders message templates as Pushover HTML, escaping all
+// literal text and values.
+var htmlMarkup = notifications.TemplateMarkup{
+	Escape: html.EscapeString,
+	Bold:   func(s string) string { return "<b>" + s + "</b>" },
+	Italic: func(s string) string { return "<i>" + s + "</i>" },
 }
 
 // SendResult sends a result notification.
@@ -141,6 +208,23 @@ func (p *Provider) SendTest(ctx context.Context) error {
 	return err
 }
 
+// SendAlert sends an admin alert at high priority.
+func (p *Provider) SendAlert(ctx context.Context, alert *notifications.AlertNotification) error {
+	params := url.Values{
+		"token":    {p.config.AppToken},
+		"user":     {p.config.UserKey},
+		"title":    {alert.Title},
+		"message":  {alert.Message},
+		"priority": {"1"},
+	}
+	if alert.URL != "" {
+		params.Set("url", alert.URL)
+	}
+
+	_, err := p.send(ctx, params)
+	return err
+}
+
 // send sends a message to Pushover and returns the receipt/request ID.
 func (p *Provider) send(ctx context.Context, params url.Values) (string, error) {
 	req, err := http.NewRequestWithContext(ctx, "POST", pushoverAPIURL, strings.NewReader(params.Encode()))
@@ -159,8 +243,8 @@ func (p *Provider) send(ctx context.Context, params url.Values) (string, error)
 	body, _ := io.ReadAll(resp.Body)
 
 	var response struct {
-		Status  int    `json:"status"`
-		Request string `json:"request"`
+		Status  int      `json:"status"`
+		Request string   `json:"request"`
 		Errors  []string `json:"errors,omitempty"`
 	}
 
@@ -0,0 +1,95 @@
+package notifications
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+// routedProvider delivers approvals through a provider to an approval
+// route's recipient. It keeps the provider's name, so message templates and
+// the notification log treat it as that provider.
+type routedProvider struct {
+	Provider
+	sender EscalationSender
+	target string
+}
+
+func (p routedProvider) SendApproval(ctx context.Context, notification *ApprovalNotification) (string, error) {
+	return p.sender.SendApprovalTo(ctx, notification, p.target)
+}
+
+// routeApproval returns providers for the approval routes matching the
+// given calendars. fallback reports whether the usual providers should be
+// notified too: when no calendars are known, or one of them has no usable
+// route.
+func (m *Manager) routeApproval(calendars []string) (routed []Provider, fallback bool) {
+	routes := m.config.Approval.Routes
+	if len(routes) == 0 || len(calendars) == 0 {
+		return nil, true
+	}
+
+	seen := make(map[config.ApprovalRoute]bool)
+	for _, calendarID := range calendars {
+		usable := false
+		for _, route := range matchRoutes(routes, calendarID) {
+			provider := m.GetProviderByName(route.Provider)
+			if provider == nil || !provider.Enabled() {
+				util.Warn("Approval route provider is not enabled", "provider", route.Provider, "calendar", calendarID)
+				continue
+			}
+			sender, ok := provider.(EscalationSender)
+			if !ok {
+				util.Warn("Approval route provider cannot send to other recipients", "provider", route.Provider, "calendar", calendarID)
+				continue
+			}
+			usable = true
+			if !seen[route] {
+				seen[route] = true
+				routed = append(routed, routedProvider{Provider: provider, sender: sender, target: route.Target})
+			}
+		}
+		if !usable {
+			fallback = true
+		}
+	}
+	return routed, fallback
+}
+
+// matchRoutes returns the routes for a calendar. Routes naming the calendar
+// exactly win over patterns; among patterns the longest matching one wins,
+// and the first listed on a tie. Every route for the winning calendar or
+// pattern is returned, so one calendar can notify several recipients.
+// Calendars are compared without case.
+func matchRoutes(routes []config.ApprovalRoute, calendarID string) []config.ApprovalRoute {
+	calendarID = strings.ToLower(calendarID)
+	best := ""
+	exact := false
+	for _, route := range routes {
+		pattern := strings.ToLower(route.Calendar)
+		if !route.IsPattern() {
+			if pattern == calendarID {
+				best, exact = pattern, true
+				break
+			}
+			continue
+		}
+		if matched, _ := path.Match(pattern, calendarID); matched && len(pattern) > len(best) {
+			best = pattern
+		}
+	}
+	if best == "" {
+		return nil
+	}
+
+	var matched []config.ApprovalRoute
+	for _, route := range routes {
+		if strings.ToLower(route.Calendar) == best && route.IsPattern() == !exact {
+			matched = append(matched, route)
+		}
+	}
+	return matched
+}
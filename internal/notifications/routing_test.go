@@ -0,0 +1,97 @@
+package notifications
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+)
+
+func TestMatchRoutesPrecedence(t *testing.T) {
+	routes := []config.ApprovalRoute{
+		{Calendar: "*", Provider: "ntfy", Target: "everyone"},
+		{Calendar: "*@group.calendar.google.com", Provider: "ntfy", Target: "teams"},
+		{Calendar: "eng-*@group.calendar.google.com", Provider: "telegram", Target: "eng"},
+		{Calendar: "ops-*@group.calendar.google.com", Provider: "telegram", Target: "ops"},
+		{Calendar: "CEO@example.com", Provider: "telegram", Target: "exec"},
+		{Calendar: "ceo@example.com", Provider: "pushover", Target: "assistant"},
+		{Calendar: "*@example.com", Provider: "ntfy", Target: "staff"},
+	}
+
+	tests := []struct {
+		calendar string
+		want     []string
+	}{
+		// Exact IDs beat every pattern, and all routes for the ID are used
+		{"ceo@example.com", []string{"exec", "assistant"}},
+		{"dana@example.com", []string{"staff"}},
+		// The longest matching pattern wins
+		{"eng-backend@group.calendar.google.com", []string{"eng"}},
+		{"design@group.calendar.google.com", []string{"teams"}},
+		{"primary", []string{"everyone"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, route := range matchRoutes(routes, tt.calendar) {
+			got = append(got, route.Target)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: routed to %v, want %v", tt.calendar, got, tt.want)
+		}
+	}
+
+	if got := matchRoutes(routes[4:6], "other@example.com"); got != nil {
+		t.Errorf("expected no route, got %v", got)
+	}
+}
+
+func TestSendApprovalRequestFollowsRoutes(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{}
+	cfg.Approval.Routes = []config.ApprovalRoute{
+		{Calendar: "ceo@example.com", Provider: "telegram", Target: "-100exec"},
+		{Calendar: "*@group.calendar.google.com", Provider: "ntfy", Target: "teams"},
+	}
+	mgr := NewManager(db, cfg)
+	telegram := &escalatingProvider{recordingProvider: recordingProvider{name: "telegram"}}
+	ntfy := &escalatingProvider{recordingProvider: recordingProvider{name: "ntfy"}}
+	plain := &recordingProvider{name: "plain"}
+	mgr.RegisterProvider(telegram)
+	mgr.RegisterProvider(ntfy)
+	mgr.RegisterProvider(plain)
+
+	send := func(id string, calendars ...string) {
+		t.Helper()
+		notification := &ApprovalNotification{RequestID: id, Calendars: calendars, Force: true}
+		if err := mgr.SendApprovalRequest(context.Background(), notification); err != nil {
+			t.Fatalf("SendApprovalRequest(%s): %v", id, err)
+		}
+	}
+
+	// Routed calendars only reach their route's recipient
+	send("req_ceo", "ceo@example.com")
+	// A calendar without a route falls back to every provider
+	send("req_own", "primary")
+	// A batch reaches each calendar's route, plus everyone for the unrouted one
+	send("req_batch", "ceo@example.com", "eng@group.calendar.google.com", "primary")
+
+	if want := []string{"req_ceo@-100exec", "req_batch@-100exec"}; !reflect.DeepEqual(telegram.recipients, want) {
+		t.Errorf("telegram routed = %v, want %v", telegram.recipients, want)
+	}
+	if want := []string{"req_batch@teams"}; !reflect.DeepEqual(ntfy.recipients, want) {
+		t.Errorf("ntfy routed = %v, want %v", ntfy.recipients, want)
+	}
+	for name, sent := range map[string][]string{"telegram": telegram.sent, "ntfy": ntfy.sent, "plain": plain.sent} {
+		if want := []string{"req_own", "req_batch"}; !reflect.DeepEqual(sent, want) {
+			t.Errorf("%s usual recipient got %v, want %v", name, sent, want)
+		}
+	}
+}
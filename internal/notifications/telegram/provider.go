@@ -13,15 +13,23 @@ import (
 
 	"github.com/dtorcivia/schedlock/internal/config"
 	"github.com/dtorcivia/schedlock/internal/notifications"
+	"github.com/dtorcivia/schedlock/internal/util"
 )
 
 const telegramAPIBase = "https://api.telegram.org/bot"
 
+// denyReasonAction is the callback action for preset deny-reason buttons.
+const denyReasonAction = "denyr"
+
 // Provider implements Telegram notifications with inline keyboards.
 type Provider struct {
 	config  *config.TelegramConfig
 	client  *http.Client
 	baseURL string
+
+	// escalationChatID may also press approval buttons, since escalated
+	// requests are sent there.
+	escalationChatID string
 }
 
 // NewProvider creates a new Telegram provider.
@@ -83,9 +91,105 @@ type messageResult struct {
 	MessageID int64 `json:"message_id"`
 }
 
+// SetEscalationChatID allows button presses from the chat that receives
+// escalated approval requests.
+func (p *Provider) SetEscalationChatID(chatID string) {
+	p.escalationChatID = chatID
+}
+
 // SendApproval sends an approval request notification with inline keyboard.
 func (p *Provider) SendApproval(ctx context.Context, notification *notifications.ApprovalNotification) (string, error) {
+	return p.SendApprovalTo(ctx, notification, p.config.ChatID)
+}
+
+// SendApprovalTo sends an approval request notification with inline keyboard
+// to another chat. Buttons there only work if the chat was allowed with
+// SetEscalationChatID.
+func (p *Provider) SendApprovalTo(ctx context.Context, notification *notifications.ApprovalNotification, chatID string) (string, error) {
+	text, keyboard := p.approvalMessage(notification)
+
+	req := sendMessageRequest{
+		ChatID:      chatID,
+		Text:        text,
+		ParseMode:   "MarkdownV2",
+		ReplyMarkup: keyboard,
+	}
+
+	return p.sendMessage(ctx, &req)
+}
+
+// RenderApproval returns the approval text and inline keyboard without
+// sending them.
+func (p *Provider) RenderApproval(notification *notifications.ApprovalNotification) *notifications.RenderedApproval {
+	text, keyboard := p.approvalMessage(notification)
+	return &notifications.RenderedApproval{
+		Provider: p.Name(),
+		Format:   "markdown_v2",
+		Body:     text,
+		Markup:   keyboard,
+	}
+}
+
+// approvalMessage builds the MarkdownV2 approval text and its buttons.
+func (p *Provider) approvalMessage(notification *notifications.ApprovalNotification) (string, *InlineKeyboardMarkup) {
 	var text strings.Builder
+	if notification.MessageTemplate != "" {
+		body, err := notifications.RenderApprovalTemplate(notification.MessageTemplate, notification, markdownMarkup)
+		if err == nil {
+			text.WriteString(body)
+		} else {
+			util.Warn("Telegram message template failed, using default message", "error", err)
+		}
+	}
+	if text.Len() == 0 {
+		writeDefaultApproval(&text, notification)
+	}
+
+	// Create inline keyboard with approve/deny buttons
+	keyboard := &InlineKeyboardMarkup{
+		InlineKeyboard: [][]InlineKeyboardButton{
+			{
+				{Text: "Approve", CallbackData: fmt.Sprintf("approve:%s", notification.RequestID)},
+				{Text: "Deny", CallbackData: fmt.Sprintf("deny:%s", notification.RequestID)},
+			},
+		},
+	}
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, p.denyReasonRows(notification.RequestID)...)
+	// Add link to public approval page (works without login)
+	if notification.ApprovePageURL != "" {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []InlineKeyboardButton{
+			{Text: "View Details", URL: notification.ApprovePageURL},
+		})
+	} else if notification.WebURL != "" {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []InlineKeyboardButton{
+			{Text: "View Details", URL: notification.WebURL},
+		})
+	}
+
+	return text.String(), keyboard
+}
+
+// denyReasonRows builds the preset deny-reason buttons, two per row. The
+// callback data carries the preset's index rather than its text to stay
+// within Telegram's 64-byte callback data limit.
+func (p *Provider) denyReasonRows(requestID string) [][]InlineKeyboardButton {
+	var rows [][]InlineKeyboardButton
+	for i, reason := range p.config.DenyReasons {
+		button := InlineKeyboardButton{
+			Text:         "Deny: " + reason,
+			CallbackData: fmt.Sprintf("%s:%d:%s", denyReasonAction, i, requestID),
+		}
+		if i%2 == 0 {
+			rows = append(rows, []InlineKeyboardButton{button})
+		} else {
+			rows[len(rows)-1] = append(rows[len(rows)-1], button)
+		}
+	}
+	return rows
+}
+
+// writeDefaultApproval writes the built-in approval message.
+func writeDefaultApproval(text *strings.Builder, notification *notifications.ApprovalNotification) {
 	text.WriteString(fmt.Sprintf("*%s*\n\n", escapeMarkdown(notification.Summary)))
 	text.WriteString(fmt.Sprintf("*Operation:* %s\n", escapeMarkdown(notification.Operation)))
 
@@ -94,7 +198,7 @@ func (p *Provider) SendApproval(ctx context.Context, notification *notifications
 			text.WriteString(fmt.Sprintf("*Event:* %s\n", escapeMarkdown(notification.Details.Title)))
 		}
 		if !notification.Details.StartTime.IsZero() {
-			text.WriteString(fmt.Sprintf("*When:* %s\n", notification.Details.StartTime.Format("Mon Jan 2, 3:04 PM")))
+			text.WriteString(fmt.Sprintf("*When:* %s\n", notifications.FormatEventTime(notification.Details.StartTime)))
 		}
 		if notification.Details.Location != "" {
 			text.WriteString(fmt.Sprintf("*Where:* %s\n", escapeMarkdown(notification.Details.Location)))
@@ -102,6 +206,12 @@ func (p *Provider) SendApproval(ctx context.Context, notification *notifications
 		if len(notification.Details.Attendees) > 0 {
 			text.WriteString(fmt.Sprintf("*Attendees:* %s\n", escapeMarkdown(strings.Join(notification.Details.Attendees, ", "))))
 		}
+		if len(notification.Details.OptionalAttendees) > 0 {
+			text.WriteString(fmt.Sprintf("*Optional:* %s\n", escapeMarkdown(strings.Join(notification.Details.OptionalAttendees, ", "))))
+		}
+		if len(notification.Details.Calendars) > 0 {
+			text.WriteString(fmt.Sprintf("*Calendars:* %s\n", escapeMarkdown(strings.Join(notification.Details.Calendars, ", "))))
+		}
 		if notification.Details.Description != "" {
 			desc := notification.Details.Description
 			if len(desc) > 200 {
@@ -111,38 +221,17 @@ func (p *Provider) SendApproval(ctx context.Context, notification *notifications
 		}
 	}
 
-	text.WriteString(fmt.Sprintf("\n*Expires:* %s\n", notification.ExpiresIn))
-	text.WriteString(fmt.Sprintf("\n_Request ID: %s_", notification.RequestID))
+	text.WriteString(fmt.Sprintf("\n*Expires:* %s\n", escapeMarkdown(notification.ExpiresIn)))
+	text.WriteString(fmt.Sprintf("\n_Request ID: %s_", escapeMarkdown(notification.RequestID)))
 	text.WriteString("\n\nReply to this message to suggest changes")
+}
 
-	// Create inline keyboard with approve/deny buttons
-	keyboard := &InlineKeyboardMarkup{
-		InlineKeyboard: [][]InlineKeyboardButton{
-			{
-				{Text: "Approve", CallbackData: fmt.Sprintf("approve:%s", notification.RequestID)},
-				{Text: "Deny", CallbackData: fmt.Sprintf("deny:%s", notification.RequestID)},
-			},
-		},
-	}
-	// Add link to public approval page (works without login)
-	if notification.ApprovePageURL != "" {
-		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []InlineKeyboardButton{
-			{Text: "View Details", URL: notification.ApprovePageURL},
-		})
-	} else if notification.WebURL != "" {
-		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []InlineKeyboardButton{
-			{Text: "View Details", URL: notification.WebURL},
-		})
-	}
-
-	req := sendMessageRequest{
-		ChatID:      p.config.ChatID,
-		Text:        text.String(),
-		ParseMode:   "MarkdownV2",
-		ReplyMarkup: keyboard,
-	}
-
-	return p.sendMessage(ctx, &req)
+// markdownMarkup renders message templates as MarkdownV2, escaping all
+// literal text and values so a template cannot break message parsing.
+var markdownMarkup = notifications.TemplateMarkup{
+	Escape: escapeMarkdown,
+	Bold:   func(s string) string { return "*" + s + "*" },
+	Italic: func(s string) string { return "_" + s + "_" },
 }
 
 // SendResult sends a result notification.
@@ -203,6 +292,23 @@ func (p *Provider) SendTest(ctx context.Context) error {
 	return err
 }
 
+// SendAlert sends an admin alert.
+func (p *Provider) SendAlert(ctx context.Context, alert *notifications.AlertNotification) error {
+	text := fmt.Sprintf("*%s*\n\n%s", escapeMarkdown(alert.Title), escapeMarkdown(alert.Message))
+	if alert.URL != "" {
+		text += "\n\n" + escapeMarkdown(alert.URL)
+	}
+
+	req := sendMessageRequest{
+		ChatID:    p.config.ChatID,
+		Text:      text,
+		ParseMode: "MarkdownV2",
+	}
+
+	_, err := p.sendMessage(ctx, &req)
+	return err
+}
+
 // RemoveKeyboard removes the inline keyboard from a message.
 func (p *Provider) RemoveKeyboard(ctx context.Context, messageID int64, status string) error {
 	emoji := "OK"
@@ -3,6 +3,7 @@ package telegram
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -22,10 +23,10 @@ type Update struct {
 
 // Message represents a Telegram message.
 type Message struct {
-	MessageID      int64   `json:"message_id"`
-	From           *User   `json:"from,omitempty"`
-	Chat           *Chat   `json:"chat"`
-	Text           string  `json:"text,omitempty"`
+	MessageID      int64    `json:"message_id"`
+	From           *User    `json:"from,omitempty"`
+	Chat           *Chat    `json:"chat"`
+	Text           string   `json:"text,omitempty"`
 	ReplyToMessage *Message `json:"reply_to_message,omitempty"`
 }
 
@@ -85,6 +86,11 @@ func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
 		util.Error("Failed to read webhook body", "error", err)
 		http.Error(w, "Failed to read body", http.StatusBadRequest)
 		return
@@ -153,6 +159,18 @@ func (h *WebhookHandler) handleCallbackQuery(ctx context.Context, query *Callbac
 		return
 	}
 
+	// Resolve preset deny reasons: "denyr:index:request_id"
+	var reason string
+	if action == denyReasonAction {
+		var err error
+		if reason, requestID, err = h.parseDenyReason(requestID); err != nil {
+			util.Warn("Invalid deny reason callback", "data", query.Data, "error", err)
+			h.answerCallbackQuery(ctx, query.ID, "This deny reason is no longer available. Use Deny instead.")
+			return
+		}
+		action = "deny"
+	}
+
 	// Only allow approve/deny actions
 	if action != "approve" && action != "deny" {
 		util.Warn("Unknown callback action", "action", action)
@@ -174,6 +192,7 @@ func (h *WebhookHandler) handleCallbackQuery(ctx context.Context, query *Callbac
 		Provider:    "telegram",
 		RequestID:   requestID,
 		Action:      action,
+		Reason:      reason,
 		RespondedBy: respondedBy,
 	}
 
@@ -201,10 +220,26 @@ func (h *WebhookHandler) handleCallbackQuery(ctx context.Context, query *Callbac
 	util.Info("Processed Telegram callback",
 		"action", action,
 		"request_id", requestID,
+		"reason", reason,
 		"responded_by", respondedBy,
 	)
 }
 
+// parseDenyReason splits "index:request_id" from a deny-reason button and
+// looks up the preset. Presets are matched by index, so a button sent before
+// the list was shortened is rejected rather than mapped to the wrong reason.
+func (h *WebhookHandler) parseDenyReason(data string) (reason, requestID string, err error) {
+	parts := strings.SplitN(data, ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("missing request ID")
+	}
+	index, err := strconv.Atoi(parts[0])
+	if err != nil || index < 0 || index >= len(h.provider.config.DenyReasons) {
+		return "", "", fmt.Errorf("unknown deny reason %q", parts[0])
+	}
+	return h.provider.config.DenyReasons[index], parts[1], nil
+}
+
 // handleReply processes message replies as suggestions.
 func (h *WebhookHandler) handleReply(ctx context.Context, msg *Message) {
 	// Find the original notification by message ID
@@ -264,7 +299,8 @@ func (h *WebhookHandler) isAllowedChat(chatID int64) bool {
 	if h.provider == nil || h.provider.config == nil || h.provider.config.ChatID == "" {
 		return false
 	}
-	return fmt.Sprintf("%d", chatID) == h.provider.config.ChatID
+	id := fmt.Sprintf("%d", chatID)
+	return id == h.provider.config.ChatID || (h.provider.escalationChatID != "" && id == h.provider.escalationChatID)
 }
 
 // answerCallbackQuery acknowledges a callback query.
@@ -0,0 +1,83 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/notifications"
+)
+
+func TestDenyReasonButtons(t *testing.T) {
+	provider := NewProvider(&config.TelegramConfig{
+		ChatID:      "42",
+		DenyReasons: []string{"conflict", "wrong attendees", "too early"},
+	})
+	handler := &WebhookHandler{provider: provider}
+
+	rows := provider.denyReasonRows("req_abc")
+	if len(rows) != 2 || len(rows[0]) != 2 || len(rows[1]) != 1 {
+		t.Fatalf("unexpected layout: %+v", rows)
+	}
+
+	for i, want := range []string{"conflict", "wrong attendees", "too early"} {
+		button := rows[i/2][i%2]
+		if button.Text != "Deny: "+want {
+			t.Errorf("button %d text = %q", i, button.Text)
+		}
+		if len(button.CallbackData) > 64 {
+			t.Errorf("button %d callback data exceeds 64 bytes", i)
+		}
+
+		// Callback data is "denyr:index:request_id"; the handler receives
+		// everything after the action
+		var index int
+		var requestID string
+		if _, err := fmt.Sscanf(button.CallbackData, denyReasonAction+":%d:%s", &index, &requestID); err != nil {
+			t.Fatalf("parse callback data %q: %v", button.CallbackData, err)
+		}
+		reason, gotID, err := handler.parseDenyReason(fmt.Sprintf("%d:%s", index, requestID))
+		if err != nil {
+			t.Fatalf("parseDenyReason: %v", err)
+		}
+		if reason != want || gotID != "req_abc" {
+			t.Errorf("got (%q, %q), want (%q, req_abc)", reason, gotID, want)
+		}
+	}
+
+	if _, _, err := handler.parseDenyReason("7:req_abc"); err == nil {
+		t.Error("expected error for unknown preset index")
+	}
+}
+
+func TestRenderApprovalMatchesSentMessage(t *testing.T) {
+	provider := NewProvider(&config.TelegramConfig{ChatID: "42"})
+	notification := &notifications.ApprovalNotification{
+		RequestID: "req_abc",
+		Operation: "create_event",
+		Summary:   "Sync v1.2 (team-wide)",
+		ExpiresIn: "in 1h",
+		WebURL:    "https://schedlock.example.com/requests/req_abc",
+	}
+	rendered := provider.RenderApproval(notification)
+
+	if rendered.Provider != "telegram" || rendered.Format != "markdown_v2" {
+		t.Errorf("unexpected provider/format: %s/%s", rendered.Provider, rendered.Format)
+	}
+	if !strings.Contains(rendered.Body, `*Sync v1\.2 \(team\-wide\)*`) {
+		t.Errorf("summary not escaped for MarkdownV2:\n%s", rendered.Body)
+	}
+
+	text, keyboard := provider.approvalMessage(notification)
+	if text != rendered.Body {
+		t.Error("rendered body differs from the message SendApproval sends")
+	}
+	markup, ok := rendered.Markup.(*InlineKeyboardMarkup)
+	if !ok || len(markup.InlineKeyboard) != len(keyboard.InlineKeyboard) {
+		t.Fatalf("unexpected markup: %#v", rendered.Markup)
+	}
+	if markup.InlineKeyboard[0][0].CallbackData != "approve:req_abc" {
+		t.Errorf("approve button = %+v", markup.InlineKeyboard[0][0])
+	}
+}
@@ -0,0 +1,182 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+// MaxTemplateLength caps custom approval message templates.
+const MaxTemplateLength = 2000
+
+// TemplateData is the field set available to approval message templates,
+// e.g. "{{.Summary}} on {{.Start}} (expires in {{.ExpiresIn}})".
+type TemplateData struct {
+	Summary   string // one-line request summary
+	Operation string // create_event, update_event, ...
+	Priority  string // low, normal, high
+	RequestID string
+	Title     string // event title
+	Start     string // formatted start time, empty if none
+	End       string // formatted end time, empty if none
+	Location  string
+	Attendees string // comma-separated
+	Optional  string // comma-separated optional attendees
+	Calendar  string // target calendar; comma-separated for batch creates
+	ExpiresIn string // e.g. "15m"
+	ExpiresAt string // formatted expiry time
+}
+
+// TemplateMarkup adapts a template to a provider's text format. Escape is
+// applied to literal template text and to every field value, so a template
+// can never produce invalid markup; formatting is only available through
+// the bold and italic functions.
+type TemplateMarkup struct {
+	Escape func(string) string
+	Bold   func(string) string
+	Italic func(string) string
+}
+
+// templateTimeFormat matches the times in the built-in messages.
+const templateTimeFormat = "Mon Jan 2, 3:04 PM"
+
+// FormatEventTime formats an event start for the built-in messages in the
+// display locale, with the relative time in relative display mode. The
+// event's own timezone is kept.
+func FormatEventTime(t time.Time) string {
+	if f := util.GetDefaultFormatter(); f != nil {
+		return f.FormatEventTime(t, templateTimeFormat)
+	}
+	return t.Format(templateTimeFormat)
+}
+
+// formatLayout formats a time for the built-in messages in the display
+// locale.
+func formatLayout(t time.Time) string {
+	if f := util.GetDefaultFormatter(); f != nil {
+		return f.FormatLayout(t, templateTimeFormat)
+	}
+	return t.Format(templateTimeFormat)
+}
+
+// ValidateMessageTemplate checks that a template parses, stays within the
+// length limit and only uses known fields.
+func ValidateMessageTemplate(text string) error {
+	if len(text) > MaxTemplateLength {
+		return fmt.Errorf("template exceeds %d characters", MaxTemplateLength)
+	}
+	sample := &ApprovalNotification{
+		RequestID: "req_sample",
+		Operation: "create_event",
+		Priority:  "normal",
+		Summary:   "Create: Sample event",
+		ExpiresIn: "15m",
+		ExpiresAt: time.Now(),
+		Details: &EventDetails{
+			Title:     "Sample event",
+			StartTime: time.Now(),
+			EndTime:   time.Now().Add(time.Hour),
+			Attendees: []string{"a@example.com"},
+		},
+	}
+	_, err := RenderApprovalTemplate(text, sample, TemplateMarkup{Escape: func(s string) string { return s }})
+	return err
+}
+
+// RenderApprovalTemplate renders an approval message from a custom template.
+func RenderApprovalTemplate(text string, n *ApprovalNotification, markup TemplateMarkup) (string, error) {
+	escape := markup.Escape
+	if escape == nil {
+		escape = func(s string) string { return s }
+	}
+	bold, italic := markup.Bold, markup.Italic
+	if bold == nil {
+		bold = func(s string) string { return s }
+	}
+	if italic == nil {
+		italic = func(s string) string { return s }
+	}
+
+	tmpl, err := template.New("message").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"bold": bold, "italic": italic}).
+		Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	if markup.Escape != nil {
+		escapeTextNodes(tmpl.Tree.Root, escape)
+	}
+
+	data := templateData(n)
+	for _, field := range []*string{
+		&data.Summary, &data.Operation, &data.Priority, &data.RequestID, &data.Title, &data.Start,
+		&data.End, &data.Location, &data.Attendees, &data.Optional, &data.Calendar, &data.ExpiresIn, &data.ExpiresAt,
+	} {
+		*field = escape(*field)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	return out.String(), nil
+}
+
+// escapeTextNodes escapes the literal text of a parsed template in place.
+func escapeTextNodes(node parse.Node, escape func(string) string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			escapeTextNodes(child, escape)
+		}
+	case *parse.TextNode:
+		n.Text = []byte(escape(string(n.Text)))
+	case *parse.IfNode:
+		escapeTextNodes(n.List, escape)
+		escapeTextNodes(n.ElseList, escape)
+	case *parse.RangeNode:
+		escapeTextNodes(n.List, escape)
+		escapeTextNodes(n.ElseList, escape)
+	case *parse.WithNode:
+		escapeTextNodes(n.List, escape)
+		escapeTextNodes(n.ElseList, escape)
+	}
+}
+
+func templateData(n *ApprovalNotification) TemplateData {
+	data := TemplateData{
+		Summary:   n.Summary,
+		Operation: n.Operation,
+		Priority:  n.Priority,
+		RequestID: n.RequestID,
+		ExpiresIn: n.ExpiresIn,
+	}
+	if !n.ExpiresAt.IsZero() {
+		data.ExpiresAt = formatLayout(n.ExpiresAt)
+	}
+	if d := n.Details; d != nil {
+		data.Title = d.Title
+		data.Location = d.Location
+		data.Attendees = strings.Join(d.Attendees, ", ")
+		data.Optional = strings.Join(d.OptionalAttendees, ", ")
+		data.Calendar = d.CalendarID
+		if len(d.Calendars) > 0 {
+			data.Calendar = strings.Join(d.Calendars, ", ")
+		}
+		if !d.StartTime.IsZero() {
+			data.Start = FormatEventTime(d.StartTime)
+		}
+		if !d.EndTime.IsZero() {
+			data.End = formatLayout(d.EndTime)
+		}
+	}
+	return data
+}
@@ -0,0 +1,48 @@
+package notifications
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderApprovalTemplate(t *testing.T) {
+	n := &ApprovalNotification{
+		RequestID: "req_1",
+		Summary:   "Create: Lunch",
+		ExpiresIn: "15m",
+		Details: &EventDetails{
+			Title:     "Lunch *with* Bob",
+			StartTime: time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	markup := TemplateMarkup{
+		Escape: func(s string) string { return strings.ReplaceAll(s, "*", `\*`) },
+		Bold:   func(s string) string { return "*" + s + "*" },
+	}
+
+	got, err := RenderApprovalTemplate("{{bold .Title}} * {{.Start}}", n, markup)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := `*Lunch \*with\* Bob* \* Mon Mar 2, 12:00 PM`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := RenderApprovalTemplate("{{.Nope}}", n, markup); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestValidateMessageTemplate(t *testing.T) {
+	if err := ValidateMessageTemplate("{{.Summary}} expires in {{.ExpiresIn}}"); err != nil {
+		t.Errorf("valid template rejected: %v", err)
+	}
+	if err := ValidateMessageTemplate("{{.Summary"); err == nil {
+		t.Error("expected parse error")
+	}
+	if err := ValidateMessageTemplate(strings.Repeat("a", MaxTemplateLength+1)); err == nil {
+		t.Error("expected length error")
+	}
+}
@@ -7,20 +7,47 @@ import (
 
 // ApprovalNotification contains data for sending approval notifications.
 type ApprovalNotification struct {
-	RequestID     string
-	Operation     string
-	Summary       string
-	Details       *EventDetails
-	ApproveURL    string // API callback URL (for background HTTP actions)
-	DenyURL       string // API callback URL (for background HTTP actions)
-	SuggestURL    string // API callback URL (for background HTTP actions)
+	RequestID      string
+	Operation      string
+	Priority       string // Request priority (low, normal, high)
+	Summary        string
+	Details        *EventDetails
+	ApproveURL     string // API callback URL (for background HTTP actions)
+	DenyURL        string // API callback URL (for background HTTP actions)
+	SuggestURL     string // API callback URL (for background HTTP actions)
 	ApprovePageURL string // Public web page URL (for browser links)
-	WebURL        string // Authenticated web UI URL
-	ExpiresAt     time.Time
-	ExpiresIn     string
-	DecisionToken string
+	WebURL         string // Authenticated web UI URL
+	ExpiresAt      time.Time
+	ExpiresIn      string
+	DecisionToken  string
+
+	// Calendars lists the calendars the request touches, used to pick
+	// approval routes.
+	Calendars []string
+
+	// MessageTemplate is the receiving provider's custom body template, set
+	// by the manager per provider. Empty means the built-in message.
+	MessageTemplate string
+
+	// Force sends to every provider, including those that already delivered
+	// an approval message for this request.
+	Force bool
 }
 
+// RenderedApproval is an approval message as a provider builds it, before
+// delivery. It is used to preview notifications.
+type RenderedApproval struct {
+	Provider string      `json:"provider"`
+	Format   string      `json:"format"` // how Body is interpreted: "text", "html", "markdown_v2" or "json"
+	Title    string      `json:"title,omitempty"`
+	Body     string      `json:"body"`
+	Markup   interface{} `json:"markup,omitempty"` // buttons, actions and links sent alongside the body
+}
+
+// PreviewDecisionToken stands in for the decision token in previewed
+// messages, so previews show the link layout without issuing a live token.
+const PreviewDecisionToken = "preview"
+
 // EventDetails contains human-readable event information.
 type EventDetails struct {
 	Title       string
@@ -29,19 +56,35 @@ type EventDetails struct {
 	Location    string
 	Attendees   []string
 	Description string
-	CalendarID  string
-	EventID     string // For updates/deletes
+
+	OptionalAttendees []string // Invited as optional guests
+	CalendarID        string
+	EventID           string   // For updates/deletes
+	Calendars         []string // Every target calendar, for batch creates
 }
 
 // ResultNotification contains data for result notifications.
 type ResultNotification struct {
-	RequestID  string
-	Operation  string
-	Status     string
-	Message    string
-	EventURL   string
-	Error      string
-	Result     json.RawMessage
+	RequestID string
+	Operation string
+	Status    string
+	Message   string
+	EventURL  string
+	Error     string
+	Result    json.RawMessage
+}
+
+// Alert kinds sent through SendAlert.
+const (
+	AlertOAuthUnhealthy = "oauth_unhealthy"
+)
+
+// AlertNotification is an admin-facing alert that is not tied to a request.
+type AlertNotification struct {
+	Kind    string // e.g. AlertOAuthUnhealthy
+	Title   string
+	Message string
+	URL     string // where the admin can fix the problem
 }
 
 // Callback represents an approval callback from a notification provider.
@@ -50,6 +93,7 @@ type Callback struct {
 	RequestID   string
 	Action      string // "approve", "deny", "suggest"
 	Suggestion  string
+	Reason      string // preset reason for a deny
 	MessageID   string
 	ChatID      string
 	RespondedBy string
@@ -49,16 +49,16 @@ func (p *Provider) Enabled() bool {
 
 // WebhookPayload is the JSON structure sent to the webhook.
 type WebhookPayload struct {
-	Event     string                 `json:"event"`
-	Timestamp string                 `json:"timestamp"`
-	RequestID string                 `json:"request_id"`
-	Operation string                 `json:"operation"`
-	Summary   string                 `json:"summary,omitempty"`
-	Status    string                 `json:"status,omitempty"`
-	Message   string                 `json:"message,omitempty"`
-	ExpiresAt string                 `json:"expires_at,omitempty"`
-	URLs      *WebhookURLs           `json:"urls,omitempty"`
-	Details   *WebhookEventDetails   `json:"details,omitempty"`
+	Event     string               `json:"event"`
+	Timestamp string               `json:"timestamp"`
+	RequestID string               `json:"request_id"`
+	Operation string               `json:"operation"`
+	Summary   string               `json:"summary,omitempty"`
+	Status    string               `json:"status,omitempty"`
+	Message   string               `json:"message,omitempty"`
+	ExpiresAt string               `json:"expires_at,omitempty"`
+	URLs      *WebhookURLs         `json:"urls,omitempty"`
+	Details   *WebhookEventDetails `json:"details,omitempty"`
 }
 
 // WebhookURLs contains the approval/deny URLs.
@@ -79,10 +79,28 @@ type WebhookEventDetails struct {
 	Description string   `json:"description,omitempty"`
 	CalendarID  string   `json:"calendar_id,omitempty"`
 	EventID     string   `json:"event_id,omitempty"`
+	Calendars   []string `json:"calendars,omitempty"`
+
+	OptionalAttendees []string `json:"optional_attendees,omitempty"`
 }
 
 // SendApproval sends an approval request notification.
 func (p *Provider) SendApproval(ctx context.Context, notification *notifications.ApprovalNotification) (string, error) {
+	return p.send(ctx, approvalPayload(notification))
+}
+
+// RenderApproval returns the JSON approval payload without sending it.
+func (p *Provider) RenderApproval(notification *notifications.ApprovalNotification) *notifications.RenderedApproval {
+	data, _ := json.MarshalIndent(approvalPayload(notification), "", "  ")
+	return &notifications.RenderedApproval{
+		Provider: p.Name(),
+		Format:   "json",
+		Body:     string(data),
+	}
+}
+
+// approvalPayload builds the webhook body for an approval request.
+func approvalPayload(notification *notifications.ApprovalNotification) WebhookPayload {
 	payload := WebhookPayload{
 		Event:     "approval_request",
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
@@ -106,6 +124,9 @@ func (p *Provider) SendApproval(ctx context.Context, notification *notifications
 			Description: notification.Details.Description,
 			CalendarID:  notification.Details.CalendarID,
 			EventID:     notification.Details.EventID,
+			Calendars:   notification.Details.Calendars,
+
+			OptionalAttendees: notification.Details.OptionalAttendees,
 		}
 		if !notification.Details.StartTime.IsZero() {
 			payload.Details.StartTime = notification.Details.StartTime.Format(time.RFC3339)
@@ -115,7 +136,7 @@ func (p *Provider) SendApproval(ctx context.Context, notification *notifications
 		}
 	}
 
-	return p.send(ctx, payload)
+	return payload
 }
 
 // SendResult sends a result notification.
@@ -148,6 +169,23 @@ func (p *Provider) SendTest(ctx context.Context) error {
 	return err
 }
 
+// SendAlert sends an admin alert as an "alert" event.
+func (p *Provider) SendAlert(ctx context.Context, alert *notifications.AlertNotification) error {
+	payload := WebhookPayload{
+		Event:     "alert",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Operation: alert.Kind,
+		Summary:   alert.Title,
+		Message:   alert.Message,
+	}
+	if alert.URL != "" {
+		payload.URLs = &WebhookURLs{Web: alert.URL}
+	}
+
+	_, err := p.send(ctx, payload)
+	return err
+}
+
 // send sends the payload to the webhook URL.
 func (p *Provider) send(ctx context.Context, payload WebhookPayload) (string, error) {
 	jsonData, err := json.Marshal(payload)
@@ -0,0 +1,30 @@
+package requests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// ContentHash fingerprints an operation and its payload for content
+// deduplication. The payload is decoded and re-encoded first, so key order
+// and whitespace do not change the hash; values are compared exactly.
+func ContentHash(operation string, payload json.RawMessage) (string, error) {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber() // keep large integers and number formatting intact
+	if err := decoder.Decode(&value); err != nil {
+		return "", err
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.New()
+	sum.Write([]byte(operation))
+	sum.Write([]byte{0})
+	sum.Write(normalized)
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
@@ -0,0 +1,91 @@
+package requests
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+func TestContentHashNormalization(t *testing.T) {
+	base, err := ContentHash("create_event", json.RawMessage(`{"summary":"Standup","start":"2026-03-02T09:00:00Z","attendees":["a@example.com"]}`))
+	if err != nil {
+		t.Fatalf("ContentHash: %v", err)
+	}
+
+	same := []string{
+		`{"attendees":["a@example.com"],"start":"2026-03-02T09:00:00Z","summary":"Standup"}`,
+		"{\n  \"summary\": \"Standup\",\n  \"start\": \"2026-03-02T09:00:00Z\",\n  \"attendees\": [\"a@example.com\"]\n}",
+	}
+	for _, payload := range same {
+		if got, _ := ContentHash("create_event", json.RawMessage(payload)); got != base {
+			t.Errorf("hash of %s differs from the original", payload)
+		}
+	}
+
+	different := map[string]string{
+		"value":     `{"summary":"Standup!","start":"2026-03-02T09:00:00Z","attendees":["a@example.com"]}`,
+		"attendees": `{"summary":"Standup","start":"2026-03-02T09:00:00Z","attendees":["a@example.com","b@example.com"]}`,
+	}
+	for name, payload := range different {
+		if got, _ := ContentHash("create_event", json.RawMessage(payload)); got == base {
+			t.Errorf("changed %s hashed the same", name)
+		}
+	}
+
+	if got, _ := ContentHash("update_event", json.RawMessage(`{"summary":"Standup","start":"2026-03-02T09:00:00Z","attendees":["a@example.com"]}`)); got == base {
+		t.Error("a different operation hashed the same")
+	}
+	if _, err := ContentHash("create_event", json.RawMessage(`{not json`)); err == nil {
+		t.Error("expected malformed payload to fail")
+	}
+}
+
+func TestFindByContentHash(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`INSERT INTO api_keys (id, key_hash, key_prefix, name, tier) VALUES ('key1', 'hash', 'sk_test', 'Test', 'write')`); err != nil {
+		t.Fatalf("Failed to insert api key: %v", err)
+	}
+
+	payload := `{"summary":"Standup"}`
+	now := time.Now()
+	insert := func(id, status string, age time.Duration) {
+		t.Helper()
+		_, err := db.Exec(`
+			INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status, created_at)
+			VALUES (?, 'key1', 'create_event', ?, ?, ?, ?)
+		`, id, payload, util.SQLiteTimestamp(now.Add(time.Hour)), status, util.SQLiteTimestamp(now.Add(-age)))
+		if err != nil {
+			t.Fatalf("Failed to insert request: %v", err)
+		}
+	}
+	insert("req_old", database.StatusPendingApproval, time.Hour)
+	insert("req_denied", database.StatusDenied, time.Minute)
+	insert("req_recent", database.StatusPendingApproval, 2*time.Minute)
+
+	repo := NewRepository(db)
+	hash, _ := ContentHash("create_event", json.RawMessage(`{ "summary": "Standup" }`))
+
+	found, err := repo.FindByContentHash(ctx, "key1", "create_event", hash, now.Add(-10*time.Minute))
+	if err != nil {
+		t.Fatalf("FindByContentHash: %v", err)
+	}
+	if found == nil || found.ID != "req_recent" {
+		t.Fatalf("found %+v, want req_recent", found)
+	}
+
+	found, err = repo.FindByContentHash(ctx, "key2", "create_event", hash, now.Add(-10*time.Minute))
+	if err != nil || found != nil {
+		t.Errorf("another key's request matched: %+v, %v", found, err)
+	}
+}
@@ -25,29 +25,89 @@ func NewRepository(db *database.DB) *Repository {
 
 // CreateRequest contains the data needed to create a new request.
 type CreateRequest struct {
-	APIKeyID    string
-	Operation   string
-	Payload     json.RawMessage
-	ExpiresAt   time.Time
+	APIKeyID  string
+	Operation string
+	Payload   json.RawMessage
+	ExpiresAt time.Time
+	Priority  string
+	Context   string
+	Tags      []string
 }
 
 // Create stores a new request.
 func (r *Repository) Create(ctx context.Context, req *CreateRequest) (*database.Request, error) {
+	id, err := insertRequest(ctx, r.db, req)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// CreateWithinLimit stores a new request unless the key already has
+// maxPending requests awaiting approval. The count and the insert share one
+// write transaction, so concurrent submissions cannot both slip under the
+// limit. When the limit is reached nothing is stored and the returned
+// request is nil. The count of pending requests is returned either way.
+func (r *Repository) CreateWithinLimit(ctx context.Context, req *CreateRequest, maxPending int) (*database.Request, int, error) {
+	var id string
+	var pending int
+	err := r.db.ImmediateTx(ctx, func(conn *sql.Conn) error {
+		// The api_key_id index keeps this to a scan of the key's own requests
+		if err := conn.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM requests INDEXED BY idx_requests_api_key
+			WHERE api_key_id = ? AND status = ?
+		`, req.APIKeyID, database.StatusPendingApproval).Scan(&pending); err != nil {
+			return fmt.Errorf("failed to count pending requests: %w", err)
+		}
+		if pending >= maxPending {
+			return nil
+		}
+
+		var err error
+		id, err = insertRequest(ctx, conn, req)
+		return err
+	})
+	if err != nil || id == "" {
+		return nil, pending, err
+	}
+
+	created, err := r.GetByID(ctx, id)
+	return created, pending, err
+}
+
+// execer is satisfied by both the connection pool and a single connection.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertRequest writes a new pending request and returns its ID.
+func insertRequest(ctx context.Context, db execer, req *CreateRequest) (string, error) {
 	id, err := crypto.GenerateRequestID()
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate request ID: %w", err)
+		return "", fmt.Errorf("failed to generate request ID: %w", err)
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = database.PriorityNormal
+	}
+
+	tags := "[]"
+	if len(req.Tags) > 0 {
+		encoded, _ := json.Marshal(req.Tags)
+		tags = string(encoded)
 	}
 
-	_, err = r.db.ExecContext(ctx, `
-		INSERT INTO requests (id, api_key_id, operation, status, payload, expires_at)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`, id, req.APIKeyID, req.Operation, database.StatusPendingApproval, string(req.Payload), util.SQLiteTimestamp(req.ExpiresAt))
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO requests (id, api_key_id, operation, status, payload, expires_at, priority, context, tags)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, id, req.APIKeyID, req.Operation, database.StatusPendingApproval, string(req.Payload), util.SQLiteTimestamp(req.ExpiresAt), priority, req.Context, tags)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert request: %w", err)
+		return "", fmt.Errorf("failed to insert request: %w", err)
 	}
 
-	return r.GetByID(ctx, id)
+	return id, nil
 }
 
 // GetByID retrieves a request by its ID.
@@ -56,7 +116,7 @@ func (r *Repository) GetByID(ctx context.Context, id string) (*database.Request,
 		SELECT id, api_key_id, operation, status, payload, result, error,
 		       suggestion_text, suggestion_at, suggestion_by,
 		       created_at, expires_at, decided_at, decided_by,
-		       executed_at, retry_count, webhook_notified_at
+		       executed_at, retry_count, manual_retry_count, webhook_notified_at, priority, context, tags
 		FROM requests
 		WHERE id = ?
 	`, id)
@@ -74,7 +134,7 @@ func (r *Repository) GetByAPIKeyID(ctx context.Context, apiKeyID string, limit i
 		SELECT id, api_key_id, operation, status, payload, result, error,
 		       suggestion_text, suggestion_at, suggestion_by,
 		       created_at, expires_at, decided_at, decided_by,
-		       executed_at, retry_count, webhook_notified_at
+		       executed_at, retry_count, manual_retry_count, webhook_notified_at, priority, context, tags
 		FROM requests
 		WHERE api_key_id = ?
 		ORDER BY created_at DESC
@@ -89,13 +149,39 @@ func (r *Repository) GetByAPIKeyID(ctx context.Context, apiKeyID string, limit i
 	return scanRequests(rows)
 }
 
+// GetByAPIKeyIDAndTag retrieves an API key's requests carrying the given tag.
+func (r *Repository) GetByAPIKeyIDAndTag(ctx context.Context, apiKeyID, tag string, limit int) ([]database.Request, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, api_key_id, operation, status, payload, result, error,
+		       suggestion_text, suggestion_at, suggestion_by,
+		       created_at, expires_at, decided_at, decided_by,
+		       executed_at, retry_count, manual_retry_count, webhook_notified_at, priority, context, tags
+		FROM requests
+		WHERE api_key_id = ?
+		  AND EXISTS (SELECT 1 FROM json_each(requests.tags) WHERE value = ?)
+		ORDER BY created_at DESC
+		LIMIT ?
+	`, apiKeyID, tag, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query requests: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRequests(rows)
+}
+
 // GetPending retrieves all pending requests.
 func (r *Repository) GetPending(ctx context.Context) ([]database.Request, error) {
-	rows, err := r.db.QueryContext(ctx, `
+	rows, err := r.db.Reader().QueryContext(ctx, `
 		SELECT id, api_key_id, operation, status, payload, result, error,
 		       suggestion_text, suggestion_at, suggestion_by,
 		       created_at, expires_at, decided_at, decided_by,
-		       executed_at, retry_count, webhook_notified_at
+		       executed_at, retry_count, manual_retry_count, webhook_notified_at, priority, context, tags
 		FROM requests
 		WHERE status = ?
 		ORDER BY created_at ASC
@@ -115,7 +201,7 @@ func (r *Repository) GetExpired(ctx context.Context) ([]database.Request, error)
 		SELECT id, api_key_id, operation, status, payload, result, error,
 		       suggestion_text, suggestion_at, suggestion_by,
 		       created_at, expires_at, decided_at, decided_by,
-		       executed_at, retry_count, webhook_notified_at
+		       executed_at, retry_count, manual_retry_count, webhook_notified_at, priority, context, tags
 		FROM requests
 		WHERE status = ? AND expires_at < datetime('now')
 	`, database.StatusPendingApproval)
@@ -128,6 +214,99 @@ func (r *Repository) GetExpired(ctx context.Context) ([]database.Request, error)
 	return scanRequests(rows)
 }
 
+// GetNearingExpiry retrieves pending requests that expire within the given window,
+// were created at least minAge ago, and have not been reminded yet.
+func (r *Repository) GetNearingExpiry(ctx context.Context, window, minAge time.Duration) ([]database.Request, error) {
+	now := time.Now().UTC()
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, api_key_id, operation, status, payload, result, error,
+		       suggestion_text, suggestion_at, suggestion_by,
+		       created_at, expires_at, decided_at, decided_by,
+		       executed_at, retry_count, manual_retry_count, webhook_notified_at, priority, context, tags
+		FROM requests
+		WHERE status = ?
+		  AND reminded_at IS NULL
+		  AND expires_at > ?
+		  AND expires_at <= ?
+		  AND created_at <= ?
+		ORDER BY expires_at ASC
+	`, database.StatusPendingApproval,
+		util.SQLiteTimestamp(now),
+		util.SQLiteTimestamp(now.Add(window)),
+		util.SQLiteTimestamp(now.Add(-minAge)))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query requests nearing expiry: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRequests(rows)
+}
+
+// GetUnescalated retrieves pending requests created at least delay ago that
+// have not been escalated yet.
+func (r *Repository) GetUnescalated(ctx context.Context, delay time.Duration) ([]database.Request, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, api_key_id, operation, status, payload, result, error,
+		       suggestion_text, suggestion_at, suggestion_by,
+		       created_at, expires_at, decided_at, decided_by,
+		       executed_at, retry_count, manual_retry_count, webhook_notified_at, priority, context, tags
+		FROM requests
+		WHERE status = ?
+		  AND escalated_at IS NULL
+		  AND created_at <= ?
+		  AND expires_at > ?
+		ORDER BY created_at ASC
+	`, database.StatusPendingApproval,
+		util.SQLiteTimestamp(time.Now().Add(-delay)),
+		util.SQLiteTimestamp(time.Now()))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query requests to escalate: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRequests(rows)
+}
+
+// MarkEscalated records that a request was escalated.
+// Returns false if the request was already escalated or is no longer pending.
+func (r *Repository) MarkEscalated(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE requests
+		SET escalated_at = datetime('now')
+		WHERE id = ? AND status = ? AND escalated_at IS NULL
+	`, id, database.StatusPendingApproval)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark request escalated: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// MarkReminded records that an expiry reminder was sent.
+// Returns false if the request was already reminded or is no longer pending.
+func (r *Repository) MarkReminded(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE requests
+		SET reminded_at = datetime('now')
+		WHERE id = ? AND status = ? AND reminded_at IS NULL
+	`, id, database.StatusPendingApproval)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark request reminded: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
 // UpdateStatus atomically updates a request's status.
 // Returns true if the update succeeded, false if the request was already transitioned.
 func (r *Repository) UpdateStatus(ctx context.Context, id, newStatus, decidedBy string) (bool, error) {
@@ -255,6 +434,24 @@ func (r *Repository) IncrementRetryCount(ctx context.Context, id string) error {
 	return err
 }
 
+// RetryFailed moves a failed request back to approved for another run,
+// clearing its error and automatic retry count and counting the manual
+// retry. Returns false if the request is not failed.
+func (r *Repository) RetryFailed(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE requests
+		SET status = ?, error = NULL, retry_count = 0, manual_retry_count = manual_retry_count + 1
+		WHERE id = ? AND status = ?
+	`, database.StatusApproved, id, database.StatusFailed)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to retry request: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
 // SetWebhookNotified marks the webhook as sent.
 func (r *Repository) SetWebhookNotified(ctx context.Context, id string) error {
 	_, err := r.db.ExecContext(ctx, `
@@ -266,6 +463,33 @@ func (r *Repository) SetWebhookNotified(ctx context.Context, id string) error {
 	return err
 }
 
+// MarkResultFetched records that the caller has read a completed request,
+// which exempts it from the unclaimed results cleanup. Only the first read
+// is recorded; other statuses are left unmarked.
+func (r *Repository) MarkResultFetched(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE requests
+		SET result_fetched_at = datetime('now')
+		WHERE id = ? AND status = ? AND result_fetched_at IS NULL
+	`, id, database.StatusCompleted)
+
+	return err
+}
+
+// NextWebhookSequence increments and returns the request's webhook sequence
+// number. It is stored with the request so numbering survives restarts.
+func (r *Repository) NextWebhookSequence(ctx context.Context, id string) (int64, error) {
+	var seq int64
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE requests
+		SET webhook_sequence = webhook_sequence + 1
+		WHERE id = ?
+		RETURNING webhook_sequence
+	`, id).Scan(&seq)
+
+	return seq, err
+}
+
 // Cancel marks a request as cancelled.
 func (r *Repository) Cancel(ctx context.Context, id, apiKeyID string) error {
 	result, err := r.db.ExecContext(ctx, `
@@ -305,6 +529,42 @@ func (r *Repository) FindByIdempotencyKey(ctx context.Context, apiKeyID, key str
 	return r.GetByID(ctx, requestID)
 }
 
+// FindByContentHash returns the most recent request from a key, created
+// after since, whose operation and payload hash to hash. Denied, expired,
+// cancelled and failed requests are skipped so a deliberate retry is not
+// swallowed.
+func (r *Repository) FindByContentHash(ctx context.Context, apiKeyID, operation, hash string, since time.Time) (*database.Request, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, api_key_id, operation, status, payload, result, error,
+		       suggestion_text, suggestion_at, suggestion_by,
+		       created_at, expires_at, decided_at, decided_by,
+		       executed_at, retry_count, manual_retry_count, webhook_notified_at, priority, context, tags
+		FROM requests INDEXED BY idx_requests_api_key
+		WHERE api_key_id = ?
+		  AND operation = ?
+		  AND created_at > ?
+		  AND status NOT IN (?, ?, ?, ?)
+		ORDER BY created_at DESC
+	`, apiKeyID, operation, util.SQLiteTimestamp(since),
+		database.StatusDenied, database.StatusExpired, database.StatusCancelled, database.StatusFailed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent requests: %w", err)
+	}
+	defer rows.Close()
+
+	candidates, err := scanRequests(rows)
+	if err != nil {
+		return nil, err
+	}
+	for i := range candidates {
+		candidateHash, err := ContentHash(candidates[i].Operation, candidates[i].Payload)
+		if err == nil && candidateHash == hash {
+			return &candidates[i], nil
+		}
+	}
+	return nil, nil
+}
+
 // StoreIdempotencyKey stores an idempotency key mapping.
 func (r *Repository) StoreIdempotencyKey(ctx context.Context, apiKeyID, key, requestID string) error {
 	_, err := r.db.ExecContext(ctx, `
@@ -315,12 +575,18 @@ func (r *Repository) StoreIdempotencyKey(ctx context.Context, apiKeyID, key, req
 	return err
 }
 
-// GetStats returns request statistics.
-func (r *Repository) GetStats(ctx context.Context) (*RequestStats, error) {
-	stats := &RequestStats{}
+// GetStats returns request statistics. Status counts and the daily total
+// cover the last day; operation counts and decision times cover the last
+// windowDays days.
+func (r *Repository) GetStats(ctx context.Context, windowDays int) (*RequestStats, error) {
+	if windowDays < 1 {
+		windowDays = 1
+	}
+	stats := &RequestStats{WindowDays: windowDays}
+	window := fmt.Sprintf("-%d days", windowDays)
 
 	// Count by status
-	rows, err := r.db.QueryContext(ctx, `
+	rows, err := r.db.Reader().QueryContext(ctx, `
 		SELECT status, COUNT(*) FROM requests
 		WHERE created_at > datetime('now', '-1 day')
 		GROUP BY status
@@ -341,15 +607,56 @@ func (r *Repository) GetStats(ctx context.Context) (*RequestStats, error) {
 	}
 
 	// Total pending
-	r.db.QueryRowContext(ctx, `
+	r.db.Reader().QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM requests WHERE status = ?
 	`, database.StatusPendingApproval).Scan(&stats.TotalPending)
 
 	// Total today
-	r.db.QueryRowContext(ctx, `
+	r.db.Reader().QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM requests WHERE created_at > datetime('now', '-1 day')
 	`).Scan(&stats.TotalToday)
 
+	// Count by operation
+	opRows, err := r.db.Reader().QueryContext(ctx, `
+		SELECT operation, COUNT(*) FROM requests
+		WHERE created_at > datetime('now', ?)
+		GROUP BY operation
+	`, window)
+	if err != nil {
+		return nil, err
+	}
+	defer opRows.Close()
+
+	stats.OperationCounts = make(map[string]int)
+	for opRows.Next() {
+		var operation string
+		var count int
+		if err := opRows.Scan(&operation, &count); err != nil {
+			return nil, err
+		}
+		stats.OperationCounts[operation] = count
+	}
+	if err := opRows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Average time to decision, counting only decisions made by a person
+	// (auto-approvals, timeouts and API cancellations are excluded)
+	var avgSeconds sql.NullFloat64
+	err = r.db.Reader().QueryRowContext(ctx, `
+		SELECT COUNT(*), AVG((julianday(decided_at) - julianday(created_at)) * 86400)
+		FROM requests
+		WHERE decided_at IS NOT NULL
+		  AND created_at > datetime('now', ?)
+		  AND decided_by NOT IN ('auto', 'policy', 'timeout', 'api')
+	`, window).Scan(&stats.DecidedCount, &avgSeconds)
+	if err != nil {
+		return nil, err
+	}
+	if avgSeconds.Valid {
+		stats.AvgDecisionSeconds = avgSeconds.Float64
+	}
+
 	return stats, nil
 }
 
@@ -358,21 +665,33 @@ type RequestStats struct {
 	StatusCounts map[string]int
 	TotalPending int
 	TotalToday   int
+
+	// Per-operation and decision-time figures over the last WindowDays days
+	WindowDays         int
+	OperationCounts    map[string]int
+	DecidedCount       int
+	AvgDecisionSeconds float64
+}
+
+// AvgDecisionTime returns the average time to decision as a duration.
+func (s *RequestStats) AvgDecisionTime() time.Duration {
+	return time.Duration(s.AvgDecisionSeconds * float64(time.Second)).Round(time.Second)
 }
 
 // Helper functions
 
 func scanRequest(row *sql.Row) (*database.Request, error) {
 	var (
-		req                database.Request
-		payload            string
-		result             sql.NullString
-		createdAt          string
-		expiresAt          string
-		suggestionAt       sql.NullString
-		decidedAt          sql.NullString
-		executedAt         sql.NullString
-		webhookNotifiedAt  sql.NullString
+		req               database.Request
+		payload           string
+		result            sql.NullString
+		createdAt         string
+		expiresAt         string
+		suggestionAt      sql.NullString
+		decidedAt         sql.NullString
+		executedAt        sql.NullString
+		webhookNotifiedAt sql.NullString
+		tags              string
 	)
 
 	err := row.Scan(
@@ -380,7 +699,7 @@ func scanRequest(row *sql.Row) (*database.Request, error) {
 		&payload, &result, &req.Error,
 		&req.SuggestionText, &suggestionAt, &req.SuggestionBy,
 		&createdAt, &expiresAt, &decidedAt, &req.DecidedBy,
-		&executedAt, &req.RetryCount, &webhookNotifiedAt,
+		&executedAt, &req.RetryCount, &req.ManualRetryCount, &webhookNotifiedAt, &req.Priority, &req.Context, &tags,
 	)
 
 	if err == sql.ErrNoRows {
@@ -391,6 +710,7 @@ func scanRequest(row *sql.Row) (*database.Request, error) {
 	}
 
 	req.Payload = json.RawMessage(payload)
+	json.Unmarshal([]byte(tags), &req.Tags)
 	if result.Valid {
 		req.Result = json.RawMessage(result.String)
 	}
@@ -423,15 +743,16 @@ func scanRequests(rows *sql.Rows) ([]database.Request, error) {
 
 	for rows.Next() {
 		var (
-			req                database.Request
-			payload            string
-			result             sql.NullString
-			createdAt          string
-			expiresAt          string
-			suggestionAt       sql.NullString
-			decidedAt          sql.NullString
-			executedAt         sql.NullString
-			webhookNotifiedAt  sql.NullString
+			req               database.Request
+			payload           string
+			result            sql.NullString
+			createdAt         string
+			expiresAt         string
+			suggestionAt      sql.NullString
+			decidedAt         sql.NullString
+			executedAt        sql.NullString
+			webhookNotifiedAt sql.NullString
+			tags              string
 		)
 
 		err := rows.Scan(
@@ -439,7 +760,7 @@ func scanRequests(rows *sql.Rows) ([]database.Request, error) {
 			&payload, &result, &req.Error,
 			&req.SuggestionText, &suggestionAt, &req.SuggestionBy,
 			&createdAt, &expiresAt, &decidedAt, &req.DecidedBy,
-			&executedAt, &req.RetryCount, &webhookNotifiedAt,
+			&executedAt, &req.RetryCount, &req.ManualRetryCount, &webhookNotifiedAt, &req.Priority, &req.Context, &tags,
 		)
 
 		if err != nil {
@@ -447,6 +768,7 @@ func scanRequests(rows *sql.Rows) ([]database.Request, error) {
 		}
 
 		req.Payload = json.RawMessage(payload)
+		json.Unmarshal([]byte(tags), &req.Tags)
 		if result.Valid {
 			req.Result = json.RawMessage(result.String)
 		}
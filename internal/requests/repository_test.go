@@ -3,6 +3,7 @@ package requests
 import (
 	"context"
 	"encoding/json"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -156,6 +157,64 @@ func TestRepository_GetByAPIKeyID(t *testing.T) {
 	}
 }
 
+func TestRepository_GetByAPIKeyIDAndTag(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	repo := NewRepository(db)
+
+	ctx := context.Background()
+	for _, key := range []string{"key_a", "key_b"} {
+		if _, err := db.Exec(`
+			INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+			VALUES (?, ?, 'sk_test', 'Test', 'write')
+		`, key, "hash_"+key); err != nil {
+			t.Fatalf("Failed to insert API key: %v", err)
+		}
+	}
+
+	for _, c := range []struct {
+		key  string
+		tags []string
+	}{
+		{"key_a", []string{"team:eng", "project:launch"}},
+		{"key_a", []string{"team:ops"}},
+		{"key_a", nil},
+		{"key_b", []string{"team:eng"}},
+	} {
+		if _, err := repo.Create(ctx, &CreateRequest{
+			APIKeyID:  c.key,
+			Operation: database.OperationCreateEvent,
+			Payload:   json.RawMessage(`{}`),
+			ExpiresAt: time.Now().Add(time.Hour),
+			Tags:      c.tags,
+		}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	requests, err := repo.GetByAPIKeyIDAndTag(ctx, "key_a", "team:eng", 10)
+	if err != nil {
+		t.Fatalf("GetByAPIKeyIDAndTag failed: %v", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(requests))
+	}
+	if got := strings.Join(requests[0].Tags, ","); got != "team:eng,project:launch" {
+		t.Errorf("Tags mismatch: got %q", got)
+	}
+
+	requests, err = repo.GetByAPIKeyIDAndTag(ctx, "key_a", "team", 10)
+	if err != nil {
+		t.Fatalf("GetByAPIKeyIDAndTag failed: %v", err)
+	}
+	if len(requests) != 0 {
+		t.Errorf("Expected a partial tag to match nothing, got %d requests", len(requests))
+	}
+}
+
 func TestRepository_GetPending(t *testing.T) {
 	repo, db := setupTestRepo(t)
 	defer db.Close()
@@ -487,7 +546,7 @@ func TestRepository_GetStats(t *testing.T) {
 	// Deny another
 	repo.UpdateStatus(ctx, req3.ID, database.StatusDenied, "admin")
 
-	stats, err := repo.GetStats(ctx)
+	stats, err := repo.GetStats(ctx, 7)
 	if err != nil {
 		t.Fatalf("GetStats failed: %v", err)
 	}
@@ -500,6 +559,76 @@ func TestRepository_GetStats(t *testing.T) {
 	}
 }
 
+func TestRepository_GetStats_OperationsAndDecisionTime(t *testing.T) {
+	repo, db := setupTestRepo(t)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_test', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+
+	create := func(operation string) string {
+		req, err := repo.Create(ctx, &CreateRequest{
+			APIKeyID:  "key_test",
+			Operation: operation,
+			Payload:   json.RawMessage(`{}`),
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return req.ID
+	}
+	decide := func(id, decidedBy, createdOffset string) {
+		if _, err := db.ExecContext(ctx, `
+			UPDATE requests
+			SET status = ?, created_at = datetime('now', ?), decided_at = datetime('now'), decided_by = ?
+			WHERE id = ?
+		`, database.StatusApproved, createdOffset, decidedBy, id); err != nil {
+			t.Fatalf("decide %s: %v", id, err)
+		}
+	}
+
+	// Two approver decisions: 10 and 20 minutes after creation
+	decide(create(database.OperationCreateEvent), "web:admin", "-600 seconds")
+	decide(create(database.OperationCreateEvent), "telegram", "-1200 seconds")
+	// Automatic decisions do not count towards time to decision
+	decide(create(database.OperationUpdateEvent), "auto", "-3600 seconds")
+	create(database.OperationDeleteEvent)
+	// Outside the window entirely
+	decide(create(database.OperationDeleteEvent), "web:admin", "-30 days")
+
+	stats, err := repo.GetStats(ctx, 7)
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+
+	wantOps := map[string]int{
+		database.OperationCreateEvent: 2,
+		database.OperationUpdateEvent: 1,
+		database.OperationDeleteEvent: 1,
+	}
+	for op, want := range wantOps {
+		if got := stats.OperationCounts[op]; got != want {
+			t.Errorf("OperationCounts[%s] = %d, want %d", op, got, want)
+		}
+	}
+	if stats.WindowDays != 7 {
+		t.Errorf("WindowDays = %d, want 7", stats.WindowDays)
+	}
+	if stats.DecidedCount != 2 {
+		t.Errorf("DecidedCount = %d, want 2", stats.DecidedCount)
+	}
+	if got := stats.AvgDecisionTime(); got != 15*time.Minute {
+		t.Errorf("AvgDecisionTime = %v, want 15m", got)
+	}
+}
+
 func TestRepository_IncrementRetryCount(t *testing.T) {
 	repo, db := setupTestRepo(t)
 	defer db.Close()
@@ -0,0 +1,69 @@
+package response
+
+import "net/http"
+
+// ItemResult is the outcome of one item in a batch operation. Status is an
+// HTTP status code for the item alone. Data describes the item and Error is
+// set only when it failed.
+type ItemResult struct {
+	Index  int         `json:"index"`
+	Status int         `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+	Error  *APIError   `json:"error,omitempty"`
+}
+
+// OK reports whether the item succeeded.
+func (r ItemResult) OK() bool {
+	return r.Status >= 200 && r.Status < 300
+}
+
+// ItemSuccess returns a successful item result.
+func ItemSuccess(index, status int, data interface{}) ItemResult {
+	return ItemResult{Index: index, Status: status, Data: data}
+}
+
+// ItemFailure returns a failed item result. An empty code is derived from
+// the status, as Error does.
+func ItemFailure(index, status int, code, message string) ItemResult {
+	if code == "" {
+		code = statusToErrorCode(status)
+	}
+	return ItemResult{Index: index, Status: status, Error: &APIError{Code: code, Message: message}}
+}
+
+// MultiStatusResponse is the body written by MultiStatus. Every batch
+// endpoint reports through it, so clients parse per-item outcomes the same
+// way everywhere.
+type MultiStatusResponse struct {
+	Results   []ItemResult `json:"results"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+}
+
+// NewMultiStatus counts the successes and failures in results.
+func NewMultiStatus(results []ItemResult) MultiStatusResponse {
+	if results == nil {
+		results = []ItemResult{}
+	}
+	body := MultiStatusResponse{Results: results}
+	for _, r := range results {
+		if r.OK() {
+			body.Succeeded++
+		} else {
+			body.Failed++
+		}
+	}
+	return body
+}
+
+// MultiStatus writes per-item batch results. The status is 200 when every
+// item succeeded and 207 Multi-Status when any failed; the body has the same
+// shape either way.
+func MultiStatus(w http.ResponseWriter, results []ItemResult) {
+	body := NewMultiStatus(results)
+	status := http.StatusOK
+	if body.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	JSON(w, status, body)
+}
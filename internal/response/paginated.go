@@ -0,0 +1,57 @@
+package response
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// EnvelopeParam is the query parameter that asks a list endpoint for the
+// paginated envelope instead of its original body.
+const EnvelopeParam = "envelope"
+
+// Pagination describes one page of a list. Count is the number of items on
+// the page. Cursor listings set NextPageToken while more pages remain;
+// offset listings set Total, Limit and Offset.
+type Pagination struct {
+	Count         int    `json:"count"`
+	NextPageToken string `json:"next_page_token,omitempty"`
+	Total         *int   `json:"total,omitempty"`
+	Limit         int    `json:"limit,omitempty"`
+	Offset        *int   `json:"offset,omitempty"`
+}
+
+// OffsetPage returns the pagination of an offset listing.
+func OffsetPage(count, total, limit, offset int) Pagination {
+	return Pagination{Count: count, Total: &total, Limit: limit, Offset: &offset}
+}
+
+// PaginatedResponse is the body written by Paginated for clients that ask
+// for the envelope. Every list endpoint uses it, so clients page through
+// them the same way.
+type PaginatedResponse struct {
+	Data       interface{} `json:"data"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+// WantsEnvelope reports whether the request asked for the paginated
+// envelope with ?envelope=true.
+func WantsEnvelope(r *http.Request) bool {
+	v, err := strconv.ParseBool(r.URL.Query().Get(EnvelopeParam))
+	return err == nil && v
+}
+
+// Paginated writes a list. Clients that ask for the envelope get
+// {"data": items, "pagination": page}; others get legacy, the body the
+// endpoint returned before the envelope existed. A nil slice is written as
+// an empty array.
+func Paginated(w http.ResponseWriter, r *http.Request, items interface{}, page Pagination, legacy map[string]interface{}) {
+	if !WantsEnvelope(r) {
+		JSON(w, http.StatusOK, legacy)
+		return
+	}
+	if v := reflect.ValueOf(items); !v.IsValid() || v.Kind() == reflect.Slice && v.IsNil() {
+		items = []interface{}{}
+	}
+	JSON(w, http.StatusOK, PaginatedResponse{Data: items, Pagination: page})
+}
@@ -3,8 +3,10 @@ package response
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // JSON writes a JSON response.
@@ -63,12 +65,16 @@ func statusToErrorCode(status int) string {
 		return "NOT_FOUND"
 	case http.StatusConflict:
 		return "CONFLICT"
+	case http.StatusRequestEntityTooLarge:
+		return "PAYLOAD_TOO_LARGE"
 	case http.StatusTooManyRequests:
 		return "RATE_LIMITED"
 	case http.StatusInternalServerError:
 		return "INTERNAL_ERROR"
 	case http.StatusBadGateway:
 		return "GOOGLE_API_ERROR"
+	case http.StatusServiceUnavailable:
+		return "SERVICE_UNAVAILABLE"
 	default:
 		return "ERROR"
 	}
@@ -148,13 +154,45 @@ func WriteInsufficientPermissions(w http.ResponseWriter, tier, operation string)
 		})
 }
 
-// WriteRateLimited writes a 429 rate limited error.
-func WriteRateLimited(w http.ResponseWriter, retryAfter int) {
-	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+// RateLimit holds the values reported in rate limit response headers.
+type RateLimit struct {
+	Limit      int // Maximum requests in a burst
+	Remaining  int // Requests left before limiting
+	Reset      int // Seconds until the limit is fully restored
+	RetryAfter int // Seconds until the next request is accepted
+}
+
+// SetRateLimitHeaders sets the X-RateLimit-* headers.
+func SetRateLimitHeaders(w http.ResponseWriter, rl RateLimit) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rl.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(rl.Remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.Itoa(rl.Reset))
+}
+
+// SetRetryAfter sets the Retry-After header to a delay in whole seconds,
+// rounding up so clients never retry early.
+func SetRetryAfter(w http.ResponseWriter, d time.Duration) {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 0 {
+		seconds = 0
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
+
+// SetRetryAfterDate sets the Retry-After header in HTTP-date form, for
+// limits that lift at a fixed time rather than after a delay.
+func SetRetryAfterDate(w http.ResponseWriter, t time.Time) {
+	w.Header().Set("Retry-After", t.UTC().Format(http.TimeFormat))
+}
+
+// WriteRateLimited writes a 429 rate limited error with rate limit headers.
+func WriteRateLimited(w http.ResponseWriter, rl RateLimit) {
+	SetRateLimitHeaders(w, rl)
+	SetRetryAfter(w, time.Duration(rl.RetryAfter)*time.Second)
 	WriteErrorWithDetails(w, http.StatusTooManyRequests, ErrCodeRateLimited,
 		"Too many requests, please slow down",
 		"", map[string]interface{}{
-			"retry_after_seconds": retryAfter,
+			"retry_after_seconds": rl.RetryAfter,
 		})
 }
 
@@ -0,0 +1,135 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteRateLimitedRetryAfterIsSeconds(t *testing.T) {
+	rr := httptest.NewRecorder()
+	WriteRateLimited(rr, RateLimit{Limit: 20, Remaining: 0, Reset: 90, RetryAfter: 65})
+
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("Retry-After"); got != "65" {
+		t.Errorf("Retry-After = %q, want %q", got, "65")
+	}
+	if got := rr.Header().Get("X-RateLimit-Reset"); got != "90" {
+		t.Errorf("X-RateLimit-Reset = %q, want %q", got, "90")
+	}
+}
+
+func TestSetRetryAfter(t *testing.T) {
+	cases := []struct {
+		delay time.Duration
+		want  string
+	}{
+		{60 * time.Second, "60"},
+		{1500 * time.Millisecond, "2"},
+		{0, "0"},
+		{-time.Second, "0"},
+	}
+
+	for _, tc := range cases {
+		rr := httptest.NewRecorder()
+		SetRetryAfter(rr, tc.delay)
+		if got := rr.Header().Get("Retry-After"); got != tc.want {
+			t.Errorf("SetRetryAfter(%v) = %q, want %q", tc.delay, got, tc.want)
+		}
+	}
+}
+
+func TestSetRetryAfterDate(t *testing.T) {
+	rr := httptest.NewRecorder()
+	at := time.Date(2026, 1, 28, 15, 4, 5, 0, time.FixedZone("EST", -5*60*60))
+	SetRetryAfterDate(rr, at)
+
+	if got := rr.Header().Get("Retry-After"); got != "Wed, 28 Jan 2026 20:04:05 GMT" {
+		t.Errorf("Retry-After = %q", got)
+	}
+}
+
+func TestMultiStatus(t *testing.T) {
+	cases := []struct {
+		name      string
+		results   []ItemResult
+		status    int
+		succeeded int
+		failed    int
+	}{
+		{"all succeed", []ItemResult{
+			ItemSuccess(0, http.StatusCreated, "a"),
+			ItemSuccess(1, http.StatusCreated, "b"),
+		}, http.StatusOK, 2, 0},
+		{"all fail", []ItemResult{
+			ItemFailure(0, http.StatusBadGateway, "", "boom"),
+			ItemFailure(1, http.StatusForbidden, ErrCodeConstraintViolation, "not allowed"),
+		}, http.StatusMultiStatus, 0, 2},
+		{"mixed", []ItemResult{
+			ItemSuccess(0, http.StatusCreated, "a"),
+			ItemFailure(1, http.StatusBadGateway, "", "boom"),
+		}, http.StatusMultiStatus, 1, 1},
+		{"empty", nil, http.StatusOK, 0, 0},
+	}
+
+	for _, tc := range cases {
+		rr := httptest.NewRecorder()
+		MultiStatus(rr, tc.results)
+		if rr.Code != tc.status {
+			t.Errorf("%s: status = %d, want %d", tc.name, rr.Code, tc.status)
+		}
+
+		var body MultiStatusResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: decode: %v", tc.name, err)
+		}
+		if body.Succeeded != tc.succeeded || body.Failed != tc.failed {
+			t.Errorf("%s: succeeded/failed = %d/%d, want %d/%d", tc.name, body.Succeeded, body.Failed, tc.succeeded, tc.failed)
+		}
+		if body.Results == nil || len(body.Results) != len(tc.results) {
+			t.Errorf("%s: got %d results, want %d", tc.name, len(body.Results), len(tc.results))
+		}
+		for i, r := range body.Results {
+			if r.Index != i {
+				t.Errorf("%s: result %d has index %d", tc.name, i, r.Index)
+			}
+			if r.OK() != (r.Error == nil) {
+				t.Errorf("%s: result %d status %d but error %+v", tc.name, i, r.Status, r.Error)
+			}
+		}
+	}
+}
+
+func TestItemFailureDefaultsCode(t *testing.T) {
+	if got := ItemFailure(0, http.StatusBadGateway, "", "boom").Error.Code; got != ErrCodeGoogleAPIError {
+		t.Errorf("code = %q, want %q", got, ErrCodeGoogleAPIError)
+	}
+}
+
+func TestPaginated(t *testing.T) {
+	legacy := map[string]interface{}{"events": []string{"a"}, "next_page_token": "tok"}
+
+	rr := httptest.NewRecorder()
+	Paginated(rr, httptest.NewRequest("GET", "/list", nil), []string{"a"}, Pagination{Count: 1, NextPageToken: "tok"}, legacy)
+	if got := strings.TrimSpace(rr.Body.String()); got != `{"events":["a"],"next_page_token":"tok"}` {
+		t.Errorf("without envelope = %s", got)
+	}
+
+	rr = httptest.NewRecorder()
+	Paginated(rr, httptest.NewRequest("GET", "/list?envelope=true", nil), []string{"a"}, Pagination{Count: 1, NextPageToken: "tok"}, legacy)
+	if got := strings.TrimSpace(rr.Body.String()); got != `{"data":["a"],"pagination":{"count":1,"next_page_token":"tok"}}` {
+		t.Errorf("cursor envelope = %s", got)
+	}
+
+	rr = httptest.NewRecorder()
+	var none []string
+	Paginated(rr, httptest.NewRequest("GET", "/list?envelope=true", nil), none, OffsetPage(0, 12, 10, 20), legacy)
+	if got := strings.TrimSpace(rr.Body.String()); got != `{"data":[],"pagination":{"count":0,"total":12,"limit":10,"offset":20}}` {
+		t.Errorf("offset envelope = %s", got)
+	}
+}
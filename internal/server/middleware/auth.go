@@ -42,8 +42,9 @@ func GetAuthenticatedKey(r *http.Request) *apikeys.AuthenticatedKey {
 	return APIKeyFromContext(r.Context())
 }
 
-// APIKeyAuth returns middleware that validates API key authentication.
-func APIKeyAuth(repo *apikeys.Repository, limiter *RateLimiter) func(http.Handler) http.Handler {
+// APIKeyAuth returns middleware that validates API key authentication and
+// enforces the key's allowed_ips constraint against the resolved client IP.
+func APIKeyAuth(repo *apikeys.Repository, limiter *RateLimiter, clientIPs *ClientIPResolver) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Extract API key from Authorization header
@@ -73,12 +74,22 @@ func APIKeyAuth(repo *apikeys.Repository, limiter *RateLimiter) func(http.Handle
 				return
 			}
 
-			// Check rate limits
-			if limiter != nil && !limiter.Allow(authKey.ID, authKey.Tier) {
-				response.WriteRateLimited(w, 60) // Retry after 60 seconds
+			// Check source address restrictions
+			if !apikeys.IPAllowed(authKey, clientIPs.ClientIP(r)) {
+				response.WriteError(w, http.StatusUnauthorized, response.ErrCodeUnauthorized, "This API key cannot be used from this address")
 				return
 			}
 
+			// Check rate limits
+			if limiter != nil {
+				allowed, status := limiter.AllowWithStatus(authKey.ID, authKey.Tier)
+				if !allowed {
+					response.WriteRateLimited(w, status.Headers())
+					return
+				}
+				response.SetRateLimitHeaders(w, status.Headers())
+			}
+
 			// Update last used timestamp (async, don't block request)
 			go repo.UpdateLastUsed(context.Background(), authKey.ID)
 
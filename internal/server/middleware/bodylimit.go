@@ -0,0 +1,19 @@
+// Package middleware provides request body size limiting.
+package middleware
+
+import (
+	"net/http"
+)
+
+// MaxBodyBytes returns middleware that caps every request body at limit bytes.
+// Reads past the limit fail with *http.MaxBytesError.
+func MaxBodyBytes(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limit > 0 && r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+// defaultForwardedHeaders are read from trusted proxies when no client IP
+// headers are configured.
+var defaultForwardedHeaders = []string{"X-Forwarded-For", "X-Real-IP"}
+
+// ClientIPResolver determines the originating client IP of a request. Proxy
+// headers are only read when configured, since any client can set them.
+type ClientIPResolver struct {
+	headers []string
+	trusted []netip.Prefix
+}
+
+// NewClientIPResolver creates a resolver that reads the given headers, in
+// order, when the peer is one of the trusted proxies. X-Forwarded-For and
+// X-Real-IP are read if no headers are given. Without trusted proxies it
+// always uses the connection's peer address, whatever headers are given.
+// Invalid proxy entries are skipped; the configuration rejects them at
+// startup.
+func NewClientIPResolver(headers, trustedProxies []string) *ClientIPResolver {
+	c := &ClientIPResolver{}
+	for _, entry := range trustedProxies {
+		if prefix, err := util.ParseIPPrefix(entry); err == nil {
+			c.trusted = append(c.trusted, prefix)
+		}
+	}
+	if len(c.trusted) == 0 {
+		return c
+	}
+	if len(headers) == 0 {
+		headers = defaultForwardedHeaders
+	}
+	for _, header := range headers {
+		c.headers = append(c.headers, http.CanonicalHeaderKey(strings.TrimSpace(header)))
+	}
+	return c
+}
+
+// ClientIP returns the client IP for the request. X-Forwarded-For is read
+// from the right, skipping trusted proxies: entries left of the first
+// untrusted address were supplied by the client and cannot be believed.
+func (c *ClientIPResolver) ClientIP(r *http.Request) string {
+	peer := peerIP(r)
+	if c == nil || !c.isTrusted(peer) {
+		return peer
+	}
+
+	for _, header := range c.headers {
+		if header == "X-Forwarded-For" {
+			if ip := c.forwardedFor(r.Header.Values(header)); ip != "" {
+				return ip
+			}
+			continue
+		}
+		if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get(header))); err == nil {
+			return addr.Unmap().String()
+		}
+	}
+	return peer
+}
+
+// forwardedFor returns the nearest untrusted hop in X-Forwarded-For, or the
+// furthest hop if every entry is a trusted proxy.
+func (c *ClientIPResolver) forwardedFor(values []string) string {
+	hops := strings.Split(strings.Join(values, ","), ",")
+	client := ""
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		client = addr.Unmap().String()
+		if !c.isTrusted(client) {
+			break
+		}
+	}
+	return client
+}
+
+// isTrusted reports whether ip belongs to a trusted proxy.
+func (c *ClientIPResolver) isTrusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range c.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware resolves the client IP once and stores it in the request
+// context, where logging, audit entries and the login limiter read it.
+func (c *ClientIPResolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := util.WithClientIP(r.Context(), c.ClientIP(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// peerIP returns the host part of the connection's remote address.
+func peerIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/crypto"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+func TestClientIPResolver(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []string
+		proxies []string
+		peer    string
+		set     map[string]string
+		want    string
+	}{
+		{"peer address by default", nil, nil, "192.0.2.1:4321", map[string]string{"X-Forwarded-For": "198.51.100.9"}, "192.0.2.1"},
+		{"cloudflare header", []string{"CF-Connecting-IP"}, []string{"192.0.2.0/24"}, "192.0.2.1:4321", map[string]string{"Cf-Connecting-Ip": "198.51.100.9"}, "198.51.100.9"},
+		{"forwarded for uses last hop", []string{"x-forwarded-for"}, []string{"192.0.2.0/24"}, "192.0.2.1:4321", map[string]string{"X-Forwarded-For": "203.0.113.1, 198.51.100.9"}, "198.51.100.9"},
+		{"invalid header falls through", []string{"X-Real-IP", "CF-Connecting-IP"}, []string{"192.0.2.0/24"}, "192.0.2.1:4321", map[string]string{"X-Real-IP": "garbage", "CF-Connecting-IP": "198.51.100.9"}, "198.51.100.9"},
+		{"missing header uses peer", []string{"CF-Connecting-IP"}, []string{"192.0.2.0/24"}, "192.0.2.1:4321", nil, "192.0.2.1"},
+
+		{"trusted proxy forwarded for", nil, []string{"10.0.0.0/8"}, "10.0.0.5:4321", map[string]string{"X-Forwarded-For": "198.51.100.9"}, "198.51.100.9"},
+		{"trusted proxy real ip", nil, []string{"10.0.0.5"}, "10.0.0.5:4321", map[string]string{"X-Real-IP": "198.51.100.9"}, "198.51.100.9"},
+		{"proxy chain skips trusted hops", nil, []string{"10.0.0.0/8"}, "10.0.0.5:4321", map[string]string{"X-Forwarded-For": "198.51.100.9, 10.1.1.1, 10.2.2.2"}, "198.51.100.9"},
+		{"all hops trusted uses furthest", nil, []string{"10.0.0.0/8"}, "10.0.0.5:4321", map[string]string{"X-Forwarded-For": "10.3.3.3, 10.1.1.1"}, "10.3.3.3"},
+		{"ipv6 peer", nil, []string{"fd00::/8"}, "[fd00::1]:4321", map[string]string{"X-Forwarded-For": "2001:db8::7"}, "2001:db8::7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://example.com/", nil)
+			req.RemoteAddr = tt.peer
+			for header, value := range tt.set {
+				req.Header.Set(header, value)
+			}
+			if got := NewClientIPResolver(tt.headers, tt.proxies).ClientIP(req); got != tt.want {
+				t.Errorf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIPResolverResistsSpoofing(t *testing.T) {
+	resolver := NewClientIPResolver(nil, []string{"10.0.0.0/8"})
+
+	tests := []struct {
+		name string
+		peer string
+		set  map[string]string
+		want string
+	}{
+		{"untrusted peer forwarded for", "203.0.113.50:1234", map[string]string{"X-Forwarded-For": "10.0.0.1"}, "203.0.113.50"},
+		{"untrusted peer real ip", "203.0.113.50:1234", map[string]string{"X-Real-IP": "198.51.100.9"}, "203.0.113.50"},
+		{"client prepends fake hop", "10.0.0.5:1234", map[string]string{"X-Forwarded-For": "10.9.9.9, 203.0.113.50"}, "203.0.113.50"},
+		{"client sends garbage hop", "10.0.0.5:1234", map[string]string{"X-Forwarded-For": "not-an-ip, 203.0.113.50"}, "203.0.113.50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://example.com/", nil)
+			req.RemoteAddr = tt.peer
+			for header, value := range tt.set {
+				req.Header.Set(header, value)
+			}
+			if got := resolver.ClientIP(req); got != tt.want {
+				t.Errorf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIPResolverIgnoresHeadersWithoutTrustedProxies(t *testing.T) {
+	resolver := NewClientIPResolver([]string{"CF-Connecting-IP", "X-Forwarded-For"}, nil)
+
+	for _, header := range []string{"CF-Connecting-IP", "X-Forwarded-For"} {
+		req := httptest.NewRequest("GET", "http://example.com/", nil)
+		req.RemoteAddr = "203.0.113.50:1234"
+		req.Header.Set(header, "198.51.100.9")
+		if got := resolver.ClientIP(req); got != "203.0.113.50" {
+			t.Errorf("%s: ClientIP = %q, want the peer 203.0.113.50", header, got)
+		}
+	}
+}
+
+func TestClientIPMiddlewareStoresIP(t *testing.T) {
+	var got string
+	handler := NewClientIPResolver(nil, []string{"10.0.0.0/8"}).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = util.ClientIPFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest("GET", "http://example.com/", nil)
+	req.RemoteAddr = "10.0.0.5:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.9")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "198.51.100.9" {
+		t.Errorf("context client IP = %q, want 198.51.100.9", got)
+	}
+}
+
+func TestAPIKeyAuthEnforcesAllowedIPs(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	hasher, err := crypto.NewAPIKeyHasher("test-secret-key-12345")
+	if err != nil {
+		t.Fatalf("hasher: %v", err)
+	}
+	repo := apikeys.NewRepository(db, hasher)
+	_, fullKey, err := repo.Create(context.Background(), "Office", "read", &database.KeyConstraints{AllowedIPs: []string{"198.51.100.0/24"}})
+	if err != nil {
+		t.Fatalf("create key: %v", err)
+	}
+
+	handler := APIKeyAuth(repo, nil, NewClientIPResolver([]string{"CF-Connecting-IP"}, []string{"10.0.0.0/8"}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(peer, clientIP string) int {
+		req := httptest.NewRequest("GET", "http://example.com/api/calendar/list", nil)
+		req.RemoteAddr = peer
+		req.Header.Set("Authorization", "Bearer "+fullKey)
+		req.Header.Set("CF-Connecting-IP", clientIP)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	if code := do("10.0.0.5:1234", "198.51.100.20"); code != http.StatusOK {
+		t.Errorf("allowed address: got %d, want 200", code)
+	}
+	if code := do("10.0.0.5:1234", "203.0.113.5"); code != http.StatusUnauthorized {
+		t.Errorf("other address: got %d, want 401", code)
+	}
+	if code := do("203.0.113.5:1234", "198.51.100.20"); code != http.StatusUnauthorized {
+		t.Errorf("spoofed header from an untrusted peer: got %d, want 401", code)
+	}
+}
@@ -47,13 +47,10 @@ func Logging(next http.Handler) http.Handler {
 		// Calculate duration
 		duration := time.Since(start)
 
-		// Get client IP (handle proxies)
-		clientIP := r.Header.Get("X-Forwarded-For")
+		// Client IP as resolved behind trusted proxies
+		clientIP := util.ClientIPFromContext(r.Context())
 		if clientIP == "" {
-			clientIP = r.Header.Get("X-Real-IP")
-		}
-		if clientIP == "" {
-			clientIP = r.RemoteAddr
+			clientIP = peerIP(r)
 		}
 
 		// Get API key prefix if authenticated
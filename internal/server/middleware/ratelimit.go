@@ -2,10 +2,12 @@
 package middleware
 
 import (
+	"math"
 	"sync"
 	"time"
 
 	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/response"
 )
 
 // RateLimiter implements per-tier rate limiting using token buckets.
@@ -31,9 +33,24 @@ func NewRateLimiter(limits config.RateLimitsConfig) *RateLimiter {
 	}
 }
 
+// RateLimitStatus describes a key's bucket after a rate limit check.
+type RateLimitStatus struct {
+	Limit      int           // Bucket capacity (burst)
+	Remaining  int           // Whole requests left in the bucket
+	Reset      time.Duration // Until the bucket is full again
+	RetryAfter time.Duration // Until the next request is allowed; zero when allowed
+}
+
 // Allow checks if a request should be allowed based on the rate limit.
 // Returns true if allowed, false if rate limited.
 func (rl *RateLimiter) Allow(keyID, tier string) bool {
+	allowed, _ := rl.AllowWithStatus(keyID, tier)
+	return allowed
+}
+
+// AllowWithStatus is like Allow but also reports the bucket state, for
+// rate limit response headers.
+func (rl *RateLimiter) AllowWithStatus(keyID, tier string) (bool, RateLimitStatus) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
@@ -48,12 +65,12 @@ func (rl *RateLimiter) Allow(keyID, tier string) bool {
 	bucket.refill()
 
 	// Check if we have tokens available
-	if bucket.tokens >= 1.0 {
+	allowed := bucket.tokens >= 1.0
+	if allowed {
 		bucket.tokens -= 1.0
-		return true
 	}
 
-	return false
+	return allowed, bucket.status()
 }
 
 // createBucket creates a new token bucket for the given tier.
@@ -95,6 +112,35 @@ func (tb *tokenBucket) refill() {
 	}
 }
 
+// status reports the bucket's current state.
+func (tb *tokenBucket) status() RateLimitStatus {
+	s := RateLimitStatus{
+		Limit:     int(tb.maxTokens),
+		Remaining: int(tb.tokens),
+	}
+	if tb.refillRate > 0 {
+		s.Reset = time.Duration((tb.maxTokens - tb.tokens) / tb.refillRate * float64(time.Second))
+		if tb.tokens < 1.0 {
+			s.RetryAfter = time.Duration((1.0 - tb.tokens) / tb.refillRate * float64(time.Second))
+		}
+	} else if tb.tokens < 1.0 {
+		// A bucket that never refills stays empty
+		s.RetryAfter = time.Minute
+	}
+	return s
+}
+
+// Headers converts the status to the values reported in response headers,
+// rounding durations up to whole seconds.
+func (s RateLimitStatus) Headers() response.RateLimit {
+	return response.RateLimit{
+		Limit:      s.Limit,
+		Remaining:  s.Remaining,
+		Reset:      int(math.Ceil(s.Reset.Seconds())),
+		RetryAfter: int(math.Ceil(s.RetryAfter.Seconds())),
+	}
+}
+
 // GetRemainingTokens returns the number of remaining tokens for a key.
 func (rl *RateLimiter) GetRemainingTokens(keyID string) int {
 	rl.mu.RLock()
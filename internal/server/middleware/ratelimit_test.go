@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/crypto"
+	"github.com/dtorcivia/schedlock/internal/database"
+)
+
+func testLimits() config.RateLimitsConfig {
+	return config.RateLimitsConfig{
+		Read:  config.TierLimit{RequestsPerMinute: 60, Burst: 3},
+		Write: config.TierLimit{RequestsPerMinute: 30, Burst: 2},
+		Admin: config.TierLimit{RequestsPerMinute: 120, Burst: 10},
+	}
+}
+
+func TestAllowWithStatus(t *testing.T) {
+	rl := NewRateLimiter(testLimits())
+
+	allowed, status := rl.AllowWithStatus("key1", "read")
+	if !allowed {
+		t.Fatal("first request should be allowed")
+	}
+	if status.Limit != 3 || status.Remaining != 2 {
+		t.Errorf("after one request: limit %d remaining %d, want 3 and 2", status.Limit, status.Remaining)
+	}
+	if status.RetryAfter != 0 {
+		t.Errorf("RetryAfter = %v, want 0 while allowed", status.RetryAfter)
+	}
+
+	rl.AllowWithStatus("key1", "read")
+	rl.AllowWithStatus("key1", "read")
+	allowed, status = rl.AllowWithStatus("key1", "read")
+	if allowed {
+		t.Fatal("fourth request should exceed a burst of 3")
+	}
+	if status.Remaining != 0 {
+		t.Errorf("Remaining = %d, want 0", status.Remaining)
+	}
+
+	// 60/min refills one token per second: about 1s until the next request
+	// and about 3s until the bucket is full again
+	headers := status.Headers()
+	if headers.RetryAfter != 1 {
+		t.Errorf("RetryAfter = %d, want 1", headers.RetryAfter)
+	}
+	if headers.Reset != 3 {
+		t.Errorf("Reset = %d, want 3", headers.Reset)
+	}
+}
+
+func TestAPIKeyAuthSetsRateLimitHeaders(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	hasher, err := crypto.NewAPIKeyHasher("test-secret-key-12345")
+	if err != nil {
+		t.Fatalf("hasher: %v", err)
+	}
+	repo := apikeys.NewRepository(db, hasher)
+	_, fullKey, err := repo.Create(context.Background(), "Writer", "write", nil)
+	if err != nil {
+		t.Fatalf("create key: %v", err)
+	}
+
+	handler := APIKeyAuth(repo, NewRateLimiter(testLimits()), nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "http://example.com/api/calendar/list", nil)
+		req.Header.Set("Authorization", "Bearer "+fullKey)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := do()
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("X-RateLimit-Limit"); got != "2" {
+		t.Errorf("X-RateLimit-Limit = %q, want 2", got)
+	}
+	if got := rr.Header().Get("X-RateLimit-Remaining"); got != "1" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 1", got)
+	}
+	// 30/min refills a token every 2s
+	if got := rr.Header().Get("X-RateLimit-Reset"); got != "2" {
+		t.Errorf("X-RateLimit-Reset = %q, want 2", got)
+	}
+
+	do()
+	rr = do()
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 0", got)
+	}
+	if got := rr.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+	if got := rr.Header().Get("X-RateLimit-Reset"); got != "4" {
+		t.Errorf("X-RateLimit-Reset = %q, want 4", got)
+	}
+}
+
+func TestRateLimitStatusHeadersRoundUp(t *testing.T) {
+	status := RateLimitStatus{Limit: 5, Remaining: 0, Reset: 2100 * time.Millisecond, RetryAfter: 10 * time.Millisecond}
+	headers := status.Headers()
+	if headers.Reset != 3 || headers.RetryAfter != 1 {
+		t.Errorf("got reset %d retry %d, want 3 and 1", headers.Reset, headers.RetryAfter)
+	}
+}
@@ -17,6 +17,7 @@ func (s *Server) setupRoutes() {
 	// Health check (no auth required)
 	s.router.HandleFunc("GET /health", s.handleHealth)
 	s.router.HandleFunc("GET /api/health", s.handleHealth)
+	s.router.HandleFunc("GET /api/openapi.json", s.apiHandler.OpenAPI)
 
 	// Callback routes (token-based auth, no API key required)
 	// These must be registered before the authenticated /api/* handler
@@ -31,7 +32,7 @@ func (s *Server) setupRoutes() {
 	s.apiHandler.RegisterRoutes(apiMux)
 
 	// Wrap API routes with authentication and rate limiting
-	apiHandler := middleware.APIKeyAuth(s.apiKeyRepo, s.rateLimiter)(apiMux)
+	apiHandler := middleware.APIKeyAuth(s.apiKeyRepo, s.rateLimiter, s.clientIPs)(apiMux)
 	s.router.Handle("/api/{path...}", apiHandler)
 
 	// Telegram webhook (special auth via bot token in URL)
@@ -67,6 +68,9 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	oauthStatus := "not_configured"
 	if s.oauthMgr.IsAuthenticated() {
 		oauthStatus = "connected"
+		if s.oauthMgr.Health().Unhealthy {
+			oauthStatus = "unhealthy"
+		}
 	}
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{
@@ -30,28 +30,33 @@ import (
 
 // Server is the main HTTP server for SchedLock.
 type Server struct {
-	config          *config.Config
-	db              *database.DB
-	router          *http.ServeMux
-	apiKeyRepo      *apikeys.Repository
-	requestRepo     *requests.Repository
-	tokenRepo       *tokens.Repository
-	apiKeyHasher    *crypto.APIKeyHasher
-	encryptor       *crypto.Encryptor
-	rateLimiter     *middleware.RateLimiter
-	displayFormat   *util.DisplayFormatter
-	oauthMgr        *google.OAuthManager
-	calendarClient  *google.CalendarClient
-	engine          *engine.Engine
-	notificationMgr *notifications.Manager
-	webhookClient   *webhook.Client
-	auditLogger     *engine.AuditLogger
-	sessionMgr      *web.SessionManager
-	apiHandler      *api.Handler
-	webHandler      *web.Handler
-	timeoutWorker   *workers.TimeoutWorker
-	cleanupWorker   *workers.CleanupWorker
-	telegramHandler *telegram.WebhookHandler
+	config           *config.Config
+	db               *database.DB
+	router           *http.ServeMux
+	apiKeyRepo       *apikeys.Repository
+	requestRepo      *requests.Repository
+	tokenRepo        *tokens.Repository
+	apiKeyHasher     *crypto.APIKeyHasher
+	encryptor        *crypto.Encryptor
+	rateLimiter      *middleware.RateLimiter
+	clientIPs        *middleware.ClientIPResolver
+	displayFormat    *util.DisplayFormatter
+	oauthMgr         *google.OAuthManager
+	calendarClient   *google.CalendarClient
+	engine           *engine.Engine
+	notificationMgr  *notifications.Manager
+	webhookClient    *webhook.Client
+	auditClient      *webhook.AuditClient
+	settingsClient   *webhook.AuditClient
+	auditLogger      *engine.AuditLogger
+	sessionMgr       *web.SessionManager
+	apiHandler       *api.Handler
+	webHandler       *web.Handler
+	timeoutWorker    *workers.TimeoutWorker
+	reminderWorker   *workers.ReminderWorker
+	escalationWorker *workers.EscalationWorker
+	cleanupWorker    *workers.CleanupWorker
+	telegramHandler  *telegram.WebhookHandler
 }
 
 // New creates a new Server instance.
@@ -73,6 +78,8 @@ func New(cfg *config.Config, db *database.DB) (*Server, error) {
 		cfg.Display.DateFormat,
 		cfg.Display.TimeFormat,
 		cfg.Display.DatetimeFormat,
+		cfg.Display.Locale,
+		cfg.Display.RelativeTime,
 	)
 	if err != nil {
 		return nil, err
@@ -92,12 +99,24 @@ func New(cfg *config.Config, db *database.DB) (*Server, error) {
 
 	// Initialize Calendar client
 	calendarClient := google.NewCalendarClient(oauthMgr)
+	calendarClient.SetConcurrencyLimit(cfg.Google.MaxConcurrentCalls, time.Duration(cfg.Google.CallWaitMs)*time.Millisecond)
+	calendarClient.SetEventCache(time.Duration(cfg.Google.EventCacheTTLSeconds)*time.Second, cfg.Google.EventCacheSize)
 
-	// Initialize audit logger
+	// Initialize audit logger, streaming entries to the audit and settings
+	// webhooks if configured
 	auditLogger := engine.NewAuditLogger(db)
+	auditClient := webhook.NewAuditClient(&cfg.Audit.Webhook)
+	if auditClient.Enabled() {
+		auditLogger.AddSink(auditClient)
+	}
+	settingsClient := webhook.NewSettingsClient(&cfg.Audit.SettingsWebhook)
+	if settingsClient.Enabled() {
+		auditLogger.AddSink(settingsClient)
+	}
 
 	// Initialize engine
 	eng := engine.NewEngine(cfg, requestRepo, calendarClient, auditLogger, tokenRepo)
+	eng.SetKeyLookup(apiKeyRepo)
 
 	// Initialize notification credentials store
 	credentialsStore, err := notifications.NewCredentialsStore(db, cfg.Auth.EncryptionKey)
@@ -110,6 +129,7 @@ func New(cfg *config.Config, db *database.DB) (*Server, error) {
 
 	// Initialize notification manager
 	notificationMgr := notifications.NewManager(db, cfg)
+	notificationMgr.SetCredentialsStore(credentialsStore)
 
 	// Register notification providers
 	if cfg.Notifications.Ntfy.Enabled {
@@ -122,6 +142,9 @@ func New(cfg *config.Config, db *database.DB) (*Server, error) {
 	var telegramProvider *telegram.Provider
 	if cfg.Notifications.Telegram.Enabled {
 		telegramProvider = telegram.NewProvider(&cfg.Notifications.Telegram)
+		if cfg.Approval.EscalationMinutes > 0 && cfg.Approval.EscalationProvider == "telegram" {
+			telegramProvider.SetEscalationChatID(cfg.Approval.EscalationTarget)
+		}
 		notificationMgr.RegisterProvider(telegramProvider)
 	}
 	// Always register webhook provider (enabled state checked dynamically via credentials store)
@@ -129,9 +152,13 @@ func New(cfg *config.Config, db *database.DB) (*Server, error) {
 
 	// Set notification manager on engine
 	eng.SetNotifier(notificationMgr)
+	oauthMgr.SetAlerter(notificationMgr)
 
 	// Initialize webhook client
-	webhookClient := webhook.NewClient(&cfg.Moltbot, db)
+	webhookClient, err := webhook.NewClient(&cfg.Moltbot, db)
+	if err != nil {
+		return nil, err
+	}
 	eng.SetWebhookClient(webhookClient)
 
 	// Initialize session manager
@@ -143,6 +170,7 @@ func New(cfg *config.Config, db *database.DB) (*Server, error) {
 	// Initialize API handler
 	apiHandler := api.NewHandler(
 		cfg,
+		db,
 		eng,
 		requestRepo,
 		apiKeyRepo,
@@ -151,6 +179,8 @@ func New(cfg *config.Config, db *database.DB) (*Server, error) {
 		notificationMgr,
 		auditLogger,
 	)
+	apiHandler.SetWebhookPinger(webhookClient)
+	apiHandler.SetWebhookFailures(webhookClient)
 
 	// Initialize web handler
 	webHandler, err := web.NewHandler(
@@ -169,33 +199,46 @@ func New(cfg *config.Config, db *database.DB) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
+	webHandler.SetWebhookPinger(webhookClient)
+	webHandler.SetWebhookFailures(webhookClient)
 
 	// Initialize workers
 	timeoutWorker := workers.NewTimeoutWorker(requestRepo, db, eng, &cfg.Approval, 30*time.Second)
+	timeoutWorker.SetAuditLogger(auditLogger)
+	timeoutWorker.SetKeyLookup(apiKeyRepo)
+	reminderWorker := workers.NewReminderWorker(requestRepo, eng, &cfg.Approval, time.Minute)
+	escalationWorker := workers.NewEscalationWorker(requestRepo, eng, &cfg.Approval, time.Minute)
 	cleanupWorker := workers.NewCleanupWorker(db, &cfg.Retention)
+	apiHandler.SetRetentionPreviewer(cleanupWorker)
+	apiHandler.SetOAuthStatus(oauthMgr)
 
 	s := &Server{
-		config:          cfg,
-		db:              db,
-		router:          http.NewServeMux(),
-		apiKeyRepo:      apiKeyRepo,
-		requestRepo:     requestRepo,
-		tokenRepo:       tokenRepo,
-		apiKeyHasher:    apiKeyHasher,
-		encryptor:       encryptor,
-		rateLimiter:     rateLimiter,
-		displayFormat:   displayFormat,
-		oauthMgr:        oauthMgr,
-		calendarClient:  calendarClient,
-		engine:          eng,
-		notificationMgr: notificationMgr,
-		webhookClient:   webhookClient,
-		auditLogger:     auditLogger,
-		sessionMgr:      sessionMgr,
-		apiHandler:      apiHandler,
-		webHandler:      webHandler,
-		timeoutWorker:   timeoutWorker,
-		cleanupWorker:   cleanupWorker,
+		config:           cfg,
+		db:               db,
+		router:           http.NewServeMux(),
+		apiKeyRepo:       apiKeyRepo,
+		requestRepo:      requestRepo,
+		tokenRepo:        tokenRepo,
+		apiKeyHasher:     apiKeyHasher,
+		encryptor:        encryptor,
+		rateLimiter:      rateLimiter,
+		clientIPs:        middleware.NewClientIPResolver(cfg.Server.ClientIPHeaders, cfg.Server.TrustedProxies),
+		displayFormat:    displayFormat,
+		oauthMgr:         oauthMgr,
+		calendarClient:   calendarClient,
+		engine:           eng,
+		notificationMgr:  notificationMgr,
+		webhookClient:    webhookClient,
+		auditClient:      auditClient,
+		settingsClient:   settingsClient,
+		auditLogger:      auditLogger,
+		sessionMgr:       sessionMgr,
+		apiHandler:       apiHandler,
+		webHandler:       webHandler,
+		timeoutWorker:    timeoutWorker,
+		reminderWorker:   reminderWorker,
+		escalationWorker: escalationWorker,
+		cleanupWorker:    cleanupWorker,
 	}
 
 	// Initialize Telegram webhook handler if enabled
@@ -214,12 +257,18 @@ func (s *Server) Handler() http.Handler {
 	// Build middleware chain (applied in reverse order)
 	var handler http.Handler = s.router
 
+	// Cap request bodies (API JSON, web forms, provider webhooks)
+	handler = middleware.MaxBodyBytes(s.config.Server.MaxBodyBytes)(handler)
+
 	// Recovery middleware (outermost - catches panics)
 	handler = middleware.Recovery(handler)
 
 	// Logging middleware
 	handler = middleware.Logging(handler)
 
+	// Resolve the client IP behind trusted proxies for everything above
+	handler = s.clientIPs.Middleware(handler)
+
 	// CORS middleware (if needed for external API access)
 	handler = middleware.CORS(handler)
 
@@ -237,12 +286,22 @@ func (s *Server) StartBackgroundWorkers(ctx context.Context) error {
 	// Start timeout worker
 	go s.timeoutWorker.Start(ctx)
 
+	// Start expiry reminder worker
+	go s.reminderWorker.Start(ctx)
+
+	// Start escalation worker
+	go s.escalationWorker.Start(ctx)
+
 	// Start cleanup worker
 	go s.cleanupWorker.Start(ctx)
 
 	// Start webhook retry worker
 	go s.webhookClient.StartRetryWorker(ctx)
 
+	// Start audit and settings webhook workers
+	go s.auditClient.Start(ctx)
+	go s.settingsClient.Start(ctx)
+
 	// Register Telegram webhook if enabled
 	if s.config.Notifications.Telegram.Enabled && s.config.Notifications.Telegram.BotToken != "" && s.config.Notifications.Telegram.AutoRegisterWebhook {
 		webhookURL := s.config.Server.BaseURL + s.config.Notifications.Telegram.WebhookPath
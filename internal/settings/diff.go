@@ -0,0 +1,78 @@
+package settings
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+)
+
+// Redacted stands in for secret values in a Change.
+const Redacted = "[redacted]"
+
+// Change is one setting that differs between two snapshots.
+type Change struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// Snapshot returns the config values runtime settings can change, keyed
+// like the settings audit entry.
+func Snapshot(cfg *config.Config) map[string]interface{} {
+	routes := make([]string, 0, len(cfg.Approval.Routes))
+	for _, route := range cfg.Approval.Routes {
+		routes = append(routes, fmt.Sprintf("%s %s %s", route.Calendar, route.Provider, route.Target))
+	}
+	return map[string]interface{}{
+		"approval_timeout_minutes":  cfg.Approval.TimeoutMinutes,
+		"approval_default_action":   cfg.Approval.DefaultAction,
+		"approvers":                 strings.Join(cfg.Approval.Approvers, ", "),
+		"approval_routes":           strings.Join(routes, ", "),
+		"approval_require_all":      cfg.Approval.RequireAll,
+		"retention_enabled":         cfg.Retention.Enabled,
+		"retention_completed_days":  cfg.Retention.CompletedRequestsDays,
+		"retention_audit_days":      cfg.Retention.AuditLogDays,
+		"retention_webhook_days":    cfg.Retention.WebhookFailuresDays,
+		"retention_unclaimed_hours": cfg.Retention.UnclaimedResultsHours,
+		"logging_level":             cfg.Logging.Level,
+		"logging_format":            cfg.Logging.Format,
+		"display_timezone":          cfg.Display.Timezone,
+		"display_date_format":       cfg.Display.DateFormat,
+		"display_time_format":       cfg.Display.TimeFormat,
+		"display_datetime_format":   cfg.Display.DatetimeFormat,
+		"display_locale":            cfg.Display.Locale,
+		"display_relative_time":     cfg.Display.RelativeTime,
+		"server_base_url":           cfg.Server.BaseURL,
+	}
+}
+
+// Diff lists the fields whose values differ between two snapshots, sorted
+// by field. A field missing from one side compares as nil. Secret fields,
+// matched on the name after the last ".", report only that they changed:
+// both values are Redacted.
+func Diff(before, after map[string]interface{}, secret map[string]bool) []Change {
+	fields := make(map[string]bool, len(after))
+	for field := range before {
+		fields[field] = true
+	}
+	for field := range after {
+		fields[field] = true
+	}
+
+	changes := []Change{}
+	for field := range fields {
+		old, updated := before[field], after[field]
+		if reflect.DeepEqual(old, updated) {
+			continue
+		}
+		if secret[field[strings.LastIndex(field, ".")+1:]] {
+			old, updated = Redacted, Redacted
+		}
+		changes = append(changes, Change{Field: field, Old: old, New: updated})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
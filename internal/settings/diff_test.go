@@ -0,0 +1,55 @@
+package settings
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+)
+
+func TestDiffRedactsSecrets(t *testing.T) {
+	before := map[string]interface{}{
+		"ntfy.enabled": true,
+		"ntfy.topic":   "alerts",
+		"ntfy.token":   "tk_old",
+		"ntfy.server":  "https://ntfy.sh",
+	}
+	after := map[string]interface{}{
+		"ntfy.enabled":  true,
+		"ntfy.topic":    "pager",
+		"ntfy.token":    "tk_new",
+		"ntfy.server":   "https://ntfy.sh",
+		"ntfy.priority": "high",
+	}
+
+	got := Diff(before, after, map[string]bool{"token": true})
+	want := []Change{
+		{Field: "ntfy.priority", Old: nil, New: "high"},
+		{Field: "ntfy.token", Old: Redacted, New: Redacted},
+		{Field: "ntfy.topic", Old: "alerts", New: "pager"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Approval.TimeoutMinutes = 15
+	before := Snapshot(cfg)
+
+	cfg.Approval.TimeoutMinutes = 30
+	cfg.Approval.Routes = []config.ApprovalRoute{{Calendar: "team@example.com", Provider: "ntfy", Target: "team"}}
+	got := Diff(before, Snapshot(cfg), nil)
+
+	want := []Change{
+		{Field: "approval_routes", Old: "", New: "team@example.com ntfy team"},
+		{Field: "approval_timeout_minutes", Old: 15, New: 30},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff() = %+v, want %+v", got, want)
+	}
+	if changes := Diff(before, before, nil); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %+v", changes)
+	}
+}
@@ -36,8 +36,13 @@ type RuntimeSettings struct {
 }
 
 type ApprovalSettings struct {
-	TimeoutMinutes int    `json:"timeout_minutes"`
-	DefaultAction  string `json:"default_action"`
+	TimeoutMinutes int      `json:"timeout_minutes"`
+	DefaultAction  string   `json:"default_action"`
+	Approvers      []string `json:"approvers"` // nil keeps the configured list; empty clears it
+
+	Routes []config.ApprovalRoute `json:"routes"` // nil keeps the configured routes; empty clears them
+
+	RequireAll *bool `json:"require_all,omitempty"` // nil keeps the configured value
 }
 
 type RetentionSettings struct {
@@ -45,6 +50,7 @@ type RetentionSettings struct {
 	CompletedRequestsDays int   `json:"completed_requests_days"`
 	AuditLogDays          int   `json:"audit_log_days"`
 	WebhookFailuresDays   int   `json:"webhook_failures_days"`
+	UnclaimedResultsHours *int  `json:"unclaimed_results_hours,omitempty"` // nil keeps the configured value; 0 disables
 }
 
 type LoggingSettings struct {
@@ -57,6 +63,8 @@ type DisplaySettings struct {
 	DateFormat     string `json:"date_format"`
 	TimeFormat     string `json:"time_format"`
 	DatetimeFormat string `json:"datetime_format"`
+	Locale         string `json:"locale,omitempty"`
+	RelativeTime   *bool  `json:"relative_time,omitempty"`
 }
 
 // ServerSettings holds server configuration.
@@ -180,6 +188,12 @@ func (s *RuntimeSettings) Validate() error {
 		if s.Approval.DefaultAction != "" && s.Approval.DefaultAction != "approve" && s.Approval.DefaultAction != "deny" {
 			return fmt.Errorf("approval default action must be approve or deny")
 		}
+		if err := config.ValidateApprovers(s.Approval.Approvers); err != nil {
+			return err
+		}
+		if err := config.ValidateApprovalRoutes(s.Approval.Routes); err != nil {
+			return err
+		}
 	}
 	if s.Retention != nil {
 		if s.Retention.CompletedRequestsDays < 1 || s.Retention.CompletedRequestsDays > 3650 {
@@ -191,6 +205,9 @@ func (s *RuntimeSettings) Validate() error {
 		if s.Retention.WebhookFailuresDays < 1 || s.Retention.WebhookFailuresDays > 3650 {
 			return fmt.Errorf("webhook failures retention must be between 1 and 3650 days")
 		}
+		if h := s.Retention.UnclaimedResultsHours; h != nil && (*h < 0 || *h > 87600) {
+			return fmt.Errorf("unclaimed results retention must be between 0 and 87600 hours")
+		}
 	}
 	if s.Logging != nil {
 		if s.Logging.Level != "" {
@@ -205,10 +222,17 @@ func (s *RuntimeSettings) Validate() error {
 		}
 	}
 	if s.Display != nil && s.Display.Timezone != "" {
-		if _, err := util.NewDisplayFormatter(s.Display.Timezone, "", "", ""); err != nil {
+		if _, err := util.NewDisplayFormatter(s.Display.Timezone, "", "", "", "", false); err != nil {
 			return fmt.Errorf("invalid display timezone: %w", err)
 		}
 	}
+	if s.Display != nil && s.Display.Locale != "" {
+		locale, err := util.NormalizeLocale(s.Display.Locale)
+		if err != nil {
+			return fmt.Errorf("invalid display locale: %w", err)
+		}
+		s.Display.Locale = locale
+	}
 	if s.Server != nil && s.Server.BaseURL != "" {
 		if !strings.HasPrefix(s.Server.BaseURL, "http://") && !strings.HasPrefix(s.Server.BaseURL, "https://") {
 			return fmt.Errorf("base_url must start with http:// or https://")
@@ -235,6 +259,15 @@ func (s *RuntimeSettings) ApplyTo(cfg *config.Config) error {
 		if s.Approval.DefaultAction != "" {
 			cfg.Approval.DefaultAction = s.Approval.DefaultAction
 		}
+		if s.Approval.Approvers != nil {
+			cfg.Approval.Approvers = s.Approval.Approvers
+		}
+		if s.Approval.Routes != nil {
+			cfg.Approval.Routes = s.Approval.Routes
+		}
+		if s.Approval.RequireAll != nil {
+			cfg.Approval.RequireAll = *s.Approval.RequireAll
+		}
 	}
 	if s.Retention != nil {
 		if s.Retention.Enabled != nil {
@@ -249,6 +282,9 @@ func (s *RuntimeSettings) ApplyTo(cfg *config.Config) error {
 		if s.Retention.WebhookFailuresDays > 0 {
 			cfg.Retention.WebhookFailuresDays = s.Retention.WebhookFailuresDays
 		}
+		if s.Retention.UnclaimedResultsHours != nil {
+			cfg.Retention.UnclaimedResultsHours = *s.Retention.UnclaimedResultsHours
+		}
 	}
 	if s.Logging != nil {
 		if s.Logging.Level != "" {
@@ -271,6 +307,12 @@ func (s *RuntimeSettings) ApplyTo(cfg *config.Config) error {
 		if s.Display.DatetimeFormat != "" {
 			cfg.Display.DatetimeFormat = s.Display.DatetimeFormat
 		}
+		if s.Display.Locale != "" {
+			cfg.Display.Locale = s.Display.Locale
+		}
+		if s.Display.RelativeTime != nil {
+			cfg.Display.RelativeTime = *s.Display.RelativeTime
+		}
 	}
 	if s.Server != nil && s.Server.BaseURL != "" {
 		cfg.Server.BaseURL = s.Server.BaseURL
@@ -22,6 +22,7 @@ func TestRuntimeSettingsApplyTo(t *testing.T) {
 			CompletedRequestsDays: 10,
 			AuditLogDays:          20,
 			WebhookFailuresDays:   30,
+			UnclaimedResultsHours: 12,
 		},
 		Logging: config.LoggingConfig{
 			Level:  "info",
@@ -36,16 +37,21 @@ func TestRuntimeSettingsApplyTo(t *testing.T) {
 	}
 
 	retentionEnabled := false
+	unclaimedHours := 0
+	requireAll := true
 	settings := &RuntimeSettings{
 		Approval: &ApprovalSettings{
 			TimeoutMinutes: 45,
 			DefaultAction:  "approve",
+			Approvers:      []string{"Dana Lee", "sam@example.com"},
+			RequireAll:     &requireAll,
 		},
 		Retention: &RetentionSettings{
 			Enabled:               &retentionEnabled,
 			CompletedRequestsDays: 60,
 			AuditLogDays:          90,
 			WebhookFailuresDays:   120,
+			UnclaimedResultsHours: &unclaimedHours,
 		},
 		Logging: &LoggingSettings{
 			Level:  "debug",
@@ -69,6 +75,12 @@ func TestRuntimeSettingsApplyTo(t *testing.T) {
 	if cfg.Approval.DefaultAction != "approve" {
 		t.Fatalf("expected approval default approve, got %s", cfg.Approval.DefaultAction)
 	}
+	if len(cfg.Approval.Approvers) != 2 || cfg.Approval.Approvers[1] != "sam@example.com" {
+		t.Fatalf("expected approvers to be applied, got %v", cfg.Approval.Approvers)
+	}
+	if !cfg.Approval.RequireAll {
+		t.Fatalf("expected require_all to be turned on")
+	}
 	if cfg.Retention.CompletedRequestsDays != 60 {
 		t.Fatalf("expected retention requests 60, got %d", cfg.Retention.CompletedRequestsDays)
 	}
@@ -78,6 +90,9 @@ func TestRuntimeSettingsApplyTo(t *testing.T) {
 	if cfg.Retention.WebhookFailuresDays != 120 {
 		t.Fatalf("expected retention webhook 120, got %d", cfg.Retention.WebhookFailuresDays)
 	}
+	if cfg.Retention.UnclaimedResultsHours != 0 {
+		t.Fatalf("expected unclaimed results TTL to be turned off, got %d", cfg.Retention.UnclaimedResultsHours)
+	}
 	if cfg.Retention.Enabled {
 		t.Fatalf("expected retention enabled false")
 	}
@@ -108,6 +123,16 @@ func TestRuntimeSettingsValidate(t *testing.T) {
 		t.Fatalf("expected validation error for approval timeout")
 	}
 
+	settings = &RuntimeSettings{
+		Approval: &ApprovalSettings{
+			TimeoutMinutes: 30,
+			Approvers:      []string{"Dana", "dana"},
+		},
+	}
+	if err := settings.Validate(); err == nil {
+		t.Fatalf("expected validation error for duplicate approvers")
+	}
+
 	settings = &RuntimeSettings{
 		Logging: &LoggingSettings{
 			Level: "verbose",
@@ -125,6 +150,27 @@ func TestRuntimeSettingsValidate(t *testing.T) {
 	if err := settings.Validate(); err == nil {
 		t.Fatalf("expected validation error for timezone")
 	}
+
+	settings = &RuntimeSettings{
+		Display: &DisplaySettings{
+			Locale: "tlh",
+		},
+	}
+	if err := settings.Validate(); err == nil {
+		t.Fatalf("expected validation error for locale")
+	}
+
+	settings = &RuntimeSettings{
+		Display: &DisplaySettings{
+			Locale: "fr-CA",
+		},
+	}
+	if err := settings.Validate(); err != nil {
+		t.Fatalf("expected regional locale to be accepted: %v", err)
+	}
+	if settings.Display.Locale != "fr" {
+		t.Fatalf("expected locale normalized to fr, got %s", settings.Display.Locale)
+	}
 }
 
 func TestStoreSaveLoad(t *testing.T) {
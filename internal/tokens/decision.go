@@ -25,7 +25,10 @@ func NewRepository(db *database.DB) *Repository {
 
 // Create generates and stores a new decision token for a request.
 // Returns the token (to be used in URLs) - store the hash only.
-func (r *Repository) Create(ctx context.Context, requestID string, expiresAt time.Time) (string, error) {
+// pinSatisfied marks links created by an authenticated admin, which skip
+// the approval PIN. The flag is stored with the token hash, so it cannot
+// be added to an existing link.
+func (r *Repository) Create(ctx context.Context, requestID string, expiresAt time.Time, pinSatisfied bool) (string, error) {
 	token, hash, err := crypto.GenerateDecisionToken()
 	if err != nil {
 		return "", fmt.Errorf("failed to generate token: %w", err)
@@ -34,9 +37,9 @@ func (r *Repository) Create(ctx context.Context, requestID string, expiresAt tim
 	allowedActions, _ := json.Marshal([]string{"approve", "deny", "suggest"})
 
 	_, err = r.db.ExecContext(ctx, `
-		INSERT INTO decision_tokens (token_hash, request_id, allowed_actions, expires_at)
-		VALUES (?, ?, ?, ?)
-	`, hash, requestID, string(allowedActions), util.SQLiteTimestamp(expiresAt))
+		INSERT INTO decision_tokens (token_hash, request_id, allowed_actions, expires_at, pin_satisfied)
+		VALUES (?, ?, ?, ?, ?)
+	`, hash, requestID, string(allowedActions), util.SQLiteTimestamp(expiresAt), pinSatisfied)
 
 	if err != nil {
 		return "", fmt.Errorf("failed to store token: %w", err)
@@ -49,6 +52,7 @@ func (r *Repository) Create(ctx context.Context, requestID string, expiresAt tim
 type ValidateResult struct {
 	RequestID      string
 	AllowedActions []string
+	PinSatisfied   bool
 	Valid          bool
 	Error          string
 }
@@ -63,13 +67,14 @@ func (r *Repository) Validate(ctx context.Context, token string) (*ValidateResul
 		expiresAt      string
 		consumedAt     sql.NullString
 		consumedAction sql.NullString
+		pinSatisfied   bool
 	)
 
 	err := r.db.QueryRowContext(ctx, `
-		SELECT request_id, allowed_actions, expires_at, consumed_at, consumed_action
+		SELECT request_id, allowed_actions, expires_at, consumed_at, consumed_action, pin_satisfied
 		FROM decision_tokens
 		WHERE token_hash = ?
-	`, hash).Scan(&requestID, &allowedJSON, &expiresAt, &consumedAt, &consumedAction)
+	`, hash).Scan(&requestID, &allowedJSON, &expiresAt, &consumedAt, &consumedAction, &pinSatisfied)
 
 	if err == sql.ErrNoRows {
 		return &ValidateResult{Valid: false, Error: "token not found"}, nil
@@ -104,6 +109,7 @@ func (r *Repository) Validate(ctx context.Context, token string) (*ValidateResul
 	return &ValidateResult{
 		RequestID:      requestID,
 		AllowedActions: allowedActions,
+		PinSatisfied:   pinSatisfied,
 		Valid:          true,
 	}, nil
 }
@@ -157,7 +163,7 @@ func (r *Repository) Consume(ctx context.Context, token, action string) (string,
 // GetByRequestID retrieves all tokens for a request.
 func (r *Repository) GetByRequestID(ctx context.Context, requestID string) ([]database.DecisionToken, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT token_hash, request_id, allowed_actions, expires_at, consumed_at, consumed_action, created_at
+		SELECT token_hash, request_id, allowed_actions, expires_at, consumed_at, consumed_action, created_at, pin_satisfied
 		FROM decision_tokens
 		WHERE request_id = ?
 	`, requestID)
@@ -179,7 +185,7 @@ func (r *Repository) GetByRequestID(ctx context.Context, requestID string) ([]da
 
 		if err := rows.Scan(
 			&tok.TokenHash, &tok.RequestID, &allowedJSON,
-			&expiresAt, &consumedAt, &tok.ConsumedAction, &createdAt,
+			&expiresAt, &consumedAt, &tok.ConsumedAction, &createdAt, &tok.PinSatisfied,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,86 @@
+package tokens
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+func setupTestRepo(t *testing.T) (*Repository, time.Time) {
+	t.Helper()
+
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	expiresAt := time.Now().Add(time.Hour)
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_test', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, payload, expires_at)
+		VALUES ('req_test', 'key_test', 'create_event', '{}', ?)
+	`, util.SQLiteTimestamp(expiresAt)); err != nil {
+		t.Fatalf("insert request: %v", err)
+	}
+
+	return NewRepository(db), expiresAt
+}
+
+func TestCreate_PinSatisfiedRoundTrips(t *testing.T) {
+	repo, expiresAt := setupTestRepo(t)
+	ctx := context.Background()
+
+	for _, pinSatisfied := range []bool{false, true} {
+		token, err := repo.Create(ctx, "req_test", expiresAt, pinSatisfied)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+
+		result, err := repo.Validate(ctx, token)
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		if !result.Valid {
+			t.Fatalf("expected valid token, got %q", result.Error)
+		}
+		if result.PinSatisfied != pinSatisfied {
+			t.Errorf("PinSatisfied = %v, want %v", result.PinSatisfied, pinSatisfied)
+		}
+	}
+
+	stored, err := repo.GetByRequestID(ctx, "req_test")
+	if err != nil {
+		t.Fatalf("GetByRequestID failed: %v", err)
+	}
+	bypass := 0
+	for _, tok := range stored {
+		if tok.PinSatisfied {
+			bypass++
+		}
+	}
+	if len(stored) != 2 || bypass != 1 {
+		t.Errorf("expected one bypass token of two, got %d of %d", bypass, len(stored))
+	}
+}
+
+func TestValidate_ForgedTokenDoesNotSkipPIN(t *testing.T) {
+	repo, _ := setupTestRepo(t)
+
+	result, err := repo.Validate(context.Background(), "not-a-real-token")
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if result.Valid || result.PinSatisfied {
+		t.Errorf("unknown token should be invalid without PIN bypass: %+v", result)
+	}
+}
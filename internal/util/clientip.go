@@ -0,0 +1,40 @@
+package util
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+)
+
+type clientIPContextKey struct{}
+
+// WithClientIP returns a context carrying the resolved client IP of a request.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPContextKey{}, ip)
+}
+
+// ClientIPFromContext returns the client IP stored by WithClientIP, or "".
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPContextKey{}).(string)
+	return ip
+}
+
+// ParseIPPrefix parses a single address or a CIDR range. A single address
+// becomes a prefix that contains only itself; IPv4-mapped IPv6 addresses are
+// treated as IPv4.
+func ParseIPPrefix(entry string) (netip.Prefix, error) {
+	entry = strings.TrimSpace(entry)
+	if strings.Contains(entry, "/") {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(entry)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
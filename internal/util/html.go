@@ -0,0 +1,257 @@
+package util
+
+import (
+	"html"
+	"strings"
+)
+
+// allowedHTMLTags are the tags kept by SanitizeHTML: the formatting Google
+// Calendar renders in event descriptions.
+var allowedHTMLTags = map[string]bool{
+	"a": true, "b": true, "strong": true, "i": true, "em": true, "u": true,
+	"br": true, "p": true, "ul": true, "ol": true, "li": true,
+}
+
+// voidHTMLTags never have a closing tag.
+var voidHTMLTags = map[string]bool{"br": true}
+
+// droppedHTMLTags are removed together with everything inside them.
+var droppedHTMLTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "noembed": true, "noframes": true, "template": true,
+	"textarea": true, "title": true, "xmp": true, "svg": true, "math": true,
+}
+
+// allowedLinkSchemes are the URL schemes kept in link targets.
+var allowedLinkSchemes = []string{"http://", "https://", "mailto:"}
+
+// ContainsHTML reports whether s contains anything that looks like an HTML
+// tag or comment.
+func ContainsHTML(s string) bool {
+	return htmlTagRegex.MatchString(s)
+}
+
+// StripHTML removes HTML tags and comments from s, keeping the text.
+func StripHTML(s string) string {
+	return htmlTagRegex.ReplaceAllString(s, "")
+}
+
+// SanitizeDescription cleans an event description. With allowHTML the
+// formatting tags in allowedHTMLTags are kept and everything else is
+// removed; otherwise all tags are stripped and whitespace is normalized
+// like SanitizeString.
+func SanitizeDescription(s string, allowHTML bool) string {
+	if allowHTML {
+		return strings.TrimSpace(SanitizeHTML(s))
+	}
+	return SanitizeString(StripHTML(s))
+}
+
+// SanitizeHTML rebuilds s keeping only allowlisted tags. Allowed tags lose
+// every attribute except a link's href, which must be an http, https or
+// mailto URL. Scripts, styles and similar elements are dropped with their
+// content, other tags are dropped leaving their text, and text is
+// re-escaped. Unclosed tags are closed at the end, so the result is safe to
+// embed in a page.
+func SanitizeHTML(s string) string {
+	var out strings.Builder
+	var open []string
+
+	for len(s) > 0 {
+		lt := strings.IndexByte(s, '<')
+		if lt < 0 {
+			out.WriteString(escapeHTMLText(s))
+			break
+		}
+		out.WriteString(escapeHTMLText(s[:lt]))
+		s = s[lt:]
+
+		// Comments, doctypes and processing instructions are dropped
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s[4:], "-->")
+			if end < 0 {
+				break
+			}
+			s = s[4+end+3:]
+			continue
+		}
+		if strings.HasPrefix(s, "<!") || strings.HasPrefix(s, "<?") {
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				break
+			}
+			s = s[end+1:]
+			continue
+		}
+
+		tag, rest, ok := parseHTMLTag(s)
+		if !ok {
+			// A lone "<" is text
+			out.WriteString("&lt;")
+			s = s[1:]
+			continue
+		}
+		s = rest
+
+		switch {
+		case droppedHTMLTags[tag.name] && !tag.closing:
+			s = skipHTMLElement(s, tag.name)
+		case !allowedHTMLTags[tag.name]:
+		case tag.closing:
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != tag.name {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					out.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+		default:
+			out.WriteString("<" + tag.name)
+			if tag.name == "a" {
+				if href, ok := safeLink(tag.attrs["href"]); ok {
+					out.WriteString(` href="` + html.EscapeString(href) + `"`)
+				}
+			}
+			out.WriteString(">")
+			if !voidHTMLTags[tag.name] {
+				open = append(open, tag.name)
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		out.WriteString("</" + open[i] + ">")
+	}
+	return out.String()
+}
+
+// htmlTag is a parsed start or end tag.
+type htmlTag struct {
+	name    string
+	closing bool
+	attrs   map[string]string
+}
+
+// parseHTMLTag parses the tag at the start of s, which begins with "<". It
+// returns the input after the tag, or ok false when s does not start with a
+// complete tag.
+func parseHTMLTag(s string) (tag htmlTag, rest string, ok bool) {
+	i := 1
+	if i < len(s) && s[i] == '/' {
+		tag.closing = true
+		i++
+	}
+	start := i
+	for i < len(s) && isTagNameChar(s[i], i == start) {
+		i++
+	}
+	if i == start {
+		return tag, s, false
+	}
+	tag.name = strings.ToLower(s[start:i])
+	tag.attrs = make(map[string]string)
+
+	for i < len(s) {
+		switch c := s[i]; {
+		case c == '>':
+			return tag, s[i+1:], true
+		case c == '/' || isHTMLSpace(c):
+			i++
+		default:
+			nameStart := i
+			for i < len(s) && s[i] != '=' && s[i] != '>' && s[i] != '/' && !isHTMLSpace(s[i]) {
+				i++
+			}
+			name := strings.ToLower(s[nameStart:i])
+			for i < len(s) && isHTMLSpace(s[i]) {
+				i++
+			}
+			value := ""
+			if i < len(s) && s[i] == '=' {
+				i++
+				for i < len(s) && isHTMLSpace(s[i]) {
+					i++
+				}
+				if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+					quote := s[i]
+					end := strings.IndexByte(s[i+1:], quote)
+					if end < 0 {
+						return tag, s, false
+					}
+					value = s[i+1 : i+1+end]
+					i += end + 2
+				} else {
+					valueStart := i
+					for i < len(s) && s[i] != '>' && !isHTMLSpace(s[i]) {
+						i++
+					}
+					value = s[valueStart:i]
+				}
+			}
+			if _, seen := tag.attrs[name]; !seen {
+				tag.attrs[name] = html.UnescapeString(value)
+			}
+		}
+	}
+	return tag, s, false
+}
+
+// skipHTMLElement returns s after the end tag closing the named element, or
+// "" when it is never closed.
+func skipHTMLElement(s, name string) string {
+	lower := strings.ToLower(s)
+	for offset := 0; ; {
+		i := strings.Index(lower[offset:], "</"+name)
+		if i < 0 {
+			return ""
+		}
+		i += offset
+		after := i + 2 + len(name)
+		if after < len(s) && isTagNameChar(s[after], false) {
+			offset = after
+			continue
+		}
+		end := strings.IndexByte(s[after:], '>')
+		if end < 0 {
+			return ""
+		}
+		return s[after+end+1:]
+	}
+}
+
+// safeLink returns href when it uses an allowed scheme. Control characters
+// and spaces are removed first, as browsers ignore them in schemes.
+func safeLink(href string) (string, bool) {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, href)
+	lower := strings.ToLower(cleaned)
+	for _, scheme := range allowedLinkSchemes {
+		if strings.HasPrefix(lower, scheme) {
+			return cleaned, true
+		}
+	}
+	return "", false
+}
+
+// escapeHTMLText normalizes entities in text and escapes it again.
+func escapeHTMLText(s string) string {
+	return html.EscapeString(html.UnescapeString(s))
+}
+
+func isTagNameChar(c byte, first bool) bool {
+	if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+		return true
+	}
+	return !first && c >= '0' && c <= '9'
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
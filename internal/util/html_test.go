@@ -0,0 +1,84 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "Agenda & notes", "Agenda &amp; notes"},
+		{"allowed formatting", "<b>Bold</b> <i>it</i> <u>u</u><br/>next", "<b>Bold</b> <i>it</i> <u>u</u><br>next"},
+		{"lists", "<UL><li>one<li>two</UL>", "<ul><li>one<li>two</li></li></ul>"},
+		{"link", `<a href="https://example.com/?a=1&amp;b=2" target="_blank">doc</a>`, `<a href="https://example.com/?a=1&amp;b=2">doc</a>`},
+		{"mailto link", `<a href='mailto:ana@example.com'>mail</a>`, `<a href="mailto:ana@example.com">mail</a>`},
+		{"unknown tags keep text", `<div class="x"><span>hi</span></div>`, "hi"},
+		{"unclosed tags are closed", "<b><i>open", "<b><i>open</i></b>"},
+		{"stray closing tag", "text</b>", "text"},
+		{"lone angle bracket", "a < b > c", "a &lt; b &gt; c"},
+		{"comment", "a<!-- <script>x</script> -->b", "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeHTML(tt.in); got != tt.want {
+				t.Errorf("SanitizeHTML(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeHTMLBlocksXSS(t *testing.T) {
+	attacks := []string{
+		`<script>alert(1)</script>`,
+		`<SCRIPT SRC=//evil.example/x.js></SCRIPT>`,
+		`<scr<script>ipt>alert(1)</script>`,
+		`<img src=x onerror=alert(1)>`,
+		`<svg onload=alert(1)><script>alert(1)</script></svg>`,
+		`<a href="javascript:alert(1)">x</a>`,
+		`<a href="JaVaScRiPt:alert(1)">x</a>`,
+		`<a href="jav&#x09;ascript:alert(1)">x</a>`,
+		`<a href=" javascript:alert(1)">x</a>`,
+		`<a href="data:text/html,<script>alert(1)</script>">x</a>`,
+		`<a href="https://ok.example" onclick="alert(1)">x</a>`,
+		`<b onmouseover=alert(1)>x</b>`,
+		`<style>body{background:url(javascript:alert(1))}</style>`,
+		`<iframe src="https://evil.example"></iframe>`,
+		`<a href="https://ok.example/"onclick=alert(1)>x</a>`,
+		`&lt;script&gt;alert(1)&lt;/script&gt;`,
+		`<a href='https://ok.example/" onclick="alert(1)'>x</a>`,
+		`<p style="x" <script>alert(1)</script>`,
+		`<!--><script>alert(1)</script>-->`,
+		`<math><mtext><script>alert(1)</script></mtext></math>`,
+	}
+	for _, attack := range attacks {
+		got := SanitizeHTML(attack)
+		// Text is escaped, so every "<" left must open an allowlisted tag
+		for i := strings.IndexByte(got, '<'); i >= 0; i = strings.IndexByte(got, '<') {
+			got = got[i:]
+			tag := safeTagPattern.FindString(got)
+			if tag == "" {
+				t.Errorf("SanitizeHTML(%q) kept unsafe markup at %q", attack, got)
+				break
+			}
+			got = got[len(tag):]
+		}
+	}
+}
+
+// safeTagPattern matches the only markup SanitizeHTML may produce.
+var safeTagPattern = regexp.MustCompile(`^(</?(b|strong|i|em|u|br|p|ul|ol|li|a)>|<a href="(https?://|mailto:)[^"<>]*">)`)
+
+func TestSanitizeDescription(t *testing.T) {
+	in := "  <b>Agenda</b>\n<script>alert(1)</script> review  "
+	if got := SanitizeDescription(in, false); got != "Agenda alert(1) review" {
+		t.Errorf("plain = %q", got)
+	}
+	if got := SanitizeDescription(in, true); got != "<b>Agenda</b>\n review" {
+		t.Errorf("html = %q", got)
+	}
+}
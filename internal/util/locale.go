@@ -0,0 +1,126 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultLocale is used when no display locale is configured.
+const DefaultLocale = "en"
+
+// SupportedLocales lists the display locales, by language code.
+var SupportedLocales = []string{"en", "de", "fr", "es"}
+
+// NormalizeLocale reduces a locale such as "de-DE" or "fr_CA" to its
+// language code and reports an error if the language is not supported.
+// An empty locale means DefaultLocale.
+func NormalizeLocale(locale string) (string, error) {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if locale == "" {
+		return DefaultLocale, nil
+	}
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	if _, ok := localeWords[locale]; !ok {
+		return "", fmt.Errorf("unsupported locale %q (supported: %s)", locale, strings.Join(SupportedLocales, ", "))
+	}
+	return locale, nil
+}
+
+// calendarNamePattern matches the English month and weekday names Go's
+// time package writes, full names first so they win over abbreviations.
+var calendarNamePattern = regexp.MustCompile(`\b(January|February|March|April|May|June|July|August|September|October|November|December|Monday|Tuesday|Wednesday|Thursday|Friday|Saturday|Sunday|Jan|Feb|Mar|Apr|Jun|Jul|Aug|Sep|Oct|Nov|Dec|Mon|Tue|Wed|Thu|Fri|Sat|Sun)\b`)
+
+// localeNames maps English month and weekday names to their translation.
+// English needs no entry.
+var localeNames = map[string]map[string]string{
+	"de": calendarNames(
+		[]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		[]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		[]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		[]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	),
+	"fr": calendarNames(
+		[]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		[]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		[]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		[]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	),
+	"es": calendarNames(
+		[]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		[]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic"},
+		[]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		[]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+	),
+}
+
+// calendarNames builds a translation table from names ordered January to
+// December and Sunday to Saturday.
+func calendarNames(months, shortMonths, days, shortDays []string) map[string]string {
+	names := make(map[string]string, 38)
+	for i := range months {
+		month := time.Month(i + 1)
+		names[month.String()] = months[i]
+		// "May" is both the full and the short name; keep the full one.
+		if short := month.String()[:3]; short != month.String() {
+			names[short] = shortMonths[i]
+		}
+	}
+	for i := range days {
+		day := time.Weekday(i)
+		names[day.String()] = days[i]
+		names[day.String()[:3]] = shortDays[i]
+	}
+	return names
+}
+
+// relativeWords holds the phrases used for durations and relative times.
+// in and ago are format strings taking the duration.
+type relativeWords struct {
+	in, ago, expired string
+	// units holds singular and plural forms of seconds, minutes, hours
+	// and days.
+	units [4][2]string
+}
+
+var localeWords = map[string]relativeWords{
+	"en": {
+		in: "in %s", ago: "%s ago", expired: "expired",
+		units: [4][2]string{{"second", "seconds"}, {"minute", "minutes"}, {"hour", "hours"}, {"day", "days"}},
+	},
+	"de": {
+		in: "in %s", ago: "vor %s", expired: "abgelaufen",
+		units: [4][2]string{{"Sekunde", "Sekunden"}, {"Minute", "Minuten"}, {"Stunde", "Stunden"}, {"Tag", "Tagen"}},
+	},
+	"fr": {
+		in: "dans %s", ago: "il y a %s", expired: "expiré",
+		units: [4][2]string{{"seconde", "secondes"}, {"minute", "minutes"}, {"heure", "heures"}, {"jour", "jours"}},
+	},
+	"es": {
+		in: "en %s", ago: "hace %s", expired: "expirado",
+		units: [4][2]string{{"segundo", "segundos"}, {"minuto", "minutos"}, {"hora", "horas"}, {"día", "días"}},
+	},
+}
+
+// duration converts a duration to a human-readable string in the largest
+// whole unit, e.g. "3 hours".
+func (w relativeWords) duration(d time.Duration) string {
+	var n, unit int
+	switch {
+	case d < time.Minute:
+		n, unit = int(d.Seconds()), 0
+	case d < time.Hour:
+		n, unit = int(d.Minutes()), 1
+	case d < 24*time.Hour:
+		n, unit = int(d.Hours()), 2
+	default:
+		n, unit = int(d.Hours()/24), 3
+	}
+	if n == 1 {
+		return "1 " + w.units[unit][0]
+	}
+	return fmt.Sprintf("%d %s", n, w.units[unit][1])
+}
@@ -14,14 +14,25 @@ type DisplayFormatter struct {
 	DateFormat     string
 	TimeFormat     string
 	DatetimeFormat string
+	// Locale selects the language of month and weekday names and of
+	// relative phrases. Layouts still use Go's reference time.
+	Locale string
+	// Relative shows timestamps alongside their distance from now,
+	// e.g. "Jan 2, 2006 at 3:04 PM (in 2 hours)".
+	Relative bool
 }
 
-// NewDisplayFormatter creates a formatter for the specified timezone.
-func NewDisplayFormatter(timezone string, dateFormat, timeFormat, datetimeFormat string) (*DisplayFormatter, error) {
+// NewDisplayFormatter creates a formatter for the specified timezone and
+// locale. An empty locale means English.
+func NewDisplayFormatter(timezone string, dateFormat, timeFormat, datetimeFormat, locale string, relative bool) (*DisplayFormatter, error) {
 	loc, err := time.LoadLocation(timezone)
 	if err != nil {
 		return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
 	}
+	locale, err = NormalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
 
 	if dateFormat == "" {
 		dateFormat = "Jan 2, 2006"
@@ -38,85 +49,120 @@ func NewDisplayFormatter(timezone string, dateFormat, timeFormat, datetimeFormat
 		DateFormat:     dateFormat,
 		TimeFormat:     timeFormat,
 		DatetimeFormat: datetimeFormat,
+		Locale:         locale,
+		Relative:       relative,
 	}, nil
 }
 
 // FormatDate formats a time as date only in local timezone.
 func (f *DisplayFormatter) FormatDate(t time.Time) string {
-	return t.In(f.Location).Format(f.DateFormat)
+	return f.format(t, f.DateFormat)
 }
 
 // FormatTime formats a time as time only in local timezone.
 func (f *DisplayFormatter) FormatTime(t time.Time) string {
-	return t.In(f.Location).Format(f.TimeFormat)
+	return f.format(t, f.TimeFormat)
 }
 
 // FormatDateTime formats a time as full datetime in local timezone.
 func (f *DisplayFormatter) FormatDateTime(t time.Time) string {
-	return t.In(f.Location).Format(f.DatetimeFormat)
+	return f.format(t, f.DatetimeFormat)
+}
+
+// FormatTimestamp formats a full datetime for display, followed by the
+// relative time when relative mode is on.
+func (f *DisplayFormatter) FormatTimestamp(t time.Time) string {
+	return f.withRelative(f.FormatDateTime(t), t)
+}
+
+// FormatLayout formats a time with the given layout in its own timezone,
+// translating month and weekday names.
+func (f *DisplayFormatter) FormatLayout(t time.Time, layout string) string {
+	return f.translate(t.Format(layout))
+}
+
+// FormatEventTime formats an event time like FormatLayout, followed by the
+// relative time when relative mode is on. Notifications use it so an
+// event's own timezone is kept.
+func (f *DisplayFormatter) FormatEventTime(t time.Time, layout string) string {
+	return f.withRelative(f.FormatLayout(t, layout), t)
 }
 
-// FormatDateTimeWithZone formats with timezone abbreviation.
+// FormatDateTimeWithZone formats with timezone abbreviation, followed by
+// the relative time when relative mode is on.
 func (f *DisplayFormatter) FormatDateTimeWithZone(t time.Time) string {
-	local := t.In(f.Location)
-	zone, _ := local.Zone()
-	return local.Format(f.DatetimeFormat) + " " + zone
+	zone, _ := t.In(f.Location).Zone()
+	return f.withRelative(f.FormatDateTime(t)+" "+zone, t)
+}
+
+// FormatDateTimeIn formats a full datetime with its zone abbreviation in
+// loc instead of the display timezone, for viewers in another timezone.
+func (f *DisplayFormatter) FormatDateTimeIn(t time.Time, loc *time.Location) string {
+	t = t.In(loc)
+	zone, _ := t.Zone()
+	return f.translate(t.Format(f.DatetimeFormat)) + " " + zone
 }
 
 // FormatRelative formats a time relative to now (e.g., "in 47 minutes", "2 hours ago").
 func (f *DisplayFormatter) FormatRelative(t time.Time) string {
-	now := time.Now()
-	diff := t.Sub(now)
+	words := f.words()
+	diff := time.Until(t)
 
 	if diff < 0 {
 		// Past
-		diff = -diff
-		return formatDuration(diff) + " ago"
+		return fmt.Sprintf(words.ago, words.duration(-diff))
 	}
 	// Future
-	return "in " + formatDuration(diff)
+	return fmt.Sprintf(words.in, words.duration(diff))
 }
 
-// FormatExpiresIn formats expiry time for notifications.
+// FormatExpiresIn formats expiry time for notifications. In relative mode
+// the absolute expiry time follows the duration.
 func (f *DisplayFormatter) FormatExpiresIn(expiresAt time.Time) string {
+	words := f.words()
 	diff := time.Until(expiresAt)
 	if diff <= 0 {
-		return "expired"
+		return words.expired
 	}
-	return formatDuration(diff)
+	if f.Relative {
+		return words.duration(diff) + " (" + f.FormatDateTime(expiresAt) + ")"
+	}
+	return words.duration(diff)
 }
 
-// formatDuration converts a duration to human-readable string.
-func formatDuration(d time.Duration) string {
-	if d < time.Minute {
-		secs := int(d.Seconds())
-		if secs == 1 {
-			return "1 second"
-		}
-		return fmt.Sprintf("%d seconds", secs)
-	}
+// format renders t in the display timezone.
+func (f *DisplayFormatter) format(t time.Time, layout string) string {
+	return f.translate(t.In(f.Location).Format(layout))
+}
 
-	if d < time.Hour {
-		mins := int(d.Minutes())
-		if mins == 1 {
-			return "1 minute"
-		}
-		return fmt.Sprintf("%d minutes", mins)
+// translate replaces English month and weekday names in formatted with
+// those of the formatter's locale.
+func (f *DisplayFormatter) translate(formatted string) string {
+	names := localeNames[f.Locale]
+	if names == nil {
+		return formatted
 	}
-
-	if d < 24*time.Hour {
-		hours := int(d.Hours())
-		if hours == 1 {
-			return "1 hour"
+	return calendarNamePattern.ReplaceAllStringFunc(formatted, func(name string) string {
+		if translated, ok := names[name]; ok {
+			return translated
 		}
-		return fmt.Sprintf("%d hours", hours)
+		return name
+	})
+}
+
+// withRelative appends the relative time to formatted in relative mode.
+func (f *DisplayFormatter) withRelative(formatted string, t time.Time) string {
+	if !f.Relative {
+		return formatted
 	}
+	return formatted + " (" + f.FormatRelative(t) + ")"
+}
 
-	days := int(d.Hours() / 24)
-	if days == 1 {
-		return "1 day"
+func (f *DisplayFormatter) words() relativeWords {
+	if words, ok := localeWords[f.Locale]; ok {
+		return words
 	}
-	return fmt.Sprintf("%d days", days)
+	return localeWords[DefaultLocale]
 }
 
 // ParseRFC3339 parses an RFC3339 timestamp.
@@ -149,7 +195,7 @@ var defaultFormatter *DisplayFormatter
 
 func init() {
 	// Create a default formatter with UTC timezone
-	defaultFormatter, _ = NewDisplayFormatter("UTC", "", "", "")
+	defaultFormatter, _ = NewDisplayFormatter("UTC", "", "", "", "", false)
 }
 
 // SetDefaultFormatter sets the global default formatter.
@@ -0,0 +1,120 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDisplayFormatterLocales(t *testing.T) {
+	// 17:30 UTC on a Sunday is Monday morning in Tokyo.
+	ts := time.Date(2026, time.March, 1, 17, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		timezone string
+		locale   string
+		want     string
+	}{
+		{"UTC", "", "Sunday, March 1, 2026 17:30"},
+		{"America/New_York", "en", "Sunday, March 1, 2026 12:30"},
+		{"Europe/Berlin", "de-DE", "Sonntag, März 1, 2026 18:30"},
+		{"Europe/Paris", "fr", "dimanche, mars 1, 2026 18:30"},
+		{"America/Mexico_City", "es_MX", "domingo, marzo 1, 2026 11:30"},
+		{"Asia/Tokyo", "de", "Montag, März 2, 2026 02:30"},
+	}
+	for _, tt := range tests {
+		f, err := NewDisplayFormatter(tt.timezone, "", "", "Monday, January 2, 2006 15:04", tt.locale, false)
+		if err != nil {
+			t.Fatalf("NewDisplayFormatter(%s, %s): %v", tt.timezone, tt.locale, err)
+		}
+		if got := f.FormatDateTime(ts); got != tt.want {
+			t.Errorf("%s/%s: got %q, want %q", tt.timezone, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayFormatterAbbreviations(t *testing.T) {
+	ts := time.Date(2026, time.May, 5, 9, 0, 0, 0, time.UTC)
+	f, err := NewDisplayFormatter("UTC", "Mon Jan 2", "", "", "es", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.FormatDate(ts); got != "mar mayo 5" {
+		t.Fatalf("expected Spanish abbreviations, got %q", got)
+	}
+}
+
+func TestDisplayFormatterRejectsUnknownLocale(t *testing.T) {
+	if _, err := NewDisplayFormatter("UTC", "", "", "", "xx", false); err == nil {
+		t.Fatal("expected unsupported locale to be rejected")
+	}
+}
+
+func TestDisplayFormatterRelative(t *testing.T) {
+	start := time.Now().Add(2*time.Hour + time.Minute)
+
+	for _, tz := range []string{"UTC", "America/Los_Angeles", "Asia/Kolkata"} {
+		f, err := NewDisplayFormatter(tz, "", "", "", "", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := f.FormatDateTimeWithZone(start)
+		zone, _ := start.In(f.Location).Zone()
+		want := start.In(f.Location).Format(f.DatetimeFormat) + " " + zone + " (in 2 hours)"
+		if got != want {
+			t.Errorf("%s: got %q, want %q", tz, got, want)
+		}
+	}
+
+	f, _ := NewDisplayFormatter("UTC", "", "", "", "fr", true)
+	if got := f.FormatRelative(time.Now().Add(-3 * 24 * time.Hour)); got != "il y a 3 jours" {
+		t.Errorf("expected French past relative time, got %q", got)
+	}
+
+	f.Relative = false
+	if got := f.FormatTimestamp(start); strings.Contains(got, "(") {
+		t.Errorf("expected no relative time when disabled, got %q", got)
+	}
+}
+
+func TestFormatExpiresIn(t *testing.T) {
+	expiresAt := time.Now().Add(15*time.Minute + 30*time.Second)
+
+	f, _ := NewDisplayFormatter("America/New_York", "", "", "", "", false)
+	if got := f.FormatExpiresIn(expiresAt); got != "15 minutes" {
+		t.Errorf("got %q", got)
+	}
+	if got := f.FormatExpiresIn(time.Now().Add(-time.Minute)); got != "expired" {
+		t.Errorf("got %q", got)
+	}
+
+	f, _ = NewDisplayFormatter("Europe/Berlin", "", "15:04", "15:04", "de", true)
+	want := "15 Minuten (" + expiresAt.In(f.Location).Format("15:04") + ")"
+	if got := f.FormatExpiresIn(expiresAt); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := f.FormatExpiresIn(time.Now().Add(-time.Minute)); got != "abgelaufen" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestFormatEventTimeKeepsEventZone(t *testing.T) {
+	pacific := time.FixedZone("PDT", -7*3600)
+	ts := time.Date(2026, time.October, 6, 15, 0, 0, 0, pacific)
+
+	f, _ := NewDisplayFormatter("Europe/Paris", "", "", "", "fr", false)
+	if got := f.FormatEventTime(ts, "Mon Jan 2, 15:04 MST"); got != "mar. oct. 6, 15:00 PDT" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestFormatDateTimeIn(t *testing.T) {
+	// 3pm Pacific is 6pm Eastern; the display timezone is ignored
+	ts := time.Date(2026, time.October, 6, 15, 0, 0, 0, time.FixedZone("", -7*3600))
+	eastern, _ := time.LoadLocation("America/New_York")
+
+	f, _ := NewDisplayFormatter("Europe/Berlin", "", "", "Mon Jan 2, 3:04 PM", "", true)
+	if got := f.FormatDateTimeIn(ts, eastern); got != "Tue Oct 6, 6:00 PM EDT" {
+		t.Fatalf("got %q", got)
+	}
+}
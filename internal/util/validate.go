@@ -5,24 +5,67 @@ import (
 	"fmt"
 	"net/mail"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // Validation errors
 var (
-	ErrEmptyField       = fmt.Errorf("field cannot be empty")
-	ErrInvalidEmail     = fmt.Errorf("invalid email address")
-	ErrInvalidTime      = fmt.Errorf("invalid time format (expected RFC3339)")
-	ErrEndBeforeStart   = fmt.Errorf("end time must be after start time")
-	ErrPastTime         = fmt.Errorf("time cannot be in the past")
-	ErrInvalidCalendarID = fmt.Errorf("invalid calendar ID")
-	ErrInvalidColorID   = fmt.Errorf("invalid color ID (must be 1-11)")
-	ErrInvalidVisibility = fmt.Errorf("invalid visibility (must be default, public, or private)")
-	ErrDurationTooLong  = fmt.Errorf("event duration exceeds maximum allowed")
-	ErrTooManyAttendees = fmt.Errorf("too many attendees")
+	ErrEmptyField          = fmt.Errorf("field cannot be empty")
+	ErrInvalidEmail        = fmt.Errorf("invalid email address")
+	ErrInvalidTime         = fmt.Errorf("invalid time format (expected RFC3339)")
+	ErrEndBeforeStart      = fmt.Errorf("end time must be after start time")
+	ErrPastTime            = fmt.Errorf("time cannot be in the past")
+	ErrInvalidCalendarID   = fmt.Errorf("invalid calendar ID")
+	ErrInvalidColorID      = fmt.Errorf("invalid color ID (must be 1-11)")
+	ErrInvalidVisibility   = fmt.Errorf("invalid visibility (must be default, public, or private)")
+	ErrInvalidTransparency = fmt.Errorf("invalid transparency (must be opaque or transparent)")
+	ErrInvalidSendUpdates  = fmt.Errorf("invalid sendUpdates (must be all, externalOnly, or none)")
+	ErrInvalidReminder     = fmt.Errorf("invalid reminder (method must be email or popup, minutes 0-40320)")
+	ErrDurationTooLong     = fmt.Errorf("event duration exceeds maximum allowed")
+	ErrTooManyAttendees    = fmt.Errorf("too many attendees")
 )
 
+// Limits on private extended properties. Google allows more, but these keep
+// payloads and approval pages readable.
+const (
+	MaxExtendedProperties    = 20
+	MaxExtendedPropertyKey   = 44
+	MaxExtendedPropertyValue = 1024
+)
+
+// MaxRequestContextLength caps the caller's explanation attached to a request.
+const MaxRequestContextLength = 2000
+
+// ErrRequestContextTooLong is returned for an oversized request context.
+var ErrRequestContextTooLong = fmt.Errorf("context exceeds %d characters", MaxRequestContextLength)
+
+// Limits on request tags.
+const (
+	MaxRequestTags   = 10
+	MaxRequestTagLen = 64
+)
+
+// Tag errors
+var (
+	ErrInvalidTag  = fmt.Errorf("invalid tag (use lowercase letters, digits, '.', '_' or '-', optionally as key:value, up to %d characters)", MaxRequestTagLen)
+	ErrTooManyTags = fmt.Errorf("too many tags (maximum %d)", MaxRequestTags)
+)
+
+// requestTagRegex matches a tag such as "urgent" or "team:eng"
+var requestTagRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*(:[a-z0-9][a-z0-9._-]*)?$`)
+
+// htmlTagRegex matches HTML tags and comments
+var htmlTagRegex = regexp.MustCompile(`(?s)<!--.*?-->|<[^>]*>`)
+
+// ReservedPropertyPrefix is reserved for extended properties SchedLock sets
+// itself, so callers cannot forge them.
+const ReservedPropertyPrefix = "schedlock"
+
+// propertyKeyRegex matches allowed extended property keys
+var propertyKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
 // calendarIDRegex matches valid Google Calendar IDs
 var calendarIDRegex = regexp.MustCompile(`^(primary|[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}|[a-z0-9]+@group\.calendar\.google\.com)$`)
 
@@ -145,6 +188,82 @@ func ValidateVisibility(visibility string) error {
 	return ErrInvalidVisibility
 }
 
+// ValidateTransparency checks if transparency value is valid.
+func ValidateTransparency(transparency string) error {
+	if transparency == "" {
+		return nil // Optional, defaults to "opaque" (busy)
+	}
+
+	if transparency == "opaque" || transparency == "transparent" {
+		return nil
+	}
+
+	return ErrInvalidTransparency
+}
+
+// ValidateSendUpdates checks the attendee notification setting.
+func ValidateSendUpdates(sendUpdates string) error {
+	switch sendUpdates {
+	case "", "all", "externalOnly", "none":
+		return nil // Empty leaves Google's default
+	}
+	return ErrInvalidSendUpdates
+}
+
+// MaxReminderMinutes is the furthest ahead Google allows a reminder (four weeks).
+const MaxReminderMinutes = 40320
+
+// MaxDescriptionSuffixLength caps the footer added to event descriptions by
+// configured event defaults.
+const MaxDescriptionSuffixLength = 1024
+
+// ValidateReminder checks a reminder's method and lead time.
+func ValidateReminder(method string, minutes int) error {
+	if (method != "email" && method != "popup") || minutes < 0 || minutes > MaxReminderMinutes {
+		return ErrInvalidReminder
+	}
+	return nil
+}
+
+// ParseReminder parses a reminder written as "method:minutes", such as
+// "popup:10".
+func ParseReminder(s string) (string, int, error) {
+	method, minutesStr, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return "", 0, ErrInvalidReminder
+	}
+	minutes, err := strconv.Atoi(minutesStr)
+	if err != nil {
+		return "", 0, ErrInvalidReminder
+	}
+	if err := ValidateReminder(method, minutes); err != nil {
+		return "", 0, err
+	}
+	return method, minutes, nil
+}
+
+// ValidateExtendedProperties checks the number, keys and value sizes of an
+// event's private extended properties.
+func ValidateExtendedProperties(props map[string]string) error {
+	if len(props) > MaxExtendedProperties {
+		return fmt.Errorf("too many extended properties: %d exceeds maximum of %d", len(props), MaxExtendedProperties)
+	}
+
+	for key, value := range props {
+		if key == "" || len(key) > MaxExtendedPropertyKey || !propertyKeyRegex.MatchString(key) {
+			return fmt.Errorf("invalid extended property key %q (letters, digits, '.', '_' and '-', up to %d characters)", key, MaxExtendedPropertyKey)
+		}
+		if strings.HasPrefix(strings.ToLower(key), ReservedPropertyPrefix) {
+			return fmt.Errorf("extended property key %q uses the reserved %q prefix", key, ReservedPropertyPrefix)
+		}
+		if len(value) > MaxExtendedPropertyValue {
+			return fmt.Errorf("extended property %q exceeds %d bytes", key, MaxExtendedPropertyValue)
+		}
+	}
+
+	return nil
+}
+
 // ValidateAttendeeCount checks if attendee count is within limits.
 func ValidateAttendeeCount(count, max int) error {
 	if max <= 0 {
@@ -167,6 +286,41 @@ func SanitizeString(s string) string {
 	return s
 }
 
+// SanitizeRequestContext strips HTML from a request's context text and checks
+// its length. Unlike SanitizeString it keeps line breaks, which matter for
+// lists and paragraphs.
+func SanitizeRequestContext(s string) (string, error) {
+	s = htmlTagRegex.ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.TrimSpace(s)
+	if len(s) > MaxRequestContextLength {
+		return "", ErrRequestContextTooLong
+	}
+	return s, nil
+}
+
+// NormalizeTags lowercases and trims request tags, drops empty entries and
+// duplicates, and checks each tag's format and the total count.
+func NormalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > MaxRequestTagLen || !requestTagRegex.MatchString(tag) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTag, tag)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > MaxRequestTags {
+		return nil, ErrTooManyTags
+	}
+	return normalized, nil
+}
+
 // TruncateString truncates a string to max length, adding ellipsis if needed.
 func TruncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
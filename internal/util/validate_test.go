@@ -0,0 +1,16 @@
+package util
+
+import "testing"
+
+func TestValidateTransparency(t *testing.T) {
+	for _, value := range []string{"", "opaque", "transparent"} {
+		if err := ValidateTransparency(value); err != nil {
+			t.Errorf("ValidateTransparency(%q) = %v, want nil", value, err)
+		}
+	}
+	for _, value := range []string{"busy", "free", "Opaque"} {
+		if err := ValidateTransparency(value); err != ErrInvalidTransparency {
+			t.Errorf("ValidateTransparency(%q) = %v, want ErrInvalidTransparency", value, err)
+		}
+	}
+}
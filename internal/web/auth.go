@@ -7,6 +7,7 @@ import (
 	"crypto/subtle"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"net/http"
 	"time"
 
@@ -19,6 +20,9 @@ import (
 const (
 	sessionCookieName = "schedlock_session"
 	csrfCookieName    = "schedlock_csrf"
+
+	// sessionIDPrefixLen is how much of a session ID is shown to the admin.
+	sessionIDPrefixLen = 12
 )
 
 // SessionManager handles web UI sessions.
@@ -41,6 +45,22 @@ type Session struct {
 	CreatedAt time.Time
 	ExpiresAt time.Time
 	CSRFToken string
+	// Timezone is the IANA timezone chosen for viewing event times, or
+	// empty to use the one the browser reports.
+	Timezone string
+}
+
+// SessionInfo describes an active session for display. The full session ID
+// is kept unexported so it never reaches templates or JSON responses.
+type SessionInfo struct {
+	id           string
+	IDPrefix     string    `json:"id_prefix"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastActivity time.Time `json:"last_activity"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	IPAddress    string    `json:"ip_address"`
+	UserAgent    string    `json:"user_agent"`
+	Current      bool      `json:"current"`
 }
 
 // CreateSession creates a new session for a user.
@@ -77,17 +97,21 @@ func (m *SessionManager) CreateSession(ctx context.Context, userID, ipAddress, u
 	}, nil
 }
 
-// ValidateSession checks if a session is valid.
+// ValidateSession checks if a session is valid. A session is rejected once
+// its absolute expiry passes or, with an idle timeout configured, once it has
+// been unused for longer than the idle window. Valid sessions have their
+// activity recorded, and their expiry extended when SessionRefresh is on.
 func (m *SessionManager) ValidateSession(ctx context.Context, sessionID string) (*Session, error) {
 	var session Session
 	var createdAt, expiresAt string
+	var lastActivity, timezone sql.NullString
 	var csrfToken string
 
 	err := m.db.QueryRowContext(ctx, `
-		SELECT id, ip_address, user_agent, created_at, expires_at, csrf_token
+		SELECT id, ip_address, user_agent, created_at, expires_at, last_activity, csrf_token, timezone
 		FROM sessions
 		WHERE id = ? AND expires_at > datetime('now')
-	`, sessionID).Scan(&session.ID, &session.IPAddress, &session.UserAgent, &createdAt, &expiresAt, &csrfToken)
+	`, sessionID).Scan(&session.ID, &session.IPAddress, &session.UserAgent, &createdAt, &expiresAt, &lastActivity, &csrfToken, &timezone)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -100,16 +124,113 @@ func (m *SessionManager) ValidateSession(ctx context.Context, sessionID string)
 	session.ExpiresAt, _ = util.ParseSQLiteTimestamp(expiresAt)
 	session.UserID = "admin"
 	session.CSRFToken = csrfToken
+	session.Timezone = timezone.String
+
+	if m.idleExpired(lastActivity, session.CreatedAt) {
+		if err := m.DeleteSession(ctx, sessionID); err != nil {
+			util.Warn("Failed to delete idle session", "error", err)
+		}
+		return nil, nil
+	}
+
+	if m.config.SessionRefresh {
+		if err := m.RefreshSession(ctx, sessionID); err != nil {
+			return nil, err
+		}
+		session.ExpiresAt = time.Now().Add(m.sessionDuration())
+	} else if m.config.SessionIdleTimeout > 0 {
+		// Without refresh the expiry is fixed, but activity still resets the idle window
+		if err := m.touchSession(ctx, sessionID); err != nil {
+			return nil, err
+		}
+	}
 
 	return &session, nil
 }
 
+// idleExpired reports whether a session has been unused for longer than the
+// idle timeout. Sessions with no recorded activity are measured from creation.
+func (m *SessionManager) idleExpired(lastActivity sql.NullString, createdAt time.Time) bool {
+	if m.config.SessionIdleTimeout <= 0 {
+		return false
+	}
+
+	lastSeen := createdAt
+	if lastActivity.Valid {
+		if parsed, err := util.ParseSQLiteTimestamp(lastActivity.String); err == nil {
+			lastSeen = parsed
+		}
+	}
+	return time.Since(lastSeen) > m.config.SessionIdleTimeout
+}
+
+// touchSession records activity without extending the session's expiry.
+func (m *SessionManager) touchSession(ctx context.Context, sessionID string) error {
+	_, err := m.db.ExecContext(ctx, `UPDATE sessions SET last_activity = datetime('now') WHERE id = ?`, sessionID)
+	return err
+}
+
+// SetTimezone stores the timezone a session views event times in. An empty
+// timezone clears the choice.
+func (m *SessionManager) SetTimezone(ctx context.Context, sessionID, timezone string) error {
+	_, err := m.db.ExecContext(ctx, `UPDATE sessions SET timezone = NULLIF(?, '') WHERE id = ?`, timezone, sessionID)
+	return err
+}
+
 // DeleteSession removes a session.
 func (m *SessionManager) DeleteSession(ctx context.Context, sessionID string) error {
 	_, err := m.db.ExecContext(ctx, `DELETE FROM sessions WHERE id = ?`, sessionID)
 	return err
 }
 
+// ListSessions returns all unexpired sessions, most recently active first.
+func (m *SessionManager) ListSessions(ctx context.Context) ([]SessionInfo, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT id, created_at, expires_at, last_activity, ip_address, user_agent
+		FROM sessions
+		WHERE expires_at > datetime('now')
+		ORDER BY COALESCE(last_activity, created_at) DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []SessionInfo
+	for rows.Next() {
+		var info SessionInfo
+		var createdAt, expiresAt string
+		var lastActivity, ipAddress, userAgent sql.NullString
+		if err := rows.Scan(&info.id, &createdAt, &expiresAt, &lastActivity, &ipAddress, &userAgent); err != nil {
+			return nil, err
+		}
+
+		info.IDPrefix = info.id
+		if len(info.IDPrefix) > sessionIDPrefixLen {
+			info.IDPrefix = info.IDPrefix[:sessionIDPrefixLen]
+		}
+		info.CreatedAt, _ = util.ParseSQLiteTimestamp(createdAt)
+		info.ExpiresAt, _ = util.ParseSQLiteTimestamp(expiresAt)
+		if lastActivity.Valid {
+			info.LastActivity, _ = util.ParseSQLiteTimestamp(lastActivity.String)
+		}
+		info.IPAddress = ipAddress.String
+		info.UserAgent = userAgent.String
+		sessions = append(sessions, info)
+	}
+
+	return sessions, rows.Err()
+}
+
+// DeleteAllExcept removes every session other than keepID and returns how many were removed.
+func (m *SessionManager) DeleteAllExcept(ctx context.Context, keepID string) (int64, error) {
+	result, err := m.db.ExecContext(ctx, `DELETE FROM sessions WHERE id != ?`, keepID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 // RefreshSession extends a session's expiration.
 func (m *SessionManager) RefreshSession(ctx context.Context, sessionID string) error {
 	expiresAt := time.Now().Add(m.sessionDuration())
@@ -254,6 +375,7 @@ func (m *SessionManager) RequireSession(next http.Handler) http.Handler {
 			return
 		}
 
+		// Validation also records activity and refreshes the session
 		session, err := m.ValidateSession(r.Context(), sessionID)
 		if err != nil || session == nil {
 			ClearSessionCookie(w)
@@ -261,11 +383,6 @@ func (m *SessionManager) RequireSession(next http.Handler) http.Handler {
 			return
 		}
 
-		// Refresh session
-		if m.config.SessionRefresh {
-			m.RefreshSession(r.Context(), sessionID)
-		}
-
 		// Add session to context
 		ctx := WithSession(r.Context(), session)
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -283,6 +400,14 @@ func (m *SessionManager) sessionDuration() time.Duration {
 func CSRFProtection(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodDelete {
+			// Parse up front so an oversized form is reported as such, not as a CSRF failure
+			if err := r.ParseForm(); err != nil {
+				var maxErr *http.MaxBytesError
+				if errors.As(err, &maxErr) {
+					http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+					return
+				}
+			}
 			if !ValidateCSRF(r) {
 				http.Error(w, "CSRF token invalid", http.StatusForbidden)
 				return
@@ -0,0 +1,194 @@
+package web
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+func newTestSessionManager(t *testing.T, cfg *config.AuthConfig) *SessionManager {
+	t.Helper()
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewSessionManager(db, cfg)
+}
+
+// insertSession stores a session with explicit timestamps, relative to now.
+func insertSession(t *testing.T, m *SessionManager, id string, expiresIn, lastActivityAgo time.Duration) {
+	t.Helper()
+	now := time.Now()
+	_, err := m.db.Exec(`
+		INSERT INTO sessions (id, created_at, expires_at, last_activity, ip_address, user_agent, csrf_token)
+		VALUES (?, ?, ?, ?, '127.0.0.1', 'test', 'csrf')
+	`, id, util.SQLiteTimestamp(now.Add(-lastActivityAgo)), util.SQLiteTimestamp(now.Add(expiresIn)), util.SQLiteTimestamp(now.Add(-lastActivityAgo)))
+	if err != nil {
+		t.Fatalf("Failed to insert session: %v", err)
+	}
+}
+
+func TestValidateSessionIdleTimeout(t *testing.T) {
+	ctx := context.Background()
+	m := newTestSessionManager(t, &config.AuthConfig{
+		SessionDuration:    24 * time.Hour,
+		SessionIdleTimeout: 30 * time.Minute,
+	})
+
+	insertSession(t, m, "active", 12*time.Hour, 5*time.Minute)
+	insertSession(t, m, "idle", 12*time.Hour, time.Hour)
+
+	if session, err := m.ValidateSession(ctx, "active"); err != nil || session == nil {
+		t.Fatalf("expected recently used session to be valid, got %v (err %v)", session, err)
+	}
+
+	// Idle beyond the window: rejected even though the absolute expiry is hours away
+	if session, err := m.ValidateSession(ctx, "idle"); err != nil || session != nil {
+		t.Fatalf("expected idle session to be rejected, got %v (err %v)", session, err)
+	}
+	var count int
+	m.db.QueryRow(`SELECT COUNT(*) FROM sessions WHERE id = 'idle'`).Scan(&count)
+	if count != 0 {
+		t.Error("expected idle session to be deleted")
+	}
+}
+
+func TestValidateSessionAbsoluteExpiryWins(t *testing.T) {
+	ctx := context.Background()
+	m := newTestSessionManager(t, &config.AuthConfig{
+		SessionDuration:    24 * time.Hour,
+		SessionIdleTimeout: 30 * time.Minute,
+		SessionRefresh:     false,
+	})
+
+	// Active a minute ago but past its absolute expiry
+	insertSession(t, m, "expired", -time.Minute, time.Minute)
+
+	if session, err := m.ValidateSession(ctx, "expired"); err != nil || session != nil {
+		t.Fatalf("expected expired session to be rejected, got %v (err %v)", session, err)
+	}
+}
+
+func TestValidateSessionRecordsActivity(t *testing.T) {
+	ctx := context.Background()
+
+	for _, refresh := range []bool{true, false} {
+		m := newTestSessionManager(t, &config.AuthConfig{
+			SessionDuration:    24 * time.Hour,
+			SessionIdleTimeout: 30 * time.Minute,
+			SessionRefresh:     refresh,
+		})
+		insertSession(t, m, "sess", time.Hour, 20*time.Minute)
+
+		if session, err := m.ValidateSession(ctx, "sess"); err != nil || session == nil {
+			t.Fatalf("refresh=%v: expected session to be valid, got %v (err %v)", refresh, session, err)
+		}
+
+		var lastActivity, expiresAt string
+		if err := m.db.QueryRow(`SELECT last_activity, expires_at FROM sessions WHERE id = 'sess'`).Scan(&lastActivity, &expiresAt); err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		seen, _ := util.ParseSQLiteTimestamp(lastActivity)
+		if time.Since(seen) > time.Minute {
+			t.Errorf("refresh=%v: expected last_activity to be updated, got %s", refresh, lastActivity)
+		}
+
+		// Only SessionRefresh extends the absolute expiry
+		expiry, _ := util.ParseSQLiteTimestamp(expiresAt)
+		extended := time.Until(expiry) > 2*time.Hour
+		if extended != refresh {
+			t.Errorf("refresh=%v: expiry extended = %v", refresh, extended)
+		}
+	}
+}
+
+func TestValidateSessionWithoutIdleTimeout(t *testing.T) {
+	m := newTestSessionManager(t, &config.AuthConfig{SessionDuration: 24 * time.Hour})
+	insertSession(t, m, "old", time.Hour, 72*time.Hour)
+
+	if session, err := m.ValidateSession(context.Background(), "old"); err != nil || session == nil {
+		t.Fatalf("expected session to be valid with idle timeout disabled, got %v (err %v)", session, err)
+	}
+}
+
+func TestSessionTimezone(t *testing.T) {
+	ctx := context.Background()
+	m := newTestSessionManager(t, &config.AuthConfig{SessionDuration: time.Hour})
+	insertSession(t, m, "sess_tz", time.Hour, 0)
+
+	if err := m.SetTimezone(ctx, "sess_tz", "America/Los_Angeles"); err != nil {
+		t.Fatalf("SetTimezone() error = %v", err)
+	}
+	session, err := m.ValidateSession(ctx, "sess_tz")
+	if err != nil || session == nil {
+		t.Fatalf("ValidateSession() = %v, %v", session, err)
+	}
+	if session.Timezone != "America/Los_Angeles" {
+		t.Errorf("Timezone = %q", session.Timezone)
+	}
+
+	// Clearing the choice falls back to the browser's timezone
+	m.SetTimezone(ctx, "sess_tz", "")
+	if session, _ = m.ValidateSession(ctx, "sess_tz"); session.Timezone != "" {
+		t.Errorf("expected cleared timezone, got %q", session.Timezone)
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	m := newTestSessionManager(t, &config.AuthConfig{SessionDuration: 24 * time.Hour})
+	insertSession(t, m, "sess_idle_aaaaaaaaaaaa", time.Hour, time.Hour)
+	insertSession(t, m, "sess_recent_bbbbbbbbbb", time.Hour, time.Minute)
+	insertSession(t, m, "sess_expired_cccccccc", -time.Minute, time.Minute)
+
+	sessions, err := m.ListSessions(context.Background())
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	// Expired sessions are left out; the most recently active comes first
+	if len(sessions) != 2 || sessions[0].id != "sess_recent_bbbbbbbbbb" || sessions[1].id != "sess_idle_aaaaaaaaaaaa" {
+		t.Fatalf("sessions = %+v, want recent then idle", sessions)
+	}
+	if sessions[0].IDPrefix != "sess_recent_" {
+		t.Errorf("IDPrefix = %q, want the first %d characters", sessions[0].IDPrefix, sessionIDPrefixLen)
+	}
+	if sessions[0].IPAddress != "127.0.0.1" || sessions[0].UserAgent != "test" {
+		t.Errorf("session = %+v, want its IP address and user agent", sessions[0])
+	}
+}
+
+func TestDeleteAllExcept(t *testing.T) {
+	ctx := context.Background()
+	m := newTestSessionManager(t, &config.AuthConfig{SessionDuration: 24 * time.Hour})
+	for _, id := range []string{"sess_keep", "sess_other_1", "sess_other_2"} {
+		insertSession(t, m, id, time.Hour, 0)
+	}
+
+	revoked, err := m.DeleteAllExcept(ctx, "sess_keep")
+	if err != nil {
+		t.Fatalf("DeleteAllExcept() error = %v", err)
+	}
+	if revoked != 2 {
+		t.Errorf("revoked = %d, want 2", revoked)
+	}
+
+	var ids []string
+	rows, err := m.db.Query(`SELECT id FROM sessions`)
+	if err != nil {
+		t.Fatalf("query sessions: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		rows.Scan(&id)
+		ids = append(ids, id)
+	}
+	if len(ids) != 1 || ids[0] != "sess_keep" {
+		t.Errorf("remaining sessions = %v, want only sess_keep", ids)
+	}
+}
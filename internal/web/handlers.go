@@ -3,6 +3,7 @@ package web
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"net/http"
@@ -19,9 +20,11 @@ import (
 	"github.com/dtorcivia/schedlock/internal/google"
 	"github.com/dtorcivia/schedlock/internal/notifications"
 	"github.com/dtorcivia/schedlock/internal/requests"
+	"github.com/dtorcivia/schedlock/internal/response"
 	"github.com/dtorcivia/schedlock/internal/settings"
 	"github.com/dtorcivia/schedlock/internal/tokens"
 	"github.com/dtorcivia/schedlock/internal/util"
+	"github.com/dtorcivia/schedlock/internal/webhook"
 )
 
 // Handler provides web UI handlers.
@@ -39,6 +42,29 @@ type Handler struct {
 	oauthMgr         *google.OAuthManager
 	notificationMgr  *notifications.Manager
 	auditLogger      *engine.AuditLogger
+	webhookPinger    WebhookPinger
+	webhookFailures  WebhookFailureStore
+}
+
+// WebhookPinger sends a test event to the Moltbot webhook.
+type WebhookPinger interface {
+	Ping(ctx context.Context) (*webhook.PingResult, error)
+}
+
+// SetWebhookPinger sets the client used by TestWebhook.
+func (h *Handler) SetWebhookPinger(p WebhookPinger) {
+	h.webhookPinger = p
+}
+
+// WebhookFailureStore lists and redelivers failed Moltbot webhooks.
+type WebhookFailureStore interface {
+	ListFailures(ctx context.Context, opts webhook.FailureListOptions) ([]webhook.Failure, int, error)
+	RetryFailure(ctx context.Context, id int64) (*webhook.Failure, error)
+}
+
+// SetWebhookFailures sets the store used by the webhook failures page.
+func (h *Handler) SetWebhookFailures(s WebhookFailureStore) {
+	h.webhookFailures = s
 }
 
 // NewHandler creates a new web handler.
@@ -81,20 +107,23 @@ func NewHandler(
 // loadTemplates loads all HTML templates.
 // Each page is loaded separately with its own copy of the layout to avoid name collisions.
 func loadTemplates(dir string) (*template.Template, error) {
-	formatter := util.GetDefaultFormatter()
+	// The formatter is looked up on each call so display settings saved at
+	// runtime take effect without reloading the templates.
 	funcMap := template.FuncMap{
 		"formatTime": func(t time.Time) string {
-			if formatter != nil {
-				return formatter.FormatDateTime(t)
+			if formatter := util.GetDefaultFormatter(); formatter != nil {
+				return formatter.FormatTimestamp(t)
 			}
 			return t.Format("Jan 2, 2006 3:04 PM")
 		},
 		"formatDate": func(t time.Time) string {
-			if formatter != nil {
+			if formatter := util.GetDefaultFormatter(); formatter != nil {
 				return formatter.FormatDate(t)
 			}
 			return t.Format("Jan 2, 2006")
 		},
+		"formatTimeIn":    formatTimeIn,
+		"formatEventTime": formatEventTime,
 		"formatJSON": func(v interface{}) string {
 			data, _ := json.MarshalIndent(v, "", "  ")
 			return string(data)
@@ -156,6 +185,7 @@ func loadTemplates(dir string) (*template.Template, error) {
 		"login.html", "dashboard.html", "pending.html", "detail.html",
 		"history.html", "apikeys.html", "settings.html", "oauth.html",
 		"oauth_not_configured.html", "setup.html", "setup_complete.html",
+		"webhook_failures.html", "apikey_constraints.html",
 	}
 
 	// Standalone approve page with its own minimal layout
@@ -172,7 +202,7 @@ func loadTemplates(dir string) (*template.Template, error) {
     <link href="https://fonts.googleapis.com/css2?family=Newsreader:ital,opsz,wght@0,6..72,300;0,6..72,400;0,6..72,500;0,6..72,600;1,6..72,400&family=Inter:wght@400;450;500;600&family=JetBrains+Mono:wght@400;500&display=swap" rel="stylesheet">
     <link rel="stylesheet" href="/static/css/styles.css">
 </head>
-<body>
+<body{{if .DetectTimezone}} data-detect-timezone{{end}}>
     {{template "content-approve.html" .}}
     <script>
         (function() {
@@ -180,6 +210,18 @@ func loadTemplates(dir string) (*template.Template, error) {
             const savedTheme = localStorage.getItem('schedlock-theme') || 'system';
             html.setAttribute('data-theme', savedTheme);
         })();
+
+        // Report the browser's timezone so event times can be shown in it
+        (function() {
+            const tz = Intl.DateTimeFormat().resolvedOptions().timeZone;
+            if (!tz || document.cookie.split('; ').includes('schedlock_tz=' + tz)) return;
+            document.cookie = 'schedlock_tz=' + tz + '; path=/; max-age=31536000; SameSite=Lax';
+            // Reload once so this page uses it; cookies may be blocked
+            if ('detectTimezone' in document.body.dataset && !sessionStorage.getItem('schedlock-tz-reloaded')) {
+                sessionStorage.setItem('schedlock-tz-reloaded', '1');
+                location.reload();
+            }
+        })();
     </script>
 </body>
 </html>
@@ -311,10 +353,9 @@ func (h *Handler) LoginSubmit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create session
-	ipAddress := r.RemoteAddr
 	userAgent := r.UserAgent()
 
-	session, err := h.sessionMgr.CreateSession(r.Context(), "admin", ipAddress, userAgent)
+	session, err := h.sessionMgr.CreateSession(r.Context(), "admin", ip, userAgent)
 	if err != nil {
 		h.render(w, r, "login.html", map[string]interface{}{
 			"Title": "Sign In",
@@ -349,7 +390,7 @@ func (h *Handler) Dashboard(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// Get stats
-	stats, _ := h.requestRepo.GetStats(ctx)
+	stats, _ := h.requestRepo.GetStats(ctx, h.config.Display.StatsWindowDays)
 	apiKeyStats, _ := h.apiKeyRepo.Count(ctx)
 	totalAPIKeys := 0
 	for _, count := range apiKeyStats {
@@ -366,6 +407,7 @@ func (h *Handler) Dashboard(w http.ResponseWriter, r *http.Request) {
 		"APIKeyTotal":     totalAPIKeys,
 		"PendingCount":    len(pending),
 		"PendingRequests": pending,
+		"OAuthHealth":     h.oauthMgr.Health(),
 	})
 }
 
@@ -380,17 +422,79 @@ func (h *Handler) PendingRequests(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ApprovalReason explains which key constraint held a request for approval.
+type ApprovalReason struct {
+	Constraint string
+	Reason     string
+}
+
+// approvalReason reads the constraint decision recorded when the request
+// was created. Requests created before decisions were recorded, or that
+// did not need approval, have none.
+func approvalReason(entries []database.AuditLogEntry) *ApprovalReason {
+	for _, entry := range entries {
+		if entry.EventType != database.AuditRequestCreated {
+			continue
+		}
+		var details struct {
+			Decision   string `json:"decision"`
+			Constraint string `json:"decision_constraint"`
+			Reason     string `json:"decision_reason"`
+		}
+		if err := json.Unmarshal(entry.Details, &details); err != nil || details.Decision != "require_approval" || details.Reason == "" {
+			return nil
+		}
+		return &ApprovalReason{Constraint: details.Constraint, Reason: details.Reason}
+	}
+	return nil
+}
+
 // EventDisplayData holds parsed event data for human-readable display.
 type EventDisplayData struct {
 	Summary     string
 	Description string
-	Location    string
-	CalendarID  string
-	EventID     string // for update/delete
-	Start       time.Time
-	End         time.Time
-	Attendees   []string
-	IsAllDay    bool
+
+	DescriptionHTML template.HTML // sanitized formatting; empty for plain text descriptions
+
+	Location   string
+	CalendarID string
+	EventID    string // for update/delete/move
+	Start      time.Time
+	End        time.Time
+	Attendees  []string
+	Rooms      []string
+	IsAllDay   bool
+
+	OptionalAttendees []string
+
+	// Visibility ("default"/"public"/"private") and transparency ("opaque"/"transparent")
+	Visibility   string
+	Transparency string
+
+	GuestPermissions []google.GuestPermission
+	SendUpdates      string // who Google emails, as shown to approvers; empty if unset
+
+	// RecurringEditScope is the recurring edit scope of an update ("instance",
+	// "following" or "all"); empty for other requests.
+	RecurringEditScope string
+
+	// DestinationCalendarID is the target calendar for move requests.
+	DestinationCalendarID string
+
+	// Set when a create request duplicates an existing event.
+	DuplicateOfEventID string
+	DuplicateOfLink    string
+
+	// Every event a batch create request will insert.
+	BatchEvents []BatchEventDisplay
+}
+
+// BatchEventDisplay is one event in a batch create request.
+type BatchEventDisplay struct {
+	CalendarID string
+	Summary    string
+	Start      time.Time
+	End        time.Time
 }
 
 // RequestDetail shows a specific request.
@@ -408,9 +512,23 @@ func (h *Handler) RequestDetail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.render(w, r, "detail.html", h.requestDetailData(r, req))
+}
+
+// requestDetailData builds the template data for a request's detail page.
+func (h *Handler) requestDetailData(r *http.Request, req *database.Request) map[string]interface{} {
+	ctx := r.Context()
+	requestID := req.ID
+
 	// Get audit log for this request
 	auditEntries, _ := h.auditLogger.GetByRequestID(ctx, requestID)
 
+	// Get notification delivery history
+	notificationLog, err := h.notificationMgr.GetNotificationLog(ctx, requestID)
+	if err != nil {
+		util.Error("Failed to load notification log", "error", err, "request_id", requestID)
+	}
+
 	// Parse payload for display
 	var payload interface{}
 	json.Unmarshal(req.Payload, &payload)
@@ -418,29 +536,261 @@ func (h *Handler) RequestDetail(w http.ResponseWriter, r *http.Request) {
 	// Parse into human-readable format based on operation type
 	eventData := h.parseEventPayload(req.Operation, req.Payload)
 
-	h.render(w, r, "detail.html", map[string]interface{}{
+	data := map[string]interface{}{
 		"Title":        "Request Details",
 		"Request":      req,
 		"Payload":      payload,
 		"EventData":    eventData,
 		"AuditEntries": auditEntries,
+
+		"NotificationLog":  notificationLog,
+		"NotificationSent": r.URL.Query().Get("notified") == "1",
+		"Retried":          r.URL.Query().Get("retried") == "1",
+	}
+	if reason := approvalReason(auditEntries); reason != nil {
+		data["ApprovalReason"] = reason
+	}
+	addTimezoneData(r, data)
+	if req.Status == database.StatusApproved && h.engine != nil {
+		if ahead, ok := h.engine.QueuePosition(requestID); ok {
+			data["QueuePosition"] = queuePositionHTML(requestID, ahead, true)
+		}
+	}
+
+	if req.Operation == database.OperationUpdateEvent && req.Status == database.StatusPendingApproval {
+		data["Diff"], data["DiffUnavailable"] = h.updateDiff(ctx, req)
+	}
+
+	// Look up open slots on demand so approvers can counter-propose a time
+	if r.URL.Query().Get("slots") == "1" && req.Status == database.StatusPendingApproval {
+		slots, message := h.suggestFreeSlots(ctx, requestID)
+		data["FreeSlotsChecked"] = true
+		data["FreeSlots"] = slots
+		data["FreeSlotsError"] = message
+	}
+
+	return data
+}
+
+// suggestFreeSlots finds open slots for a request. When none can be looked
+// up it returns a message explaining why, for display on the detail page.
+func (h *Handler) suggestFreeSlots(ctx context.Context, requestID string) ([]engine.FreeSlot, string) {
+	if h.oauthMgr == nil || !h.oauthMgr.HasToken(ctx) {
+		return nil, "Connect Google Calendar in Settings to look up free slots."
+	}
+
+	slots, err := h.engine.SuggestFreeSlots(ctx, requestID, 0)
+	if errors.Is(err, engine.ErrNoRequestedTime) {
+		return nil, "This request does not propose an event time."
+	}
+	if err != nil {
+		util.Error("Failed to look up free slots", "error", err, "request_id", requestID)
+		return nil, "Could not look up free slots. Check the Google Calendar connection and try again."
+	}
+	return slots, ""
+}
+
+// updateDiff compares a pending update with the current event. When the
+// event cannot be fetched it returns a message explaining why, and pages
+// fall back to showing only the proposed values.
+func (h *Handler) updateDiff(ctx context.Context, req *database.Request) ([]google.Diff, string) {
+	if h.oauthMgr == nil || !h.oauthMgr.HasToken(ctx) {
+		return nil, "Google Calendar is not connected, so only the proposed values are shown."
+	}
+
+	diffs, err := h.engine.UpdateDiff(ctx, req)
+	if errors.Is(err, engine.ErrEventNotFound) {
+		return nil, "The event no longer exists, so only the proposed values are shown."
+	}
+	if err != nil {
+		util.Error("Failed to load event for diff", "error", err, "request_id", req.ID)
+		return nil, "Could not load the current event, so only the proposed values are shown."
+	}
+	return diffs, ""
+}
+
+// ResendNotification re-sends approval notifications for a pending request.
+func (h *Handler) ResendNotification(w http.ResponseWriter, r *http.Request) {
+	requestID := r.PathValue("requestId")
+	session := GetSession(r.Context())
+
+	actor := "web:admin"
+	if session != nil {
+		actor = "web:" + session.UserID
+	}
+
+	force := r.FormValue("force") == "1"
+	if err := h.engine.ResendApprovalNotification(r.Context(), requestID, actor, force); err != nil {
+		var cooldown *engine.ResendCooldownError
+		if errors.As(err, &cooldown) {
+			response.SetRetryAfter(w, cooldown.RetryAfter)
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		if errors.Is(err, notifications.ErrAlreadyDelivered) {
+			http.Error(w, "Every provider already delivered this request. Use Resend to All to notify them again.", http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	redirect := "/requests/" + requestID + "?notified=1"
+
+	// Check if HTMX request
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", redirect)
+		return
+	}
+
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+// RetryRequest runs a failed request again.
+func (h *Handler) RetryRequest(w http.ResponseWriter, r *http.Request) {
+	requestID := r.PathValue("requestId")
+	session := GetSession(r.Context())
+
+	actor := "web:admin"
+	if session != nil {
+		actor = "web:" + session.UserID
+	}
+
+	if err := h.engine.RetryFailedRequest(r.Context(), requestID, actor); err != nil {
+		switch {
+		case errors.Is(err, engine.ErrRequestNotFound):
+			http.Error(w, "Request not found", http.StatusNotFound)
+		case errors.Is(err, engine.ErrRequestNotFailed):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			util.Error("Failed to retry request", "error", err, "request_id", requestID)
+			http.Error(w, "Failed to retry request", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	redirect := "/requests/" + requestID + "?retried=1"
+
+	// Check if HTMX request
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", redirect)
+		return
+	}
+
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+// QueuePosition returns the queue position fragment for an approved request,
+// which the detail page polls until the request leaves the queue.
+func (h *Handler) QueuePosition(w http.ResponseWriter, r *http.Request) {
+	requestID := r.PathValue("requestId")
+	var ahead int
+	var queued bool
+	if h.engine != nil {
+		ahead, queued = h.engine.QueuePosition(requestID)
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.Write([]byte(queuePositionHTML(requestID, ahead, queued)))
+}
+
+// queuePositionHTML renders how many requests will run before an approved
+// one. While it is queued the fragment refreshes itself every few seconds.
+func queuePositionHTML(requestID string, ahead int, queued bool) template.HTML {
+	if !queued {
+		return `<div id="queue-position" class="alert alert-info mb-6">This request has left the execution queue. Reload the page for its result.</div>`
+	}
+
+	message := "Next to run."
+	switch {
+	case ahead == 1:
+		message = "1 request ahead of this one."
+	case ahead > 1:
+		message = fmt.Sprintf("%d requests ahead of this one.", ahead)
+	}
+	return template.HTML(`<div id="queue-position" class="alert alert-info mb-6" hx-get="/requests/` + template.HTMLEscapeString(requestID) +
+		`/queue" hx-trigger="every 3s" hx-swap="outerHTML"><strong>Waiting to run.</strong> ` + message + `</div>`)
+}
+
+// CreateApprovalLink issues a one-time public approval link for a pending
+// request that skips the approval PIN, since the admin creating it is
+// already signed in.
+func (h *Handler) CreateApprovalLink(w http.ResponseWriter, r *http.Request) {
+	requestID := r.PathValue("requestId")
+	ctx := r.Context()
+
+	req, err := h.requestRepo.GetByID(ctx, requestID)
+	if err != nil || req == nil {
+		http.Error(w, "Request not found", http.StatusNotFound)
+		return
+	}
+	if req.Status != database.StatusPendingApproval {
+		http.Error(w, "Request is no longer pending", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.tokenRepo.Create(ctx, req.ID, req.ExpiresAt, true)
+	if err != nil {
+		http.Error(w, "Failed to create approval link", http.StatusInternalServerError)
+		return
+	}
+	link := strings.TrimRight(h.config.Server.BaseURL, "/") + "/approve/" + token
+
+	actor := "web:admin"
+	if session := GetSession(ctx); session != nil {
+		actor = "web:" + session.UserID
+	}
+	h.auditLogger.Log(ctx, database.AuditApprovalLinkCreated, req.ID, req.APIKeyID, actor, map[string]interface{}{
+		"pin_satisfied": true,
+		"expires_at":    req.ExpiresAt,
 	})
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<div class="alert alert-warning" style="margin-top: var(--space-4);">
+			<p><strong>Approval link created.</strong> Anyone with this link can approve or deny the request without the PIN until it is used or the request expires.</p>
+			<code class="key-display" style="display: block; margin-top: var(--space-2); word-break: break-all;">` + template.HTMLEscapeString(link) + `</code>
+		</div>`))
+		return
+	}
+
+	data := h.requestDetailData(r, req)
+	data["ApprovalLink"] = link
+	h.render(w, r, "detail.html", data)
 }
 
 // parseEventPayload extracts human-readable event data from the payload.
 func (h *Handler) parseEventPayload(operation string, payload json.RawMessage) *EventDisplayData {
-	data := &EventDisplayData{}
+	data := &EventDisplayData{GuestPermissions: guestPermissions(operation, payload)}
+
+	var options struct {
+		SendUpdates        string `json:"sendUpdates"`
+		RecurringEditScope string `json:"recurringEditScope"`
+	}
+	if err := json.Unmarshal(payload, &options); err == nil {
+		data.SendUpdates = sendUpdatesLabel(options.SendUpdates)
+		data.RecurringEditScope = options.RecurringEditScope
+	}
 
 	switch operation {
 	case "create_event":
 		var intent struct {
-			Summary     string    `json:"summary"`
-			Description string    `json:"description"`
-			Location    string    `json:"location"`
-			CalendarID  string    `json:"calendarId"`
-			Start       time.Time `json:"start"`
-			End         time.Time `json:"end"`
-			Attendees   []string  `json:"attendees"`
+			Summary      string    `json:"summary"`
+			Description  string    `json:"description"`
+			Location     string    `json:"location"`
+			CalendarID   string    `json:"calendarId"`
+			Start        time.Time `json:"start"`
+			End          time.Time `json:"end"`
+			Attendees    []string  `json:"attendees"`
+			Rooms        []string  `json:"rooms"`
+			Visibility   string    `json:"visibility"`
+			Transparency string    `json:"transparency"`
+
+			OptionalAttendees []string `json:"optionalAttendees"`
+
+			DuplicateOf *struct {
+				EventID  string `json:"eventId"`
+				HtmlLink string `json:"htmlLink"`
+			} `json:"duplicateOf"`
 		}
 		if err := json.Unmarshal(payload, &intent); err == nil {
 			data.Summary = intent.Summary
@@ -450,18 +800,28 @@ func (h *Handler) parseEventPayload(operation string, payload json.RawMessage) *
 			data.Start = intent.Start
 			data.End = intent.End
 			data.Attendees = intent.Attendees
+			data.OptionalAttendees = intent.OptionalAttendees
+			data.Rooms = intent.Rooms
+			data.Visibility = intent.Visibility
+			data.Transparency = intent.Transparency
+			if intent.DuplicateOf != nil {
+				data.DuplicateOfEventID = intent.DuplicateOf.EventID
+				data.DuplicateOfLink = intent.DuplicateOf.HtmlLink
+			}
 		}
 
 	case "update_event":
 		var intent struct {
-			EventID     string     `json:"eventId"`
-			CalendarID  string     `json:"calendarId"`
-			Summary     *string    `json:"summary"`
-			Description *string    `json:"description"`
-			Location    *string    `json:"location"`
-			Start       *time.Time `json:"start"`
-			End         *time.Time `json:"end"`
-			Attendees   []string   `json:"attendees"`
+			EventID      string     `json:"eventId"`
+			CalendarID   string     `json:"calendarId"`
+			Summary      *string    `json:"summary"`
+			Description  *string    `json:"description"`
+			Location     *string    `json:"location"`
+			Start        *time.Time `json:"start"`
+			End          *time.Time `json:"end"`
+			Attendees    []string   `json:"attendees"`
+			Visibility   *string    `json:"visibility"`
+			Transparency *string    `json:"transparency"`
 		}
 		if err := json.Unmarshal(payload, &intent); err == nil {
 			data.EventID = intent.EventID
@@ -482,6 +842,12 @@ func (h *Handler) parseEventPayload(operation string, payload json.RawMessage) *
 				data.End = *intent.End
 			}
 			data.Attendees = intent.Attendees
+			if intent.Visibility != nil {
+				data.Visibility = *intent.Visibility
+			}
+			if intent.Transparency != nil {
+				data.Transparency = *intent.Transparency
+			}
 		}
 
 	case "delete_event":
@@ -493,8 +859,43 @@ func (h *Handler) parseEventPayload(operation string, payload json.RawMessage) *
 			data.EventID = intent.EventID
 			data.CalendarID = intent.CalendarID
 		}
+
+	case "move_event":
+		var intent struct {
+			EventID               string `json:"eventId"`
+			CalendarID            string `json:"calendarId"`
+			DestinationCalendarID string `json:"destinationCalendarId"`
+		}
+		if err := json.Unmarshal(payload, &intent); err == nil {
+			data.EventID = intent.EventID
+			data.CalendarID = intent.CalendarID
+			data.DestinationCalendarID = intent.DestinationCalendarID
+		}
+
+	case "create_events_batch":
+		var batch google.EventBatchIntent
+		if err := json.Unmarshal(payload, &batch); err == nil && len(batch.Events) > 0 {
+			first := batch.Events[0]
+			data.Summary = first.Summary
+			data.Description = first.Description
+			data.Location = first.Location
+			data.Start = first.Start
+			data.End = first.End
+			data.Attendees = first.Attendees
+			data.OptionalAttendees = first.OptionalAttendees
+			data.Rooms = first.Rooms
+			for _, event := range batch.Events {
+				data.BatchEvents = append(data.BatchEvents, BatchEventDisplay{
+					CalendarID: event.CalendarID,
+					Summary:    event.Summary,
+					Start:      event.Start,
+					End:        event.End,
+				})
+			}
+		}
 	}
 
+	data.DescriptionHTML = descriptionHTML(data.Description)
 	return data
 }
 
@@ -696,26 +1097,56 @@ func (h *Handler) UpdatePayload(w http.ResponseWriter, r *http.Request) {
 // History shows audit log.
 func (h *Handler) History(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	entries, _ := h.auditLogger.GetRecent(ctx, 100)
+	tag := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("tag")))
+
+	var entries []database.AuditLogEntry
+	if tag != "" {
+		entries, _ = h.auditLogger.GetByRequestTag(ctx, tag, 100)
+	} else {
+		entries, _ = h.auditLogger.GetRecent(ctx, 100)
+	}
 
 	h.render(w, r, "history.html", map[string]interface{}{
 		"Title":   "Audit History",
 		"Entries": entries,
+		"Tag":     tag,
 	})
 }
 
+// apiKeysPageSize is the number of keys shown per page in the web UI.
+const apiKeysPageSize = 25
+
 // APIKeys shows API key management.
 func (h *Handler) APIKeys(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	keys, err := h.apiKeyRepo.List(ctx, false)
+
+	page := 1
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 1 {
+		page = p
+	}
+
+	keys, total, err := h.apiKeyRepo.List(ctx, apikeys.ListOptions{
+		Limit:  apiKeysPageSize,
+		Offset: (page - 1) * apiKeysPageSize,
+	})
 	if err != nil {
 		http.Error(w, "Failed to load API keys: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	totalPages := (total + apiKeysPageSize - 1) / apiKeysPageSize
+
 	h.render(w, r, "apikeys.html", map[string]interface{}{
-		"Title": "API Keys",
-		"Keys":  keys,
+		"Title":      "API Keys",
+		"Keys":       keys,
+		"AllScopes":  apikeys.AllScopes(),
+		"Total":      total,
+		"Page":       page,
+		"TotalPages": totalPages,
+		"PrevPage":   page - 1,
+		"NextPage":   page + 1,
+		"HasPrev":    page > 1,
+		"HasNext":    page < totalPages,
 	})
 }
 
@@ -729,8 +1160,18 @@ func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// No scopes selected means the tier's default set
+	var scopes []string
+	if selected := r.Form["scopes"]; len(selected) > 0 {
+		if err := apikeys.ValidateScopes(tier, selected); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		scopes = selected
+	}
+
 	ctx := r.Context()
-	apiKey, fullKey, err := h.apiKeyRepo.Create(ctx, name, tier, nil)
+	apiKey, fullKey, err := h.apiKeyRepo.CreateWithScopes(ctx, name, tier, scopes, nil)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -738,8 +1179,9 @@ func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 
 	// Log to audit
 	h.auditLogger.Log(ctx, database.AuditAPIKeyCreated, "", apiKey.ID, "web:admin", map[string]interface{}{
-		"name": name,
-		"tier": tier,
+		"name":   name,
+		"tier":   tier,
+		"scopes": apiKey.Scopes,
 	})
 
 	// If HTMX request, return the new key display with copy button
@@ -813,6 +1255,9 @@ type NotificationConfigView struct {
 	URL            string // for generic webhook
 	Secret         string // for generic webhook HMAC
 	TimeoutSeconds int    // for generic webhook
+	MinPriority    string // lowest request priority to notify about
+
+	MessageTemplate string // custom approval message template
 }
 
 // Settings shows settings page.
@@ -823,6 +1268,7 @@ func (h *Handler) Settings(w http.ResponseWriter, r *http.Request) {
 	updated := r.URL.Query().Get("updated") == "1"
 	notificationsUpdated := r.URL.Query().Get("notifications_updated") == "1"
 	oauthUpdated := r.URL.Query().Get("oauth_updated") == "1"
+	sessionsRevoked := r.URL.Query().Get("sessions_revoked") == "1"
 
 	// Load notification credentials from database
 	ntfyConfig := NotificationConfigView{Server: "https://ntfy.sh", Priority: "high"}
@@ -842,6 +1288,8 @@ func (h *Handler) Settings(w http.ResponseWriter, r *http.Request) {
 				ntfyConfig.Topic = nc.Topic
 				ntfyConfig.Token = nc.Token
 				ntfyConfig.Priority = nc.Priority
+				ntfyConfig.MinPriority = nc.MinPriority
+				ntfyConfig.MessageTemplate = nc.MessageTemplate
 			}
 		}
 		if creds, _ := h.credentialsStore.Load(ctx, "pushover"); creds != nil {
@@ -851,6 +1299,8 @@ func (h *Handler) Settings(w http.ResponseWriter, r *http.Request) {
 				pushoverConfig.UserKey = pc.UserKey
 				pushoverConfig.Priority = pc.Priority
 				pushoverConfig.Sound = pc.Sound
+				pushoverConfig.MinPriority = pc.MinPriority
+				pushoverConfig.MessageTemplate = pc.MessageTemplate
 			}
 		}
 		if creds, _ := h.credentialsStore.Load(ctx, "telegram"); creds != nil {
@@ -859,6 +1309,8 @@ func (h *Handler) Settings(w http.ResponseWriter, r *http.Request) {
 				telegramConfig.BotToken = tc.BotToken
 				telegramConfig.ChatID = tc.ChatID
 				telegramConfig.WebhookSecret = tc.WebhookSecret
+				telegramConfig.MinPriority = tc.MinPriority
+				telegramConfig.MessageTemplate = tc.MessageTemplate
 			}
 		}
 		if creds, _ := h.credentialsStore.Load(ctx, "webhook"); creds != nil {
@@ -867,6 +1319,7 @@ func (h *Handler) Settings(w http.ResponseWriter, r *http.Request) {
 				webhookConfig.URL = wc.URL
 				webhookConfig.Secret = wc.Secret
 				webhookConfig.TimeoutSeconds = wc.TimeoutSeconds
+				webhookConfig.MinPriority = wc.MinPriority
 			}
 		}
 		// Load Google OAuth credentials
@@ -917,11 +1370,22 @@ func (h *Handler) Settings(w http.ResponseWriter, r *http.Request) {
 		hasApprovalPIN, _ = h.settingsStore.HasApprovalPIN(ctx)
 	}
 
+	sessions, err := h.listSessions(r)
+	if err != nil {
+		util.Error("Failed to list sessions", "error", err)
+	}
+
+	oauthStatus, err := h.oauthMgr.TokenStatus(ctx)
+	if err != nil {
+		util.Error("Failed to load OAuth token status", "error", err)
+	}
+
 	h.render(w, r, "settings.html", map[string]interface{}{
 		"Title":                 "Settings",
 		"Providers":             providers,
 		"OAuthConnected":        oauthConnected,
 		"OAuthConfigured":       h.oauthMgr.IsConfigured(),
+		"OAuthStatus":           oauthStatus,
 		"Config":                h.config,
 		"Updated":               updated,
 		"NotificationsUpdated":  notificationsUpdated,
@@ -933,6 +1397,8 @@ func (h *Handler) Settings(w http.ResponseWriter, r *http.Request) {
 		"GoogleOAuthClientID":   googleOAuthClientID,
 		"GoogleOAuthConfigured": googleOAuthConfigured,
 		"HasApprovalPIN":        hasApprovalPIN,
+		"Sessions":              sessions,
+		"SessionsRevoked":       sessionsRevoked,
 	})
 }
 
@@ -944,15 +1410,18 @@ func (h *Handler) SaveNotificationSettings(w http.ResponseWriter, r *http.Reques
 	}
 
 	ctx := r.Context()
+	var err error
+	before := h.credentialsSnapshot(ctx, notificationProviders...)
 
 	// Save ntfy config
 	ntfyEnabled := r.FormValue("ntfy_enabled") == "on"
 	if ntfyEnabled {
 		ntfyCreds := &notifications.NtfyCredentials{
-			ServerURL:      strings.TrimSpace(r.FormValue("ntfy_server")),
-			Topic:          strings.TrimSpace(r.FormValue("ntfy_topic")),
-			Token:          strings.TrimSpace(r.FormValue("ntfy_token")),
-			Priority:       strings.TrimSpace(r.FormValue("ntfy_priority")),
+			ServerURL:   strings.TrimSpace(r.FormValue("ntfy_server")),
+			Topic:       strings.TrimSpace(r.FormValue("ntfy_topic")),
+			Token:       strings.TrimSpace(r.FormValue("ntfy_token")),
+			Priority:    strings.TrimSpace(r.FormValue("ntfy_priority")),
+			MinPriority: formMinPriority(r, "ntfy_min_priority"),
 		}
 		if ntfyCreds.ServerURL == "" {
 			ntfyCreds.ServerURL = "https://ntfy.sh"
@@ -961,12 +1430,17 @@ func (h *Handler) SaveNotificationSettings(w http.ResponseWriter, r *http.Reques
 			h.renderSettingsError(w, r, "ntfy topic is required when ntfy is enabled")
 			return
 		}
+		if ntfyCreds.MessageTemplate, err = formMessageTemplate(r, "ntfy_message_template"); err != nil {
+			h.renderSettingsError(w, r, "ntfy message template: "+err.Error())
+			return
+		}
 		if err := h.credentialsStore.Save(ctx, "ntfy", true, ntfyCreds); err != nil {
 			h.renderSettingsError(w, r, "failed to save ntfy credentials")
 			return
 		}
-	} else {
-		h.credentialsStore.Save(ctx, "ntfy", false, &notifications.NtfyCredentials{})
+	} else if err := h.credentialsStore.SetEnabled(ctx, "ntfy", false); err != nil {
+		h.renderSettingsError(w, r, "failed to disable ntfy")
+		return
 	}
 
 	// Save Pushover config
@@ -974,21 +1448,27 @@ func (h *Handler) SaveNotificationSettings(w http.ResponseWriter, r *http.Reques
 	if pushoverEnabled {
 		priority, _ := strconv.Atoi(r.FormValue("pushover_priority"))
 		pushoverCreds := &notifications.PushoverCredentials{
-			AppToken: strings.TrimSpace(r.FormValue("pushover_app_token")),
-			UserKey:  strings.TrimSpace(r.FormValue("pushover_user_key")),
-			Priority: priority,
-			Sound:    strings.TrimSpace(r.FormValue("pushover_sound")),
+			AppToken:    strings.TrimSpace(r.FormValue("pushover_app_token")),
+			UserKey:     strings.TrimSpace(r.FormValue("pushover_user_key")),
+			Priority:    priority,
+			Sound:       strings.TrimSpace(r.FormValue("pushover_sound")),
+			MinPriority: formMinPriority(r, "pushover_min_priority"),
 		}
 		if pushoverCreds.AppToken == "" || pushoverCreds.UserKey == "" {
 			h.renderSettingsError(w, r, "Pushover app token and user key are required")
 			return
 		}
+		if pushoverCreds.MessageTemplate, err = formMessageTemplate(r, "pushover_message_template"); err != nil {
+			h.renderSettingsError(w, r, "Pushover message template: "+err.Error())
+			return
+		}
 		if err := h.credentialsStore.Save(ctx, "pushover", true, pushoverCreds); err != nil {
 			h.renderSettingsError(w, r, "failed to save Pushover credentials")
 			return
 		}
-	} else {
-		h.credentialsStore.Save(ctx, "pushover", false, &notifications.PushoverCredentials{})
+	} else if err := h.credentialsStore.SetEnabled(ctx, "pushover", false); err != nil {
+		h.renderSettingsError(w, r, "failed to disable Pushover")
+		return
 	}
 
 	// Save Telegram config
@@ -998,17 +1478,23 @@ func (h *Handler) SaveNotificationSettings(w http.ResponseWriter, r *http.Reques
 			BotToken:      strings.TrimSpace(r.FormValue("telegram_bot_token")),
 			ChatID:        strings.TrimSpace(r.FormValue("telegram_chat_id")),
 			WebhookSecret: strings.TrimSpace(r.FormValue("telegram_webhook_secret")),
+			MinPriority:   formMinPriority(r, "telegram_min_priority"),
 		}
 		if telegramCreds.BotToken == "" || telegramCreds.ChatID == "" {
 			h.renderSettingsError(w, r, "Telegram bot token and chat ID are required")
 			return
 		}
+		if telegramCreds.MessageTemplate, err = formMessageTemplate(r, "telegram_message_template"); err != nil {
+			h.renderSettingsError(w, r, "Telegram message template: "+err.Error())
+			return
+		}
 		if err := h.credentialsStore.Save(ctx, "telegram", true, telegramCreds); err != nil {
 			h.renderSettingsError(w, r, "failed to save Telegram credentials")
 			return
 		}
-	} else {
-		h.credentialsStore.Save(ctx, "telegram", false, &notifications.TelegramCredentials{})
+	} else if err := h.credentialsStore.SetEnabled(ctx, "telegram", false); err != nil {
+		h.renderSettingsError(w, r, "failed to disable Telegram")
+		return
 	}
 
 	// Save Webhook config
@@ -1025,6 +1511,7 @@ func (h *Handler) SaveNotificationSettings(w http.ResponseWriter, r *http.Reques
 			URL:            strings.TrimSpace(r.FormValue("webhook_url")),
 			Secret:         strings.TrimSpace(r.FormValue("webhook_secret")),
 			TimeoutSeconds: timeout,
+			MinPriority:    formMinPriority(r, "webhook_min_priority"),
 		}
 		if webhookCreds.URL == "" {
 			h.renderSettingsError(w, r, "Webhook URL is required when webhook is enabled")
@@ -1034,8 +1521,9 @@ func (h *Handler) SaveNotificationSettings(w http.ResponseWriter, r *http.Reques
 			h.renderSettingsError(w, r, "failed to save Webhook credentials")
 			return
 		}
-	} else {
-		h.credentialsStore.Save(ctx, "webhook", false, &notifications.WebhookCredentials{})
+	} else if err := h.credentialsStore.SetEnabled(ctx, "webhook", false); err != nil {
+		h.renderSettingsError(w, r, "failed to disable Webhook")
+		return
 	}
 
 	// Audit log
@@ -1046,12 +1534,76 @@ func (h *Handler) SaveNotificationSettings(w http.ResponseWriter, r *http.Reques
 			"pushover_enabled":              pushoverEnabled,
 			"telegram_enabled":              telegramEnabled,
 			"webhook_enabled":               webhookEnabled,
+			"changes":                       settings.Diff(before, h.credentialsSnapshot(ctx, notificationProviders...), secretCredentialFields),
 		})
 	}
 
 	http.Redirect(w, r, "/settings?notifications_updated=1", http.StatusSeeOther)
 }
 
+// notificationProviders are the providers configured on the settings page.
+var notificationProviders = []string{"ntfy", "pushover", "telegram", "webhook"}
+
+// secretCredentialFields are credential fields whose values never appear in
+// settings change diffs; a change only shows as redacted.
+var secretCredentialFields = map[string]bool{
+	"token":          true,
+	"app_token":      true,
+	"user_key":       true,
+	"bot_token":      true,
+	"webhook_secret": true,
+	"secret":         true,
+	"client_secret":  true,
+}
+
+// credentialsSnapshot returns the stored settings of the given providers,
+// keyed "provider.field" by credential JSON name, for diffing changes.
+func (h *Handler) credentialsSnapshot(ctx context.Context, providers ...string) map[string]interface{} {
+	snapshot := make(map[string]interface{})
+	for _, provider := range providers {
+		creds, err := h.credentialsStore.Load(ctx, provider)
+		if err != nil || creds == nil {
+			continue
+		}
+		snapshot[provider+".enabled"] = creds.Enabled
+		data, err := json.Marshal(creds.Credentials)
+		if err != nil {
+			continue
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			continue
+		}
+		for name, value := range fields {
+			snapshot[provider+"."+name] = value
+		}
+	}
+	return snapshot
+}
+
+// formMinPriority reads a provider's minimum request priority from the form.
+// Unknown values fall back to notifying for every request.
+func formMinPriority(r *http.Request, field string) string {
+	value := strings.TrimSpace(r.FormValue(field))
+	if !database.ValidPriority(value) {
+		return ""
+	}
+	return value
+}
+
+// formMessageTemplate reads and validates a provider's custom approval
+// message template. Blank means the built-in message.
+func formMessageTemplate(r *http.Request, field string) (string, error) {
+	value := strings.TrimSpace(strings.ReplaceAll(r.FormValue(field), "\r\n", "\n"))
+	if value == "" {
+		return "", nil
+	}
+	if err := notifications.ValidateMessageTemplate(value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
 // SaveGoogleOAuthSettings saves Google OAuth credentials.
 func (h *Handler) SaveGoogleOAuthSettings(w http.ResponseWriter, r *http.Request) {
 	if h.credentialsStore == nil {
@@ -1060,6 +1612,7 @@ func (h *Handler) SaveGoogleOAuthSettings(w http.ResponseWriter, r *http.Request
 	}
 
 	ctx := r.Context()
+	before := h.credentialsSnapshot(ctx, "google_oauth")
 
 	clientID := strings.TrimSpace(r.FormValue("google_client_id"))
 	clientSecret := strings.TrimSpace(r.FormValue("google_client_secret"))
@@ -1097,8 +1650,11 @@ func (h *Handler) SaveGoogleOAuthSettings(w http.ResponseWriter, r *http.Request
 
 	// Audit log (don't log actual credentials)
 	if h.auditLogger != nil {
-		h.auditLogger.Log(ctx, database.AuditSettingsChanged, "", "", "web:admin",
-			map[string]interface{}{"google_oauth_updated": true, "configured": clientID != ""})
+		h.auditLogger.Log(ctx, database.AuditSettingsChanged, "", "", "web:admin", map[string]interface{}{
+			"google_oauth_updated": true,
+			"configured":           clientID != "",
+			"changes":              settings.Diff(before, h.credentialsSnapshot(ctx, "google_oauth"), secretCredentialFields),
+		})
 	}
 
 	http.Redirect(w, r, "/settings?oauth_updated=1", http.StatusSeeOther)
@@ -1112,6 +1668,7 @@ func (h *Handler) SaveSettings(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
+	before := settings.Snapshot(h.config)
 
 	approvalTimeout, err := parseIntField(r, "approval_timeout_minutes", h.config.Approval.TimeoutMinutes)
 	if err != nil {
@@ -1133,12 +1690,36 @@ func (h *Handler) SaveSettings(w http.ResponseWriter, r *http.Request) {
 		h.renderSettingsError(w, r, err.Error())
 		return
 	}
+	retentionUnclaimed, err := parseIntField(r, "retention_unclaimed_hours", h.config.Retention.UnclaimedResultsHours)
+	if err != nil {
+		h.renderSettingsError(w, r, err.Error())
+		return
+	}
 	retentionEnabled := r.FormValue("retention_enabled") == "on"
+	requireAll := r.FormValue("approval_require_all") == "on"
 
 	defaultAction := strings.TrimSpace(r.FormValue("approval_default_action"))
 	if defaultAction == "" {
 		defaultAction = h.config.Approval.DefaultAction
 	}
+	approvers := []string{}
+	for _, line := range strings.Split(r.FormValue("approvers"), "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			approvers = append(approvers, name)
+		}
+	}
+	approvalRoutes := []config.ApprovalRoute{}
+	for _, line := range strings.Split(r.FormValue("approval_routes"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			h.renderSettingsError(w, r, fmt.Sprintf("approval route %q must be: calendar provider target", strings.TrimSpace(line)))
+			return
+		}
+		approvalRoutes = append(approvalRoutes, config.ApprovalRoute{Calendar: fields[0], Provider: fields[1], Target: fields[2]})
+	}
 	logLevel := strings.TrimSpace(r.FormValue("logging_level"))
 	if logLevel == "" {
 		logLevel = h.config.Logging.Level
@@ -1163,6 +1744,11 @@ func (h *Handler) SaveSettings(w http.ResponseWriter, r *http.Request) {
 	if displayDatetimeFormat == "" {
 		displayDatetimeFormat = h.config.Display.DatetimeFormat
 	}
+	displayLocale := strings.TrimSpace(r.FormValue("display_locale"))
+	if displayLocale == "" {
+		displayLocale = h.config.Display.Locale
+	}
+	displayRelativeTime := r.FormValue("display_relative_time") == "on"
 
 	// Parse server base URL
 	serverBaseURL := strings.TrimSpace(r.FormValue("server_base_url"))
@@ -1204,12 +1790,16 @@ func (h *Handler) SaveSettings(w http.ResponseWriter, r *http.Request) {
 		Approval: &settings.ApprovalSettings{
 			TimeoutMinutes: approvalTimeout,
 			DefaultAction:  defaultAction,
+			Approvers:      approvers,
+			Routes:         approvalRoutes,
+			RequireAll:     &requireAll,
 		},
 		Retention: &settings.RetentionSettings{
 			Enabled:               &retentionEnabled,
 			CompletedRequestsDays: retentionRequests,
 			AuditLogDays:          retentionAudit,
 			WebhookFailuresDays:   retentionWebhook,
+			UnclaimedResultsHours: &retentionUnclaimed,
 		},
 		Logging: &settings.LoggingSettings{
 			Level:  logLevel,
@@ -1220,6 +1810,8 @@ func (h *Handler) SaveSettings(w http.ResponseWriter, r *http.Request) {
 			DateFormat:     displayDateFormat,
 			TimeFormat:     displayTimeFormat,
 			DatetimeFormat: displayDatetimeFormat,
+			Locale:         displayLocale,
+			RelativeTime:   &displayRelativeTime,
 		},
 		Server: &settings.ServerSettings{
 			BaseURL: serverBaseURL,
@@ -1252,15 +1844,24 @@ func (h *Handler) SaveSettings(w http.ResponseWriter, r *http.Request) {
 		h.config.Display.DateFormat,
 		h.config.Display.TimeFormat,
 		h.config.Display.DatetimeFormat,
+		h.config.Display.Locale,
+		h.config.Display.RelativeTime,
 	)
 	if err == nil {
 		util.SetDefaultFormatter(formatter)
 	}
 
 	if h.auditLogger != nil {
+		changes := settings.Diff(before, settings.Snapshot(h.config), nil)
+		if clearPIN || approvalPIN != "" {
+			changes = append(changes, settings.Change{Field: "approval_pin", Old: settings.Redacted, New: settings.Redacted})
+		}
 		h.auditLogger.Log(ctx, database.AuditSettingsChanged, "", "", "web:admin", map[string]interface{}{
 			"approval_timeout_minutes": approvalTimeout,
 			"approval_default_action":  defaultAction,
+			"approvers":                approvers,
+			"approval_routes":          len(approvalRoutes),
+			"approval_require_all":     requireAll,
 			"retention_enabled":        retentionEnabled,
 			"retention_completed_days": retentionRequests,
 			"retention_audit_days":     retentionAudit,
@@ -1271,7 +1872,10 @@ func (h *Handler) SaveSettings(w http.ResponseWriter, r *http.Request) {
 			"display_date_format":      displayDateFormat,
 			"display_time_format":      displayTimeFormat,
 			"display_datetime_format":  displayDatetimeFormat,
+			"display_locale":           displayLocale,
+			"display_relative_time":    displayRelativeTime,
 			"server_base_url":          serverBaseURL,
+			"changes":                  changes,
 		})
 	}
 
@@ -1324,6 +1928,275 @@ func (h *Handler) TestNotification(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// TestWebhook sends a test event to the Moltbot webhook.
+func (h *Handler) TestWebhook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if h.webhookPinger == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"message": "Webhook client unavailable",
+		})
+		return
+	}
+
+	result, err := h.webhookPinger.Ping(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"message": "Test failed: " + err.Error(),
+		})
+		return
+	}
+
+	if !result.Success {
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":     false,
+			"message":     fmt.Sprintf("Test failed after %d ms: %s", result.LatencyMs, result.Error),
+			"status_code": result.StatusCode,
+			"latency_ms":  result.LatencyMs,
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":     true,
+		"message":     fmt.Sprintf("Webhook responded with HTTP %d in %d ms", result.StatusCode, result.LatencyMs),
+		"status_code": result.StatusCode,
+		"latency_ms":  result.LatencyMs,
+	})
+}
+
+// webhookFailuresPageSize is the number of failures shown per page.
+const webhookFailuresPageSize = 25
+
+// WebhookFailures lists failed webhook deliveries with state and request filters.
+func (h *Handler) WebhookFailures(w http.ResponseWriter, r *http.Request) {
+	if h.webhookFailures == nil {
+		http.Error(w, "Webhook client unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	state := query.Get("state")
+	switch state {
+	case webhook.FailuresUnresolved, webhook.FailuresResolved, webhook.FailuresAll:
+	default:
+		state = webhook.FailuresUnresolved
+	}
+	requestID := strings.TrimSpace(query.Get("request_id"))
+
+	page := 1
+	if p, err := strconv.Atoi(query.Get("page")); err == nil && p > 1 {
+		page = p
+	}
+
+	failures, total, err := h.webhookFailures.ListFailures(r.Context(), webhook.FailureListOptions{
+		State:     state,
+		RequestID: requestID,
+		Limit:     webhookFailuresPageSize,
+		Offset:    (page - 1) * webhookFailuresPageSize,
+	})
+	if err != nil {
+		http.Error(w, "Failed to load webhook failures: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	totalPages := (total + webhookFailuresPageSize - 1) / webhookFailuresPageSize
+
+	h.render(w, r, "webhook_failures.html", map[string]interface{}{
+		"Title":      "Webhook Failures",
+		"Failures":   failures,
+		"State":      state,
+		"RequestID":  requestID,
+		"Total":      total,
+		"Page":       page,
+		"TotalPages": totalPages,
+		"PrevPage":   page - 1,
+		"NextPage":   page + 1,
+		"HasPrev":    page > 1,
+		"HasNext":    page < totalPages,
+	})
+}
+
+// RetryWebhookFailure redelivers one failed webhook and reports the outcome as JSON.
+func (h *Handler) RetryWebhookFailure(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if h.webhookFailures == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"message": "Webhook client unavailable",
+		})
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"message": "Invalid failure ID",
+		})
+		return
+	}
+
+	ctx := r.Context()
+	failure, err := h.webhookFailures.RetryFailure(ctx, id)
+	if failure == nil || errors.Is(err, webhook.ErrFailureResolved) || errors.Is(err, webhook.ErrNotConfigured) {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, webhook.ErrFailureNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, webhook.ErrFailureResolved):
+			status = http.StatusConflict
+		case errors.Is(err, webhook.ErrNotConfigured):
+			status = http.StatusBadRequest
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"message": "Retry failed: " + err.Error(),
+		})
+		return
+	}
+
+	h.auditLogger.Log(ctx, database.AuditWebhookRetried, failure.RequestID, "", "web:admin", map[string]interface{}{
+		"failure_id": failure.ID,
+		"success":    err == nil,
+	})
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":  false,
+			"message":  "Delivery failed again: " + err.Error(),
+			"attempts": failure.Attempts,
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Webhook delivered",
+	})
+}
+
+// listSessions returns active sessions with the caller's own session flagged.
+func (h *Handler) listSessions(r *http.Request) ([]SessionInfo, error) {
+	sessions, err := h.sessionMgr.ListSessions(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	currentID := GetSessionID(r)
+	for i := range sessions {
+		sessions[i].Current = sessions[i].id == currentID
+	}
+	return sessions, nil
+}
+
+// ListSessions returns active web sessions as JSON.
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	sessions, err := h.listSessions(r)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "failed to list sessions",
+		})
+		return
+	}
+	if sessions == nil {
+		sessions = []SessionInfo{}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sessions": sessions,
+	})
+}
+
+// RevokeSession deletes a single session identified by its ID prefix.
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	prefix := r.PathValue("sessionId")
+
+	ctx := r.Context()
+	sessions, err := h.listSessions(r)
+	if err != nil {
+		http.Error(w, "failed to list sessions", http.StatusInternalServerError)
+		return
+	}
+
+	var target *SessionInfo
+	for i := range sessions {
+		if sessions[i].IDPrefix == prefix {
+			target = &sessions[i]
+			break
+		}
+	}
+	if target == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.sessionMgr.DeleteSession(ctx, target.id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Log to audit
+	h.auditLogger.Log(ctx, database.AuditSessionRevoked, "", "", "web:admin", map[string]interface{}{
+		"session":    target.IDPrefix,
+		"ip_address": target.IPAddress,
+		"user_agent": target.UserAgent,
+		"current":    target.Current,
+	})
+
+	redirect := "/settings?sessions_revoked=1"
+	if target.Current {
+		ClearSessionCookie(w)
+		redirect = "/login"
+	}
+
+	// If HTMX request
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", redirect)
+		return
+	}
+
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+// RevokeOtherSessions deletes every session except the caller's own.
+func (h *Handler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentID := GetSessionID(r)
+
+	revoked, err := h.sessionMgr.DeleteAllExcept(ctx, currentID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Log to audit
+	h.auditLogger.Log(ctx, database.AuditSessionRevoked, "", "", "web:admin", map[string]interface{}{
+		"scope":   "all_except_current",
+		"revoked": revoked,
+	})
+
+	// If HTMX request
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", "/settings?sessions_revoked=1")
+		return
+	}
+
+	http.Redirect(w, r, "/settings?sessions_revoked=1", http.StatusSeeOther)
+}
+
 // OAuthStart initiates OAuth flow.
 func (h *Handler) OAuthStart(w http.ResponseWriter, r *http.Request) {
 	// Check if OAuth is configured
@@ -1402,11 +2275,16 @@ func (h *Handler) PublicApprove(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	// Check if PIN is required
+	// Check if PIN is required. Links created by a signed-in admin skip it.
 	requiresPIN := false
 	if h.settingsStore != nil {
 		requiresPIN, _ = h.settingsStore.HasApprovalPIN(ctx)
 	}
+	if requiresPIN {
+		if result, err := h.tokenRepo.Validate(ctx, token); err == nil && result.Valid && result.PinSatisfied {
+			requiresPIN = false
+		}
+	}
 
 	// Handle POST (approval/denial action)
 	if r.Method == http.MethodPost {
@@ -1416,6 +2294,17 @@ func (h *Handler) PublicApprove(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// Named approvers must say who is deciding
+		decidedBy := "link"
+		if len(h.config.Approval.Approvers) > 0 {
+			approver, ok := h.config.Approval.Approver(r.FormValue("approver"))
+			if !ok {
+				h.renderApproveWithError(w, r, token, requiresPIN, "ApproverError", "Choose who is approving.")
+				return
+			}
+			decidedBy = "link:" + approver
+		}
+
 		// Validate PIN if required
 		if requiresPIN {
 			pin := r.FormValue("pin")
@@ -1426,7 +2315,7 @@ func (h *Handler) PublicApprove(w http.ResponseWriter, r *http.Request) {
 			}
 			if !valid {
 				// Re-show the form with error
-				h.renderApproveWithPINError(w, ctx, token, "Incorrect PIN. Please try again.")
+				h.renderApproveWithError(w, r, token, requiresPIN, "PINError", "Incorrect PIN. Please try again.")
 				return
 			}
 		}
@@ -1439,7 +2328,7 @@ func (h *Handler) PublicApprove(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Process the approval/denial
-		if err := h.engine.ProcessApproval(ctx, requestID, action, "link"); err != nil {
+		if err := h.engine.ProcessApproval(ctx, requestID, action, decidedBy); err != nil {
 			h.renderApproveError(w, "Processing Failed", err.Error(), false)
 			return
 		}
@@ -1486,22 +2375,32 @@ func (h *Handler) PublicApprove(w http.ResponseWriter, r *http.Request) {
 
 	// Parse event details from payload
 	eventDetails := extractEventDetails(req.Payload)
+	eventDetails.GuestPermissions = guestPermissions(req.Operation, req.Payload)
 
 	// Calculate expires in
 	expiresIn := formatDuration(time.Until(req.ExpiresAt))
 
-	h.renderApprove(w, "approve-layout", map[string]interface{}{
+	data := map[string]interface{}{
 		"Title":        "Approve Request",
 		"Token":        token,
 		"Request":      req,
 		"EventDetails": eventDetails,
 		"ExpiresIn":    expiresIn,
 		"RequiresPIN":  requiresPIN,
-	})
+		"Approvers":    h.config.Approval.Approvers,
+	}
+	addTimezoneData(r, data)
+	if req.Operation == database.OperationUpdateEvent {
+		data["Diff"], data["DiffUnavailable"] = h.updateDiff(ctx, req)
+	}
+	h.renderApprove(w, "approve-layout", data)
 }
 
-// renderApproveWithPINError re-renders the approval page with a PIN error message.
-func (h *Handler) renderApproveWithPINError(w http.ResponseWriter, ctx context.Context, token, pinError string) {
+// renderApproveWithError re-renders the approval page with an error message
+// for one field, e.g. "PINError" or "ApproverError".
+func (h *Handler) renderApproveWithError(w http.ResponseWriter, r *http.Request, token string, requiresPIN bool, errorField, message string) {
+	ctx := r.Context()
+
 	// Re-validate token to get request details
 	result, err := h.tokenRepo.Validate(ctx, token)
 	if err != nil || !result.Valid {
@@ -1516,17 +2415,24 @@ func (h *Handler) renderApproveWithPINError(w http.ResponseWriter, ctx context.C
 	}
 
 	eventDetails := extractEventDetails(req.Payload)
+	eventDetails.GuestPermissions = guestPermissions(req.Operation, req.Payload)
 	expiresIn := formatDuration(time.Until(req.ExpiresAt))
 
-	h.renderApprove(w, "approve-layout", map[string]interface{}{
+	data := map[string]interface{}{
 		"Title":        "Approve Request",
 		"Token":        token,
 		"Request":      req,
 		"EventDetails": eventDetails,
 		"ExpiresIn":    expiresIn,
-		"RequiresPIN":  true,
-		"PINError":     pinError,
-	})
+		"RequiresPIN":  requiresPIN,
+		"Approvers":    h.config.Approval.Approvers,
+		errorField:     message,
+	}
+	addTimezoneData(r, data)
+	if req.Operation == database.OperationUpdateEvent {
+		data["Diff"], data["DiffUnavailable"] = h.updateDiff(ctx, req)
+	}
+	h.renderApprove(w, "approve-layout", data)
 }
 
 // EventDetails holds extracted event information for display.
@@ -1534,9 +2440,94 @@ type EventDetails struct {
 	Title       string
 	StartTime   string
 	EndTime     string
+	Start       time.Time // zero for all-day events
+	End         time.Time
 	Location    string
 	Description string
 	Attendees   string
+	Rooms       string
+
+	OptionalAttendees string
+
+	DescriptionHTML template.HTML // sanitized formatting; empty for plain text descriptions
+
+	Visibility   string
+	Transparency string
+
+	GuestPermissions []google.GuestPermission
+	SendUpdates      string // who Google emails, as shown to approvers; empty if unset
+
+	RecurringEditScope string // which occurrences an update changes; empty if unset
+
+	// Move requests
+	Calendar            string
+	DestinationCalendar string
+	EventID             string
+
+	// Duplicate requests
+	DuplicateOfEventID string
+	DuplicateOfLink    string
+
+	// Batch create requests: one "calendar: title" line per event
+	BatchTargets []string
+}
+
+// guestPermissions returns the guest permissions approvers should see. A
+// create shows the effective value of every flag once it has guests or sets
+// a flag; an update shows only the flags it changes.
+func guestPermissions(operation string, payload []byte) []google.GuestPermission {
+	switch operation {
+	case database.OperationCreateEvent:
+		var intent google.EventIntent
+		if err := json.Unmarshal(payload, &intent); err != nil {
+			return nil
+		}
+		if len(intent.Attendees) == 0 && intent.GuestsCanModify == nil &&
+			intent.GuestsCanInviteOthers == nil && intent.GuestsCanSeeOtherGuests == nil {
+			return nil
+		}
+		return intent.GuestPermissions()
+	case database.OperationUpdateEvent:
+		var intent google.EventUpdateIntent
+		if err := json.Unmarshal(payload, &intent); err != nil {
+			return nil
+		}
+		return intent.GuestPermissions()
+	}
+	return nil
+}
+
+// sendUpdatesLabel describes a sendUpdates value for approvers.
+func sendUpdatesLabel(sendUpdates string) string {
+	switch sendUpdates {
+	case "all":
+		return "All guests"
+	case "externalOnly":
+		return "External guests only"
+	case "none":
+		return "No one"
+	}
+	return ""
+}
+
+// payloadEventTime reads an event time from a payload field. It returns the
+// time, or a zero time and a display string for all-day dates.
+func payloadEventTime(v interface{}) (time.Time, string) {
+	dt, _ := v.(string)
+	if field, ok := v.(map[string]interface{}); ok {
+		if date, ok := field["date"].(string); ok {
+			return time.Time{}, date + " (all day)"
+		}
+		dt, _ = field["dateTime"].(string)
+	}
+	t, err := time.Parse(time.RFC3339, dt)
+	if err != nil {
+		return time.Time{}, ""
+	}
+	if formatter := util.GetDefaultFormatter(); formatter != nil {
+		return t, formatter.FormatTimestamp(t)
+	}
+	return t, t.Format("Mon Jan 2, 2006 3:04 PM")
 }
 
 // extractEventDetails parses the request payload to extract event information.
@@ -1555,56 +2546,124 @@ func extractEventDetails(payload []byte) EventDetails {
 		details.Title = v
 	}
 
-	// Try to get start time
-	if start, ok := data["start"].(map[string]interface{}); ok {
-		if dt, ok := start["dateTime"].(string); ok {
-			if t, err := time.Parse(time.RFC3339, dt); err == nil {
-				formatter := util.GetDefaultFormatter()
-				if formatter != nil {
-					details.StartTime = formatter.FormatDateTime(t)
-				} else {
-					details.StartTime = t.Format("Mon Jan 2, 2006 3:04 PM")
-				}
-			}
-		} else if d, ok := start["date"].(string); ok {
-			details.StartTime = d + " (all day)"
-		}
-	}
+	// Try to get start time. Intents send RFC 3339 strings; Google's
+	// shape nests them under dateTime or date.
+	details.Start, details.StartTime = payloadEventTime(data["start"])
+	details.End, details.EndTime = payloadEventTime(data["end"])
 
 	// Location
 	if v, ok := data["location"].(string); ok {
 		details.Location = v
 	}
 
+	// Visibility and busy/free
+	details.Visibility, _ = data["visibility"].(string)
+	details.Transparency, _ = data["transparency"].(string)
+
+	// Attendee notifications
+	sendUpdates, _ := data["sendUpdates"].(string)
+	details.SendUpdates = sendUpdatesLabel(sendUpdates)
+
+	// Occurrences changed by a recurring event update
+	details.RecurringEditScope, _ = data["recurringEditScope"].(string)
+
+	// Original event (duplicate requests)
+	if src, ok := data["duplicateOf"].(map[string]interface{}); ok {
+		details.DuplicateOfEventID, _ = src["eventId"].(string)
+		details.DuplicateOfLink, _ = src["htmlLink"].(string)
+	}
+
+	// Every target of a batch create request
+	if events, ok := data["events"].([]interface{}); ok {
+		for i, raw := range events {
+			event, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			calendarID, _ := event["calendarId"].(string)
+			summary, _ := event["summary"].(string)
+			if i == 0 {
+				details.Title = summary
+			}
+			details.BatchTargets = append(details.BatchTargets, calendarID+": "+summary)
+		}
+	}
+
+	// Source/destination calendars (move requests)
+	if v, ok := data["destinationCalendarId"].(string); ok {
+		details.DestinationCalendar = v
+		details.Calendar, _ = data["calendarId"].(string)
+		details.EventID, _ = data["eventId"].(string)
+	}
+
 	// Description (truncate if long)
 	if v, ok := data["description"].(string); ok {
 		if len(v) > 200 {
-			details.Description = v[:200] + "..."
+			// Never cut inside a tag, so a formatted description still sanitizes cleanly
+			cut := v[:200]
+			if lt := strings.LastIndexByte(cut, '<'); lt > strings.LastIndexByte(cut, '>') {
+				cut = cut[:lt]
+			}
+			details.Description = cut + "..."
 		} else {
 			details.Description = v
 		}
+		details.DescriptionHTML = descriptionHTML(details.Description)
 	}
 
-	// Attendees
-	if attendees, ok := data["attendees"].([]interface{}); ok {
-		var emails []string
-		for _, a := range attendees {
-			if att, ok := a.(map[string]interface{}); ok {
-				if email, ok := att["email"].(string); ok {
-					emails = append(emails, email)
-				}
+	// Attendees, with optional guests listed apart
+	details.Attendees = summarizeEmails(payloadEmails(data["attendees"]))
+	details.OptionalAttendees = summarizeEmails(payloadEmails(data["optionalAttendees"]))
+
+	// Rooms are resource attendees, listed apart from people
+	if rooms, ok := data["rooms"].([]interface{}); ok {
+		var names []string
+		for _, room := range rooms {
+			if email, ok := room.(string); ok {
+				names = append(names, email)
 			}
 		}
-		if len(emails) > 0 {
-			if len(emails) > 3 {
-				details.Attendees = strings.Join(emails[:3], ", ") + fmt.Sprintf(" (+%d more)", len(emails)-3)
-			} else {
-				details.Attendees = strings.Join(emails, ", ")
+		details.Rooms = strings.Join(names, ", ")
+	}
+
+	return details
+}
+
+// descriptionHTML returns a formatted description as sanitized HTML, or ""
+// for plain text, which templates escape as usual. Descriptions are
+// sanitized when requests are made; this guards payloads stored before that
+// or edited since.
+func descriptionHTML(description string) template.HTML {
+	if !util.ContainsHTML(description) {
+		return ""
+	}
+	return template.HTML(util.SanitizeHTML(description))
+}
+
+// payloadEmails reads a list of attendees from a request payload, given
+// either as email addresses or as objects with an "email" field.
+func payloadEmails(v interface{}) []string {
+	attendees, _ := v.([]interface{})
+	var emails []string
+	for _, a := range attendees {
+		switch att := a.(type) {
+		case string:
+			emails = append(emails, att)
+		case map[string]interface{}:
+			if email, ok := att["email"].(string); ok {
+				emails = append(emails, email)
 			}
 		}
 	}
+	return emails
+}
 
-	return details
+// summarizeEmails lists up to three addresses and counts the rest.
+func summarizeEmails(emails []string) string {
+	if len(emails) > 3 {
+		return strings.Join(emails[:3], ", ") + fmt.Sprintf(" (+%d more)", len(emails)-3)
+	}
+	return strings.Join(emails, ", ")
 }
 
 // formatDuration formats a duration in a human-readable way.
@@ -0,0 +1,341 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/engine"
+	"github.com/dtorcivia/schedlock/internal/notifications"
+	"github.com/dtorcivia/schedlock/internal/requests"
+	"github.com/dtorcivia/schedlock/internal/tokens"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+func TestApprovalReason(t *testing.T) {
+	created := func(details string) database.AuditLogEntry {
+		return database.AuditLogEntry{EventType: database.AuditRequestCreated, Details: json.RawMessage(details)}
+	}
+
+	entries := []database.AuditLogEntry{
+		{EventType: database.AuditNotificationSent, Details: json.RawMessage(`{}`)},
+		created(`{"decision":"require_approval","decision_constraint":"max_attendees","decision_reason":"Too many attendees"}`),
+	}
+	got := approvalReason(entries)
+	if got == nil || got.Constraint != "max_attendees" || got.Reason != "Too many attendees" {
+		t.Fatalf("approvalReason = %+v", got)
+	}
+
+	for _, details := range []string{
+		`{"decision":"allow","decision_constraint":"tier","decision_reason":"admin-tier keys may perform create_event without approval"}`,
+		`{"operation":"create_event"}`,
+	} {
+		if got := approvalReason([]database.AuditLogEntry{created(details)}); got != nil {
+			t.Errorf("approvalReason(%s) = %+v, want nil", details, got)
+		}
+	}
+}
+
+func TestExtractEventDetailsAttendees(t *testing.T) {
+	details := extractEventDetails([]byte(`{
+		"summary": "Review",
+		"attendees": ["ana@example.com"],
+		"optionalAttendees": ["ben@example.com", "cy@example.com", "di@example.com", "ed@example.com"]
+	}`))
+	if details.Attendees != "ana@example.com" {
+		t.Errorf("Attendees = %q", details.Attendees)
+	}
+	if want := "ben@example.com, cy@example.com, di@example.com (+1 more)"; details.OptionalAttendees != want {
+		t.Errorf("OptionalAttendees = %q, want %q", details.OptionalAttendees, want)
+	}
+
+	details = extractEventDetails([]byte(`{"summary": "Review", "attendees": [{"email": "ana@example.com"}]}`))
+	if details.Attendees != "ana@example.com" || details.OptionalAttendees != "" {
+		t.Errorf("details = %+v", details)
+	}
+}
+
+func TestExtractEventDetailsDescriptionHTML(t *testing.T) {
+	details := extractEventDetails([]byte(`{"description": "<b>Agenda</b><script>alert(1)</script><a href=\"javascript:alert(1)\">x</a>"}`))
+	if want := `<b>Agenda</b><a>x</a>`; string(details.DescriptionHTML) != want {
+		t.Errorf("DescriptionHTML = %q, want %q", details.DescriptionHTML, want)
+	}
+
+	details = extractEventDetails([]byte(`{"description": "Agenda & notes"}`))
+	if details.DescriptionHTML != "" || details.Description != "Agenda & notes" {
+		t.Errorf("plain description = %+v", details)
+	}
+
+	long := strings.Repeat("x", 195) + `<a href="https://example.com/doc">doc</a>`
+	details = extractEventDetails([]byte(`{"description": ` + strconv.Quote(long) + `}`))
+	if want := strings.Repeat("x", 195) + "..."; details.Description != want {
+		t.Errorf("truncated Description = %q, want the cut before the link tag", details.Description)
+	}
+}
+
+// newSessionTestHandler returns a handler with only sessions and auditing
+// wired up, and three live sessions: current, other_1 and other_2.
+func newSessionTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	m := newTestSessionManager(t, &config.AuthConfig{SessionDuration: 24 * time.Hour})
+	for _, id := range []string{"sess_current_0000", "sess_other_1_0000", "sess_other_2_0000"} {
+		insertSession(t, m, id, time.Hour, 0)
+	}
+	return &Handler{sessionMgr: m, auditLogger: engine.NewAuditLogger(m.db)}
+}
+
+// remainingSessions returns the IDs of every stored session.
+func remainingSessions(t *testing.T, h *Handler) map[string]bool {
+	t.Helper()
+	rows, err := h.sessionMgr.db.Query(`SELECT id FROM sessions`)
+	if err != nil {
+		t.Fatalf("query sessions: %v", err)
+	}
+	defer rows.Close()
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		rows.Scan(&id)
+		ids[id] = true
+	}
+	return ids
+}
+
+// sessionRevokedDetails returns the details of every session_revoked audit entry.
+func sessionRevokedDetails(t *testing.T, h *Handler) []map[string]interface{} {
+	t.Helper()
+	entries, err := h.auditLogger.GetByEventType(context.Background(), database.AuditSessionRevoked, 10)
+	if err != nil {
+		t.Fatalf("GetByEventType() error = %v", err)
+	}
+	var details []map[string]interface{}
+	for _, entry := range entries {
+		var d map[string]interface{}
+		if err := json.Unmarshal(entry.Details, &d); err != nil {
+			t.Fatalf("audit details: %v", err)
+		}
+		details = append(details, d)
+	}
+	return details
+}
+
+func TestRevokeSession(t *testing.T) {
+	h := newSessionTestHandler(t)
+
+	req := httptest.NewRequest("POST", "/settings/sessions/sess_other_1/revoke", nil)
+	req.SetPathValue("sessionId", "sess_other_1")
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: "sess_current_0000"})
+	rr := httptest.NewRecorder()
+	h.RevokeSession(rr, req)
+
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/settings?sessions_revoked=1" {
+		t.Fatalf("response = %d to %q, want a redirect back to settings", rr.Code, rr.Header().Get("Location"))
+	}
+	ids := remainingSessions(t, h)
+	if len(ids) != 2 || !ids["sess_current_0000"] || !ids["sess_other_2_0000"] {
+		t.Errorf("remaining sessions = %v, want current and other_2", ids)
+	}
+
+	details := sessionRevokedDetails(t, h)
+	if len(details) != 1 || details[0]["session"] != "sess_other_1" || details[0]["current"] != false {
+		t.Errorf("audit details = %v, want one entry for sess_other_1", details)
+	}
+
+	// An unknown prefix revokes nothing
+	req = httptest.NewRequest("POST", "/settings/sessions/sess_missing/revoke", nil)
+	req.SetPathValue("sessionId", "sess_missing")
+	rr = httptest.NewRecorder()
+	h.RevokeSession(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("unknown session: got %d, want 404", rr.Code)
+	}
+	if ids := remainingSessions(t, h); len(ids) != 2 {
+		t.Errorf("remaining sessions = %v after revoking an unknown one", ids)
+	}
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	h := newSessionTestHandler(t)
+
+	req := httptest.NewRequest("POST", "/settings/sessions/revoke-others", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: "sess_current_0000"})
+	rr := httptest.NewRecorder()
+	h.RevokeOtherSessions(rr, req)
+
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("response = %d, want 303", rr.Code)
+	}
+	ids := remainingSessions(t, h)
+	if len(ids) != 1 || !ids["sess_current_0000"] {
+		t.Errorf("remaining sessions = %v, want only the current one", ids)
+	}
+
+	details := sessionRevokedDetails(t, h)
+	if len(details) != 1 || details[0]["scope"] != "all_except_current" || details[0]["revoked"] != float64(2) {
+		t.Errorf("audit details = %v, want one all_except_current entry revoking 2", details)
+	}
+}
+
+// countingProvider is an enabled notification provider that records how
+// many approvals it sent.
+type countingProvider struct{ sent int }
+
+func (p *countingProvider) Name() string  { return "telegram" }
+func (p *countingProvider) Enabled() bool { return true }
+
+func (p *countingProvider) SendApproval(ctx context.Context, n *notifications.ApprovalNotification) (string, error) {
+	p.sent++
+	return "msg_" + strconv.Itoa(p.sent), nil
+}
+
+func (p *countingProvider) SendResult(ctx context.Context, n *notifications.ResultNotification) error {
+	return nil
+}
+
+func (p *countingProvider) SendTest(ctx context.Context) error { return nil }
+
+// newRequestTestHandler returns a handler with templates, requests and
+// notifications wired up, and one pending request, req_pending.
+func newRequestTestHandler(t *testing.T) (*Handler, *countingProvider) {
+	t.Helper()
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_test', 'hash', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status)
+		VALUES ('req_pending', 'key_test', 'create_event', ?, ?, ?)
+	`, `{"calendarId":"primary","summary":"Planning","start":"2030-01-01T10:00:00Z","end":"2030-01-01T11:00:00Z"}`,
+		util.SQLiteTimestamp(time.Now().Add(time.Hour)), database.StatusPendingApproval); err != nil {
+		t.Fatalf("insert request: %v", err)
+	}
+
+	templates, err := loadTemplates(filepath.Join("..", "..", "web", "templates"))
+	if err != nil {
+		t.Fatalf("load templates: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Approval.ResendCooldownSeconds = 60
+	provider := &countingProvider{}
+	notifier := notifications.NewManager(db, cfg)
+	notifier.RegisterProvider(provider)
+
+	requestRepo := requests.NewRepository(db)
+	auditLogger := engine.NewAuditLogger(db)
+	e := engine.NewEngine(cfg, requestRepo, nil, auditLogger, tokens.NewRepository(db))
+	e.SetNotifier(notifier)
+
+	return &Handler{
+		config:          cfg,
+		templates:       templates,
+		sessionMgr:      NewSessionManager(db, &config.AuthConfig{SessionDuration: time.Hour}),
+		requestRepo:     requestRepo,
+		engine:          e,
+		notificationMgr: notifier,
+		auditLogger:     auditLogger,
+	}, provider
+}
+
+func TestRequestDetailNotificationLog(t *testing.T) {
+	h, _ := newRequestTestHandler(t)
+
+	detail := func() string {
+		req := httptest.NewRequest("GET", "/requests/req_pending", nil)
+		req.SetPathValue("requestId", "req_pending")
+		rr := httptest.NewRecorder()
+		h.RequestDetail(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("detail page: got %d: %s", rr.Code, rr.Body.String())
+		}
+		return rr.Body.String()
+	}
+
+	if body := detail(); !strings.Contains(body, "No notifications have been sent for this request.") {
+		t.Error("expected the empty notification log message")
+	}
+
+	for _, row := range []struct{ provider, status, errMsg string }{
+		{"telegram", database.NotificationSent, ""},
+		{"pushover", database.NotificationFailed, "invalid user key"},
+	} {
+		if _, err := h.sessionMgr.db.Exec(`
+			INSERT INTO notification_log (request_id, provider, status, error)
+			VALUES ('req_pending', ?, ?, NULLIF(?, ''))
+		`, row.provider, row.status, row.errMsg); err != nil {
+			t.Fatalf("insert notification log: %v", err)
+		}
+	}
+
+	body := detail()
+	for _, want := range []string{"telegram", "pushover", "badge-error", "invalid user key"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("detail page is missing %q from the notification log", want)
+		}
+	}
+	if strings.Contains(body, "No notifications have been sent") {
+		t.Error("detail page still shows the empty notification log message")
+	}
+}
+
+func TestResendNotification(t *testing.T) {
+	h, provider := newRequestTestHandler(t)
+
+	resend := func(force bool) *httptest.ResponseRecorder {
+		form := url.Values{}
+		if force {
+			form.Set("force", "1")
+		}
+		req := httptest.NewRequest("POST", "/requests/req_pending/resend", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetPathValue("requestId", "req_pending")
+		rr := httptest.NewRecorder()
+		h.ResendNotification(rr, req)
+		return rr
+	}
+
+	// The notification sent when the request was submitted
+	if err := h.notificationMgr.SendApprovalRequest(context.Background(), &notifications.ApprovalNotification{RequestID: "req_pending"}); err != nil {
+		t.Fatalf("SendApprovalRequest() error = %v", err)
+	}
+
+	// Every provider has delivered, so a plain resend is refused without
+	// starting the cooldown
+	rr := resend(false)
+	if rr.Code != http.StatusConflict || !strings.Contains(rr.Body.String(), "Resend to All") {
+		t.Errorf("resend: got %d: %s, want 409", rr.Code, rr.Body.String())
+	}
+
+	rr = resend(true)
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/requests/req_pending?notified=1" {
+		t.Fatalf("resend to all: got %d to %q, want a redirect to the detail page", rr.Code, rr.Header().Get("Location"))
+	}
+	if provider.sent != 2 {
+		t.Errorf("provider sent %d approvals, want 2", provider.sent)
+	}
+
+	rr = resend(true)
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") == "" {
+		t.Errorf("resend within the cooldown: got %d (Retry-After %q), want 429", rr.Code, rr.Header().Get("Retry-After"))
+	}
+	if provider.sent != 2 {
+		t.Errorf("provider sent %d approvals during the cooldown", provider.sent)
+	}
+}
@@ -3,24 +3,20 @@ package web
 import (
 	"net"
 	"net/http"
-	"strings"
+
+	"github.com/dtorcivia/schedlock/internal/util"
 )
 
-// clientIP attempts to extract the originating client IP from common headers.
+// clientIP returns the client IP resolved by the server's client IP
+// middleware, which only believes proxy headers from trusted proxies.
+// Without it the connection's peer address is used.
 func clientIP(r *http.Request) string {
 	if r == nil {
 		return ""
 	}
 
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		parts := strings.Split(xff, ",")
-		if len(parts) > 0 {
-			return strings.TrimSpace(parts[0])
-		}
-	}
-
-	if xrip := r.Header.Get("X-Real-IP"); xrip != "" {
-		return strings.TrimSpace(xrip)
+	if ip := util.ClientIPFromContext(r.Context()); ip != "" {
+		return ip
 	}
 
 	host, _, err := net.SplitHostPort(r.RemoteAddr)
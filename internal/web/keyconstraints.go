@@ -0,0 +1,242 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/settings"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+// ConstraintsForm holds the constraints editable on the key constraints
+// page, as form values. Lists are one entry per line.
+type ConstraintsForm struct {
+	CalendarAllowlist       string
+	DefaultCalendar         string
+	MaxDurationMinutes      string
+	MaxAttendees            string
+	MaxPendingRequests      string
+	AttendeeDomainAllowlist string
+	AttendeeDomainBlocklist string
+	AllowExternalAttendees  string // "", "true" or "false"
+	AllowedIPs              string
+	BlockAllDayEvents       bool
+}
+
+// constraintsFormFields are the JSON names of the constraints the form
+// edits. Every other constraint is kept as it is.
+var constraintsFormFields = []string{
+	"calendar_allowlist", "default_calendar", "max_duration_minutes", "max_attendees",
+	"max_pending_requests", "attendee_domain_allowlist", "attendee_domain_blocklist",
+	"allow_external_attendees", "allowed_ips", "block_all_day_events",
+}
+
+// newConstraintsForm fills the form from stored constraints.
+func newConstraintsForm(c *database.KeyConstraints) ConstraintsForm {
+	if c == nil {
+		return ConstraintsForm{}
+	}
+	form := ConstraintsForm{
+		CalendarAllowlist:       strings.Join(c.CalendarAllowlist, "\n"),
+		DefaultCalendar:         c.DefaultCalendar,
+		AttendeeDomainAllowlist: strings.Join(c.AttendeeDomainAllowlist, "\n"),
+		AttendeeDomainBlocklist: strings.Join(c.AttendeeDomainBlocklist, "\n"),
+		AllowedIPs:              strings.Join(c.AllowedIPs, "\n"),
+		BlockAllDayEvents:       c.BlockAllDayEvents,
+	}
+	for _, field := range []struct {
+		value int
+		dest  *string
+	}{
+		{c.MaxDurationMinutes, &form.MaxDurationMinutes},
+		{c.MaxAttendees, &form.MaxAttendees},
+		{c.MaxPendingRequests, &form.MaxPendingRequests},
+	} {
+		if field.value > 0 {
+			*field.dest = strconv.Itoa(field.value)
+		}
+	}
+	if c.AllowExternalAttendees != nil {
+		form.AllowExternalAttendees = strconv.FormatBool(*c.AllowExternalAttendees)
+	}
+	return form
+}
+
+// constraintsFormFromRequest reads the submitted form.
+func constraintsFormFromRequest(r *http.Request) ConstraintsForm {
+	return ConstraintsForm{
+		CalendarAllowlist:       r.FormValue("calendar_allowlist"),
+		DefaultCalendar:         strings.TrimSpace(r.FormValue("default_calendar")),
+		MaxDurationMinutes:      strings.TrimSpace(r.FormValue("max_duration_minutes")),
+		MaxAttendees:            strings.TrimSpace(r.FormValue("max_attendees")),
+		MaxPendingRequests:      strings.TrimSpace(r.FormValue("max_pending_requests")),
+		AttendeeDomainAllowlist: r.FormValue("attendee_domain_allowlist"),
+		AttendeeDomainBlocklist: r.FormValue("attendee_domain_blocklist"),
+		AllowExternalAttendees:  r.FormValue("allow_external_attendees"),
+		AllowedIPs:              r.FormValue("allowed_ips"),
+		BlockAllDayEvents:       r.FormValue("block_all_day_events") == "on",
+	}
+}
+
+// patch returns the form as a constraints merge patch. Empty inputs become
+// null, which removes the constraint.
+func (f ConstraintsForm) patch() (map[string]interface{}, error) {
+	patch := map[string]interface{}{
+		"calendar_allowlist":        formList(f.CalendarAllowlist),
+		"default_calendar":          nil,
+		"attendee_domain_allowlist": formList(f.AttendeeDomainAllowlist),
+		"attendee_domain_blocklist": formList(f.AttendeeDomainBlocklist),
+		"allowed_ips":               formList(f.AllowedIPs),
+		"allow_external_attendees":  nil,
+		"block_all_day_events":      nil,
+	}
+	if f.DefaultCalendar != "" {
+		patch["default_calendar"] = f.DefaultCalendar
+	}
+	if f.BlockAllDayEvents {
+		patch["block_all_day_events"] = true
+	}
+	switch f.AllowExternalAttendees {
+	case "":
+	case "true", "false":
+		patch["allow_external_attendees"] = f.AllowExternalAttendees == "true"
+	default:
+		return nil, fmt.Errorf("invalid external attendees choice")
+	}
+
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"max_duration_minutes", f.MaxDurationMinutes},
+		{"max_attendees", f.MaxAttendees},
+		{"max_pending_requests", f.MaxPendingRequests},
+	} {
+		patch[field.name] = nil
+		if field.value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(field.value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%s must be a whole number of at least 0", field.name)
+		}
+		if n > 0 {
+			patch[field.name] = n
+		}
+	}
+	return patch, nil
+}
+
+// formList splits a textarea into its non-empty lines, or nil when empty.
+func formList(value string) interface{} {
+	var items []string
+	for _, line := range strings.FieldsFunc(value, func(r rune) bool { return r == '\n' || r == ',' }) {
+		if line = strings.TrimSpace(line); line != "" {
+			items = append(items, line)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	return items
+}
+
+// otherConstraints returns the key's constraints the form does not edit.
+func otherConstraints(c *database.KeyConstraints) map[string]interface{} {
+	fields := apikeys.ConstraintsSnapshot(c)
+	for _, field := range constraintsFormFields {
+		delete(fields, field)
+	}
+	return fields
+}
+
+// KeyConstraints shows the form for editing an API key's constraints.
+func (h *Handler) KeyConstraints(w http.ResponseWriter, r *http.Request) {
+	key := h.loadConstraintsKey(w, r)
+	if key == nil {
+		return
+	}
+	h.renderKeyConstraints(w, r, key, newConstraintsForm(key.Constraints), "")
+}
+
+// SaveKeyConstraints applies the constraints form to an API key, recording
+// the changed fields in the audit log.
+func (h *Handler) SaveKeyConstraints(w http.ResponseWriter, r *http.Request) {
+	key := h.loadConstraintsKey(w, r)
+	if key == nil {
+		return
+	}
+	if key.RevokedAt.Valid {
+		http.Error(w, "API key is revoked", http.StatusConflict)
+		return
+	}
+
+	form := constraintsFormFromRequest(r)
+	patch, err := form.patch()
+	if err != nil {
+		h.renderKeyConstraints(w, r, key, form, err.Error())
+		return
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	updated, err := apikeys.MergeConstraints(key.Constraints, data)
+	if err == nil {
+		err = apikeys.ValidateConstraints(updated)
+	}
+	if err != nil {
+		h.renderKeyConstraints(w, r, key, form, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	changes := settings.Diff(apikeys.ConstraintsSnapshot(key.Constraints), apikeys.ConstraintsSnapshot(updated), nil)
+	if len(changes) > 0 {
+		if err := h.apiKeyRepo.UpdateConstraints(ctx, key.ID, updated); err != nil {
+			util.Error("Failed to update key constraints", "error", err, "api_key_id", key.ID)
+			h.renderKeyConstraints(w, r, key, form, "Failed to save constraints.")
+			return
+		}
+		h.auditLogger.Log(ctx, database.AuditAPIKeyUpdated, "", key.ID, "web:admin", map[string]interface{}{
+			"changes": changes,
+		})
+	}
+
+	http.Redirect(w, r, "/apikeys/"+key.ID+"/constraints?saved=1", http.StatusSeeOther)
+}
+
+// loadConstraintsKey returns the API key named in the path, or writes an
+// error response and returns nil.
+func (h *Handler) loadConstraintsKey(w http.ResponseWriter, r *http.Request) *database.APIKey {
+	key, err := h.apiKeyRepo.GetByID(r.Context(), r.PathValue("keyId"))
+	if err != nil {
+		http.Error(w, "Failed to load API key: "+err.Error(), http.StatusInternalServerError)
+		return nil
+	}
+	if key == nil {
+		http.Error(w, "API key not found", http.StatusNotFound)
+		return nil
+	}
+	return key
+}
+
+func (h *Handler) renderKeyConstraints(w http.ResponseWriter, r *http.Request, key *database.APIKey, form ConstraintsForm, errMessage string) {
+	data := map[string]interface{}{
+		"Title": "Key Constraints",
+		"Key":   key,
+		"Form":  form,
+		"Saved": r.URL.Query().Get("saved") == "1",
+		"Error": errMessage,
+	}
+	if other := otherConstraints(key.Constraints); len(other) > 0 {
+		data["OtherConstraints"] = other
+	}
+	h.render(w, r, "apikey_constraints.html", data)
+}
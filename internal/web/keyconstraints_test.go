@@ -0,0 +1,50 @@
+package web
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/database"
+)
+
+func TestConstraintsFormKeepsOtherConstraints(t *testing.T) {
+	current := &database.KeyConstraints{
+		CalendarAllowlist: []string{"primary"},
+		MaxAttendees:      10,
+		TimeoutAction:     "deny",
+	}
+
+	form := newConstraintsForm(current)
+	form.CalendarAllowlist = "primary\n  team@example.com \n"
+	form.MaxAttendees = ""
+	form.AllowExternalAttendees = "false"
+
+	patch, err := form.patch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(patch)
+	merged, err := apikeys.MergeConstraints(current, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(merged.CalendarAllowlist) != 2 || merged.CalendarAllowlist[1] != "team@example.com" {
+		t.Errorf("allowlist = %v", merged.CalendarAllowlist)
+	}
+	if merged.MaxAttendees != 0 {
+		t.Errorf("expected cleared max_attendees, got %d", merged.MaxAttendees)
+	}
+	if merged.AllowExternalAttendees == nil || *merged.AllowExternalAttendees {
+		t.Errorf("expected external attendees disallowed")
+	}
+	if merged.TimeoutAction != "deny" {
+		t.Errorf("expected constraint outside the form to be kept, got %q", merged.TimeoutAction)
+	}
+
+	form.MaxDurationMinutes = "-5"
+	if _, err := form.patch(); err == nil {
+		t.Error("expected negative duration to be rejected")
+	}
+}
@@ -33,6 +33,10 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	protected.HandleFunc("POST /requests/{requestId}/deny", h.DenyRequest)
 	protected.HandleFunc("POST /requests/{requestId}/suggest", h.SuggestChange)
 	protected.HandleFunc("POST /requests/{requestId}/update", h.UpdatePayload)
+	protected.HandleFunc("POST /requests/{requestId}/notify", h.ResendNotification)
+	protected.HandleFunc("POST /requests/{requestId}/retry", h.RetryRequest)
+	protected.HandleFunc("GET /requests/{requestId}/queue", h.QueuePosition)
+	protected.HandleFunc("POST /requests/{requestId}/approval-link", h.CreateApprovalLink)
 
 	// History
 	protected.HandleFunc("GET /history", h.History)
@@ -41,13 +45,22 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	protected.HandleFunc("GET /apikeys", h.APIKeys)
 	protected.HandleFunc("POST /apikeys", h.CreateAPIKey)
 	protected.HandleFunc("POST /apikeys/{keyId}/revoke", h.RevokeAPIKey)
+	protected.HandleFunc("GET /apikeys/{keyId}/constraints", h.KeyConstraints)
+	protected.HandleFunc("POST /apikeys/{keyId}/constraints", h.SaveKeyConstraints)
 
 	// Settings
 	protected.HandleFunc("GET /settings", h.Settings)
 	protected.HandleFunc("POST /settings/test-notification", h.TestNotification)
+	protected.HandleFunc("POST /settings/test-webhook", h.TestWebhook)
+	protected.HandleFunc("GET /settings/webhook-failures", h.WebhookFailures)
+	protected.HandleFunc("POST /settings/webhook-failures/{id}/retry", h.RetryWebhookFailure)
 	protected.HandleFunc("POST /settings/save", h.SaveSettings)
 	protected.HandleFunc("POST /settings/notifications", h.SaveNotificationSettings)
 	protected.HandleFunc("POST /settings/google-oauth", h.SaveGoogleOAuthSettings)
+	protected.HandleFunc("GET /settings/sessions", h.ListSessions)
+	protected.HandleFunc("POST /settings/sessions/revoke-others", h.RevokeOtherSessions)
+	protected.HandleFunc("POST /settings/sessions/{sessionId}/revoke", h.RevokeSession)
+	protected.HandleFunc("POST /settings/timezone", h.SetTimezone)
 	protected.HandleFunc("GET /oauth/start", h.OAuthStart)
 
 	// Apply session middleware to protected routes
@@ -66,8 +79,10 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.Handle("GET /history", protectedHandler)
 	mux.Handle("GET /apikeys", protectedHandler)
 	mux.Handle("POST /apikeys", protectedHandler)
+	mux.Handle("GET /apikeys/", protectedHandler)
 	mux.Handle("POST /apikeys/", protectedHandler)
 	mux.Handle("GET /settings", protectedHandler)
+	mux.Handle("GET /settings/", protectedHandler)
 	mux.Handle("POST /settings/", protectedHandler)
 	mux.Handle("GET /oauth/start", protectedHandler)
 }
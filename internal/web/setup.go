@@ -1,13 +1,22 @@
 package web
 
 import (
+	"context"
+	"fmt"
 	"html/template"
 	"net/http"
 
+	"github.com/dtorcivia/schedlock/internal/apikeys"
 	"github.com/dtorcivia/schedlock/internal/config"
 	schedcrypto "github.com/dtorcivia/schedlock/internal/crypto"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/engine"
+	"github.com/dtorcivia/schedlock/internal/util"
 )
 
+// initialAPIKeyName names the admin key optionally created during setup.
+const initialAPIKeyName = "Initial admin key"
+
 // SetupHandler handles the first-run setup wizard.
 type SetupHandler struct {
 	config     *config.Config
@@ -43,6 +52,7 @@ func (h *SetupHandler) SetupSubmit(w http.ResponseWriter, r *http.Request) {
 	baseURL := r.FormValue("base_url")
 	googleClientID := r.FormValue("google_client_id")
 	googleClientSecret := r.FormValue("google_client_secret")
+	createAPIKey := r.FormValue("create_api_key") == "1"
 
 	// Validation
 	if password == "" {
@@ -84,10 +94,51 @@ func (h *SetupHandler) SetupSubmit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Render success page with restart instructions
-	h.render(w, "setup_complete.html", map[string]interface{}{
+	data := map[string]interface{}{
 		"Title": "Setup Complete",
+	}
+
+	// The key is hashed with the server secret just saved to the config
+	// file, so it authenticates once the server restarts
+	if createAPIKey {
+		fullKey, err := h.createInitialAPIKey(r.Context())
+		if err != nil {
+			util.GetDefaultLogger().Error("Failed to create initial API key", "error", err)
+			data["APIKeyError"] = "The admin API key could not be created. Create one from the API Keys page after restarting."
+		} else {
+			data["APIKey"] = fullKey
+		}
+	}
+
+	// Render success page with restart instructions
+	h.render(w, "setup_complete.html", data)
+}
+
+// createInitialAPIKey stores a new admin-tier key and returns the full key,
+// which is only ever shown on the setup complete page.
+func (h *SetupHandler) createInitialAPIKey(ctx context.Context) (string, error) {
+	hasher, err := schedcrypto.NewAPIKeyHasher(h.config.Auth.SecretKey)
+	if err != nil {
+		return "", err
+	}
+
+	db, err := database.Open(h.config.Database.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	apiKey, fullKey, err := apikeys.NewRepository(db, hasher).CreateWithScopes(ctx, initialAPIKeyName, database.TierAdmin, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create key: %w", err)
+	}
+
+	engine.NewAuditLogger(db).Log(ctx, database.AuditAPIKeyCreated, "", apiKey.ID, "setup", map[string]interface{}{
+		"name":   apiKey.Name,
+		"tier":   apiKey.Tier,
+		"scopes": apiKey.Scopes,
 	})
+	return fullKey, nil
 }
 
 // RegisterRoutes registers setup wizard routes.
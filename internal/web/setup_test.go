@@ -0,0 +1,49 @@
+package web
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/dtorcivia/schedlock/internal/apikeys"
+	"github.com/dtorcivia/schedlock/internal/config"
+	schedcrypto "github.com/dtorcivia/schedlock/internal/crypto"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/engine"
+)
+
+func TestCreateInitialAPIKey(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{}
+	cfg.Auth.SecretKey = "setup-test-secret"
+	cfg.Database.Path = filepath.Join(t.TempDir(), "schedlock.db")
+	h := &SetupHandler{config: cfg}
+
+	fullKey, err := h.createInitialAPIKey(ctx)
+	if err != nil {
+		t.Fatalf("createInitialAPIKey: %v", err)
+	}
+
+	db, err := database.Open(cfg.Database.Path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	hasher, _ := schedcrypto.NewAPIKeyHasher(cfg.Auth.SecretKey)
+	key, err := apikeys.NewRepository(db, hasher).Authenticate(ctx, fullKey)
+	if err != nil {
+		t.Fatalf("initial key does not authenticate: %v", err)
+	}
+	if key.Tier != database.TierAdmin {
+		t.Errorf("tier = %q, want admin", key.Tier)
+	}
+
+	entries, err := engine.NewAuditLogger(db).GetByEventType(ctx, database.AuditAPIKeyCreated, 10)
+	if err != nil {
+		t.Fatalf("GetByEventType: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Actor.String != "setup" {
+		t.Errorf("audit entries = %+v, want one created by setup", entries)
+	}
+}
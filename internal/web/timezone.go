@@ -0,0 +1,135 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+// timezoneCookieName holds the timezone the browser reports, set by a
+// script on every page so event times can be shown in the viewer's zone.
+const timezoneCookieName = "schedlock_tz"
+
+// timezoneChoices are offered in the timezone picker. The viewer's current
+// timezone is added when it is not listed.
+var timezoneChoices = []string{
+	"UTC",
+	"America/Los_Angeles",
+	"America/Denver",
+	"America/Chicago",
+	"America/New_York",
+	"America/Sao_Paulo",
+	"Europe/London",
+	"Europe/Paris",
+	"Europe/Berlin",
+	"Africa/Johannesburg",
+	"Asia/Dubai",
+	"Asia/Kolkata",
+	"Asia/Singapore",
+	"Asia/Tokyo",
+	"Australia/Sydney",
+	"Pacific/Auckland",
+}
+
+// validTimezone reports whether tz names an IANA timezone. "Local" is
+// rejected because it means the server's zone, not the viewer's.
+func validTimezone(tz string) bool {
+	if tz == "" || tz == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(tz)
+	return err == nil
+}
+
+// viewerTimezone returns the timezone to show event times in: the one
+// chosen in the session, then a "tz" query parameter, then the one the
+// browser reported. It returns "" when none is known, meaning the display
+// timezone.
+func viewerTimezone(r *http.Request) string {
+	if session := GetSession(r.Context()); session != nil && validTimezone(session.Timezone) {
+		return session.Timezone
+	}
+	if tz := r.URL.Query().Get("tz"); validTimezone(tz) {
+		return tz
+	}
+	if cookie, err := r.Cookie(timezoneCookieName); err == nil && validTimezone(cookie.Value) {
+		return cookie.Value
+	}
+	return ""
+}
+
+// timezoneOptions returns the picker choices, including current.
+func timezoneOptions(current string) []string {
+	if current == "" {
+		return timezoneChoices
+	}
+	for _, tz := range timezoneChoices {
+		if tz == current {
+			return timezoneChoices
+		}
+	}
+	return append([]string{current}, timezoneChoices...)
+}
+
+// addTimezoneData adds the viewer's timezone and the picker choices to
+// page data. DetectTimezone asks the page to reload once the browser has
+// reported its timezone, so the first view is not in the server's zone.
+func addTimezoneData(r *http.Request, data map[string]interface{}) {
+	tz := viewerTimezone(r)
+	data["ViewerTimezone"] = tz
+	data["TimezoneOptions"] = timezoneOptions(tz)
+	data["DetectTimezone"] = tz == "" && r.Method == http.MethodGet
+}
+
+// formatTimeIn formats t with its zone abbreviation in the named timezone,
+// or in the display timezone when tz is empty or unknown.
+func formatTimeIn(t time.Time, tz string) string {
+	formatter := util.GetDefaultFormatter()
+	if formatter == nil {
+		return t.Format("Jan 2, 2006 3:04 PM MST")
+	}
+	loc := formatter.Location
+	if validTimezone(tz) {
+		loc, _ = time.LoadLocation(tz)
+	}
+	return formatter.FormatDateTimeIn(t, loc)
+}
+
+// formatEventTime formats t in the timezone it was given in, so approvers
+// see the event's own time beside their local one.
+func formatEventTime(t time.Time) string {
+	formatter := util.GetDefaultFormatter()
+	if formatter == nil {
+		return t.Format("Jan 2, 2006 3:04 PM MST")
+	}
+	return formatter.FormatLayout(t, formatter.DatetimeFormat+" MST")
+}
+
+// SetTimezone stores the timezone the signed-in approver views event times
+// in, then returns to the page the picker was on.
+func (h *Handler) SetTimezone(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	tz := r.FormValue("timezone")
+	if tz != "" && !validTimezone(tz) {
+		http.Error(w, "Unknown timezone", http.StatusBadRequest)
+		return
+	}
+	if err := h.sessionMgr.SetTimezone(r.Context(), GetSessionID(r), tz); err != nil {
+		util.Error("Failed to save session timezone", "error", err)
+		http.Error(w, "Failed to save timezone", http.StatusInternalServerError)
+		return
+	}
+
+	// Only return to local pages
+	next := r.FormValue("next")
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		next = "/dashboard"
+	}
+	http.Redirect(w, r, next, http.StatusSeeOther)
+}
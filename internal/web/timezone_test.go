@@ -0,0 +1,45 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestViewerTimezone(t *testing.T) {
+	tests := []struct {
+		name    string
+		session string
+		query   string
+		cookie  string
+		want    string
+	}{
+		{"nothing known", "", "", "", ""},
+		{"browser cookie", "", "", "Asia/Tokyo", "Asia/Tokyo"},
+		{"query beats cookie", "", "Europe/Paris", "Asia/Tokyo", "Europe/Paris"},
+		{"session beats all", "America/Chicago", "Europe/Paris", "Asia/Tokyo", "America/Chicago"},
+		{"unknown zones ignored", "Mars/Olympus", "Local", "Nowhere/Land", ""},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/approve/tok?tz="+tt.query, nil)
+		if tt.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: timezoneCookieName, Value: tt.cookie})
+		}
+		if tt.session != "" {
+			r = r.WithContext(context.WithValue(r.Context(), sessionContextKey, &Session{Timezone: tt.session}))
+		}
+		if got := viewerTimezone(r); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTimezoneOptionsIncludesCurrent(t *testing.T) {
+	if opts := timezoneOptions("America/Halifax"); opts[0] != "America/Halifax" || len(opts) != len(timezoneChoices)+1 {
+		t.Errorf("expected current timezone first, got %v", opts)
+	}
+	if opts := timezoneOptions("UTC"); len(opts) != len(timezoneChoices) {
+		t.Errorf("expected listed timezone not to be repeated, got %v", opts)
+	}
+}
@@ -0,0 +1,231 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+// auditDrainTimeout bounds the final flush when the server shuts down.
+const auditDrainTimeout = 5 * time.Second
+
+// AuditClient streams audit log entries to the audit webhook. Delivery is
+// best-effort: Publish never blocks, entries are dropped when the queue is
+// full, and a batch that fails every retry is logged and discarded. The
+// audit log table stays the source of truth.
+type AuditClient struct {
+	config     *config.AuditWebhookConfig
+	httpClient *http.Client
+	queue      chan AuditPayload
+
+	event      string          // payload event name
+	eventTypes map[string]bool // audit event types to stream; nil streams all
+}
+
+// NewAuditClient creates a client for the audit webhook.
+func NewAuditClient(cfg *config.AuditWebhookConfig) *AuditClient {
+	timeout := 30 * time.Second
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+
+	queueSize := cfg.QueueSize
+	if queueSize < 1 {
+		queueSize = config.DefaultAuditWebhookQueueSize
+	}
+
+	return &AuditClient{
+		config:     cfg,
+		httpClient: &http.Client{Timeout: timeout},
+		queue:      make(chan AuditPayload, queueSize),
+		event:      EventAuditEntry,
+	}
+}
+
+// NewSettingsClient creates a client for the settings webhook. It streams
+// only settings changes, as EventSettingsChanged payloads whose details
+// hold the redacted diff.
+func NewSettingsClient(cfg *config.AuditWebhookConfig) *AuditClient {
+	c := NewAuditClient(cfg)
+	c.event = EventSettingsChanged
+	c.eventTypes = map[string]bool{database.AuditSettingsChanged: true}
+	return c
+}
+
+// Enabled returns whether an audit webhook URL is configured.
+func (c *AuditClient) Enabled() bool {
+	return c.config.URL != ""
+}
+
+// Publish queues an entry for delivery. It implements engine.AuditSink.
+func (c *AuditClient) Publish(entry database.AuditLogEntry) {
+	if !c.Enabled() || (c.eventTypes != nil && !c.eventTypes[entry.EventType]) {
+		return
+	}
+
+	payload := AuditPayload{
+		Event:     c.event,
+		ID:        entry.ID,
+		Timestamp: entry.Timestamp.UTC().Format(time.RFC3339),
+		EventType: entry.EventType,
+		RequestID: entry.RequestID.String,
+		APIKeyID:  entry.APIKeyID.String,
+		Actor:     entry.Actor.String,
+		IPAddress: entry.IPAddress.String,
+		Details:   entry.Details,
+	}
+
+	select {
+	case c.queue <- payload:
+	default:
+		util.Warn("Audit webhook queue full, dropping entry", "audit_id", entry.ID, "event_type", entry.EventType)
+	}
+}
+
+// Start delivers queued entries until ctx is cancelled, then makes one
+// last attempt to send whatever is still queued.
+func (c *AuditClient) Start(ctx context.Context) {
+	if !c.Enabled() {
+		return
+	}
+
+	util.Info("Starting audit webhook worker")
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.drain(nil)
+			util.Info("Audit webhook worker stopping")
+			return
+		case payload := <-c.queue:
+			batch := c.collect(ctx, payload)
+			if ctx.Err() != nil {
+				// Shutting down: deliver under the drain timeout instead
+				c.drain(batch)
+				util.Info("Audit webhook worker stopping")
+				return
+			}
+			c.send(ctx, batch)
+		}
+	}
+}
+
+// collect gathers entries for one delivery. Without batching it returns
+// just first; otherwise it waits for the batch window or max size.
+func (c *AuditClient) collect(ctx context.Context, first AuditPayload) []AuditPayload {
+	batch := []AuditPayload{first}
+	if c.config.BatchWindowMs <= 0 {
+		return batch
+	}
+
+	window := time.NewTimer(time.Duration(c.config.BatchWindowMs) * time.Millisecond)
+	defer window.Stop()
+
+	for len(batch) < c.config.BatchMaxSize {
+		select {
+		case payload := <-c.queue:
+			batch = append(batch, payload)
+		case <-window.C:
+			return batch
+		case <-ctx.Done():
+			return batch
+		}
+	}
+	return batch
+}
+
+// drain sends pending and everything left in the queue on shutdown,
+// without waiting for batch windows.
+func (c *AuditClient) drain(pending []AuditPayload) {
+	ctx, cancel := context.WithTimeout(context.Background(), auditDrainTimeout)
+	defer cancel()
+
+	maxSize := c.config.BatchMaxSize
+	if c.config.BatchWindowMs <= 0 {
+		maxSize = 1
+	}
+
+	for ctx.Err() == nil {
+		batch := pending
+		pending = nil
+	fill:
+		for len(batch) < maxSize {
+			select {
+			case payload := <-c.queue:
+				batch = append(batch, payload)
+			default:
+				break fill
+			}
+		}
+		if len(batch) == 0 {
+			return
+		}
+		c.send(ctx, batch)
+	}
+}
+
+// send delivers one batch. With batching enabled the body is always a
+// JSON array; otherwise it is the single payload object.
+func (c *AuditClient) send(ctx context.Context, batch []AuditPayload) {
+	var body interface{} = batch
+	if c.config.BatchWindowMs <= 0 {
+		body = batch[0]
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		util.Error("Failed to marshal audit webhook payload", "error", err)
+		return
+	}
+
+	if err := c.deliverWithRetry(ctx, data); err != nil {
+		util.Error("Audit webhook delivery failed, dropping entries",
+			"entries", len(batch),
+			"first_audit_id", batch[0].ID,
+			"error", err,
+		)
+	}
+}
+
+// deliverWithRetry posts data, retrying with the configured backoff.
+// Waiting stops early if ctx is cancelled.
+func (c *AuditClient) deliverWithRetry(ctx context.Context, data []byte) error {
+	var lastErr error
+	maxAttempts := c.config.MaxRetries + 1
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			backoffSeconds := attempt * 2
+			if attempt-1 < len(c.config.RetryBackoff) {
+				backoffSeconds = c.config.RetryBackoff[attempt-1]
+			}
+			select {
+			case <-time.After(time.Duration(backoffSeconds) * time.Second):
+			case <-ctx.Done():
+				return fmt.Errorf("%w (after %d attempts)", lastErr, attempt)
+			}
+		}
+
+		_, err := postJSON(ctx, c.httpClient, c.config.URL, c.config.Secret, data)
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+		util.Warn("Audit webhook delivery failed",
+			"attempt", attempt+1,
+			"error", err,
+		)
+	}
+
+	return lastErr
+}
@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+func TestAuditClientBatchesSignedEntries(t *testing.T) {
+	bodies := make(chan []byte, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if got := r.Header.Get("X-SchedLock-Signature"); got != util.ComputeHMAC(data, "audit-secret") {
+			t.Errorf("signature = %q", got)
+		}
+		bodies <- data
+	}))
+	defer server.Close()
+
+	client := NewAuditClient(&config.AuditWebhookConfig{
+		URL:           server.URL,
+		Secret:        "audit-secret",
+		BatchWindowMs: 50,
+		BatchMaxSize:  10,
+		QueueSize:     10,
+	})
+	for i := int64(1); i <= 3; i++ {
+		client.Publish(database.AuditLogEntry{
+			ID:        i,
+			Timestamp: time.Now(),
+			EventType: database.AuditRequestApproved,
+			RequestID: sql.NullString{String: "req_1", Valid: true},
+			Details:   json.RawMessage(`{"ok":true}`),
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		client.Start(ctx)
+		close(done)
+	}()
+
+	select {
+	case data := <-bodies:
+		var batch []AuditPayload
+		if err := json.Unmarshal(data, &batch); err != nil {
+			t.Fatalf("expected array payload, got %s", data)
+		}
+		if len(batch) != 3 {
+			t.Fatalf("batch has %d entries, want 3", len(batch))
+		}
+		if batch[0].Event != EventAuditEntry || batch[0].ID != 1 || batch[0].RequestID != "req_1" || string(batch[0].Details) != `{"ok":true}` {
+			t.Errorf("unexpected payload: %+v", batch[0])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("audit batch was not delivered")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestAuditClientDrainsOnShutdown(t *testing.T) {
+	bodies := make(chan []byte, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies <- data
+	}))
+	defer server.Close()
+
+	client := NewAuditClient(&config.AuditWebhookConfig{URL: server.URL, BatchMaxSize: 10, QueueSize: 10})
+	client.Publish(database.AuditLogEntry{ID: 7, EventType: database.AuditLoginSuccess})
+
+	// Already cancelled: the worker only drains what is queued
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.Start(ctx)
+
+	select {
+	case data := <-bodies:
+		var payload AuditPayload
+		if err := json.Unmarshal(data, &payload); err != nil {
+			t.Fatalf("expected single payload object, got %s", data)
+		}
+		if payload.ID != 7 {
+			t.Errorf("ID = %d, want 7", payload.ID)
+		}
+	default:
+		t.Fatal("queued entry was not sent on shutdown")
+	}
+}
+
+func TestAuditClientPublishNeverBlocks(t *testing.T) {
+	client := NewAuditClient(&config.AuditWebhookConfig{URL: "http://127.0.0.1:1", BatchMaxSize: 1, QueueSize: 1})
+
+	finished := make(chan struct{})
+	go func() {
+		// No worker is running, so only the first entry fits
+		for i := int64(0); i < 5; i++ {
+			client.Publish(database.AuditLogEntry{ID: i, EventType: database.AuditLoginSuccess})
+		}
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a full queue")
+	}
+	if len(client.queue) != 1 {
+		t.Errorf("queue holds %d entries, want 1", len(client.queue))
+	}
+}
+
+func TestAuditClientDisabled(t *testing.T) {
+	client := NewAuditClient(&config.AuditWebhookConfig{QueueSize: 1})
+	client.Publish(database.AuditLogEntry{ID: 1})
+	if client.Enabled() || len(client.queue) != 0 {
+		t.Error("client without a URL should ignore entries")
+	}
+}
+
+func TestSettingsClientStreamsOnlySettingsChanges(t *testing.T) {
+	bodies := make(chan []byte, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies <- data
+	}))
+	defer server.Close()
+
+	client := NewSettingsClient(&config.AuditWebhookConfig{URL: server.URL, BatchMaxSize: 10, QueueSize: 10})
+	client.Publish(database.AuditLogEntry{ID: 1, Timestamp: time.Now(), EventType: database.AuditRequestApproved})
+	client.Publish(database.AuditLogEntry{
+		ID:        2,
+		Timestamp: time.Now(),
+		EventType: database.AuditSettingsChanged,
+		Details:   json.RawMessage(`{"changes":[{"field":"ntfy.token","old":"[redacted]","new":"[redacted]"}]}`),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		client.Start(ctx)
+		close(done)
+	}()
+
+	select {
+	case data := <-bodies:
+		var payload AuditPayload
+		if err := json.Unmarshal(data, &payload); err != nil {
+			t.Fatalf("expected object payload, got %s", data)
+		}
+		if payload.Event != EventSettingsChanged || payload.ID != 2 {
+			t.Errorf("unexpected payload: %+v", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("settings change was not delivered")
+	}
+
+	cancel()
+	<-done
+	select {
+	case data := <-bodies:
+		t.Fatalf("unexpected delivery: %s", data)
+	default:
+	}
+}
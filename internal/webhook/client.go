@@ -4,10 +4,15 @@ package webhook
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/dtorcivia/schedlock/internal/config"
@@ -22,60 +27,301 @@ type Client struct {
 	config     *config.MoltbotConfig
 	db         *database.DB
 	httpClient *http.Client
+
+	// Batching state; only used when BatchWindowMs > 0
+	batchMu    sync.Mutex
+	batch      []*queuedEvent
+	batchTimer *time.Timer
+}
+
+// queuedEvent is an event waiting for its batch to be flushed.
+type queuedEvent struct {
+	payload      WebhookPayload
+	destinations []string // limits delivery when set, see engine.WebhookEvent
+	done         chan error
 }
 
-// NewClient creates a new webhook client.
-func NewClient(cfg *config.MoltbotConfig, db *database.DB) *Client {
+// NewClient creates a new webhook client. A configured client certificate
+// or CA bundle is loaded here so a bad file fails startup, not delivery.
+func NewClient(cfg *config.MoltbotConfig, db *database.DB) (*Client, error) {
 	timeout := 30 * time.Second
 	if cfg.Webhook.TimeoutSeconds > 0 {
 		timeout = time.Duration(cfg.Webhook.TimeoutSeconds) * time.Second
 	}
+
+	// The timeout covers the whole exchange, including the TLS handshake.
+	httpClient := &http.Client{
+		Timeout: timeout,
+	}
+
+	tlsConfig, err := loadClientTLSConfig(&cfg.Webhook)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		httpClient.Transport = transport
+	}
+
 	return &Client{
-		config: cfg,
-		db:     db,
-		httpClient: &http.Client{
-			Timeout: timeout,
-		},
+		config:     cfg,
+		db:         db,
+		httpClient: httpClient,
+	}, nil
+}
+
+// loadClientTLSConfig builds the TLS config from the client certificate and
+// CA bundle, either of which may be set alone. It returns nil if neither is.
+func loadClientTLSConfig(cfg *config.WebhookConfig) (*tls.Config, error) {
+	if cfg.ClientCertFile == "" && cfg.ClientKeyFile == "" && cfg.CACertFile == "" {
+		return nil, nil
 	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if cfg.ClientCertFile != "" || cfg.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load webhook client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.CACertFile != "" {
+		caPEM, err := os.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read webhook CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in webhook CA bundle %s", cfg.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
 }
 
 // Enabled returns whether the webhook client is configured.
 func (c *Client) Enabled() bool {
-	// Backward-compatible: enable if URL is provided.
-	if c.config.Webhook.URL == "" {
-		return false
+	// Backward-compatible: enable if any URL is provided.
+	return len(c.config.Webhook.AllDestinations()) > 0
+}
+
+// destination returns the configured destination with the given name.
+func (c *Client) destination(name string) (config.WebhookDestination, bool) {
+	for _, dest := range c.config.Webhook.AllDestinations() {
+		if dest.Name == name {
+			return dest, true
+		}
+	}
+	return config.WebhookDestination{}, false
+}
+
+// eachDestination calls send for every destination in parallel, so a slow
+// or failing receiver does not hold up the others, and waits for them all.
+func (c *Client) eachDestination(send func(dest config.WebhookDestination)) {
+	var wg sync.WaitGroup
+	for _, dest := range c.config.Webhook.AllDestinations() {
+		wg.Add(1)
+		go func(dest config.WebhookDestination) {
+			defer wg.Done()
+			send(dest)
+		}(dest)
 	}
-	return true
+	wg.Wait()
 }
 
-// Deliver sends a webhook event to Moltbot.
+// Deliver sends a webhook event to every destination that wants its status,
+// or only to those of them it names. Each destination is retried and its
+// failures recorded on its own; the returned error joins the destinations
+// that failed. With batching enabled the event is queued and Deliver blocks
+// until its batch has been sent.
 func (c *Client) Deliver(ctx context.Context, event engine.WebhookEvent) error {
 	if !c.Enabled() {
 		return nil
 	}
 
+	payload := buildPayload(event)
+
+	if c.config.Webhook.BatchWindowMs > 0 {
+		return c.enqueue(ctx, payload, event.Destinations)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	var mu sync.Mutex
+	var errs []error
+	c.eachDestination(func(dest config.WebhookDestination) {
+		if !wantsEvent(dest, event.Status, event.Destinations) {
+			return
+		}
+		if err := c.deliverWithRetry(ctx, dest, data); err != nil {
+			// Log the failure for retry
+			c.logFailure(ctx, dest.Name, event.RequestID, event.Status, data, err)
+			mu.Lock()
+			errs = append(errs, fmt.Errorf("%s: %w", dest.Name, err))
+			mu.Unlock()
+			return
+		}
+		util.Info("Webhook delivered successfully",
+			"destination", dest.Name,
+			"request_id", event.RequestID,
+			"status", event.Status,
+		)
+	})
+	return errors.Join(errs...)
+}
+
+// buildPayload converts an engine event into the wire payload.
+func buildPayload(event engine.WebhookEvent) WebhookPayload {
 	payload := WebhookPayload{
 		Event:     "request.status",
 		RequestID: event.RequestID,
 		Status:    event.Status,
 		Message:   event.Message,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Sequence:  event.Sequence,
 	}
 
 	if event.Suggestion != "" {
 		payload.Suggestion = event.Suggestion
 	}
+	payload.Reason = event.Reason
 
 	if len(event.Result) > 0 {
 		payload.Result = event.Result
 	}
+	payload.Diff = event.Diff
 
-	data, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	return payload
+}
+
+// wantsEvent reports whether dest receives an event for status: it must
+// want the status and, when the event names its destinations, be one of them.
+func wantsEvent(dest config.WebhookDestination, status string, destinations []string) bool {
+	if !dest.Wants(status) {
+		return false
+	}
+	if len(destinations) == 0 {
+		return true
+	}
+	for _, name := range destinations {
+		if name == dest.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueue adds a payload to the current batch and waits for the flush result.
+func (c *Client) enqueue(ctx context.Context, payload WebhookPayload, destinations []string) error {
+	queued := &queuedEvent{payload: payload, destinations: destinations, done: make(chan error, 1)}
+
+	c.batchMu.Lock()
+	c.batch = append(c.batch, queued)
+	if len(c.batch) >= c.config.Webhook.BatchMaxSize {
+		batch := c.takeBatchLocked()
+		c.batchMu.Unlock()
+		go c.sendBatch(batch)
+	} else {
+		if c.batchTimer == nil {
+			window := time.Duration(c.config.Webhook.BatchWindowMs) * time.Millisecond
+			c.batchTimer = time.AfterFunc(window, c.flushBatch)
+		}
+		c.batchMu.Unlock()
+	}
+
+	select {
+	case err := <-queued.done:
+		return err
+	case <-ctx.Done():
+		// The event stays queued and is still delivered with its batch
+		return ctx.Err()
+	}
+}
+
+// flushBatch sends whatever is queued when the batch window closes.
+func (c *Client) flushBatch() {
+	c.batchMu.Lock()
+	batch := c.takeBatchLocked()
+	c.batchMu.Unlock()
+
+	if len(batch) > 0 {
+		c.sendBatch(batch)
 	}
+}
 
-	// Try to deliver with retries
+// takeBatchLocked detaches the queued events and stops the window timer.
+// The caller must hold batchMu.
+func (c *Client) takeBatchLocked() []*queuedEvent {
+	batch := c.batch
+	c.batch = nil
+	if c.batchTimer != nil {
+		c.batchTimer.Stop()
+		c.batchTimer = nil
+	}
+	return batch
+}
+
+// sendBatch delivers queued events to each destination as a single JSON
+// array holding the events that destination wants. If a destination's batch
+// cannot be delivered, each of its events is recorded in webhook_failures on
+// its own so the retry worker redelivers them individually.
+func (c *Client) sendBatch(batch []*queuedEvent) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	errs := make([][]error, len(batch))
+	c.eachDestination(func(dest config.WebhookDestination) {
+		var included []int
+		var payloads []WebhookPayload
+		for i, queued := range batch {
+			if wantsEvent(dest, queued.payload.Status, queued.destinations) {
+				included = append(included, i)
+				payloads = append(payloads, queued.payload)
+			}
+		}
+		if len(payloads) == 0 {
+			return
+		}
+
+		data, err := json.Marshal(payloads)
+		if err != nil {
+			err = fmt.Errorf("failed to marshal webhook batch: %w", err)
+		} else {
+			err = c.deliverWithRetry(ctx, dest, data)
+		}
+		if err == nil {
+			util.Info("Webhook batch delivered successfully", "destination", dest.Name, "events", len(payloads))
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, i := range included {
+			errs[i] = append(errs[i], fmt.Errorf("%s: %w", dest.Name, err))
+			single, marshalErr := json.Marshal(batch[i].payload)
+			if marshalErr != nil {
+				continue
+			}
+			c.logFailure(ctx, dest.Name, batch[i].payload.RequestID, batch[i].payload.Status, single, err)
+		}
+	})
+
+	for i, queued := range batch {
+		queued.done <- errors.Join(errs[i]...)
+	}
+}
+
+// deliverWithRetry posts data to dest, retrying with the configured backoff.
+func (c *Client) deliverWithRetry(ctx context.Context, dest config.WebhookDestination, data []byte) error {
 	var lastErr error
 	maxAttempts := c.config.Webhook.MaxRetries + 1
 	if maxAttempts < 1 {
@@ -91,70 +337,165 @@ func (c *Client) Deliver(ctx context.Context, event engine.WebhookEvent) error {
 			time.Sleep(time.Duration(backoffSeconds) * time.Second)
 		}
 
-		err := c.doDelivery(ctx, data)
+		err := c.doDelivery(ctx, dest, data)
 		if err == nil {
-			util.Info("Webhook delivered successfully",
-				"request_id", event.RequestID,
-				"status", event.Status,
-			)
 			return nil
 		}
 
 		lastErr = err
 		util.Warn("Webhook delivery failed",
+			"destination", dest.Name,
 			"attempt", attempt+1,
 			"error", err,
 		)
 	}
 
-	// Log the failure for retry
-	c.logFailure(ctx, event.RequestID, event.Status, data, lastErr)
-
 	return lastErr
 }
 
+// PingStatus is the status carried by synthetic test events.
+const PingStatus = "test"
+
+// ErrNotConfigured is returned by Ping when no webhook URL is set.
+var ErrNotConfigured = errors.New("moltbot webhook is not configured")
+
+// PingResult reports the outcome of a test delivery. With several
+// destinations it summarizes them: it succeeds only when every destination
+// did, the status, latency and error come from the first that failed, and
+// Destinations holds each result.
+type PingResult struct {
+	Destination  string       `json:"destination,omitempty"`
+	Success      bool         `json:"success"`
+	StatusCode   int          `json:"status_code,omitempty"`
+	LatencyMs    int64        `json:"latency_ms"`
+	Error        string       `json:"error,omitempty"`
+	Destinations []PingResult `json:"destinations,omitempty"`
+}
+
+// Ping sends a single synthetic event with status "test" to every
+// destination so operators can check connectivity, TLS and signing. It
+// ignores status filters and never retries or records failures.
+func (c *Client) Ping(ctx context.Context) (*PingResult, error) {
+	if !c.Enabled() {
+		return nil, ErrNotConfigured
+	}
+
+	payload := buildPayload(engine.WebhookEvent{
+		RequestID: "test",
+		Status:    PingStatus,
+		Message:   "SchedLock webhook test. No action is required.",
+	})
+
+	// Match the shape receivers get in production
+	var body interface{} = payload
+	if c.config.Webhook.BatchWindowMs > 0 {
+		body = []WebhookPayload{payload}
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	destinations := c.config.Webhook.AllDestinations()
+	results := make([]PingResult, len(destinations))
+	var wg sync.WaitGroup
+	for i, dest := range destinations {
+		wg.Add(1)
+		go func(i int, dest config.WebhookDestination) {
+			defer wg.Done()
+			start := time.Now()
+			statusCode, err := c.post(ctx, dest, data)
+			results[i] = PingResult{
+				Destination: dest.Name,
+				Success:     err == nil,
+				StatusCode:  statusCode,
+				LatencyMs:   time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+			util.Info("Webhook test delivered",
+				"destination", dest.Name,
+				"success", results[i].Success,
+				"status_code", results[i].StatusCode,
+				"latency_ms", results[i].LatencyMs,
+			)
+		}(i, dest)
+	}
+	wg.Wait()
+
+	if len(results) == 1 {
+		return &results[0], nil
+	}
+	summary := results[0]
+	for _, result := range results {
+		if !result.Success {
+			summary = result
+			summary.Error = result.Destination + ": " + result.Error
+			break
+		}
+	}
+	summary.Destination = ""
+	summary.Destinations = results
+	return &summary, nil
+}
+
 // doDelivery performs the actual HTTP request.
-func (c *Client) doDelivery(ctx context.Context, data []byte) error {
-	req, err := http.NewRequestWithContext(ctx, "POST", c.config.Webhook.URL, bytes.NewReader(data))
+func (c *Client) doDelivery(ctx context.Context, dest config.WebhookDestination, data []byte) error {
+	_, err := c.post(ctx, dest, data)
+	return err
+}
+
+// post sends data to the destination's URL and returns the HTTP status
+// code, or zero if no response was received.
+func (c *Client) post(ctx context.Context, dest config.WebhookDestination, data []byte) (int, error) {
+	return postJSON(ctx, c.httpClient, dest.URL, dest.Token, data)
+}
+
+// postJSON posts data, signing it with secret when one is set, and returns
+// the HTTP status code, or zero if no response was received.
+func postJSON(ctx context.Context, httpClient *http.Client, url, secret string, data []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "SchedLock/1.0")
 
 	// Add authentication header if configured
-	if c.config.Webhook.Token != "" {
-		signature := util.ComputeHMAC(data, c.config.Webhook.Token)
+	if secret != "" {
+		signature := util.ComputeHMAC(data, secret)
 		req.Header.Set("X-SchedLock-Signature", signature)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return 0, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, string(body))
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	return nil
+	return resp.StatusCode, nil
 }
 
-// logFailure records a failed webhook delivery for later retry.
-func (c *Client) logFailure(ctx context.Context, requestID, status string, payload []byte, err error) {
+// logFailure records a failed delivery to a destination for later retry.
+func (c *Client) logFailure(ctx context.Context, destination, requestID, status string, payload []byte, err error) {
 	webhookID, idErr := crypto.GenerateWebhookID()
 	if idErr != nil {
 		webhookID = fmt.Sprintf("whk_%d", time.Now().UnixNano())
 	}
 
 	_, dbErr := c.db.ExecContext(ctx, `
-		INSERT INTO webhook_failures (webhook_id, request_id, status, payload, error, attempts)
-		VALUES (?, ?, ?, ?, ?, 1)
-	`, webhookID, requestID, status, string(payload), err.Error())
+		INSERT INTO webhook_failures (webhook_id, destination, request_id, status, payload, error, attempts)
+		VALUES (?, ?, ?, ?, ?, ?, 1)
+	`, webhookID, destination, requestID, status, string(payload), err.Error())
 
 	if dbErr != nil {
 		util.Error("Failed to log webhook failure", "error", dbErr)
@@ -164,7 +505,7 @@ func (c *Client) logFailure(ctx context.Context, requestID, status string, paylo
 // RetryFailures attempts to redeliver failed webhooks.
 func (c *Client) RetryFailures(ctx context.Context) {
 	rows, err := c.db.QueryContext(ctx, `
-		SELECT id, webhook_id, request_id, status, payload, attempts
+		SELECT id, webhook_id, destination, request_id, status, payload, attempts
 		FROM webhook_failures
 		WHERE resolved_at IS NULL
 		AND attempts < ?
@@ -180,24 +521,25 @@ func (c *Client) RetryFailures(ctx context.Context) {
 
 	for rows.Next() {
 		var (
-			id        int64
-			webhookID string
-			requestID string
-			status    string
-			payload   string
-			attempts  int
+			id          int64
+			webhookID   string
+			destination string
+			requestID   string
+			status      string
+			payload     string
+			attempts    int
 		)
 
-		if err := rows.Scan(&id, &webhookID, &requestID, &status, &payload, &attempts); err != nil {
+		if err := rows.Scan(&id, &webhookID, &destination, &requestID, &status, &payload, &attempts); err != nil {
 			continue
 		}
 
 		// Try to deliver
-		err := c.doDelivery(ctx, []byte(payload))
+		err := c.redeliver(ctx, destination, []byte(payload))
 		if err == nil {
 			// Success - mark resolved
 			c.db.ExecContext(ctx, `UPDATE webhook_failures SET resolved_at = datetime('now') WHERE id = ?`, id)
-			util.Info("Webhook retry succeeded", "request_id", requestID, "webhook_id", webhookID)
+			util.Info("Webhook retry succeeded", "destination", destination, "request_id", requestID, "webhook_id", webhookID)
 		} else {
 			// Increment attempts
 			c.db.ExecContext(ctx, `
@@ -206,6 +548,7 @@ func (c *Client) RetryFailures(ctx context.Context) {
 				WHERE id = ?
 			`, id)
 			util.Warn("Webhook retry failed",
+				"destination", destination,
 				"request_id", requestID,
 				"attempts", attempts+1,
 				"error", err,
@@ -214,6 +557,25 @@ func (c *Client) RetryFailures(ctx context.Context) {
 	}
 }
 
+// redeliver sends a stored payload to the destination it failed for. A
+// destination that was removed from the config counts as a failed attempt.
+// Failures are stored as single events, so with batching enabled the event
+// is wrapped in a one-element array to match live deliveries.
+func (c *Client) redeliver(ctx context.Context, destination string, payload []byte) error {
+	dest, ok := c.destination(destination)
+	if !ok {
+		return fmt.Errorf("webhook destination %q is no longer configured", destination)
+	}
+	if c.config.Webhook.BatchWindowMs > 0 {
+		data, err := json.Marshal([]json.RawMessage{payload})
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhook batch: %w", err)
+		}
+		payload = data
+	}
+	return c.doDelivery(ctx, dest, payload)
+}
+
 // StartRetryWorker starts a background worker for retrying failed webhooks.
 func (c *Client) StartRetryWorker(ctx context.Context) {
 	if !c.Enabled() {
@@ -0,0 +1,564 @@
+package webhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/engine"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+func newBatchTestClient(t *testing.T, url string, maxSize int) (*Client, *database.DB) {
+	t.Helper()
+
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	client, err := NewClient(&config.MoltbotConfig{Webhook: config.WebhookConfig{
+		URL:           url,
+		BatchWindowMs: 50,
+		BatchMaxSize:  maxSize,
+	}}, db)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client, db
+}
+
+func deliverConcurrently(client *Client, ids ...string) []error {
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			errs[i] = client.Deliver(context.Background(), engine.WebhookEvent{RequestID: id, Status: "approved"})
+		}(i, id)
+	}
+	wg.Wait()
+	return errs
+}
+
+func TestDeliverBatchesEvents(t *testing.T) {
+	var mu sync.Mutex
+	var bodies [][]WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var batch []WebhookPayload
+		if err := json.Unmarshal(data, &batch); err != nil {
+			t.Errorf("expected array payload, got %s", data)
+		}
+		mu.Lock()
+		bodies = append(bodies, batch)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client, _ := newBatchTestClient(t, server.URL, 2)
+
+	for _, err := range deliverConcurrently(client, "req_1", "req_2", "req_3") {
+		if err != nil {
+			t.Fatalf("Deliver() error = %v", err)
+		}
+	}
+
+	// Max size 2 flushes one full batch early and the remainder on the window
+	total := 0
+	for _, batch := range bodies {
+		if len(batch) > 2 {
+			t.Errorf("batch of %d exceeds max size", len(batch))
+		}
+		total += len(batch)
+	}
+	if len(bodies) != 2 || total != 3 {
+		t.Errorf("expected 3 events in 2 requests, got %d events in %d requests", total, len(bodies))
+	}
+}
+
+func TestDeliverBatchFailureRecordsEachEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, db := newBatchTestClient(t, server.URL, 10)
+
+	for _, err := range deliverConcurrently(client, "req_1", "req_2") {
+		if err == nil {
+			t.Fatal("expected Deliver() to report the batch failure")
+		}
+	}
+
+	rows, err := db.Query(`SELECT request_id, payload FROM webhook_failures ORDER BY request_id`)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	defer rows.Close()
+
+	var requestIDs []string
+	for rows.Next() {
+		var requestID, payload string
+		if err := rows.Scan(&requestID, &payload); err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		// Failures are stored as single events so retries are per-event
+		var single WebhookPayload
+		if err := json.Unmarshal([]byte(payload), &single); err != nil || single.RequestID != requestID {
+			t.Errorf("expected single-event payload for %s, got %s", requestID, payload)
+		}
+		requestIDs = append(requestIDs, requestID)
+	}
+	if len(requestIDs) != 2 || requestIDs[0] != "req_1" || requestIDs[1] != "req_2" {
+		t.Errorf("expected failures for req_1 and req_2, got %v", requestIDs)
+	}
+}
+
+func TestPingSendsSignedTestEvent(t *testing.T) {
+	var gotPayload WebhookPayload
+	var gotSignature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &gotPayload)
+		if r.Header.Get("X-SchedLock-Signature") == util.ComputeHMAC(data, "secret") {
+			gotSignature = "valid"
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	client, err := NewClient(&config.MoltbotConfig{Webhook: config.WebhookConfig{
+		URL:   server.URL,
+		Token: "secret",
+	}}, db)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	result, err := client.Ping(context.Background())
+	if err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if !result.Success || result.StatusCode != http.StatusNoContent {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if gotPayload.Status != PingStatus {
+		t.Errorf("status = %q, want %q", gotPayload.Status, PingStatus)
+	}
+	if gotSignature != "valid" {
+		t.Error("expected a valid signature header")
+	}
+}
+
+func TestPingReportsFailureWithoutLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client, db := newBatchTestClient(t, server.URL, 10)
+
+	result, err := client.Ping(context.Background())
+	if err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if result.Success || result.StatusCode != http.StatusUnauthorized || result.Error == "" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	var failures int
+	if err := db.QueryRow("SELECT COUNT(*) FROM webhook_failures").Scan(&failures); err != nil {
+		t.Fatalf("count failures: %v", err)
+	}
+	if failures != 0 {
+		t.Errorf("expected no logged failures, got %d", failures)
+	}
+}
+
+func TestPingNotConfigured(t *testing.T) {
+	client, _ := newBatchTestClient(t, "", 10)
+	if _, err := client.Ping(context.Background()); err != ErrNotConfigured {
+		t.Fatalf("Ping() error = %v, want ErrNotConfigured", err)
+	}
+}
+
+// recordingServer counts the request IDs it receives, answering with status.
+type recordingServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	received []string
+}
+
+func newRecordingServer(t *testing.T, status int) *recordingServer {
+	t.Helper()
+	rs := &recordingServer{}
+	rs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var batch []WebhookPayload
+		if json.Unmarshal(data, &batch) != nil {
+			var single WebhookPayload
+			json.Unmarshal(data, &single)
+			batch = []WebhookPayload{single}
+		}
+		rs.mu.Lock()
+		for _, payload := range batch {
+			rs.received = append(rs.received, payload.RequestID+":"+payload.Status)
+		}
+		rs.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(rs.Close)
+	return rs
+}
+
+func (rs *recordingServer) got() []string {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return append([]string(nil), rs.received...)
+}
+
+func TestDeliverFansOutByDestination(t *testing.T) {
+	primary := newRecordingServer(t, http.StatusOK)
+	audit := newRecordingServer(t, http.StatusOK)
+	broken := newRecordingServer(t, http.StatusBadGateway)
+
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	client, err := NewClient(&config.MoltbotConfig{Webhook: config.WebhookConfig{
+		URL:      primary.URL,
+		NotifyOn: []string{"approved", "completed"},
+		Destinations: []config.WebhookDestination{
+			{Name: "audit", URL: audit.URL, NotifyOn: []string{"completed", "failed"}},
+			{Name: "broken", URL: broken.URL},
+		},
+	}}, db)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if err := client.Deliver(ctx, engine.WebhookEvent{RequestID: "req_1", Status: "approved"}); err == nil {
+		t.Fatal("expected the broken destination's error")
+	}
+	// A status only the audit destination wants reaches no one else
+	if err := client.Deliver(ctx, engine.WebhookEvent{RequestID: "req_1", Status: "failed"}); err != nil {
+		t.Fatalf("Deliver(failed) error = %v", err)
+	}
+
+	if got := primary.got(); len(got) != 1 || got[0] != "req_1:approved" {
+		t.Errorf("primary received %v", got)
+	}
+	if got := audit.got(); len(got) != 1 || got[0] != "req_1:failed" {
+		t.Errorf("audit received %v", got)
+	}
+	// Without its own filter, broken inherits the default one
+	if got := broken.got(); len(got) != 1 || got[0] != "req_1:approved" {
+		t.Errorf("broken received %v", got)
+	}
+
+	failures, _, err := client.ListFailures(ctx, FailureListOptions{})
+	if err != nil {
+		t.Fatalf("ListFailures() error = %v", err)
+	}
+	if len(failures) != 1 || failures[0].Destination != "broken" || failures[0].Status != "approved" {
+		t.Fatalf("failures = %+v, want one for broken", failures)
+	}
+
+	// Retries only go to the destination that failed
+	if _, err := client.RetryFailure(ctx, failures[0].ID); err == nil {
+		t.Fatal("expected the retry to fail again")
+	}
+	if got := primary.got(); len(got) != 1 {
+		t.Errorf("retry reached the primary destination: %v", got)
+	}
+	if got := broken.got(); len(got) != 2 {
+		t.Errorf("broken received %v, want the retry", got)
+	}
+}
+
+func TestDeliverBatchFiltersPerDestination(t *testing.T) {
+	primary := newRecordingServer(t, http.StatusOK)
+	results := newRecordingServer(t, http.StatusOK)
+
+	client, _ := newBatchTestClient(t, primary.URL, 10)
+	client.config.Webhook.Destinations = []config.WebhookDestination{
+		{Name: "results", URL: results.URL, NotifyOn: []string{"completed"}},
+	}
+
+	var wg sync.WaitGroup
+	for _, status := range []string{"approved", "completed"} {
+		wg.Add(1)
+		go func(status string) {
+			defer wg.Done()
+			if err := client.Deliver(context.Background(), engine.WebhookEvent{RequestID: "req_1", Status: status}); err != nil {
+				t.Errorf("Deliver(%s) error = %v", status, err)
+			}
+		}(status)
+	}
+	wg.Wait()
+
+	if got := primary.got(); len(got) != 2 {
+		t.Errorf("primary received %v, want both events", got)
+	}
+	if got := results.got(); len(got) != 1 || got[0] != "req_1:completed" {
+		t.Errorf("results received %v, want only the completed event", got)
+	}
+}
+
+func TestDeliverOnlyToNamedDestinations(t *testing.T) {
+	primary := newRecordingServer(t, http.StatusOK)
+	approvals := newRecordingServer(t, http.StatusOK)
+
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	client, err := NewClient(&config.MoltbotConfig{Webhook: config.WebhookConfig{
+		URL: primary.URL,
+		Destinations: []config.WebhookDestination{
+			{Name: "approvals", URL: approvals.URL, NotifyOn: []string{"approved"}},
+		},
+	}}, db)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	event := engine.WebhookEvent{RequestID: "req_1", Status: "approved", Destinations: []string{"approvals"}}
+	if err := client.Deliver(context.Background(), event); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if got := primary.got(); len(got) != 0 {
+		t.Errorf("primary received %v, want nothing", got)
+	}
+	if got := approvals.got(); len(got) != 1 || got[0] != "req_1:approved" {
+		t.Errorf("approvals received %v", got)
+	}
+
+	// Naming a destination does not override its filter
+	event = engine.WebhookEvent{RequestID: "req_1", Status: "completed", Destinations: []string{"approvals"}}
+	if err := client.Deliver(context.Background(), event); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if got := approvals.got(); len(got) != 1 {
+		t.Errorf("approvals received %v, want only the approved event", got)
+	}
+}
+
+func TestRetryFailuresWrapsBatchedEvent(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(data))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client, db := newBatchTestClient(t, server.URL, 10)
+	client.config.Webhook.MaxRetries = 3
+	ctx := context.Background()
+	dest := client.config.Webhook.AllDestinations()[0]
+	client.logFailure(ctx, dest.Name, "req_1", "approved", []byte(`{"event":"request.status","request_id":"req_1","status":"approved"}`), errors.New("timeout"))
+
+	client.RetryFailures(ctx)
+
+	if len(bodies) != 1 {
+		t.Fatalf("expected one redelivery, got %d", len(bodies))
+	}
+	var batch []WebhookPayload
+	if err := json.Unmarshal([]byte(bodies[0]), &batch); err != nil || len(batch) != 1 || batch[0].RequestID != "req_1" {
+		t.Errorf("expected a one-event array like live batches, got %s", bodies[0])
+	}
+	var resolved int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM webhook_failures WHERE resolved_at IS NOT NULL`).Scan(&resolved); err != nil || resolved != 1 {
+		t.Errorf("resolved failures = %d (%v), want 1", resolved, err)
+	}
+}
+
+func TestRetryFailureRemovedDestination(t *testing.T) {
+	client, _ := newBatchTestClient(t, "http://127.0.0.1:1", 1)
+	ctx := context.Background()
+	client.logFailure(ctx, "gone", "req_1", "approved", []byte(`{"request_id":"req_1"}`), errors.New("timeout"))
+
+	failures, _, err := client.ListFailures(ctx, FailureListOptions{})
+	if err != nil || len(failures) != 1 {
+		t.Fatalf("ListFailures() = %v, %v", failures, err)
+	}
+	failure, err := client.RetryFailure(ctx, failures[0].ID)
+	if err == nil || !strings.Contains(err.Error(), "no longer configured") {
+		t.Fatalf("RetryFailure() error = %v, want a missing destination error", err)
+	}
+	if failure.Attempts != 2 {
+		t.Errorf("attempts = %d, want the retry counted", failure.Attempts)
+	}
+}
+
+func TestPingEveryDestination(t *testing.T) {
+	ok := newRecordingServer(t, http.StatusOK)
+	down := newRecordingServer(t, http.StatusServiceUnavailable)
+
+	client, _ := newBatchTestClient(t, ok.URL, 10)
+	client.config.Webhook.Destinations = []config.WebhookDestination{
+		{Name: "down", URL: down.URL, NotifyOn: []string{"completed"}},
+	}
+
+	result, err := client.Ping(context.Background())
+	if err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if result.Success || result.StatusCode != http.StatusServiceUnavailable || !strings.HasPrefix(result.Error, "down: ") {
+		t.Fatalf("summary = %+v, want the down destination's failure", result)
+	}
+	if len(result.Destinations) != 2 || !result.Destinations[0].Success || result.Destinations[1].Destination != "down" {
+		t.Errorf("destinations = %+v", result.Destinations)
+	}
+	// Test events ignore status filters
+	if len(down.got()) != 1 {
+		t.Errorf("down received %v, want the test event", down.got())
+	}
+}
+
+// writeClientCert writes a self-signed client certificate and its key as
+// PEM files and returns their paths.
+func writeClientCert(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "schedlock"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "client.pem")
+	keyFile = filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestLoadClientTLSConfigClientCert(t *testing.T) {
+	certFile, keyFile := writeClientCert(t)
+
+	tlsConfig, err := loadClientTLSConfig(&config.WebhookConfig{ClientCertFile: certFile, ClientKeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("loadClientTLSConfig() error = %v", err)
+	}
+	if tlsConfig == nil || len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("tlsConfig = %+v, want one client certificate", tlsConfig)
+	}
+	if tlsConfig.RootCAs != nil {
+		t.Error("RootCAs set without a CA bundle, want the system roots")
+	}
+}
+
+func TestLoadClientTLSConfigBadPaths(t *testing.T) {
+	certFile, keyFile := writeClientCert(t)
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+
+	for name, cfg := range map[string]config.WebhookConfig{
+		"missing cert":      {ClientCertFile: missing, ClientKeyFile: keyFile},
+		"missing key":       {ClientCertFile: certFile, ClientKeyFile: missing},
+		"missing CA":        {CACertFile: missing},
+		"CA without certs":  {CACertFile: keyFile},
+		"cert with bad CA":  {ClientCertFile: certFile, ClientKeyFile: keyFile, CACertFile: missing},
+		"key is not a cert": {ClientCertFile: keyFile, ClientKeyFile: keyFile},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewClient(&config.MoltbotConfig{Webhook: cfg}, nil); err == nil {
+				t.Fatal("expected NewClient to fail")
+			}
+		})
+	}
+}
+
+func TestCACertWithoutClientCert(t *testing.T) {
+	received := make(chan struct{}, 1)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatalf("write CA: %v", err)
+	}
+
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	client, err := NewClient(&config.MoltbotConfig{Webhook: config.WebhookConfig{
+		URL:        server.URL,
+		CACertFile: caFile,
+		MaxRetries: 1,
+	}}, db)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	// The test server's certificate is only trusted through the CA bundle
+	if err := client.Deliver(context.Background(), engine.WebhookEvent{RequestID: "req_1", Status: "approved"}); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	select {
+	case <-received:
+	default:
+		t.Fatal("the receiver got no request")
+	}
+}
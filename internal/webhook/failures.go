@@ -0,0 +1,183 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+// Errors returned by RetryFailure.
+var (
+	ErrFailureNotFound = errors.New("webhook failure not found")
+	ErrFailureResolved = errors.New("webhook failure is already resolved")
+)
+
+// Failure is a recorded webhook delivery that failed all its attempts.
+type Failure struct {
+	ID          int64           `json:"id"`
+	WebhookID   string          `json:"webhook_id"`
+	Destination string          `json:"destination"`
+	RequestID   string          `json:"request_id"`
+	Status      string          `json:"status"`
+	Payload     json.RawMessage `json:"payload"`
+	Error       string          `json:"error,omitempty"`
+	Attempts    int             `json:"attempts"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ResolvedAt  *time.Time      `json:"resolved_at,omitempty"`
+}
+
+// States accepted by FailureListOptions.State.
+const (
+	FailuresUnresolved = "unresolved"
+	FailuresResolved   = "resolved"
+	FailuresAll        = "all"
+)
+
+// FailureListOptions filters and paginates webhook failure listings.
+type FailureListOptions struct {
+	State     string // FailuresUnresolved, FailuresResolved or FailuresAll; empty means all
+	RequestID string // Optional: exact request ID
+	Limit     int    // 0 = no limit
+	Offset    int
+}
+
+// ListFailures returns failures matching the options, newest first, along
+// with the total number of matches before pagination.
+func (c *Client) ListFailures(ctx context.Context, opts FailureListOptions) ([]Failure, int, error) {
+	var conditions []string
+	var args []interface{}
+	switch opts.State {
+	case FailuresUnresolved:
+		conditions = append(conditions, "resolved_at IS NULL")
+	case FailuresResolved:
+		conditions = append(conditions, "resolved_at IS NOT NULL")
+	case "", FailuresAll:
+	default:
+		return nil, 0, fmt.Errorf("invalid state %q", opts.State)
+	}
+	if opts.RequestID != "" {
+		conditions = append(conditions, "request_id = ?")
+		args = append(args, opts.RequestID)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := c.db.Reader().QueryRowContext(ctx, "SELECT COUNT(*) FROM webhook_failures"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("database error: %w", err)
+	}
+
+	query := `
+		SELECT id, webhook_id, destination, request_id, status, payload, error, attempts, created_at, resolved_at
+		FROM webhook_failures
+	` + where + " ORDER BY id DESC"
+	if opts.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, opts.Limit, opts.Offset)
+	}
+
+	rows, err := c.db.Reader().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("database error: %w", err)
+	}
+	defer rows.Close()
+
+	failures := []Failure{}
+	for rows.Next() {
+		failure, err := scanFailure(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		failures = append(failures, *failure)
+	}
+	return failures, total, rows.Err()
+}
+
+// RetryFailure redelivers one failed webhook now. On success the failure is
+// marked resolved; otherwise its attempt count and error are updated and the
+// delivery error is returned alongside the updated failure.
+func (c *Client) RetryFailure(ctx context.Context, id int64) (*Failure, error) {
+	failure, err := c.getFailure(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if failure.ResolvedAt != nil {
+		return failure, ErrFailureResolved
+	}
+	if !c.Enabled() {
+		return failure, ErrNotConfigured
+	}
+
+	deliveryErr := c.redeliver(ctx, failure.Destination, failure.Payload)
+	if deliveryErr == nil {
+		_, err = c.db.ExecContext(ctx, `UPDATE webhook_failures SET resolved_at = datetime('now') WHERE id = ?`, id)
+		util.Info("Manual webhook retry succeeded", "destination", failure.Destination, "request_id", failure.RequestID, "webhook_id", failure.WebhookID)
+	} else {
+		_, err = c.db.ExecContext(ctx, `
+			UPDATE webhook_failures
+			SET attempts = attempts + 1, error = ?
+			WHERE id = ?
+		`, deliveryErr.Error(), id)
+		util.Warn("Manual webhook retry failed", "destination", failure.Destination, "request_id", failure.RequestID, "webhook_id", failure.WebhookID, "error", deliveryErr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	updated, err := c.getFailure(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return updated, deliveryErr
+}
+
+func (c *Client) getFailure(ctx context.Context, id int64) (*Failure, error) {
+	row := c.db.QueryRowContext(ctx, `
+		SELECT id, webhook_id, destination, request_id, status, payload, error, attempts, created_at, resolved_at
+		FROM webhook_failures
+		WHERE id = ?
+	`, id)
+	failure, err := scanFailure(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrFailureNotFound
+	}
+	return failure, err
+}
+
+func scanFailure(row interface{ Scan(...interface{}) error }) (*Failure, error) {
+	var (
+		failure    Failure
+		payload    string
+		errText    sql.NullString
+		createdAt  sql.NullString
+		resolvedAt sql.NullString
+	)
+	if err := row.Scan(&failure.ID, &failure.WebhookID, &failure.Destination, &failure.RequestID, &failure.Status,
+		&payload, &errText, &failure.Attempts, &createdAt, &resolvedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	failure.Payload = json.RawMessage(payload)
+	failure.Error = errText.String
+	if createdAt.Valid {
+		failure.CreatedAt, _ = util.ParseSQLiteTimestamp(createdAt.String)
+	}
+	if resolvedAt.Valid {
+		if t, err := util.ParseSQLiteTimestamp(resolvedAt.String); err == nil {
+			failure.ResolvedAt = &t
+		}
+	}
+	return &failure, nil
+}
@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+)
+
+func TestListFailuresFiltersAndPaginates(t *testing.T) {
+	client, db := newBatchTestClient(t, "http://127.0.0.1:1", 1)
+	ctx := context.Background()
+
+	for _, id := range []string{"req_1", "req_2", "req_2", "req_3"} {
+		client.logFailure(ctx, config.DefaultWebhookDestination, id, "approved", []byte(`{"request_id":"`+id+`"}`), errors.New("connection refused"))
+	}
+	if _, err := db.Exec(`UPDATE webhook_failures SET resolved_at = datetime('now') WHERE request_id = 'req_3'`); err != nil {
+		t.Fatalf("resolve failure: %v", err)
+	}
+
+	unresolved, total, err := client.ListFailures(ctx, FailureListOptions{State: FailuresUnresolved})
+	if err != nil {
+		t.Fatalf("ListFailures() error = %v", err)
+	}
+	if total != 3 || len(unresolved) != 3 {
+		t.Fatalf("unresolved: got %d of %d, want 3 of 3", len(unresolved), total)
+	}
+	if unresolved[0].ID < unresolved[1].ID {
+		t.Errorf("expected newest first, got ids %d, %d", unresolved[0].ID, unresolved[1].ID)
+	}
+
+	resolved, total, err := client.ListFailures(ctx, FailureListOptions{State: FailuresResolved})
+	if err != nil {
+		t.Fatalf("ListFailures() error = %v", err)
+	}
+	if total != 1 || resolved[0].RequestID != "req_3" || resolved[0].ResolvedAt == nil {
+		t.Errorf("resolved: got %+v (total %d)", resolved, total)
+	}
+
+	page, total, err := client.ListFailures(ctx, FailureListOptions{State: FailuresAll, RequestID: "req_2", Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("ListFailures() error = %v", err)
+	}
+	if total != 2 || len(page) != 1 || page[0].RequestID != "req_2" {
+		t.Errorf("request filter page: got %+v (total %d)", page, total)
+	}
+}
+
+func TestRetryFailureResolvesOnSuccess(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := newBatchTestClient(t, server.URL, 1)
+	ctx := context.Background()
+	client.logFailure(ctx, config.DefaultWebhookDestination, "req_1", "approved", []byte(`{"request_id":"req_1"}`), errors.New("timeout"))
+
+	failures, _, err := client.ListFailures(ctx, FailureListOptions{})
+	if err != nil || len(failures) != 1 {
+		t.Fatalf("ListFailures() = %v, %v", failures, err)
+	}
+	id := failures[0].ID
+
+	failure, err := client.RetryFailure(ctx, id)
+	if err == nil {
+		t.Fatal("expected delivery error while receiver is failing")
+	}
+	if failure == nil || failure.Attempts != 2 || failure.ResolvedAt != nil {
+		t.Fatalf("after failed retry: got %+v", failure)
+	}
+
+	fail.Store(false)
+	failure, err = client.RetryFailure(ctx, id)
+	if err != nil {
+		t.Fatalf("RetryFailure() error = %v", err)
+	}
+	if failure.ResolvedAt == nil {
+		t.Error("expected failure to be marked resolved")
+	}
+
+	if _, err := client.RetryFailure(ctx, id); !errors.Is(err, ErrFailureResolved) {
+		t.Errorf("retrying resolved failure: got %v, want ErrFailureResolved", err)
+	}
+	if _, err := client.RetryFailure(ctx, id+100); !errors.Is(err, ErrFailureNotFound) {
+		t.Errorf("retrying missing failure: got %v, want ErrFailureNotFound", err)
+	}
+}
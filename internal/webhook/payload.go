@@ -1,6 +1,10 @@
 package webhook
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/dtorcivia/schedlock/internal/google"
+)
 
 // WebhookPayload represents the payload sent to Moltbot.
 type WebhookPayload struct {
@@ -9,8 +13,24 @@ type WebhookPayload struct {
 	Status     string          `json:"status"`
 	Message    string          `json:"message"`
 	Suggestion string          `json:"suggestion,omitempty"`
+	Reason     string          `json:"reason,omitempty"`
 	Result     json.RawMessage `json:"result,omitempty"`
 	Timestamp  string          `json:"timestamp"`
+	Sequence   int64           `json:"sequence"` // per-request, increases with each event
+	Diff       []google.Diff   `json:"diff,omitempty"`
+}
+
+// AuditPayload is one audit log entry sent to the audit webhook.
+type AuditPayload struct {
+	Event     string          `json:"event"` // EventAuditEntry, or EventSettingsChanged from the settings webhook
+	ID        int64           `json:"id"`
+	Timestamp string          `json:"timestamp"`
+	EventType string          `json:"event_type"`
+	RequestID string          `json:"request_id,omitempty"`
+	APIKeyID  string          `json:"api_key_id,omitempty"`
+	Actor     string          `json:"actor,omitempty"`
+	IPAddress string          `json:"ip_address,omitempty"`
+	Details   json.RawMessage `json:"details,omitempty"`
 }
 
 // Event types for webhooks.
@@ -22,4 +42,6 @@ const (
 	EventRequestComplete = "request.completed"
 	EventRequestFailed   = "request.failed"
 	EventSuggestion      = "request.suggestion"
+	EventAuditEntry      = "audit.entry"
+	EventSettingsChanged = "settings.changed"
 )
@@ -34,6 +34,7 @@ func (w *CleanupWorker) Start(ctx context.Context) {
 		"request_days", w.config.CompletedRequestsDays,
 		"audit_days", w.config.AuditLogDays,
 		"webhook_days", w.config.WebhookFailuresDays,
+		"unclaimed_hours", w.config.UnclaimedResultsHours,
 	)
 
 	ticker := time.NewTicker(w.interval)
@@ -65,6 +66,9 @@ func (w *CleanupWorker) runCleanup(ctx context.Context) {
 	// Clean up old requests
 	w.cleanupRequests(ctx)
 
+	// Clean up completed requests whose caller never fetched them
+	w.cleanupUnclaimedResults(ctx)
+
 	// Clean up old audit logs
 	w.cleanupAuditLogs(ctx)
 
@@ -89,13 +93,8 @@ func (w *CleanupWorker) runCleanup(ctx context.Context) {
 
 // cleanupRequests removes old completed/failed/expired requests.
 func (w *CleanupWorker) cleanupRequests(ctx context.Context) {
-	result, err := w.db.ExecContext(ctx, `
-		DELETE FROM requests
-		WHERE status IN (?, ?, ?, ?, ?)
-		AND created_at < datetime('now', ?)
-	`, database.StatusCompleted, database.StatusFailed, database.StatusExpired,
-		database.StatusDenied, database.StatusCancelled,
-		fmt.Sprintf("-%d days", w.config.CompletedRequestsDays))
+	where, args := w.requestsFilter()
+	result, err := w.db.ExecContext(ctx, `DELETE FROM requests WHERE `+where, args...)
 
 	if err != nil {
 		util.Error("Failed to cleanup requests", "error", err)
@@ -107,12 +106,29 @@ func (w *CleanupWorker) cleanupRequests(ctx context.Context) {
 	}
 }
 
+// cleanupUnclaimedResults removes completed requests the caller never
+// fetched, once the unclaimed results TTL has passed.
+func (w *CleanupWorker) cleanupUnclaimedResults(ctx context.Context) {
+	if w.config.UnclaimedResultsHours <= 0 {
+		return
+	}
+	where, args := w.unclaimedResultsFilter()
+	result, err := w.db.ExecContext(ctx, `DELETE FROM requests WHERE `+where, args...)
+
+	if err != nil {
+		util.Error("Failed to cleanup unclaimed results", "error", err)
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		util.Info("Cleaned up unclaimed request results", "count", rows)
+	}
+}
+
 // cleanupAuditLogs removes old audit log entries.
 func (w *CleanupWorker) cleanupAuditLogs(ctx context.Context) {
-	result, err := w.db.ExecContext(ctx, `
-		DELETE FROM audit_log
-		WHERE timestamp < datetime('now', ?)
-	`, fmt.Sprintf("-%d days", w.config.AuditLogDays))
+	where, args := w.auditLogsFilter()
+	result, err := w.db.ExecContext(ctx, `DELETE FROM audit_log WHERE `+where, args...)
 
 	if err != nil {
 		util.Error("Failed to cleanup audit logs", "error", err)
@@ -143,10 +159,8 @@ func (w *CleanupWorker) cleanupDecisionTokens(ctx context.Context) {
 
 // cleanupIdempotencyKeys removes old idempotency keys (older than 24 hours).
 func (w *CleanupWorker) cleanupIdempotencyKeys(ctx context.Context) {
-	result, err := w.db.ExecContext(ctx, `
-		DELETE FROM idempotency_keys
-		WHERE created_at < datetime('now', '-24 hours')
-	`)
+	where, args := idempotencyKeysFilter()
+	result, err := w.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE `+where, args...)
 
 	if err != nil {
 		util.Error("Failed to cleanup idempotency keys", "error", err)
@@ -177,10 +191,8 @@ func (w *CleanupWorker) cleanupNotificationLogs(ctx context.Context) {
 
 // cleanupWebhookFailures removes old webhook failure records.
 func (w *CleanupWorker) cleanupWebhookFailures(ctx context.Context) {
-	result, err := w.db.ExecContext(ctx, `
-		DELETE FROM webhook_failures
-		WHERE created_at < datetime('now', ?)
-	`, fmt.Sprintf("-%d days", w.config.WebhookFailuresDays))
+	where, args := w.webhookFailuresFilter()
+	result, err := w.db.ExecContext(ctx, `DELETE FROM webhook_failures WHERE `+where, args...)
 
 	if err != nil {
 		util.Error("Failed to cleanup webhook failures", "error", err)
@@ -209,6 +221,131 @@ func (w *CleanupWorker) cleanupSessions(ctx context.Context) {
 	}
 }
 
+// The filters below select the rows a cleanup run deletes. Preview uses
+// the same filters, so it always reports exactly what cleanup would remove.
+
+func (w *CleanupWorker) requestsFilter() (string, []interface{}) {
+	return `status IN (?, ?, ?, ?, ?) AND created_at < datetime('now', ?)`, []interface{}{
+		database.StatusCompleted, database.StatusFailed, database.StatusExpired,
+		database.StatusDenied, database.StatusCancelled,
+		fmt.Sprintf("-%d days", w.config.CompletedRequestsDays),
+	}
+}
+
+// unclaimedResultsFilter leaves out requests the requests filter already
+// selects, so a preview does not count them twice. It selects nothing when
+// the unclaimed results TTL is off.
+func (w *CleanupWorker) unclaimedResultsFilter() (string, []interface{}) {
+	if w.config.UnclaimedResultsHours <= 0 {
+		return `0`, nil
+	}
+	return `status = ? AND result_fetched_at IS NULL AND executed_at < datetime('now', ?) AND created_at >= datetime('now', ?)`, []interface{}{
+		database.StatusCompleted,
+		fmt.Sprintf("-%d hours", w.config.UnclaimedResultsHours),
+		fmt.Sprintf("-%d days", w.config.CompletedRequestsDays),
+	}
+}
+
+func (w *CleanupWorker) auditLogsFilter() (string, []interface{}) {
+	return `timestamp < datetime('now', ?)`, []interface{}{fmt.Sprintf("-%d days", w.config.AuditLogDays)}
+}
+
+func idempotencyKeysFilter() (string, []interface{}) {
+	return `created_at < datetime('now', '-24 hours')`, nil
+}
+
+func (w *CleanupWorker) webhookFailuresFilter() (string, []interface{}) {
+	return `created_at < datetime('now', ?)`, []interface{}{fmt.Sprintf("-%d days", w.config.WebhookFailuresDays)}
+}
+
+// MaxPreviewIDs caps the IDs listed per category in a retention preview.
+const MaxPreviewIDs = 500
+
+// RetentionPreview reports what the next cleanup run would delete under
+// the current retention settings.
+type RetentionPreview struct {
+	Enabled         bool           `json:"enabled"` // false means cleanup deletes nothing
+	Requests        RetentionCount `json:"requests"`
+	AuditEntries    RetentionCount `json:"audit_entries"`
+	IdempotencyKeys RetentionCount `json:"idempotency_keys"`
+	WebhookFailures RetentionCount `json:"webhook_failures"`
+
+	// UnclaimedResults are completed requests never fetched by their
+	// caller, deleted before the requests threshold.
+	UnclaimedResults RetentionCount `json:"unclaimed_results"`
+}
+
+// RetentionCount is the number of rows of one kind due for deletion.
+type RetentionCount struct {
+	OlderThanDays  int      `json:"older_than_days,omitempty"`
+	OlderThanHours int      `json:"older_than_hours,omitempty"`
+	Count          int      `json:"count"`
+	IDs            []string `json:"ids,omitempty"` // oldest first, at most MaxPreviewIDs
+}
+
+// Preview counts the rows the retention cleanup would delete, without
+// deleting anything. With includeIDs, the IDs of those rows are listed too.
+func (w *CleanupWorker) Preview(ctx context.Context, includeIDs bool) (*RetentionPreview, error) {
+	preview := &RetentionPreview{Enabled: w.config.Enabled}
+
+	targets := []struct {
+		count    *RetentionCount
+		table    string
+		idCol    string
+		orderCol string
+		name     string
+		days     int
+		hours    int
+		filter   func() (string, []interface{})
+	}{
+		{&preview.Requests, "requests", "id", "created_at", "requests", w.config.CompletedRequestsDays, 0, w.requestsFilter},
+		{&preview.AuditEntries, "audit_log", "id", "timestamp", "audit entries", w.config.AuditLogDays, 0, w.auditLogsFilter},
+		{&preview.IdempotencyKeys, "idempotency_keys", "idempotency_key", "created_at", "idempotency keys", 0, 0, idempotencyKeysFilter},
+		{&preview.WebhookFailures, "webhook_failures", "id", "created_at", "webhook failures", w.config.WebhookFailuresDays, 0, w.webhookFailuresFilter},
+		{&preview.UnclaimedResults, "requests", "id", "executed_at", "unclaimed results", 0, w.config.UnclaimedResultsHours, w.unclaimedResultsFilter},
+	}
+
+	for _, t := range targets {
+		where, args := t.filter()
+		t.count.OlderThanDays = t.days
+		t.count.OlderThanHours = t.hours
+		if err := w.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+t.table+` WHERE `+where, args...).Scan(&t.count.Count); err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", t.name, err)
+		}
+		if !includeIDs || t.count.Count == 0 {
+			continue
+		}
+
+		ids, err := w.previewIDs(ctx, t.table, t.idCol, t.orderCol, where, args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", t.name, err)
+		}
+		t.count.IDs = ids
+	}
+
+	return preview, nil
+}
+
+// previewIDs lists the IDs matching a cleanup filter, oldest first.
+func (w *CleanupWorker) previewIDs(ctx context.Context, table, idCol, orderCol, where string, args []interface{}) ([]string, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT %d`, idCol, table, where, orderCol, MaxPreviewIDs)
+	rows, err := w.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // maybeVacuum runs VACUUM periodically (every 24 hours).
 func (w *CleanupWorker) maybeVacuum(ctx context.Context) {
 	// Check if we should vacuum (store last vacuum time in settings)
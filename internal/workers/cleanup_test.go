@@ -0,0 +1,165 @@
+package workers
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/requests"
+)
+
+func TestCleanupPreviewMatchesCleanup(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	for _, stmt := range []string{
+		`INSERT INTO api_keys (id, key_hash, key_prefix, name, tier) VALUES ('key_a', 'hash_a', 'sk_test', 'Test', 'write')`,
+		`INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status, created_at)
+			VALUES ('req_old', 'key_a', 'create_event', '{}', datetime('now'), 'completed', datetime('now', '-100 days'))`,
+		`INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status, created_at)
+			VALUES ('req_pending', 'key_a', 'create_event', '{}', datetime('now'), 'pending_approval', datetime('now', '-100 days'))`,
+		`INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status)
+			VALUES ('req_new', 'key_a', 'create_event', '{}', datetime('now'), 'completed')`,
+		`INSERT INTO audit_log (event_type, timestamp) VALUES ('request_created', datetime('now', '-400 days'))`,
+		`INSERT INTO audit_log (event_type) VALUES ('request_created')`,
+		`INSERT INTO idempotency_keys (api_key_id, idempotency_key, request_id, created_at)
+			VALUES ('key_a', 'idem_old', 'req_new', datetime('now', '-2 days'))`,
+		`INSERT INTO webhook_failures (webhook_id, request_id, status, payload, created_at)
+			VALUES ('wh_old', 'req_new', 'completed', '{}', datetime('now', '-40 days'))`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	w := NewCleanupWorker(db, &config.RetentionConfig{
+		Enabled:               true,
+		CompletedRequestsDays: 90,
+		AuditLogDays:          365,
+		WebhookFailuresDays:   30,
+	})
+	ctx := context.Background()
+
+	preview, err := w.Preview(ctx, true)
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	if preview.Requests.Count != 1 || len(preview.Requests.IDs) != 1 || preview.Requests.IDs[0] != "req_old" {
+		t.Errorf("requests = %+v, want only req_old", preview.Requests)
+	}
+	if preview.Requests.OlderThanDays != 90 {
+		t.Errorf("requests threshold = %d, want 90", preview.Requests.OlderThanDays)
+	}
+	if preview.AuditEntries.Count != 1 || preview.IdempotencyKeys.Count != 1 || preview.WebhookFailures.Count != 1 {
+		t.Errorf("counts = audit %d, idempotency %d, webhook %d; want 1 each",
+			preview.AuditEntries.Count, preview.IdempotencyKeys.Count, preview.WebhookFailures.Count)
+	}
+	if len(preview.IdempotencyKeys.IDs) != 1 || preview.IdempotencyKeys.IDs[0] != "idem_old" {
+		t.Errorf("idempotency IDs = %v, want [idem_old]", preview.IdempotencyKeys.IDs)
+	}
+
+	var remaining int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM requests`).Scan(&remaining); err != nil || remaining != 3 {
+		t.Fatalf("preview deleted requests: %d remain (err %v)", remaining, err)
+	}
+
+	w.cleanupIdempotencyKeys(ctx)
+	w.cleanupWebhookFailures(ctx)
+	w.cleanupRequests(ctx)
+	w.cleanupAuditLogs(ctx)
+
+	after, err := w.Preview(ctx, false)
+	if err != nil {
+		t.Fatalf("Preview after cleanup failed: %v", err)
+	}
+	if after.Requests.Count+after.AuditEntries.Count+after.IdempotencyKeys.Count+after.WebhookFailures.Count != 0 {
+		t.Errorf("cleanup left rows the preview reported: %+v", after)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM requests`).Scan(&remaining); err != nil || remaining != 2 {
+		t.Errorf("cleanup should delete exactly the previewed request: %d remain (err %v)", remaining, err)
+	}
+}
+
+func TestCleanupUnclaimedResults(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	for _, stmt := range []string{
+		`INSERT INTO api_keys (id, key_hash, key_prefix, name, tier) VALUES ('key_a', 'hash_a', 'sk_test', 'Test', 'write')`,
+		`INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status, created_at, executed_at)
+			VALUES ('req_unclaimed', 'key_a', 'create_event', '{}', datetime('now'), 'completed', datetime('now', '-2 days'), datetime('now', '-30 hours'))`,
+		`INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status, created_at, executed_at)
+			VALUES ('req_fetched', 'key_a', 'create_event', '{}', datetime('now'), 'completed', datetime('now', '-2 days'), datetime('now', '-30 hours'))`,
+		`INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status, created_at, executed_at)
+			VALUES ('req_recent', 'key_a', 'create_event', '{}', datetime('now'), 'completed', datetime('now', '-2 hours'), datetime('now', '-1 hours'))`,
+		`INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status, created_at)
+			VALUES ('req_failed', 'key_a', 'create_event', '{}', datetime('now'), 'failed', datetime('now', '-2 days'))`,
+		`INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status, created_at, executed_at)
+			VALUES ('req_old', 'key_a', 'create_event', '{}', datetime('now'), 'completed', datetime('now', '-100 days'), datetime('now', '-100 days'))`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	repo := requests.NewRepository(db)
+	for _, id := range []string{"req_fetched", "req_failed"} {
+		if err := repo.MarkResultFetched(ctx, id); err != nil {
+			t.Fatalf("MarkResultFetched(%s): %v", id, err)
+		}
+	}
+
+	w := NewCleanupWorker(db, &config.RetentionConfig{
+		Enabled:               true,
+		CompletedRequestsDays: 90,
+		UnclaimedResultsHours: 24,
+	})
+
+	preview, err := w.Preview(ctx, true)
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	if preview.UnclaimedResults.Count != 1 || len(preview.UnclaimedResults.IDs) != 1 || preview.UnclaimedResults.IDs[0] != "req_unclaimed" {
+		t.Errorf("unclaimed results = %+v, want only req_unclaimed", preview.UnclaimedResults)
+	}
+	if preview.UnclaimedResults.OlderThanHours != 24 {
+		t.Errorf("unclaimed threshold = %d, want 24", preview.UnclaimedResults.OlderThanHours)
+	}
+	if preview.Requests.Count != 1 {
+		t.Errorf("requests = %+v, want only req_old", preview.Requests)
+	}
+
+	w.cleanupUnclaimedResults(ctx)
+
+	var remaining []string
+	rows, err := db.Query(`SELECT id FROM requests ORDER BY id`)
+	if err != nil {
+		t.Fatalf("list requests: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		remaining = append(remaining, id)
+	}
+	if want := "req_failed,req_fetched,req_old,req_recent"; strings.Join(remaining, ",") != want {
+		t.Errorf("remaining = %v, want %s", remaining, want)
+	}
+
+	w.config.UnclaimedResultsHours = 0
+	if preview, err := w.Preview(ctx, false); err != nil || preview.UnclaimedResults.Count != 0 {
+		t.Errorf("disabled TTL should select nothing: %+v (err %v)", preview, err)
+	}
+}
@@ -0,0 +1,99 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/requests"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+// Escalator sends a pending request to the escalation recipient.
+type Escalator interface {
+	SendEscalation(ctx context.Context, req *database.Request, providerName, recipient string) error
+}
+
+// EscalationWorker sends requests that stay pending too long to a secondary
+// approver, once per request.
+type EscalationWorker struct {
+	requestRepo *requests.Repository
+	escalator   Escalator
+	interval    time.Duration
+	config      *config.ApprovalConfig
+}
+
+// NewEscalationWorker creates a new escalation worker.
+func NewEscalationWorker(requestRepo *requests.Repository, escalator Escalator, cfg *config.ApprovalConfig, interval time.Duration) *EscalationWorker {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &EscalationWorker{
+		requestRepo: requestRepo,
+		escalator:   escalator,
+		interval:    interval,
+		config:      cfg,
+	}
+}
+
+// Start starts the escalation worker.
+func (w *EscalationWorker) Start(ctx context.Context) {
+	if w.config == nil || w.config.EscalationMinutes <= 0 {
+		util.Info("Escalation worker disabled")
+		return
+	}
+
+	util.Info("Starting escalation worker",
+		"interval", w.interval,
+		"escalation_minutes", w.config.EscalationMinutes,
+		"provider", w.config.EscalationProvider,
+	)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			util.Info("Escalation worker stopping")
+			return
+		case <-ticker.C:
+			w.processEscalations(ctx)
+		}
+	}
+}
+
+// processEscalations escalates each request that has been pending longer
+// than the configured delay. Decided requests are no longer pending, so they
+// are never escalated.
+func (w *EscalationWorker) processEscalations(ctx context.Context) {
+	delay := time.Duration(w.config.EscalationMinutes) * time.Minute
+
+	pending, err := w.requestRepo.GetUnescalated(ctx, delay)
+	if err != nil {
+		util.Error("Failed to get requests to escalate", "error", err)
+		return
+	}
+
+	for i := range pending {
+		req := &pending[i]
+
+		// Claim the escalation first so concurrent runs never double-send
+		claimed, err := w.requestRepo.MarkEscalated(ctx, req.ID)
+		if err != nil {
+			util.Error("Failed to mark request escalated", "error", err, "request_id", req.ID)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := w.escalator.SendEscalation(ctx, req, w.config.EscalationProvider, w.config.EscalationTarget); err != nil {
+			util.Error("Failed to send escalation", "error", err, "request_id", req.ID)
+			continue
+		}
+
+		util.Info("Request escalated", "request_id", req.ID, "provider", w.config.EscalationProvider)
+	}
+}
@@ -0,0 +1,67 @@
+package workers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/requests"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+type recordingEscalator struct {
+	sent []string
+}
+
+func (e *recordingEscalator) SendEscalation(ctx context.Context, req *database.Request, providerName, recipient string) error {
+	e.sent = append(e.sent, req.ID+"->"+providerName+":"+recipient)
+	return nil
+}
+
+func TestEscalationSendsOncePerPendingRequest(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_a', 'hash_a', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+
+	now := time.Now()
+	expiresAt := util.SQLiteTimestamp(now.Add(time.Hour))
+	for _, r := range []struct {
+		id        string
+		createdAt time.Time
+		status    string
+	}{
+		{"req_old", now.Add(-20 * time.Minute), database.StatusPendingApproval},
+		{"req_new", now.Add(-2 * time.Minute), database.StatusPendingApproval},
+		{"req_decided", now.Add(-20 * time.Minute), database.StatusApproved},
+	} {
+		if _, err := db.Exec(`
+			INSERT INTO requests (id, api_key_id, operation, payload, created_at, expires_at, status)
+			VALUES (?, 'key_a', 'create_event', '{}', ?, ?, ?)
+		`, r.id, util.SQLiteTimestamp(r.createdAt), expiresAt, r.status); err != nil {
+			t.Fatalf("insert request: %v", err)
+		}
+	}
+
+	escalator := &recordingEscalator{}
+	cfg := &config.ApprovalConfig{EscalationMinutes: 10, EscalationProvider: "telegram", EscalationTarget: "-100123"}
+	w := NewEscalationWorker(requests.NewRepository(db), escalator, cfg, time.Minute)
+
+	w.processEscalations(context.Background())
+	w.processEscalations(context.Background())
+
+	if len(escalator.sent) != 1 || escalator.sent[0] != "req_old->telegram:-100123" {
+		t.Errorf("escalations = %v, want only req_old once", escalator.sent)
+	}
+}
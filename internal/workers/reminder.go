@@ -0,0 +1,96 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/requests"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+// Reminder re-sends a pending request's approval notification.
+type Reminder interface {
+	SendExpiryReminder(ctx context.Context, req *database.Request) error
+}
+
+// ReminderWorker re-notifies approvers about pending requests nearing expiry.
+type ReminderWorker struct {
+	requestRepo *requests.Repository
+	reminder    Reminder
+	interval    time.Duration
+	config      *config.ApprovalConfig
+}
+
+// NewReminderWorker creates a new reminder worker.
+func NewReminderWorker(requestRepo *requests.Repository, reminder Reminder, cfg *config.ApprovalConfig, interval time.Duration) *ReminderWorker {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &ReminderWorker{
+		requestRepo: requestRepo,
+		reminder:    reminder,
+		interval:    interval,
+		config:      cfg,
+	}
+}
+
+// Start starts the reminder worker.
+func (w *ReminderWorker) Start(ctx context.Context) {
+	if w.config == nil || w.config.ReminderMinutes <= 0 {
+		util.Info("Reminder worker disabled")
+		return
+	}
+
+	util.Info("Starting reminder worker",
+		"interval", w.interval,
+		"reminder_minutes", w.config.ReminderMinutes,
+	)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			util.Info("Reminder worker stopping")
+			return
+		case <-ticker.C:
+			w.processReminders(ctx)
+		}
+	}
+}
+
+// processReminders sends one reminder per pending request nearing expiry.
+func (w *ReminderWorker) processReminders(ctx context.Context) {
+	window := time.Duration(w.config.ReminderMinutes) * time.Minute
+	minAge := time.Duration(w.config.ReminderMinAgeMinutes) * time.Minute
+
+	pending, err := w.requestRepo.GetNearingExpiry(ctx, window, minAge)
+	if err != nil {
+		util.Error("Failed to get requests nearing expiry", "error", err)
+		return
+	}
+
+	for i := range pending {
+		req := &pending[i]
+
+		// Claim the reminder first so concurrent runs never double-send
+		claimed, err := w.requestRepo.MarkReminded(ctx, req.ID)
+		if err != nil {
+			util.Error("Failed to mark request reminded", "error", err, "request_id", req.ID)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := w.reminder.SendExpiryReminder(ctx, req); err != nil {
+			util.Error("Failed to send expiry reminder", "error", err, "request_id", req.ID)
+			continue
+		}
+
+		util.Info("Expiry reminder sent", "request_id", req.ID, "expires_at", req.ExpiresAt)
+	}
+}
@@ -0,0 +1,155 @@
+package workers
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/requests"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+type recordingReminder struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (r *recordingReminder) SendExpiryReminder(ctx context.Context, req *database.Request) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, req.ID)
+	return nil
+}
+
+func TestReminderSendsOncePerRequestNearingExpiry(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_a', 'hash_a', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+
+	now := time.Now()
+	for _, r := range []struct {
+		id        string
+		createdAt time.Time
+		expiresAt time.Time
+		status    string
+	}{
+		{"req_due", now.Add(-20 * time.Minute), now.Add(3 * time.Minute), database.StatusPendingApproval},
+		{"req_young", now.Add(-1 * time.Minute), now.Add(3 * time.Minute), database.StatusPendingApproval},
+		{"req_later", now.Add(-20 * time.Minute), now.Add(30 * time.Minute), database.StatusPendingApproval},
+		{"req_expired", now.Add(-20 * time.Minute), now.Add(-time.Minute), database.StatusPendingApproval},
+		{"req_decided", now.Add(-20 * time.Minute), now.Add(3 * time.Minute), database.StatusApproved},
+	} {
+		if _, err := db.Exec(`
+			INSERT INTO requests (id, api_key_id, operation, payload, created_at, expires_at, status)
+			VALUES (?, 'key_a', 'create_event', '{}', ?, ?, ?)
+		`, r.id, util.SQLiteTimestamp(r.createdAt), util.SQLiteTimestamp(r.expiresAt), r.status); err != nil {
+			t.Fatalf("insert request: %v", err)
+		}
+	}
+
+	reminder := &recordingReminder{}
+	cfg := &config.ApprovalConfig{ReminderMinutes: 5, ReminderMinAgeMinutes: 5}
+	w := NewReminderWorker(requests.NewRepository(db), reminder, cfg, time.Minute)
+
+	// Overlapping runs must not both claim the same request
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.processReminders(context.Background())
+		}()
+	}
+	wg.Wait()
+	w.processReminders(context.Background())
+
+	if len(reminder.sent) != 1 || reminder.sent[0] != "req_due" {
+		t.Errorf("reminders = %v, want only req_due once", reminder.sent)
+	}
+}
+
+func TestReminderSkipsRequestsYoungerThanMinAge(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`
+		INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+		VALUES ('key_a', 'hash_a', 'sk_test', 'Test', 'write')
+	`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+
+	// A request with a short timeout would be reminded as soon as it is
+	// created; the minimum age holds it back
+	now := time.Now()
+	if _, err := db.Exec(`
+		INSERT INTO requests (id, api_key_id, operation, payload, created_at, expires_at)
+		VALUES ('req_short', 'key_a', 'create_event', '{}', ?, ?)
+	`, util.SQLiteTimestamp(now.Add(-2*time.Minute)), util.SQLiteTimestamp(now.Add(2*time.Minute))); err != nil {
+		t.Fatalf("insert request: %v", err)
+	}
+
+	repo := requests.NewRepository(db)
+	ctx := context.Background()
+
+	due, err := repo.GetNearingExpiry(ctx, 5*time.Minute, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("GetNearingExpiry: %v", err)
+	}
+	if len(due) != 0 {
+		t.Errorf("GetNearingExpiry with a 5 minute min age = %d requests, want 0", len(due))
+	}
+	due, err = repo.GetNearingExpiry(ctx, 5*time.Minute, time.Minute)
+	if err != nil {
+		t.Fatalf("GetNearingExpiry: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != "req_short" {
+		t.Errorf("GetNearingExpiry with a 1 minute min age = %v, want req_short", due)
+	}
+
+	claimed, err := repo.MarkReminded(ctx, "req_short")
+	if err != nil || !claimed {
+		t.Fatalf("MarkReminded = %v, %v; want the first claim to succeed", claimed, err)
+	}
+	if claimed, err := repo.MarkReminded(ctx, "req_short"); err != nil || claimed {
+		t.Errorf("second MarkReminded = %v, %v; want false", claimed, err)
+	}
+	if due, _ := repo.GetNearingExpiry(ctx, 5*time.Minute, time.Minute); len(due) != 0 {
+		t.Errorf("reminded request is still listed: %v", due)
+	}
+}
+
+func TestReminderWorkerDisabledByDefault(t *testing.T) {
+	if config.DefaultApprovalReminderMinutes != 0 {
+		t.Fatalf("DefaultApprovalReminderMinutes = %d, want reminders off by default", config.DefaultApprovalReminderMinutes)
+	}
+
+	// Start returns at once when reminders are off
+	w := NewReminderWorker(nil, &recordingReminder{}, &config.ApprovalConfig{}, time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		w.Start(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Start kept running with reminders off")
+	}
+}
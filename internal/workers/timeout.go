@@ -19,23 +19,37 @@ type TimeoutWorker struct {
 	engine      *engine.Engine
 	interval    time.Duration
 	config      *config.ApprovalConfig
+	auditLogger *engine.AuditLogger
+	keyLookup   engine.KeyLookup
 	webhookChan chan<- string // Channel to notify webhook client of expirations
 }
 
 // NewTimeoutWorker creates a new timeout worker.
-func NewTimeoutWorker(requestRepo *requests.Repository, db *database.DB, engine *engine.Engine, cfg *config.ApprovalConfig, interval time.Duration) *TimeoutWorker {
+func NewTimeoutWorker(requestRepo *requests.Repository, db *database.DB, eng *engine.Engine, cfg *config.ApprovalConfig, interval time.Duration) *TimeoutWorker {
 	if interval <= 0 {
 		interval = 30 * time.Second
 	}
 	return &TimeoutWorker{
 		requestRepo: requestRepo,
 		db:          db,
-		engine:      engine,
+		engine:      eng,
 		interval:    interval,
 		config:      cfg,
+		auditLogger: engine.NewAuditLogger(db),
 	}
 }
 
+// SetAuditLogger replaces the worker's audit logger, so its entries share
+// the server's audit sink.
+func (w *TimeoutWorker) SetAuditLogger(logger *engine.AuditLogger) {
+	w.auditLogger = logger
+}
+
+// SetKeyLookup sets where per-key timeout actions are read from.
+func (w *TimeoutWorker) SetKeyLookup(k engine.KeyLookup) {
+	w.keyLookup = k
+}
+
 // SetWebhookChannel sets the channel for webhook notifications.
 func (w *TimeoutWorker) SetWebhookChannel(ch chan<- string) {
 	w.webhookChan = ch
@@ -78,16 +92,19 @@ func (w *TimeoutWorker) processExpired(ctx context.Context) {
 	util.Info("Processing expired requests", "count", len(expired))
 
 	for _, req := range expired {
-		defaultAction := "deny"
-		if w.config != nil && w.config.DefaultAction != "" {
-			defaultAction = w.config.DefaultAction
-		}
-		if defaultAction == "approve" && w.engine != nil {
-			if err := w.engine.ProcessApproval(ctx, req.ID, "approve", "timeout"); err != nil {
-				util.Error("Failed to auto-approve expired request", "error", err, "request_id", req.ID)
+		action, source := w.timeoutAction(ctx, req.APIKeyID)
+		if w.engine != nil {
+			// Decide like an approver would, so the approved or denied
+			// webhook fires and approvals are executed
+			if err := w.engine.ProcessApprovalWithReason(ctx, req.ID, action, "timeout", "approval timed out"); err != nil {
+				util.Error("Failed to apply timeout action", "error", err, "request_id", req.ID, "action", action)
 				continue
 			}
-			util.Info("Request auto-approved on timeout", "request_id", req.ID)
+			w.auditLogger.Log(ctx, database.AuditRequestExpired, req.ID, req.APIKeyID, "timeout_worker", map[string]interface{}{
+				"default_action": action,
+				"source":         source,
+			})
+			util.Info("Applied default action on timeout", "request_id", req.ID, "action", action, "source", source)
 			continue
 		}
 
@@ -112,14 +129,25 @@ func (w *TimeoutWorker) processExpired(ctx context.Context) {
 	}
 }
 
+// timeoutAction returns the action for a timed-out request of the given key
+// and where it came from: the key's timeout_action constraint ("key") or
+// the approval default action ("config").
+func (w *TimeoutWorker) timeoutAction(ctx context.Context, apiKeyID string) (action, source string) {
+	if w.keyLookup != nil && apiKeyID != "" {
+		key, err := w.keyLookup.GetByID(ctx, apiKeyID)
+		if err != nil {
+			util.Warn("Failed to load key timeout action", "api_key_id", apiKeyID, "error", err)
+		} else if key != nil && key.Constraints != nil && key.Constraints.TimeoutAction != "" {
+			return key.Constraints.TimeoutAction, "key"
+		}
+	}
+	if w.config != nil && w.config.DefaultAction != "" {
+		return w.config.DefaultAction, "config"
+	}
+	return "deny", "config"
+}
+
 // logAudit logs an expiration event to the audit log.
 func (w *TimeoutWorker) logAudit(ctx context.Context, requestID, apiKeyID, eventType string) {
-	_, err := w.db.ExecContext(ctx, `
-		INSERT INTO audit_log (event_type, request_id, api_key_id, actor, details)
-		VALUES (?, ?, ?, ?, NULL)
-	`, eventType, requestID, apiKeyID, "timeout_worker")
-
-	if err != nil {
-		util.Error("Failed to log expiration audit", "error", err)
-	}
+	w.auditLogger.Log(ctx, eventType, requestID, apiKeyID, "timeout_worker", nil)
 }
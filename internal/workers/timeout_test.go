@@ -0,0 +1,116 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dtorcivia/schedlock/internal/config"
+	"github.com/dtorcivia/schedlock/internal/database"
+	"github.com/dtorcivia/schedlock/internal/engine"
+	"github.com/dtorcivia/schedlock/internal/requests"
+	"github.com/dtorcivia/schedlock/internal/util"
+)
+
+type fakeKeyLookup map[string]*database.APIKey
+
+func (f fakeKeyLookup) GetByID(ctx context.Context, id string) (*database.APIKey, error) {
+	if key, ok := f[id]; ok {
+		return key, nil
+	}
+	return nil, errors.New("not found")
+}
+
+// newTimeoutTest opens a database with one key and one timed-out request
+// per key ID, and returns a worker using the given default action.
+func newTimeoutTest(t *testing.T, defaultAction string, keyIDs ...string) (*TimeoutWorker, *requests.Repository, *database.DB) {
+	t.Helper()
+
+	db, err := database.Open(filepath.Join(t.TempDir(), "schedlock.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	expiresAt := util.SQLiteTimestamp(time.Now().Add(-time.Minute))
+	for _, keyID := range keyIDs {
+		if _, err := db.Exec(`
+			INSERT INTO api_keys (id, key_hash, key_prefix, name, tier)
+			VALUES (?, ?, 'sk_test', 'Test', 'write')
+		`, keyID, "hash_"+keyID); err != nil {
+			t.Fatalf("insert api key: %v", err)
+		}
+		if _, err := db.Exec(`
+			INSERT INTO requests (id, api_key_id, operation, payload, expires_at, status)
+			VALUES (?, ?, 'create_event', '{}', ?, ?)
+		`, "req_"+keyID, keyID, expiresAt, database.StatusPendingApproval); err != nil {
+			t.Fatalf("insert request: %v", err)
+		}
+	}
+
+	cfg := &config.Config{Approval: config.ApprovalConfig{DefaultAction: defaultAction}}
+	repo := requests.NewRepository(db)
+	auditLogger := engine.NewAuditLogger(db)
+	eng := engine.NewEngine(cfg, repo, nil, auditLogger, nil)
+
+	w := NewTimeoutWorker(repo, db, eng, &cfg.Approval, time.Minute)
+	w.SetAuditLogger(auditLogger)
+	return w, repo, db
+}
+
+func requestStatus(t *testing.T, repo *requests.Repository, id string) string {
+	t.Helper()
+	req, err := repo.GetByID(context.Background(), id)
+	if err != nil || req == nil {
+		t.Fatalf("load %s: %v", id, err)
+	}
+	return req.Status
+}
+
+func TestTimeoutAppliesDefaultAction(t *testing.T) {
+	for _, tc := range []struct {
+		action string
+		want   string
+	}{
+		{"approve", database.StatusApproved},
+		{"deny", database.StatusDenied},
+	} {
+		t.Run(tc.action, func(t *testing.T) {
+			w, repo, db := newTimeoutTest(t, tc.action, "key_a")
+			w.processExpired(context.Background())
+
+			if got := requestStatus(t, repo, "req_key_a"); got != tc.want {
+				t.Errorf("status = %s, want %s", got, tc.want)
+			}
+
+			var details string
+			if err := db.QueryRow(`
+				SELECT details FROM audit_log WHERE request_id = 'req_key_a' AND event_type = ?
+			`, database.AuditRequestExpired).Scan(&details); err != nil {
+				t.Fatalf("expected an expiry audit entry: %v", err)
+			}
+			if want := `{"default_action":"` + tc.action + `","source":"config"}`; details != want {
+				t.Errorf("audit details = %s, want %s", details, want)
+			}
+		})
+	}
+}
+
+func TestTimeoutKeyOverridesDefaultAction(t *testing.T) {
+	w, repo, _ := newTimeoutTest(t, "deny", "key_auto", "key_plain")
+	w.SetKeyLookup(fakeKeyLookup{
+		"key_auto":  {ID: "key_auto", Constraints: &database.KeyConstraints{TimeoutAction: "approve"}},
+		"key_plain": {ID: "key_plain"},
+	})
+
+	w.processExpired(context.Background())
+
+	if got := requestStatus(t, repo, "req_key_auto"); got != database.StatusApproved {
+		t.Errorf("key with timeout_action approve: status = %s, want approved", got)
+	}
+	if got := requestStatus(t, repo, "req_key_plain"); got != database.StatusDenied {
+		t.Errorf("key without override: status = %s, want denied", got)
+	}
+}